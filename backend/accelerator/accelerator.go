@@ -0,0 +1,153 @@
+// Package accelerator offloads the multi-scalar multiplications and the FFTs
+// of the provers to a hardware device, typically a CUDA GPU.
+//
+// The provers only call the accelerator when gnark is built with the gpu build
+// tag, otherwise [Enabled] is false and the hooks are compiled out. The device
+// is provided by a separate package which binds the GPU library and calls
+// [Register] in its init function, for example:
+//
+//	import _ "example.com/gnark-cuda" // registers a CUDA device
+//
+// and is built with
+//
+//	go build -tags gpu
+//
+// The multi-scalar multiplications larger than the device capacity are split
+// in chunks whose results are summed on the CPU. The operations the device does
+// not support (curve, group or size) fall back to the CPU implementation.
+//
+// The Groth16 prover offloads its multi-scalar multiplications and the FFTs of
+// the quotient computation, the PLONK prover offloads the multi-scalar
+// multiplications of its KZG commitments.
+package accelerator
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// ErrUnsupported is returned by a [Device] for an operation it does not
+// support. The operation is then done on the CPU.
+var ErrUnsupported = errors.New("operation not supported by the device")
+
+// Group identifies the group of the points of a multi-scalar multiplication.
+type Group uint8
+
+const (
+	G1 Group = iota
+	G2
+)
+
+// Device is a hardware accelerator.
+//
+// An operation which returns [ErrUnsupported] must leave its arguments
+// unchanged.
+//
+// The arguments of the operations are the types of the gnark-crypto package of
+// the curve: *G1Jac, []G1Affine for G1, *G2Jac, []G2Affine for G2, []fr.Element
+// for the scalars and *fft.Domain for the domains.
+type Device interface {
+	// Name returns the name of the device, for logging.
+	Name() string
+
+	// MultiExpCapacity returns the maximum number of points of a multi-scalar
+	// multiplication in group of curve that the device can do at once, or 0
+	// if it is not supported.
+	MultiExpCapacity(curve ecc.ID, group Group) int
+
+	// MultiExp sets res to Σ scalars[i]·points[i], with len(points) =
+	// len(scalars) ⩽ MultiExpCapacity.
+	MultiExp(curve ecc.ID, res, points, scalars any) error
+
+	// FFTCapacity returns the maximum size of an FFT on curve that the device
+	// can do, or 0 if it is not supported.
+	FFTCapacity(curve ecc.ID) int
+
+	// FFT computes in place the FFT of a, with len(a) the cardinality of the
+	// domain, its inverse if inverse is set, on the coset of the domain
+	// shifted by its multiplicative generator if coset is set. The input and
+	// the output are in natural order. The device must support the four
+	// variants of the FFT up to FFTCapacity.
+	FFT(curve ecc.ID, domain, a any, inverse, coset bool) error
+}
+
+var (
+	lock   sync.RWMutex
+	device Device
+)
+
+// Register sets the device used by the provers. It replaces the previously
+// registered device, if any, and unregisters it if d is nil.
+func Register(d Device) {
+	lock.Lock()
+	defer lock.Unlock()
+	device = d
+}
+
+// Get returns the registered device, or nil if there is none or if gnark is
+// built without the gpu build tag.
+func Get() Device {
+	if !Enabled {
+		return nil
+	}
+	lock.RLock()
+	defer lock.RUnlock()
+	return device
+}
+
+// MultiExp sets res to Σ scalars[i]·points[i] with the registered device, in
+// chunks of at most the device capacity summed with add. It returns false if
+// there is no device or if it does not support the operation, in which case
+// the caller must do it on the CPU.
+func MultiExp[R any, P any, S any](curve ecc.ID, group Group, res *R, points []P, scalars []S, add func(res, partial *R)) (bool, error) {
+	d := Get()
+	if d == nil || len(points) == 0 {
+		return false, nil
+	}
+	if len(points) != len(scalars) {
+		return false, errors.New("points and scalars lengths don't match")
+	}
+	capacity := d.MultiExpCapacity(curve, group)
+	if capacity <= 0 {
+		return false, nil
+	}
+
+	var partial R
+	for start := 0; start < len(points); start += capacity {
+		end := start + capacity
+		if end > len(points) {
+			end = len(points)
+		}
+		err := d.MultiExp(curve, &partial, points[start:end], scalars[start:end])
+		if errors.Is(err, ErrUnsupported) && start == 0 {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if start == 0 {
+			*res = partial
+		} else {
+			add(res, &partial)
+		}
+	}
+	return true, nil
+}
+
+// FFT computes in place the FFT of a with the registered device, see
+// [Device.FFT]. It returns false if there is no device or if it does not
+// support the operation, in which case a is unchanged and the caller must do
+// it on the CPU.
+func FFT[S any](curve ecc.ID, domain any, a []S, inverse, coset bool) (bool, error) {
+	d := Get()
+	if d == nil || len(a) > d.FFTCapacity(curve) {
+		return false, nil
+	}
+	err := d.FFT(curve, domain, a, inverse, coset)
+	if errors.Is(err, ErrUnsupported) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,157 @@
+//go:build gpu
+// +build gpu
+
+package accelerator_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark/backend/accelerator"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// cpuDevice is a BN254 device computing on the CPU.
+type cpuDevice struct {
+	capacity          int
+	nbMultiExp, nbFFT int
+}
+
+func (d *cpuDevice) Name() string { return "cpu" }
+
+func (d *cpuDevice) MultiExpCapacity(curve ecc.ID, group accelerator.Group) int {
+	if curve != ecc.BN254 {
+		return 0
+	}
+	return d.capacity
+}
+
+func (d *cpuDevice) MultiExp(curve ecc.ID, res, points, scalars any) (err error) {
+	d.nbMultiExp++
+	switch res := res.(type) {
+	case *bn254.G1Jac:
+		_, err = res.MultiExp(points.([]bn254.G1Affine), scalars.([]fr.Element), ecc.MultiExpConfig{})
+	case *bn254.G2Jac:
+		_, err = res.MultiExp(points.([]bn254.G2Affine), scalars.([]fr.Element), ecc.MultiExpConfig{})
+	default:
+		err = accelerator.ErrUnsupported
+	}
+	return err
+}
+
+func (d *cpuDevice) FFTCapacity(curve ecc.ID) int {
+	if curve != ecc.BN254 {
+		return 0
+	}
+	return 1 << 20
+}
+
+func (d *cpuDevice) FFT(curve ecc.ID, domain, a any, inverse, coset bool) error {
+	d.nbFFT++
+	var opts []fft.Option
+	if coset {
+		opts = append(opts, fft.OnCoset())
+	}
+	v := a.([]fr.Element)
+	if inverse {
+		domain.(*fft.Domain).FFTInverse(v, fft.DIF, opts...)
+	} else {
+		domain.(*fft.Domain).FFT(v, fft.DIF, opts...)
+	}
+	fft.BitReverse(v)
+	return nil
+}
+
+type circuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	x := c.X
+	for i := 0; i < 10; i++ {
+		x = api.Mul(x, x)
+	}
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(x, c.Y)
+	return nil
+}
+
+func TestMultiExp(t *testing.T) {
+	assert := require.New(t)
+	d := &cpuDevice{capacity: 3}
+	accelerator.Register(d)
+	defer accelerator.Register(nil)
+
+	_, _, g1, _ := bn254.Generators()
+	points := make([]bn254.G1Affine, 10)
+	scalars := make([]fr.Element, len(points))
+	for i := range points {
+		points[i] = g1
+		scalars[i].SetUint64(uint64(i))
+	}
+
+	var res, expected bn254.G1Jac
+	ok, err := accelerator.MultiExp(ecc.BN254, accelerator.G1, &res, points, scalars, func(res, partial *bn254.G1Jac) { res.AddAssign(partial) })
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal(4, d.nbMultiExp, "expected 4 chunks")
+	_, err = expected.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	assert.NoError(err)
+	assert.True(res.Equal(&expected))
+
+	// unsupported curve
+	ok, err = accelerator.MultiExp(ecc.BLS12_381, accelerator.G1, &res, points, scalars, func(res, partial *bn254.G1Jac) { res.AddAssign(partial) })
+	assert.NoError(err)
+	assert.False(ok)
+}
+
+func TestProvers(t *testing.T) {
+	assert := test.NewAssert(t)
+	d := &cpuDevice{capacity: 5}
+	accelerator.Register(d)
+	defer accelerator.Register(nil)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381} {
+		assert.Run(func(assert *test.Assert) {
+			fullWitness, err := frontend.NewWitness(&circuit{X: 1, Y: 1}, curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &circuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs, pk, fullWitness)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, publicWitness))
+
+			sccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &circuit{})
+			assert.NoError(err)
+			srs, err := test.NewKZGSRS(sccs)
+			assert.NoError(err)
+			ppk, pvk, err := plonk.Setup(sccs, srs)
+			assert.NoError(err)
+			pproof, err := plonk.Prove(sccs, ppk, fullWitness)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(pproof, pvk, publicWitness))
+		}, curve.String())
+	}
+	if d.nbMultiExp == 0 || d.nbFFT == 0 {
+		t.Fatal("the device was not used")
+	}
+}
@@ -0,0 +1,7 @@
+//go:build gpu
+// +build gpu
+
+package accelerator
+
+// Enabled is true when gnark is built with the gpu build tag.
+const Enabled = true
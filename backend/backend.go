@@ -17,6 +17,7 @@ package backend
 
 import (
 	"crypto/sha256"
+	"errors"
 	"hash"
 
 	"github.com/consensys/gnark/constraint/solver"
@@ -30,11 +31,14 @@ const (
 	GROTH16
 	PLONK
 	PLONKFRI
+	MARLIN
+	SPARTAN
+	BULLETPROOFS
 )
 
 // Implemented return the list of proof systems implemented in gnark
 func Implemented() []ID {
-	return []ID{GROTH16, PLONK, PLONKFRI}
+	return []ID{GROTH16, PLONK, PLONKFRI, MARLIN, SPARTAN, BULLETPROOFS}
 }
 
 // String returns the string representation of a proof system
@@ -46,6 +50,12 @@ func (id ID) String() string {
 		return "plonk"
 	case PLONKFRI:
 		return "plonkFRI"
+	case MARLIN:
+		return "marlin"
+	case SPARTAN:
+		return "spartan"
+	case BULLETPROOFS:
+		return "bulletproofs"
 	default:
 		return "unknown"
 	}
@@ -58,10 +68,13 @@ type ProverOption func(*ProverConfig) error
 
 // ProverConfig is the configuration for the prover with the options applied.
 type ProverConfig struct {
-	SolverOpts     []solver.Option
-	HashToFieldFn  hash.Hash
-	ChallengeHash  hash.Hash
-	KZGFoldingHash hash.Hash
+	SolverOpts      []solver.Option
+	HashToFieldFn   hash.Hash
+	ChallengeHash   hash.Hash
+	KZGFoldingHash  hash.Hash
+	ChunkSize       int
+	NoZeroKnowledge bool
+	RecoverPanics   bool
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
@@ -78,6 +91,9 @@ func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
 			return ProverConfig{}, err
 		}
 	}
+	if opt.RecoverPanics {
+		opt.SolverOpts = append(opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)], solver.WithPanicRecovery())
+	}
 	return opt, nil
 }
 
@@ -122,6 +138,51 @@ func WithProverKZGFoldingHashFunction(hFunc hash.Hash) ProverOption {
 	}
 }
 
+// WithProverChunkSize sets the number of points of the proving key the
+// streaming Groth16 prover reads and processes at once. A smaller chunk size
+// lowers the memory usage of the prover at the cost of more disk reads and
+// slower multi-exponentiations. If not set, a default of 2²⁰ points is used.
+// Only the streaming Groth16 prover is affected.
+func WithProverChunkSize(chunkSize int) ProverOption {
+	return func(pc *ProverConfig) error {
+		if chunkSize <= 0 {
+			return errors.New("chunk size must be positive")
+		}
+		pc.ChunkSize = chunkSize
+		return nil
+	}
+}
+
+// WithProverNoZeroKnowledge disables the randomization of the Groth16 proofs
+// by the random r and s, which are set to 0. The proofs are then deterministic
+// and faster to compute, as one multi-exponentiation in G1 is skipped, and
+// they are verified as usual. They leak information on the secret witness, so
+// this option must only be used when the soundness of the proof is needed but
+// not its privacy, for example for verifiable computation on public data. Only
+// the Groth16 provers are affected.
+func WithProverNoZeroKnowledge() ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.NoZeroKnowledge = true
+		return nil
+	}
+}
+
+// WithProverPanicRecovery makes the provers return an error wrapping
+// [github.com/consensys/gnark.ErrPanic] instead of panicking. The error holds
+// the panic value and the stack trace.
+//
+// The option covers the whole call to the Prove functions of the backend
+// packages (for example groth16.Prove), including the solver. The provers
+// validate their inputs before starting their own goroutines, whose panics
+// can't be recovered. The curve-typed packages they dispatch to (for example
+// groth16/bn254) only recover the panics of the solver.
+func WithProverPanicRecovery() ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.RecoverPanics = true
+		return nil
+	}
+}
+
 // VerifierOption defines option for altering the behavior of the verifier. See
 // the descriptions of functions returning instances of this type for
 // implemented options.
@@ -132,6 +193,7 @@ type VerifierConfig struct {
 	HashToFieldFn  hash.Hash
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
+	RecoverPanics  bool
 }
 
 // NewVerifierConfig returns a default [VerifierConfig] with given verifier
@@ -183,3 +245,47 @@ func WithVerifierKZGFoldingHashFunction(hFunc hash.Hash) VerifierOption {
 		return nil
 	}
 }
+
+// WithVerifierPanicRecovery makes the Verify functions of the backend packages
+// (for example groth16.Verify) return an error wrapping
+// [github.com/consensys/gnark.ErrPanic] instead of panicking, see
+// [WithProverPanicRecovery].
+func WithVerifierPanicRecovery() VerifierOption {
+	return func(pc *VerifierConfig) error {
+		pc.RecoverPanics = true
+		return nil
+	}
+}
+
+// SetupOption defines option for altering the behavior of the setup. See the
+// descriptions of the functions returning instances of this type for
+// implemented options.
+type SetupOption func(*SetupConfig) error
+
+// SetupConfig is the configuration for the setup with the options applied.
+type SetupConfig struct {
+	RecoverPanics bool
+}
+
+// NewSetupConfig returns a default [SetupConfig] with given setup options
+// applied.
+func NewSetupConfig(opts ...SetupOption) (SetupConfig, error) {
+	var opt SetupConfig
+	for _, option := range opts {
+		if err := option(&opt); err != nil {
+			return SetupConfig{}, err
+		}
+	}
+	return opt, nil
+}
+
+// WithSetupPanicRecovery makes the Setup functions of the backend packages
+// (for example groth16.Setup) return an error wrapping
+// [github.com/consensys/gnark.ErrPanic] instead of panicking, see
+// [WithProverPanicRecovery].
+func WithSetupPanicRecovery() SetupOption {
+	return func(sc *SetupConfig) error {
+		sc.RecoverPanics = true
+		return nil
+	}
+}
@@ -0,0 +1,193 @@
+// Package beacon implements a client of the drand public randomness beacon.
+//
+// The randomness of a beacon round is not known before the round is emitted
+// and can be verified by anyone against the public key of the drand network.
+// It is used to finalize the MPC setup ceremonies of Groth16 (see the mpcsetup
+// packages of the backends): the last contribution is derived from the
+// randomness of a round fixed in advance, so that the coordinator can't bias
+// the final parameters. The beacon is part of the ceremony transcript and
+// must be published along with the contributions.
+//
+// Only the chained scheme (pedersen-bls-chained) of the drand mainnet is
+// supported: the public key is in G1 and the signatures in G2 of BLS12-381,
+// and the signed message of a round depends on the signature of the previous
+// round.
+package beacon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+const (
+	// DefaultURL is the URL of the HTTP API of the drand mainnet.
+	DefaultURL = "https://api.drand.sh"
+
+	// MainnetPublicKey is the hex-encoded public key of the default chain of
+	// the drand mainnet.
+	MainnetPublicKey = "868f005eb8e6e4ca0a47c8a77ceaa5309a47978a7c71bc5cce96366b5d7a569937c529eeda66c7293784a9402801af31"
+
+	// DST is the domain separation tag used to hash the messages to G2.
+	DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of a round doesn't
+	// verify against the public key of the network.
+	ErrInvalidSignature = errors.New("invalid beacon signature")
+	// ErrInvalidRandomness is returned when the randomness of a round isn't
+	// the hash of its signature.
+	ErrInvalidRandomness = errors.New("invalid beacon randomness")
+)
+
+// Beacon is the output of a round of a drand chained network. It is encoded
+// in JSON as returned by the HTTP API of drand.
+type Beacon struct {
+	Round             uint64
+	Randomness        []byte // sha256 of the signature
+	Signature         []byte // compressed G2 point
+	PreviousSignature []byte // compressed G2 point
+}
+
+type jsonBeacon struct {
+	Round             uint64 `json:"round"`
+	Randomness        string `json:"randomness"`
+	Signature         string `json:"signature"`
+	PreviousSignature string `json:"previous_signature"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (b *Beacon) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonBeacon{
+		Round:             b.Round,
+		Randomness:        hex.EncodeToString(b.Randomness),
+		Signature:         hex.EncodeToString(b.Signature),
+		PreviousSignature: hex.EncodeToString(b.PreviousSignature),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Beacon) UnmarshalJSON(data []byte) error {
+	var jb jsonBeacon
+	if err := json.Unmarshal(data, &jb); err != nil {
+		return err
+	}
+	var err error
+	b.Round = jb.Round
+	if b.Randomness, err = hex.DecodeString(jb.Randomness); err != nil {
+		return fmt.Errorf("decode randomness: %w", err)
+	}
+	if b.Signature, err = hex.DecodeString(jb.Signature); err != nil {
+		return fmt.Errorf("decode signature: %w", err)
+	}
+	if b.PreviousSignature, err = hex.DecodeString(jb.PreviousSignature); err != nil {
+		return fmt.Errorf("decode previous signature: %w", err)
+	}
+	return nil
+}
+
+// Message returns the message signed by the network at this round, that is
+// sha256(previous signature ‖ round), with the round in big-endian.
+func (b *Beacon) Message() []byte {
+	var round [8]byte
+	binary.BigEndian.PutUint64(round[:], b.Round)
+	h := sha256.New()
+	h.Write(b.PreviousSignature)
+	h.Write(round[:])
+	return h.Sum(nil)
+}
+
+// Verify checks that the randomness is the hash of the signature and that
+// the signature of the round is valid for the given compressed public key.
+func (b *Beacon) Verify(publicKey []byte) error {
+	var pk bls12381.G1Affine
+	if _, err := pk.SetBytes(publicKey); err != nil {
+		return fmt.Errorf("decode public key: %w", err)
+	}
+	var sig bls12381.G2Affine
+	if _, err := sig.SetBytes(b.Signature); err != nil {
+		return fmt.Errorf("decode signature: %w", err)
+	}
+	randomness := sha256.Sum256(b.Signature)
+	if len(b.Randomness) != len(randomness) || string(b.Randomness) != string(randomness[:]) {
+		return ErrInvalidRandomness
+	}
+	hm, err := bls12381.HashToG2(b.Message(), []byte(DST))
+	if err != nil {
+		return err
+	}
+	// e(g₁, σ) = e(pk, H(m))
+	_, _, g1, _ := bls12381.Generators()
+	var negPk bls12381.G1Affine
+	negPk.Neg(&pk)
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{g1, negPk}, []bls12381.G2Affine{sig, hm})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Client fetches and verifies the rounds of a drand network over HTTP.
+type Client struct {
+	url        string
+	publicKey  []byte
+	httpClient *http.Client
+}
+
+// NewClient returns a client of the drand network served at url, with the
+// given hex-encoded public key. If httpClient is nil, [http.DefaultClient] is
+// used.
+func NewClient(url, publicKey string, httpClient *http.Client) (*Client, error) {
+	pk, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key: %w", err)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		url:        strings.TrimSuffix(url, "/"),
+		publicKey:  pk,
+		httpClient: httpClient,
+	}, nil
+}
+
+// Get fetches the given round and verifies it. The round must have been
+// emitted by the network.
+func (c *Client) Get(ctx context.Context, round uint64) (*Beacon, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/public/%d", c.url, round), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch round %d: %w", round, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch round %d: %s", round, resp.Status)
+	}
+	var b Beacon
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return nil, fmt.Errorf("decode round %d: %w", round, err)
+	}
+	if b.Round != round {
+		return nil, fmt.Errorf("requested round %d, got %d", round, b.Round)
+	}
+	if err := b.Verify(c.publicKey); err != nil {
+		return nil, fmt.Errorf("round %d: %w", round, err)
+	}
+	return &b, nil
+}
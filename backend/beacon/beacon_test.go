@@ -0,0 +1,107 @@
+package beacon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/require"
+)
+
+// testNetwork signs chained rounds with a fixed secret key.
+type testNetwork struct {
+	sk     big.Int
+	pk     bls12381.G1Affine
+	rounds []*Beacon
+}
+
+func newTestNetwork(t *testing.T, nbRounds int) *testNetwork {
+	var n testNetwork
+	n.sk.SetString("1234567890abcdef1234567890abcdef", 16)
+	_, _, g1, _ := bls12381.Generators()
+	n.pk.ScalarMultiplication(&g1, &n.sk)
+	prev := make([]byte, 96) // genesis
+	for r := 1; r <= nbRounds; r++ {
+		b := &Beacon{Round: uint64(r), PreviousSignature: prev}
+		hm, err := bls12381.HashToG2(b.Message(), []byte(DST))
+		require.NoError(t, err)
+		var sig bls12381.G2Affine
+		sig.ScalarMultiplication(&hm, &n.sk)
+		sigBytes := sig.Bytes()
+		b.Signature = sigBytes[:]
+		randomness := sha256.Sum256(b.Signature)
+		b.Randomness = randomness[:]
+		n.rounds = append(n.rounds, b)
+		prev = b.Signature
+	}
+	return &n
+}
+
+func (n *testNetwork) publicKey() string {
+	pk := n.pk.Bytes()
+	return hex.EncodeToString(pk[:])
+}
+
+func TestVerify(t *testing.T) {
+	assert := require.New(t)
+	n := newTestNetwork(t, 3)
+	pk := n.pk.Bytes()
+	for _, b := range n.rounds {
+		assert.NoError(b.Verify(pk[:]))
+	}
+
+	// the signature doesn't verify for another round
+	b := *n.rounds[1]
+	b.Round++
+	assert.ErrorIs(b.Verify(pk[:]), ErrInvalidSignature)
+
+	// the randomness must be the hash of the signature
+	b = *n.rounds[1]
+	b.Randomness = n.rounds[0].Randomness
+	assert.ErrorIs(b.Verify(pk[:]), ErrInvalidRandomness)
+}
+
+func TestMainnetPublicKey(t *testing.T) {
+	pk, err := hex.DecodeString(MainnetPublicKey)
+	require.NoError(t, err)
+	var p bls12381.G1Affine
+	_, err = p.SetBytes(pk)
+	require.NoError(t, err)
+}
+
+func TestClient(t *testing.T) {
+	assert := require.New(t)
+	n := newTestNetwork(t, 3)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var round int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/public/"), "%d", &round); err != nil || round < 1 || round > len(n.rounds) {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(n.rounds[round-1])
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, n.publicKey(), srv.Client())
+	assert.NoError(err)
+	b, err := c.Get(context.Background(), 2)
+	assert.NoError(err)
+	assert.Equal(n.rounds[1], b)
+
+	_, err = c.Get(context.Background(), 4)
+	assert.Error(err)
+
+	// a client with another public key rejects the rounds
+	c, err = NewClient(srv.URL, MainnetPublicKey, srv.Client())
+	assert.NoError(err)
+	_, err = c.Get(context.Background(), 2)
+	assert.ErrorIs(err, ErrInvalidSignature)
+}
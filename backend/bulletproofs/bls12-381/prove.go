@@ -0,0 +1,272 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package bulletproofs
+
+import (
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	cs "github.com/consensys/gnark/constraint/bls12-381"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/logger"
+	"math/big"
+	"time"
+)
+
+// Proof represents a Bulletproofs proof generated by Prove.
+//
+// The proof consists of the commitments to the gates and to the polynomial
+// t(X) = ⟨l(X), r(X)⟩, of the evaluation of t at a random point, and of the
+// inner product argument proving it against the committed l(X) and r(X).
+type Proof struct {
+	// AI, AO, S are the commitments to (aₗ, aᵣ), aₒ and to the blinding
+	// vectors (sₗ, sᵣ)
+	AI, AO, S curve.G1Affine
+
+	// T are the commitments to t₁, t₃, t₄, t₅, t₆, the coefficients of t(X)
+	// but the one of degree 2, which the verifier knows
+	T [5]curve.G1Affine
+
+	// TauX, Mu are the blinding factors of t(x) and of the commitment to
+	// l(x), r(x)
+	TauX, Mu fr.Element
+
+	// THat is t(x)
+	THat fr.Element
+
+	// Opening proves ⟨l(x), r(x)⟩ = THat
+	Opening InnerProductProof
+}
+
+// Prove generates a Bulletproofs proof from a circuit, its proving key and the
+// full witness.
+//
+// The prover commits to the gates aₗ, aᵣ, aₒ (see [VerifyingKey]), and shows,
+// for random challenges y and z, that
+//
+//	⟨aₗ∘aᵣ - aₒ, yⁿ⟩ + ⟨wₗ, aₗ⟩ + ⟨wᵣ, aᵣ⟩ + ⟨wₒ, aₒ⟩ = c
+//
+// where wₗ, wᵣ, wₒ, c are the linear constraints weighted by the powers of z.
+// This is the coefficient of degree 2 of t(X) = ⟨l(X), r(X)⟩, with
+//
+//	l(X) = (aₗ + y⁻ⁿ∘wᵣ)⋅X + aₒ⋅X² + sₗ⋅X³
+//	r(X) = yⁿ∘aᵣ⋅X - yⁿ + wₗ⋅X + wₒ + yⁿ∘sᵣ⋅X³
+//
+// The prover commits to the other coefficients of t, and the verifier checks
+// t at a random point x. Finally, an inner product argument shows that l(x)
+// and r(x) are consistent with the commitments and that t(x) = ⟨l(x), r(x)⟩.
+// The blinding vectors sₗ, sᵣ make the proof zero-knowledge.
+func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+
+	log := logger.Logger().With().
+		Str("curve", r1cs.CurveID().String()).
+		Int("nbConstraints", r1cs.GetNbConstraints()).
+		Str("backend", "bulletproofs").Logger()
+
+	// parse the options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("get prover options: %w", err)
+	}
+
+	start := time.Now()
+
+	// solve the R1CS
+	_solution, err := r1cs.Solve(fullWitness, opt.SolverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	solution := _solution.(*cs.R1CSSolution)
+
+	var proof Proof
+	vk := pk.Vk
+	n := int(vk.NbGates)
+	m := int(vk.NbConstraints)
+	nbPublic := int(vk.NbPublicVariables)
+	fs := fiatshamir.NewTranscript(opt.ChallengeHash, challengeNames(vk)...)
+
+	// round 1: commit to the gates
+	aL := make([]fr.Element, n)
+	aR := make([]fr.Element, n)
+	aO := make([]fr.Element, n)
+	copy(aL, solution.A)
+	copy(aR, solution.B)
+	copy(aO, solution.C)
+	for j, w := range solution.W[nbPublic:] {
+		aL[m+j] = w
+		aR[m+j].SetOne()
+		aO[m+j] = w
+	}
+	sL, err := randomVector(n)
+	if err != nil {
+		return nil, err
+	}
+	sR, err := randomVector(n)
+	if err != nil {
+		return nil, err
+	}
+	blindings, err := randomVector(3) // α, β, ρ
+	if err != nil {
+		return nil, err
+	}
+	if proof.AI, err = vk.commit(aL, aR, blindings[0]); err != nil {
+		return nil, err
+	}
+	if proof.AO, err = vk.commit(aO, nil, blindings[1]); err != nil {
+		return nil, err
+	}
+	if proof.S, err = vk.commit(sL, sR, blindings[2]); err != nil {
+		return nil, err
+	}
+	publicInputs := solution.W[1:nbPublic]
+	if err := bindPublicData(&fs, "y", vk, publicInputs); err != nil {
+		return nil, err
+	}
+	y, err := deriveRandomness(&fs, "y", &proof.AI, &proof.AO, &proof.S)
+	if err != nil {
+		return nil, err
+	}
+	z, err := deriveRandomness(&fs, "z")
+	if err != nil {
+		return nil, err
+	}
+	if y.IsZero() || z.IsZero() {
+		return nil, errors.New("null challenge")
+	}
+
+	// round 2: commit to the coefficients of t(X)
+	var yInv fr.Element
+	yInv.Inverse(&y)
+	yN := powers(y, n)
+	yInvN := powers(yInv, n)
+	wL, wR, wO, _ := vk.weights(z, publicInputs)
+
+	// l(X) = l₁⋅X + l₂⋅X² + l₃⋅X³, r(X) = r₀ + r₁⋅X + r₃⋅X³
+	l1, r0, r1, r3 := make([]fr.Element, n), make([]fr.Element, n), make([]fr.Element, n), make([]fr.Element, n)
+	l2, l3 := aO, sL
+	utils.Parallelize(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			l1[i].Mul(&yInvN[i], &wR[i]).Add(&l1[i], &aL[i])
+			r0[i].Sub(&wO[i], &yN[i])
+			r1[i].Mul(&yN[i], &aR[i]).Add(&r1[i], &wL[i])
+			r3[i].Mul(&yN[i], &sR[i])
+		}
+	})
+	var t [5]fr.Element
+	t[0] = innerProduct(l1, r0)
+	t[1] = innerProduct(l2, r1)
+	tmp := innerProduct(l3, r0)
+	t[1].Add(&t[1], &tmp)
+	t[2] = innerProduct(l1, r3)
+	tmp = innerProduct(l3, r1)
+	t[2].Add(&t[2], &tmp)
+	t[3] = innerProduct(l2, r3)
+	t[4] = innerProduct(l3, r3)
+	tau, err := randomVector(len(t))
+	if err != nil {
+		return nil, err
+	}
+	for i := range t {
+		if proof.T[i], err = vk.commitValue(t[i], tau[i]); err != nil {
+			return nil, err
+		}
+	}
+	x, err := deriveRandomness(&fs, "x", &proof.T[0], &proof.T[1], &proof.T[2], &proof.T[3], &proof.T[4])
+	if err != nil {
+		return nil, err
+	}
+	if x.IsZero() {
+		return nil, errors.New("null challenge")
+	}
+
+	// round 3: evaluate at x
+	var x2, x3 fr.Element
+	x2.Square(&x)
+	x3.Mul(&x2, &x)
+	l, r := make([]fr.Element, n), make([]fr.Element, n)
+	utils.Parallelize(n, func(start, end int) {
+		var u fr.Element
+		for i := start; i < end; i++ {
+			l[i].Mul(&l3[i], &x).Add(&l[i], &l2[i]).Mul(&l[i], &x).Add(&l[i], &l1[i]).Mul(&l[i], &x)
+			r[i].Mul(&r3[i], &x3).Add(&r[i], &r0[i])
+			u.Mul(&r1[i], &x)
+			r[i].Add(&r[i], &u)
+		}
+	})
+	proof.THat = innerProduct(l, r)
+	xPow := [5]fr.Element{x, x3}
+	xPow[2].Mul(&x3, &x)
+	xPow[3].Mul(&xPow[2], &x)
+	xPow[4].Mul(&xPow[3], &x)
+	for i := range tau {
+		tmp.Mul(&tau[i], &xPow[i])
+		proof.TauX.Add(&proof.TauX, &tmp)
+	}
+	proof.Mu.Mul(&blindings[2], &x).Add(&proof.Mu, &blindings[1]).Mul(&proof.Mu, &x).Add(&proof.Mu, &blindings[0]).Mul(&proof.Mu, &x)
+
+	// round 4: prove ⟨l(x), r(x)⟩ = t(x) against the generators G, y⁻ⁿ∘H
+	if err := bindValues(&fs, "u", proof.TauX, proof.Mu, proof.THat); err != nil {
+		return nil, err
+	}
+	xu, err := deriveRandomness(&fs, "u")
+	if err != nil {
+		return nil, err
+	}
+	var bXu big.Int
+	var uPrime curve.G1Affine
+	uPrime.ScalarMultiplication(&vk.U, xu.BigInt(&bXu))
+	h := make([]curve.G1Affine, n)
+	utils.Parallelize(n, func(start, end int) {
+		var b big.Int
+		for i := start; i < end; i++ {
+			h[i].ScalarMultiplication(&vk.H[i], yInvN[i].BigInt(&b))
+		}
+	})
+	g := append([]curve.G1Affine(nil), vk.G...)
+	if proof.Opening, err = proveInnerProduct(&fs, l, r, g, h, uPrime); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
+
+	return &proof, nil
+}
+
+// commitValue returns v⋅V + r⋅Blinding.
+func (vk *VerifyingKey) commitValue(v, r fr.Element) (curve.G1Affine, error) {
+	var res curve.G1Affine
+	if _, err := res.MultiExp([]curve.G1Affine{vk.V, vk.Blinding}, []fr.Element{v, r}, ecc.MultiExpConfig{}); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// randomVector returns n random field elements.
+func randomVector(n int) ([]fr.Element, error) {
+	res := make([]fr.Element, n)
+	for i := range res {
+		if _, err := res[i].SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,388 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bulletproofs implements the Bulletproofs Zero Knowledge Proof
+// system for arithmetic circuits. It needs no setup at all: the generators of
+// the commitments are hashed to the curve from a public seed.
+//
+// Bulletproofs consumes the R1CS compiled for Groth16, with one multiplication
+// gate per constraint and per witness variable, tied together by linear
+// constraints. Proofs are made of 2⋅log₂(n)+8 points and 5 scalars, where n
+// is the number of gates rounded up to a power of 2, which is about 1KB for a
+// 64 bits range check. Proving and verifying take time linear in n, so the
+// backend is meant for short statements, such as range proofs in wallets,
+// where a trusted setup ceremony is not an option.
+//
+// Circuits using commitments (frontend.Committer) are not supported.
+//
+// # See also
+//
+// https://eprint.iacr.org/2017/1066
+package bulletproofs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/internal/utils"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/consensys/gnark/backend/witness"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+
+	bulletproofs_bls12377 "github.com/consensys/gnark/backend/bulletproofs/bls12-377"
+	bulletproofs_bls12381 "github.com/consensys/gnark/backend/bulletproofs/bls12-381"
+	bulletproofs_bls24315 "github.com/consensys/gnark/backend/bulletproofs/bls24-315"
+	bulletproofs_bls24317 "github.com/consensys/gnark/backend/bulletproofs/bls24-317"
+	bulletproofs_bn254 "github.com/consensys/gnark/backend/bulletproofs/bn254"
+	bulletproofs_bw6633 "github.com/consensys/gnark/backend/bulletproofs/bw6-633"
+	bulletproofs_bw6761 "github.com/consensys/gnark/backend/bulletproofs/bw6-761"
+
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// Proof represents a Bulletproofs proof generated by bulletproofs.Prove
+//
+// it's underlying implementation is curve specific (see gnark/backend/bulletproofs)
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+}
+
+// ProvingKey represents a Bulletproofs ProvingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/bulletproofs)
+type ProvingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	VerifyingKey() interface{}
+}
+
+// VerifyingKey represents a Bulletproofs VerifyingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/bulletproofs)
+type VerifyingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	NbPublicWitness() int // number of elements expected in the public witness
+}
+
+var (
+	errProvingKeyCurve   = fmt.Errorf("%w: the proving key does not match the constraint system curve", gnark.ErrCurveMismatch)
+	errVerifyingKeyCurve = fmt.Errorf("%w: the verifying key does not match the proof curve", gnark.ErrCurveMismatch)
+)
+
+// checkR1CS returns an error if ccs was compiled for a PLONK backend.
+func checkR1CS(ccs constraint.ConstraintSystem) error {
+	if ccs == nil {
+		return nil
+	}
+	if _, ok := ccs.GetCommitments().(constraint.Groth16Commitments); !ok {
+		return errors.New("the constraint system is not a R1CS")
+	}
+	return nil
+}
+
+// Setup reads the matrices of the R1CS and derives the generators of the
+// commitment scheme from a public seed.
+func Setup(ccs constraint.ConstraintSystem, opts ...backend.SetupOption) (_ ProvingKey, _ VerifyingKey, err error) {
+	cfg, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(ccs); err != nil {
+		return nil, nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.R1CS:
+		return bulletproofs_bn254.Setup(tccs)
+	case *cs_bls12381.R1CS:
+		return bulletproofs_bls12381.Setup(tccs)
+	case *cs_bls12377.R1CS:
+		return bulletproofs_bls12377.Setup(tccs)
+	case *cs_bw6761.R1CS:
+		return bulletproofs_bw6761.Setup(tccs)
+	case *cs_bls24317.R1CS:
+		return bulletproofs_bls24317.Setup(tccs)
+	case *cs_bls24315.R1CS:
+		return bulletproofs_bls24315.Setup(tccs)
+	case *cs_bw6633.R1CS:
+		return bulletproofs_bw6633.Setup(tccs)
+	default:
+		return nil, nil, errors.New("unrecognized R1CS curve type")
+	}
+
+}
+
+// Prove generates a Bulletproofs proof from a circuit, its proving key and the full
+// witness.
+func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(ccs); err != nil {
+		return nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.R1CS:
+		_pk, ok := pk.(*bulletproofs_bn254.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return bulletproofs_bn254.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls12381.R1CS:
+		_pk, ok := pk.(*bulletproofs_bls12381.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return bulletproofs_bls12381.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls12377.R1CS:
+		_pk, ok := pk.(*bulletproofs_bls12377.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return bulletproofs_bls12377.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bw6761.R1CS:
+		_pk, ok := pk.(*bulletproofs_bw6761.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return bulletproofs_bw6761.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls24317.R1CS:
+		_pk, ok := pk.(*bulletproofs_bls24317.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return bulletproofs_bls24317.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls24315.R1CS:
+		_pk, ok := pk.(*bulletproofs_bls24315.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return bulletproofs_bls24315.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bw6633.R1CS:
+		_pk, ok := pk.(*bulletproofs_bw6633.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return bulletproofs_bw6633.Prove(tccs, _pk, fullWitness, opts...)
+	default:
+		return nil, errors.New("unrecognized R1CS curve type")
+	}
+}
+
+// Verify verifies a Bulletproofs proof, from the proof, the verifying key and the
+// public witness.
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) (err error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+
+	switch _proof := proof.(type) {
+
+	case *bulletproofs_bn254.Proof:
+		w, ok := publicWitness.Vector().(fr_bn254.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*bulletproofs_bn254.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return bulletproofs_bn254.Verify(_proof, _vk, w, opts...)
+
+	case *bulletproofs_bls12381.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*bulletproofs_bls12381.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return bulletproofs_bls12381.Verify(_proof, _vk, w, opts...)
+
+	case *bulletproofs_bls12377.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12377.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*bulletproofs_bls12377.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return bulletproofs_bls12377.Verify(_proof, _vk, w, opts...)
+
+	case *bulletproofs_bw6761.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*bulletproofs_bw6761.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return bulletproofs_bw6761.Verify(_proof, _vk, w, opts...)
+
+	case *bulletproofs_bls24317.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24317.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*bulletproofs_bls24317.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return bulletproofs_bls24317.Verify(_proof, _vk, w, opts...)
+
+	case *bulletproofs_bls24315.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*bulletproofs_bls24315.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return bulletproofs_bls24315.Verify(_proof, _vk, w, opts...)
+
+	case *bulletproofs_bw6633.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6633.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*bulletproofs_bw6633.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return bulletproofs_bw6633.Verify(_proof, _vk, w, opts...)
+	default:
+		return errors.New("unrecognized proof type")
+	}
+}
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
+// This function exists for serialization purposes
+func NewProvingKey(curveID ecc.ID) ProvingKey {
+	var pk ProvingKey
+	switch curveID {
+	case ecc.BN254:
+		pk = &bulletproofs_bn254.ProvingKey{}
+	case ecc.BLS12_377:
+		pk = &bulletproofs_bls12377.ProvingKey{}
+	case ecc.BLS12_381:
+		pk = &bulletproofs_bls12381.ProvingKey{}
+	case ecc.BW6_761:
+		pk = &bulletproofs_bw6761.ProvingKey{}
+	case ecc.BLS24_317:
+		pk = &bulletproofs_bls24317.ProvingKey{}
+	case ecc.BLS24_315:
+		pk = &bulletproofs_bls24315.ProvingKey{}
+	case ecc.BW6_633:
+		pk = &bulletproofs_bw6633.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pk
+}
+
+// NewProof instantiates a curve-typed Proof and returns an interface
+// This function exists for serialization purposes
+func NewProof(curveID ecc.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case ecc.BN254:
+		proof = &bulletproofs_bn254.Proof{}
+	case ecc.BLS12_377:
+		proof = &bulletproofs_bls12377.Proof{}
+	case ecc.BLS12_381:
+		proof = &bulletproofs_bls12381.Proof{}
+	case ecc.BW6_761:
+		proof = &bulletproofs_bw6761.Proof{}
+	case ecc.BLS24_317:
+		proof = &bulletproofs_bls24317.Proof{}
+	case ecc.BLS24_315:
+		proof = &bulletproofs_bls24315.Proof{}
+	case ecc.BW6_633:
+		proof = &bulletproofs_bw6633.Proof{}
+	default:
+		panic("not implemented")
+	}
+
+	return proof
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var vk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		vk = &bulletproofs_bn254.VerifyingKey{}
+	case ecc.BLS12_377:
+		vk = &bulletproofs_bls12377.VerifyingKey{}
+	case ecc.BLS12_381:
+		vk = &bulletproofs_bls12381.VerifyingKey{}
+	case ecc.BW6_761:
+		vk = &bulletproofs_bw6761.VerifyingKey{}
+	case ecc.BLS24_317:
+		vk = &bulletproofs_bls24317.VerifyingKey{}
+	case ecc.BLS24_315:
+		vk = &bulletproofs_bls24315.VerifyingKey{}
+	case ecc.BW6_633:
+		vk = &bulletproofs_bw6633.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return vk
+}
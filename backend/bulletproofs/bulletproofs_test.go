@@ -0,0 +1,217 @@
+package bulletproofs_test
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	curve_bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/bulletproofs"
+	bulletproofs_bn254 "github.com/consensys/gnark/backend/bulletproofs/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type refCircuit struct {
+	nbConstraints int
+	X             frontend.Variable
+	Y             frontend.Variable `gnark:",public"`
+	Z             frontend.Variable `gnark:",public"`
+}
+
+func (circuit *refCircuit) Define(api frontend.API) error {
+	x := circuit.X
+	for i := 0; i < circuit.nbConstraints; i++ {
+		x = api.Mul(x, x)
+	}
+	api.AssertIsEqual(x, circuit.Y)
+	api.AssertIsEqual(api.Add(circuit.X, api.Mul(3, circuit.Y), 5), circuit.Z)
+	return nil
+}
+
+func referenceAssignment(curve ecc.ID, nbConstraints int) *refCircuit {
+	var good refCircuit
+	good.X = 2
+
+	// compute expected Y and Z
+	expectedY := new(big.Int).SetUint64(2)
+	exp := big.NewInt(1)
+	exp.Lsh(exp, uint(nbConstraints))
+	expectedY.Exp(expectedY, exp, curve.ScalarField())
+	expectedZ := new(big.Int).Mul(expectedY, big.NewInt(3))
+	expectedZ.Add(expectedZ, big.NewInt(7)).Mod(expectedZ, curve.ScalarField())
+
+	good.Y = expectedY
+	good.Z = expectedZ
+	return &good
+}
+
+func TestProver(t *testing.T) {
+	const nbConstraints = 100
+	for _, curve := range getCurves() {
+		t.Run(curve.String(), func(t *testing.T) {
+			var b1, b2 bytes.Buffer
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+			assert.NoError(err)
+			fullWitness, err := frontend.NewWitness(referenceAssignment(curve, nbConstraints), curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			pk, vk, err := bulletproofs.Setup(ccs)
+			assert.NoError(err)
+
+			// write the PK to ensure it is not mutated
+			_, err = pk.WriteTo(&b1)
+			assert.NoError(err)
+
+			proof, err := bulletproofs.Prove(ccs, pk, fullWitness)
+			assert.NoError(err)
+
+			// check pk
+			_, err = pk.WriteTo(&b2)
+			assert.NoError(err)
+
+			assert.True(bytes.Equal(b1.Bytes(), b2.Bytes()), "bulletproofs prover mutated the proving key")
+
+			assert.NoError(bulletproofs.Verify(proof, vk, publicWitness))
+
+			// wrong public witness
+			bad := referenceAssignment(curve, nbConstraints)
+			bad.Z = 1
+			badWitness, err := frontend.NewWitness(bad, curve.ScalarField(), frontend.PublicOnly())
+			assert.NoError(err)
+			assert.Error(bulletproofs.Verify(proof, vk, badWitness))
+
+			// unsatisfied constraints
+			_, err = bulletproofs.Prove(ccs, pk, badFullWitness(t, curve, nbConstraints))
+			assert.Error(err)
+		})
+	}
+}
+
+func TestTamperedProof(t *testing.T) {
+	const nbConstraints = 10
+	assert := require.New(t)
+	curve := ecc.BN254
+
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(referenceAssignment(curve, nbConstraints), curve.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	pk, vk, err := bulletproofs.Setup(ccs)
+	assert.NoError(err)
+	proof, err := bulletproofs.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	assert.NoError(bulletproofs.Verify(proof, vk, publicWitness))
+
+	tampered := *proof.(*bulletproofs_bn254.Proof)
+	tampered.THat.SetOne()
+	assert.Error(bulletproofs.Verify(&tampered, vk, publicWitness))
+
+	tampered = *proof.(*bulletproofs_bn254.Proof)
+	tampered.T[2], tampered.T[3] = tampered.T[3], tampered.T[2]
+	assert.Error(bulletproofs.Verify(&tampered, vk, publicWitness))
+
+	tampered = *proof.(*bulletproofs_bn254.Proof)
+	tampered.AI, tampered.AO = tampered.AO, tampered.AI
+	assert.Error(bulletproofs.Verify(&tampered, vk, publicWitness))
+
+	tampered = *proof.(*bulletproofs_bn254.Proof)
+	tampered.Opening.A.SetOne()
+	assert.Error(bulletproofs.Verify(&tampered, vk, publicWitness))
+
+	tampered = *proof.(*bulletproofs_bn254.Proof)
+	tampered.Opening.L = append([]curve_bn254.G1Affine(nil), tampered.Opening.L...)
+	tampered.Opening.L[0], tampered.Opening.L[1] = tampered.Opening.L[1], tampered.Opening.L[0]
+	assert.Error(bulletproofs.Verify(&tampered, vk, publicWitness))
+
+	tampered = *proof.(*bulletproofs_bn254.Proof)
+	tampered.Opening.R = tampered.Opening.R[1:]
+	assert.Error(bulletproofs.Verify(&tampered, vk, publicWitness))
+
+	// the challenges depend on the hash function
+	assert.Error(bulletproofs.Verify(proof, vk, publicWitness, backend.WithVerifierChallengeHashFunction(sha512.New())))
+}
+
+// rangeCircuit proves that Balance - Amount is in [0, 2⁶⁴), for a secret
+// balance and a public amount.
+type rangeCircuit struct {
+	Balance frontend.Variable
+	Amount  frontend.Variable `gnark:",public"`
+}
+
+func (circuit *rangeCircuit) Define(api frontend.API) error {
+	api.ToBinary(api.Sub(circuit.Balance, circuit.Amount), 64)
+	return nil
+}
+
+func TestRangeProof(t *testing.T) {
+	assert := require.New(t)
+	curve := ecc.BN254
+
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &rangeCircuit{})
+	assert.NoError(err)
+	pk, vk, err := bulletproofs.Setup(ccs)
+	assert.NoError(err)
+
+	fullWitness, err := frontend.NewWitness(&rangeCircuit{Balance: 1000, Amount: 400}, curve.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+	proof, err := bulletproofs.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	assert.NoError(bulletproofs.Verify(proof, vk, publicWitness))
+
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	assert.LessOrEqual(buf.Len(), 1024, "range proof is too large")
+
+	// the proof is bound to the public amount
+	badWitness, err := frontend.NewWitness(&rangeCircuit{Amount: 300}, curve.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	assert.Error(bulletproofs.Verify(proof, vk, badWitness))
+
+	// the balance is too low
+	fullWitness, err = frontend.NewWitness(&rangeCircuit{Balance: 400, Amount: 1000}, curve.ScalarField())
+	assert.NoError(err)
+	_, err = bulletproofs.Prove(ccs, pk, fullWitness)
+	assert.Error(err)
+}
+
+func TestCheckCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&refCircuit{nbConstraints: 5},
+		test.WithValidAssignment(referenceAssignment(ecc.BN254, 5)),
+		test.WithCurves(ecc.BN254),
+		test.WithBackends(backend.BULLETPROOFS),
+	)
+}
+
+func badFullWitness(t *testing.T, curve ecc.ID, nbConstraints int) witness.Witness {
+	bad := referenceAssignment(curve, nbConstraints)
+	bad.X = 3
+	w, err := frontend.NewWitness(bad, curve.ScalarField())
+	require.NoError(t, err)
+	return w
+}
+
+func getCurves() []ecc.ID {
+	if testing.Short() {
+		return []ecc.ID{ecc.BN254}
+	}
+	return gnark.Curves()
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package bulletproofs
+
+import (
+	"errors"
+	curve "github.com/consensys/gnark-crypto/ecc/bw6-633"
+	"io"
+)
+
+// WriteRawTo writes binary encoding of Proof to w without point compression
+func (proof *Proof) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, curve.RawEncoding())
+}
+
+// WriteTo writes binary encoding of Proof to w with point compression
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w)
+}
+
+func (proof *Proof) writeTo(w io.Writer, options ...func(*curve.Encoder)) (int64, error) {
+	enc := curve.NewEncoder(w, options...)
+
+	toEncode := []interface{}{
+		&proof.AI,
+		&proof.AO,
+		&proof.S,
+	}
+	for i := range proof.T {
+		toEncode = append(toEncode, &proof.T[i])
+	}
+	toEncode = append(toEncode,
+		&proof.TauX,
+		&proof.Mu,
+		&proof.THat,
+		proof.Opening.L,
+		proof.Opening.R,
+		&proof.Opening.A,
+		&proof.Opening.B,
+	)
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads binary representation of Proof from r
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	toDecode := []interface{}{
+		&proof.AI,
+		&proof.AO,
+		&proof.S,
+	}
+	for i := range proof.T {
+		toDecode = append(toDecode, &proof.T[i])
+	}
+	toDecode = append(toDecode,
+		&proof.TauX,
+		&proof.Mu,
+		&proof.THat,
+		&proof.Opening.L,
+		&proof.Opening.R,
+		&proof.Opening.A,
+		&proof.Opening.B,
+	)
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of ProvingKey to w
+func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.Vk.WriteTo(w)
+}
+
+// WriteRawTo writes binary encoding of ProvingKey to w without point compression
+func (pk *ProvingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return pk.Vk.WriteRawTo(w)
+}
+
+// ReadFrom reads from binary representation in r into ProvingKey
+func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	pk.Vk = &VerifyingKey{}
+	return pk.Vk.ReadFrom(r)
+}
+
+// UnsafeReadFrom reads from binary representation in r into ProvingKey without subgroup checks
+func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	pk.Vk = &VerifyingKey{}
+	return pk.Vk.UnsafeReadFrom(r)
+}
+
+// WriteTo writes binary encoding of VerifyingKey to w
+func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return vk.writeTo(w)
+}
+
+// WriteRawTo writes binary encoding of VerifyingKey to w without point compression
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return vk.writeTo(w, curve.RawEncoding())
+}
+
+func (vk *VerifyingKey) writeTo(w io.Writer, options ...func(*curve.Encoder)) (n int64, err error) {
+	enc := curve.NewEncoder(w, options...)
+
+	toEncode := []interface{}{
+		vk.NbGates,
+		vk.NbConstraints,
+		vk.NbPublicVariables,
+		vk.NbWitnessVariables,
+	}
+	for i := range vk.Index {
+		toEncode = append(toEncode, vk.Index[i].Rows, vk.Index[i].Cols, vk.Index[i].Coeffs)
+	}
+	toEncode = append(toEncode, vk.G, vk.H, &vk.V, &vk.Blinding, &vk.U)
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads from binary representation in r into VerifyingKey
+func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	return vk.readFrom(r)
+}
+
+// UnsafeReadFrom reads from binary representation in r into VerifyingKey
+// without subgroup checks
+func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return vk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
+	toDecode := []interface{}{
+		&vk.NbGates,
+		&vk.NbConstraints,
+		&vk.NbPublicVariables,
+		&vk.NbWitnessVariables,
+	}
+	for i := range vk.Index {
+		toDecode = append(toDecode, &vk.Index[i].Rows, &vk.Index[i].Cols, &vk.Index[i].Coeffs)
+	}
+	toDecode = append(toDecode, &vk.G, &vk.H, &vk.V, &vk.Blinding, &vk.U)
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	if !isPowerOfTwo(vk.NbGates) || vk.NbConstraints+vk.NbWitnessVariables > vk.NbGates ||
+		uint64(len(vk.G)) != vk.NbGates || uint64(len(vk.H)) != vk.NbGates || vk.NbPublicVariables == 0 {
+		return dec.BytesRead(), errors.New("invalid verifying key sizes")
+	}
+	for i := range vk.Index {
+		m := &vk.Index[i]
+		if len(m.Rows) != len(m.Coeffs) || len(m.Cols) != len(m.Coeffs) {
+			return dec.BytesRead(), errors.New("invalid matrix encoding")
+		}
+		for j := range m.Coeffs {
+			if m.Rows[j] >= vk.NbConstraints || m.Cols[j] >= vk.NbPublicVariables+vk.NbWitnessVariables {
+				return dec.BytesRead(), errors.New("invalid matrix encoding")
+			}
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+func isPowerOfTwo(n uint64) bool {
+	return n != 0 && n&(n-1) == 0
+}
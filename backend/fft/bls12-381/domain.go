@@ -0,0 +1,315 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package fft
+
+import (
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gcfft "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark/internal/utils"
+	"math/big"
+	"math/bits"
+	"runtime"
+)
+
+// Domain is a multiplicative subgroup of the scalar field of power of two
+// cardinality, with the precomputed twiddle factors of the FFT and the powers
+// of its coset shift. It is the domain used by the provers of the backends.
+type Domain = gcfft.Domain
+
+// Decimation selects the decimation in time or in frequency of the FFT, see
+// [Domain.FFT].
+type Decimation = gcfft.Decimation
+
+const (
+	// DIT is the decimation in time, which takes its input in bit-reversed
+	// order.
+	DIT = gcfft.DIT
+	// DIF is the decimation in frequency, which returns its output in
+	// bit-reversed order.
+	DIF = gcfft.DIF
+)
+
+// DomainOption configures a domain, see [NewDomain].
+type DomainOption func(*domainConfig)
+
+type domainConfig struct {
+	shift *fr.Element
+}
+
+// WithCosetShift sets the shift of the coset of the domain used by the
+// evaluations on a coset. By default, it is the multiplicative generator of the
+// scalar field, as in the provers.
+func WithCosetShift(shift fr.Element) DomainOption {
+	return func(c *domainConfig) {
+		c.shift = &shift
+	}
+}
+
+// NewDomain returns the domain of smallest cardinality greater than or equal to
+// m. It returns an error if the scalar field has no such subgroup, or if the
+// coset shift is zero or in the domain.
+func NewDomain(m uint64, opts ...DomainOption) (*Domain, error) {
+	var cfg domainConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if _, err := gcfft.Generator(m); err != nil {
+		return nil, err
+	}
+	if cfg.shift == nil {
+		return gcfft.NewDomain(m), nil
+	}
+	d := gcfft.NewDomain(m, *cfg.shift)
+	var sn fr.Element
+	sn.Exp(*cfg.shift, new(big.Int).SetUint64(d.Cardinality))
+	if cfg.shift.IsZero() || sn.IsOne() {
+		return nil, errors.New("coset shift in the domain")
+	}
+	return d, nil
+}
+
+// Roots returns the elements 1, ω, ..., ωⁿ⁻¹ of the domain d, where ω is its
+// generator and n its cardinality.
+func Roots(d *Domain) []fr.Element {
+	return powers(fr.One(), d.Generator, d.Cardinality)
+}
+
+// CosetRoots returns the elements u, uω, ..., uωⁿ⁻¹ of the coset of the domain
+// d, where u is the coset shift.
+func CosetRoots(d *Domain) []fr.Element {
+	return powers(d.FrMultiplicativeGen, d.Generator, d.Cardinality)
+}
+
+// BitReverse applies the bit-reversal permutation to v, whose length must be a
+// power of two. It works with the elements of the scalar field as well as with
+// curve points, for example to reorder a SRS in Lagrange form.
+func BitReverse[T any](v []T) {
+	n := uint64(len(v))
+	if bits.OnesCount64(n) != 1 {
+		panic("len(v) must be a power of 2")
+	}
+	nn := uint64(64 - bits.TrailingZeros64(n))
+	for i := uint64(0); i < n; i++ {
+		iRev := bits.Reverse64(i) >> nn
+		if iRev > i {
+			v[i], v[iRev] = v[iRev], v[i]
+		}
+	}
+}
+
+// EvaluationOption configures the batch evaluations and interpolations.
+type EvaluationOption func(*evaluationConfig)
+
+type evaluationConfig struct {
+	coset       bool
+	bitReversed bool
+}
+
+// OnCoset sets the points of the evaluations to the coset of the domain
+// instead of the domain itself.
+func OnCoset() EvaluationOption {
+	return func(c *evaluationConfig) {
+		c.coset = true
+	}
+}
+
+// BitReversed sets the evaluations in bit-reversed order instead of the natural
+// order, which saves a permutation when the caller doesn't need the latter.
+func BitReversed() EvaluationOption {
+	return func(c *evaluationConfig) {
+		c.bitReversed = true
+	}
+}
+
+// Evaluate returns the evaluations on the domain d of the polynomials given by
+// their coefficients. The polynomials must have at most n coefficients, where n
+// is the cardinality of d, and are left unchanged. The FFTs run in parallel.
+func Evaluate(d *Domain, polys [][]fr.Element, opts ...EvaluationOption) ([][]fr.Element, error) {
+	cfg := evaluationOptions(opts)
+	res := make([][]fr.Element, len(polys))
+	for i := range polys {
+		if uint64(len(polys[i])) > d.Cardinality {
+			return nil, errors.New("polynomial degree larger than the domain")
+		}
+		res[i] = make([]fr.Element, d.Cardinality)
+		copy(res[i], polys[i])
+	}
+	batch(res, func(p []fr.Element, fftOpts []gcfft.Option) {
+		if cfg.coset {
+			fftOpts = append(fftOpts, gcfft.OnCoset())
+		}
+		d.FFT(p, DIF, fftOpts...)
+		if !cfg.bitReversed {
+			BitReverse(p)
+		}
+	})
+	return res, nil
+}
+
+// Interpolate returns the coefficients of the polynomials given by their
+// evaluations on the domain d, the inverse of [Evaluate] with the same options.
+// The evaluations are left unchanged.
+func Interpolate(d *Domain, evals [][]fr.Element, opts ...EvaluationOption) ([][]fr.Element, error) {
+	cfg := evaluationOptions(opts)
+	res := make([][]fr.Element, len(evals))
+	for i := range evals {
+		if uint64(len(evals[i])) != d.Cardinality {
+			return nil, errors.New("number of evaluations different from the domain cardinality")
+		}
+		res[i] = make([]fr.Element, d.Cardinality)
+		copy(res[i], evals[i])
+	}
+	batch(res, func(p []fr.Element, fftOpts []gcfft.Option) {
+		if cfg.coset {
+			fftOpts = append(fftOpts, gcfft.OnCoset())
+		}
+		if cfg.bitReversed {
+			d.FFTInverse(p, DIT, fftOpts...)
+		} else {
+			d.FFTInverse(p, DIF, fftOpts...)
+			BitReverse(p)
+		}
+	})
+	return res, nil
+}
+
+// EvaluateLagrange returns the evaluations at z of the polynomials given by
+// their evaluations on the domain d in the natural order, or on its coset with
+// the option [OnCoset]. It uses the barycentric formula
+//
+//	p(z) = (zⁿ - uⁿ)/(n·uⁿ) ∑ pᵢ·xᵢ/(z - xᵢ)
+//
+// where xᵢ = uωⁱ are the points of the evaluations, so that the inversions are
+// shared by all the polynomials.
+func EvaluateLagrange(d *Domain, evals [][]fr.Element, z fr.Element, opts ...EvaluationOption) ([]fr.Element, error) {
+	cfg := evaluationOptions(opts)
+	if cfg.bitReversed {
+		return nil, errors.New("bit-reversed order not supported")
+	}
+	for i := range evals {
+		if uint64(len(evals[i])) != d.Cardinality {
+			return nil, errors.New("number of evaluations different from the domain cardinality")
+		}
+	}
+	var points []fr.Element
+	if cfg.coset {
+		points = CosetRoots(d)
+	} else {
+		points = Roots(d)
+	}
+	res := make([]fr.Element, len(evals))
+
+	// z is one of the points
+	for i := range points {
+		if points[i].Equal(&z) {
+			for j := range evals {
+				res[j] = evals[j][i]
+			}
+			return res, nil
+		}
+	}
+
+	// weights xᵢ/(z - xᵢ)
+	weights := make([]fr.Element, len(points))
+	for i := range points {
+		weights[i].Sub(&z, &points[i])
+	}
+	weights = fr.BatchInvert(weights)
+	for i := range points {
+		weights[i].Mul(&weights[i], &points[i])
+	}
+
+	// (zⁿ - uⁿ)/(n·uⁿ)
+	n := new(big.Int).SetUint64(d.Cardinality)
+	var factor, un fr.Element
+	factor.Exp(z, n)
+	un.SetOne()
+	if cfg.coset {
+		un.Exp(d.FrMultiplicativeGen, n)
+	}
+	factor.Sub(&factor, &un)
+	un.Inverse(&un)
+	factor.Mul(&factor, &un).Mul(&factor, &d.CardinalityInv)
+
+	utils.Parallelize(len(evals), func(start, end int) {
+		for j := start; j < end; j++ {
+			var t fr.Element
+			for i := range weights {
+				t.Mul(&evals[j][i], &weights[i])
+				res[j].Add(&res[j], &t)
+			}
+			res[j].Mul(&res[j], &factor)
+		}
+	})
+	return res, nil
+}
+
+// EvaluateVanishingOnCoset returns the evaluations of the vanishing polynomial
+// Xᵐ - 1 of domainSmall on the coset of domainBig, where m is the cardinality
+// of domainSmall. As they take only n/m distinct values, where n is the
+// cardinality of domainBig, the i-th evaluation is at index i mod n/m.
+func EvaluateVanishingOnCoset(domainSmall, domainBig *Domain) ([]fr.Element, error) {
+	if domainSmall.Cardinality > domainBig.Cardinality {
+		return nil, errors.New("the small domain is larger than the big one")
+	}
+	ratio := domainBig.Cardinality / domainSmall.Cardinality
+	m := new(big.Int).SetUint64(domainSmall.Cardinality)
+	var first, step fr.Element
+	first.Exp(domainBig.FrMultiplicativeGen, m)
+	step.Exp(domainBig.Generator, m)
+	res := powers(first, step, ratio)
+	one := fr.One()
+	for i := range res {
+		res[i].Sub(&res[i], &one)
+	}
+	return res, nil
+}
+
+// powers returns a, ab, ..., abⁿ⁻¹.
+func powers(a, b fr.Element, n uint64) []fr.Element {
+	res := make([]fr.Element, n)
+	res[0] = a
+	for i := uint64(1); i < n; i++ {
+		res[i].Mul(&res[i-1], &b)
+	}
+	return res
+}
+
+func evaluationOptions(opts []EvaluationOption) evaluationConfig {
+	var cfg evaluationConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return cfg
+}
+
+// batch runs f on each vector in parallel, sharing the CPUs between the FFTs.
+func batch(vectors [][]fr.Element, f func(v []fr.Element, opts []gcfft.Option)) {
+	if len(vectors) == 0 {
+		return
+	}
+	nbTasks := runtime.NumCPU() / len(vectors)
+	if nbTasks < 1 {
+		nbTasks = 1
+	}
+	utils.Parallelize(len(vectors), func(start, end int) {
+		for i := start; i < end; i++ {
+			f(vectors[i], []gcfft.Option{gcfft.WithNbTasks(nbTasks)})
+		}
+	})
+}
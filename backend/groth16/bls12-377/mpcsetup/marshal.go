@@ -150,6 +150,7 @@ func (c *Phase2Evaluations) WriteTo(writer io.Writer) (int64, error) {
 	toEncode := []interface{}{
 		c.G1.A,
 		c.G1.B,
+		c.G1.VKK,
 		c.G2.B,
 	}
 
@@ -168,6 +169,7 @@ func (c *Phase2Evaluations) ReadFrom(reader io.Reader) (int64, error) {
 	toEncode := []interface{}{
 		&c.G1.A,
 		&c.G1.B,
+		&c.G1.VKK,
 		&c.G2.B,
 	}
 
@@ -23,6 +23,7 @@ import (
 
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark/backend/beacon"
 	"github.com/consensys/gnark/constraint"
 	cs "github.com/consensys/gnark/constraint/bls12-377"
 )
@@ -180,9 +181,21 @@ func InitPhase2(r1cs *cs.R1CS, srs1 *Phase1) (Phase2, Phase2Evaluations) {
 
 func (c *Phase2) Contribute() {
 	// Sample toxic δ
-	var delta, deltaInv fr.Element
-	var deltaBI, deltaInvBI big.Int
+	var delta fr.Element
 	delta.SetRandom()
+	c.contribute(delta)
+}
+
+// ContributeFromBeacon finalizes phase 2 with a contribution derived from the
+// randomness of a public beacon round. The round must be fixed before the last
+// contribution is made, and the beacon verified with [beacon.Beacon.Verify].
+func (c *Phase2) ContributeFromBeacon(b *beacon.Beacon) {
+	c.contribute(beaconScalars(b, c.Hash, 1)[0])
+}
+
+func (c *Phase2) contribute(delta fr.Element) {
+	var deltaInv fr.Element
+	var deltaBI, deltaInvBI big.Int
 	deltaInv.Inverse(&delta)
 
 	delta.BigInt(&deltaBI)
@@ -219,6 +232,23 @@ func VerifyPhase2(c0, c1 *Phase2, c ...*Phase2) error {
 	return nil
 }
 
+// VerifyPhase2Beacon checks that contribution is the contribution derived
+// from the beacon round b on top of current. The beacon itself must be
+// verified with [beacon.Beacon.Verify].
+func VerifyPhase2Beacon(current, contribution *Phase2, b *beacon.Beacon) error {
+	if err := verifyPhase2(current, contribution); err != nil {
+		return err
+	}
+	var deltaBI big.Int
+	beaconScalars(b, current.Hash, 1)[0].BigInt(&deltaBI)
+	var delta curve.G2Affine
+	delta.ScalarMultiplication(&current.Parameters.G2.Delta, &deltaBI)
+	if !delta.Equal(&contribution.Parameters.G2.Delta) {
+		return errors.New("couldn't verify that δ is derived from the beacon")
+	}
+	return nil
+}
+
 func verifyPhase2(current, contribution *Phase2) error {
 	// Compute R for δ
 	deltaR := genR(contribution.PublicKey.SG, contribution.PublicKey.SXG, current.Hash[:], 1)
@@ -46,8 +46,9 @@ func TestContributionSerialization(t *testing.T) {
 	r1cs := ccs.(*cs.R1CS)
 
 	// Phase 2
-	srs2, _ := InitPhase2(r1cs, &srs1)
+	srs2, evals := InitPhase2(r1cs, &srs1)
 	srs2.Contribute()
 
 	assert.NoError(gnarkio.RoundTripCheck(&srs2, func() interface{} { return new(Phase2) }))
+	assert.NoError(gnarkio.RoundTripCheck(&evals, func() interface{} { return new(Phase2Evaluations) }))
 }
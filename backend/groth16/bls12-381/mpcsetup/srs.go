@@ -0,0 +1,43 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package mpcsetup
+
+import (
+	"fmt"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+// KZGSRS returns a KZG structured reference string of the given size, made of
+// the powers of τ of phase1. It can be used with PLONK instead of a locally
+// generated SRS. phase1 must be the verified final state of the Phase 1 of a
+// ceremony, for example imported from a public transcript.
+func (phase1 *Phase1) KZGSRS(size uint64) (*kzg.SRS, error) {
+	tau1, tau2 := phase1.Parameters.G1.Tau, phase1.Parameters.G2.Tau
+	if size < 2 {
+		return nil, kzg.ErrMinSRSSize
+	}
+	if size > uint64(len(tau1)) || len(tau2) < 2 {
+		return nil, fmt.Errorf("the Phase 1 has %d powers of τ, %d requested", len(tau1), size)
+	}
+
+	var srs kzg.SRS
+	srs.Pk.G1 = append([]curve.G1Affine(nil), tau1[:size]...)
+	srs.Vk.G1 = tau1[0]
+	srs.Vk.G2 = [2]curve.G2Affine{tau2[0], tau2[1]}
+	return &srs, nil
+}
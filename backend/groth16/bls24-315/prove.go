@@ -18,6 +18,7 @@ package groth16
 
 import (
 	"fmt"
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
@@ -25,6 +26,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/hash_to_field"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/pedersen"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/accelerator"
 	"github.com/consensys/gnark/backend/groth16/internal"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
@@ -47,6 +49,31 @@ type Proof struct {
 	CommitmentPok curve.G1Affine   // Batched proof of knowledge of the above commitments
 }
 
+// errKeyMismatch is returned by the provers when the proving key was not
+// generated for the constraint system.
+var errKeyMismatch = fmt.Errorf("%w: the proving key does not match the constraint system", gnark.ErrKeyMismatch)
+
+// checkSizes returns an error if the sizes of the proving key are not the ones
+// of a key generated for r1cs. The prover works on several goroutines, where
+// a mismatched key would make it panic.
+func (pk *ProvingKey) checkSizes(r1cs *cs.R1CS) error {
+	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
+	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
+	nbPrivateWires := r1cs.GetNbSecretVariables() + r1cs.NbInternalVariables - internal.NbElements(commitmentInfo.GetPrivateCommitted()) - len(commitmentInfo)
+
+	if len(pk.InfinityA) != nbWires || len(pk.InfinityB) != nbWires ||
+		uint64(len(pk.G1.A))+pk.NbInfinityA != uint64(nbWires) ||
+		uint64(len(pk.G1.B))+pk.NbInfinityB != uint64(nbWires) ||
+		uint64(len(pk.G2.B))+pk.NbInfinityB != uint64(nbWires) ||
+		len(pk.G1.K) != nbPrivateWires ||
+		pk.Domain.Cardinality < uint64(r1cs.GetNbConstraints()) ||
+		uint64(len(pk.G1.Z))+1 != pk.Domain.Cardinality ||
+		len(pk.CommitmentKeys) != len(commitmentInfo) {
+		return errKeyMismatch
+	}
+	return nil
+}
+
 // isValid ensures proof elements are in the correct subgroup
 func (proof *Proof) isValid() bool {
 	return proof.Ar.IsInSubGroup() && proof.Krs.IsInSubGroup() && proof.Bs.IsInSubGroup()
@@ -69,78 +96,32 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 
 	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Int("nbConstraints", r1cs.GetNbConstraints()).Str("backend", "groth16").Logger()
 
+	if err := pk.checkSizes(r1cs); err != nil {
+		return nil, err
+	}
+
 	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
 
 	proof := &Proof{Commitments: make([]curve.G1Affine, len(commitmentInfo))}
 
-	solverOpts := opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)]
-
-	privateCommittedValues := make([][]fr.Element, len(commitmentInfo))
-	for i := range commitmentInfo {
-		solverOpts = append(solverOpts, solver.OverrideHint(commitmentInfo[i].HintID, func(i int) solver.Hint {
-			return func(_ *big.Int, in []*big.Int, out []*big.Int) error {
-				privateCommittedValues[i] = make([]fr.Element, len(commitmentInfo[i].PrivateCommitted))
-				hashed := in[:len(commitmentInfo[i].PublicAndCommitmentCommitted)]
-				committed := in[len(hashed):]
-				for j, inJ := range committed {
-					privateCommittedValues[i][j].SetBigInt(inJ)
-				}
-
-				var err error
-				if proof.Commitments[i], err = pk.CommitmentKeys[i].Commit(privateCommittedValues[i]); err != nil {
-					return err
-				}
-
-				opt.HashToFieldFn.Write(constraint.SerializeCommitment(proof.Commitments[i].Marshal(), hashed, (fr.Bits-1)/8+1))
-				hashBts := opt.HashToFieldFn.Sum(nil)
-				opt.HashToFieldFn.Reset()
-				nbBuf := fr.Bytes
-				if opt.HashToFieldFn.Size() < fr.Bytes {
-					nbBuf = opt.HashToFieldFn.Size()
-				}
-				var res fr.Element
-				res.SetBytes(hashBts[:nbBuf])
-				res.BigInt(out[0])
-				return err
-			}
-		}(i)))
-	}
-
-	if r1cs.GkrInfo.Is() {
-		var gkrData cs.GkrSolvingData
-		solverOpts = append(solverOpts,
-			solver.OverrideHint(r1cs.GkrInfo.SolveHintID, cs.GkrSolveHint(r1cs.GkrInfo, &gkrData)),
-			solver.OverrideHint(r1cs.GkrInfo.ProveHintID, cs.GkrProveHint(r1cs.GkrInfo.HashName, &gkrData)))
-	}
-
-	_solution, err := r1cs.Solve(fullWitness, solverOpts...)
+	solution, err := solve(r1cs, pk.CommitmentKeys, fullWitness, &opt, proof)
 	if err != nil {
 		return nil, err
 	}
-
-	solution := _solution.(*cs.R1CSSolution)
 	wireValues := []fr.Element(solution.W)
 
 	start := time.Now()
 
-	commitmentsSerialized := make([]byte, fr.Bytes*len(commitmentInfo))
-	for i := range commitmentInfo {
-		copy(commitmentsSerialized[fr.Bytes*i:], wireValues[commitmentInfo[i].CommitmentIndex].Marshal())
-	}
-
-	if proof.CommitmentPok, err = pedersen.BatchProve(pk.CommitmentKeys, privateCommittedValues, commitmentsSerialized); err != nil {
-		return nil, err
-	}
-
 	// H (witness reduction / FFT part)
 	var h []fr.Element
-	chHDone := make(chan struct{}, 1)
+	chHDone := make(chan error, 1)
 	go func() {
-		h = computeH(solution.A, solution.B, solution.C, &pk.Domain)
+		var err error
+		h, err = computeH(solution.A, solution.B, solution.C, &pk.Domain)
 		solution.A = nil
 		solution.B = nil
 		solution.C = nil
-		chHDone <- struct{}{}
+		chHDone <- err
 	}()
 
 	// we need to copy and filter the wireValues for each multi exp
@@ -171,22 +152,11 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		close(chWireValuesB)
 	}()
 
-	// sample random r and s
-	var r, s big.Int
-	var _r, _s, _kr fr.Element
-	if _, err := _r.SetRandom(); err != nil {
-		return nil, err
-	}
-	if _, err := _s.SetRandom(); err != nil {
+	// sample random r and s, which are 0 without zero-knowledge
+	r, s, deltas, err := sampleBlinding(&pk.G1.Delta, opt.NoZeroKnowledge)
+	if err != nil {
 		return nil, err
 	}
-	_kr.Mul(&_r, &_s).Neg(&_kr)
-
-	_r.BigInt(&r)
-	_s.BigInt(&s)
-
-	// computes r[δ], s[δ], kr[δ]
-	deltas := curve.BatchScalarMultiplicationG1(&pk.G1.Delta, []fr.Element{_r, _s, _kr})
 
 	var bs1, ar curve.G1Jac
 
@@ -195,7 +165,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chBs1Done := make(chan error, 1)
 	computeBS1 := func() {
 		<-chWireValuesB
-		if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(&bs1, pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chBs1Done <- err
 			close(chBs1Done)
 			return
@@ -208,7 +178,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	chArDone := make(chan error, 1)
 	computeAR1 := func() {
 		<-chWireValuesA
-		if _, err := ar.MultiExp(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(&ar, pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chArDone <- err
 			close(chArDone)
 			return
@@ -228,7 +198,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		chKrs2Done := make(chan error, 1)
 		sizeH := int(pk.Domain.Cardinality - 1) // comes from the fact the deg(H)=(n-1)+(n-1)-n=n-2
 		go func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
+			err := multiExpG1(&krs2, pk.G1.Z, h[:sizeH], ecc.MultiExpConfig{NbTasks: n / 2})
 			chKrs2Done <- err
 		}()
 
@@ -238,12 +208,15 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 		toRemove = append(toRemove, commitmentInfo.CommitmentIndexes())
 		_wireValues := filterHeap(wireValues[r1cs.GetNbPublicVariables():], r1cs.GetNbPublicVariables(), internal.ConcatAll(toRemove...))
 
-		if _, err := krs.MultiExp(pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if err := multiExpG1(&krs, pk.G1.K, _wireValues, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chKrsDone <- err
 			return
 		}
 		krs.AddMixed(&deltas[2])
 		n := 3
+		if opt.NoZeroKnowledge {
+			n = 2 // r = 0, bs1 is not computed
+		}
 		for n != 0 {
 			select {
 			case err := <-chKrs2Done:
@@ -257,8 +230,10 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 					chKrsDone <- err
 					return
 				}
-				p1.ScalarMultiplication(&ar, &s)
-				krs.AddAssign(&p1)
+				if !opt.NoZeroKnowledge {
+					p1.ScalarMultiplication(&ar, &s)
+					krs.AddAssign(&p1)
+				}
 			case err := <-chBs1Done:
 				if err != nil {
 					chKrsDone <- err
@@ -284,13 +259,15 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 			nbTasks *= 2
 		}
 		<-chWireValuesB
-		if _, err := Bs.MultiExp(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+		if err := multiExpG2(&Bs, pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
 			return err
 		}
 
-		deltaS.FromAffine(&pk.G2.Delta)
-		deltaS.ScalarMultiplication(&deltaS, &s)
-		Bs.AddAssign(&deltaS)
+		if !opt.NoZeroKnowledge {
+			deltaS.FromAffine(&pk.G2.Delta)
+			deltaS.ScalarMultiplication(&deltaS, &s)
+			Bs.AddAssign(&deltaS)
+		}
 		Bs.AddMixed(&pk.G2.Beta)
 
 		proof.Bs.FromJacobian(&Bs)
@@ -298,12 +275,16 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	}
 
 	// wait for FFT to end, as it uses all our CPUs
-	<-chHDone
+	if err := <-chHDone; err != nil {
+		return nil, err
+	}
 
 	// schedule our proof part computations
 	go computeKRS()
 	go computeAR1()
-	go computeBS1()
+	if !opt.NoZeroKnowledge {
+		go computeBS1()
+	}
 	if err := computeBS2(); err != nil {
 		return nil, err
 	}
@@ -318,6 +299,95 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...b
 	return proof, nil
 }
 
+// sampleBlinding returns the random r and s blinding the proof, and r[δ], s[δ]
+// and -rs[δ]. They are all 0 if noZeroKnowledge is set, which makes the proof
+// deterministic.
+func sampleBlinding(delta *curve.G1Affine, noZeroKnowledge bool) (r, s big.Int, deltas []curve.G1Affine, err error) {
+	if noZeroKnowledge {
+		return r, s, make([]curve.G1Affine, 3), nil
+	}
+	var _r, _s, _kr fr.Element
+	if _, err = _r.SetRandom(); err != nil {
+		return
+	}
+	if _, err = _s.SetRandom(); err != nil {
+		return
+	}
+	_kr.Mul(&_r, &_s).Neg(&_kr)
+
+	_r.BigInt(&r)
+	_s.BigInt(&s)
+
+	// computes r[δ], s[δ], kr[δ]
+	deltas = curve.BatchScalarMultiplicationG1(delta, []fr.Element{_r, _s, _kr})
+	return
+}
+
+// solve solves the constraint system with the commitments hints overridden to
+// compute the commitments of the proof and their proof of knowledge.
+func solve(r1cs *cs.R1CS, commitmentKeys []pedersen.ProvingKey, fullWitness witness.Witness, opt *backend.ProverConfig, proof *Proof) (*cs.R1CSSolution, error) {
+	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
+
+	solverOpts := opt.SolverOpts[:len(opt.SolverOpts):len(opt.SolverOpts)]
+
+	privateCommittedValues := make([][]fr.Element, len(commitmentInfo))
+	for i := range commitmentInfo {
+		solverOpts = append(solverOpts, solver.OverrideHint(commitmentInfo[i].HintID, func(i int) solver.Hint {
+			return func(_ *big.Int, in []*big.Int, out []*big.Int) error {
+				privateCommittedValues[i] = make([]fr.Element, len(commitmentInfo[i].PrivateCommitted))
+				hashed := in[:len(commitmentInfo[i].PublicAndCommitmentCommitted)]
+				committed := in[len(hashed):]
+				for j, inJ := range committed {
+					privateCommittedValues[i][j].SetBigInt(inJ)
+				}
+
+				var err error
+				if proof.Commitments[i], err = commitmentKeys[i].Commit(privateCommittedValues[i]); err != nil {
+					return err
+				}
+
+				opt.HashToFieldFn.Write(constraint.SerializeCommitment(proof.Commitments[i].Marshal(), hashed, (fr.Bits-1)/8+1))
+				hashBts := opt.HashToFieldFn.Sum(nil)
+				opt.HashToFieldFn.Reset()
+				nbBuf := fr.Bytes
+				if opt.HashToFieldFn.Size() < fr.Bytes {
+					nbBuf = opt.HashToFieldFn.Size()
+				}
+				var res fr.Element
+				res.SetBytes(hashBts[:nbBuf])
+				res.BigInt(out[0])
+				return err
+			}
+		}(i)))
+	}
+
+	if r1cs.GkrInfo.Is() {
+		var gkrData cs.GkrSolvingData
+		solverOpts = append(solverOpts,
+			solver.OverrideHint(r1cs.GkrInfo.SolveHintID, cs.GkrSolveHint(r1cs.GkrInfo, &gkrData)),
+			solver.OverrideHint(r1cs.GkrInfo.ProveHintID, cs.GkrProveHint(r1cs.GkrInfo.HashName, &gkrData)))
+	}
+
+	_solution, err := r1cs.Solve(fullWitness, solverOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	solution := _solution.(*cs.R1CSSolution)
+	wireValues := []fr.Element(solution.W)
+
+	commitmentsSerialized := make([]byte, fr.Bytes*len(commitmentInfo))
+	for i := range commitmentInfo {
+		copy(commitmentsSerialized[fr.Bytes*i:], wireValues[commitmentInfo[i].CommitmentIndex].Marshal())
+	}
+
+	if proof.CommitmentPok, err = pedersen.BatchProve(commitmentKeys, privateCommittedValues, commitmentsSerialized); err != nil {
+		return nil, err
+	}
+
+	return solution, nil
+}
+
 // if len(toRemove) == 0, returns slice
 // else, returns a new slice without the indexes in toRemove. The first value in the slice is taken as indexes as sliceFirstIndex
 // this assumes len(slice) > len(toRemove)
@@ -347,7 +417,7 @@ func filterHeap(slice []fr.Element, sliceFirstIndex int, toRemove []int) (r []fr
 	return
 }
 
-func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
+func computeH(a, b, c []fr.Element, domain *fft.Domain) ([]fr.Element, error) {
 	// H part of Krs
 	// Compute H (hz=ab-c, where z=-2 on ker X^n+1 (z(x)=x^n-1))
 	// 	1 - _a = ifft(a), _b = ifft(b), _c = ifft(c)
@@ -363,13 +433,22 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	c = append(c, padding...)
 	n = len(a)
 
-	domain.FFTInverse(a, fft.DIF)
-	domain.FFTInverse(b, fft.DIF)
-	domain.FFTInverse(c, fft.DIF)
-
-	domain.FFT(a, fft.DIT, fft.OnCoset())
-	domain.FFT(b, fft.DIT, fft.OnCoset())
-	domain.FFT(c, fft.DIT, fft.OnCoset())
+	onDevice := false
+	if accelerator.Enabled {
+		var err error
+		if onDevice, err = cosetEvaluationsOnDevice(domain, a, b, c); err != nil {
+			return nil, err
+		}
+	}
+	if !onDevice {
+		domain.FFTInverse(a, fft.DIF)
+		domain.FFTInverse(b, fft.DIF)
+		domain.FFTInverse(c, fft.DIF)
+
+		domain.FFT(a, fft.DIT, fft.OnCoset())
+		domain.FFT(b, fft.DIT, fft.OnCoset())
+		domain.FFT(c, fft.DIT, fft.OnCoset())
+	}
 
 	var den, one fr.Element
 	one.SetOne()
@@ -387,7 +466,69 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	})
 
 	// ifft_coset
+	if onDevice {
+		ok, err := accelerator.FFT(curve.ID, domain, a, true, true)
+		if err == nil && !ok {
+			err = accelerator.ErrUnsupported
+		}
+		if err != nil {
+			return nil, fmt.Errorf("device FFT: %w", err)
+		}
+		// the points of the proving key are in bit-reversed order
+		fft.BitReverse(a)
+		return a, nil
+	}
 	domain.FFTInverse(a, fft.DIF, fft.OnCoset())
 
-	return a
+	return a, nil
+}
+
+// cosetEvaluationsOnDevice sets in place the vectors of evaluations on the
+// domain to their evaluations on the coset, with the registered accelerator.
+// It returns false if the accelerator can't do it and the vectors are
+// unchanged.
+func cosetEvaluationsOnDevice(domain *fft.Domain, vectors ...[]fr.Element) (bool, error) {
+	for i, v := range vectors {
+		ok, err := accelerator.FFT(curve.ID, domain, v, true, false)
+		if err == nil && !ok && i == 0 {
+			return false, nil
+		}
+		if err == nil && ok {
+			ok, err = accelerator.FFT(curve.ID, domain, v, false, true)
+		}
+		// the vectors have been modified, we can't fall back to the CPU
+		if err == nil && !ok {
+			err = accelerator.ErrUnsupported
+		}
+		if err != nil {
+			return false, fmt.Errorf("device FFT: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// multiExpG1 sets res to Σ scalars[i]·points[i], with the registered
+// accelerator if any.
+func multiExpG1(res *curve.G1Jac, points []curve.G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) error {
+	if accelerator.Enabled {
+		ok, err := accelerator.MultiExp(curve.ID, accelerator.G1, res, points, scalars, func(res, partial *curve.G1Jac) { res.AddAssign(partial) })
+		if ok || err != nil {
+			return err
+		}
+	}
+	_, err := res.MultiExp(points, scalars, config)
+	return err
+}
+
+// multiExpG2 sets res to Σ scalars[i]·points[i], with the registered
+// accelerator if any.
+func multiExpG2(res *curve.G2Jac, points []curve.G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) error {
+	if accelerator.Enabled {
+		ok, err := accelerator.MultiExp(curve.ID, accelerator.G2, res, points, scalars, func(res, partial *curve.G2Jac) { res.AddAssign(partial) })
+		if ok || err != nil {
+			return err
+		}
+	}
+	_, err := res.MultiExp(points, scalars, config)
+	return err
 }
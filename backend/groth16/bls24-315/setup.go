@@ -17,7 +17,9 @@
 package groth16
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
@@ -78,7 +80,8 @@ type VerifyingKey struct {
 	e curve.GT // not serialized
 
 	CommitmentKey                pedersen.VerifyingKey
-	PublicAndCommitmentCommitted [][]int // indexes of public/commitment committed variables
+	PublicAndCommitmentCommitted [][]int      // indexes of public/commitment committed variables
+	CommitmentBasisDigests       []fr.Element // digests of the bases of the commitments, see VerifyCommitmentLink
 }
 
 // Setup constructs the SRS
@@ -93,6 +96,30 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 		- loop through the pure structural constraints, eValuate A(X), B(X), C(X) with simple formula, the gate number is len(gateOrdering)+len(InpureStructuralConstraints)+current iterator
 	*/
 
+	// samples toxic waste
+	toxicWaste, err := sampleToxicWaste()
+	if err != nil {
+		return err
+	}
+
+	return setup(r1cs, pk, vk, toxicWaste)
+}
+
+// UnsafeSetup is a deterministic and fast Setup for tests only: the toxic waste
+// is derived from seed and τ = 1, so that only the first constraint is encoded
+// in the keys and most of their points are at infinity. The proofs generated
+// with the proving key are accepted by the verifying key, but anyone can forge
+// proofs of false statements.
+func UnsafeSetup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey, seed []byte) error {
+	toxicWaste, err := unsafeToxicWaste(seed)
+	if err != nil {
+		return err
+	}
+	return setup(r1cs, pk, vk, toxicWaste)
+}
+
+// setup constructs the SRS from the toxic waste.
+func setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey, toxicWaste toxicWaste) error {
 	// get R1CS nb constraints, wires and public/private inputs
 	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
 
@@ -110,12 +137,6 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	// Setting group for fft
 	domain := fft.NewDomain(uint64(r1cs.GetNbConstraints()))
 
-	// samples toxic waste
-	toxicWaste, err := sampleToxicWaste()
-	if err != nil {
-		return err
-	}
-
 	// Setup coeffs to compute pk.G1.A, pk.G1.B, pk.G1.K
 	A, B, C := setupABC(r1cs, domain, toxicWaste)
 
@@ -248,7 +269,7 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 		g1Scalars = append(g1Scalars, ckK[i]...)
 	}
 
-	g1PointsAff := curve.BatchScalarMultiplicationG1(&g1, g1Scalars)
+	g1PointsAff := batchScalarMultiplicationG1(&g1, g1Scalars)
 
 	// sets pk: [α]₁, [β]₁, [δ]₁
 	pk.G1.Alpha = g1PointsAff[0]
@@ -287,12 +308,25 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 		return errors.New("didn't consume all G1 points") // TODO @Tabaie Remove this
 	}
 
-	pk.CommitmentKeys, vk.CommitmentKey, err = pedersen.Setup(commitmentBases...)
+	var err error
+	if toxicWaste.sigma.IsZero() {
+		pk.CommitmentKeys, vk.CommitmentKey, err = pedersen.Setup(commitmentBases...)
+	} else {
+		pk.CommitmentKeys, vk.CommitmentKey, err = unsafePedersenSetup(&toxicWaste.sigma, commitmentBases)
+	}
 	if err != nil {
 		return err
 	}
 
 	vk.PublicAndCommitmentCommitted = commitmentInfo.GetPublicAndCommitmentCommitted(commitmentWires, r1cs.GetNbPublicVariables())
+	vk.CommitmentBasisDigests = nil
+	for i := range commitmentBases {
+		digest, err := commitmentBasisDigest(commitmentBases[i])
+		if err != nil {
+			return err
+		}
+		vk.CommitmentBasisDigests = append(vk.CommitmentBasisDigests, digest)
+	}
 
 	// ---------------------------------------------------------------------------------------------
 	// G2 scalars
@@ -305,7 +339,7 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	// compute our batch scalar multiplication with g2 elements
 	g2Scalars := append(B, toxicWaste.beta, toxicWaste.delta, toxicWaste.gamma)
 
-	g2PointsAff := curve.BatchScalarMultiplicationG2(&g2, g2Scalars)
+	g2PointsAff := batchScalarMultiplicationG2(&g2, g2Scalars)
 
 	pk.G2.B = g2PointsAff[:len(B)]
 
@@ -370,6 +404,15 @@ func setupABC(r1cs *cs.R1CS, domain *fft.Domain, toxicWaste toxicWaste) (A []fr.
 	}
 	tInv := fr.BatchInvert(t)
 
+	// if t = ωʲ, the formula below doesn't apply: Lᵢ(t) = 1 if i = j, 0 otherwise
+	root := -1
+	for i := range t {
+		if t[i].IsZero() {
+			root = i
+			break
+		}
+	}
+
 	// evaluation of the i-th lagrange polynomial at t
 	var L fr.Element
 
@@ -411,6 +454,13 @@ func setupABC(r1cs *cs.R1CS, domain *fft.Domain, toxicWaste toxicWaste) (A []fr.
 	j := 0
 	it := r1cs.GetR1CIterator()
 	for c := it.Next(); c != nil; c = it.Next() {
+		if root != -1 {
+			if j == root {
+				L.SetOne()
+			} else {
+				L.SetZero()
+			}
+		}
 		for _, t := range c.L {
 			accumulate(&A[t.WireID()], t, &L)
 		}
@@ -439,6 +489,10 @@ type toxicWaste struct {
 	// Montgomery form of params
 	t, alpha, beta, gamma, delta fr.Element
 	gammaInv, deltaInv           fr.Element
+
+	// sigma is the trapdoor of the commitment keys. It is only set by
+	// unsafeToxicWaste, the commitment keys are random otherwise.
+	sigma fr.Element
 }
 
 func sampleToxicWaste() (toxicWaste, error) {
@@ -477,8 +531,126 @@ func sampleToxicWaste() (toxicWaste, error) {
 	return res, nil
 }
 
+// unsafeToxicWaste derives the toxic waste of [UnsafeSetup] from seed, with t = 1.
+func unsafeToxicWaste(seed []byte) (toxicWaste, error) {
+	res := toxicWaste{t: fr.One()}
+
+	s, err := fr.Hash(seed, []byte("gnark-groth16-unsafe-setup"), 5)
+	if err != nil {
+		return res, err
+	}
+	res.alpha, res.beta, res.gamma, res.delta, res.sigma = s[0], s[1], s[2], s[3], s[4]
+	if res.alpha.IsZero() || res.beta.IsZero() || res.gamma.IsZero() || res.delta.IsZero() || res.sigma.IsZero() {
+		return res, errors.New("null toxic waste")
+	}
+
+	res.gammaInv.Inverse(&res.gamma)
+	res.deltaInv.Inverse(&res.delta)
+
+	return res, nil
+}
+
+// unsafePedersenSetup is pedersen.Setup with the trapdoor sigma and the G2
+// generator instead of random ones. The keys are built through their encoding
+// since their fields are not exported.
+func unsafePedersenSetup(sigma *fr.Element, bases [][]curve.G1Affine) ([]pedersen.ProvingKey, pedersen.VerifyingKey, error) {
+	var vk pedersen.VerifyingKey
+	_, _, _, g := curve.Generators()
+	var sigmaInvNeg fr.Element
+	sigmaInvNeg.Inverse(sigma).Neg(&sigmaInvNeg)
+	var gRootSigmaNeg curve.G2Affine
+	gRootSigmaNeg.ScalarMultiplication(&g, sigmaInvNeg.BigInt(new(big.Int)))
+
+	var buf bytes.Buffer
+	enc := curve.NewEncoder(&buf, curve.RawEncoding())
+	if err := enc.Encode(&g); err != nil {
+		return nil, vk, err
+	}
+	if err := enc.Encode(&gRootSigmaNeg); err != nil {
+		return nil, vk, err
+	}
+	if _, err := vk.UnsafeReadFrom(&buf); err != nil {
+		return nil, vk, err
+	}
+
+	var s big.Int
+	sigma.BigInt(&s)
+	pk := make([]pedersen.ProvingKey, len(bases))
+	for i := range bases {
+		basisExpSigma := make([]curve.G1Affine, len(bases[i]))
+		for j := range bases[i] {
+			basisExpSigma[j].ScalarMultiplication(&bases[i][j], &s)
+		}
+		buf.Reset()
+		enc := curve.NewEncoder(&buf, curve.RawEncoding())
+		if err := enc.Encode(bases[i]); err != nil {
+			return nil, vk, err
+		}
+		if err := enc.Encode(basisExpSigma); err != nil {
+			return nil, vk, err
+		}
+		if _, err := pk[i].ReadFrom(&buf); err != nil {
+			return nil, vk, err
+		}
+	}
+	return pk, vk, nil
+}
+
+// batchScalarMultiplicationG1 returns the multiples of base by the scalars.
+// The zero scalars are skipped and give the point at infinity.
+func batchScalarMultiplicationG1(base *curve.G1Affine, scalars []fr.Element) []curve.G1Affine {
+	nonZero := nonZeroScalars(scalars)
+	if len(nonZero) == len(scalars) {
+		return curve.BatchScalarMultiplicationG1(base, scalars)
+	}
+	res := make([]curve.G1Affine, len(scalars))
+	if len(nonZero) == 0 {
+		return res
+	}
+	points := curve.BatchScalarMultiplicationG1(base, nonZero)
+	for i, j := 0, 0; i < len(scalars); i++ {
+		if !scalars[i].IsZero() {
+			res[i] = points[j]
+			j++
+		}
+	}
+	return res
+}
+
+// batchScalarMultiplicationG2 returns the multiples of base by the scalars.
+// The zero scalars are skipped and give the point at infinity.
+func batchScalarMultiplicationG2(base *curve.G2Affine, scalars []fr.Element) []curve.G2Affine {
+	nonZero := nonZeroScalars(scalars)
+	if len(nonZero) == len(scalars) {
+		return curve.BatchScalarMultiplicationG2(base, scalars)
+	}
+	res := make([]curve.G2Affine, len(scalars))
+	if len(nonZero) == 0 {
+		return res
+	}
+	points := curve.BatchScalarMultiplicationG2(base, nonZero)
+	for i, j := 0, 0; i < len(scalars); i++ {
+		if !scalars[i].IsZero() {
+			res[i] = points[j]
+			j++
+		}
+	}
+	return res
+}
+
+func nonZeroScalars(scalars []fr.Element) []fr.Element {
+	res := make([]fr.Element, 0, len(scalars))
+	for i := range scalars {
+		if !scalars[i].IsZero() {
+			res = append(res, scalars[i])
+		}
+	}
+	return res
+}
+
 // DummySetup fills a random ProvingKey
-// used for test or benchmarking purposes
+// used for test or benchmarking purposes. The proofs it generates don't
+// verify, see [UnsafeSetup] for a fast setup with a verifying key.
 func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	// get R1CS nb constraints, wires and public/private inputs
 	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
@@ -649,6 +821,92 @@ func (vk *VerifyingKey) CurveID() ecc.ID {
 	return curve.ID
 }
 
+// CheckKeys returns an error if pk and vk were not generated together by
+// Setup for the provided r1cs.
+//
+// Besides the sizes of the keys, it checks with a single pairing check that
+// the G1 and G2 elements of the keys encode the same β and δ, and that the
+// query [B(t)]₁ of pk matches [B(t)]₂. The other queries can't be checked
+// without the toxic waste or a witness: see [github.com/consensys/gnark/backend/groth16.SelfTest]
+// for a proof round-trip with the keys.
+func CheckKeys(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
+	if !pk.G1.Alpha.Equal(&vk.G1.Alpha) || !pk.G1.Beta.Equal(&vk.G1.Beta) || !pk.G1.Delta.Equal(&vk.G1.Delta) ||
+		!pk.G2.Beta.Equal(&vk.G2.Beta) || !pk.G2.Delta.Equal(&vk.G2.Delta) {
+		return errors.New("proving key and verifying key don't come from the same setup")
+	}
+
+	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
+	nbPrivateCommittedWires := internal.NbElements(commitmentInfo.GetPrivateCommitted())
+	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
+	nbPublicWires := r1cs.GetNbPublicVariables() + len(commitmentInfo)
+	nbPrivateWires := r1cs.GetNbSecretVariables() + r1cs.NbInternalVariables - nbPrivateCommittedWires - len(commitmentInfo)
+	domain := fft.NewDomain(uint64(r1cs.GetNbConstraints()))
+
+	if len(vk.G1.K) != nbPublicWires {
+		return fmt.Errorf("verifying key has %d public wires, constraint system has %d", len(vk.G1.K), nbPublicWires)
+	}
+	if pk.Domain.Cardinality != domain.Cardinality || len(pk.G1.Z) != int(domain.Cardinality)-1 {
+		return fmt.Errorf("proving key domain has cardinality %d, expected %d", pk.Domain.Cardinality, domain.Cardinality)
+	}
+	if len(pk.InfinityA) != nbWires || len(pk.InfinityB) != nbWires {
+		return fmt.Errorf("proving key has %d wires, constraint system has %d", len(pk.InfinityA), nbWires)
+	}
+	if len(pk.G1.A) != nbWires-int(pk.NbInfinityA) || len(pk.G1.B) != nbWires-int(pk.NbInfinityB) || len(pk.G2.B) != nbWires-int(pk.NbInfinityB) {
+		return errors.New("proving key is truncated")
+	}
+	if len(pk.G1.K) != nbPrivateWires {
+		return fmt.Errorf("proving key has %d private wires, constraint system has %d", len(pk.G1.K), nbPrivateWires)
+	}
+	if len(pk.CommitmentKeys) != len(commitmentInfo) {
+		return fmt.Errorf("proving key has %d commitment keys, constraint system has %d commitments", len(pk.CommitmentKeys), len(commitmentInfo))
+	}
+	if len(vk.CommitmentBasisDigests) != len(commitmentInfo) {
+		return fmt.Errorf("verifying key has %d commitment basis digests, constraint system has %d commitments", len(vk.CommitmentBasisDigests), len(commitmentInfo))
+	}
+	for i := range pk.CommitmentKeys {
+		basis, err := pk.CommitmentBasis(i)
+		if err != nil {
+			return err
+		}
+		digest, err := commitmentBasisDigest(basis)
+		if err != nil {
+			return err
+		}
+		if !digest.Equal(&vk.CommitmentBasisDigests[i]) {
+			return fmt.Errorf("commitment %d: proving key and verifying key are not consistent", i)
+		}
+	}
+
+	// with random r, sᵢ:
+	// e([β]₁ + r⋅[δ]₁ + ∑ᵢ sᵢ⋅[Bᵢ(t)]₁, [1]₂) == e([1]₁, [β]₂ + r⋅[δ]₂ + ∑ᵢ sᵢ⋅[Bᵢ(t)]₂)
+	scalars := make([]fr.Element, len(pk.G1.B)+1)
+	for i := range scalars {
+		if _, err := scalars[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+	var lhs curve.G1Affine
+	if _, err := lhs.MultiExp(append([]curve.G1Affine{pk.G1.Delta}, pk.G1.B...), scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	lhs.Add(&lhs, &pk.G1.Beta)
+	var rhs curve.G2Affine
+	if _, err := rhs.MultiExp(append([]curve.G2Affine{vk.G2.Delta}, pk.G2.B...), scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	rhs.Add(&rhs, &vk.G2.Beta)
+	_, _, g1, g2 := curve.Generators()
+	g1.Neg(&g1)
+	ok, err := curve.PairingCheck([]curve.G1Affine{lhs, g1}, []curve.G2Affine{g2, rhs})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("proving key and verifying key are not consistent")
+	}
+	return nil
+}
+
 // NbPublicWitness returns the number of elements in the expected public witness
 func (vk *VerifyingKey) NbPublicWitness() int {
 	return (len(vk.G1.K) - 1)
@@ -19,9 +19,12 @@ package groth16
 import (
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math/big"
 	"time"
 
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
@@ -51,7 +54,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
 
 	if len(publicWitness) != nbPublicVars-1 {
-		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), len(vk.G1.K)-1)
+		return fmt.Errorf("%w: invalid witness size, got %d, expected %d (public - ONE_WIRE)", gnark.ErrInvalidWitness, len(publicWitness), len(vk.G1.K)-1)
 	}
 	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Logger()
 	start := time.Now()
@@ -72,6 +75,56 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		close(chDone)
 	}()
 
+	publicWitness, folded, err := vk.commitmentWires(proof, publicWitness, opt.HashToFieldFn)
+	if err != nil {
+		return err
+	}
+	if err = vk.CommitmentKey.Verify(folded, proof.CommitmentPok); err != nil {
+		return err
+	}
+
+	// compute e(Σx.[Kvk(t)]1, -[γ]2)
+	var kSum curve.G1Jac
+	if _, err := kSum.MultiExp(vk.G1.K[1:], publicWitness, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	kSum.AddMixed(&vk.G1.K[0])
+
+	for i := range proof.Commitments {
+		kSum.AddMixed(&proof.Commitments[i])
+	}
+
+	var kSumAff curve.G1Affine
+	kSumAff.FromJacobian(&kSum)
+
+	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+	if err != nil {
+		return err
+	}
+
+	// wait for (eKrsδ, eArBs)
+	if err := <-chDone; err != nil {
+		return err
+	}
+
+	right = curve.FinalExponentiation(&right, &doubleML)
+	if !vk.e.Equal(&right) {
+		return errPairingCheckFailed
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+	return nil
+}
+
+// commitmentWires returns the public witness extended with the values of the
+// commitment wires, which are derived from the commitments in the proof, and
+// the commitments folded into one for the proof of knowledge.
+func (vk *VerifyingKey) commitmentWires(proof *Proof, publicWitness fr.Vector, hashToField hash.Hash) (fr.Vector, curve.G1Affine, error) {
+	if len(proof.Commitments) != len(vk.PublicAndCommitmentCommitted) {
+		return nil, curve.G1Affine{}, errors.New("invalid number of commitments")
+	}
+	publicWitness = append(make(fr.Vector, 0, len(publicWitness)+len(vk.PublicAndCommitmentCommitted)), publicWitness...)
+
 	maxNbPublicCommitted := 0
 	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
 		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
@@ -85,12 +138,12 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
 			offset += fr.Bytes
 		}
-		opt.HashToFieldFn.Write(commitmentPrehashSerialized[:offset])
-		hashBts := opt.HashToFieldFn.Sum(nil)
-		opt.HashToFieldFn.Reset()
+		hashToField.Write(commitmentPrehashSerialized[:offset])
+		hashBts := hashToField.Sum(nil)
+		hashToField.Reset()
 		nbBuf := fr.Bytes
-		if opt.HashToFieldFn.Size() < fr.Bytes {
-			nbBuf = opt.HashToFieldFn.Size()
+		if hashToField.Size() < fr.Bytes {
+			nbBuf = hashToField.Size()
 		}
 		var res fr.Element
 		res.SetBytes(hashBts[:nbBuf])
@@ -98,44 +151,136 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		copy(commitmentsSerialized[i*fr.Bytes:], res.Marshal())
 	}
 
-	if folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized); err != nil {
-		return err
-	} else {
-		if err = vk.CommitmentKey.Verify(folded, proof.CommitmentPok); err != nil {
+	folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized)
+	return publicWitness, folded, err
+}
+
+// BatchVerify verifies several proofs for the same VerifyingKey, each with
+// its public witness. It returns an error if any of the proofs is invalid,
+// without telling which one.
+//
+// The pairing equations of the proofs are combined with random coefficients
+// rᵢ drawn by the verifier:
+//
+//	∏ᵢ e(rᵢ⋅Arᵢ, Bsᵢ) ⋅ e(∑ᵢ rᵢ⋅Kᵢ, -[γ]₂) ⋅ e(∑ᵢ rᵢ⋅Krsᵢ, -[δ]₂) = e(α, β)^(∑ᵢ rᵢ)
+//
+// where Kᵢ is the public input term of the proof i. The sums are computed with
+// multi-exponentiations, so that verifying N proofs takes N+2 Miller loops and
+// a single final exponentiation, instead of N times the cost of [Verify]. The
+// proofs of knowledge of the commitments, if any, are batched the same way.
+func BatchVerify(vk *VerifyingKey, proofs []*Proof, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	opt, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("new verifier config: %w", err)
+	}
+	if opt.HashToFieldFn == nil {
+		opt.HashToFieldFn = hash_to_field.New([]byte(constraint.CommitmentDst))
+	}
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs but %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
+	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+
+	// random coefficients, r₀ = 1
+	r := make([]fr.Element, len(proofs))
+	r[0].SetOne()
+	for i := 1; i < len(r); i++ {
+		if _, err := r[i].SetRandom(); err != nil {
 			return err
 		}
 	}
 
-	// compute e(Σx.[Kvk(t)]1, -[γ]2)
-	var kSum curve.G1Jac
-	if _, err := kSum.MultiExp(vk.G1.K[1:], publicWitness, ecc.MultiExpConfig{}); err != nil {
-		return err
-	}
-	kSum.AddMixed(&vk.G1.K[0])
+	// ∑ᵢ rᵢ⋅Kᵢ is computed as ⟨∑ᵢ rᵢ⋅xᵢ, Kvk⟩ + ∑ᵢ rᵢ⋅∑ⱼ commitmentsᵢⱼ, where xᵢ is
+	// the public witness of the proof i, prepended with 1
+	kScalars := make([]fr.Element, len(vk.G1.K))
+	var commitments, foldedCommitments, poks []curve.G1Affine
+	var commitmentScalars []fr.Element
+	var t fr.Element
+	for i, proof := range proofs {
+		if len(publicWitnesses[i]) != nbPublicVars-1 {
+			return fmt.Errorf("%w: invalid witness size for proof %d, got %d, expected %d (public - ONE_WIRE)", gnark.ErrInvalidWitness, i, len(publicWitnesses[i]), nbPublicVars-1)
+		}
+		if !proof.isValid() {
+			return errCorrectSubgroupCheckFailed
+		}
+		publicWitness, folded, err := vk.commitmentWires(proof, publicWitnesses[i], opt.HashToFieldFn)
+		if err != nil {
+			return err
+		}
+		if len(vk.PublicAndCommitmentCommitted) != 0 {
+			foldedCommitments = append(foldedCommitments, folded)
+			poks = append(poks, proof.CommitmentPok)
+		}
 
-	for i := range proof.Commitments {
-		kSum.AddMixed(&proof.Commitments[i])
+		kScalars[0].Add(&kScalars[0], &r[i])
+		for j := range publicWitness {
+			t.Mul(&publicWitness[j], &r[i])
+			kScalars[j+1].Add(&kScalars[j+1], &t)
+		}
+		for j := range proof.Commitments {
+			commitments = append(commitments, proof.Commitments[j])
+			commitmentScalars = append(commitmentScalars, r[i])
+		}
 	}
 
-	var kSumAff curve.G1Affine
-	kSumAff.FromJacobian(&kSum)
+	// the proofs of knowledge of the commitments
+	if len(foldedCommitments) != 0 {
+		var folded, pok curve.G1Affine
+		if _, err := folded.MultiExp(foldedCommitments, r, ecc.MultiExpConfig{}); err != nil {
+			return err
+		}
+		if _, err := pok.MultiExp(poks, r, ecc.MultiExpConfig{}); err != nil {
+			return err
+		}
+		if err := vk.CommitmentKey.Verify(folded, pok); err != nil {
+			return err
+		}
+	}
 
-	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
-	if err != nil {
+	// ∑ᵢ rᵢ⋅Kᵢ, ∑ᵢ rᵢ⋅Krsᵢ and the rᵢ⋅Arᵢ
+	var kSum, krsSum curve.G1Affine
+	if _, err := kSum.MultiExp(append(append([]curve.G1Affine(nil), vk.G1.K...), commitments...), append(kScalars, commitmentScalars...), ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	krs := make([]curve.G1Affine, len(proofs))
+	for i := range proofs {
+		krs[i] = proofs[i].Krs
+	}
+	if _, err := krsSum.MultiExp(krs, r, ecc.MultiExpConfig{}); err != nil {
 		return err
 	}
+	p := make([]curve.G1Affine, 0, len(proofs)+2)
+	q := make([]curve.G2Affine, 0, len(proofs)+2)
+	var b big.Int
+	for i := range proofs {
+		var ar curve.G1Affine
+		ar.ScalarMultiplication(&proofs[i].Ar, r[i].BigInt(&b))
+		p = append(p, ar)
+		q = append(q, proofs[i].Bs)
+	}
+	p = append(p, kSum, krsSum)
+	q = append(q, vk.G2.gammaNeg, vk.G2.deltaNeg)
 
-	// wait for (eKrsδ, eArBs)
-	if err := <-chDone; err != nil {
+	ml, err := curve.MillerLoop(p, q)
+	if err != nil {
 		return err
 	}
+	ml = curve.FinalExponentiation(&ml)
 
-	right = curve.FinalExponentiation(&right, &doubleML)
-	if !vk.e.Equal(&right) {
+	// e(α, β)^(∑ᵢ rᵢ)
+	var expected curve.GT
+	expected.Exp(vk.e, kScalars[0].BigInt(&b))
+	if !expected.Equal(&ml) {
 		return errPairingCheckFailed
 	}
 
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
 	return nil
 }
 
@@ -17,6 +17,8 @@
 package groth16
 
 import (
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
 
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/pedersen"
@@ -98,7 +100,12 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.WriteTo(w)
+	if m, err = vk.CommitmentKey.WriteTo(w); err != nil {
+		return m + n, err
+	}
+	n += m
+	digests := fr.Vector(vk.CommitmentBasisDigests)
+	m, err = digests.WriteTo(w)
 	return m + n, err
 }
 
@@ -110,7 +117,12 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.WriteRawTo(w)
+	if m, err = vk.CommitmentKey.WriteRawTo(w); err != nil {
+		return m + n, err
+	}
+	n += m
+	digests := fr.Vector(vk.CommitmentBasisDigests)
+	m, err = digests.WriteTo(w)
 	return m + n, err
 }
 
@@ -172,7 +184,11 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.ReadFrom(r)
+	if m, err = vk.CommitmentKey.ReadFrom(r); err != nil {
+		return m + n, err
+	}
+	n += m
+	m, err = vk.readCommitmentBasisDigests(r)
 	return m + n, err
 }
 
@@ -184,10 +200,33 @@ func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.UnsafeReadFrom(r)
+	if m, err = vk.CommitmentKey.UnsafeReadFrom(r); err != nil {
+		return m + n, err
+	}
+	n += m
+	m, err = vk.readCommitmentBasisDigests(r)
 	return m + n, err
 }
 
+// readCommitmentBasisDigests reads the digests of the commitment bases, which
+// are appended after the commitment key. Keys serialized before the digests
+// were added end with the commitment key and are read without digests.
+func (vk *VerifyingKey) readCommitmentBasisDigests(r io.Reader) (int64, error) {
+	var digests fr.Vector
+	n, err := digests.ReadFrom(r)
+	vk.CommitmentBasisDigests = nil
+	if err == io.EOF && n == 0 {
+		return 0, nil
+	}
+	if err != nil {
+		return n, err
+	}
+	if len(digests) != 0 {
+		vk.CommitmentBasisDigests = digests
+	}
+	return n, nil
+}
+
 func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
 	dec := curve.NewDecoder(r, decOptions...)
 
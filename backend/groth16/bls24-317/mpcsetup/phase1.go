@@ -19,10 +19,12 @@ package mpcsetup
 import (
 	"crypto/sha256"
 	"errors"
-	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
-	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
 	"math"
 	"math/big"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark/backend/beacon"
 )
 
 // Phase1 represents the Phase1 of the MPC described in
@@ -87,13 +89,26 @@ func InitPhase1(power int) (phase1 Phase1) {
 
 // Contribute contributes randomness to the phase1 object. This mutates phase1.
 func (phase1 *Phase1) Contribute() {
-	N := len(phase1.Parameters.G2.Tau)
-
 	// Generate key pairs
 	var tau, alpha, beta fr.Element
 	tau.SetRandom()
 	alpha.SetRandom()
 	beta.SetRandom()
+	phase1.contribute(tau, alpha, beta)
+}
+
+// ContributeFromBeacon finalizes phase 1 with a contribution derived from the
+// randomness of a public beacon round. The round must be fixed before the last
+// contribution is made, and the beacon verified with [beacon.Beacon.Verify].
+// This mutates phase1.
+func (phase1 *Phase1) ContributeFromBeacon(b *beacon.Beacon) {
+	s := beaconScalars(b, phase1.Hash, 3)
+	phase1.contribute(s[0], s[1], s[2])
+}
+
+func (phase1 *Phase1) contribute(tau, alpha, beta fr.Element) {
+	N := len(phase1.Parameters.G2.Tau)
+
 	phase1.PublicKeys.Tau = newPublicKey(tau, phase1.Hash[:], 1)
 	phase1.PublicKeys.Alpha = newPublicKey(alpha, phase1.Hash[:], 2)
 	phase1.PublicKeys.Beta = newPublicKey(beta, phase1.Hash[:], 3)
@@ -131,6 +146,36 @@ func VerifyPhase1(c0, c1 *Phase1, c ...*Phase1) error {
 	return nil
 }
 
+// VerifyPhase1Beacon checks that contribution is the contribution derived
+// from the beacon round b on top of current. The beacon itself must be
+// verified with [beacon.Beacon.Verify].
+func VerifyPhase1Beacon(current, contribution *Phase1, b *beacon.Beacon) error {
+	if err := verifyPhase1(current, contribution); err != nil {
+		return err
+	}
+	s := beaconScalars(b, current.Hash, 3)
+	var tauBI, alphaBI, betaBI big.Int
+	s[0].BigInt(&tauBI)
+	s[1].BigInt(&alphaBI)
+	s[2].BigInt(&betaBI)
+	var tau curve.G2Affine
+	var alpha curve.G1Affine
+	var beta curve.G2Affine
+	tau.ScalarMultiplication(&current.Parameters.G2.Tau[1], &tauBI)
+	alpha.ScalarMultiplication(&current.Parameters.G1.AlphaTau[0], &alphaBI)
+	beta.ScalarMultiplication(&current.Parameters.G2.Beta, &betaBI)
+	if !tau.Equal(&contribution.Parameters.G2.Tau[1]) {
+		return errors.New("couldn't verify that τ is derived from the beacon")
+	}
+	if !alpha.Equal(&contribution.Parameters.G1.AlphaTau[0]) {
+		return errors.New("couldn't verify that α is derived from the beacon")
+	}
+	if !beta.Equal(&contribution.Parameters.G2.Beta) {
+		return errors.New("couldn't verify that β is derived from the beacon")
+	}
+	return nil
+}
+
 // verifyPhase1 checks that a contribution is based on a known previous Phase1 state.
 func verifyPhase1(current, contribution *Phase1) error {
 	// Compute R for τ, α, β
@@ -167,22 +212,8 @@ func verifyPhase1(current, contribution *Phase1) error {
 	}
 
 	// Check for valid updates using powers of τ
-	_, _, g1, g2 := curve.Generators()
-	tauL1, tauL2 := linearCombinationG1(contribution.Parameters.G1.Tau)
-	if !sameRatio(tauL1, tauL2, contribution.Parameters.G2.Tau[1], g2) {
-		return errors.New("couldn't verify valid powers of τ in G₁")
-	}
-	alphaL1, alphaL2 := linearCombinationG1(contribution.Parameters.G1.AlphaTau)
-	if !sameRatio(alphaL1, alphaL2, contribution.Parameters.G2.Tau[1], g2) {
-		return errors.New("couldn't verify valid powers of α(τ) in G₁")
-	}
-	betaL1, betaL2 := linearCombinationG1(contribution.Parameters.G1.BetaTau)
-	if !sameRatio(betaL1, betaL2, contribution.Parameters.G2.Tau[1], g2) {
-		return errors.New("couldn't verify valid powers of α(τ) in G₁")
-	}
-	tau2L1, tau2L2 := linearCombinationG2(contribution.Parameters.G2.Tau)
-	if !sameRatio(contribution.Parameters.G1.Tau[1], g1, tau2L1, tau2L2) {
-		return errors.New("couldn't verify valid powers of τ in G₂")
+	if err := verifyPowers(contribution); err != nil {
+		return err
 	}
 
 	// Check hash of the contribution
@@ -196,6 +227,29 @@ func verifyPhase1(current, contribution *Phase1) error {
 	return nil
 }
 
+// verifyPowers checks that the parameters of phase1 are consistent powers of τ,
+// multiplied by α and β for AlphaTau and BetaTau.
+func verifyPowers(phase1 *Phase1) error {
+	_, _, g1, g2 := curve.Generators()
+	tauL1, tauL2 := linearCombinationG1(phase1.Parameters.G1.Tau)
+	if !sameRatio(tauL1, tauL2, phase1.Parameters.G2.Tau[1], g2) {
+		return errors.New("couldn't verify valid powers of τ in G₁")
+	}
+	alphaL1, alphaL2 := linearCombinationG1(phase1.Parameters.G1.AlphaTau)
+	if !sameRatio(alphaL1, alphaL2, phase1.Parameters.G2.Tau[1], g2) {
+		return errors.New("couldn't verify valid powers of α(τ) in G₁")
+	}
+	betaL1, betaL2 := linearCombinationG1(phase1.Parameters.G1.BetaTau)
+	if !sameRatio(betaL1, betaL2, phase1.Parameters.G2.Tau[1], g2) {
+		return errors.New("couldn't verify valid powers of α(τ) in G₁")
+	}
+	tau2L1, tau2L2 := linearCombinationG2(phase1.Parameters.G2.Tau)
+	if !sameRatio(phase1.Parameters.G1.Tau[1], g1, tau2L1, tau2L2) {
+		return errors.New("couldn't verify valid powers of τ in G₂")
+	}
+	return nil
+}
+
 func (phase1 *Phase1) hash() []byte {
 	sha := sha256.New()
 	phase1.writeTo(sha)
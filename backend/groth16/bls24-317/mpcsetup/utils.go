@@ -25,6 +25,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark/backend/beacon"
 	"github.com/consensys/gnark/internal/utils"
 )
 
@@ -57,6 +58,23 @@ func newPublicKey(x fr.Element, challenge []byte, dst byte) PublicKey {
 	return pk
 }
 
+// beaconDST is the domain separation tag of the contributions derived from a
+// beacon.
+const beaconDST = "gnark/mpcsetup/beacon"
+
+// beaconScalars derives n scalars from the randomness of the beacon round b and
+// the hash of the previous contribution.
+func beaconScalars(b *beacon.Beacon, challenge []byte, n int) []fr.Element {
+	msg := make([]byte, 0, len(b.Randomness)+len(challenge))
+	msg = append(msg, b.Randomness...)
+	msg = append(msg, challenge...)
+	s, err := fr.Hash(msg, []byte(beaconDST), n)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 func bitReverse[T any](a []T) {
 	n := uint64(len(a))
 	nn := uint64(64 - bits.TrailingZeros64(n))
@@ -17,11 +17,16 @@
 package groth16_test
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
@@ -148,3 +153,71 @@ func TestOneSecretOnePublicCommitted(t *testing.T) {
 		Two: 2,
 	})
 }
+
+type twoSecretsCommittedCircuit struct {
+	X, Y frontend.Variable
+	Sum  frontend.Variable `gnark:",public"`
+}
+
+func (c *twoSecretsCommittedCircuit) Define(api frontend.API) error {
+	commit, err := api.Compiler().(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(commit, 0)
+	api.AssertIsEqual(api.Add(c.X, c.Y), c.Sum)
+	return nil
+}
+
+func TestCommitmentLink(t *testing.T) {
+	_r1cs, pk, vk := setup(t, &twoSecretsCommittedCircuit{})
+	public, proof := prove(t, &twoSecretsCommittedCircuit{X: 3, Y: 5, Sum: 8}, _r1cs, pk)
+	assert.NoError(t, groth16.Verify(proof, vk, public))
+
+	_pk := pk.(*groth16_bn254.ProvingKey)
+	_vk := vk.(*groth16_bn254.VerifyingKey)
+	_proof := proof.(*groth16_bn254.Proof)
+	basis, err := _pk.CommitmentBasis(0)
+	assert.NoError(t, err)
+
+	// external commitment to (X, Y), committed beforehand with its own key
+	_, _, g1, _ := curve.Generators()
+	bases := make([]curve.G1Affine, 3)
+	for i := range bases {
+		bases[i].ScalarMultiplication(&g1, big.NewInt(int64(10+i)))
+	}
+	var values [3]fr.Element // X, Y and the blinding factor
+	values[0].SetUint64(3)
+	values[1].SetUint64(5)
+	_, err = values[2].SetRandom()
+	assert.NoError(t, err)
+	var commitment curve.G1Affine
+	_, err = commitment.MultiExp(bases, values[:], ecc.MultiExpConfig{})
+	assert.NoError(t, err)
+
+	link, err := groth16_bn254.ProveCommitmentLink(_pk, _proof, 0, values[:2], bases, values[2])
+	assert.NoError(t, err)
+	assert.NoError(t, groth16_bn254.VerifyCommitmentLink(_vk, _proof, 0, basis, commitment, bases, link))
+
+	var buf bytes.Buffer
+	_, err = link.WriteTo(&buf)
+	assert.NoError(t, err)
+	var decoded groth16_bn254.CommitmentLink
+	_, err = decoded.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.NoError(t, groth16_bn254.VerifyCommitmentLink(_vk, _proof, 0, basis, commitment, bases, &decoded))
+
+	// external commitment to other values
+	var other curve.G1Affine
+	other.Add(&commitment, &bases[0])
+	assert.Error(t, groth16_bn254.VerifyCommitmentLink(_vk, _proof, 0, basis, other, bases, link))
+
+	// basis not matching the verifying key
+	wrongBasis := append([]curve.G1Affine{}, basis...)
+	wrongBasis[0].Add(&wrongBasis[0], &bases[0])
+	assert.Error(t, groth16_bn254.VerifyCommitmentLink(_vk, _proof, 0, wrongBasis, commitment, bases, link))
+
+	values[0].SetUint64(4)
+	_, err = groth16_bn254.ProveCommitmentLink(_pk, _proof, 0, values[:2], bases, values[2])
+	assert.Error(t, err)
+}
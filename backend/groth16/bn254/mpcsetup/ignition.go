@@ -0,0 +1,181 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package mpcsetup
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"golang.org/x/crypto/blake2b"
+	"hash"
+	"io"
+)
+
+// ignitionManifest is the header of a transcript of the Aztec Ignition
+// ceremony, in big-endian.
+type ignitionManifest struct {
+	TranscriptNumber uint32
+	TotalTranscripts uint32
+	TotalG1Points    uint32
+	TotalG2Points    uint32
+	NbG1Points       uint32
+	NbG2Points       uint32
+	StartFrom        uint32
+}
+
+// ReadIgnition reads the transcripts of the Aztec Ignition ceremony and returns
+// a KZG structured reference string of the given size. The transcripts must be
+// given in order, starting from the first one, and only the first transcripts
+// holding the size - 1 needed powers are read.
+//
+// A transcript is made of the manifest, the points [τ]₁, [τ²]₁, … of G1, the
+// points of G2 starting from [τ]₂ and of the BLAKE2b checksum of the previous
+// data. The checksums, the subgroups and the consistency of the powers of τ
+// are checked. The contributions are not verified: their verification is done
+// by the ceremony and its attestations.
+func ReadIgnition(size uint64, transcripts ...io.Reader) (*kzg.SRS, error) {
+	if size < 2 {
+		return nil, kzg.ErrMinSRSSize
+	}
+	_, _, g1, g2 := curve.Generators()
+
+	var srs kzg.SRS
+	srs.Pk.G1 = make([]curve.G1Affine, 1, size)
+	srs.Pk.G1[0] = g1
+	srs.Vk.G1 = g1
+	srs.Vk.G2[0] = g2
+
+	for i, r := range transcripts {
+		if uint64(len(srs.Pk.G1)) == size {
+			break
+		}
+		var err error
+		if srs.Pk.G1, err = readIgnitionTranscript(r, uint32(i), srs.Pk.G1, int(size), &srs.Vk.G2[1]); err != nil {
+			return nil, fmt.Errorf("transcript %d: %w", i, err)
+		}
+	}
+	if uint64(len(srs.Pk.G1)) != size {
+		return nil, fmt.Errorf("the transcripts have %d powers of τ, %d requested", len(srs.Pk.G1), size)
+	}
+
+	// check the powers of τ
+	if !g1InSubGroup(srs.Pk.G1) || !g2InSubGroup(srs.Vk.G2[1:]) {
+		return nil, errors.New("point not in the subgroup")
+	}
+	tauL1, tauL2 := linearCombinationG1(srs.Pk.G1)
+	if !sameRatio(tauL1, tauL2, srs.Vk.G2[1], g2) {
+		return nil, errors.New("couldn't verify valid powers of τ in G₁")
+	}
+
+	return &srs, nil
+}
+
+// readIgnitionTranscript reads the transcript number i and appends its G1
+// points to g1 until it has size points. It sets tauG2 from the first
+// transcript.
+func readIgnitionTranscript(r io.Reader, i uint32, g1 []curve.G1Affine, size int, tauG2 *curve.G2Affine) ([]curve.G1Affine, error) {
+	h, err := blake2b.New512(nil)
+	if err != nil {
+		return nil, err
+	}
+	tr := io.TeeReader(r, h)
+
+	var m ignitionManifest
+	if err := binary.Read(tr, binary.BigEndian, &m); err != nil {
+		return nil, err
+	}
+	if m.TranscriptNumber != i || int(m.StartFrom) != len(g1)-1 {
+		return nil, errors.New("the transcripts are not in order")
+	}
+	if i == 0 && m.NbG2Points == 0 {
+		return nil, errors.New("missing [τ]₂ in the first transcript")
+	}
+
+	// G1 points
+	const sizeG1 = 2 * fp.Bytes
+	buf := make([]byte, sizeG1)
+	for j := uint32(0); j < m.NbG1Points; j++ {
+		if _, err := io.ReadFull(tr, buf); err != nil {
+			return nil, err
+		}
+		if len(g1) == size {
+			continue
+		}
+		var p curve.G1Affine
+		if err := setIgnitionElements(buf, &p.X, &p.Y); err != nil {
+			return nil, err
+		}
+		if !p.IsOnCurve() {
+			return nil, errors.New("point not on the curve")
+		}
+		g1 = append(g1, p)
+	}
+
+	// G2 points
+	buf = make([]byte, 4*fp.Bytes)
+	for j := uint32(0); j < m.NbG2Points; j++ {
+		if _, err := io.ReadFull(tr, buf); err != nil {
+			return nil, err
+		}
+		if i != 0 || j != 0 {
+			continue
+		}
+		if err := setIgnitionElements(buf, &tauG2.X.A0, &tauG2.X.A1, &tauG2.Y.A0, &tauG2.Y.A1); err != nil {
+			return nil, err
+		}
+		if !tauG2.IsOnCurve() {
+			return nil, errors.New("point not on the curve")
+		}
+	}
+
+	return g1, checkIgnitionChecksum(r, h)
+}
+
+// checkIgnitionChecksum reads the checksum from r and compares it with h.
+func checkIgnitionChecksum(r io.Reader, h hash.Hash) error {
+	checksum := make([]byte, blake2b.Size)
+	if _, err := io.ReadFull(r, checksum); err != nil {
+		return err
+	}
+	expected := h.Sum(nil)
+	for i := range checksum {
+		if checksum[i] != expected[i] {
+			return errors.New("invalid checksum")
+		}
+	}
+	return nil
+}
+
+// setIgnitionElements decodes the base field elements from b, in the Ignition
+// format: 64-bit limbs from the least significant one, each in big-endian.
+func setIgnitionElements(b []byte, elements ...*fp.Element) error {
+	const nbLimbs = fp.Bytes / 8
+	var be [fp.Bytes]byte
+	for i, e := range elements {
+		limbs := b[i*fp.Bytes:]
+		for j := 0; j < nbLimbs; j++ {
+			copy(be[8*(nbLimbs-1-j):], limbs[8*j:8*(j+1)])
+		}
+		if err := e.SetBytesCanonical(be[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package mpcsetup
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/internal/utils"
+	"io"
+	"math/big"
+)
+
+// sections of a snarkjs .ptau file
+const (
+	ptauHeader     = 1
+	ptauTauG1      = 2
+	ptauTauG2      = 3
+	ptauAlphaTauG1 = 4
+	ptauBetaTauG1  = 5
+	ptauBetaG2     = 6
+)
+
+// ReadPtau reads a Powers of Tau transcript in the snarkjs .ptau format, such
+// as the files of the Perpetual Powers of Tau ceremony, and returns it as a
+// Phase 1 for circuits of up to 2ᵖᵒʷᵉʳ constraints, power being at most the
+// power of the file.
+//
+// The points are checked to be in the prime order subgroups, and the powers of
+// τ, α⋅τ and β⋅τ to be consistent. The contributions of the file are not
+// verified: their verification is done by the ceremony and its attestations.
+// The Phase 1 is the starting point of the Phase 2 of the ceremony, or can be
+// converted with [Phase1.KZGSRS].
+func ReadPtau(r io.Reader, power int) (*Phase1, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	if string(hdr[:4]) != "ptau" {
+		return nil, errors.New("not a ptau file")
+	}
+	nbSections := binary.LittleEndian.Uint32(hdr[8:])
+
+	var phase1 Phase1
+	n := 1 << power
+	params := &phase1.Parameters
+	filePower := -1
+	for i := uint32(0); i < nbSections; i++ {
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return nil, err
+		}
+		id := binary.LittleEndian.Uint32(hdr[:4])
+		size := binary.LittleEndian.Uint64(hdr[4:])
+		section := io.LimitReader(r, int64(size))
+
+		var err error
+		if id == ptauHeader {
+			filePower, err = readPtauHeader(section)
+			if err == nil && power > filePower {
+				err = fmt.Errorf("the file has power %d, %d requested", filePower, power)
+			}
+		} else if id <= ptauBetaG2 && filePower < 0 {
+			err = errors.New("the header must be the first section")
+		} else {
+			switch id {
+			case ptauTauG1:
+				params.G1.Tau, err = readPtauG1(section, 2*n-1)
+			case ptauTauG2:
+				params.G2.Tau, err = readPtauG2(section, n)
+			case ptauAlphaTauG1:
+				params.G1.AlphaTau, err = readPtauG1(section, n)
+			case ptauBetaTauG1:
+				params.G1.BetaTau, err = readPtauG1(section, n)
+			case ptauBetaG2:
+				var betaG2 []curve.G2Affine
+				if betaG2, err = readPtauG2(section, 1); err == nil {
+					params.G2.Beta = betaG2[0]
+				}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", id, err)
+		}
+
+		// skip the rest of the section
+		if _, err := io.Copy(io.Discard, section); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.G1.Tau == nil || params.G2.Tau == nil || params.G1.AlphaTau == nil || params.G1.BetaTau == nil || params.G2.Beta.IsInfinity() {
+		return nil, errors.New("missing sections in the ptau file")
+	}
+	if err := verifyImportedPhase1(&phase1); err != nil {
+		return nil, err
+	}
+	phase1.Hash = phase1.hash()
+
+	return &phase1, nil
+}
+
+// verifyImportedPhase1 checks that the parameters of a Phase 1 which does not
+// come from the contributions of this package are well formed.
+func verifyImportedPhase1(phase1 *Phase1) error {
+	params := &phase1.Parameters
+	_, _, g1, g2 := curve.Generators()
+	if len(params.G2.Tau) < 2 {
+		return errors.New("the Phase 1 must have at least 2 powers of τ")
+	}
+	if !params.G1.Tau[0].Equal(&g1) || !params.G2.Tau[0].Equal(&g2) {
+		return errors.New("the first powers of τ must be the generators")
+	}
+	for _, points := range [][]curve.G1Affine{params.G1.Tau, params.G1.AlphaTau, params.G1.BetaTau} {
+		if !g1InSubGroup(points) {
+			return errors.New("point not in the G₁ subgroup")
+		}
+	}
+	if !g2InSubGroup(append(params.G2.Tau, params.G2.Beta)) {
+		return errors.New("point not in the G₂ subgroup")
+	}
+	if err := verifyPowers(phase1); err != nil {
+		return err
+	}
+	if !sameRatio(params.G1.BetaTau[0], g1, g2, params.G2.Beta) {
+		return errors.New("couldn't verify that [β]₁ and [β]₂ are consistent")
+	}
+	return nil
+}
+
+func g1InSubGroup(points []curve.G1Affine) bool {
+	ok := make([]bool, len(points))
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			ok[i] = !points[i].IsInfinity() && points[i].IsInSubGroup()
+		}
+	})
+	for i := range ok {
+		if !ok[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func g2InSubGroup(points []curve.G2Affine) bool {
+	ok := make([]bool, len(points))
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			ok[i] = !points[i].IsInfinity() && points[i].IsInSubGroup()
+		}
+	})
+	for i := range ok {
+		if !ok[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readPtauHeader reads the header section and returns the power of the file.
+func readPtauHeader(r io.Reader) (int, error) {
+	var n8 uint32
+	if err := binary.Read(r, binary.LittleEndian, &n8); err != nil {
+		return 0, err
+	}
+	if n8 != fp.Bytes {
+		return 0, errors.New("the file is not defined on BN254")
+	}
+	q := make([]byte, n8)
+	if _, err := io.ReadFull(r, q); err != nil {
+		return 0, err
+	}
+	reverse(q)
+	if new(big.Int).SetBytes(q).Cmp(fp.Modulus()) != 0 {
+		return 0, errors.New("the file is not defined on BN254")
+	}
+	var power uint32
+	if err := binary.Read(r, binary.LittleEndian, &power); err != nil {
+		return 0, err
+	}
+	return int(power), nil
+}
+
+// readPtauG1 reads n points of G1 in the snarkjs format.
+func readPtauG1(r io.Reader, n int) ([]curve.G1Affine, error) {
+	buf := make([]byte, n*2*fp.Bytes)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	res := make([]curve.G1Affine, n)
+	errs := make([]error, n)
+	utils.Parallelize(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			b := buf[i*2*fp.Bytes:]
+			if errs[i] = setPtauElements(b, &res[i].X, &res[i].Y); errs[i] == nil && !res[i].IsOnCurve() {
+				errs[i] = errors.New("point not on the curve")
+			}
+		}
+	})
+	return res, firstError(errs)
+}
+
+// readPtauG2 reads n points of G2 in the snarkjs format.
+func readPtauG2(r io.Reader, n int) ([]curve.G2Affine, error) {
+	buf := make([]byte, n*4*fp.Bytes)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	res := make([]curve.G2Affine, n)
+	errs := make([]error, n)
+	utils.Parallelize(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			b := buf[i*4*fp.Bytes:]
+			if errs[i] = setPtauElements(b, &res[i].X.A0, &res[i].X.A1, &res[i].Y.A0, &res[i].Y.A1); errs[i] == nil && !res[i].IsOnCurve() {
+				errs[i] = errors.New("point not on the curve")
+			}
+		}
+	})
+	return res, firstError(errs)
+}
+
+// rInv is the inverse of the Montgomery constant R = 2ᵇⁱᵗˢ of the snarkjs
+// encoding of the base field elements.
+var rInv = func() fp.Element {
+	var r fp.Element
+	r.SetBigInt(new(big.Int).Lsh(big.NewInt(1), 8*fp.Bytes))
+	return *r.Inverse(&r)
+}()
+
+// setPtauElements decodes the base field elements from b, in the snarkjs
+// format: little-endian and in Montgomery form. The point at infinity is
+// encoded with zeros.
+func setPtauElements(b []byte, elements ...*fp.Element) error {
+	var be [fp.Bytes]byte
+	for i, e := range elements {
+		copy(be[:], b[i*fp.Bytes:(i+1)*fp.Bytes])
+		reverse(be[:])
+		if err := e.SetBytesCanonical(be[:]); err != nil {
+			return err
+		}
+		e.Mul(e, &rInv)
+	}
+	return nil
+}
+
+// firstError returns the first non nil error of errs.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
@@ -22,6 +22,7 @@ import (
 	cs "github.com/consensys/gnark/constraint/bn254"
 	"testing"
 
+	"github.com/consensys/gnark/backend/beacon"
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
@@ -52,6 +53,15 @@ func TestSetupCircuit(t *testing.T) {
 		assert.NoError(VerifyPhase1(&prev, &srs1))
 	}
 
+	// Finalize phase1 with a beacon
+	b1 := &beacon.Beacon{Round: 1, Randomness: []byte("round 1")}
+	{
+		prev := srs1.clone()
+		srs1.ContributeFromBeacon(b1)
+		assert.NoError(VerifyPhase1Beacon(&prev, &srs1, b1))
+		assert.Error(VerifyPhase1Beacon(&prev, &srs1, &beacon.Beacon{Round: 2, Randomness: []byte("round 2")}))
+	}
+
 	// Compile the circuit
 	var myCircuit Circuit
 	ccs, err := frontend.Compile(curve.ID.ScalarField(), r1cs.NewBuilder, &myCircuit)
@@ -73,6 +83,15 @@ func TestSetupCircuit(t *testing.T) {
 		assert.NoError(VerifyPhase2(&prev, &srs2))
 	}
 
+	// Finalize phase2 with a beacon
+	b2 := &beacon.Beacon{Round: 3, Randomness: []byte("round 3")}
+	{
+		prev := srs2.clone()
+		srs2.ContributeFromBeacon(b2)
+		assert.NoError(VerifyPhase2Beacon(&prev, &srs2, b2))
+		assert.Error(VerifyPhase2Beacon(&prev, &srs2, b1))
+	}
+
 	// Extract the proving and verifying keys
 	pk, vk := ExtractKeys(&srs1, &srs2, &evals, ccs.GetNbConstraints())
 
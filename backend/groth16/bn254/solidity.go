@@ -1,5 +1,13 @@
 package groth16
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"golang.org/x/crypto/sha3"
+)
+
 // solidityTemplate
 // this is an experimental feature and gnark solidity generator as not been thoroughly tested
 const solidityTemplate = `
@@ -543,3 +551,54 @@ contract Verifier {
     }
 }
 `
+
+// MarshalSolidity converts a proof to the uint256[8] array of points (A, B, C)
+// in EIP-197 format expected by the verifyProof function of the Solidity
+// contract written by ExportSolidity. The commitments of the proof are not
+// encoded, the contract doesn't support them.
+func (proof *Proof) MarshalSolidity() []byte {
+	res := make([]byte, 0, 8*32)
+
+	// uint256 a_x;
+	// uint256 a_y;
+	tmp64 := proof.Ar.RawBytes()
+	res = append(res, tmp64[:]...)
+
+	// uint256 b_x_1;
+	// uint256 b_x_0;
+	// uint256 b_y_1;
+	// uint256 b_y_0;
+	tmp128 := proof.Bs.RawBytes()
+	res = append(res, tmp128[:]...)
+
+	// uint256 c_x;
+	// uint256 c_y;
+	tmp64 = proof.Krs.RawBytes()
+	res = append(res, tmp64[:]...)
+
+	return res
+}
+
+// SolidityCalldata returns the ABI encoded call to the verifyProof function of
+// the Solidity contract written by ExportSolidity, for proof and the public
+// witness, so that it can be sent as is in a transaction or an eth_call.
+func SolidityCalldata(proof *Proof, publicWitness fr.Vector) ([]byte, error) {
+	if len(proof.Commitments) != 0 {
+		return nil, errors.New("the solidity verifier doesn't support commitments")
+	}
+
+	// verifyProof(uint256[8],uint256[n]) selector
+	h := sha3.NewLegacyKeccak256()
+	fmt.Fprintf(h, "verifyProof(uint256[8],uint256[%d])", len(publicWitness))
+	selector := h.Sum(nil)[:4]
+
+	// the arrays have a static size, they are encoded in place
+	res := make([]byte, 0, 4+8*32+len(publicWitness)*fr.Bytes)
+	res = append(res, selector...)
+	res = append(res, proof.MarshalSolidity()...)
+	for i := range publicWitness {
+		tmp32 := publicWitness[i].Bytes()
+		res = append(res, tmp32[:]...)
+	}
+	return res, nil
+}
@@ -0,0 +1,46 @@
+package groth16
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolidityCalldata(t *testing.T) {
+	assert := require.New(t)
+
+	_, _, g1, g2 := curve.Generators()
+	var proof Proof
+	proof.Ar.ScalarMultiplication(&g1, big.NewInt(2))
+	proof.Bs.ScalarMultiplication(&g2, big.NewInt(3))
+	proof.Krs.ScalarMultiplication(&g1, big.NewInt(5))
+	publicWitness := fr.Vector{fr.NewElement(7), fr.NewElement(11)}
+
+	// the points are encoded as in the raw encoding of the proof
+	var buf bytes.Buffer
+	_, err := proof.WriteRawTo(&buf)
+	assert.NoError(err)
+	assert.Equal(buf.Bytes()[:8*32], proof.MarshalSolidity())
+
+	calldata, err := SolidityCalldata(&proof, publicWitness)
+	assert.NoError(err)
+	assert.Len(calldata, 4+10*32)
+	assert.Equal(proof.MarshalSolidity(), calldata[4:4+8*32])
+	for i := range publicWitness {
+		b := publicWitness[i].Bytes()
+		assert.Equal(b[:], calldata[4+(8+i)*32:4+(9+i)*32])
+	}
+
+	// the selector depends on the number of public inputs
+	other, err := SolidityCalldata(&proof, publicWitness[:1])
+	assert.NoError(err)
+	assert.NotEqual(calldata[:4], other[:4])
+
+	proof.Commitments = []curve.G1Affine{g1}
+	_, err = SolidityCalldata(&proof, publicWitness)
+	assert.Error(err)
+}
@@ -111,6 +111,11 @@ func TestVerifyingKeySerialization(t *testing.T) {
 				}
 				_, vk.CommitmentKey, err = pedersen.Setup(bases...)
 				assert.NoError(t, err)
+				for i := range bases {
+					digest, err := commitmentBasisDigest(bases[i])
+					assert.NoError(t, err)
+					vk.CommitmentBasisDigests = append(vk.CommitmentBasisDigests, digest)
+				}
 			}
 
 			err = io.RoundTripCheck(&vk, func() any { return new(VerifyingKey) })
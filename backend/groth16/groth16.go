@@ -20,8 +20,11 @@
 package groth16
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/witness"
@@ -42,6 +45,8 @@ import (
 	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
 	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
 
+	"github.com/consensys/gnark/backend/groth16/internal"
+	"github.com/consensys/gnark/internal/utils"
 	gnarkio "github.com/consensys/gnark/io"
 
 	groth16_bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377"
@@ -80,9 +85,63 @@ type ProvingKey interface {
 	// NbG2 returns the number of G2 elements in the ProvingKey
 	NbG2() int
 
+	// WriteSectionsTo writes the ProvingKey in the sectioned format, see
+	// [NewSectionedProvingKey]
+	WriteSectionsTo(w io.Writer) (int64, error)
+
 	IsDifferent(interface{}) bool
 }
 
+// StreamingProvingKey represents a Groth16 ProvingKey read from disk in chunks
+// by [ProveStreaming], see [NewStreamingProvingKey].
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+type StreamingProvingKey interface {
+	CurveID() ecc.ID
+
+	// NbG1 returns the number of G1 elements in the ProvingKey
+	NbG1() int
+
+	// NbG2 returns the number of G2 elements in the ProvingKey
+	NbG2() int
+}
+
+// ProvingKeySection identifies a slice of points of a ProvingKey, decoded
+// independently of the others in a SectionedProvingKey.
+type ProvingKeySection = internal.Section
+
+const (
+	SectionA  = internal.SectionA  // [A(τ)]₁ of the wires
+	SectionB1 = internal.SectionB1 // [B(τ)]₁ of the wires
+	SectionZ  = internal.SectionZ  // [τⁱ·Z(τ)/δ]₁ of the quotient
+	SectionK  = internal.SectionK  // [(βA(τ)+αB(τ)+C(τ))/δ]₁ of the private wires
+	SectionB2 = internal.SectionB2 // [B(τ)]₂ of the wires
+)
+
+// SectionedProvingKey represents a Groth16 ProvingKey whose sections are
+// decoded lazily, see [OpenSectionedProvingKey].
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+type SectionedProvingKey interface {
+	CurveID() ecc.ID
+
+	// NbG1 returns the number of G1 elements in the ProvingKey
+	NbG1() int
+
+	// NbG2 returns the number of G2 elements in the ProvingKey
+	NbG2() int
+
+	// Load decodes the given sections and keeps them in memory until they
+	// are released
+	Load(sections ...ProvingKeySection) error
+
+	// Release frees the memory of the given sections
+	Release(sections ...ProvingKeySection)
+
+	// Close releases the sections and unmaps the file of the key
+	Close() error
+}
+
 // VerifyingKey represents a Groth16 VerifyingKey
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
@@ -108,8 +167,31 @@ type VerifyingKey interface {
 	IsDifferent(interface{}) bool
 }
 
+var (
+	errProvingKeyCurve   = fmt.Errorf("%w: the proving key does not match the constraint system curve", gnark.ErrCurveMismatch)
+	errVerifyingKeyCurve = fmt.Errorf("%w: the verifying key does not match the proof curve", gnark.ErrCurveMismatch)
+)
+
+// checkR1CS returns an error if r1cs was compiled for a PLONK backend. The
+// curve-typed R1CS and SparseR1CS are the same Go type, so the type switches
+// below can't tell them apart.
+func checkR1CS(r1cs constraint.ConstraintSystem) error {
+	if r1cs == nil {
+		return nil
+	}
+	if _, ok := r1cs.GetCommitments().(constraint.Groth16Commitments); !ok {
+		return errors.New("the constraint system is not a R1CS")
+	}
+	return nil
+}
+
 // Verify runs the groth16.Verify algorithm on provided proof with given witness
-func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) (err error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
 
 	switch _proof := proof.(type) {
 	case *groth16_bls12377.Proof:
@@ -117,48 +199,144 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return groth16_bls12377.Verify(_proof, vk.(*groth16_bls12377.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*groth16_bls12377.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return groth16_bls12377.Verify(_proof, _vk, w, opts...)
 	case *groth16_bls12381.Proof:
 		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return groth16_bls12381.Verify(_proof, vk.(*groth16_bls12381.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*groth16_bls12381.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return groth16_bls12381.Verify(_proof, _vk, w, opts...)
 	case *groth16_bn254.Proof:
 		w, ok := publicWitness.Vector().(fr_bn254.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return groth16_bn254.Verify(_proof, vk.(*groth16_bn254.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*groth16_bn254.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return groth16_bn254.Verify(_proof, _vk, w, opts...)
 	case *groth16_bw6761.Proof:
 		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return groth16_bw6761.Verify(_proof, vk.(*groth16_bw6761.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*groth16_bw6761.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return groth16_bw6761.Verify(_proof, _vk, w, opts...)
 	case *groth16_bls24317.Proof:
 		w, ok := publicWitness.Vector().(fr_bls24317.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return groth16_bls24317.Verify(_proof, vk.(*groth16_bls24317.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*groth16_bls24317.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return groth16_bls24317.Verify(_proof, _vk, w, opts...)
 	case *groth16_bls24315.Proof:
 		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return groth16_bls24315.Verify(_proof, vk.(*groth16_bls24315.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*groth16_bls24315.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return groth16_bls24315.Verify(_proof, _vk, w, opts...)
 	case *groth16_bw6633.Proof:
 		w, ok := publicWitness.Vector().(fr_bw6633.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return groth16_bw6633.Verify(_proof, vk.(*groth16_bw6633.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*groth16_bw6633.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return groth16_bw6633.Verify(_proof, _vk, w, opts...)
 	default:
-		panic("unrecognized R1CS curve type")
+		return errors.New("unrecognized R1CS curve type")
 	}
 }
 
+// BatchVerify verifies several proofs for the same VerifyingKey, each with
+// its public witness. It returns an error if any of the proofs is invalid.
+//
+// The pairing equations are combined with a random linear combination, so
+// that N proofs are verified with N+2 Miller loops, a single final
+// exponentiation and a few multi-exponentiations, instead of N calls to
+// Verify.
+func BatchVerify(vk VerifyingKey, proofs []Proof, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) (err error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+
+	switch _vk := vk.(type) {
+	case *groth16_bls12377.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls12377.Proof, w []fr_bls12377.Vector) error {
+			return groth16_bls12377.BatchVerify(_vk, p, w, opts...)
+		})
+	case *groth16_bls12381.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls12381.Proof, w []fr_bls12381.Vector) error {
+			return groth16_bls12381.BatchVerify(_vk, p, w, opts...)
+		})
+	case *groth16_bn254.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bn254.Proof, w []fr_bn254.Vector) error {
+			return groth16_bn254.BatchVerify(_vk, p, w, opts...)
+		})
+	case *groth16_bw6761.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bw6761.Proof, w []fr_bw6761.Vector) error {
+			return groth16_bw6761.BatchVerify(_vk, p, w, opts...)
+		})
+	case *groth16_bls24317.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls24317.Proof, w []fr_bls24317.Vector) error {
+			return groth16_bls24317.BatchVerify(_vk, p, w, opts...)
+		})
+	case *groth16_bls24315.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bls24315.Proof, w []fr_bls24315.Vector) error {
+			return groth16_bls24315.BatchVerify(_vk, p, w, opts...)
+		})
+	case *groth16_bw6633.VerifyingKey:
+		return batchVerify(proofs, publicWitnesses, func(p []*groth16_bw6633.Proof, w []fr_bw6633.Vector) error {
+			return groth16_bw6633.BatchVerify(_vk, p, w, opts...)
+		})
+	default:
+		return errors.New("unrecognized R1CS curve type")
+	}
+}
+
+// batchVerify casts the proofs and the public witnesses to their curve typed
+// implementation, and calls verify with them.
+func batchVerify[P any, W any](proofs []Proof, publicWitnesses []witness.Witness, verify func([]P, []W) error) error {
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs but %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	_proofs := make([]P, len(proofs))
+	_publicWitnesses := make([]W, len(publicWitnesses))
+	for i := range proofs {
+		var ok bool
+		if _proofs[i], ok = proofs[i].(P); !ok {
+			return errVerifyingKeyCurve
+		}
+		if _publicWitnesses[i], ok = publicWitnesses[i].Vector().(W); !ok {
+			return witness.ErrInvalidWitness
+		}
+	}
+	return verify(_proofs, _publicWitnesses)
+}
+
 // Prove runs the groth16.Prove algorithm.
 //
 // if the force flag is set:
@@ -166,32 +344,264 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 //		will execute all the prover computations, even if the witness is invalid
 //	 will produce an invalid proof
 //		internally, the solution vector to the R1CS will be filled with random values which may impact benchmarking
-func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(r1cs); err != nil {
+		return nil, err
+	}
 
 	switch _r1cs := r1cs.(type) {
 	case *cs_bls12377.R1CS:
-		return groth16_bls12377.Prove(_r1cs, pk.(*groth16_bls12377.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*groth16_bls12377.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return groth16_bls12377.Prove(_r1cs, _pk, fullWitness, opts...)
 
 	case *cs_bls12381.R1CS:
-		return groth16_bls12381.Prove(_r1cs, pk.(*groth16_bls12381.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*groth16_bls12381.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return groth16_bls12381.Prove(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bn254.R1CS:
+		_pk, ok := pk.(*groth16_bn254.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return groth16_bn254.Prove(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bw6761.R1CS:
+		_pk, ok := pk.(*groth16_bw6761.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return groth16_bw6761.Prove(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bls24317.R1CS:
+		_pk, ok := pk.(*groth16_bls24317.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return groth16_bls24317.Prove(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bls24315.R1CS:
+		_pk, ok := pk.(*groth16_bls24315.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return groth16_bls24315.Prove(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bw6633.R1CS:
+		_pk, ok := pk.(*groth16_bw6633.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return groth16_bw6633.Prove(_r1cs, _pk, fullWitness, opts...)
+
+	default:
+		return nil, errors.New("unrecognized R1CS curve type")
+	}
+}
+
+// ProveStreaming generates the proof of knowledge of a r1cs with full witness
+// (secret + public part), like [Prove], with the points of the proving key read
+// from disk in chunks. The size of the chunks is set with
+// [backend.WithProverChunkSize]. It trades proving time for a memory usage
+// which does not depend on the size of the proving key.
+func ProveStreaming(r1cs constraint.ConstraintSystem, pk StreamingProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(r1cs); err != nil {
+		return nil, err
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *cs_bls12377.R1CS:
+		_pk, ok := pk.(*groth16_bls12377.StreamingProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls12377.ProveStreaming(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	case *cs_bls12381.R1CS:
+		_pk, ok := pk.(*groth16_bls12381.StreamingProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls12381.ProveStreaming(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	case *cs_bn254.R1CS:
+		_pk, ok := pk.(*groth16_bn254.StreamingProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bn254.ProveStreaming(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	case *cs_bw6761.R1CS:
+		_pk, ok := pk.(*groth16_bw6761.StreamingProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bw6761.ProveStreaming(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	case *cs_bls24317.R1CS:
+		_pk, ok := pk.(*groth16_bls24317.StreamingProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls24317.ProveStreaming(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	case *cs_bls24315.R1CS:
+		_pk, ok := pk.(*groth16_bls24315.StreamingProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls24315.ProveStreaming(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	case *cs_bw6633.R1CS:
+		_pk, ok := pk.(*groth16_bw6633.StreamingProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bw6633.ProveStreaming(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	default:
+		return nil, errors.New("unrecognized R1CS curve type")
+	}
+}
+
+// ProveSectioned generates the proof of knowledge of a r1cs with full witness
+// (secret + public part), like [Prove], with the sections of the proving key
+// which are not loaded decoded for the duration of their multi-exponentiation
+// only. The memory used by the proving key is then the one of the loaded
+// sections plus the largest section.
+func ProveSectioned(r1cs constraint.ConstraintSystem, pk SectionedProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(r1cs); err != nil {
+		return nil, err
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *cs_bls12377.R1CS:
+		_pk, ok := pk.(*groth16_bls12377.SectionedProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls12377.ProveSectioned(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
+
+	case *cs_bls12381.R1CS:
+		_pk, ok := pk.(*groth16_bls12381.SectionedProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls12381.ProveSectioned(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
 
 	case *cs_bn254.R1CS:
-		return groth16_bn254.Prove(_r1cs, pk.(*groth16_bn254.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*groth16_bn254.SectionedProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bn254.ProveSectioned(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
 
 	case *cs_bw6761.R1CS:
-		return groth16_bw6761.Prove(_r1cs, pk.(*groth16_bw6761.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*groth16_bw6761.SectionedProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bw6761.ProveSectioned(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
 
 	case *cs_bls24317.R1CS:
-		return groth16_bls24317.Prove(_r1cs, pk.(*groth16_bls24317.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*groth16_bls24317.SectionedProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls24317.ProveSectioned(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
 
 	case *cs_bls24315.R1CS:
-		return groth16_bls24315.Prove(_r1cs, pk.(*groth16_bls24315.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*groth16_bls24315.SectionedProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bls24315.ProveSectioned(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
 
 	case *cs_bw6633.R1CS:
-		return groth16_bw6633.Prove(_r1cs, pk.(*groth16_bw6633.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*groth16_bw6633.SectionedProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		proof, err := groth16_bw6633.ProveSectioned(_r1cs, _pk, fullWitness, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return proof, nil
 
 	default:
-		panic("unrecognized R1CS curve type")
+		return nil, errors.New("unrecognized R1CS curve type")
 	}
 }
 
@@ -203,7 +613,15 @@ func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.
 //
 // Two main solutions to this deployment issues are: running the Setup through a MPC (multi party computation)
 // or using a ZKP backend like PLONK where the per-circuit Setup is deterministic.
-func Setup(r1cs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+func Setup(r1cs constraint.ConstraintSystem, opts ...backend.SetupOption) (_ ProvingKey, _ VerifyingKey, err error) {
+	cfg, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(r1cs); err != nil {
+		return nil, nil, err
+	}
 
 	switch _r1cs := r1cs.(type) {
 	case *cs_bls12377.R1CS:
@@ -256,13 +674,82 @@ func Setup(r1cs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
 		}
 		return &pk, &vk, nil
 	default:
-		panic("unrecognized R1CS curve type")
+		return nil, nil, errors.New("unrecognized R1CS curve type")
+	}
+}
+
+// UnsafeSetup is a deterministic and fast Setup for tests only. The keys are
+// derived from seed and encode only the first constraint of the circuit: the
+// proofs generated with the proving key verify, but anyone can forge proofs of
+// false statements. It is meant for test suites which check the plumbing
+// around the proofs, and must never be used in production.
+func UnsafeSetup(r1cs constraint.ConstraintSystem, seed []byte) (ProvingKey, VerifyingKey, error) {
+	if err := checkR1CS(r1cs); err != nil {
+		return nil, nil, err
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *cs_bls12377.R1CS:
+		var pk groth16_bls12377.ProvingKey
+		var vk groth16_bls12377.VerifyingKey
+		if err := groth16_bls12377.UnsafeSetup(_r1cs, &pk, &vk, seed); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *cs_bls12381.R1CS:
+		var pk groth16_bls12381.ProvingKey
+		var vk groth16_bls12381.VerifyingKey
+		if err := groth16_bls12381.UnsafeSetup(_r1cs, &pk, &vk, seed); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *cs_bn254.R1CS:
+		var pk groth16_bn254.ProvingKey
+		var vk groth16_bn254.VerifyingKey
+		if err := groth16_bn254.UnsafeSetup(_r1cs, &pk, &vk, seed); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *cs_bw6761.R1CS:
+		var pk groth16_bw6761.ProvingKey
+		var vk groth16_bw6761.VerifyingKey
+		if err := groth16_bw6761.UnsafeSetup(_r1cs, &pk, &vk, seed); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *cs_bls24317.R1CS:
+		var pk groth16_bls24317.ProvingKey
+		var vk groth16_bls24317.VerifyingKey
+		if err := groth16_bls24317.UnsafeSetup(_r1cs, &pk, &vk, seed); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *cs_bls24315.R1CS:
+		var pk groth16_bls24315.ProvingKey
+		var vk groth16_bls24315.VerifyingKey
+		if err := groth16_bls24315.UnsafeSetup(_r1cs, &pk, &vk, seed); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *cs_bw6633.R1CS:
+		var pk groth16_bw6633.ProvingKey
+		var vk groth16_bw6633.VerifyingKey
+		if err := groth16_bw6633.UnsafeSetup(_r1cs, &pk, &vk, seed); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	default:
+		return nil, nil, errors.New("unrecognized R1CS curve type")
 	}
 }
 
 // DummySetup create a random ProvingKey with provided R1CS
 // it doesn't return a VerifyingKey and is use for benchmarking or test purposes only.
+// See UnsafeSetup for a fast setup generating proofs which verify.
 func DummySetup(r1cs constraint.ConstraintSystem) (ProvingKey, error) {
+	if err := checkR1CS(r1cs); err != nil {
+		return nil, err
+	}
 	switch _r1cs := r1cs.(type) {
 	case *cs_bls12377.R1CS:
 		var pk groth16_bls12377.ProvingKey
@@ -307,7 +794,7 @@ func DummySetup(r1cs constraint.ConstraintSystem) (ProvingKey, error) {
 		}
 		return &pk, nil
 	default:
-		panic("unrecognized R1CS curve type")
+		return nil, errors.New("unrecognized R1CS curve type")
 	}
 }
 
@@ -336,6 +823,167 @@ func NewProvingKey(curveID ecc.ID) ProvingKey {
 	return pk
 }
 
+// NewStreamingProvingKey reads the small parts of a proving key serialized
+// with WriteTo or WriteRawTo from r, and the location of its slices of points
+// which are read in chunks when proving with [ProveStreaming]. r, typically an
+// *os.File, must remain readable while the key is used.
+func NewStreamingProvingKey(curveID ecc.ID, r io.ReaderAt) (StreamingProvingKey, error) {
+	switch curveID {
+	case ecc.BN254:
+		pk, err := groth16_bn254.NewStreamingProvingKey(r)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS12_377:
+		pk, err := groth16_bls12377.NewStreamingProvingKey(r)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS12_381:
+		pk, err := groth16_bls12381.NewStreamingProvingKey(r)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_761:
+		pk, err := groth16_bw6761.NewStreamingProvingKey(r)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_317:
+		pk, err := groth16_bls24317.NewStreamingProvingKey(r)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_315:
+		pk, err := groth16_bls24315.NewStreamingProvingKey(r)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_633:
+		pk, err := groth16_bw6633.NewStreamingProvingKey(r)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// OpenSectionedProvingKey maps in memory the file at path, a proving key
+// written with WriteSectionsTo, and decodes the small parts of the key. Its
+// sections are decoded when they are loaded or needed by [ProveSectioned].
+// The key must be closed to unmap the file.
+//
+// The file must not be modified while the key is open. On platforms without
+// memory mapping, the file is read in memory.
+func OpenSectionedProvingKey(curveID ecc.ID, path string) (SectionedProvingKey, error) {
+	switch curveID {
+	case ecc.BN254:
+		pk, err := groth16_bn254.OpenSectionedProvingKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS12_377:
+		pk, err := groth16_bls12377.OpenSectionedProvingKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS12_381:
+		pk, err := groth16_bls12381.OpenSectionedProvingKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_761:
+		pk, err := groth16_bw6761.OpenSectionedProvingKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_317:
+		pk, err := groth16_bls24317.OpenSectionedProvingKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_315:
+		pk, err := groth16_bls24315.OpenSectionedProvingKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_633:
+		pk, err := groth16_bw6633.OpenSectionedProvingKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewSectionedProvingKey decodes the small parts of a proving key written with
+// WriteSectionsTo in data. Its sections are decoded when they are loaded or
+// needed by [ProveSectioned]. data must not be modified while the key is used.
+func NewSectionedProvingKey(curveID ecc.ID, data []byte) (SectionedProvingKey, error) {
+	switch curveID {
+	case ecc.BN254:
+		pk, err := groth16_bn254.NewSectionedProvingKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS12_377:
+		pk, err := groth16_bls12377.NewSectionedProvingKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS12_381:
+		pk, err := groth16_bls12381.NewSectionedProvingKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_761:
+		pk, err := groth16_bw6761.NewSectionedProvingKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_317:
+		pk, err := groth16_bls24317.NewSectionedProvingKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BLS24_315:
+		pk, err := groth16_bls24315.NewSectionedProvingKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case ecc.BW6_633:
+		pk, err := groth16_bw6633.NewSectionedProvingKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return pk, nil
+	default:
+		panic("not implemented")
+	}
+}
+
 // NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
 // This function exists for serialization purposes
 func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
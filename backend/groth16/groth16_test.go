@@ -1,18 +1,26 @@
 package groth16_test
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCustomHashToField(t *testing.T) {
@@ -51,6 +59,277 @@ func TestCustomHashToField(t *testing.T) {
 	}
 }
 
+func TestSelfTest(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: 3})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			w, err := frontend.NewWitness(&refCircuit{X: 2, Y: 256}, curve.ScalarField())
+			assert.NoError(err)
+			assert.NoError(groth16.SelfTest(pk, vk, ccs, w))
+
+			// keys from another setup
+			_, otherVk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			assert.Error(groth16.SelfTest(pk, otherVk, ccs, w))
+
+			// keys for another circuit
+			otherCcs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: 40})
+			assert.NoError(err)
+			assert.Error(groth16.SelfTest(pk, vk, otherCcs, w))
+		}, curve.String())
+	}
+
+	assert.Run(func(assert *test.Assert) {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: 3})
+		assert.NoError(err)
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+		otherCcs, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: 3})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&refCircuit{X: 2, Y: 256}, ecc.BLS12_381.ScalarField())
+		assert.NoError(err)
+		err = groth16.SelfTest(pk, vk, otherCcs, w)
+		assert.ErrorIs(err, gnark.ErrCurveMismatch)
+	}, "curve_mismatch")
+
+	assert.Run(func(assert *test.Assert) {
+		// corrupted points in keys of the right sizes
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: 3})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&refCircuit{X: 2, Y: 256}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		for _, corrupt := range []func(pk *groth16_bn254.ProvingKey){
+			// detected by CheckKeys
+			func(pk *groth16_bn254.ProvingKey) { pk.G1.B[0].Add(&pk.G1.B[0], &pk.G1.B[0]) },
+			// detected by the proof round-trip only
+			func(pk *groth16_bn254.ProvingKey) { pk.G1.K[0].Add(&pk.G1.K[0], &pk.G1.K[0]) },
+			func(pk *groth16_bn254.ProvingKey) { pk.G1.Z[0].Add(&pk.G1.Z[0], &pk.G1.Z[0]) },
+		} {
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			corrupt(pk.(*groth16_bn254.ProvingKey))
+			assert.Error(groth16.SelfTest(pk, vk, ccs, w))
+		}
+		pk, vk, err := groth16.Setup(ccs)
+		assert.NoError(err)
+		_pk := pk.(*groth16_bn254.ProvingKey)
+		_pk.G1.B[0].Add(&_pk.G1.B[0], &_pk.G1.B[0])
+		assert.Error(groth16_bn254.CheckKeys(ccs.(*cs_bn254.R1CS), _pk, vk.(*groth16_bn254.VerifyingKey)))
+	}, "corrupted_keys")
+}
+
+func TestUnsafeSetup(t *testing.T) {
+	for _, curve := range getCurves() {
+		for _, circuit := range []frontend.Circuit{&refCircuit{nbConstraints: 3}, &commitmentPublicCircuit{}} {
+			t.Run(fmt.Sprintf("%s/%T", curve, circuit), func(t *testing.T) {
+				assert := require.New(t)
+				ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit)
+				assert.NoError(err)
+
+				pk, vk, err := groth16.UnsafeSetup(ccs, []byte("seed"))
+				assert.NoError(err)
+
+				// same seed, same keys
+				pk2, vk2, err := groth16.UnsafeSetup(ccs, []byte("seed"))
+				assert.NoError(err)
+				var b1, b2 bytes.Buffer
+				_, err = pk.WriteRawTo(&b1)
+				assert.NoError(err)
+				_, err = pk2.WriteRawTo(&b2)
+				assert.NoError(err)
+				assert.Equal(b1.Bytes(), b2.Bytes(), "proving keys differ")
+				b1.Reset()
+				b2.Reset()
+				_, err = vk.WriteRawTo(&b1)
+				assert.NoError(err)
+				_, err = vk2.WriteRawTo(&b2)
+				assert.NoError(err)
+				assert.Equal(b1.Bytes(), b2.Bytes(), "verifying keys differ")
+
+				// other seed, other keys
+				_, vk3, err := groth16.UnsafeSetup(ccs, []byte("other seed"))
+				assert.NoError(err)
+
+				// X = 3, Y = X⁸
+				x := big.NewInt(3)
+				y := new(big.Int).Exp(x, big.NewInt(8), curve.ScalarField())
+				var assignment frontend.Circuit
+				if _, ok := circuit.(*refCircuit); ok {
+					assignment = &refCircuit{X: x, Y: y}
+				} else {
+					assignment = &commitmentPublicCircuit{X: x, Y: y}
+				}
+				fullWitness, err := frontend.NewWitness(assignment, curve.ScalarField())
+				assert.NoError(err)
+				proof, err := groth16.Prove(ccs, pk, fullWitness)
+				assert.NoError(err)
+				publicWitness, err := fullWitness.Public()
+				assert.NoError(err)
+				assert.NoError(groth16.Verify(proof, vk, publicWitness))
+				assert.Error(groth16.Verify(proof, vk3, publicWitness))
+			})
+		}
+	}
+}
+
+func TestProveNoZeroKnowledge(t *testing.T) {
+	for _, curve := range getCurves() {
+		t.Run(curve.String(), func(t *testing.T) {
+			assert := require.New(t)
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &commitmentPublicCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+
+			// X = 3, Y = X⁸
+			x := big.NewInt(3)
+			assignment := &commitmentPublicCircuit{X: x, Y: new(big.Int).Exp(x, big.NewInt(8), curve.ScalarField())}
+			fullWitness, err := frontend.NewWitness(assignment, curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			var encodedPk bytes.Buffer
+			_, err = pk.WriteTo(&encodedPk)
+			assert.NoError(err)
+			spk, err := groth16.NewStreamingProvingKey(curve, bytes.NewReader(encodedPk.Bytes()))
+			assert.NoError(err)
+
+			// the proofs are deterministic
+			var encoded [][]byte
+			for i := 0; i < 2; i++ {
+				for _, prove := range []func() (groth16.Proof, error){
+					func() (groth16.Proof, error) {
+						return groth16.Prove(ccs, pk, fullWitness, backend.WithProverNoZeroKnowledge())
+					},
+					func() (groth16.Proof, error) {
+						return groth16.ProveStreaming(ccs, spk, fullWitness, backend.WithProverNoZeroKnowledge())
+					},
+				} {
+					proof, err := prove()
+					assert.NoError(err)
+					assert.NoError(groth16.Verify(proof, vk, publicWitness))
+					var buf bytes.Buffer
+					_, err = proof.WriteRawTo(&buf)
+					assert.NoError(err)
+					encoded = append(encoded, buf.Bytes())
+				}
+			}
+			for i := 1; i < len(encoded); i++ {
+				assert.Equal(encoded[0], encoded[i])
+			}
+		})
+	}
+}
+
+func TestProveStreaming(t *testing.T) {
+	for _, curve := range getCurves() {
+		for _, circuit := range []frontend.Circuit{&refCircuit{nbConstraints: 5}, &commitmentPublicCircuit{}} {
+			t.Run(fmt.Sprintf("%s/%T", curve, circuit), func(t *testing.T) {
+				assert := require.New(t)
+				ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit)
+				assert.NoError(err)
+				pk, vk, err := groth16.Setup(ccs)
+				assert.NoError(err)
+
+				// X = 3, Y = X³² or X⁸
+				x := big.NewInt(3)
+				var assignment frontend.Circuit
+				if _, ok := circuit.(*refCircuit); ok {
+					assignment = &refCircuit{X: x, Y: new(big.Int).Exp(x, big.NewInt(32), curve.ScalarField())}
+				} else {
+					assignment = &commitmentPublicCircuit{X: x, Y: new(big.Int).Exp(x, big.NewInt(8), curve.ScalarField())}
+				}
+				fullWitness, err := frontend.NewWitness(assignment, curve.ScalarField())
+				assert.NoError(err)
+				publicWitness, err := fullWitness.Public()
+				assert.NoError(err)
+
+				var compressed, raw bytes.Buffer
+				_, err = pk.WriteTo(&compressed)
+				assert.NoError(err)
+				_, err = pk.WriteRawTo(&raw)
+				assert.NoError(err)
+
+				for _, encoded := range [][]byte{compressed.Bytes(), raw.Bytes()} {
+					spk, err := groth16.NewStreamingProvingKey(curve, bytes.NewReader(encoded))
+					assert.NoError(err)
+					assert.Equal(pk.NbG1(), spk.NbG1())
+					assert.Equal(pk.NbG2(), spk.NbG2())
+
+					for _, opts := range [][]backend.ProverOption{nil, {backend.WithProverChunkSize(1)}, {backend.WithProverChunkSize(3)}} {
+						proof, err := groth16.ProveStreaming(ccs, spk, fullWitness, opts...)
+						assert.NoError(err)
+						assert.NoError(groth16.Verify(proof, vk, publicWitness))
+					}
+
+					// truncated key
+					spk, err = groth16.NewStreamingProvingKey(curve, bytes.NewReader(encoded[:len(encoded)/2]))
+					if err == nil {
+						_, err = groth16.ProveStreaming(ccs, spk, fullWitness)
+					}
+					assert.Error(err)
+				}
+			})
+		}
+	}
+}
+
+func TestProveSectioned(t *testing.T) {
+	for _, curve := range getCurves() {
+		t.Run(curve.String(), func(t *testing.T) {
+			assert := require.New(t)
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &commitmentPublicCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+
+			// X = 3, Y = X⁸
+			x := big.NewInt(3)
+			assignment := &commitmentPublicCircuit{X: x, Y: new(big.Int).Exp(x, big.NewInt(8), curve.ScalarField())}
+			fullWitness, err := frontend.NewWitness(assignment, curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			path := filepath.Join(t.TempDir(), "pk")
+			f, err := os.Create(path)
+			assert.NoError(err)
+			_, err = pk.WriteSectionsTo(f)
+			assert.NoError(err)
+			assert.NoError(f.Close())
+
+			spk, err := groth16.OpenSectionedProvingKey(curve, path)
+			assert.NoError(err)
+			defer spk.Close()
+			assert.Equal(pk.NbG1(), spk.NbG1())
+			assert.Equal(pk.NbG2(), spk.NbG2())
+
+			// nothing loaded, some sections loaded, all sections loaded
+			sections := [][]groth16.ProvingKeySection{nil, {groth16.SectionA, groth16.SectionB2}, {groth16.SectionA, groth16.SectionB1, groth16.SectionZ, groth16.SectionK, groth16.SectionB2}}
+			for _, loaded := range sections {
+				assert.NoError(spk.Load(loaded...))
+				for _, opts := range [][]backend.ProverOption{nil, {backend.WithProverChunkSize(2)}} {
+					proof, err := groth16.ProveSectioned(ccs, spk, fullWitness, opts...)
+					assert.NoError(err)
+					assert.NoError(groth16.Verify(proof, vk, publicWitness))
+				}
+				spk.Release(loaded...)
+			}
+
+			// truncated key
+			data, err := os.ReadFile(path)
+			assert.NoError(err)
+			_, err = groth16.NewSectionedProvingKey(curve, data[:len(data)-1])
+			assert.Error(err)
+		})
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -117,6 +396,138 @@ func BenchmarkVerifier(b *testing.B) {
 	}
 }
 
+func TestBatchVerify(t *testing.T) {
+	const nbProofs = 4
+	for _, curve := range getCurves() {
+		for _, circuit := range []frontend.Circuit{&refCircuit{nbConstraints: 3}, &commitmentPublicCircuit{}} {
+			t.Run(fmt.Sprintf("%s/%T", curve, circuit), func(t *testing.T) {
+				assert := require.New(t)
+				ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit)
+				assert.NoError(err)
+				pk, vk, err := groth16.Setup(ccs)
+				assert.NoError(err)
+
+				proofs := make([]groth16.Proof, nbProofs)
+				publicWitnesses := make([]witness.Witness, nbProofs)
+				for i := range proofs {
+					// X = i+2, Y = X⁸
+					x := big.NewInt(int64(i + 2))
+					y := new(big.Int).Exp(x, big.NewInt(8), curve.ScalarField())
+					var assignment frontend.Circuit
+					if _, ok := circuit.(*refCircuit); ok {
+						assignment = &refCircuit{X: x, Y: y}
+					} else {
+						assignment = &commitmentPublicCircuit{X: x, Y: y}
+					}
+					fullWitness, err := frontend.NewWitness(assignment, curve.ScalarField())
+					assert.NoError(err)
+					proofs[i], err = groth16.Prove(ccs, pk, fullWitness)
+					assert.NoError(err)
+					publicWitnesses[i], err = fullWitness.Public()
+					assert.NoError(err)
+				}
+
+				assert.NoError(groth16.BatchVerify(vk, proofs, publicWitnesses))
+				assert.NoError(groth16.BatchVerify(vk, proofs[:1], publicWitnesses[:1]))
+				assert.NoError(groth16.BatchVerify(vk, nil, nil))
+
+				// public witnesses swapped
+				swapped := append([]witness.Witness(nil), publicWitnesses...)
+				swapped[1], swapped[2] = swapped[2], swapped[1]
+				assert.Error(groth16.BatchVerify(vk, proofs, swapped))
+
+				// one invalid proof
+				invalid := append([]groth16.Proof(nil), proofs...)
+				invalid[3] = proofs[0]
+				assert.Error(groth16.BatchVerify(vk, invalid, publicWitnesses))
+
+				// mismatched lengths
+				assert.Error(groth16.BatchVerify(vk, proofs, publicWitnesses[1:]))
+			})
+		}
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	assert := require.New(t)
+	compile := func(curve ecc.ID, newBuilder frontend.NewBuilder, nbConstraints int) constraint.ConstraintSystem {
+		ccs, err := frontend.Compile(curve.ScalarField(), newBuilder, &refCircuit{nbConstraints: nbConstraints})
+		assert.NoError(err)
+		return ccs
+	}
+	ccs := compile(ecc.BN254, r1cs.NewBuilder, 3)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&refCircuit{X: 2, Y: 256}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	// keys for another circuit
+	otherPk, _, err := groth16.Setup(compile(ecc.BN254, r1cs.NewBuilder, 40))
+	assert.NoError(err)
+	_, err = groth16.Prove(ccs, otherPk, w)
+	assert.ErrorIs(err, gnark.ErrKeyMismatch)
+
+	// keys on another curve
+	blsPk, blsVk, err := groth16.Setup(compile(ecc.BLS12_381, r1cs.NewBuilder, 3))
+	assert.NoError(err)
+	_, err = groth16.Prove(ccs, blsPk, w)
+	assert.ErrorIs(err, gnark.ErrCurveMismatch)
+	assert.ErrorIs(groth16.Verify(proof, blsVk, pw), gnark.ErrCurveMismatch)
+	assert.ErrorIs(groth16.BatchVerify(blsVk, []groth16.Proof{proof}, []witness.Witness{pw}), gnark.ErrCurveMismatch)
+	_, err = groth16.Prove(ccs, nil, w)
+	assert.ErrorIs(err, gnark.ErrCurveMismatch)
+
+	// constraint system compiled for PLONK
+	sparse := compile(ecc.BN254, scs.NewBuilder, 3)
+	_, _, err = groth16.Setup(sparse)
+	assert.Error(err)
+	_, _, err = groth16.UnsafeSetup(sparse, nil)
+	assert.Error(err)
+	_, err = groth16.DummySetup(sparse)
+	assert.Error(err)
+	_, err = groth16.Prove(sparse, pk, w)
+	assert.Error(err)
+
+	// no constraint system
+	_, _, err = groth16.Setup(nil)
+	assert.Error(err)
+	assert.Error(groth16.Verify(nil, vk, pw))
+}
+
+func TestPanicRecovery(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: 3})
+	assert.NoError(err)
+	pk, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&refCircuit{X: 2, Y: 256}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	// keys of the right type but nil
+	nilPk, nilVk := (*groth16_bn254.ProvingKey)(nil), (*groth16_bn254.VerifyingKey)(nil)
+	assert.Panics(func() { _, _ = groth16.Prove(ccs, nilPk, w) })
+	_, err = groth16.Prove(ccs, nilPk, w, backend.WithProverPanicRecovery())
+	assert.ErrorIs(err, gnark.ErrPanic)
+	assert.Panics(func() { _ = groth16.Verify(proof, nilVk, pw) })
+	assert.ErrorIs(groth16.Verify(proof, nilVk, pw, backend.WithVerifierPanicRecovery()), gnark.ErrPanic)
+
+	// corrupted constraint system
+	corrupted, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: 3})
+	assert.NoError(err)
+	corrupted.(*cs_bn254.R1CS).Blueprints = nil
+	assert.Panics(func() { _, _, _ = groth16.Setup(corrupted) })
+	_, _, err = groth16.Setup(corrupted, backend.WithSetupPanicRecovery())
+	assert.ErrorIs(err, gnark.ErrPanic)
+}
+
 type refCircuit struct {
 	nbConstraints int
 	X             frontend.Variable
@@ -168,6 +579,24 @@ func (c *commitmentCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+// commitmentPublicCircuit commits to a secret and a public variable
+type commitmentPublicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *commitmentPublicCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	api.AssertIsDifferent(cmt, 0)
+	x2 := api.Mul(c.X, c.X)
+	x4 := api.Mul(x2, x2)
+	api.AssertIsEqual(api.Mul(x4, x4), c.Y)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
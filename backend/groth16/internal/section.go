@@ -0,0 +1,33 @@
+package internal
+
+import "fmt"
+
+// Section identifies a slice of points of a Groth16 proving key, stored
+// independently in the sectioned proving key format.
+type Section uint8
+
+const (
+	SectionA Section = iota
+	SectionB1
+	SectionZ
+	SectionK
+	SectionB2
+	NbSections
+)
+
+func (s Section) String() string {
+	switch s {
+	case SectionA:
+		return "A"
+	case SectionB1:
+		return "B1"
+	case SectionZ:
+		return "Z"
+	case SectionK:
+		return "K"
+	case SectionB2:
+		return "B2"
+	default:
+		return fmt.Sprintf("Section(%d)", uint8(s))
+	}
+}
@@ -0,0 +1,567 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mpcsetup implements the multi-party computation ceremony generating
+// the Groth16 proving and verifying keys of a circuit, so that no single party
+// knows the toxic waste of the setup.
+//
+// The ceremony has two phases, described in https://eprint.iacr.org/2017/1050.pdf:
+//   - Phase 1 ("Powers of Tau") is independent of the circuit, and only depends
+//     on its size. It is started with [InitPhase1].
+//   - Phase 2 is specific to a circuit. It is started from the result of
+//     Phase 1 with [InitPhase2].
+//
+// In each phase, the coordinator sends the current state to a participant, who
+// deserializes it, calls Contribute and sends it back. The coordinator checks
+// the contribution with [VerifyPhase1] or [VerifyPhase2] before forwarding it
+// to the next participant. Each phase is finalized with a contribution derived
+// from a public random beacon (see [beacon.Beacon]), checked with
+// [VerifyPhase1Beacon] or [VerifyPhase2Beacon]. Finally, [ExtractKeys] returns
+// the keys to use with the groth16 package.
+//
+// Instead of running Phase 1, the result of a public ceremony can be imported
+// with [ReadPtau], which reads the files of the Perpetual Powers of Tau. The
+// powers of τ of a Phase 1 can also be used as the KZG SRS of PLONK, see
+// [KZGSRS]. The transcripts of the Aztec Ignition ceremony, on BN254, are read
+// by the ReadIgnition function of the BN254 package.
+//
+// The objects of this package are curve-typed, their underlying implementation
+// is in the mpcsetup package of each curve.
+package mpcsetup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/beacon"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+
+	mpcsetup_bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377/mpcsetup"
+	mpcsetup_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381/mpcsetup"
+	mpcsetup_bls24315 "github.com/consensys/gnark/backend/groth16/bls24-315/mpcsetup"
+	mpcsetup_bls24317 "github.com/consensys/gnark/backend/groth16/bls24-317/mpcsetup"
+	mpcsetup_bn254 "github.com/consensys/gnark/backend/groth16/bn254/mpcsetup"
+	mpcsetup_bw6633 "github.com/consensys/gnark/backend/groth16/bw6-633/mpcsetup"
+	mpcsetup_bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761/mpcsetup"
+)
+
+// Phase1 represents the state of the Phase 1 of the ceremony, after a number of
+// contributions.
+type Phase1 interface {
+	io.WriterTo
+	io.ReaderFrom
+
+	// Contribute adds a random contribution to the state.
+	Contribute()
+
+	// ContributeFromBeacon adds a contribution derived from a random beacon,
+	// which finalizes the phase.
+	ContributeFromBeacon(b *beacon.Beacon)
+}
+
+// Phase2 represents the state of the Phase 2 of the ceremony, after a number of
+// contributions.
+type Phase2 interface {
+	io.WriterTo
+	io.ReaderFrom
+
+	// Contribute adds a random contribution to the state.
+	Contribute()
+
+	// ContributeFromBeacon adds a contribution derived from a random beacon,
+	// which finalizes the phase.
+	ContributeFromBeacon(b *beacon.Beacon)
+}
+
+// Phase2Evaluations stores the parts of the keys which are computed by the
+// coordinator at the beginning of Phase 2, and do not depend on the
+// contributions.
+type Phase2Evaluations interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+var errCurveMismatch = errors.New("the objects are not defined on the same curve")
+
+// InitPhase1 returns the initial state of the Phase 1 of the ceremony, for
+// circuits of up to 2ᵖᵒʷᵉʳ constraints.
+func InitPhase1(curveID ecc.ID, power int) (Phase1, error) {
+	switch curveID {
+	case ecc.BN254:
+		srs1 := mpcsetup_bn254.InitPhase1(power)
+		return &srs1, nil
+	case ecc.BLS12_377:
+		srs1 := mpcsetup_bls12377.InitPhase1(power)
+		return &srs1, nil
+	case ecc.BLS12_381:
+		srs1 := mpcsetup_bls12381.InitPhase1(power)
+		return &srs1, nil
+	case ecc.BW6_761:
+		srs1 := mpcsetup_bw6761.InitPhase1(power)
+		return &srs1, nil
+	case ecc.BLS24_317:
+		srs1 := mpcsetup_bls24317.InitPhase1(power)
+		return &srs1, nil
+	case ecc.BLS24_315:
+		srs1 := mpcsetup_bls24315.InitPhase1(power)
+		return &srs1, nil
+	case ecc.BW6_633:
+		srs1 := mpcsetup_bw6633.InitPhase1(power)
+		return &srs1, nil
+	default:
+		return nil, errors.New("unsupported curve")
+	}
+}
+
+// NewPhase1 instantiates a curve-typed Phase1 and returns an interface object.
+// This function exists for serialization purposes.
+func NewPhase1(curveID ecc.ID) Phase1 {
+	var srs1 Phase1
+	switch curveID {
+	case ecc.BN254:
+		srs1 = &mpcsetup_bn254.Phase1{}
+	case ecc.BLS12_377:
+		srs1 = &mpcsetup_bls12377.Phase1{}
+	case ecc.BLS12_381:
+		srs1 = &mpcsetup_bls12381.Phase1{}
+	case ecc.BW6_761:
+		srs1 = &mpcsetup_bw6761.Phase1{}
+	case ecc.BLS24_317:
+		srs1 = &mpcsetup_bls24317.Phase1{}
+	case ecc.BLS24_315:
+		srs1 = &mpcsetup_bls24315.Phase1{}
+	case ecc.BW6_633:
+		srs1 = &mpcsetup_bw6633.Phase1{}
+	default:
+		panic("not implemented")
+	}
+	return srs1
+}
+
+// VerifyPhase1 checks that each contribution is a valid contribution on top of
+// the previous one.
+func VerifyPhase1(c0, c1 Phase1, c ...Phase1) error {
+	contribs := append([]Phase1{c0, c1}, c...)
+	switch c0.(type) {
+	case *mpcsetup_bn254.Phase1:
+		return verifyContributions(contribs, mpcsetup_bn254.VerifyPhase1)
+	case *mpcsetup_bls12377.Phase1:
+		return verifyContributions(contribs, mpcsetup_bls12377.VerifyPhase1)
+	case *mpcsetup_bls12381.Phase1:
+		return verifyContributions(contribs, mpcsetup_bls12381.VerifyPhase1)
+	case *mpcsetup_bw6761.Phase1:
+		return verifyContributions(contribs, mpcsetup_bw6761.VerifyPhase1)
+	case *mpcsetup_bls24317.Phase1:
+		return verifyContributions(contribs, mpcsetup_bls24317.VerifyPhase1)
+	case *mpcsetup_bls24315.Phase1:
+		return verifyContributions(contribs, mpcsetup_bls24315.VerifyPhase1)
+	case *mpcsetup_bw6633.Phase1:
+		return verifyContributions(contribs, mpcsetup_bw6633.VerifyPhase1)
+	default:
+		panic("unrecognized Phase1 curve type")
+	}
+}
+
+// VerifyPhase1Beacon checks that contribution is the contribution derived from
+// the beacon round b on top of current. The beacon itself must be verified with
+// [beacon.Beacon.Verify].
+func VerifyPhase1Beacon(current, contribution Phase1, b *beacon.Beacon) error {
+	switch current.(type) {
+	case *mpcsetup_bn254.Phase1:
+		return verifyBeacon(current, contribution, b, mpcsetup_bn254.VerifyPhase1Beacon)
+	case *mpcsetup_bls12377.Phase1:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls12377.VerifyPhase1Beacon)
+	case *mpcsetup_bls12381.Phase1:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls12381.VerifyPhase1Beacon)
+	case *mpcsetup_bw6761.Phase1:
+		return verifyBeacon(current, contribution, b, mpcsetup_bw6761.VerifyPhase1Beacon)
+	case *mpcsetup_bls24317.Phase1:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls24317.VerifyPhase1Beacon)
+	case *mpcsetup_bls24315.Phase1:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls24315.VerifyPhase1Beacon)
+	case *mpcsetup_bw6633.Phase1:
+		return verifyBeacon(current, contribution, b, mpcsetup_bw6633.VerifyPhase1Beacon)
+	default:
+		panic("unrecognized Phase1 curve type")
+	}
+}
+
+// ReadPtau reads a Powers of Tau transcript in the snarkjs .ptau format, such
+// as the files of the Perpetual Powers of Tau ceremony, and returns it as a
+// Phase 1 for circuits of up to 2ᵖᵒʷᵉʳ constraints. Only BN254 and BLS12-381
+// are supported by the format.
+func ReadPtau(curveID ecc.ID, r io.Reader, power int) (Phase1, error) {
+	switch curveID {
+	case ecc.BN254:
+		srs1, err := mpcsetup_bn254.ReadPtau(r, power)
+		if err != nil {
+			return nil, err
+		}
+		return srs1, nil
+	case ecc.BLS12_381:
+		srs1, err := mpcsetup_bls12381.ReadPtau(r, power)
+		if err != nil {
+			return nil, err
+		}
+		return srs1, nil
+	default:
+		return nil, errors.New("unsupported curve")
+	}
+}
+
+// KZGSRS returns a KZG structured reference string of the given size, made of
+// the powers of τ of srs1, to be used with PLONK.
+func KZGSRS(srs1 Phase1, size uint64) (kzg.SRS, error) {
+	switch _srs1 := srs1.(type) {
+	case *mpcsetup_bn254.Phase1:
+		srs, err := _srs1.KZGSRS(size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case *mpcsetup_bls12377.Phase1:
+		srs, err := _srs1.KZGSRS(size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case *mpcsetup_bls12381.Phase1:
+		srs, err := _srs1.KZGSRS(size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case *mpcsetup_bw6761.Phase1:
+		srs, err := _srs1.KZGSRS(size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case *mpcsetup_bls24317.Phase1:
+		srs, err := _srs1.KZGSRS(size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case *mpcsetup_bls24315.Phase1:
+		srs, err := _srs1.KZGSRS(size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case *mpcsetup_bw6633.Phase1:
+		srs, err := _srs1.KZGSRS(size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	default:
+		panic("unrecognized Phase1 curve type")
+	}
+}
+
+// InitPhase2 returns the initial state of the Phase 2 of the ceremony for the
+// circuit r1cs, from the final state of the Phase 1, and the evaluations which
+// complete the keys.
+//
+// The size of the Phase 1 must be the size of the domain of the circuit, that
+// is the number of constraints rounded up to a power of 2.
+func InitPhase2(r1cs constraint.ConstraintSystem, srs1 Phase1) (Phase2, Phase2Evaluations, error) {
+	if len(r1cs.GetCommitments().CommitmentIndexes()) != 0 {
+		return nil, nil, errors.New("commitments are not supported by the MPC setup")
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *cs_bn254.R1CS:
+		_srs1, ok := srs1.(*mpcsetup_bn254.Phase1)
+		if !ok {
+			return nil, nil, errCurveMismatch
+		}
+		if err := checkDomain(r1cs, len(_srs1.Parameters.G1.AlphaTau)); err != nil {
+			return nil, nil, err
+		}
+		srs2, evals := mpcsetup_bn254.InitPhase2(_r1cs, _srs1)
+		return &srs2, &evals, nil
+	case *cs_bls12377.R1CS:
+		_srs1, ok := srs1.(*mpcsetup_bls12377.Phase1)
+		if !ok {
+			return nil, nil, errCurveMismatch
+		}
+		if err := checkDomain(r1cs, len(_srs1.Parameters.G1.AlphaTau)); err != nil {
+			return nil, nil, err
+		}
+		srs2, evals := mpcsetup_bls12377.InitPhase2(_r1cs, _srs1)
+		return &srs2, &evals, nil
+	case *cs_bls12381.R1CS:
+		_srs1, ok := srs1.(*mpcsetup_bls12381.Phase1)
+		if !ok {
+			return nil, nil, errCurveMismatch
+		}
+		if err := checkDomain(r1cs, len(_srs1.Parameters.G1.AlphaTau)); err != nil {
+			return nil, nil, err
+		}
+		srs2, evals := mpcsetup_bls12381.InitPhase2(_r1cs, _srs1)
+		return &srs2, &evals, nil
+	case *cs_bw6761.R1CS:
+		_srs1, ok := srs1.(*mpcsetup_bw6761.Phase1)
+		if !ok {
+			return nil, nil, errCurveMismatch
+		}
+		if err := checkDomain(r1cs, len(_srs1.Parameters.G1.AlphaTau)); err != nil {
+			return nil, nil, err
+		}
+		srs2, evals := mpcsetup_bw6761.InitPhase2(_r1cs, _srs1)
+		return &srs2, &evals, nil
+	case *cs_bls24317.R1CS:
+		_srs1, ok := srs1.(*mpcsetup_bls24317.Phase1)
+		if !ok {
+			return nil, nil, errCurveMismatch
+		}
+		if err := checkDomain(r1cs, len(_srs1.Parameters.G1.AlphaTau)); err != nil {
+			return nil, nil, err
+		}
+		srs2, evals := mpcsetup_bls24317.InitPhase2(_r1cs, _srs1)
+		return &srs2, &evals, nil
+	case *cs_bls24315.R1CS:
+		_srs1, ok := srs1.(*mpcsetup_bls24315.Phase1)
+		if !ok {
+			return nil, nil, errCurveMismatch
+		}
+		if err := checkDomain(r1cs, len(_srs1.Parameters.G1.AlphaTau)); err != nil {
+			return nil, nil, err
+		}
+		srs2, evals := mpcsetup_bls24315.InitPhase2(_r1cs, _srs1)
+		return &srs2, &evals, nil
+	case *cs_bw6633.R1CS:
+		_srs1, ok := srs1.(*mpcsetup_bw6633.Phase1)
+		if !ok {
+			return nil, nil, errCurveMismatch
+		}
+		if err := checkDomain(r1cs, len(_srs1.Parameters.G1.AlphaTau)); err != nil {
+			return nil, nil, err
+		}
+		srs2, evals := mpcsetup_bw6633.InitPhase2(_r1cs, _srs1)
+		return &srs2, &evals, nil
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// NewPhase2 instantiates a curve-typed Phase2 and returns an interface object.
+// This function exists for serialization purposes.
+func NewPhase2(curveID ecc.ID) Phase2 {
+	var srs2 Phase2
+	switch curveID {
+	case ecc.BN254:
+		srs2 = &mpcsetup_bn254.Phase2{}
+	case ecc.BLS12_377:
+		srs2 = &mpcsetup_bls12377.Phase2{}
+	case ecc.BLS12_381:
+		srs2 = &mpcsetup_bls12381.Phase2{}
+	case ecc.BW6_761:
+		srs2 = &mpcsetup_bw6761.Phase2{}
+	case ecc.BLS24_317:
+		srs2 = &mpcsetup_bls24317.Phase2{}
+	case ecc.BLS24_315:
+		srs2 = &mpcsetup_bls24315.Phase2{}
+	case ecc.BW6_633:
+		srs2 = &mpcsetup_bw6633.Phase2{}
+	default:
+		panic("not implemented")
+	}
+	return srs2
+}
+
+// NewPhase2Evaluations instantiates curve-typed Phase2Evaluations and returns an
+// interface object. This function exists for serialization purposes.
+func NewPhase2Evaluations(curveID ecc.ID) Phase2Evaluations {
+	var evals Phase2Evaluations
+	switch curveID {
+	case ecc.BN254:
+		evals = &mpcsetup_bn254.Phase2Evaluations{}
+	case ecc.BLS12_377:
+		evals = &mpcsetup_bls12377.Phase2Evaluations{}
+	case ecc.BLS12_381:
+		evals = &mpcsetup_bls12381.Phase2Evaluations{}
+	case ecc.BW6_761:
+		evals = &mpcsetup_bw6761.Phase2Evaluations{}
+	case ecc.BLS24_317:
+		evals = &mpcsetup_bls24317.Phase2Evaluations{}
+	case ecc.BLS24_315:
+		evals = &mpcsetup_bls24315.Phase2Evaluations{}
+	case ecc.BW6_633:
+		evals = &mpcsetup_bw6633.Phase2Evaluations{}
+	default:
+		panic("not implemented")
+	}
+	return evals
+}
+
+// VerifyPhase2 checks that each contribution is a valid contribution on top of
+// the previous one.
+func VerifyPhase2(c0, c1 Phase2, c ...Phase2) error {
+	contribs := append([]Phase2{c0, c1}, c...)
+	switch c0.(type) {
+	case *mpcsetup_bn254.Phase2:
+		return verifyContributions(contribs, mpcsetup_bn254.VerifyPhase2)
+	case *mpcsetup_bls12377.Phase2:
+		return verifyContributions(contribs, mpcsetup_bls12377.VerifyPhase2)
+	case *mpcsetup_bls12381.Phase2:
+		return verifyContributions(contribs, mpcsetup_bls12381.VerifyPhase2)
+	case *mpcsetup_bw6761.Phase2:
+		return verifyContributions(contribs, mpcsetup_bw6761.VerifyPhase2)
+	case *mpcsetup_bls24317.Phase2:
+		return verifyContributions(contribs, mpcsetup_bls24317.VerifyPhase2)
+	case *mpcsetup_bls24315.Phase2:
+		return verifyContributions(contribs, mpcsetup_bls24315.VerifyPhase2)
+	case *mpcsetup_bw6633.Phase2:
+		return verifyContributions(contribs, mpcsetup_bw6633.VerifyPhase2)
+	default:
+		panic("unrecognized Phase2 curve type")
+	}
+}
+
+// VerifyPhase2Beacon checks that contribution is the contribution derived from
+// the beacon round b on top of current. The beacon itself must be verified with
+// [beacon.Beacon.Verify].
+func VerifyPhase2Beacon(current, contribution Phase2, b *beacon.Beacon) error {
+	switch current.(type) {
+	case *mpcsetup_bn254.Phase2:
+		return verifyBeacon(current, contribution, b, mpcsetup_bn254.VerifyPhase2Beacon)
+	case *mpcsetup_bls12377.Phase2:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls12377.VerifyPhase2Beacon)
+	case *mpcsetup_bls12381.Phase2:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls12381.VerifyPhase2Beacon)
+	case *mpcsetup_bw6761.Phase2:
+		return verifyBeacon(current, contribution, b, mpcsetup_bw6761.VerifyPhase2Beacon)
+	case *mpcsetup_bls24317.Phase2:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls24317.VerifyPhase2Beacon)
+	case *mpcsetup_bls24315.Phase2:
+		return verifyBeacon(current, contribution, b, mpcsetup_bls24315.VerifyPhase2Beacon)
+	case *mpcsetup_bw6633.Phase2:
+		return verifyBeacon(current, contribution, b, mpcsetup_bw6633.VerifyPhase2Beacon)
+	default:
+		panic("unrecognized Phase2 curve type")
+	}
+}
+
+// ExtractKeys returns the Groth16 proving and verifying keys of the circuit
+// from the final states of both phases, nConstraints being the number of
+// constraints of the circuit.
+func ExtractKeys(srs1 Phase1, srs2 Phase2, evals Phase2Evaluations, nConstraints int) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	switch _srs1 := srs1.(type) {
+	case *mpcsetup_bn254.Phase1:
+		_srs2, ok := srs2.(*mpcsetup_bn254.Phase2)
+		_evals, ok2 := evals.(*mpcsetup_bn254.Phase2Evaluations)
+		if !ok || !ok2 {
+			return nil, nil, errCurveMismatch
+		}
+		pk, vk := mpcsetup_bn254.ExtractKeys(_srs1, _srs2, _evals, nConstraints)
+		return &pk, &vk, nil
+	case *mpcsetup_bls12377.Phase1:
+		_srs2, ok := srs2.(*mpcsetup_bls12377.Phase2)
+		_evals, ok2 := evals.(*mpcsetup_bls12377.Phase2Evaluations)
+		if !ok || !ok2 {
+			return nil, nil, errCurveMismatch
+		}
+		pk, vk := mpcsetup_bls12377.ExtractKeys(_srs1, _srs2, _evals, nConstraints)
+		return &pk, &vk, nil
+	case *mpcsetup_bls12381.Phase1:
+		_srs2, ok := srs2.(*mpcsetup_bls12381.Phase2)
+		_evals, ok2 := evals.(*mpcsetup_bls12381.Phase2Evaluations)
+		if !ok || !ok2 {
+			return nil, nil, errCurveMismatch
+		}
+		pk, vk := mpcsetup_bls12381.ExtractKeys(_srs1, _srs2, _evals, nConstraints)
+		return &pk, &vk, nil
+	case *mpcsetup_bw6761.Phase1:
+		_srs2, ok := srs2.(*mpcsetup_bw6761.Phase2)
+		_evals, ok2 := evals.(*mpcsetup_bw6761.Phase2Evaluations)
+		if !ok || !ok2 {
+			return nil, nil, errCurveMismatch
+		}
+		pk, vk := mpcsetup_bw6761.ExtractKeys(_srs1, _srs2, _evals, nConstraints)
+		return &pk, &vk, nil
+	case *mpcsetup_bls24317.Phase1:
+		_srs2, ok := srs2.(*mpcsetup_bls24317.Phase2)
+		_evals, ok2 := evals.(*mpcsetup_bls24317.Phase2Evaluations)
+		if !ok || !ok2 {
+			return nil, nil, errCurveMismatch
+		}
+		pk, vk := mpcsetup_bls24317.ExtractKeys(_srs1, _srs2, _evals, nConstraints)
+		return &pk, &vk, nil
+	case *mpcsetup_bls24315.Phase1:
+		_srs2, ok := srs2.(*mpcsetup_bls24315.Phase2)
+		_evals, ok2 := evals.(*mpcsetup_bls24315.Phase2Evaluations)
+		if !ok || !ok2 {
+			return nil, nil, errCurveMismatch
+		}
+		pk, vk := mpcsetup_bls24315.ExtractKeys(_srs1, _srs2, _evals, nConstraints)
+		return &pk, &vk, nil
+	case *mpcsetup_bw6633.Phase1:
+		_srs2, ok := srs2.(*mpcsetup_bw6633.Phase2)
+		_evals, ok2 := evals.(*mpcsetup_bw6633.Phase2Evaluations)
+		if !ok || !ok2 {
+			return nil, nil, errCurveMismatch
+		}
+		pk, vk := mpcsetup_bw6633.ExtractKeys(_srs1, _srs2, _evals, nConstraints)
+		return &pk, &vk, nil
+	default:
+		panic("unrecognized Phase1 curve type")
+	}
+}
+
+// checkDomain returns an error if the Phase 1 of size n can not be used for
+// r1cs.
+func checkDomain(r1cs constraint.ConstraintSystem, n int) error {
+	if expected := ecc.NextPowerOfTwo(uint64(r1cs.GetNbConstraints())); uint64(n) != expected {
+		return fmt.Errorf("the Phase 1 has size %d, the circuit requires %d", n, expected)
+	}
+	return nil
+}
+
+// verifyContributions casts the contributions to their curve type T and
+// verifies them.
+func verifyContributions[T, P any](contribs []P, verify func(T, T, ...T) error) error {
+	typed := make([]T, len(contribs))
+	for i := range contribs {
+		var ok bool
+		if typed[i], ok = any(contribs[i]).(T); !ok {
+			return errCurveMismatch
+		}
+	}
+	return verify(typed[0], typed[1], typed[2:]...)
+}
+
+// verifyBeacon casts the contributions to their curve type T and verifies that
+// contribution is derived from the beacon.
+func verifyBeacon[T, P any](current, contribution P, b *beacon.Beacon, verify func(T, T, *beacon.Beacon) error) error {
+	_current, ok := any(current).(T)
+	_contribution, ok2 := any(contribution).(T)
+	if !ok || !ok2 {
+		return errCurveMismatch
+	}
+	return verify(_current, _contribution, b)
+}
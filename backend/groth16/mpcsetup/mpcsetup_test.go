@@ -0,0 +1,147 @@
+package mpcsetup_test
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"math/bits"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/beacon"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/groth16/mpcsetup"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type circuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	x := c.X
+	for i := 0; i < 10; i++ {
+		x = api.Mul(x, x)
+	}
+	api.AssertIsEqual(x, c.Y)
+	return nil
+}
+
+// roundTrip serializes src and deserializes it into dst, as participants of a
+// ceremony exchange the states.
+func roundTrip[T io.ReaderFrom](assert *require.Assertions, src io.WriterTo, dst T) T {
+	var buf bytes.Buffer
+	_, err := src.WriteTo(&buf)
+	assert.NoError(err)
+	_, err = dst.ReadFrom(&buf)
+	assert.NoError(err)
+	return dst
+}
+
+func TestCeremony(t *testing.T) {
+	for _, curveID := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_317, ecc.BLS24_315, ecc.BW6_633} {
+		t.Run(curveID.String(), func(t *testing.T) {
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(curveID.ScalarField(), r1cs.NewBuilder, &circuit{})
+			assert.NoError(err)
+			power := bits.Len(uint(ccs.GetNbConstraints() - 1))
+
+			// phase 1
+			srs1, err := mpcsetup.InitPhase1(curveID, power)
+			assert.NoError(err)
+			for i := 0; i < 2; i++ {
+				next := roundTrip(assert, srs1, mpcsetup.NewPhase1(curveID))
+				next.Contribute()
+				next = roundTrip(assert, next, mpcsetup.NewPhase1(curveID))
+				assert.NoError(mpcsetup.VerifyPhase1(srs1, next))
+				srs1 = next
+			}
+			b1 := &beacon.Beacon{Round: 1, Randomness: []byte("round 1")}
+			final1 := roundTrip(assert, srs1, mpcsetup.NewPhase1(curveID))
+			final1.ContributeFromBeacon(b1)
+			assert.NoError(mpcsetup.VerifyPhase1Beacon(srs1, final1, b1))
+
+			// phase 2
+			srs2, evals, err := mpcsetup.InitPhase2(ccs, final1)
+			assert.NoError(err)
+			evals = roundTrip(assert, evals, mpcsetup.NewPhase2Evaluations(curveID))
+			for i := 0; i < 2; i++ {
+				next := roundTrip(assert, srs2, mpcsetup.NewPhase2(curveID))
+				next.Contribute()
+				assert.NoError(mpcsetup.VerifyPhase2(srs2, next))
+				srs2 = next
+			}
+			b2 := &beacon.Beacon{Round: 2, Randomness: []byte("round 2")}
+			final2 := roundTrip(assert, srs2, mpcsetup.NewPhase2(curveID))
+			final2.ContributeFromBeacon(b2)
+			assert.NoError(mpcsetup.VerifyPhase2Beacon(srs2, final2, b2))
+			assert.Error(mpcsetup.VerifyPhase2Beacon(srs2, final2, b1))
+
+			// the keys prove and verify
+			pk, vk, err := mpcsetup.ExtractKeys(final1, final2, evals, ccs.GetNbConstraints())
+			assert.NoError(err)
+			y := new(big.Int).Exp(big.NewInt(3), big.NewInt(1<<10), curveID.ScalarField())
+			w, err := frontend.NewWitness(&circuit{X: 3, Y: y}, curveID.ScalarField())
+			assert.NoError(err)
+			pw, err := w.Public()
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs, pk, w)
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, vk, pw))
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit{})
+	assert.NoError(err)
+
+	// the Phase 1 must match the size of the circuit
+	srs1, err := mpcsetup.InitPhase1(ecc.BN254, 8)
+	assert.NoError(err)
+	_, _, err = mpcsetup.InitPhase2(ccs, srs1)
+	assert.Error(err)
+
+	// the objects must be on the same curve
+	other, err := mpcsetup.InitPhase1(ecc.BLS12_381, 4)
+	assert.NoError(err)
+	_, _, err = mpcsetup.InitPhase2(ccs, other)
+	assert.Error(err)
+	srs1, err = mpcsetup.InitPhase1(ecc.BN254, 4)
+	assert.NoError(err)
+	assert.Error(mpcsetup.VerifyPhase1(srs1, other))
+}
+
+func TestKZGSRS(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit{})
+	assert.NoError(err)
+	size := ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints()+ccs.GetNbPublicVariables())) + 3
+
+	// the Phase 1 has 2ᵖᵒʷᵉʳ⁺¹ - 1 powers of τ in G₁
+	srs1, err := mpcsetup.InitPhase1(ecc.BN254, bits.Len64(size))
+	assert.NoError(err)
+	srs1.Contribute()
+	srs, err := mpcsetup.KZGSRS(srs1, size)
+	assert.NoError(err)
+
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	y := new(big.Int).Exp(big.NewInt(3), big.NewInt(1<<10), ecc.BN254.ScalarField())
+	w, err := frontend.NewWitness(&circuit{X: 3, Y: y}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, pw))
+}
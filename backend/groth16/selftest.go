@@ -0,0 +1,100 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	"github.com/consensys/gnark/internal/utils"
+
+	groth16_bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	groth16_bls24315 "github.com/consensys/gnark/backend/groth16/bls24-315"
+	groth16_bls24317 "github.com/consensys/gnark/backend/groth16/bls24-317"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	groth16_bw6633 "github.com/consensys/gnark/backend/groth16/bw6-633"
+	groth16_bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761"
+)
+
+// SelfTest checks that pk, vk and ccs can be used together and that the
+// Groth16 implementation for their curve works on this machine. It is meant
+// to be called at service startup, to catch corrupted key files, mismatched
+// artifacts or miscompiled binaries before serving proofs.
+//
+// It performs the following checks:
+//   - pk, vk and ccs are defined over the same curve;
+//   - pk and vk come from the same setup and their sizes match ccs (see
+//     CheckKeys in the curve-typed packages);
+//   - fullWitness, a valid assignment of ccs (for example a fixture shipped
+//     with the keys), is proved with pk and the proof is verified with vk.
+//
+// The proof round-trip exercises every element of pk, so that a key with the
+// right sizes but corrupted points fails.
+func SelfTest(pk ProvingKey, vk VerifyingKey, ccs constraint.ConstraintSystem, fullWitness witness.Witness) error {
+	curveID := utils.FieldToCurve(ccs.Field())
+	if pk.CurveID() != curveID || vk.CurveID() != curveID {
+		return fmt.Errorf("self-test: %w: constraint system is defined over %s, proving key over %s and verifying key over %s",
+			gnark.ErrCurveMismatch, curveID, pk.CurveID(), vk.CurveID())
+	}
+
+	if err := checkKeys(pk, vk, ccs); err != nil {
+		return fmt.Errorf("self-test: %w", err)
+	}
+
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		return fmt.Errorf("self-test: public witness: %w", err)
+	}
+	proof, err := Prove(ccs, pk, fullWitness)
+	if err != nil {
+		return fmt.Errorf("self-test: prove: %w", err)
+	}
+	if err := Verify(proof, vk, publicWitness); err != nil {
+		return fmt.Errorf("self-test: verify: %w", err)
+	}
+	return nil
+}
+
+func checkKeys(pk ProvingKey, vk VerifyingKey, ccs constraint.ConstraintSystem) error {
+	switch _r1cs := ccs.(type) {
+	case *cs_bls12377.R1CS:
+		return groth16_bls12377.CheckKeys(_r1cs, pk.(*groth16_bls12377.ProvingKey), vk.(*groth16_bls12377.VerifyingKey))
+	case *cs_bls12381.R1CS:
+		return groth16_bls12381.CheckKeys(_r1cs, pk.(*groth16_bls12381.ProvingKey), vk.(*groth16_bls12381.VerifyingKey))
+	case *cs_bn254.R1CS:
+		return groth16_bn254.CheckKeys(_r1cs, pk.(*groth16_bn254.ProvingKey), vk.(*groth16_bn254.VerifyingKey))
+	case *cs_bw6761.R1CS:
+		return groth16_bw6761.CheckKeys(_r1cs, pk.(*groth16_bw6761.ProvingKey), vk.(*groth16_bw6761.VerifyingKey))
+	case *cs_bls24317.R1CS:
+		return groth16_bls24317.CheckKeys(_r1cs, pk.(*groth16_bls24317.ProvingKey), vk.(*groth16_bls24317.VerifyingKey))
+	case *cs_bls24315.R1CS:
+		return groth16_bls24315.CheckKeys(_r1cs, pk.(*groth16_bls24315.ProvingKey), vk.(*groth16_bls24315.VerifyingKey))
+	case *cs_bw6633.R1CS:
+		return groth16_bw6633.CheckKeys(_r1cs, pk.(*groth16_bw6633.ProvingKey), vk.(*groth16_bw6633.VerifyingKey))
+	default:
+		return errors.New("unrecognized R1CS curve type")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package marlin
+
+import (
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark/io"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProofSerialization(t *testing.T) {
+	// create a  proof
+	var proof Proof
+	proof.randomize()
+
+	assert.NoError(t, io.RoundTripCheck(&proof, func() interface{} { return new(Proof) }))
+}
+
+func TestProvingKeySerialization(t *testing.T) {
+	// random pk
+	var pk ProvingKey
+	pk.randomize()
+
+	assert.NoError(t, io.RoundTripCheck(&pk, func() interface{} { return new(ProvingKey) }))
+}
+
+func TestVerifyingKeySerialization(t *testing.T) {
+	// create a random vk
+	var vk VerifyingKey
+	vk.randomize()
+
+	assert.NoError(t, io.RoundTripCheck(&vk, func() interface{} { return new(VerifyingKey) }))
+}
+
+func (pk *ProvingKey) randomize() {
+
+	var vk VerifyingKey
+	vk.randomize()
+	pk.Vk = &vk
+	pk.Domain[0] = *fft.NewDomain(32)
+	pk.Domain[1] = *fft.NewDomain(64)
+
+	pk.Kzg.G1 = make([]curve.G1Affine, 32)
+	for i := range pk.Kzg.G1 {
+		pk.Kzg.G1[i] = randomG1Point()
+	}
+
+	for i := range pk.Index {
+		nbEntries := 1 + rand.Intn(64) //#nosec G404 weak rng is fine here
+		pk.Index[i] = Matrix{
+			Rows:   make([]uint64, nbEntries),
+			Cols:   make([]uint64, nbEntries),
+			Coeffs: randomScalars(nbEntries),
+		}
+		for j := 0; j < nbEntries; j++ {
+			pk.Index[i].Rows[j] = uint64(rand.Intn(32)) //#nosec G404 weak rng is fine here
+			pk.Index[i].Cols[j] = uint64(rand.Intn(32)) //#nosec G404 weak rng is fine here
+		}
+	}
+}
+
+func (vk *VerifyingKey) randomize() {
+	vk.Size = rand.Uint64() //#nosec G404 weak rng is fine here
+	vk.SizeInv.SetRandom()
+	vk.Generator.SetRandom()
+	vk.SizeK = rand.Uint64() //#nosec G404 weak rng is fine here
+	vk.SizeKInv.SetRandom()
+	vk.GeneratorK.SetRandom()
+	vk.NbPublicVariables = rand.Uint64() //#nosec G404 weak rng is fine here
+
+	vk.Kzg.G1 = randomG1Point()
+	vk.Kzg.G2[0] = randomG2Point()
+	vk.Kzg.G2[1] = randomG2Point()
+
+	for i := range vk.Row {
+		vk.Row[i] = randomG1Point()
+		vk.Col[i] = randomG1Point()
+		vk.Val[i] = randomG1Point()
+	}
+}
+
+func (proof *Proof) randomize() {
+	proof.Z = randomG1Point()
+	for i := range proof.ZM {
+		proof.ZM[i] = randomG1Point()
+	}
+	proof.T = randomG1Point()
+	proof.S1 = randomG1Point()
+	proof.H1 = randomG1Point()
+	proof.F = randomG1Point()
+	proof.S2 = randomG1Point()
+	proof.H2 = randomG1Point()
+	proof.BatchedProof[0].H = randomG1Point()
+	proof.BatchedProof[0].ClaimedValues = randomScalars(nb_openings_h)
+	proof.BatchedProof[1].H = randomG1Point()
+	proof.BatchedProof[1].ClaimedValues = randomScalars(nb_openings_k)
+	for i := range proof.ShiftedOpening {
+		proof.ShiftedOpening[i].H = randomG1Point()
+		proof.ShiftedOpening[i].ClaimedValue.SetRandom()
+	}
+}
+
+func randomG2Point() curve.G2Affine {
+	_, _, _, r := curve.Generators()
+	r.ScalarMultiplication(&r, big.NewInt(int64(rand.Uint64()))) //#nosec G404 weak rng is fine here
+	return r
+}
+
+func randomG1Point() curve.G1Affine {
+	_, _, r, _ := curve.Generators()
+	r.ScalarMultiplication(&r, big.NewInt(int64(rand.Uint64()))) //#nosec G404 weak rng is fine here
+	return r
+}
+
+func randomScalars(n int) []fr.Element {
+	v := make([]fr.Element, n)
+	one := fr.One()
+	for i := 0; i < len(v); i++ {
+		if i == 0 {
+			v[i].SetRandom()
+		} else {
+			v[i].Add(&v[i-1], &one)
+		}
+	}
+	return v
+}
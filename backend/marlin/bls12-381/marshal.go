@@ -0,0 +1,302 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package marlin
+
+import (
+	"errors"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"io"
+)
+
+// WriteRawTo writes binary encoding of Proof to w without point compression
+func (proof *Proof) WriteRawTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w, curve.RawEncoding())
+}
+
+// WriteTo writes binary encoding of Proof to w with point compression
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	return proof.writeTo(w)
+}
+
+func (proof *Proof) writeTo(w io.Writer, options ...func(*curve.Encoder)) (int64, error) {
+	enc := curve.NewEncoder(w, options...)
+
+	toEncode := []interface{}{
+		&proof.Z,
+		&proof.ZM[0],
+		&proof.ZM[1],
+		&proof.ZM[2],
+		&proof.T,
+		&proof.S1,
+		&proof.H1,
+		&proof.F,
+		&proof.S2,
+		&proof.H2,
+		&proof.BatchedProof[0].H,
+		proof.BatchedProof[0].ClaimedValues,
+		&proof.BatchedProof[1].H,
+		proof.BatchedProof[1].ClaimedValues,
+		&proof.ShiftedOpening[0].H,
+		&proof.ShiftedOpening[0].ClaimedValue,
+		&proof.ShiftedOpening[1].H,
+		&proof.ShiftedOpening[1].ClaimedValue,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads binary representation of Proof from r
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	toDecode := []interface{}{
+		&proof.Z,
+		&proof.ZM[0],
+		&proof.ZM[1],
+		&proof.ZM[2],
+		&proof.T,
+		&proof.S1,
+		&proof.H1,
+		&proof.F,
+		&proof.S2,
+		&proof.H2,
+		&proof.BatchedProof[0].H,
+		&proof.BatchedProof[0].ClaimedValues,
+		&proof.BatchedProof[1].H,
+		&proof.BatchedProof[1].ClaimedValues,
+		&proof.ShiftedOpening[0].H,
+		&proof.ShiftedOpening[0].ClaimedValue,
+		&proof.ShiftedOpening[1].H,
+		&proof.ShiftedOpening[1].ClaimedValue,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of ProvingKey to w
+func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, true)
+}
+
+// WriteRawTo writes binary encoding of ProvingKey to w without point compression
+func (pk *ProvingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, false)
+}
+
+func (pk *ProvingKey) writeTo(w io.Writer, withCompression bool) (n int64, err error) {
+	// encode the verifying key
+	if withCompression {
+		n, err = pk.Vk.WriteTo(w)
+	} else {
+		n, err = pk.Vk.WriteRawTo(w)
+	}
+	if err != nil {
+		return
+	}
+
+	// fft domains
+	n2, err := pk.Domain[0].WriteTo(w)
+	if err != nil {
+		return
+	}
+	n += n2
+
+	n2, err = pk.Domain[1].WriteTo(w)
+	if err != nil {
+		return
+	}
+	n += n2
+
+	// KZG key
+	if withCompression {
+		n2, err = pk.Kzg.WriteTo(w)
+	} else {
+		n2, err = pk.Kzg.WriteRawTo(w)
+	}
+	if err != nil {
+		return
+	}
+	n += n2
+
+	enc := curve.NewEncoder(w)
+	for i := range pk.Index {
+		toEncode := []interface{}{
+			pk.Index[i].Rows,
+			pk.Index[i].Cols,
+			pk.Index[i].Coeffs,
+		}
+		for _, v := range toEncode {
+			if err := enc.Encode(v); err != nil {
+				return n + enc.BytesWritten(), err
+			}
+		}
+	}
+
+	return n + enc.BytesWritten(), nil
+}
+
+// ReadFrom reads from binary representation in r into ProvingKey
+func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r, true)
+}
+
+// UnsafeReadFrom reads from binary representation in r into ProvingKey without subgroup checks
+func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r, false)
+}
+
+func (pk *ProvingKey) readFrom(r io.Reader, withSubgroupChecks bool) (int64, error) {
+	pk.Vk = &VerifyingKey{}
+	n, err := pk.Vk.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	n2, err, chDomain0 := pk.Domain[0].AsyncReadFrom(r)
+	n += n2
+	if err != nil {
+		return n, err
+	}
+
+	n2, err, chDomain1 := pk.Domain[1].AsyncReadFrom(r)
+	n += n2
+	if err != nil {
+		return n, err
+	}
+
+	if withSubgroupChecks {
+		n2, err = pk.Kzg.ReadFrom(r)
+	} else {
+		n2, err = pk.Kzg.UnsafeReadFrom(r)
+	}
+	n += n2
+	if err != nil {
+		return n, err
+	}
+
+	dec := curve.NewDecoder(r)
+	for i := range pk.Index {
+		m := &pk.Index[i]
+		toDecode := []interface{}{
+			&m.Rows,
+			&m.Cols,
+			&m.Coeffs,
+		}
+		for _, v := range toDecode {
+			if err := dec.Decode(v); err != nil {
+				return n + dec.BytesRead(), err
+			}
+		}
+		if len(m.Rows) != len(m.Coeffs) || len(m.Cols) != len(m.Coeffs) || uint64(len(m.Coeffs)) > pk.Domain[1].Cardinality {
+			return n + dec.BytesRead(), errors.New("invalid matrix encoding")
+		}
+		for j := range m.Coeffs {
+			if m.Rows[j] >= pk.Domain[0].Cardinality || m.Cols[j] >= pk.Domain[0].Cardinality {
+				return n + dec.BytesRead(), errors.New("invalid matrix encoding")
+			}
+		}
+	}
+
+	// wait for FFT to be precomputed
+	<-chDomain0
+	<-chDomain1
+
+	return n + dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of VerifyingKey to w
+func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return vk.writeTo(w)
+}
+
+// WriteRawTo writes binary encoding of VerifyingKey to w without point compression
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return vk.writeTo(w, curve.RawEncoding())
+}
+
+func (vk *VerifyingKey) writeTo(w io.Writer, options ...func(*curve.Encoder)) (n int64, err error) {
+	enc := curve.NewEncoder(w, options...)
+
+	toEncode := []interface{}{
+		vk.Size,
+		&vk.SizeInv,
+		&vk.Generator,
+		vk.SizeK,
+		&vk.SizeKInv,
+		&vk.GeneratorK,
+		vk.NbPublicVariables,
+		&vk.Kzg.G1,
+		&vk.Kzg.G2[0],
+		&vk.Kzg.G2[1],
+	}
+	for i := range vk.Row {
+		toEncode = append(toEncode, &vk.Row[i], &vk.Col[i], &vk.Val[i])
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// UnsafeReadFrom reads from binary representation in r into VerifyingKey.
+// Current implementation is a passthrough to ReadFrom
+func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return vk.ReadFrom(r)
+}
+
+// ReadFrom reads from binary representation in r into VerifyingKey
+func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	toDecode := []interface{}{
+		&vk.Size,
+		&vk.SizeInv,
+		&vk.Generator,
+		&vk.SizeK,
+		&vk.SizeKInv,
+		&vk.GeneratorK,
+		&vk.NbPublicVariables,
+		&vk.Kzg.G1,
+		&vk.Kzg.G2[0],
+		&vk.Kzg.G2[1],
+	}
+	for i := range vk.Row {
+		toDecode = append(toDecode, &vk.Row[i], &vk.Col[i], &vk.Val[i])
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
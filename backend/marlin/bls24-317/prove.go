@@ -0,0 +1,496 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package marlin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	cs "github.com/consensys/gnark/constraint/bls24-317"
+	"github.com/consensys/gnark/logger"
+	"math/big"
+	"time"
+)
+
+// Proof represents a Marlin proof generated by Prove.
+//
+// The proof consists of the commitments to the (blinded) witness polynomials,
+// of the two sumchecks proving the lincheck, and of the openings of these
+// polynomials at the challenges β₁ (on H) and β₂ (on K).
+type Proof struct {
+	// Commitments to the blinded ẑ, ẑ_A = Az, ẑ_B = Bz, ẑ_C = Cz
+	Z  kzg.Digest
+	ZM [3]kzg.Digest
+
+	// Commitments to t, to the running sum S₁ of the sumcheck on H and to the
+	// quotient H₁ of the checks on H
+	T, S1, H1 kzg.Digest
+
+	// Commitments to f, to the running sum S₂ of the sumcheck on K and to the
+	// quotient H₂ of the checks on K
+	F, S2, H2 kzg.Digest
+
+	// Batch openings at β₁ of ẑ, ẑ_A, ẑ_B, ẑ_C, t, S₁, H₁ and at β₂ of f, S₂, H₂,
+	// row, col and val of A, B, C.
+	BatchedProof [2]kzg.BatchOpeningProof
+
+	// Openings of S₁ at ωβ₁ and of S₂ at ω_Kβ₂
+	ShiftedOpening [2]kzg.OpeningProof
+}
+
+const (
+	nb_openings_h = 7
+	nb_openings_k = 12
+)
+
+// Prove generates a Marlin proof from a circuit, its proving key and the full
+// witness.
+//
+// The prover shows that z = (1, x, w) satisfies Az ∘ Bz = Cz, where A, B, C
+// are the matrices of the R1CS, with the following checks:
+//   - rowcheck: ẑ_A⋅ẑ_B - ẑ_C vanishes on H;
+//   - lincheck: for M ∈ {A, B, C}, ẑ_M is the interpolation of Mz. For random
+//     α and η, ∑_{k ∈ H} r(α, k)⋅∑_M ηᴹ⋅ẑ_M(k) - t(k)⋅ẑ(k) = 0, where
+//     t(k) = ∑_M ηᴹ⋅∑_{i ∈ H} r(α, i)⋅M[i][k]. The sum is proved with the
+//     running sum S₁, which satisfies S₁(ωX) - S₁(X) = the summand on H;
+//   - t(β₁) is proved with a second sumcheck on K, using the index
+//     polynomials row, col and val;
+//   - ẑ agrees with the public inputs.
+func Prove(r1cs *cs.R1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+
+	log := logger.Logger().With().
+		Str("curve", r1cs.CurveID().String()).
+		Int("nbConstraints", r1cs.GetNbConstraints()).
+		Str("backend", "marlin").Logger()
+
+	// parse the options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("get prover options: %w", err)
+	}
+
+	start := time.Now()
+
+	// solve the R1CS
+	_solution, err := r1cs.Solve(fullWitness, opt.SolverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	solution := _solution.(*cs.R1CSSolution)
+
+	var proof Proof
+	domainH, domainK := &pk.Domain[0], &pk.Domain[1]
+	n := int(domainH.Cardinality)
+	nbPublic := int(pk.Vk.NbPublicVariables)
+	fs := fiatshamir.NewTranscript(opt.ChallengeHash, "alpha", "eta", "lambda", "beta1", "lambda2", "beta2")
+	if err := bindPublicData(&fs, "alpha", pk.Vk, solution.W[1:nbPublic]); err != nil {
+		return nil, err
+	}
+
+	// round 1: ẑ, ẑ_A, ẑ_B, ẑ_C
+	z := make([]fr.Element, n)
+	copy(z, solution.W)
+	var zM [3][]fr.Element
+	for i, v := range [3][]fr.Element{solution.A, solution.B, solution.C} {
+		zM[i] = make([]fr.Element, n)
+		copy(zM[i], v)
+	}
+	var blindedZ []fr.Element
+	var blindedZM [3][]fr.Element
+	if blindedZ, err = blind(z, domainH, 1); err != nil {
+		return nil, err
+	}
+	if proof.Z, err = kzg.Commit(blindedZ, pk.Kzg); err != nil {
+		return nil, err
+	}
+	for i := range zM {
+		if blindedZM[i], err = blind(zM[i], domainH, 1); err != nil {
+			return nil, err
+		}
+		if proof.ZM[i], err = kzg.Commit(blindedZM[i], pk.Kzg); err != nil {
+			return nil, err
+		}
+	}
+	alpha, err := deriveRandomness(&fs, "alpha", &proof.Z, &proof.ZM[0], &proof.ZM[1], &proof.ZM[2])
+	if err != nil {
+		return nil, err
+	}
+	eta, err := deriveRandomness(&fs, "eta")
+	if err != nil {
+		return nil, err
+	}
+	var etas [3]fr.Element
+	etas[0].SetOne()
+	etas[1].Set(&eta)
+	etas[2].Square(&eta)
+
+	// round 2: t and the running sum S₁ of the sumcheck on H
+	// r(α, ωⁱ) = (αⁿ-1)/(α-ωⁱ)
+	rAlpha := make([]fr.Element, n)
+	var alphaN, vhAlpha fr.Element
+	alphaN.Exp(alpha, big.NewInt(int64(n)))
+	vhAlpha.SetOne()
+	vhAlpha.Sub(&alphaN, &vhAlpha)
+	{
+		var w fr.Element
+		w.SetOne()
+		for i := range rAlpha {
+			rAlpha[i].Sub(&alpha, &w)
+			w.Mul(&w, &domainH.Generator)
+		}
+		rAlpha = fr.BatchInvert(rAlpha)
+		for i := range rAlpha {
+			rAlpha[i].Mul(&rAlpha[i], &vhAlpha)
+		}
+	}
+	t := make([]fr.Element, n)
+	for i := range pk.Index {
+		m := &pk.Index[i]
+		var c fr.Element
+		for j := range m.Coeffs {
+			c.Mul(&rAlpha[m.Rows[j]], &m.Coeffs[j]).Mul(&c, &etas[i])
+			t[m.Cols[j]].Add(&t[m.Cols[j]], &c)
+		}
+	}
+	s1 := make([]fr.Element, n)
+	{
+		var q, c fr.Element
+		for i := 0; i < n-1; i++ {
+			q.Mul(&zM[2][i], &etas[2])
+			c.Mul(&zM[1][i], &etas[1])
+			q.Add(&q, &c).Add(&q, &zM[0][i]).Mul(&q, &rAlpha[i])
+			c.Mul(&t[i], &z[i])
+			q.Sub(&q, &c)
+			s1[i+1].Add(&s1[i], &q)
+		}
+	}
+	var blindedS1 []fr.Element
+	if blindedS1, err = blind(s1, domainH, 2); err != nil {
+		return nil, err
+	}
+	if proof.S1, err = kzg.Commit(blindedS1, pk.Kzg); err != nil {
+		return nil, err
+	}
+	domainH.FFTInverse(t, fft.DIF)
+	fft.BitReverse(t)
+	if proof.T, err = kzg.Commit(t, pk.Kzg); err != nil {
+		return nil, err
+	}
+	lambda, err := deriveRandomness(&fs, "lambda", &proof.T, &proof.S1)
+	if err != nil {
+		return nil, err
+	}
+
+	// round 3: quotient H₁ of
+	// ẑ_A⋅ẑ_B - ẑ_C + λ⋅(S₁(ωX) - S₁(X) - r(α, X)⋅∑_M ηᴹ⋅ẑ_M(X) + t(X)⋅ẑ(X)) + λ²⋅L_x(X)⋅(ẑ(X) - x̂(X))
+	// by the vanishing polynomial of H, where L_x is 1 on the public rows and
+	// 0 elsewhere and x̂ interpolates the public inputs.
+	h1, err := computeQuotientH(solution.W[:nbPublic], blindedZ, blindedZM, t, blindedS1, alpha, etas, lambda, domainH)
+	if err != nil {
+		return nil, err
+	}
+	if proof.H1, err = kzg.Commit(h1, pk.Kzg); err != nil {
+		return nil, err
+	}
+	beta1, err := deriveRandomness(&fs, "beta1", &proof.H1)
+	if err != nil {
+		return nil, err
+	}
+
+	// round 4: f and the running sum S₂ of the sumcheck on K, where
+	// f = ∑_M ηᴹ⋅val_M/((α-row_M)(β₁-col_M)) sums to σ = t(β₁)/(v_H(α)v_H(β₁)) on K.
+	rows, cols, vals := pk.indexPolynomials()
+	sizeK := int(domainK.Cardinality)
+	f := make([]fr.Element, sizeK)
+	{
+		den := make([]fr.Element, 3*sizeK)
+		var c fr.Element
+		for i := range rows {
+			for j := 0; j < sizeK; j++ {
+				den[i*sizeK+j].Sub(&alpha, &rows[i][j])
+				c.Sub(&beta1, &cols[i][j])
+				den[i*sizeK+j].Mul(&den[i*sizeK+j], &c)
+			}
+		}
+		den = fr.BatchInvert(den)
+		for i := range rows {
+			for j := 0; j < sizeK; j++ {
+				c.Mul(&vals[i][j], &den[i*sizeK+j]).Mul(&c, &etas[i])
+				f[j].Add(&f[j], &c)
+			}
+		}
+	}
+	var sigmaK fr.Element // σ/|K|
+	for j := range f {
+		sigmaK.Add(&sigmaK, &f[j])
+	}
+	sigmaK.Mul(&sigmaK, &domainK.CardinalityInv)
+	s2 := make([]fr.Element, sizeK)
+	for j := 0; j < sizeK-1; j++ {
+		s2[j+1].Add(&s2[j], &f[j]).Sub(&s2[j+1], &sigmaK)
+	}
+	for _, p := range [][]fr.Element{f, s2} {
+		domainK.FFTInverse(p, fft.DIF)
+		fft.BitReverse(p)
+	}
+	for i := range rows {
+		for _, p := range [][]fr.Element{rows[i], cols[i], vals[i]} {
+			domainK.FFTInverse(p, fft.DIF)
+			fft.BitReverse(p)
+		}
+	}
+	if proof.F, err = kzg.Commit(f, pk.Kzg); err != nil {
+		return nil, err
+	}
+	if proof.S2, err = kzg.Commit(s2, pk.Kzg); err != nil {
+		return nil, err
+	}
+	lambda2, err := deriveRandomness(&fs, "lambda2", &proof.F, &proof.S2)
+	if err != nil {
+		return nil, err
+	}
+
+	// round 5: quotient H₂ of
+	// b(X)⋅f(X) - a(X) + λ₂⋅(S₂(ω_K X) - S₂(X) - f(X) + σ/|K|)
+	// by the vanishing polynomial of K, where b = ∏_M (α-row_M)(β₁-col_M) and
+	// a = ∑_M ηᴹ⋅val_M⋅∏_{N≠M} (α-row_N)(β₁-col_N).
+	h2 := computeQuotientK(rows, cols, vals, f, s2, alpha, beta1, etas, lambda2, sigmaK, domainK)
+	if proof.H2, err = kzg.Commit(h2, pk.Kzg); err != nil {
+		return nil, err
+	}
+	beta2, err := deriveRandomness(&fs, "beta2", &proof.H2)
+	if err != nil {
+		return nil, err
+	}
+
+	// openings
+	var shifted fr.Element
+	proof.BatchedProof[0], err = kzg.BatchOpenSinglePoint(
+		[][]fr.Element{blindedZ, blindedZM[0], blindedZM[1], blindedZM[2], t, blindedS1, h1},
+		[]kzg.Digest{proof.Z, proof.ZM[0], proof.ZM[1], proof.ZM[2], proof.T, proof.S1, proof.H1},
+		beta1,
+		opt.KZGFoldingHash,
+		pk.Kzg,
+	)
+	if err != nil {
+		return nil, err
+	}
+	shifted.Mul(&beta1, &domainH.Generator)
+	if proof.ShiftedOpening[0], err = kzg.Open(blindedS1, shifted, pk.Kzg); err != nil {
+		return nil, err
+	}
+	proof.BatchedProof[1], err = kzg.BatchOpenSinglePoint(
+		[][]fr.Element{f, s2, h2, rows[0], rows[1], rows[2], cols[0], cols[1], cols[2], vals[0], vals[1], vals[2]},
+		[]kzg.Digest{proof.F, proof.S2, proof.H2, pk.Vk.Row[0], pk.Vk.Row[1], pk.Vk.Row[2], pk.Vk.Col[0], pk.Vk.Col[1], pk.Vk.Col[2], pk.Vk.Val[0], pk.Vk.Val[1], pk.Vk.Val[2]},
+		beta2,
+		opt.KZGFoldingHash,
+		pk.Kzg,
+	)
+	if err != nil {
+		return nil, err
+	}
+	shifted.Mul(&beta2, &domainK.Generator)
+	if proof.ShiftedOpening[1], err = kzg.Open(s2, shifted, pk.Kzg); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
+
+	return &proof, nil
+}
+
+// blind returns the canonical form of the polynomial whose evaluations on the
+// domain are evals, plus a random polynomial of degree nbBlinding-1 times the
+// vanishing polynomial of the domain.
+func blind(evals []fr.Element, domain *fft.Domain, nbBlinding int) ([]fr.Element, error) {
+	n := len(evals)
+	res := make([]fr.Element, n+nbBlinding)
+	copy(res, evals)
+	domain.FFTInverse(res[:n], fft.DIF)
+	fft.BitReverse(res[:n])
+	var r fr.Element
+	for i := 0; i < nbBlinding; i++ {
+		if _, err := r.SetRandom(); err != nil {
+			return nil, err
+		}
+		res[i].Sub(&res[i], &r)
+		res[n+i].Add(&res[n+i], &r)
+	}
+	return res, nil
+}
+
+// evaluateOnCoset returns the evaluations of p on the coset of the domain, in
+// natural order.
+func evaluateOnCoset(p []fr.Element, domain *fft.Domain) []fr.Element {
+	res := make([]fr.Element, domain.Cardinality)
+	copy(res, p)
+	domain.FFT(res, fft.DIF, fft.OnCoset())
+	fft.BitReverse(res)
+	return res
+}
+
+// interpolateOnCoset returns the canonical form of the polynomial whose
+// evaluations on the coset of the domain are evals, in natural order. evals is
+// modified.
+func interpolateOnCoset(evals []fr.Element, domain *fft.Domain) []fr.Element {
+	domain.FFTInverse(evals, fft.DIF, fft.OnCoset())
+	fft.BitReverse(evals)
+	return evals
+}
+
+// vanishingOnCosetInv returns the inverses of the vanishing polynomial of a
+// subgroup of size n on the coset of the big domain. They are periodic, of
+// period |big domain|/n.
+func vanishingOnCosetInv(n int, bigDomain *fft.Domain) []fr.Element {
+	rho := int(bigDomain.Cardinality) / n
+	res := make([]fr.Element, rho)
+	var c, g fr.Element
+	c.Exp(bigDomain.FrMultiplicativeGen, big.NewInt(int64(n)))
+	g.Exp(bigDomain.Generator, big.NewInt(int64(n)))
+	one := fr.One()
+	for i := range res {
+		res[i].Sub(&c, &one)
+		c.Mul(&c, &g)
+	}
+	return fr.BatchInvert(res)
+}
+
+// computeQuotientH returns the quotient of the checks on H, see [Prove].
+func computeQuotientH(x, z []fr.Element, zM [3][]fr.Element, t, s1 []fr.Element, alpha fr.Element, etas [3]fr.Element, lambda fr.Element, domainH *fft.Domain) ([]fr.Element, error) {
+	n := int(domainH.Cardinality)
+	bigDomain := fft.NewDomain(uint64(4 * n))
+	rho := int(bigDomain.Cardinality) / n
+
+	// r(α, X) = (αⁿ-Xⁿ)/(α-X) = ∑ᵢ αⁿ⁻¹⁻ⁱXⁱ
+	r := make([]fr.Element, n)
+	r[n-1].SetOne()
+	for i := n - 2; i >= 0; i-- {
+		r[i].Mul(&r[i+1], &alpha)
+	}
+
+	// L_x and x̂
+	lx := make([]fr.Element, n)
+	xHat := make([]fr.Element, n)
+	for i := range x {
+		lx[i].SetOne()
+		xHat[i].Set(&x[i])
+	}
+	for _, p := range [][]fr.Element{lx, xHat} {
+		domainH.FFTInverse(p, fft.DIF)
+		fft.BitReverse(p)
+	}
+
+	_z := evaluateOnCoset(z, bigDomain)
+	var _zM [3][]fr.Element
+	for i := range zM {
+		_zM[i] = evaluateOnCoset(zM[i], bigDomain)
+	}
+	_t := evaluateOnCoset(t, bigDomain)
+	_s1 := evaluateOnCoset(s1, bigDomain)
+	_r := evaluateOnCoset(r, bigDomain)
+	_lx := evaluateOnCoset(lx, bigDomain)
+	_xHat := evaluateOnCoset(xHat, bigDomain)
+	vInv := vanishingOnCosetInv(n, bigDomain)
+
+	var lambda2 fr.Element
+	lambda2.Square(&lambda)
+	size := int(bigDomain.Cardinality)
+	res := make([]fr.Element, size)
+	for i := range res {
+		var a, b fr.Element
+
+		// lincheck
+		a.Mul(&_zM[2][i], &etas[2])
+		b.Mul(&_zM[1][i], &etas[1])
+		a.Add(&a, &b).Add(&a, &_zM[0][i]).Mul(&a, &_r[i])
+		b.Mul(&_t[i], &_z[i])
+		a.Sub(&b, &a)
+		a.Add(&a, &_s1[(i+rho)%size]).Sub(&a, &_s1[i]).Mul(&a, &lambda)
+
+		// public inputs
+		b.Sub(&_z[i], &_xHat[i]).Mul(&b, &_lx[i]).Mul(&b, &lambda2)
+		a.Add(&a, &b)
+
+		// rowcheck
+		b.Mul(&_zM[0][i], &_zM[1][i]).Sub(&b, &_zM[2][i])
+		a.Add(&a, &b)
+
+		res[i].Mul(&a, &vInv[i%rho])
+	}
+	res = interpolateOnCoset(res, bigDomain)
+
+	// the numerator is of degree 2n, the quotient of degree n
+	for i := n + 1; i < len(res); i++ {
+		if !res[i].IsZero() {
+			return nil, errors.New("the witness does not satisfy the constraints")
+		}
+	}
+	return res[:n+1], nil
+}
+
+// computeQuotientK returns the quotient of the checks on K, see [Prove].
+func computeQuotientK(rows, cols, vals [3][]fr.Element, f, s2 []fr.Element, alpha, beta1 fr.Element, etas [3]fr.Element, lambda2, sigmaK fr.Element, domainK *fft.Domain) []fr.Element {
+	sizeK := int(domainK.Cardinality)
+	bigDomain := fft.NewDomain(uint64(8 * sizeK))
+	rho := int(bigDomain.Cardinality) / sizeK
+
+	var _rows, _cols, _vals [3][]fr.Element
+	for i := range rows {
+		_rows[i] = evaluateOnCoset(rows[i], bigDomain)
+		_cols[i] = evaluateOnCoset(cols[i], bigDomain)
+		_vals[i] = evaluateOnCoset(vals[i], bigDomain)
+	}
+	_f := evaluateOnCoset(f, bigDomain)
+	_s2 := evaluateOnCoset(s2, bigDomain)
+	vInv := vanishingOnCosetInv(sizeK, bigDomain)
+
+	size := int(bigDomain.Cardinality)
+	res := make([]fr.Element, size)
+	for i := range res {
+		var bM [3]fr.Element
+		var a, b, c fr.Element
+		for j := range bM {
+			bM[j].Sub(&alpha, &_rows[j][i])
+			c.Sub(&beta1, &_cols[j][i])
+			bM[j].Mul(&bM[j], &c)
+		}
+
+		// a = ∑_M ηᴹ⋅val_M⋅∏_{N≠M} b_N
+		a.Mul(&bM[1], &bM[2]).Mul(&a, &_vals[0][i])
+		c.Mul(&bM[0], &bM[2]).Mul(&c, &_vals[1][i]).Mul(&c, &etas[1])
+		a.Add(&a, &c)
+		c.Mul(&bM[0], &bM[1]).Mul(&c, &_vals[2][i]).Mul(&c, &etas[2])
+		a.Add(&a, &c)
+
+		// b⋅f - a
+		b.Mul(&bM[0], &bM[1]).Mul(&b, &bM[2]).Mul(&b, &_f[i]).Sub(&b, &a)
+
+		// λ₂⋅(S₂(ω_K X) - S₂(X) - f(X) + σ/|K|)
+		c.Sub(&_s2[(i+rho)%size], &_s2[i]).Sub(&c, &_f[i]).Add(&c, &sigmaK).Mul(&c, &lambda2)
+
+		res[i].Add(&b, &c).Mul(&res[i], &vInv[i%rho])
+	}
+	res = interpolateOnCoset(res, bigDomain)
+
+	// the numerator is of degree 7(|K|-1), the quotient of degree 6|K|-7
+	return res[:6*sizeK-6]
+}
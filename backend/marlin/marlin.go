@@ -0,0 +1,474 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package marlin implements the Marlin Zero Knowledge Proof system, a
+// universal-setup SNARK for R1CS.
+//
+// Marlin consumes the R1CS compiled for Groth16, so that circuits don't need to
+// be recompiled into PLONK gates: [Setup] only encodes the matrices A, B, C of
+// the R1CS (the index) with polynomials committed with a universal KZG SRS.
+//
+// The sums of the lincheck are proved with running sums on the domains instead
+// of the univariate sumcheck of the paper, so that the protocol doesn't need
+// degree bounds on the committed polynomials. Circuits using commitments
+// (frontend.Committer) are not supported.
+//
+// # See also
+//
+// https://eprint.iacr.org/2019/1047
+package marlin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/internal/utils"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/consensys/gnark/backend/witness"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+
+	marlin_bls12377 "github.com/consensys/gnark/backend/marlin/bls12-377"
+	marlin_bls12381 "github.com/consensys/gnark/backend/marlin/bls12-381"
+	marlin_bls24315 "github.com/consensys/gnark/backend/marlin/bls24-315"
+	marlin_bls24317 "github.com/consensys/gnark/backend/marlin/bls24-317"
+	marlin_bn254 "github.com/consensys/gnark/backend/marlin/bn254"
+	marlin_bw6633 "github.com/consensys/gnark/backend/marlin/bw6-633"
+	marlin_bw6761 "github.com/consensys/gnark/backend/marlin/bw6-761"
+
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	kzg_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	kzg_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/kzg"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
+
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// Proof represents a Marlin proof generated by marlin.Prove
+//
+// it's underlying implementation is curve specific (see gnark/backend/marlin)
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+}
+
+// ProvingKey represents a Marlin ProvingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/marlin)
+type ProvingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	VerifyingKey() interface{}
+}
+
+// VerifyingKey represents a Marlin VerifyingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/marlin)
+type VerifyingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	NbPublicWitness() int // number of elements expected in the public witness
+}
+
+var (
+	errProvingKeyCurve   = fmt.Errorf("%w: the proving key does not match the constraint system curve", gnark.ErrCurveMismatch)
+	errVerifyingKeyCurve = fmt.Errorf("%w: the verifying key does not match the proof curve", gnark.ErrCurveMismatch)
+	errSRSCurve          = fmt.Errorf("%w: the SRS does not match the constraint system curve", gnark.ErrCurveMismatch)
+)
+
+// checkR1CS returns an error if ccs was compiled for a PLONK backend.
+func checkR1CS(ccs constraint.ConstraintSystem) error {
+	if ccs == nil {
+		return nil
+	}
+	if _, ok := ccs.GetCommitments().(constraint.Groth16Commitments); !ok {
+		return errors.New("the constraint system is not a R1CS")
+	}
+	return nil
+}
+
+// Setup computes the index of the R1CS and commits to it with the universal
+// SRS, whose size must be at least [SRSSize].
+func Setup(ccs constraint.ConstraintSystem, kzgSrs kzg.SRS, opts ...backend.SetupOption) (_ ProvingKey, _ VerifyingKey, err error) {
+	cfg, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(ccs); err != nil {
+		return nil, nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.R1CS:
+		_kzgSrs, ok := kzgSrs.(*kzg_bn254.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return marlin_bn254.Setup(tccs, *_kzgSrs)
+	case *cs_bls12381.R1CS:
+		_kzgSrs, ok := kzgSrs.(*kzg_bls12381.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return marlin_bls12381.Setup(tccs, *_kzgSrs)
+	case *cs_bls12377.R1CS:
+		_kzgSrs, ok := kzgSrs.(*kzg_bls12377.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return marlin_bls12377.Setup(tccs, *_kzgSrs)
+	case *cs_bw6761.R1CS:
+		_kzgSrs, ok := kzgSrs.(*kzg_bw6761.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return marlin_bw6761.Setup(tccs, *_kzgSrs)
+	case *cs_bls24317.R1CS:
+		_kzgSrs, ok := kzgSrs.(*kzg_bls24317.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return marlin_bls24317.Setup(tccs, *_kzgSrs)
+	case *cs_bls24315.R1CS:
+		_kzgSrs, ok := kzgSrs.(*kzg_bls24315.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return marlin_bls24315.Setup(tccs, *_kzgSrs)
+	case *cs_bw6633.R1CS:
+		_kzgSrs, ok := kzgSrs.(*kzg_bw6633.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return marlin_bw6633.Setup(tccs, *_kzgSrs)
+	default:
+		return nil, nil, errors.New("unrecognized R1CS curve type")
+	}
+
+}
+
+// Prove generates a Marlin proof from a circuit, its proving key and the full
+// witness.
+func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(ccs); err != nil {
+		return nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.R1CS:
+		_pk, ok := pk.(*marlin_bn254.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return marlin_bn254.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls12381.R1CS:
+		_pk, ok := pk.(*marlin_bls12381.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return marlin_bls12381.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls12377.R1CS:
+		_pk, ok := pk.(*marlin_bls12377.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return marlin_bls12377.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bw6761.R1CS:
+		_pk, ok := pk.(*marlin_bw6761.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return marlin_bw6761.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls24317.R1CS:
+		_pk, ok := pk.(*marlin_bls24317.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return marlin_bls24317.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls24315.R1CS:
+		_pk, ok := pk.(*marlin_bls24315.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return marlin_bls24315.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bw6633.R1CS:
+		_pk, ok := pk.(*marlin_bw6633.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return marlin_bw6633.Prove(tccs, _pk, fullWitness, opts...)
+	default:
+		return nil, errors.New("unrecognized R1CS curve type")
+	}
+}
+
+// Verify verifies a Marlin proof, from the proof, the verifying key and the
+// public witness.
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) (err error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+
+	switch _proof := proof.(type) {
+
+	case *marlin_bn254.Proof:
+		w, ok := publicWitness.Vector().(fr_bn254.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*marlin_bn254.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return marlin_bn254.Verify(_proof, _vk, w, opts...)
+
+	case *marlin_bls12381.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*marlin_bls12381.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return marlin_bls12381.Verify(_proof, _vk, w, opts...)
+
+	case *marlin_bls12377.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12377.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*marlin_bls12377.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return marlin_bls12377.Verify(_proof, _vk, w, opts...)
+
+	case *marlin_bw6761.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*marlin_bw6761.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return marlin_bw6761.Verify(_proof, _vk, w, opts...)
+
+	case *marlin_bls24317.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24317.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*marlin_bls24317.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return marlin_bls24317.Verify(_proof, _vk, w, opts...)
+
+	case *marlin_bls24315.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*marlin_bls24315.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return marlin_bls24315.Verify(_proof, _vk, w, opts...)
+
+	case *marlin_bw6633.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6633.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*marlin_bw6633.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return marlin_bw6633.Verify(_proof, _vk, w, opts...)
+	default:
+		return errors.New("unrecognized proof type")
+	}
+}
+
+// SRSSize returns the number of G1 points of the KZG SRS needed by [Setup] for
+// the given R1CS.
+func SRSSize(ccs constraint.ConstraintSystem) int {
+	r1cs, ok := ccs.(constraint.R1CS)
+	if !ok {
+		panic("expected a R1CS")
+	}
+	nbInternal, nbSecret, nbPublic := r1cs.GetNbVariables()
+	nbRows := nbInternal + nbSecret + nbPublic
+	if r1cs.GetNbConstraints() > nbRows {
+		nbRows = r1cs.GetNbConstraints()
+	}
+	var nbEntries [3]int
+	it := r1cs.GetR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		for i, l := range []constraint.LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				if t.CoeffID() != constraint.CoeffIdZero {
+					nbEntries[i]++
+				}
+			}
+		}
+	}
+	maxEntries := nbEntries[0]
+	for i := 1; i < len(nbEntries); i++ {
+		if nbEntries[i] > maxEntries {
+			maxEntries = nbEntries[i]
+		}
+	}
+
+	switch ccs.(type) {
+	case *cs_bn254.R1CS:
+		return marlin_bn254.SRSSize(nbRows, maxEntries)
+	case *cs_bls12381.R1CS:
+		return marlin_bls12381.SRSSize(nbRows, maxEntries)
+	case *cs_bls12377.R1CS:
+		return marlin_bls12377.SRSSize(nbRows, maxEntries)
+	case *cs_bw6761.R1CS:
+		return marlin_bw6761.SRSSize(nbRows, maxEntries)
+	case *cs_bls24317.R1CS:
+		return marlin_bls24317.SRSSize(nbRows, maxEntries)
+	case *cs_bls24315.R1CS:
+		return marlin_bls24315.SRSSize(nbRows, maxEntries)
+	case *cs_bw6633.R1CS:
+		return marlin_bw6633.SRSSize(nbRows, maxEntries)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
+// This function exists for serialization purposes
+func NewProvingKey(curveID ecc.ID) ProvingKey {
+	var pk ProvingKey
+	switch curveID {
+	case ecc.BN254:
+		pk = &marlin_bn254.ProvingKey{}
+	case ecc.BLS12_377:
+		pk = &marlin_bls12377.ProvingKey{}
+	case ecc.BLS12_381:
+		pk = &marlin_bls12381.ProvingKey{}
+	case ecc.BW6_761:
+		pk = &marlin_bw6761.ProvingKey{}
+	case ecc.BLS24_317:
+		pk = &marlin_bls24317.ProvingKey{}
+	case ecc.BLS24_315:
+		pk = &marlin_bls24315.ProvingKey{}
+	case ecc.BW6_633:
+		pk = &marlin_bw6633.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pk
+}
+
+// NewProof instantiates a curve-typed Proof and returns an interface
+// This function exists for serialization purposes
+func NewProof(curveID ecc.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case ecc.BN254:
+		proof = &marlin_bn254.Proof{}
+	case ecc.BLS12_377:
+		proof = &marlin_bls12377.Proof{}
+	case ecc.BLS12_381:
+		proof = &marlin_bls12381.Proof{}
+	case ecc.BW6_761:
+		proof = &marlin_bw6761.Proof{}
+	case ecc.BLS24_317:
+		proof = &marlin_bls24317.Proof{}
+	case ecc.BLS24_315:
+		proof = &marlin_bls24315.Proof{}
+	case ecc.BW6_633:
+		proof = &marlin_bw6633.Proof{}
+	default:
+		panic("not implemented")
+	}
+
+	return proof
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var vk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		vk = &marlin_bn254.VerifyingKey{}
+	case ecc.BLS12_377:
+		vk = &marlin_bls12377.VerifyingKey{}
+	case ecc.BLS12_381:
+		vk = &marlin_bls12381.VerifyingKey{}
+	case ecc.BW6_761:
+		vk = &marlin_bw6761.VerifyingKey{}
+	case ecc.BLS24_317:
+		vk = &marlin_bls24317.VerifyingKey{}
+	case ecc.BLS24_315:
+		vk = &marlin_bls24315.VerifyingKey{}
+	case ecc.BW6_633:
+		vk = &marlin_bw6633.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return vk
+}
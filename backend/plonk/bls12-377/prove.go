@@ -42,6 +42,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/accelerator"
 	"github.com/consensys/gnark/backend/witness"
 
 	"github.com/consensys/gnark/constraint"
@@ -72,7 +73,19 @@ const (
 	id_S3
 	id_ID
 	id_LOne
-	id_Qci // [ .. , Qc_i, Pi_i, ...]
+	id_Qci // [ .. , Qc_i, Pi_i, ..., Qcg_j, ..., Φ, M, Qlk, Qtag, T, ΦS]
+)
+
+// lookup argument: offsets of the polynomials after the custom gate selectors
+// in x, and of their openings at ζ after the qcp. ΦS is only in x.
+const (
+	lk_Phi int = iota
+	lk_M
+	lk_Qlk
+	lk_Qtag
+	lk_T
+	nb_lookup_openings
+	lk_PhiS = nb_lookup_openings
 )
 
 // blinding factors
@@ -81,15 +94,19 @@ const (
 	id_Br
 	id_Bo
 	id_Bz
+	id_Bm
+	id_Bphi
 	nb_blinding_polynomials
 )
 
 // blinding orders (-1 to deactivate)
 const (
-	order_blinding_L = 1
-	order_blinding_R = 1
-	order_blinding_O = 1
-	order_blinding_Z = 2
+	order_blinding_L   = 1
+	order_blinding_R   = 1
+	order_blinding_O   = 1
+	order_blinding_Z   = 2
+	order_blinding_M   = 1
+	order_blinding_Phi = 2
 )
 
 type Proof struct {
@@ -110,6 +127,14 @@ type Proof struct {
 
 	// Opening proof of Z at zeta*mu
 	ZShiftedOpening kzg.OpeningProof
+
+	// Commitments to M, the multiplicities of the rows of the lookup tables,
+	// and to Φ, the running sum of the lookup argument. They are set only if
+	// the circuit has lookup tables, as the following opening of Φ.
+	LookupM, LookupPhi kzg.Digest
+
+	// Opening proof of Φ at zeta*mu
+	LookupPhiShiftedOpening kzg.OpeningProof
 }
 
 func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
@@ -152,10 +177,13 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	// compute accumulating ratio for the copy constraint
 	g.Go(instance.buildRatioCopyConstraint)
 
+	// compute the running sum of the lookup argument
+	g.Go(instance.buildLookupRatio)
+
 	// compute h
 	g.Go(instance.evaluateConstraints)
 
-	// open Z (blinded) at ωζ (proof.ZShiftedOpening)
+	// open Z and Φ (blinded) at ωζ (proof.ZShiftedOpening, proof.LookupPhiShiftedOpening)
 	g.Go(instance.openZ)
 
 	// fold the commitment to H ([H₀] + ζᵐ⁺²*[H₁] + ζ²⁽ᵐ⁺²⁾[H₂])
@@ -194,6 +222,14 @@ type instance struct {
 	h        *iop.Polynomial   // h is the quotient polynomial
 	blindedZ []fr.Element      // blindedZ is the blinded version of Z
 
+	// lookup argument
+	lookupRows    [][nb_lookup_columns]fr.Element // rows of the concatenated lookup tables
+	lookupQueries []lookupQuery                   // rows of the trace constrained by a lookup
+	lookupM       *iop.Polynomial                 // multiplicities of the rows of the tables
+	lookupPhi     *iop.Polynomial                 // running sum of the lookup argument
+	lookupT       *iop.Polynomial                 // ∑ᵢβⁱTᵢ
+	blindedPhi    []fr.Element                    // blinded version of Φ
+
 	foldedH       []fr.Element // foldedH is the folded version of H
 	foldedHDigest kzg.Digest   // foldedHDigest is the kzg commitment of foldedH
 
@@ -218,6 +254,7 @@ type instance struct {
 	chQk,
 	chbp,
 	chZ,
+	chLookupPhi,
 	chH,
 	chRestoreLRO,
 	chZOpening,
@@ -247,6 +284,7 @@ func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWi
 		chbp:                   make(chan struct{}, 1),
 		chGammaBeta:            make(chan struct{}, 1),
 		chZ:                    make(chan struct{}, 1),
+		chLookupPhi:            make(chan struct{}, 1),
 		chH:                    make(chan struct{}, 1),
 		chZOpening:             make(chan struct{}, 1),
 		chLinearizedPolynomial: make(chan struct{}, 1),
@@ -256,7 +294,11 @@ func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWi
 	}
 	s.initBSB22Commitments()
 	s.setupGKRHints()
-	s.x = make([]*iop.Polynomial, id_Qci+2*len(s.commitmentInfo))
+	nbPolynomials := s.idLookup()
+	if s.hasLookup() {
+		nbPolynomials += nb_lookup_openings + 1
+	}
+	s.x = make([]*iop.Polynomial, nbPolynomials)
 
 	return &s, nil
 }
@@ -296,6 +338,8 @@ func (s *instance) initBlindingPolynomials() error {
 	s.bp[id_Br] = getRandomPolynomial(order_blinding_R)
 	s.bp[id_Bo] = getRandomPolynomial(order_blinding_O)
 	s.bp[id_Bz] = getRandomPolynomial(order_blinding_Z)
+	s.bp[id_Bm] = getRandomPolynomial(order_blinding_M)
+	s.bp[id_Bphi] = getRandomPolynomial(order_blinding_Phi)
 	close(s.chbp)
 	return nil
 }
@@ -333,7 +377,7 @@ func (s *instance) bsb22Hint(commDepth int) solver.Hint {
 			return err
 		}
 		s.cCommitments[commDepth] = iop.NewPolynomial(&committedValues, iop.Form{Basis: iop.Lagrange, Layout: iop.Regular})
-		if s.proof.Bsb22Commitments[commDepth], err = kzg.Commit(s.cCommitments[commDepth].Coefficients(), s.pk.KzgLagrange); err != nil {
+		if s.proof.Bsb22Commitments[commDepth], err = kzgCommit(s.cCommitments[commDepth].Coefficients(), s.pk.KzgLagrange); err != nil {
 			return err
 		}
 		s.cCommitments[commDepth].ToCanonical(&s.pk.Domain[0]).ToRegular()
@@ -391,6 +435,14 @@ func (s *instance) solveConstraints() error {
 	if err := s.commitToLRO(); err != nil {
 		return err
 	}
+
+	// commit to the multiplicities of the lookup argument, which must be bound
+	// before gamma and beta are derived.
+	if s.hasLookup() {
+		if err := s.computeLookupMultiplicities(); err != nil {
+			return err
+		}
+	}
 	close(s.chLRO)
 	return nil
 }
@@ -469,7 +521,11 @@ func (s *instance) deriveGammaAndBeta() error {
 	case <-s.chLRO:
 	}
 
-	gamma, err := deriveRandomness(&s.fs, "gamma", &s.proof.LRO[0], &s.proof.LRO[1], &s.proof.LRO[2])
+	gammaDeps := []*curve.G1Affine{&s.proof.LRO[0], &s.proof.LRO[1], &s.proof.LRO[2]}
+	if s.hasLookup() {
+		gammaDeps = append(gammaDeps, &s.proof.LookupM)
+	}
+	gamma, err := deriveRandomness(&s.fs, "gamma", gammaDeps...)
 	if err != nil {
 		return err
 	}
@@ -492,7 +548,7 @@ func (s *instance) deriveGammaAndBeta() error {
 // /!\ The polynomial p is supposed to be in Lagrange form.
 func (s *instance) commitToPolyAndBlinding(p, b *iop.Polynomial) (commit curve.G1Affine, err error) {
 
-	commit, err = kzg.Commit(p.Coefficients(), s.pk.KzgLagrange)
+	commit, err = kzgCommit(p.Coefficients(), s.pk.KzgLagrange)
 
 	// we add in the blinding contribution
 	n := int(s.pk.Domain[0].Cardinality)
@@ -508,6 +564,9 @@ func (s *instance) deriveAlpha() (err error) {
 		alphaDeps[i] = &s.proof.Bsb22Commitments[i]
 	}
 	alphaDeps[len(alphaDeps)-1] = &s.proof.Z
+	if s.hasLookup() {
+		alphaDeps = append(alphaDeps, &s.proof.LookupPhi)
+	}
 	s.alpha, err = deriveRandomness(&s.fs, "alpha", alphaDeps...)
 	return err
 }
@@ -531,6 +590,13 @@ func (s *instance) evaluateConstraints() (err error) {
 	for i := 0; i < len(s.commitmentInfo); i++ {
 		s.x[id_Qci+2*i] = s.pk.trace.Qcp[i].Clone()
 	}
+	for i := range s.pk.trace.Qcg {
+		s.x[s.idQcg()+i] = s.pk.trace.Qcg[i].Clone()
+	}
+	if s.hasLookup() {
+		s.x[s.idLookup()+lk_Qlk] = s.pk.trace.Qlk.Clone()
+		s.x[s.idLookup()+lk_Qtag] = s.pk.trace.Qtag.Clone()
+	}
 
 	n := s.pk.Domain[0].Cardinality
 	lone := make([]fr.Element, n)
@@ -554,6 +620,19 @@ func (s *instance) evaluateConstraints() (err error) {
 	case <-s.chZ:
 	}
 
+	// wait for Φ to be committed or context done
+	select {
+	case <-s.ctx.Done():
+		return errContextDone
+	case <-s.chLookupPhi:
+	}
+	if s.hasLookup() {
+		s.x[s.idLookup()+lk_Phi] = s.lookupPhi.Clone()
+		s.x[s.idLookup()+lk_PhiS] = s.lookupPhi.Clone().Shift(1)
+		s.x[s.idLookup()+lk_M] = s.lookupM.Clone()
+		s.x[s.idLookup()+lk_T] = s.lookupT.Clone()
+	}
+
 	// derive alpha
 	if err = s.deriveAlpha(); err != nil {
 		return err
@@ -598,6 +677,157 @@ func (s *instance) evaluateConstraints() (err error) {
 	return nil
 }
 
+// idQcg returns the index in s.x of the selector of the first custom gate.
+func (s *instance) idQcg() int {
+	return id_Qci + 2*len(s.commitmentInfo)
+}
+
+// idLookup returns the index in s.x of the first polynomial of the lookup
+// argument.
+func (s *instance) idLookup() int {
+	return s.idQcg() + len(s.pk.Vk.CustomGates)
+}
+
+func (s *instance) hasLookup() bool {
+	return s.pk.Vk.NbLookupTables != 0
+}
+
+// lookupQuery is a row of the trace constrained by a lookup, with the values
+// l, r, o and the tag of the looked up table.
+type lookupQuery struct {
+	row   int
+	value [nb_lookup_columns]fr.Element
+}
+
+// computeLookupMultiplicities computes M, the number of lookups of each row of
+// the concatenated lookup tables, and commits to its blinded version. If a row
+// appears several times in the tables, only its first occurrence is counted.
+func (s *instance) computeLookupMultiplicities() (err error) {
+	n := int(s.pk.Domain[0].Cardinality)
+	tables := s.spr.GetLookupTables()
+
+	index := make(map[[nb_lookup_columns]fr.Element]int)
+	s.lookupRows = make([][nb_lookup_columns]fr.Element, 0, n)
+	for i := range tables {
+		for k := 0; k < tables[i].NbRows(); k++ {
+			var row [nb_lookup_columns]fr.Element
+			for col := range tables[i].Columns {
+				row[col].Set(&s.spr.Coefficients[tables[i].Columns[col][k]])
+			}
+			row[nb_lookup_columns-1].SetUint64(uint64(i + 1))
+			if _, ok := index[row]; !ok {
+				index[row] = len(s.lookupRows)
+			}
+			s.lookupRows = append(s.lookupRows, row)
+		}
+	}
+
+	l := s.x[id_L].Coefficients()
+	r := s.x[id_R].Coefficients()
+	o := s.x[id_O].Coefficients()
+	m := make([]fr.Element, n)
+	one := fr.One()
+	offset := s.spr.GetNbPublicVariables()
+	it := s.spr.GetSparseR1CIterator()
+	for j, c := 0, it.Next(); c != nil; j, c = j+1, it.Next() {
+		if c.Lookup == 0 {
+			continue
+		}
+		q := lookupQuery{row: offset + j}
+		q.value[0], q.value[1], q.value[2] = l[offset+j], r[offset+j], o[offset+j]
+		q.value[nb_lookup_columns-1].SetUint64(uint64(c.Lookup))
+		k, ok := index[q.value]
+		if !ok {
+			return fmt.Errorf("constraint %d: row not in lookup table %q", j, tables[c.Lookup-1].Name)
+		}
+		m[k].Add(&m[k], &one)
+		s.lookupQueries = append(s.lookupQueries, q)
+	}
+
+	s.lookupM = iop.NewPolynomial(&m, iop.Form{Basis: iop.Lagrange, Layout: iop.Regular})
+	s.proof.LookupM, err = s.commitToPolyAndBlinding(s.lookupM, s.bp[id_Bm])
+	return
+}
+
+// buildLookupRatio computes Φ, the running sum of the lookup argument, such that
+// Φ(1) = 0 and
+//
+//	Φ(ωX) - Φ(X) = qlk(X)/(γ+f(X)) - M(X)/(γ+t(X))
+//
+// on the domain, where f = l+β*r+β²*o+β³*qtag and t = ∑ᵢβⁱTᵢ. The relation holds
+// on the whole cycle iff ∑qlk/(γ+f) = ∑M/(γ+t), that is iff the looked up
+// values are rows of the tables.
+func (s *instance) buildLookupRatio() (err error) {
+	if !s.hasLookup() {
+		close(s.chLookupPhi)
+		return nil
+	}
+
+	// wait for gamma and beta to be derived (or ctx.Done())
+	select {
+	case <-s.ctx.Done():
+		return errContextDone
+	case <-s.chGammaBeta:
+	}
+
+	n := int(s.pk.Domain[0].Cardinality)
+	combine := func(v *[nb_lookup_columns]fr.Element) fr.Element {
+		var res fr.Element
+		for i := nb_lookup_columns - 1; i >= 0; i-- {
+			res.Mul(&res, &s.beta).Add(&res, &v[i])
+		}
+		res.Add(&res, &s.gamma)
+		return res
+	}
+
+	// 1/(γ+t) on the domain, and 1/(γ+f) on the rows of the lookups
+	den := make([]fr.Element, n+len(s.lookupQueries))
+	for i := 0; i < n; i++ {
+		if i < len(s.lookupRows) {
+			den[i] = combine(&s.lookupRows[i])
+		} else {
+			den[i].Set(&s.gamma)
+		}
+	}
+	for i := range s.lookupQueries {
+		den[n+i] = combine(&s.lookupQueries[i].value)
+	}
+	den = fr.BatchInvert(den)
+
+	m := s.lookupM.Coefficients()
+	inc := make([]fr.Element, n)
+	for i := range inc {
+		inc[i].Mul(&m[i], &den[i]).Neg(&inc[i])
+	}
+	for i, q := range s.lookupQueries {
+		inc[q.row].Add(&inc[q.row], &den[n+i])
+	}
+	phi := make([]fr.Element, n)
+	for i := 1; i < n; i++ {
+		phi[i].Add(&phi[i-1], &inc[i-1])
+	}
+
+	s.lookupPhi = iop.NewPolynomial(&phi, iop.Form{Basis: iop.Lagrange, Layout: iop.Regular})
+	if s.proof.LookupPhi, err = s.commitToPolyAndBlinding(s.lookupPhi, s.bp[id_Bphi]); err != nil {
+		return err
+	}
+	s.lookupPhi.ToCanonical(&s.pk.Domain[0]).ToRegular()
+	s.lookupM.ToCanonical(&s.pk.Domain[0]).ToRegular()
+
+	// t = ∑ᵢβⁱTᵢ, in canonical form
+	t := make([]fr.Element, n)
+	for i := nb_lookup_columns - 1; i >= 0; i-- {
+		ti := s.pk.trace.T[i].Coefficients()
+		for j := range t {
+			t[j].Mul(&t[j], &s.beta).Add(&t[j], &ti[j])
+		}
+	}
+	s.lookupT = iop.NewPolynomial(&t, iop.Form{Basis: iop.Canonical, Layout: iop.Regular})
+
+	close(s.chLookupPhi)
+	return nil
+}
+
 func (s *instance) buildRatioCopyConstraint() (err error) {
 	// wait for gamma and beta to be derived (or ctx.Done())
 	select {
@@ -632,7 +862,7 @@ func (s *instance) buildRatioCopyConstraint() (err error) {
 	return
 }
 
-// open Z (blinded) at ωζ
+// open Z and Φ (blinded) at ωζ
 func (s *instance) openZ() (err error) {
 	// wait for H to be committed and zeta to be derived (or ctx.Done())
 	select {
@@ -648,6 +878,13 @@ func (s *instance) openZ() (err error) {
 	if err != nil {
 		return err
 	}
+	if s.hasLookup() {
+		s.blindedPhi = getBlindedCoefficients(s.lookupPhi, s.bp[id_Bphi])
+		s.proof.LookupPhiShiftedOpening, err = kzg.Open(s.blindedPhi, zetaShifted, s.pk.Kzg)
+		if err != nil {
+			return err
+		}
+	}
 	close(s.chZOpening)
 	return nil
 }
@@ -715,6 +952,7 @@ func (s *instance) computeLinearizedPolynomial() error {
 	}
 
 	qcpzeta := make([]fr.Element, len(s.commitmentInfo))
+	cgzeta := make([]fr.Element, len(s.pk.Vk.CustomGates))
 	var blzeta, brzeta, bozeta fr.Element
 	var wg sync.WaitGroup
 	wg.Add(3 + len(s.commitmentInfo))
@@ -751,6 +989,10 @@ func (s *instance) computeLinearizedPolynomial() error {
 
 	wg.Wait()
 
+	for i := range cgzeta {
+		cgzeta[i] = s.pk.Vk.CustomGates[i].Evaluate(blzeta, brzeta)
+	}
+
 	s.linearizedPolynomial = computeLinearizedPolynomial(
 		blzeta,
 		brzeta,
@@ -761,13 +1003,14 @@ func (s *instance) computeLinearizedPolynomial() error {
 		s.zeta,
 		bzuzeta,
 		qcpzeta,
+		cgzeta,
 		s.blindedZ,
 		coefficients(s.cCommitments),
 		s.pk,
 	)
 
 	var err error
-	s.linearizedPolynomialDigest, err = kzg.Commit(s.linearizedPolynomial, s.pk.Kzg, runtime.NumCPU()*2)
+	s.linearizedPolynomialDigest, err = kzgCommit(s.linearizedPolynomial, s.pk.Kzg, runtime.NumCPU()*2)
 	if err != nil {
 		return err
 	}
@@ -820,6 +1063,23 @@ func (s *instance) batchOpening() error {
 	digestsToOpen[5] = s.pk.Vk.S[0]
 	digestsToOpen[6] = s.pk.Vk.S[1]
 
+	dataTranscript := s.proof.ZShiftedOpening.ClaimedValue.Marshal()
+	if s.hasLookup() {
+		lookupDigest, err := lookupTableDigest(s.pk.Vk, s.beta)
+		if err != nil {
+			return err
+		}
+		polysToOpen = append(polysToOpen,
+			s.blindedPhi,
+			getBlindedCoefficients(s.lookupM, s.bp[id_Bm]),
+			s.pk.trace.Qlk.Coefficients(),
+			s.pk.trace.Qtag.Coefficients(),
+			s.lookupT.Coefficients(),
+		)
+		digestsToOpen = append(digestsToOpen, s.proof.LookupPhi, s.proof.LookupM, s.pk.Vk.Qlk, s.pk.Vk.Qtag, lookupDigest)
+		dataTranscript = append(dataTranscript, s.proof.LookupPhiShiftedOpening.ClaimedValue.Marshal()...)
+	}
+
 	var err error
 	s.proof.BatchedProof, err = kzg.BatchOpenSinglePoint(
 		polysToOpen,
@@ -827,7 +1087,7 @@ func (s *instance) batchOpening() error {
 		s.zeta,
 		s.kzgFoldingHash,
 		s.pk.Kzg,
-		s.proof.ZShiftedOpening.ClaimedValue.Marshal(),
+		dataTranscript,
 	)
 
 	return err
@@ -838,7 +1098,11 @@ func (s *instance) batchOpening() error {
 func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 	n := s.pk.Domain[0].Cardinality
 
-	nbBsbGates := (len(s.x) - id_Qci + 1) >> 1
+	nbBsbGates := len(s.commitmentInfo)
+	idQcg := s.idQcg()
+	customGates := s.pk.Vk.CustomGates
+	idLookup := s.idLookup()
+	hasLookup := s.hasLookup()
 
 	gateConstraint := func(u ...fr.Element) fr.Element {
 
@@ -855,6 +1119,11 @@ func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 			tmp.Mul(&u[id_Qci+2*i], &u[id_Qci+2*i+1])
 			ic.Add(&ic, &tmp)
 		}
+		for i := range customGates {
+			tmp = customGates[i].Evaluate(u[id_L], u[id_R])
+			tmp.Mul(&tmp, &u[idQcg+i])
+			ic.Add(&ic, &tmp)
+		}
 
 		return ic
 	}
@@ -942,6 +1211,21 @@ func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 		a := gateConstraint(u...)
 		b := orderingConstraint(u...)
 		c := ratioLocalConstraint(u...)
+		if hasLookup {
+			// blind Φ, ΦS, M
+			y = s.bp[id_Bphi].Evaluate(s.twiddles0[i])
+			u[idLookup+lk_Phi].Add(&u[idLookup+lk_Phi], &y)
+			y = s.bp[id_Bphi].Evaluate(s.twiddles0[(i+1)%int(n)])
+			u[idLookup+lk_PhiS].Add(&u[idLookup+lk_PhiS], &y)
+			y = s.bp[id_Bm].Evaluate(s.twiddles0[i])
+			u[idLookup+lk_M].Add(&u[idLookup+lk_M], &y)
+
+			d := lookupConstraint(u[id_L], u[id_R], u[id_O],
+				u[idLookup+lk_Phi], u[idLookup+lk_PhiS], u[idLookup+lk_M],
+				u[idLookup+lk_Qlk], u[idLookup+lk_Qtag], u[idLookup+lk_T],
+				s.beta, s.gamma)
+			c.Add(&c, d.Mul(&d, &s.alpha))
+		}
 		c.Mul(&c, &s.alpha).Add(&c, &b).Mul(&c, &s.alpha).Add(&c, &a)
 		return c
 	}
@@ -1193,17 +1477,17 @@ func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, kzgPk kzg.ProvingKe
 	g := new(errgroup.Group)
 
 	g.Go(func() (err error) {
-		proof.H[0], err = kzg.Commit(h1, kzgPk)
+		proof.H[0], err = kzgCommit(h1, kzgPk)
 		return
 	})
 
 	g.Go(func() (err error) {
-		proof.H[1], err = kzg.Commit(h2, kzgPk)
+		proof.H[1], err = kzgCommit(h2, kzgPk)
 		return
 	})
 
 	g.Go(func() (err error) {
-		proof.H[2], err = kzg.Commit(h3, kzgPk)
+		proof.H[2], err = kzgCommit(h3, kzgPk)
 		return
 	})
 
@@ -1278,8 +1562,10 @@ func evaluateXnMinusOneDomainBigCoset(domains [2]*fft.Domain) []fr.Element {
 //
 // α²*L₁(ζ)*Z(X)
 // + α*( (l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*Z(μζ)*s3(X) - Z(X)*(l(ζ)+β*id1(ζ)+γ)*(r(ζ)+β*id2(ζ)+γ)*(o(ζ)+β*id3(ζ)+γ))
-// + l(ζ)*Ql(X) + l(ζ)r(ζ)*Qm(X) + r(ζ)*Qr(X) + o(ζ)*Qo(X) + Qk(X)
-func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, qcpZeta, blindedZCanonical []fr.Element, pi2Canonical [][]fr.Element, pk *ProvingKey) []fr.Element {
+// + l(ζ)*Ql(X) + l(ζ)r(ζ)*Qm(X) + r(ζ)*Qr(X) + o(ζ)*Qo(X) + Qk(X) + ∑ᵢPᵢ(l(ζ), r(ζ))*Qcgᵢ(X)
+//
+// where Pᵢ is the polynomial of the i-th custom gate.
+func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, qcpZeta, cgZeta, blindedZCanonical []fr.Element, pi2Canonical [][]fr.Element, pk *ProvingKey) []fr.Element {
 
 	// first part: individual constraints
 	var rl fr.Element
@@ -1335,6 +1621,7 @@ func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta,
 		cqm := pk.trace.Qm.Coefficients()
 		cqo := pk.trace.Qo.Coefficients()
 		cqk := pk.trace.Qk.Coefficients()
+		cqcg := coefficients(pk.trace.Qcg)
 
 		var t, t0, t1 fr.Element
 
@@ -1372,6 +1659,11 @@ func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta,
 					t0.Mul(&pi2Canonical[j][i], &qcpZeta[j])
 					t.Add(&t, &t0)
 				}
+
+				for j := range cgZeta {
+					t0.Mul(&cqcg[j][i], &cgZeta[j])
+					t.Add(&t, &t0) // linPol = linPol + Pⱼ(l(ζ), r(ζ))*Qcgⱼ(X)
+				}
 			}
 
 			t0.Mul(&blindedZCanonical[i], &lagrangeZeta)
@@ -1382,3 +1674,20 @@ func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta,
 }
 
 var errContextDone = errors.New("context done")
+
+// kzgCommit is kzg.Commit, with the registered accelerator if any.
+func kzgCommit(p []fr.Element, pk kzg.ProvingKey, nbTasks ...int) (kzg.Digest, error) {
+	if accelerator.Enabled && len(p) != 0 && len(p) <= len(pk.G1) {
+		var res curve.G1Jac
+		ok, err := accelerator.MultiExp(curve.ID, accelerator.G1, &res, pk.G1[:len(p)], p, func(res, partial *curve.G1Jac) { res.AddAssign(partial) })
+		if err != nil {
+			return kzg.Digest{}, err
+		}
+		if ok {
+			var digest kzg.Digest
+			digest.FromJacobian(&res)
+			return digest, nil
+		}
+	}
+	return kzg.Commit(p, pk, nbTasks...)
+}
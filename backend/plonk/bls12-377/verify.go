@@ -53,6 +53,17 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	if len(proof.Bsb22Commitments) != len(vk.Qcp) {
 		return errors.New("BSB22 Commitment number mismatch")
 	}
+	if len(vk.Qcg) != len(vk.CustomGates) {
+		return errors.New("custom gate number mismatch")
+	}
+	hasLookup := vk.NbLookupTables != 0
+	nbClaimedValues := 7 + len(vk.Qcp)
+	if hasLookup {
+		nbClaimedValues += nb_lookup_openings
+	}
+	if len(proof.BatchedProof.ClaimedValues) != nbClaimedValues {
+		return errors.New("wrong number of claimed values")
+	}
 
 	// transcript to derive the challenge
 	fs := fiatshamir.NewTranscript(cfg.ChallengeHash, "gamma", "beta", "alpha", "zeta")
@@ -63,7 +74,11 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	if err := bindPublicData(&fs, "gamma", vk, publicWitness); err != nil {
 		return err
 	}
-	gamma, err := deriveRandomness(&fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
+	gammaDeps := []*curve.G1Affine{&proof.LRO[0], &proof.LRO[1], &proof.LRO[2]}
+	if hasLookup {
+		gammaDeps = append(gammaDeps, &proof.LookupM)
+	}
+	gamma, err := deriveRandomness(&fs, "gamma", gammaDeps...)
 	if err != nil {
 		return err
 	}
@@ -74,12 +89,15 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		return err
 	}
 
-	// derive alpha from Comm(l), Comm(r), Comm(o), Com(Z), Bsb22Commitments
+	// derive alpha from Comm(l), Comm(r), Comm(o), Com(Z), Bsb22Commitments, Comm(Φ)
 	alphaDeps := make([]*curve.G1Affine, len(proof.Bsb22Commitments)+1)
 	for i := range proof.Bsb22Commitments {
 		alphaDeps[i] = &proof.Bsb22Commitments[i]
 	}
 	alphaDeps[len(alphaDeps)-1] = &proof.Z
+	if hasLookup {
+		alphaDeps = append(alphaDeps, &proof.LookupPhi)
+	}
 	alpha, err := deriveRandomness(&fs, "alpha", alphaDeps...)
 	if err != nil {
 		return err
@@ -185,6 +203,19 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		Add(&linearizedPolynomialZeta, &_s1).                // linearizedpolynomial+pi(zeta)+α*(Z(μζ))*(l(ζ)+s1(ζ)+γ)*(r(ζ)+s2(ζ)+γ)*(o(ζ)+γ)
 		Sub(&linearizedPolynomialZeta, &alphaSquareLagrange) // linearizedpolynomial+pi(zeta)+α*(Z(μζ))*(l(ζ)+s1(ζ)+γ)*(r(ζ)+s2(ζ)+γ)*(o(ζ)+γ)-α²*L₁(ζ)
 
+	// lookup argument: + α³*lk(ζ)
+	var lookupDigest kzg.Digest
+	if hasLookup {
+		lv := proof.BatchedProof.ClaimedValues[7+len(vk.Qcp):]
+		lk := lookupConstraint(l, r, o, lv[lk_Phi], proof.LookupPhiShiftedOpening.ClaimedValue, lv[lk_M], lv[lk_Qlk], lv[lk_Qtag], lv[lk_T], beta, gamma)
+		lk.Mul(&lk, &alpha).Mul(&lk, &alpha).Mul(&lk, &alpha)
+		linearizedPolynomialZeta.Add(&linearizedPolynomialZeta, &lk)
+
+		if lookupDigest, err = lookupTableDigest(vk, beta); err != nil {
+			return err
+		}
+	}
+
 	// Compute H(ζ) using the previous result: H(ζ) = prev_result/(ζⁿ-1)
 	var zetaPowerMMinusOne fr.Element
 	zetaPowerMMinusOne.Sub(&zetaPowerM, &one)
@@ -209,7 +240,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 
 	// Compute the commitment to the linearized polynomial
 	// linearizedPolynomialDigest =
-	// 		l(ζ)*ql+r(ζ)*qr+r(ζ)l(ζ)*qm+o(ζ)*qo+qk+Σᵢqc'ᵢ(ζ)*BsbCommitmentᵢ +
+	// 		l(ζ)*ql+r(ζ)*qr+r(ζ)l(ζ)*qm+o(ζ)*qo+qk+Σᵢqc'ᵢ(ζ)*BsbCommitmentᵢ+ΣᵢPᵢ(l(ζ), r(ζ))*qcgᵢ +
 	// 		α*( Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*s₃(X)-Z(X)(l(ζ)+β*id_1(ζ)+γ)*(r(ζ)+β*id_2(ζ)+γ)*(o(ζ)+β*id_3(ζ)+γ) ) +
 	// 		α²*L₁(ζ)*Z
 	// first part: individual constraints
@@ -246,6 +277,13 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		l, r, rl, o, one, /* TODO Perf @Tabaie Consider just adding Qk instead */ // first part
 		_s1, _s2, // second & third part
 	)
+
+	// custom gates
+	points = append(points, vk.Qcg...)
+	for i := range vk.CustomGates {
+		scalars = append(scalars, vk.CustomGates[i].Evaluate(l, r))
+	}
+
 	if _, err := linearizedPolynomialDigest.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
 		return err
 	}
@@ -260,12 +298,17 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	digestsToFold[4] = proof.LRO[2]
 	digestsToFold[5] = vk.S[0]
 	digestsToFold[6] = vk.S[1]
+	dataTranscript := zu.Marshal()
+	if hasLookup {
+		digestsToFold = append(digestsToFold, proof.LookupPhi, proof.LookupM, vk.Qlk, vk.Qtag, lookupDigest)
+		dataTranscript = append(dataTranscript, proof.LookupPhiShiftedOpening.ClaimedValue.Marshal()...)
+	}
 	foldedProof, foldedDigest, err := kzg.FoldProof(
 		digestsToFold,
 		&proof.BatchedProof,
 		zeta,
 		cfg.KZGFoldingHash,
-		zu.Marshal(),
+		dataTranscript,
 	)
 	if err != nil {
 		return err
@@ -274,20 +317,15 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
-	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
-		foldedDigest,
-		proof.Z,
-	},
-		[]kzg.OpeningProof{
-			foldedProof,
-			proof.ZShiftedOpening,
-		},
-		[]fr.Element{
-			zeta,
-			shiftedZeta,
-		},
-		vk.Kzg,
-	)
+	digestsToVerify := []kzg.Digest{foldedDigest, proof.Z}
+	openingProofs := []kzg.OpeningProof{foldedProof, proof.ZShiftedOpening}
+	openingPoints := []fr.Element{zeta, shiftedZeta}
+	if hasLookup {
+		digestsToVerify = append(digestsToVerify, proof.LookupPhi)
+		openingProofs = append(openingProofs, proof.LookupPhiShiftedOpening)
+		openingPoints = append(openingPoints, shiftedZeta)
+	}
+	err = kzg.BatchVerifyMultiPoints(digestsToVerify, openingProofs, openingPoints, vk.Kzg)
 
 	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
 
@@ -328,6 +366,26 @@ func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk *VerifyingKe
 			return err
 		}
 	}
+	for i := range vk.Qcg {
+		if err := fs.Bind(challenge, vk.Qcg[i].Marshal()); err != nil {
+			return err
+		}
+	}
+
+	// lookup tables
+	if vk.NbLookupTables != 0 {
+		if err := fs.Bind(challenge, vk.Qlk.Marshal()); err != nil {
+			return err
+		}
+		if err := fs.Bind(challenge, vk.Qtag.Marshal()); err != nil {
+			return err
+		}
+		for i := range vk.T {
+			if err := fs.Bind(challenge, vk.T[i].Marshal()); err != nil {
+				return err
+			}
+		}
+	}
 
 	// public inputs
 	for i := 0; i < len(publicInputs); i++ {
@@ -340,6 +398,42 @@ func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk *VerifyingKe
 
 }
 
+// lookupConstraint returns the constraint of the lookup argument
+//
+//	(Φ(μX)-Φ(X))*(γ+f(X))*(γ+t(X)) - qlk(X)*(γ+t(X)) + M(X)*(γ+f(X))
+//
+// where f = l+β*r+β²*o+β³*qtag, evaluated at a point from the evaluations of
+// the polynomials. t is the combination ∑ᵢβⁱ*Tᵢ of the columns of the lookup
+// tables, see [lookupTableDigest].
+func lookupConstraint(l, r, o, phi, phiShifted, m, qlk, qtag, t, beta, gamma fr.Element) fr.Element {
+	var f, res, tmp fr.Element
+	f.Mul(&qtag, &beta).Add(&f, &o).
+		Mul(&f, &beta).Add(&f, &r).
+		Mul(&f, &beta).Add(&f, &l).
+		Add(&f, &gamma) // γ+f
+	t.Add(&t, &gamma) // γ+t
+
+	res.Sub(&phiShifted, &phi).Mul(&res, &f).Mul(&res, &t)
+	tmp.Mul(&qlk, &t)
+	res.Sub(&res, &tmp)
+	tmp.Mul(&m, &f)
+	res.Add(&res, &tmp)
+	return res
+}
+
+// lookupTableDigest returns the commitment to ∑ᵢβⁱ*Tᵢ, where Tᵢ are the
+// columns of the lookup tables.
+func lookupTableDigest(vk *VerifyingKey, beta fr.Element) (kzg.Digest, error) {
+	var res kzg.Digest
+	scalars := make([]fr.Element, len(vk.T))
+	scalars[0].SetOne()
+	for i := 1; i < len(scalars); i++ {
+		scalars[i].Mul(&scalars[i-1], &beta)
+	}
+	_, err := res.MultiExp(vk.T[:], scalars, ecc.MultiExpConfig{})
+	return res, err
+}
+
 func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*curve.G1Affine) (fr.Element, error) {
 
 	var buf [curve.SizeOfG1AffineUncompressed]byte
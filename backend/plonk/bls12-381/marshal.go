@@ -53,6 +53,10 @@ func (proof *Proof) writeTo(w io.Writer, options ...func(*curve.Encoder)) (int64
 		&proof.ZShiftedOpening.H,
 		&proof.ZShiftedOpening.ClaimedValue,
 		proof.Bsb22Commitments,
+		&proof.LookupM,
+		&proof.LookupPhi,
+		&proof.LookupPhiShiftedOpening.H,
+		&proof.LookupPhiShiftedOpening.ClaimedValue,
 	}
 
 	for _, v := range toEncode {
@@ -80,6 +84,10 @@ func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
 		&proof.ZShiftedOpening.H,
 		&proof.ZShiftedOpening.ClaimedValue,
 		&proof.Bsb22Commitments,
+		&proof.LookupM,
+		&proof.LookupPhi,
+		&proof.LookupPhiShiftedOpening.H,
+		&proof.LookupPhiShiftedOpening.ClaimedValue,
 	}
 
 	for _, v := range toDecode {
@@ -169,6 +177,8 @@ func (pk *ProvingKey) writeTo(w io.Writer, withCompression bool) (n int64, err e
 		pk.trace.S2.Coefficients(),
 		pk.trace.S3.Coefficients(),
 		pk.trace.S,
+		coefficients(pk.trace.Qcg),
+		coefficients(pk.trace.lookupPolynomials()),
 	}
 
 	for _, v := range toEncode {
@@ -233,7 +243,7 @@ func (pk *ProvingKey) readFrom(r io.Reader, withSubgroupChecks bool) (int64, err
 	dec := curve.NewDecoder(r)
 
 	var ql, qr, qm, qo, qk, s1, s2, s3 []fr.Element
-	var qcp [][]fr.Element
+	var qcp, qcg, lookup [][]fr.Element
 
 	// TODO @gbotrel: this is a bit ugly, we should probably refactor this.
 	// The order of the variables is important, as it matches the order in which they are
@@ -288,6 +298,19 @@ func (pk *ProvingKey) readFrom(r io.Reader, withSubgroupChecks bool) (int64, err
 		return n + dec.BytesRead(), err
 	}
 
+	// read qcg
+	if err := dec.Decode(&qcg); err != nil {
+		return n + dec.BytesRead(), err
+	}
+
+	// read qlk, qtag, t
+	if err := dec.Decode(&lookup); err != nil {
+		return n + dec.BytesRead(), err
+	}
+	if len(lookup) != 0 && len(lookup) != 2+nb_lookup_columns {
+		return n + dec.BytesRead(), errors.New("invalid lookup polynomials encoding")
+	}
+
 	// wait for all AsyncReadFrom(...) to complete
 	for i := range vectors {
 		if err := <-vectors[i].chErr; err != nil {
@@ -310,6 +333,19 @@ func (pk *ProvingKey) readFrom(r io.Reader, withSubgroupChecks bool) (int64, err
 		pk.trace.Qcp[i] = iop.NewPolynomial(&qcp[i], canReg)
 	}
 
+	pk.trace.Qcg = make([]*iop.Polynomial, len(qcg))
+	for i := range qcg {
+		pk.trace.Qcg[i] = iop.NewPolynomial(&qcg[i], canReg)
+	}
+
+	if len(lookup) != 0 {
+		pk.trace.Qlk = iop.NewPolynomial(&lookup[0], canReg)
+		pk.trace.Qtag = iop.NewPolynomial(&lookup[1], canReg)
+		for i := range pk.trace.T {
+			pk.trace.T[i] = iop.NewPolynomial(&lookup[2+i], canReg)
+		}
+	}
+
 	// wait for FFT to be precomputed
 	<-chDomain0
 	<-chDomain1
@@ -331,6 +367,15 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
 func (vk *VerifyingKey) writeTo(w io.Writer, options ...func(*curve.Encoder)) (n int64, err error) {
 	enc := curve.NewEncoder(w)
 
+	cgCoeffs := make([][]fr.Element, len(vk.CustomGates))
+	cgA := make([][]uint64, len(vk.CustomGates))
+	cgB := make([][]uint64, len(vk.CustomGates))
+	for i := range vk.CustomGates {
+		cgCoeffs[i] = vk.CustomGates[i].Coeffs
+		cgA[i] = vk.CustomGates[i].A
+		cgB[i] = vk.CustomGates[i].B
+	}
+
 	toEncode := []interface{}{
 		vk.Size,
 		&vk.SizeInv,
@@ -350,6 +395,16 @@ func (vk *VerifyingKey) writeTo(w io.Writer, options ...func(*curve.Encoder)) (n
 		&vk.Kzg.G2[0],
 		&vk.Kzg.G2[1],
 		vk.CommitmentConstraintIndexes,
+		vk.Qcg,
+		cgCoeffs,
+		cgA,
+		cgB,
+		vk.NbLookupTables,
+		&vk.Qlk,
+		&vk.Qtag,
+	}
+	for i := range vk.T {
+		toEncode = append(toEncode, &vk.T[i])
 	}
 
 	for _, v := range toEncode {
@@ -370,6 +425,8 @@ func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 // ReadFrom reads from binary representation in r into VerifyingKey
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	dec := curve.NewDecoder(r)
+	var cgCoeffs [][]fr.Element
+	var cgA, cgB [][]uint64
 	toDecode := []interface{}{
 		&vk.Size,
 		&vk.SizeInv,
@@ -389,6 +446,16 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		&vk.Kzg.G2[0],
 		&vk.Kzg.G2[1],
 		&vk.CommitmentConstraintIndexes,
+		&vk.Qcg,
+		&cgCoeffs,
+		&cgA,
+		&cgB,
+		&vk.NbLookupTables,
+		&vk.Qlk,
+		&vk.Qtag,
+	}
+	for i := range vk.T {
+		toDecode = append(toDecode, &vk.T[i])
 	}
 
 	for _, v := range toDecode {
@@ -400,6 +467,20 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	if vk.Qcp == nil {
 		vk.Qcp = []kzg.Digest{}
 	}
+	if vk.Qcg == nil {
+		vk.Qcg = []kzg.Digest{}
+	}
+
+	if len(cgA) != len(cgCoeffs) || len(cgB) != len(cgCoeffs) {
+		return dec.BytesRead(), errors.New("invalid custom gates encoding")
+	}
+	vk.CustomGates = make([]CustomGate, len(cgCoeffs))
+	for i := range cgCoeffs {
+		if len(cgA[i]) != len(cgCoeffs[i]) || len(cgB[i]) != len(cgCoeffs[i]) {
+			return dec.BytesRead(), errors.New("invalid custom gates encoding")
+		}
+		vk.CustomGates[i] = CustomGate{Coeffs: cgCoeffs[i], A: cgA[i], B: cgB[i]}
+	}
 
 	return dec.BytesRead(), nil
 }
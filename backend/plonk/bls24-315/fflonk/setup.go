@@ -0,0 +1,318 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package fflonk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	cs "github.com/consensys/gnark/constraint/bls24-315"
+	"math/big"
+)
+
+// VerifyingKey stores the data needed to verify a proof:
+// * The sizes of the circuit and the generator of its domain
+// * The commitment scheme
+// * The commitment to C₀, that combines ql, qr, qo, qm, qk, s1, s2, s3
+type VerifyingKey struct {
+	// Size circuit
+	Size              uint64
+	SizeInv           fr.Element
+	Generator         fr.Element
+	NbPublicVariables uint64
+
+	// GeneratorCubeRoot is the cube root of Generator in the subgroup it
+	// generates, used to open C₂ at the cube roots of ζω.
+	GeneratorCubeRoot fr.Element
+
+	// Commitment scheme that is used for an instantiation of fflonk
+	Kzg kzg.VerifyingKey
+
+	// cosetShift generator of the coset on the small domain
+	CosetShift fr.Element
+
+	// C0 is the commitment to C₀ = ∑ᵢ Xⁱ⋅fᵢ(X⁸), where the fᵢ are ql, qr, qo,
+	// qm, qk, s1, s2, s3 in this order, with ql prepended with as many ones
+	// as there are public inputs (qk with zeroes).
+	C0 kzg.Digest
+}
+
+// ProvingKey stores the data needed to generate a proof:
+// * the commitment scheme
+// * the verifying key
+// * ql, qr, qo, qm, qk (incomplete), s1, s2, s3 in canonical form
+// * the copy constraint permutation
+type ProvingKey struct {
+	Kzg kzg.ProvingKey
+
+	// Verifying Key is embedded into the proving key (needed by Prove)
+	Vk *VerifyingKey
+
+	// Domains used for the FFTs.
+	// Domain[0] = small Domain
+	// Domain[1] = big Domain, on which the quotients are computed
+	Domain [2]fft.Domain
+
+	// Trace stores ql, qr, qo, qm, qk, s1, s2, s3 in canonical form. qk is
+	// to be completed by the prover with the public inputs.
+	Trace [nb_preprocessed][]fr.Element
+
+	// Permutation position -> permuted position (position in [0,3*Size-1])
+	Permutation []int64
+}
+
+// indices of the preprocessed polynomials in C₀
+const (
+	id_Ql int = iota
+	id_Qr
+	id_Qo
+	id_Qm
+	id_Qk
+	id_S1
+	id_S2
+	id_S3
+	nb_preprocessed
+)
+
+// Setup computes the preprocessed polynomials of the circuit and commits to
+// them, combined in C₀. The SRS must have at least [SRSSize] points.
+//
+// Circuits using custom gates, lookup tables, GKR or commitments (BSB22) are
+// not supported.
+func Setup(spr *cs.SparseR1CS, kzgSrs kzg.SRS) (*ProvingKey, *VerifyingKey, error) {
+	switch {
+	case len(spr.CommitmentInfo.CommitmentIndexes()) != 0:
+		return nil, nil, errors.New("commitments are not supported by fflonk")
+	case len(spr.GetCustomGates()) != 0:
+		return nil, nil, errors.New("custom gates are not supported by fflonk")
+	case len(spr.GetLookupTables()) != 0:
+		return nil, nil, errors.New("lookup tables are not supported by fflonk")
+	case spr.GkrInfo.Is():
+		return nil, nil, errors.New("gkr is not supported by fflonk")
+	}
+
+	var pk ProvingKey
+	var vk VerifyingKey
+	pk.Vk = &vk
+
+	// step 0: set the fft domains
+	sizeSystem := uint64(spr.GetNbConstraints() + len(spr.Public))
+	pk.Domain[0] = *fft.NewDomain(sizeSystem)
+	if pk.Domain[0].Cardinality < 2 {
+		return nil, nil, fmt.Errorf("circuit has only %d constraints; unsupported by the current implementation", spr.GetNbConstraints())
+	}
+	pk.Domain[1] = *fft.NewDomain(8 * pk.Domain[0].Cardinality)
+
+	// step 1: set the verifying key
+	vk.CosetShift.Set(&pk.Domain[0].FrMultiplicativeGen)
+	vk.Size = pk.Domain[0].Cardinality
+	vk.SizeInv.SetUint64(vk.Size).Inverse(&vk.SizeInv)
+	vk.Generator.Set(&pk.Domain[0].Generator)
+	vk.NbPublicVariables = uint64(len(spr.Public))
+
+	// ω^(1/3) = ω^(3⁻¹ mod n), as n is a power of 2
+	e := new(big.Int).ModInverse(big.NewInt(3), new(big.Int).SetUint64(vk.Size))
+	vk.GeneratorCubeRoot.Exp(vk.Generator, e)
+
+	nbG1 := SRSSize(int(sizeSystem))
+	if len(kzgSrs.Pk.G1) < nbG1 {
+		return nil, nil, fmt.Errorf("kzg srs is too small: got %d, need %d", len(kzgSrs.Pk.G1), nbG1)
+	}
+	pk.Kzg.G1 = kzgSrs.Pk.G1[:nbG1]
+	vk.Kzg = kzgSrs.Vk
+
+	// step 2: ql, qr, qo, qm, qk in Lagrange form, and the permutation
+	buildTrace(spr, &pk)
+	nbVariables := spr.NbInternalVariables + len(spr.Public) + len(spr.Secret)
+	buildPermutation(spr, &pk, nbVariables)
+	computePermutationPolynomials(&pk)
+
+	// step 3: commit to C₀
+	for i := range pk.Trace {
+		pk.Domain[0].FFTInverse(pk.Trace[i], fft.DIF)
+		fft.BitReverse(pk.Trace[i])
+	}
+	var err error
+	if vk.C0, err = kzg.Commit(combine(pk.Trace[:]), pk.Kzg); err != nil {
+		return nil, nil, err
+	}
+
+	return &pk, &vk, nil
+}
+
+// SRSSize returns the number of G1 points of the SRS needed to prove a system
+// of sizeSystem constraints (including the public inputs). The largest
+// polynomial is C₂, which combines 3 polynomials of degree at most 3n+5 where
+// n is the size of the domain.
+func SRSSize(sizeSystem int) int {
+	n := int(ecc.NextPowerOfTwo(uint64(sizeSystem)))
+	return 3 * (3*n + 6)
+}
+
+// NbPublicWitness returns the expected public witness size (number of field elements)
+func (vk *VerifyingKey) NbPublicWitness() int {
+	return int(vk.NbPublicVariables)
+}
+
+// VerifyingKey returns pk.Vk
+func (pk *ProvingKey) VerifyingKey() interface{} {
+	return pk.Vk
+}
+
+// combine returns ∑ᵢ Xⁱ⋅pᵢ(Xᵗ), where t = len(p).
+func combine(p [][]fr.Element) []fr.Element {
+	t := len(p)
+	size := 0
+	for i := range p {
+		if len(p[i]) > size {
+			size = len(p[i])
+		}
+	}
+	res := make([]fr.Element, t*size)
+	for i := range p {
+		for j := range p[i] {
+			res[t*j+i].Set(&p[i][j])
+		}
+	}
+	return res
+}
+
+// buildTrace fills ql, qr, qo, qm, qk in Lagrange form. The first rows
+// are the placeholders of the public inputs: -PUB_INPUT_i + qk_i = 0, where qk_i
+// is completed by the prover.
+func buildTrace(spr *cs.SparseR1CS, pk *ProvingKey) {
+	n := pk.Domain[0].Cardinality
+	for i := range pk.Trace {
+		pk.Trace[i] = make([]fr.Element, n)
+	}
+	ql, qr, qo, qm, qk := pk.Trace[id_Ql], pk.Trace[id_Qr], pk.Trace[id_Qo], pk.Trace[id_Qm], pk.Trace[id_Qk]
+
+	for i := 0; i < len(spr.Public); i++ {
+		ql[i].SetOne().Neg(&ql[i])
+	}
+	offset := len(spr.Public)
+
+	j := 0
+	it := spr.GetSparseR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		ql[offset+j].Set(&spr.Coefficients[c.QL])
+		qr[offset+j].Set(&spr.Coefficients[c.QR])
+		qm[offset+j].Set(&spr.Coefficients[c.QM])
+		qo[offset+j].Set(&spr.Coefficients[c.QO])
+		qk[offset+j].Set(&spr.Coefficients[c.QC])
+		j++
+	}
+}
+
+// buildPermutation builds the Permutation associated with a circuit.
+//
+// The permutation s is composed of cycles of maximum length such that
+//
+//	s. (l∥r∥o) = (l∥r∥o)
+//
+// , where l∥r∥o is the concatenation of the indices of l, r, o in
+// ql.l+qr.r+qm.l.r+qo.O+k = 0.
+func buildPermutation(spr *cs.SparseR1CS, pk *ProvingKey, nbVariables int) {
+
+	sizeSolution := int(pk.Domain[0].Cardinality)
+	sizePermutation := 3 * sizeSolution
+
+	// init permutation
+	permutation := make([]int64, sizePermutation)
+	for i := 0; i < len(permutation); i++ {
+		permutation[i] = -1
+	}
+
+	// init LRO position -> variable_ID
+	lro := make([]int, sizePermutation) // position -> variable_ID
+	for i := 0; i < len(spr.Public); i++ {
+		lro[i] = i // IDs of LRO associated to placeholders (only L needs to be taken care of)
+	}
+
+	offset := len(spr.Public)
+
+	j := 0
+	it := spr.GetSparseR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		lro[offset+j] = int(c.XA)
+		lro[sizeSolution+offset+j] = int(c.XB)
+		lro[2*sizeSolution+offset+j] = int(c.XC)
+
+		j++
+	}
+
+	// init cycle:
+	// map ID -> last position the ID was seen
+	cycle := make([]int64, nbVariables)
+	for i := 0; i < len(cycle); i++ {
+		cycle[i] = -1
+	}
+
+	for i := 0; i < len(lro); i++ {
+		if cycle[lro[i]] != -1 {
+			// if != -1, it means we already encountered this value
+			// so we need to set the corresponding permutation index.
+			permutation[i] = cycle[lro[i]]
+		}
+		cycle[lro[i]] = int64(i)
+	}
+
+	// complete the Permutation by filling the first IDs encountered
+	for i := 0; i < sizePermutation; i++ {
+		if permutation[i] == -1 {
+			permutation[i] = cycle[lro[i]]
+		}
+	}
+
+	pk.Permutation = permutation
+}
+
+// computePermutationPolynomials computes s1, s2, s3 in Lagrange form. We
+// let the permutation act on <g> || u<g> || u^{2}<g>, split the result in 3
+// parts, and interpolate each of the 3 parts on <g>.
+func computePermutationPolynomials(pk *ProvingKey) {
+	n := int(pk.Domain[0].Cardinality)
+	id := getSupportPermutation(&pk.Domain[0])
+	for i := 0; i < 3; i++ {
+		s := pk.Trace[id_S1+i]
+		for j := 0; j < n; j++ {
+			s[j].Set(&id[pk.Permutation[i*n+j]])
+		}
+	}
+}
+
+// getSupportPermutation returns the support on which the permutation acts, it is
+// <g> || u<g> || u^{2}<g>
+func getSupportPermutation(domain *fft.Domain) []fr.Element {
+
+	res := make([]fr.Element, 3*domain.Cardinality)
+
+	res[0].SetOne()
+	res[domain.Cardinality].Set(&domain.FrMultiplicativeGen)
+	res[2*domain.Cardinality].Square(&domain.FrMultiplicativeGen)
+
+	for i := uint64(1); i < domain.Cardinality; i++ {
+		res[i].Mul(&res[i-1], &domain.Generator)
+		res[domain.Cardinality+i].Mul(&res[domain.Cardinality+i-1], &domain.Generator)
+		res[2*domain.Cardinality+i].Mul(&res[2*domain.Cardinality+i-1], &domain.Generator)
+	}
+
+	return res
+}
@@ -0,0 +1,464 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package fflonk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/iop"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	cs "github.com/consensys/gnark/constraint/bls24-317"
+	"github.com/consensys/gnark/logger"
+	"math/big"
+	"time"
+)
+
+// Proof represents a fflonk proof generated by Prove.
+//
+// Instead of committing to each polynomial of PLONK, the prover commits to
+// the combinations C₁ = a(X⁴) + X⋅b(X⁴) + X²⋅c(X⁴) + X³⋅T₀(X⁴) and
+// C₂ = z(X³) + X⋅T₁(X³) + X²⋅T₂(X³), where T₀, T₁, T₂ are the quotients of
+// the gate constraint, of z(1) = 1 and of the permutation constraint. Opening
+// C₀, C₁, C₂ at the 8-th, 4-th and 3-rd roots of ζ (and of ζω for C₂)
+// amounts to opening each polynomial at ζ (and at ζω), and the openings are
+// batched in W, W' with SHPLONK, which the verifier checks with a single
+// pairing.
+type Proof struct {
+	// Commitments to C₁ and C₂
+	C1, C2 kzg.Digest
+
+	// Commitments to W and W' of the batch opening
+	W, WPrime kzg.Digest
+
+	// Values at ζ of ql, qr, qo, qm, qk (incomplete), s1, s2, s3, in the order
+	// of C₀
+	Preprocessed [nb_preprocessed]fr.Element
+
+	// Values at ζ of a, b, c, z
+	LROZ [4]fr.Element
+
+	// Values at ζω of z, T₁, T₂
+	Shifted [3]fr.Element
+}
+
+// blinding orders
+const (
+	order_blinding_LRO = 2
+	order_blinding_Z   = 3
+)
+
+// Prove generates a fflonk proof from a circuit, its proving key and the full
+// witness.
+func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+
+	log := logger.Logger().With().
+		Str("curve", spr.CurveID().String()).
+		Int("nbConstraints", spr.GetNbConstraints()).
+		Str("backend", "fflonk").Logger()
+
+	// parse the options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("get prover options: %w", err)
+	}
+
+	start := time.Now()
+
+	// solve the constraints
+	_solution, err := spr.Solve(fullWitness, opt.SolverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	solution := _solution.(*cs.SparseR1CSSolution)
+	wWitness, ok := fullWitness.Vector().(fr.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+	public := wWitness[:len(spr.Public)]
+
+	var proof Proof
+	domain := &pk.Domain[0]
+	n := int(domain.Cardinality)
+	fs := fiatshamir.NewTranscript(opt.ChallengeHash, "gamma", "beta", "xi", "alpha", "y")
+	if err := bindPublicData(&fs, "gamma", pk.Vk, public); err != nil {
+		return nil, err
+	}
+
+	// round 1: a, b, c and the quotient T₀ of the gate constraint
+	// ql⋅a + qr⋅b + qo⋅c + qm⋅a⋅b + qk + PI
+	var lro [3][]fr.Element
+	for i, v := range [3]fr.Vector{solution.L, solution.R, solution.O} {
+		if lro[i], err = blind(v, domain, order_blinding_LRO); err != nil {
+			return nil, err
+		}
+	}
+	pi := make([]fr.Element, n)
+	copy(pi, public)
+	domain.FFTInverse(pi, fft.DIF)
+	fft.BitReverse(pi)
+	t0, err := computeQuotientGate(pk, lro, pi)
+	if err != nil {
+		return nil, err
+	}
+	c1 := combine([][]fr.Element{lro[0], lro[1], lro[2], t0})
+	if proof.C1, err = kzg.Commit(c1, pk.Kzg); err != nil {
+		return nil, err
+	}
+	gamma, err := deriveRandomness(&fs, "gamma", &proof.C1)
+	if err != nil {
+		return nil, err
+	}
+	bbeta, err := fs.ComputeChallenge("beta")
+	if err != nil {
+		return nil, err
+	}
+	var beta fr.Element
+	beta.SetBytes(bbeta)
+
+	// round 2: z and the quotients T₁, T₂ of the permutation argument
+	lagReg := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
+	l, r, o := []fr.Element(solution.L), []fr.Element(solution.R), []fr.Element(solution.O)
+	ratio, err := iop.BuildRatioCopyConstraint(
+		[]*iop.Polynomial{
+			iop.NewPolynomial(&l, lagReg),
+			iop.NewPolynomial(&r, lagReg),
+			iop.NewPolynomial(&o, lagReg),
+		},
+		pk.Permutation,
+		beta,
+		gamma,
+		lagReg,
+		domain,
+	)
+	if err != nil {
+		return nil, err
+	}
+	z, err := blind(ratio.Coefficients(), domain, order_blinding_Z)
+	if err != nil {
+		return nil, err
+	}
+	t1, t2, err := computeQuotientPermutation(pk, lro, z, beta, gamma)
+	if err != nil {
+		return nil, err
+	}
+	c2 := combine([][]fr.Element{z, t1, t2})
+	if proof.C2, err = kzg.Commit(c2, pk.Kzg); err != nil {
+		return nil, err
+	}
+	xi, err := deriveRandomness(&fs, "xi", &proof.C2)
+	if err != nil {
+		return nil, err
+	}
+
+	// round 3: evaluations at ζ = ξ²⁴ and ζω
+	var zeta, zetaShifted fr.Element
+	zeta.Exp(xi, big.NewInt(24))
+	zetaShifted.Mul(&zeta, &pk.Vk.Generator)
+	for i := range pk.Trace {
+		proof.Preprocessed[i] = eval(pk.Trace[i], zeta)
+	}
+	for i := range lro {
+		proof.LROZ[i] = eval(lro[i], zeta)
+	}
+	proof.LROZ[3] = eval(z, zeta)
+	for i, p := range [][]fr.Element{z, t1, t2} {
+		proof.Shifted[i] = eval(p, zetaShifted)
+	}
+	if err := bindEvaluations(&fs, "alpha", &proof); err != nil {
+		return nil, err
+	}
+	alpha, err := deriveRandomness(&fs, "alpha")
+	if err != nil {
+		return nil, err
+	}
+
+	// round 4: W = ∑ᵢ αⁱ⋅(Cᵢ - rᵢ)/Z_Sᵢ, where Z_S₀ = X⁸-ζ, Z_S₁ = X⁴-ζ,
+	// Z_S₂ = (X³-ζ)(X³-ζω) and rᵢ is the remainder of Cᵢ modulo Z_Sᵢ.
+	c0 := combine(pk.Trace[:])
+	q0, r0 := divideByXkMinusC(c0, 8, zeta)
+	q1, r1 := divideByXkMinusC(c1, 4, zeta)
+	q2, r2a := divideByXkMinusC(c2, 3, zeta)
+	q2, r2b := divideByXkMinusC(q2, 3, zetaShifted)
+	w := make([]fr.Element, len(q2)) // q₂ is the longest quotient
+	var alphaSquare fr.Element
+	alphaSquare.Square(&alpha)
+	axpy(w, q0, fr.One())
+	axpy(w, q1, alpha)
+	axpy(w, q2, alphaSquare)
+	if proof.W, err = kzg.Commit(w, pk.Kzg); err != nil {
+		return nil, err
+	}
+	y, err := deriveRandomness(&fs, "y", &proof.W)
+	if err != nil {
+		return nil, err
+	}
+
+	// round 5: W' = L/(X-y), where
+	// L = C₀ - r₀(y) + α⋅Z_S₀(y)/Z_S₁(y)⋅(C₁ - r₁(y)) + α²⋅Z_S₀(y)/Z_S₂(y)⋅(C₂ - r₂(y)) - Z_S₀(y)⋅W
+	// vanishes at y.
+	zs := vanishingSets(y, zeta, zetaShifted)
+	var y3, tmp fr.Element
+	y3.Exp(y, big.NewInt(3))
+	r2Y := eval(r2b, y)
+	tmp.Sub(&y3, &zeta)
+	r2Y.Mul(&r2Y, &tmp)
+	tmp = eval(r2a, y)
+	r2Y.Add(&r2Y, &tmp)
+	var coeffs [3]fr.Element
+	coeffs[0].SetOne()
+	coeffs[1].Div(&zs[0], &zs[1]).Mul(&coeffs[1], &alpha)
+	coeffs[2].Div(&zs[0], &zs[2]).Mul(&coeffs[2], &alphaSquare)
+	rY := [3]fr.Element{eval(r0, y), eval(r1, y), r2Y}
+
+	lY := make([]fr.Element, len(c2)) // C₂ is the longest polynomial
+	for i, p := range [][]fr.Element{c0, c1, c2} {
+		axpy(lY, p, coeffs[i])
+		tmp.Mul(&coeffs[i], &rY[i])
+		lY[0].Sub(&lY[0], &tmp)
+	}
+	tmp.Neg(&zs[0])
+	axpy(lY, w, tmp)
+	wPrime, _ := divideByXkMinusC(lY, 1, y)
+	if proof.WPrime, err = kzg.Commit(wPrime, pk.Kzg); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
+
+	return &proof, nil
+}
+
+// blind returns the canonical form of the polynomial whose evaluations on the
+// domain are evals, plus a random polynomial of degree nbBlinding-1 times the
+// vanishing polynomial of the domain.
+func blind(evals []fr.Element, domain *fft.Domain, nbBlinding int) ([]fr.Element, error) {
+	n := len(evals)
+	res := make([]fr.Element, n+nbBlinding)
+	copy(res, evals)
+	domain.FFTInverse(res[:n], fft.DIF)
+	fft.BitReverse(res[:n])
+	var r fr.Element
+	for i := 0; i < nbBlinding; i++ {
+		if _, err := r.SetRandom(); err != nil {
+			return nil, err
+		}
+		res[i].Sub(&res[i], &r)
+		res[n+i].Add(&res[n+i], &r)
+	}
+	return res, nil
+}
+
+// eval returns p(x), p being in canonical form.
+func eval(p []fr.Element, x fr.Element) fr.Element {
+	return (*polynomial.Polynomial)(&p).Eval(&x)
+}
+
+// axpy sets res += a⋅p, res must be at least as long as p.
+func axpy(res, p []fr.Element, a fr.Element) {
+	var t fr.Element
+	for i := range p {
+		t.Mul(&p[i], &a)
+		res[i].Add(&res[i], &t)
+	}
+}
+
+// divideByXkMinusC returns the quotient and the remainder of the division of p
+// by Xᵏ-c.
+func divideByXkMinusC(p []fr.Element, k int, c fr.Element) (q, r []fr.Element) {
+	r = make([]fr.Element, k)
+	if len(p) <= k {
+		copy(r, p)
+		return nil, r
+	}
+	q = make([]fr.Element, len(p)-k)
+	var t fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		t.Set(&p[i])
+		if i < len(q) {
+			var cq fr.Element
+			cq.Mul(&c, &q[i])
+			t.Add(&t, &cq)
+		}
+		if i >= k {
+			q[i-k] = t
+		} else {
+			r[i] = t
+		}
+	}
+	return q, r
+}
+
+// vanishingSets returns the evaluations at y of the vanishing polynomials
+// X⁸-ζ, X⁴-ζ, (X³-ζ)(X³-ζω) of the sets on which C₀, C₁, C₂ are opened.
+func vanishingSets(y, zeta, zetaShifted fr.Element) [3]fr.Element {
+	var res [3]fr.Element
+	var y3, y4, t fr.Element
+	y3.Square(&y).Mul(&y3, &y)
+	y4.Square(&y).Square(&y4)
+	res[1].Sub(&y4, &zeta)
+	res[0].Square(&y4).Sub(&res[0], &zeta)
+	res[2].Sub(&y3, &zeta)
+	t.Sub(&y3, &zetaShifted)
+	res[2].Mul(&res[2], &t)
+	return res
+}
+
+// evaluateOnCoset returns the evaluations of p on the coset of the domain, in
+// natural order.
+func evaluateOnCoset(p []fr.Element, domain *fft.Domain) []fr.Element {
+	res := make([]fr.Element, domain.Cardinality)
+	copy(res, p)
+	domain.FFT(res, fft.DIF, fft.OnCoset())
+	fft.BitReverse(res)
+	return res
+}
+
+// interpolateOnCoset returns the canonical form of the polynomial whose
+// evaluations on the coset of the domain are evals, in natural order, checking
+// that it has less than size coefficients. evals is modified.
+func interpolateOnCoset(evals []fr.Element, domain *fft.Domain, size int) ([]fr.Element, error) {
+	domain.FFTInverse(evals, fft.DIF, fft.OnCoset())
+	fft.BitReverse(evals)
+	for i := size; i < len(evals); i++ {
+		if !evals[i].IsZero() {
+			return nil, errors.New("the witness does not satisfy the constraints")
+		}
+	}
+	return evals[:size], nil
+}
+
+// vanishingOnCosetInv returns the inverses of the vanishing polynomial of the
+// small domain on the coset of the big domain. They are periodic, of period
+// |big domain|/|small domain|.
+func vanishingOnCosetInv(pk *ProvingKey) []fr.Element {
+	n := pk.Domain[0].Cardinality
+	bigDomain := &pk.Domain[1]
+	res := make([]fr.Element, bigDomain.Cardinality/n)
+	var c, g fr.Element
+	c.Exp(bigDomain.FrMultiplicativeGen, new(big.Int).SetUint64(n))
+	g.Exp(bigDomain.Generator, new(big.Int).SetUint64(n))
+	one := fr.One()
+	for i := range res {
+		res[i].Sub(&c, &one)
+		c.Mul(&c, &g)
+	}
+	return fr.BatchInvert(res)
+}
+
+// computeQuotientGate returns the quotient T₀ of
+// ql⋅a + qr⋅b + qo⋅c + qm⋅a⋅b + qk + PI by the vanishing polynomial.
+func computeQuotientGate(pk *ProvingKey, lro [3][]fr.Element, pi []fr.Element) ([]fr.Element, error) {
+	n := int(pk.Domain[0].Cardinality)
+	bigDomain := &pk.Domain[1]
+	rho := int(bigDomain.Cardinality) / n
+
+	var q [id_Qk + 1][]fr.Element
+	for i := range q {
+		q[i] = evaluateOnCoset(pk.Trace[i], bigDomain)
+	}
+	_a := evaluateOnCoset(lro[0], bigDomain)
+	_b := evaluateOnCoset(lro[1], bigDomain)
+	_c := evaluateOnCoset(lro[2], bigDomain)
+	_pi := evaluateOnCoset(pi, bigDomain)
+	vInv := vanishingOnCosetInv(pk)
+
+	res := make([]fr.Element, bigDomain.Cardinality)
+	for i := range res {
+		var t fr.Element
+		res[i].Mul(&q[id_Ql][i], &_a[i])
+		t.Mul(&q[id_Qr][i], &_b[i])
+		res[i].Add(&res[i], &t)
+		t.Mul(&q[id_Qo][i], &_c[i])
+		res[i].Add(&res[i], &t)
+		t.Mul(&q[id_Qm][i], &_a[i]).Mul(&t, &_b[i])
+		res[i].Add(&res[i], &t).
+			Add(&res[i], &q[id_Qk][i]).
+			Add(&res[i], &_pi[i]).
+			Mul(&res[i], &vInv[i%rho])
+	}
+
+	// the numerator is of degree 3n+1, the quotient of degree 2n+1
+	return interpolateOnCoset(res, bigDomain, 2*n+2)
+}
+
+// computeQuotientPermutation returns the quotients T₁ of L₁⋅(z-1) and T₂ of
+//
+//	z(X)⋅(a+β⋅X+γ)(b+β⋅u⋅X+γ)(c+β⋅u²⋅X+γ) - z(ωX)⋅(a+β⋅s1+γ)(b+β⋅s2+γ)(c+β⋅s3+γ)
+//
+// by the vanishing polynomial.
+func computeQuotientPermutation(pk *ProvingKey, lro [3][]fr.Element, z []fr.Element, beta, gamma fr.Element) (t1, t2 []fr.Element, err error) {
+	n := int(pk.Domain[0].Cardinality)
+	bigDomain := &pk.Domain[1]
+	size := int(bigDomain.Cardinality)
+	rho := size / n
+
+	var _lro, _s [3][]fr.Element
+	for i := range lro {
+		_lro[i] = evaluateOnCoset(lro[i], bigDomain)
+		_s[i] = evaluateOnCoset(pk.Trace[id_S1+i], bigDomain)
+	}
+	_z := evaluateOnCoset(z, bigDomain)
+
+	// L₁ = (1/n)⋅∑ᵢ Xⁱ
+	l1 := make([]fr.Element, n)
+	for i := range l1 {
+		l1[i].Set(&pk.Domain[0].CardinalityInv)
+	}
+	_l1 := evaluateOnCoset(l1, bigDomain)
+	vInv := vanishingOnCosetInv(pk)
+
+	var shifts [3]fr.Element
+	shifts[0].Set(&beta)
+	shifts[1].Mul(&beta, &pk.Vk.CosetShift)
+	shifts[2].Mul(&shifts[1], &pk.Vk.CosetShift)
+
+	_t1 := make([]fr.Element, size)
+	_t2 := make([]fr.Element, size)
+	one := fr.One()
+	var x fr.Element
+	x.Set(&bigDomain.FrMultiplicativeGen)
+	for i := 0; i < size; i++ {
+		_t1[i].Sub(&_z[i], &one).Mul(&_t1[i], &_l1[i]).Mul(&_t1[i], &vInv[i%rho])
+
+		var num, den, t fr.Element
+		num.Set(&_z[i])
+		den.Set(&_z[(i+rho)%size])
+		for j := range _lro {
+			t.Mul(&shifts[j], &x).Add(&t, &_lro[j][i]).Add(&t, &gamma)
+			num.Mul(&num, &t)
+			t.Mul(&beta, &_s[j][i]).Add(&t, &_lro[j][i]).Add(&t, &gamma)
+			den.Mul(&den, &t)
+		}
+		_t2[i].Sub(&num, &den).Mul(&_t2[i], &vInv[i%rho])
+		x.Mul(&x, &bigDomain.Generator)
+	}
+
+	// L₁⋅(z-1) is of degree 2n+1, the permutation constraint of degree 4n+5
+	if t1, err = interpolateOnCoset(_t1, bigDomain, n+2); err != nil {
+		return nil, nil, err
+	}
+	if t2, err = interpolateOnCoset(_t2, bigDomain, 3*n+6); err != nil {
+		return nil, nil, err
+	}
+	return t1, t2, nil
+}
@@ -96,6 +96,23 @@ func (pk *ProvingKey) randomize() {
 		pk.trace.Qcp[i] = iop.NewPolynomial(&qcp, canReg)
 	}
 
+	pk.trace.Qcg = make([]*iop.Polynomial, rand.Intn(4)) //#nosec G404 weak rng is fine here
+	for i := range pk.trace.Qcg {
+		qcg := randomScalars(n)
+		pk.trace.Qcg[i] = iop.NewPolynomial(&qcg, canReg)
+	}
+
+	if rand.Intn(2) == 1 { //#nosec G404 weak rng is fine here
+		qlk := randomScalars(n)
+		qtag := randomScalars(n)
+		pk.trace.Qlk = iop.NewPolynomial(&qlk, canReg)
+		pk.trace.Qtag = iop.NewPolynomial(&qtag, canReg)
+		for i := range pk.trace.T {
+			t := randomScalars(n)
+			pk.trace.T[i] = iop.NewPolynomial(&t, canReg)
+		}
+	}
+
 	pk.trace.S = make([]int64, 3*pk.Domain[0].Cardinality)
 	pk.trace.S[0] = -12
 	pk.trace.S[len(pk.trace.S)-1] = 8888
@@ -124,6 +141,26 @@ func (vk *VerifyingKey) randomize() {
 	vk.Qo = randomG1Point()
 	vk.Qk = randomG1Point()
 	vk.Qcp = randomG1Points(rand.Intn(4)) //#nosec G404 weak rng is fine here
+	vk.Qcg = randomG1Points(rand.Intn(4)) //#nosec G404 weak rng is fine here
+	vk.CustomGates = make([]CustomGate, len(vk.Qcg))
+	for i := range vk.CustomGates {
+		nbTerms := 1 + rand.Intn(4) //#nosec G404 weak rng is fine here
+		vk.CustomGates[i] = CustomGate{
+			Coeffs: randomScalars(nbTerms),
+			A:      make([]uint64, nbTerms),
+			B:      make([]uint64, nbTerms),
+		}
+		for j := 0; j < nbTerms; j++ {
+			vk.CustomGates[i].A[j] = uint64(rand.Intn(4)) //#nosec G404 weak rng is fine here
+			vk.CustomGates[i].B[j] = uint64(rand.Intn(4)) //#nosec G404 weak rng is fine here
+		}
+	}
+	vk.NbLookupTables = uint64(rand.Intn(4)) //#nosec G404 weak rng is fine here
+	vk.Qlk = randomG1Point()
+	vk.Qtag = randomG1Point()
+	for i := range vk.T {
+		vk.T[i] = randomG1Point()
+	}
 }
 
 func (proof *Proof) randomize() {
@@ -139,6 +176,10 @@ func (proof *Proof) randomize() {
 	proof.ZShiftedOpening.H = randomG1Point()
 	proof.ZShiftedOpening.ClaimedValue.SetRandom()
 	proof.Bsb22Commitments = randomG1Points(rand.Intn(4)) //#nosec G404 weak rng is fine here
+	proof.LookupM = randomG1Point()
+	proof.LookupPhi = randomG1Point()
+	proof.LookupPhiShiftedOpening.H = randomG1Point()
+	proof.LookupPhiShiftedOpening.ClaimedValue.SetRandom()
 }
 
 func randomG2Point() curve.G2Affine {
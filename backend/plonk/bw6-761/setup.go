@@ -56,6 +56,48 @@ type VerifyingKey struct {
 	Qcp                []kzg.Digest
 
 	CommitmentConstraintIndexes []uint64
+
+	// Commitments to the selectors of the custom gates, and the polynomials of
+	// the custom gates.
+	Qcg         []kzg.Digest
+	CustomGates []CustomGate
+
+	// Commitments to the selector of the lookups, to the tags of the lookups
+	// and to the columns of the concatenated lookup tables, see [Trace]. They
+	// are set only if NbLookupTables != 0.
+	NbLookupTables uint64
+	Qlk, Qtag      kzg.Digest
+	T              [nb_lookup_columns]kzg.Digest
+}
+
+// nb_lookup_columns is the number of columns of the concatenated lookup tables:
+// the columns matched against l, r, o, and the tags of the rows.
+const nb_lookup_columns = constraint.MaxLookupTableColumns + 1
+
+// CustomGate is the polynomial
+//
+//	P(l, r) = ∑ᵢ Coeffs[i]⋅lᴬ⁽ⁱ⁾⋅rᴮ⁽ⁱ⁾
+//
+// of a custom gate, see [constraint.CustomGate].
+type CustomGate struct {
+	Coeffs []fr.Element
+	A, B   []uint64
+}
+
+// Evaluate returns P(l, r).
+func (g *CustomGate) Evaluate(l, r fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := range g.Coeffs {
+		t.Set(&g.Coeffs[i])
+		for j := uint64(0); j < g.A[i]; j++ {
+			t.Mul(&t, &l)
+		}
+		for j := uint64(0); j < g.B[i]; j++ {
+			t.Mul(&t, &r)
+		}
+		res.Add(&res, &t)
+	}
+	return res
 }
 
 // Trace stores a plonk trace as columns
@@ -68,6 +110,16 @@ type Trace struct {
 	Ql, Qr, Qm, Qo, Qk *iop.Polynomial
 	Qcp                []*iop.Polynomial
 
+	// Qcg are the selectors of the custom gates.
+	Qcg []*iop.Polynomial
+
+	// Qlk is the selector of the lookups and Qtag the tag (1 + index of the
+	// table) of each lookup. T are the columns of the concatenated lookup
+	// tables, T[nb_lookup_columns-1] storing the tag of each row, and 0 on the
+	// padding rows. They are nil if the circuit has no lookup table.
+	Qlk, Qtag *iop.Polynomial
+	T         [nb_lookup_columns]*iop.Polynomial
+
 	// Polynomials representing the splitted permutation. The full permutation's support is 3*N where N=nb wires.
 	// The set of interpolation is <g> of size N, so to represent the permutation S we let S acts on the
 	// set A=(<g>, u*<g>, u^{2}*<g>) of size 3*N, where u is outside <g> (its use is to shift the set <g>).
@@ -137,6 +189,39 @@ func Setup(spr *cs.SparseR1CS, kzgSrs kzg.SRS) (*ProvingKey, *VerifyingKey, erro
 	}
 	vk.Kzg = kzgSrs.Vk
 
+	// custom gates
+	customGates := spr.GetCustomGates()
+	vk.CustomGates = make([]CustomGate, len(customGates))
+	for i := range customGates {
+		if err := customGates[i].Check(); err != nil {
+			return nil, nil, err
+		}
+		terms := customGates[i].Terms
+		vk.CustomGates[i] = CustomGate{
+			Coeffs: make([]fr.Element, len(terms)),
+			A:      make([]uint64, len(terms)),
+			B:      make([]uint64, len(terms)),
+		}
+		for j, t := range terms {
+			vk.CustomGates[i].Coeffs[j].Set(&spr.Coefficients[t.Coeff])
+			vk.CustomGates[i].A[j] = uint64(t.A)
+			vk.CustomGates[i].B[j] = uint64(t.B)
+		}
+	}
+
+	// lookup tables
+	nbRows := 0
+	for _, t := range spr.GetLookupTables() {
+		if err := t.Check(); err != nil {
+			return nil, nil, err
+		}
+		nbRows += t.NbRows()
+	}
+	if uint64(nbRows) > vk.Size {
+		return nil, nil, fmt.Errorf("lookup tables have %d rows, more than the size %d of the domain", nbRows, vk.Size)
+	}
+	vk.NbLookupTables = uint64(len(spr.GetLookupTables()))
+
 	// step 2: ql, qr, qm, qo, qk, qcp in Lagrange Basis
 	BuildTrace(spr, &pk.trace)
 
@@ -170,7 +255,8 @@ func (pk *ProvingKey) VerifyingKey() interface{} {
 	return pk.Vk
 }
 
-// BuildTrace fills the constant columns ql, qr, qm, qo, qk from the sparser1cs.
+// BuildTrace fills the constant columns ql, qr, qm, qo, qk, the selectors of
+// the custom gates and the columns of the lookup argument from the sparser1cs.
 // Size is the size of the system that is nb_constraints+nb_public_variables
 func BuildTrace(spr *cs.SparseR1CS, pt *Trace) {
 
@@ -185,6 +271,16 @@ func BuildTrace(spr *cs.SparseR1CS, pt *Trace) {
 	qo := make([]fr.Element, size)
 	qk := make([]fr.Element, size)
 	qcp := make([][]fr.Element, len(commitmentInfo))
+	qcg := make([][]fr.Element, len(spr.GetCustomGates()))
+	for i := range qcg {
+		qcg[i] = make([]fr.Element, size)
+	}
+	tables := spr.GetLookupTables()
+	var qlk, qtag []fr.Element
+	if len(tables) != 0 {
+		qlk = make([]fr.Element, size)
+		qtag = make([]fr.Element, size)
+	}
 
 	for i := 0; i < len(spr.Public); i++ { // placeholders (-PUB_INPUT_i + qk_i = 0) TODO should return error if size is inconsistent
 		ql[i].SetOne().Neg(&ql[i])
@@ -203,6 +299,13 @@ func BuildTrace(spr *cs.SparseR1CS, pt *Trace) {
 		qm[offset+j].Set(&spr.Coefficients[c.QM])
 		qo[offset+j].Set(&spr.Coefficients[c.QO])
 		qk[offset+j].Set(&spr.Coefficients[c.QC])
+		if c.CustomGate != 0 {
+			qcg[c.CustomGate-1][offset+j].SetOne()
+		}
+		if c.Lookup != 0 {
+			qlk[offset+j].SetOne()
+			qtag[offset+j].SetUint64(uint64(c.Lookup))
+		}
 		j++
 	}
 
@@ -222,6 +325,43 @@ func BuildTrace(spr *cs.SparseR1CS, pt *Trace) {
 		}
 		pt.Qcp[i] = iop.NewPolynomial(&qcp[i], lagReg)
 	}
+
+	pt.Qcg = make([]*iop.Polynomial, len(qcg))
+	for i := range qcg {
+		pt.Qcg[i] = iop.NewPolynomial(&qcg[i], lagReg)
+	}
+
+	if len(tables) == 0 {
+		return
+	}
+	pt.Qlk = iop.NewPolynomial(&qlk, lagReg)
+	pt.Qtag = iop.NewPolynomial(&qtag, lagReg)
+	var t [nb_lookup_columns][]fr.Element
+	for i := range t {
+		t[i] = make([]fr.Element, size)
+	}
+	row := 0
+	for i := range tables {
+		for k := 0; k < tables[i].NbRows(); k++ {
+			for col := range tables[i].Columns {
+				t[col][row].Set(&spr.Coefficients[tables[i].Columns[col][k]])
+			}
+			t[nb_lookup_columns-1][row].SetUint64(uint64(i + 1))
+			row++
+		}
+	}
+	for i := range t {
+		pt.T[i] = iop.NewPolynomial(&t[i], lagReg)
+	}
+}
+
+// lookupPolynomials returns the polynomials of the lookup argument in the
+// trace, Qlk, Qtag and T, or nil if the circuit has no lookup table.
+func (pt *Trace) lookupPolynomials() []*iop.Polynomial {
+	if pt.Qlk == nil {
+		return nil
+	}
+	return append([]*iop.Polynomial{pt.Qlk, pt.Qtag}, pt.T[:]...)
 }
 
 // commitTrace commits to every polynomial in the trace, and put
@@ -245,6 +385,24 @@ func commitTrace(trace *Trace, pk *ProvingKey) error {
 			return err
 		}
 	}
+	pk.Vk.Qcg = make([]kzg.Digest, len(trace.Qcg))
+	for i := range trace.Qcg {
+		trace.Qcg[i].ToCanonical(&pk.Domain[0]).ToRegular()
+		if pk.Vk.Qcg[i], err = kzg.Commit(pk.trace.Qcg[i].Coefficients(), pk.Kzg); err != nil {
+			return err
+		}
+	}
+	if lookup := trace.lookupPolynomials(); lookup != nil {
+		digests := make([]kzg.Digest, len(lookup))
+		for i := range lookup {
+			lookup[i].ToCanonical(&pk.Domain[0]).ToRegular()
+			if digests[i], err = kzg.Commit(lookup[i].Coefficients(), pk.Kzg); err != nil {
+				return err
+			}
+		}
+		pk.Vk.Qlk, pk.Vk.Qtag = digests[0], digests[1]
+		copy(pk.Vk.T[:], digests[2:])
+	}
 	if pk.Vk.Ql, err = kzg.Commit(pk.trace.Ql.Coefficients(), pk.Kzg); err != nil {
 		return err
 	}
@@ -0,0 +1,323 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fflonk implements the fflonk variant of the PLONK Zero Knowledge
+// Proof system.
+//
+// fflonk consumes the same SparseR1CS as PLONK, but the prover commits to a
+// few combinations of the PLONK polynomials instead of committing to each of
+// them. The proof is made of 4 G1 points and the verifier performs a single
+// multi-scalar multiplication and a single pairing check, which makes
+// on-chain verification cheaper than PLONK at the cost of a slower prover
+// and a larger SRS (see [SRSSize]).
+//
+// Circuits using custom gates, lookup tables, GKR or commitments
+// (frontend.Committer) are not supported.
+//
+// # See also
+//
+// https://eprint.iacr.org/2021/1167
+package fflonk
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/consensys/gnark/backend/witness"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+
+	fflonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377/fflonk"
+	fflonk_bls12381 "github.com/consensys/gnark/backend/plonk/bls12-381/fflonk"
+	fflonk_bls24315 "github.com/consensys/gnark/backend/plonk/bls24-315/fflonk"
+	fflonk_bls24317 "github.com/consensys/gnark/backend/plonk/bls24-317/fflonk"
+	fflonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254/fflonk"
+	fflonk_bw6633 "github.com/consensys/gnark/backend/plonk/bw6-633/fflonk"
+	fflonk_bw6761 "github.com/consensys/gnark/backend/plonk/bw6-761/fflonk"
+
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/kzg"
+	kzg_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	kzg_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/kzg"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
+
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// Proof represents a fflonk proof generated by fflonk.Prove
+//
+// it's underlying implementation is curve specific (see gnark/backend/plonk)
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+}
+
+// ProvingKey represents a fflonk ProvingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/plonk)
+type ProvingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	VerifyingKey() interface{}
+}
+
+// VerifyingKey represents a fflonk VerifyingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/plonk)
+type VerifyingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	NbPublicWitness() int // number of elements expected in the public witness
+}
+
+// Setup prepares the public data associated to a circuit + public inputs. The
+// SRS must have at least [SRSSize] points.
+func Setup(ccs constraint.ConstraintSystem, kzgSrs kzg.SRS) (ProvingKey, VerifyingKey, error) {
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.SparseR1CS:
+		return fflonk_bn254.Setup(tccs, *kzgSrs.(*kzg_bn254.SRS))
+	case *cs_bls12377.SparseR1CS:
+		return fflonk_bls12377.Setup(tccs, *kzgSrs.(*kzg_bls12377.SRS))
+	case *cs_bls12381.SparseR1CS:
+		return fflonk_bls12381.Setup(tccs, *kzgSrs.(*kzg_bls12381.SRS))
+	case *cs_bw6761.SparseR1CS:
+		return fflonk_bw6761.Setup(tccs, *kzgSrs.(*kzg_bw6761.SRS))
+	case *cs_bls24317.SparseR1CS:
+		return fflonk_bls24317.Setup(tccs, *kzgSrs.(*kzg_bls24317.SRS))
+	case *cs_bls24315.SparseR1CS:
+		return fflonk_bls24315.Setup(tccs, *kzgSrs.(*kzg_bls24315.SRS))
+	case *cs_bw6633.SparseR1CS:
+		return fflonk_bw6633.Setup(tccs, *kzgSrs.(*kzg_bw6633.SRS))
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+
+}
+
+// Prove generates a fflonk proof from a circuit, its proving key and the full
+// witness.
+func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.SparseR1CS:
+		return fflonk_bn254.Prove(tccs, pk.(*fflonk_bn254.ProvingKey), fullWitness, opts...)
+
+	case *cs_bls12377.SparseR1CS:
+		return fflonk_bls12377.Prove(tccs, pk.(*fflonk_bls12377.ProvingKey), fullWitness, opts...)
+
+	case *cs_bls12381.SparseR1CS:
+		return fflonk_bls12381.Prove(tccs, pk.(*fflonk_bls12381.ProvingKey), fullWitness, opts...)
+
+	case *cs_bw6761.SparseR1CS:
+		return fflonk_bw6761.Prove(tccs, pk.(*fflonk_bw6761.ProvingKey), fullWitness, opts...)
+
+	case *cs_bls24317.SparseR1CS:
+		return fflonk_bls24317.Prove(tccs, pk.(*fflonk_bls24317.ProvingKey), fullWitness, opts...)
+
+	case *cs_bls24315.SparseR1CS:
+		return fflonk_bls24315.Prove(tccs, pk.(*fflonk_bls24315.ProvingKey), fullWitness, opts...)
+
+	case *cs_bw6633.SparseR1CS:
+		return fflonk_bw6633.Prove(tccs, pk.(*fflonk_bw6633.ProvingKey), fullWitness, opts...)
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+}
+
+// Verify verifies a fflonk proof, from the proof, the verifying key and the
+// public witness.
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+
+	switch _proof := proof.(type) {
+
+	case *fflonk_bn254.Proof:
+		w, ok := publicWitness.Vector().(fr_bn254.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return fflonk_bn254.Verify(_proof, vk.(*fflonk_bn254.VerifyingKey), w, opts...)
+
+	case *fflonk_bls12377.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12377.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return fflonk_bls12377.Verify(_proof, vk.(*fflonk_bls12377.VerifyingKey), w, opts...)
+
+	case *fflonk_bls12381.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return fflonk_bls12381.Verify(_proof, vk.(*fflonk_bls12381.VerifyingKey), w, opts...)
+
+	case *fflonk_bw6761.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return fflonk_bw6761.Verify(_proof, vk.(*fflonk_bw6761.VerifyingKey), w, opts...)
+
+	case *fflonk_bls24317.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24317.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return fflonk_bls24317.Verify(_proof, vk.(*fflonk_bls24317.VerifyingKey), w, opts...)
+
+	case *fflonk_bls24315.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return fflonk_bls24315.Verify(_proof, vk.(*fflonk_bls24315.VerifyingKey), w, opts...)
+
+	case *fflonk_bw6633.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6633.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		return fflonk_bw6633.Verify(_proof, vk.(*fflonk_bw6633.VerifyingKey), w, opts...)
+	default:
+		panic("unrecognized proof type")
+	}
+}
+
+// SRSSize returns the number of G1 points of the KZG SRS needed by [Setup] for
+// the given SparseR1CS.
+func SRSSize(ccs constraint.ConstraintSystem) int {
+	sizeSystem := ccs.GetNbConstraints() + ccs.GetNbPublicVariables()
+
+	switch ccs.(type) {
+	case *cs_bn254.SparseR1CS:
+		return fflonk_bn254.SRSSize(sizeSystem)
+	case *cs_bls12377.SparseR1CS:
+		return fflonk_bls12377.SRSSize(sizeSystem)
+	case *cs_bls12381.SparseR1CS:
+		return fflonk_bls12381.SRSSize(sizeSystem)
+	case *cs_bw6761.SparseR1CS:
+		return fflonk_bw6761.SRSSize(sizeSystem)
+	case *cs_bls24317.SparseR1CS:
+		return fflonk_bls24317.SRSSize(sizeSystem)
+	case *cs_bls24315.SparseR1CS:
+		return fflonk_bls24315.SRSSize(sizeSystem)
+	case *cs_bw6633.SparseR1CS:
+		return fflonk_bw6633.SRSSize(sizeSystem)
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+}
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
+// This function exists for serialization purposes
+func NewProvingKey(curveID ecc.ID) ProvingKey {
+	var pk ProvingKey
+	switch curveID {
+	case ecc.BN254:
+		pk = &fflonk_bn254.ProvingKey{}
+	case ecc.BLS12_377:
+		pk = &fflonk_bls12377.ProvingKey{}
+	case ecc.BLS12_381:
+		pk = &fflonk_bls12381.ProvingKey{}
+	case ecc.BW6_761:
+		pk = &fflonk_bw6761.ProvingKey{}
+	case ecc.BLS24_317:
+		pk = &fflonk_bls24317.ProvingKey{}
+	case ecc.BLS24_315:
+		pk = &fflonk_bls24315.ProvingKey{}
+	case ecc.BW6_633:
+		pk = &fflonk_bw6633.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pk
+}
+
+// NewProof instantiates a curve-typed Proof and returns an interface
+// This function exists for serialization purposes
+func NewProof(curveID ecc.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case ecc.BN254:
+		proof = &fflonk_bn254.Proof{}
+	case ecc.BLS12_377:
+		proof = &fflonk_bls12377.Proof{}
+	case ecc.BLS12_381:
+		proof = &fflonk_bls12381.Proof{}
+	case ecc.BW6_761:
+		proof = &fflonk_bw6761.Proof{}
+	case ecc.BLS24_317:
+		proof = &fflonk_bls24317.Proof{}
+	case ecc.BLS24_315:
+		proof = &fflonk_bls24315.Proof{}
+	case ecc.BW6_633:
+		proof = &fflonk_bw6633.Proof{}
+	default:
+		panic("not implemented")
+	}
+
+	return proof
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var vk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		vk = &fflonk_bn254.VerifyingKey{}
+	case ecc.BLS12_377:
+		vk = &fflonk_bls12377.VerifyingKey{}
+	case ecc.BLS12_381:
+		vk = &fflonk_bls12381.VerifyingKey{}
+	case ecc.BW6_761:
+		vk = &fflonk_bw6761.VerifyingKey{}
+	case ecc.BLS24_317:
+		vk = &fflonk_bls24317.VerifyingKey{}
+	case ecc.BLS24_315:
+		vk = &fflonk_bls24315.VerifyingKey{}
+	case ecc.BW6_633:
+		vk = &fflonk_bw6633.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return vk
+}
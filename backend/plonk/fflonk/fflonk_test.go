@@ -0,0 +1,167 @@
+package fflonk_test
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	fflonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254/fflonk"
+	"github.com/consensys/gnark/backend/plonk/fflonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type refCircuit struct {
+	nbConstraints int
+	X             frontend.Variable
+	Y             frontend.Variable `gnark:",public"`
+	Z             frontend.Variable `gnark:",public"`
+}
+
+func (circuit *refCircuit) Define(api frontend.API) error {
+	x := circuit.X
+	for i := 0; i < circuit.nbConstraints; i++ {
+		x = api.Mul(x, x)
+	}
+	api.AssertIsEqual(x, circuit.Y)
+	api.AssertIsEqual(api.Add(circuit.X, api.Mul(3, circuit.Y), 5), circuit.Z)
+	return nil
+}
+
+func referenceAssignment(curve ecc.ID, nbConstraints int) *refCircuit {
+	var good refCircuit
+	good.X = 2
+
+	// compute expected Y and Z
+	expectedY := new(big.Int).SetUint64(2)
+	exp := big.NewInt(1)
+	exp.Lsh(exp, uint(nbConstraints))
+	expectedY.Exp(expectedY, exp, curve.ScalarField())
+	expectedZ := new(big.Int).Mul(expectedY, big.NewInt(3))
+	expectedZ.Add(expectedZ, big.NewInt(7)).Mod(expectedZ, curve.ScalarField())
+
+	good.Y = expectedY
+	good.Z = expectedZ
+	return &good
+}
+
+func TestProver(t *testing.T) {
+	const nbConstraints = 100
+	for _, curve := range getCurves() {
+		t.Run(curve.String(), func(t *testing.T) {
+			var b1, b2 bytes.Buffer
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+			assert.NoError(err)
+			fullWitness, err := frontend.NewWitness(referenceAssignment(curve, nbConstraints), curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			pk, vk, err := fflonk.Setup(ccs, newSRS(t, ccs))
+			assert.NoError(err)
+
+			// write the PK to ensure it is not mutated
+			_, err = pk.WriteTo(&b1)
+			assert.NoError(err)
+
+			proof, err := fflonk.Prove(ccs, pk, fullWitness)
+			assert.NoError(err)
+
+			// check pk
+			_, err = pk.WriteTo(&b2)
+			assert.NoError(err)
+
+			assert.True(bytes.Equal(b1.Bytes(), b2.Bytes()), "fflonk prover mutated the proving key")
+
+			assert.NoError(fflonk.Verify(proof, vk, publicWitness))
+
+			// wrong public witness
+			bad := referenceAssignment(curve, nbConstraints)
+			bad.Z = 1
+			badWitness, err := frontend.NewWitness(bad, curve.ScalarField(), frontend.PublicOnly())
+			assert.NoError(err)
+			assert.Error(fflonk.Verify(proof, vk, badWitness))
+
+			// unsatisfied constraints
+			_, err = fflonk.Prove(ccs, pk, badFullWitness(t, curve, nbConstraints))
+			assert.Error(err)
+		})
+	}
+}
+
+func TestSRSTooSmall(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: 10})
+	assert.NoError(err)
+	srs, err := kzg_bn254.NewSRS(uint64(fflonk.SRSSize(ccs)-1), big.NewInt(42))
+	assert.NoError(err)
+	_, _, err = fflonk.Setup(ccs, srs)
+	assert.Error(err)
+}
+
+func TestTamperedProof(t *testing.T) {
+	const nbConstraints = 10
+	assert := require.New(t)
+	curve := ecc.BN254
+
+	ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(referenceAssignment(curve, nbConstraints), curve.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	pk, vk, err := fflonk.Setup(ccs, newSRS(t, ccs))
+	assert.NoError(err)
+	proof, err := fflonk.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	assert.NoError(fflonk.Verify(proof, vk, publicWitness))
+
+	tampered := *proof.(*fflonk_bn254.Proof)
+	tampered.LROZ[3].SetOne()
+	assert.Error(fflonk.Verify(&tampered, vk, publicWitness))
+
+	tampered = *proof.(*fflonk_bn254.Proof)
+	tampered.Shifted[1], tampered.Shifted[2] = tampered.Shifted[2], tampered.Shifted[1]
+	assert.Error(fflonk.Verify(&tampered, vk, publicWitness))
+
+	tampered = *proof.(*fflonk_bn254.Proof)
+	tampered.W, tampered.WPrime = tampered.WPrime, tampered.W
+	assert.Error(fflonk.Verify(&tampered, vk, publicWitness))
+
+	// the challenges depend on the hash function
+	assert.Error(fflonk.Verify(proof, vk, publicWitness, backend.WithVerifierChallengeHashFunction(sha512.New())))
+}
+
+func newSRS(t *testing.T, ccs constraint.ConstraintSystem) kzg.SRS {
+	srs, err := test.NewKZGSRS(ccs)
+	require.NoError(t, err)
+	return srs
+}
+
+func badFullWitness(t *testing.T, curve ecc.ID, nbConstraints int) witness.Witness {
+	bad := referenceAssignment(curve, nbConstraints)
+	bad.X = 3
+	w, err := frontend.NewWitness(bad, curve.ScalarField())
+	require.NoError(t, err)
+	return w
+}
+
+func getCurves() []ecc.ID {
+	if testing.Short() {
+		return []ecc.ID{ecc.BN254}
+	}
+	return gnark.Curves()
+}
@@ -20,12 +20,16 @@
 package plonk
 
 import (
+	"errors"
+	"fmt"
 	"io"
 
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
 
 	"github.com/consensys/gnark/backend/witness"
 	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
@@ -95,26 +99,81 @@ type VerifyingKey interface {
 	ExportSolidity(w io.Writer) error
 }
 
+var (
+	errProvingKeyCurve   = fmt.Errorf("%w: the proving key does not match the constraint system curve", gnark.ErrCurveMismatch)
+	errVerifyingKeyCurve = fmt.Errorf("%w: the verifying key does not match the proof curve", gnark.ErrCurveMismatch)
+	errSRSCurve          = fmt.Errorf("%w: the SRS does not match the constraint system curve", gnark.ErrCurveMismatch)
+)
+
+// checkSparseR1CS returns an error if ccs was compiled for an R1CS backend. The
+// curve-typed R1CS and SparseR1CS are the same Go type, so the type switches
+// below can't tell them apart.
+func checkSparseR1CS(ccs constraint.ConstraintSystem) error {
+	if ccs == nil {
+		return nil
+	}
+	if _, ok := ccs.GetCommitments().(constraint.PlonkCommitments); !ok {
+		return errors.New("the constraint system is not a SparseR1CS")
+	}
+	return nil
+}
+
 // Setup prepares the public data associated to a circuit + public inputs.
-func Setup(ccs constraint.ConstraintSystem, kzgSrs kzg.SRS) (ProvingKey, VerifyingKey, error) {
+func Setup(ccs constraint.ConstraintSystem, kzgSrs kzg.SRS, opts ...backend.SetupOption) (_ ProvingKey, _ VerifyingKey, err error) {
+	cfg, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkSparseR1CS(ccs); err != nil {
+		return nil, nil, err
+	}
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Setup(tccs, *kzgSrs.(*kzg_bn254.SRS))
+		_kzgSrs, ok := kzgSrs.(*kzg_bn254.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return plonk_bn254.Setup(tccs, *_kzgSrs)
 	case *cs_bls12381.SparseR1CS:
-		return plonk_bls12381.Setup(tccs, *kzgSrs.(*kzg_bls12381.SRS))
+		_kzgSrs, ok := kzgSrs.(*kzg_bls12381.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return plonk_bls12381.Setup(tccs, *_kzgSrs)
 	case *cs_bls12377.SparseR1CS:
-		return plonk_bls12377.Setup(tccs, *kzgSrs.(*kzg_bls12377.SRS))
+		_kzgSrs, ok := kzgSrs.(*kzg_bls12377.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return plonk_bls12377.Setup(tccs, *_kzgSrs)
 	case *cs_bw6761.SparseR1CS:
-		return plonk_bw6761.Setup(tccs, *kzgSrs.(*kzg_bw6761.SRS))
+		_kzgSrs, ok := kzgSrs.(*kzg_bw6761.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return plonk_bw6761.Setup(tccs, *_kzgSrs)
 	case *cs_bls24317.SparseR1CS:
-		return plonk_bls24317.Setup(tccs, *kzgSrs.(*kzg_bls24317.SRS))
+		_kzgSrs, ok := kzgSrs.(*kzg_bls24317.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return plonk_bls24317.Setup(tccs, *_kzgSrs)
 	case *cs_bls24315.SparseR1CS:
-		return plonk_bls24315.Setup(tccs, *kzgSrs.(*kzg_bls24315.SRS))
+		_kzgSrs, ok := kzgSrs.(*kzg_bls24315.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return plonk_bls24315.Setup(tccs, *_kzgSrs)
 	case *cs_bw6633.SparseR1CS:
-		return plonk_bw6633.Setup(tccs, *kzgSrs.(*kzg_bw6633.SRS))
+		_kzgSrs, ok := kzgSrs.(*kzg_bw6633.SRS)
+		if !ok {
+			return nil, nil, errSRSCurve
+		}
+		return plonk_bw6633.Setup(tccs, *_kzgSrs)
 	default:
-		panic("unrecognized SparseR1CS curve type")
+		return nil, nil, errors.New("unrecognized SparseR1CS curve type")
 	}
 
 }
@@ -125,37 +184,78 @@ func Setup(ccs constraint.ConstraintSystem, kzgSrs kzg.SRS) (ProvingKey, Verifyi
 //		will execute all the prover computations, even if the witness is invalid
 //	 will produce an invalid proof
 //		internally, the solution vector to the SparseR1CS will be filled with random values which may impact benchmarking
-func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkSparseR1CS(ccs); err != nil {
+		return nil, err
+	}
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Prove(tccs, pk.(*plonk_bn254.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bn254.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bn254.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls12381.SparseR1CS:
-		return plonk_bls12381.Prove(tccs, pk.(*plonk_bls12381.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls12381.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls12381.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls12377.SparseR1CS:
-		return plonk_bls12377.Prove(tccs, pk.(*plonk_bls12377.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls12377.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls12377.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bw6761.SparseR1CS:
-		return plonk_bw6761.Prove(tccs, pk.(*plonk_bw6761.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bw6761.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bw6761.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bw6633.SparseR1CS:
-		return plonk_bw6633.Prove(tccs, pk.(*plonk_bw6633.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bw6633.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bw6633.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls24317.SparseR1CS:
-		return plonk_bls24317.Prove(tccs, pk.(*plonk_bls24317.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls24317.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls24317.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls24315.SparseR1CS:
-		return plonk_bls24315.Prove(tccs, pk.(*plonk_bls24315.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls24315.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls24315.Prove(tccs, _pk, fullWitness, opts...)
 
 	default:
-		panic("unrecognized SparseR1CS curve type")
+		return nil, errors.New("unrecognized SparseR1CS curve type")
 	}
 }
 
 // Verify verifies a PLONK proof, from the proof, preprocessed public data, and public witness.
-func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) (err error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
 
 	switch _proof := proof.(type) {
 
@@ -164,52 +264,80 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bn254.Verify(_proof, vk.(*plonk_bn254.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bn254.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bn254.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bls12381.Proof:
 		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls12381.Verify(_proof, vk.(*plonk_bls12381.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls12381.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls12381.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bls12377.Proof:
 		w, ok := publicWitness.Vector().(fr_bls12377.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls12377.Verify(_proof, vk.(*plonk_bls12377.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls12377.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls12377.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bw6761.Proof:
 		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bw6761.Verify(_proof, vk.(*plonk_bw6761.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bw6761.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bw6761.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bw6633.Proof:
 		w, ok := publicWitness.Vector().(fr_bw6633.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bw6633.Verify(_proof, vk.(*plonk_bw6633.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bw6633.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bw6633.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bls24317.Proof:
 		w, ok := publicWitness.Vector().(fr_bls24317.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls24317.Verify(_proof, vk.(*plonk_bls24317.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls24317.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls24317.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bls24315.Proof:
 		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls24315.Verify(_proof, vk.(*plonk_bls24315.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls24315.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls24315.Verify(_proof, _vk, w, opts...)
 
 	default:
-		panic("unrecognized proof type")
+		return errors.New("unrecognized proof type")
 	}
 }
 
@@ -11,8 +11,11 @@ import (
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
 	"github.com/consensys/gnark/constraint"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/test"
 	"github.com/stretchr/testify/require"
@@ -299,6 +302,250 @@ func (c *commitmentCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+type customGateCircuit struct {
+	X, K frontend.Variable
+	Y    frontend.Variable `gnark:",public"`
+}
+
+func (c *customGateCircuit) Define(api frontend.API) error {
+	cg := api.(frontend.CustomGater)
+	// pow5(t, x) = t²⋅x, so that x⁵ = pow5(x², x)
+	pow5, err := cg.DefineCustomGate("pow5", frontend.GateMonomial{Coeff: big.NewInt(1), A: 2, B: 1})
+	if err != nil {
+		return err
+	}
+	// round(x, k) = x³ + 2⋅k + 7
+	round, err := cg.DefineCustomGate("round",
+		frontend.GateMonomial{Coeff: big.NewInt(1), A: 3},
+		frontend.GateMonomial{Coeff: big.NewInt(2), B: 1},
+		frontend.GateMonomial{Coeff: big.NewInt(7)},
+	)
+	if err != nil {
+		return err
+	}
+	x := c.X
+	for i := 0; i < 4; i++ {
+		x = cg.CustomGate(pow5, api.Mul(x, x), x)
+		x = cg.CustomGate(round, x, api.Neg(c.K))
+	}
+	// constant inputs
+	x = api.Add(x, cg.CustomGate(round, 3, c.K), cg.CustomGate(pow5, c.K, 2))
+	cmt, err := api.(frontend.Committer).Commit(x)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(x, c.Y)
+	return nil
+}
+
+func TestCustomGates(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		curve := curve
+		assert.Run(func(assert *test.Assert) {
+			q := curve.ScalarField()
+			x, k := big.NewInt(3), big.NewInt(11)
+			y := new(big.Int).Set(x)
+			for i := 0; i < 4; i++ {
+				y.Exp(y, big.NewInt(5), q)
+				y.Exp(y, big.NewInt(3), q).Sub(y, new(big.Int).Lsh(k, 1)).Add(y, big.NewInt(7)).Mod(y, q)
+			}
+			y.Add(y, big.NewInt(27+7)).Add(y, new(big.Int).Lsh(k, 1))
+			y.Add(y, new(big.Int).Lsh(new(big.Int).Mul(k, k), 1)).Mod(y, q)
+			assignment := &customGateCircuit{X: x, K: k, Y: y}
+			assert.NoError(test.IsSolved(&customGateCircuit{}, assignment, q))
+
+			ccs, err := frontend.Compile(q, scs.NewBuilder, &customGateCircuit{})
+			assert.NoError(err)
+			srs, err := test.NewKZGSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(assignment, q)
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, witness)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+
+			// the custom gates are part of the serialized verifying key
+			var buf bytes.Buffer
+			_, err = vk.WriteTo(&buf)
+			assert.NoError(err)
+			vk2 := plonk.NewVerifyingKey(curve)
+			_, err = vk2.ReadFrom(&buf)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk2, pubWitness))
+
+			assignment.Y = new(big.Int).Add(y, big.NewInt(1))
+			witness, err = frontend.NewWitness(assignment, q)
+			assert.NoError(err)
+			_, err = plonk.Prove(ccs, pk, witness)
+			assert.Error(err)
+		}, curve.String())
+	}
+}
+
+type lookupCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *lookupCircuit) Define(api frontend.API) error {
+	tl := api.(frontend.TableLookuper)
+	var a, b, xor, x, sq, rng []*big.Int
+	for i := 0; i < 16; i++ {
+		for j := 0; j < 16; j++ {
+			a = append(a, big.NewInt(int64(i)))
+			b = append(b, big.NewInt(int64(j)))
+			xor = append(xor, big.NewInt(int64(i^j)))
+		}
+		x = append(x, big.NewInt(int64(i)))
+		sq = append(sq, big.NewInt(int64(i*i)))
+	}
+	for i := 0; i < 256; i++ {
+		rng = append(rng, big.NewInt(int64(i)))
+	}
+	xorTable, err := tl.DefineLookupTable("xor4", a, b, xor)
+	if err != nil {
+		return err
+	}
+	squareTable, err := tl.DefineLookupTable("square4", x, sq)
+	if err != nil {
+		return err
+	}
+	rangeTable, err := tl.DefineLookupTable("range8", rng)
+	if err != nil {
+		return err
+	}
+
+	z := tl.Lookup(xorTable, c.X, c.Y)
+	tl.AssertIsInTable(xorTable, c.Y, c.X, z)
+	api.AssertIsEqual(tl.Lookup(squareTable, c.X), api.Mul(c.X, c.X))
+	// terms with a coefficient and constants
+	tl.AssertIsInTable(rangeTable, api.Mul(c.Y, 16))
+	tl.AssertIsInTable(rangeTable, 255)
+	tl.AssertIsInTable(xorTable, c.X, 0, c.X)
+	api.AssertIsEqual(tl.Lookup(squareTable, 3), 9)
+	cmt, err := api.(frontend.Committer).Commit(z)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(z, c.Z)
+	return nil
+}
+
+func TestLookupTables(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		curve := curve
+		assert.Run(func(assert *test.Assert) {
+			q := curve.ScalarField()
+			assignment := &lookupCircuit{X: 3, Y: 10, Z: 3 ^ 10}
+			assert.NoError(test.IsSolved(&lookupCircuit{}, assignment, q))
+			assert.Error(test.IsSolved(&lookupCircuit{}, &lookupCircuit{X: 3, Y: 16, Z: 3 ^ 16}, q))
+
+			ccs, err := frontend.Compile(q, scs.NewBuilder, &lookupCircuit{})
+			assert.NoError(err)
+			srs, err := test.NewKZGSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs)
+			assert.NoError(err)
+			witness, err := frontend.NewWitness(assignment, q)
+			assert.NoError(err)
+			pubWitness, err := witness.Public()
+			assert.NoError(err)
+			proof, err := plonk.Prove(ccs, pk, witness)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk, pubWitness))
+
+			// the lookup tables are part of the serialized verifying key
+			var buf bytes.Buffer
+			_, err = vk.WriteTo(&buf)
+			assert.NoError(err)
+			vk2 := plonk.NewVerifyingKey(curve)
+			_, err = vk2.ReadFrom(&buf)
+			assert.NoError(err)
+			assert.NoError(plonk.Verify(proof, vk2, pubWitness))
+
+			// X = 3 is in the square table, but (3, 16) isn't in the xor table
+			assignment = &lookupCircuit{X: 3, Y: 16, Z: 3 ^ 16}
+			witness, err = frontend.NewWitness(assignment, q)
+			assert.NoError(err)
+			_, err = plonk.Prove(ccs, pk, witness)
+			assert.Error(err)
+		}, curve.String())
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	assert := require.New(t)
+	compile := func(curve ecc.ID, newBuilder frontend.NewBuilder) constraint.ConstraintSystem {
+		ccs, err := frontend.Compile(curve.ScalarField(), newBuilder, &refCircuit{nbConstraints: 3})
+		assert.NoError(err)
+		return ccs
+	}
+	ccs := compile(ecc.BN254, scs.NewBuilder)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&refCircuit{X: 2, Y: 256}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pw, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	// keys and SRS on another curve
+	blsCcs := compile(ecc.BLS12_381, scs.NewBuilder)
+	blsSrs, err := test.NewKZGSRS(blsCcs)
+	assert.NoError(err)
+	blsPk, blsVk, err := plonk.Setup(blsCcs, blsSrs)
+	assert.NoError(err)
+	_, _, err = plonk.Setup(ccs, blsSrs)
+	assert.ErrorIs(err, gnark.ErrCurveMismatch)
+	_, err = plonk.Prove(ccs, blsPk, w)
+	assert.ErrorIs(err, gnark.ErrCurveMismatch)
+	assert.ErrorIs(plonk.Verify(proof, blsVk, pw), gnark.ErrCurveMismatch)
+	_, err = plonk.Prove(ccs, nil, w)
+	assert.ErrorIs(err, gnark.ErrCurveMismatch)
+
+	// constraint system compiled for Groth16
+	_, _, err = plonk.Setup(compile(ecc.BN254, r1cs.NewBuilder), srs)
+	assert.Error(err)
+
+	// no constraint system
+	_, _, err = plonk.Setup(nil, srs)
+	assert.Error(err)
+	assert.Error(plonk.Verify(nil, vk, pw))
+}
+
+func TestPanicRecovery(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &refCircuit{nbConstraints: 3})
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&refCircuit{X: 2, Y: 256}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// proving key of the right type but nil
+	nilPk := (*plonk_bn254.ProvingKey)(nil)
+	assert.Panics(func() { _, _ = plonk.Prove(ccs, nilPk, w) })
+	_, err = plonk.Prove(ccs, nilPk, w, backend.WithProverPanicRecovery())
+	assert.ErrorIs(err, gnark.ErrPanic)
+
+	// corrupted constraint system
+	ccs.(*cs_bn254.SparseR1CS).Blueprints = nil
+	assert.Panics(func() { _, _, _ = plonk.Setup(ccs, srs) })
+	_, _, err = plonk.Setup(ccs, srs, backend.WithSetupPanicRecovery())
+	assert.ErrorIs(err, gnark.ErrPanic)
+}
+
 type smallCircuit struct {
 	X frontend.Variable
 }
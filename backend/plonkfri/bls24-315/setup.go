@@ -18,6 +18,7 @@ package plonkfri
 
 import (
 	"crypto/sha256"
+	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fri"
@@ -100,6 +101,13 @@ type VerifyingKey struct {
 // Setup sets proving and verifying keys
 func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
 
+	if len(spr.GetCustomGates()) != 0 {
+		return nil, nil, errors.New("custom gates are not supported by the plonkfri backend")
+	}
+	if len(spr.GetLookupTables()) != 0 {
+		return nil, nil, errors.New("lookup tables are not supported by the plonkfri backend")
+	}
+
 	var pk ProvingKey
 	var vk VerifyingKey
 
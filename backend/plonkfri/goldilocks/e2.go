@@ -0,0 +1,155 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonkfri
+
+import (
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// E2 is an element A0+A1*u of the quadratic extension 𝔽ₚ[u]/(u²-7) of the
+// Goldilocks field. The verifier challenges are sampled in E2, so that the
+// soundness of the protocol does not depend on the 64-bit size of 𝔽ₚ.
+type E2 struct {
+	A0, A1 fr.Element
+}
+
+// nonResidue is the quadratic non residue defining E2
+var nonResidue = fr.NewElement(7)
+
+// Set sets z to x and returns z
+func (z *E2) Set(x *E2) *E2 {
+	*z = *x
+	return z
+}
+
+// SetElement sets z to x embedded in E2 and returns z
+func (z *E2) SetElement(x *fr.Element) *E2 {
+	z.A0.Set(x)
+	z.A1.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 and returns z
+func (z *E2) SetOne() *E2 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	return z
+}
+
+// Equal returns true if z == x
+func (z *E2) Equal(x *E2) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
+}
+
+// Add sets z to x+y and returns z
+func (z *E2) Add(x, y *E2) *E2 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	return z
+}
+
+// Sub sets z to x-y and returns z
+func (z *E2) Sub(x, y *E2) *E2 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	return z
+}
+
+// AddElement sets z to x+y and returns z
+func (z *E2) AddElement(x *E2, y *fr.Element) *E2 {
+	z.A0.Add(&x.A0, y)
+	z.A1.Set(&x.A1)
+	return z
+}
+
+// Mul sets z to x*y and returns z
+func (z *E2) Mul(x, y *E2) *E2 {
+	var a, b, c fr.Element
+	a.Mul(&x.A0, &y.A0)
+	b.Mul(&x.A1, &y.A1).Mul(&b, &nonResidue)
+	c.Mul(&x.A0, &y.A1)
+	z.A1.Mul(&x.A1, &y.A0).Add(&z.A1, &c)
+	z.A0.Add(&a, &b)
+	return z
+}
+
+// MulByElement sets z to x*y and returns z
+func (z *E2) MulByElement(x *E2, y *fr.Element) *E2 {
+	z.A0.Mul(&x.A0, y)
+	z.A1.Mul(&x.A1, y)
+	return z
+}
+
+// Square sets z to x² and returns z
+func (z *E2) Square(x *E2) *E2 {
+	return z.Mul(x, x)
+}
+
+// Inverse sets z to x⁻¹ and returns z. The inverse of 0 is 0.
+func (z *E2) Inverse(x *E2) *E2 {
+	// (a0+a1*u)⁻¹ = (a0-a1*u)/(a0²-7a1²)
+	var t0, t1 fr.Element
+	t0.Square(&x.A0)
+	t1.Square(&x.A1).Mul(&t1, &nonResidue)
+	t0.Sub(&t0, &t1).Inverse(&t0)
+	z.A0.Mul(&x.A0, &t0)
+	z.A1.Mul(&x.A1, &t0).Neg(&z.A1)
+	return z
+}
+
+// Bytes returns the big endian encoding of z
+func (z *E2) Bytes() []byte {
+	res := make([]byte, 0, 2*fr.Bytes)
+	b := z.A0.Bytes()
+	res = append(res, b[:]...)
+	b = z.A1.Bytes()
+	return append(res, b[:]...)
+}
+
+// setBytes maps a challenge returned by the transcript to E2, each half of b
+// being reduced modulo p.
+func (z *E2) setBytes(b []byte) *E2 {
+	z.A0.SetBytes(b[:len(b)/2])
+	z.A1.SetBytes(b[len(b)/2:])
+	return z
+}
+
+// batchInvertE2 returns the inverses of the elements of a, using a single
+// inversion. The inverse of 0 is 0.
+func batchInvertE2(a []E2) []E2 {
+	res := make([]E2, len(a))
+	if len(a) == 0 {
+		return res
+	}
+	var acc E2
+	acc.SetOne()
+	for i := range a {
+		res[i] = acc
+		if a[i].A0.IsZero() && a[i].A1.IsZero() {
+			continue
+		}
+		acc.Mul(&acc, &a[i])
+	}
+	acc.Inverse(&acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].A0.IsZero() && a[i].A1.IsZero() {
+			res[i] = E2{}
+			continue
+		}
+		res[i].Mul(&res[i], &acc)
+		acc.Mul(&acc, &a[i])
+	}
+	return res
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonkfri
+
+import (
+	"math/big"
+	"math/bits"
+	"runtime"
+
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// maxOrderRoot is the 2-adicity of p-1
+const maxOrderRoot = 32
+
+// multiplicativeGen generates 𝔽ₚ*. It is used as the shift of the cosets.
+var multiplicativeGen = fr.NewElement(7)
+
+// domain is a multiplicative subgroup of 𝔽ₚ* of size a power of 2, on which
+// polynomials are interpolated and evaluated in natural order.
+type domain struct {
+	Cardinality    uint64
+	CardinalityInv fr.Element
+	Generator      fr.Element
+	GeneratorInv   fr.Element
+
+	twiddles    []fr.Element // ωⁱ for i < Cardinality/2
+	twiddlesInv []fr.Element // ω⁻ⁱ for i < Cardinality/2
+}
+
+// newDomain returns the subgroup of size m, which must be a power of 2.
+func newDomain(m uint64) *domain {
+	logM := bits.TrailingZeros64(m)
+	if m&(m-1) != 0 || logM > maxOrderRoot {
+		panic("domain size must be a power of 2 smaller than 2^32")
+	}
+	var d domain
+	d.Cardinality = m
+	d.CardinalityInv.SetUint64(m).Inverse(&d.CardinalityInv)
+
+	// ω = g^((p-1)/m)
+	exp := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	exp.Rsh(exp, uint(logM))
+	d.Generator.Exp(multiplicativeGen, exp)
+	d.GeneratorInv.Inverse(&d.Generator)
+
+	d.twiddles = make([]fr.Element, m/2)
+	d.twiddlesInv = make([]fr.Element, m/2)
+	if m > 1 {
+		d.twiddles[0].SetOne()
+		d.twiddlesInv[0].SetOne()
+		for i := 1; i < len(d.twiddles); i++ {
+			d.twiddles[i].Mul(&d.twiddles[i-1], &d.Generator)
+			d.twiddlesInv[i].Mul(&d.twiddlesInv[i-1], &d.GeneratorInv)
+		}
+	}
+	return &d
+}
+
+// FFT evaluates in place the polynomial of coefficients a on the domain. If
+// a shift is provided, the polynomial is evaluated on shift*domain instead.
+func (d *domain) FFT(a []fr.Element, shift ...fr.Element) {
+	if len(shift) > 0 {
+		scalePowers(a, shift[0])
+	}
+	d.butterflies(a, d.twiddles)
+}
+
+// FFTInverse interpolates in place the evaluations a on the domain (or on
+// shift*domain if a shift is provided), and returns the coefficients.
+func (d *domain) FFTInverse(a []fr.Element, shift ...fr.Element) {
+	d.butterflies(a, d.twiddlesInv)
+	utils.Parallelize(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			a[i].Mul(&a[i], &d.CardinalityInv)
+		}
+	})
+	if len(shift) > 0 {
+		var shiftInv fr.Element
+		shiftInv.Inverse(&shift[0])
+		scalePowers(a, shiftInv)
+	}
+}
+
+// butterflies runs an iterative radix 2 Cooley-Tukey transform, natural order
+// in and out.
+func (d *domain) butterflies(a []fr.Element, twiddles []fr.Element) {
+	n := len(a)
+	if uint64(n) != d.Cardinality {
+		panic("invalid vector size")
+	}
+	bitReverse(a)
+	nbCpus := runtime.NumCPU()
+	if n < 1<<10 {
+		nbCpus = 1
+	}
+	for m := 2; m <= n; m <<= 1 {
+		half, stride := m/2, n/m
+		utils.Parallelize(n/2, func(start, end int) {
+			var t fr.Element
+			for b := start; b < end; b++ {
+				k, j := (b/half)*m, b%half
+				t.Mul(&a[k+j+half], &twiddles[j*stride])
+				a[k+j+half].Sub(&a[k+j], &t)
+				a[k+j].Add(&a[k+j], &t)
+			}
+		}, nbCpus)
+	}
+}
+
+// scalePowers sets a[i] to a[i]*xⁱ
+func scalePowers(a []fr.Element, x fr.Element) {
+	var acc fr.Element
+	acc.SetOne()
+	for i := range a {
+		a[i].Mul(&a[i], &acc)
+		acc.Mul(&acc, &x)
+	}
+}
+
+// bitReverse applies the bit reversal permutation to a, whose size must be a
+// power of 2.
+func bitReverse(a []fr.Element) {
+	n := uint64(len(a))
+	if n < 2 {
+		return
+	}
+	nn := uint64(64 - bits.TrailingZeros64(n))
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
+
+// powers returns [1, x, .., xⁿ⁻¹]
+func powers(x fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	if n == 0 {
+		return res
+	}
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &x)
+	}
+	return res
+}
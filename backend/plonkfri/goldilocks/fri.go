@@ -0,0 +1,210 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonkfri
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math/big"
+	"strconv"
+
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// ColumnsOpening opens a Merkle tree of columns at a FRI query: Values holds
+// the rows at x and -x.
+type ColumnsOpening struct {
+	Values []fr.Element
+	Path   [][]byte
+}
+
+// LayerOpening opens a folded FRI layer at x and -x.
+type LayerOpening struct {
+	Values [2]E2
+	Path   [][]byte
+}
+
+// friLayer is a folded layer of FRI, committed by the prover
+type friLayer struct {
+	evaluations []E2
+	leaves      [][]fr.Element
+	tree        *merkleTree
+}
+
+// newTranscript returns the Fiat Shamir transcript of the protocol, for a
+// circuit of the given size.
+func newTranscript(h hash.Hash, vk *VerifyingKey) *fiatshamir.Transcript {
+	names := []string{"beta", "gamma", "alpha", "zeta", "lambda"}
+	for i := 0; i < vk.nbFolds(); i++ {
+		names = append(names, "fold_"+strconv.Itoa(i))
+	}
+	names = append(names, "queries")
+	fs := fiatshamir.NewTranscript(h, names...)
+	return &fs
+}
+
+// deriveRandomness binds data to the challenge and returns it, mapped to E2
+func deriveRandomness(fs *fiatshamir.Transcript, challenge string, data ...[]byte) (E2, error) {
+	var res E2
+	for i := range data {
+		if err := fs.Bind(challenge, data[i]); err != nil {
+			return res, err
+		}
+	}
+	b, err := fs.ComputeChallenge(challenge)
+	if err != nil {
+		return res, err
+	}
+	res.setBytes(b)
+	return res, nil
+}
+
+// deriveQueries returns the positions of the FRI queries in [0, N/2), where N
+// is the size of the big domain.
+func deriveQueries(fs *fiatshamir.Transcript, final *E2, half uint64) ([]uint64, error) {
+	if err := fs.Bind("queries", final.Bytes()); err != nil {
+		return nil, err
+	}
+	seed, err := fs.ComputeChallenge("queries")
+	if err != nil {
+		return nil, err
+	}
+	res := make([]uint64, nbQueries)
+	var buf [4]byte
+	for i := range res {
+		h := sha256.New()
+		h.Write(seed)
+		binary.BigEndian.PutUint32(buf[:], uint32(i))
+		h.Write(buf[:])
+		res[i] = binary.BigEndian.Uint64(h.Sum(nil)[:8]) % half
+	}
+	return res, nil
+}
+
+// fold returns (a+b)/2 + β(a-b)/(2x), where a = f(x) and b = f(-x): it is the
+// evaluation at x² of the folded polynomial fₑ+βfₒ, where f(X)=fₑ(X²)+Xfₒ(X²).
+func fold(a, b *E2, xInv *fr.Element, beta *E2) E2 {
+	var s, d E2
+	s.Add(a, b)
+	d.Sub(a, b).MulByElement(&d, xInv).Mul(&d, beta)
+	s.Add(&s, &d)
+	s.A0.Halve()
+	s.A1.Halve()
+	return s
+}
+
+// friCommit folds the evaluations of a polynomial of degree < vk.Size on the
+// coset of the big domain, until it is constant. It returns the committed
+// layers and the final constant.
+func friCommit(fs *fiatshamir.Transcript, pk *ProvingKey, evaluations []E2) ([]friLayer, E2, error) {
+	nbFolds := pk.Vk.nbFolds()
+	layers := make([]friLayer, 0, nbFolds)
+
+	current := evaluations
+	shift := pk.Vk.CosetShift
+	generator := pk.Domain[1].Generator
+	for r := 0; r < nbFolds; r++ {
+		var data [][]byte
+		if r > 0 {
+			data = append(data, layers[r-1].tree.root())
+		}
+		beta, err := deriveRandomness(fs, "fold_"+strconv.Itoa(r), data...)
+		if err != nil {
+			return nil, E2{}, err
+		}
+
+		half := len(current) / 2
+		xInv := powers(generator, half)
+		utils.Parallelize(half, func(start, end int) {
+			for i := start; i < end; i++ {
+				xInv[i].Mul(&xInv[i], &shift)
+			}
+		})
+		xInv = fr.BatchInvert(xInv)
+
+		next := make([]E2, half)
+		utils.Parallelize(half, func(start, end int) {
+			for i := start; i < end; i++ {
+				next[i] = fold(&current[i], &current[i+half], &xInv[i], &beta)
+			}
+		})
+
+		if r < nbFolds-1 {
+			leaves := make([][]fr.Element, half/2)
+			for i := range leaves {
+				leaves[i] = []fr.Element{next[i].A0, next[i].A1, next[i+half/2].A0, next[i+half/2].A1}
+			}
+			layers = append(layers, friLayer{evaluations: next, leaves: leaves, tree: newMerkleTree(leaves)})
+		}
+
+		current = next
+		shift.Square(&shift)
+		generator.Square(&generator)
+	}
+	return layers, current[0], nil
+}
+
+// deepQuotient returns
+//
+//	∑ⱼ λʲ(fⱼ(x)-fⱼ(ζ))/(x-ζ) + λⁿ(z(x)-z(ωζ))/(x-ωζ)
+//
+// where n=nbClaims, that is the evaluation at x of the polynomial whose low
+// degree proves the consistency of the claims at ζ and ωζ with the commitments.
+func deepQuotient(f, claims *[nbClaims]E2, zShifted *E2, lambda *E2, xMinusZetaInv, xMinusZetaShiftedInv *E2) E2 {
+	var res, t E2
+	for j := nbClaims - 1; j >= 0; j-- {
+		t.Sub(&f[j], &claims[j])
+		res.Mul(&res, lambda).Add(&res, &t)
+	}
+	res.Mul(&res, xMinusZetaInv)
+	t.Sub(&f[idZ], zShifted).Mul(&t, xMinusZetaShiftedInv)
+	var lambdaN E2
+	lambdaN.SetOne()
+	for j := 0; j < nbClaims; j++ {
+		lambdaN.Mul(&lambdaN, lambda)
+	}
+	t.Mul(&t, &lambdaN)
+	return *res.Add(&res, &t)
+}
+
+// rowsToClaims maps the rows of the four committed trees (preprocessed, l r o,
+// z and h) at x (half=0) or -x (half=1) to the polynomials opened at ζ.
+func rowsToClaims(rows *[4][]fr.Element, half int) [nbClaims]E2 {
+	var res [nbClaims]E2
+	row := func(k int) []fr.Element {
+		nbColumns := len(rows[k]) / 2
+		return rows[k][half*nbColumns : (half+1)*nbColumns]
+	}
+	pre, lro, z, h := row(0), row(1), row(2), row(3)
+	for j := 0; j < nbPreprocessed; j++ {
+		res[j].SetElement(&pre[j])
+	}
+	for j := 0; j < 3; j++ {
+		res[idL+j].SetElement(&lro[j])
+		res[idH1+j] = E2{h[2*j], h[2*j+1]}
+	}
+	res[idZ] = E2{z[0], z[1]}
+	return res
+}
+
+// expUint64 returns xᵉ
+func expUint64(x fr.Element, e uint64) fr.Element {
+	var res fr.Element
+	res.Exp(x, new(big.Int).SetUint64(e))
+	return res
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonkfri
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+var errInvalidMerklePath = errors.New("invalid merkle path")
+
+// merkleTree is a sha256 Merkle tree, stored as a binary heap: nodes[1] is the
+// root and the leaves are nodes[nbLeaves:].
+type merkleTree struct {
+	nodes [][]byte
+}
+
+// leafHash and nodeHash are domain separated, so that a leaf cannot be opened
+// as an internal node.
+func leafHash(data []fr.Element) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	for i := range data {
+		b := data[i].Bytes()
+		h.Write(b[:])
+	}
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// newMerkleTree builds the tree whose leaves are the hashes of leaves[i]. The
+// number of leaves must be a power of 2.
+func newMerkleTree(leaves [][]fr.Element) *merkleTree {
+	n := len(leaves)
+	t := merkleTree{nodes: make([][]byte, 2*n)}
+	utils.Parallelize(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			t.nodes[n+i] = leafHash(leaves[i])
+		}
+	})
+	for i := n - 1; i >= 1; i-- {
+		t.nodes[i] = nodeHash(t.nodes[2*i], t.nodes[2*i+1])
+	}
+	return &t
+}
+
+// root returns the commitment to the leaves
+func (t *merkleTree) root() []byte {
+	return t.nodes[1]
+}
+
+// open returns the authentication path of the i-th leaf, from the leaf to the
+// root.
+func (t *merkleTree) open(i int) [][]byte {
+	n := len(t.nodes) / 2
+	var path [][]byte
+	for j := n + i; j > 1; j >>= 1 {
+		path = append(path, t.nodes[j^1])
+	}
+	return path
+}
+
+// verifyMerklePath checks that leaf is the i-th leaf of the tree of the given
+// root, which has 2^len(path) leaves.
+func verifyMerklePath(root []byte, leaf []fr.Element, i int, path [][]byte) error {
+	if i < 0 || i >= 1<<len(path) {
+		return errInvalidMerklePath
+	}
+	h := leafHash(leaf)
+	for _, sibling := range path {
+		if i&1 == 0 {
+			h = nodeHash(h, sibling)
+		} else {
+			h = nodeHash(sibling, h)
+		}
+		i >>= 1
+	}
+	if !bytes.Equal(h, root) {
+		return errInvalidMerklePath
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package plonkfri_test
+
+import (
+	"crypto/sha512"
+	"math/big"
+	"strconv"
+	"testing"
+
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonkfri"
+	plonkfri_goldilocks "github.com/consensys/gnark/backend/plonkfri/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type refCircuit struct {
+	nbConstraints int
+	X             frontend.Variable
+	Y             frontend.Variable `gnark:",public"`
+	Z             frontend.Variable `gnark:",public"`
+}
+
+func (circuit *refCircuit) Define(api frontend.API) error {
+	x := circuit.X
+	for i := 0; i < circuit.nbConstraints; i++ {
+		x = api.Mul(x, x)
+	}
+	api.AssertIsEqual(x, circuit.Y)
+	api.AssertIsEqual(api.Add(circuit.X, api.Mul(3, circuit.Y), 5), circuit.Z)
+	return nil
+}
+
+func referenceAssignment(nbConstraints int) *refCircuit {
+	var good refCircuit
+	good.X = 3
+
+	// compute expected Y and Z
+	expectedY := new(big.Int).SetUint64(3)
+	exp := big.NewInt(1)
+	exp.Lsh(exp, uint(nbConstraints))
+	expectedY.Exp(expectedY, exp, fr.Modulus())
+	expectedZ := new(big.Int).Mul(expectedY, big.NewInt(3))
+	expectedZ.Add(expectedZ, big.NewInt(8)).Mod(expectedZ, fr.Modulus())
+
+	good.Y = expectedY
+	good.Z = expectedZ
+	return &good
+}
+
+func TestProver(t *testing.T) {
+	for _, nbConstraints := range []int{0, 3, 1000} {
+		t.Run(strconv.Itoa(nbConstraints), func(t *testing.T) {
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(fr.Modulus(), scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+			assert.NoError(err)
+			fullWitness, err := frontend.NewWitness(referenceAssignment(nbConstraints), fr.Modulus())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			pk, vk, err := plonkfri.Setup(ccs)
+			assert.NoError(err)
+			proof, err := plonkfri.Prove(ccs, pk, fullWitness)
+			assert.NoError(err)
+			assert.NoError(plonkfri.Verify(proof, vk, publicWitness))
+
+			// wrong public witness
+			bad := referenceAssignment(nbConstraints)
+			bad.Z = 1
+			badWitness, err := frontend.NewWitness(bad, fr.Modulus(), frontend.PublicOnly())
+			assert.NoError(err)
+			assert.Error(plonkfri.Verify(proof, vk, badWitness))
+
+			// unsatisfied constraints
+			bad = referenceAssignment(nbConstraints)
+			bad.X = 2
+			badWitness, err = frontend.NewWitness(bad, fr.Modulus())
+			assert.NoError(err)
+			_, err = plonkfri.Prove(ccs, pk, badWitness)
+			assert.Error(err)
+		})
+	}
+}
+
+func TestTamperedProof(t *testing.T) {
+	const nbConstraints = 10
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(fr.Modulus(), scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+	assert.NoError(err)
+	fullWitness, err := frontend.NewWitness(referenceAssignment(nbConstraints), fr.Modulus())
+	assert.NoError(err)
+	publicWitness, err := fullWitness.Public()
+	assert.NoError(err)
+
+	pk, vk, err := plonkfri.Setup(ccs)
+	assert.NoError(err)
+	proof, err := plonkfri.Prove(ccs, pk, fullWitness)
+	assert.NoError(err)
+	assert.NoError(plonkfri.Verify(proof, vk, publicWitness))
+
+	// inconsistent claim
+	tampered := *proof.(*plonkfri_goldilocks.Proof)
+	tampered.Claims[3].A0.SetOne()
+	assert.ErrorIs(plonkfri.Verify(&tampered, vk, publicWitness), plonkfri_goldilocks.ErrInvalidAlgebraicRelation)
+
+	// wrong commitment
+	tampered = *proof.(*plonkfri_goldilocks.Proof)
+	tampered.LRO = tampered.Z
+	assert.Error(plonkfri.Verify(&tampered, vk, publicWitness))
+
+	// wrong final layer
+	tampered = *proof.(*plonkfri_goldilocks.Proof)
+	tampered.Final.A1.SetOne()
+	assert.Error(plonkfri.Verify(&tampered, vk, publicWitness))
+
+	// wrong opening
+	tampered = *proof.(*plonkfri_goldilocks.Proof)
+	tampered.Queries = append([]plonkfri_goldilocks.Query(nil), tampered.Queries...)
+	tampered.Queries[0].Layers = append([]plonkfri_goldilocks.LayerOpening(nil), tampered.Queries[0].Layers...)
+	tampered.Queries[0].Layers[1].Values[0], tampered.Queries[0].Layers[1].Values[1] = tampered.Queries[0].Layers[1].Values[1], tampered.Queries[0].Layers[1].Values[0]
+	assert.Error(plonkfri.Verify(&tampered, vk, publicWitness))
+
+	// truncated proof
+	tampered = *proof.(*plonkfri_goldilocks.Proof)
+	tampered.Queries = tampered.Queries[1:]
+	assert.Error(plonkfri.Verify(&tampered, vk, publicWitness))
+
+	// the challenges depend on the hash function
+	assert.Error(plonkfri.Verify(proof, vk, publicWitness, backend.WithVerifierChallengeHashFunction(sha512.New())))
+}
@@ -0,0 +1,379 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonkfri
+
+import (
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	cs "github.com/consensys/gnark/constraint/goldilocks"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+type Proof struct {
+	// Merkle roots of the evaluations on the big domain of l, r, o, of the
+	// permutation polynomial z, and of h₁, h₂, h₃ such that h = h₁ + Xⁿh₂ +
+	// X²ⁿh₃ is the quotient polynomial
+	LRO, Z, H []byte
+
+	// evaluations at ζ of ql, qr, qm, qo, qk (incomplete), s₁, s₂, s₃, l, r, o,
+	// z, h₁, h₂, h₃
+	Claims [nbClaims]E2
+
+	// evaluation of z at ωζ
+	ZShifted E2
+
+	// Merkle roots of the folded FRI layers. The first layer is opened from
+	// the commitments above, the last one is Final.
+	Layers [][]byte
+	Final  E2
+
+	Queries []Query
+}
+
+// Query holds the openings answering a FRI query
+type Query struct {
+	// openings of the preprocessed polynomials, l r o, z and h₁ h₂ h₃
+	Columns [4]ColumnsOpening
+
+	// openings of the folded layers
+	Layers []LayerOpening
+}
+
+// Prove generates a proof from a circuit, associated preprocessed public data, and the witness.
+//
+// The polynomials are not blinded, so the proof is not zero knowledge.
+func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (*Proof, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var proof Proof
+
+	// 0 - Fiat Shamir
+	fs := newTranscript(opt.ChallengeHash, pk.Vk)
+
+	// 1 - solve the system
+	_solution, err := spr.Solve(fullWitness, opt.SolverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	solution := _solution.(*cs.SparseR1CSSolution)
+	lro := [3][]fr.Element{solution.L, solution.R, solution.O}
+	fw, ok := fullWitness.Vector().(fr.Vector)
+	if !ok {
+		return nil, witness.ErrInvalidWitness
+	}
+
+	// 2 - commit to l, r, o
+	var lroCanonical, lroBig [3][]fr.Element
+	for i := 0; i < 3; i++ {
+		lroCanonical[i] = make([]fr.Element, pk.Domain[0].Cardinality)
+		copy(lroCanonical[i], lro[i])
+		pk.Domain[0].FFTInverse(lroCanonical[i])
+		lroBig[i] = lde(pk, lroCanonical[i])
+	}
+	lroLeaves, lroTree := commitColumns(lroBig[:])
+	proof.LRO = lroTree.root()
+
+	// 3 - compute z, challenges are derived using l, r, o + public inputs
+	dataFiatShamir := make([][]byte, 0, len(spr.Public)+2)
+	for i := 0; i < len(spr.Public); i++ {
+		dataFiatShamir = append(dataFiatShamir, fw[i].Marshal())
+	}
+	dataFiatShamir = append(dataFiatShamir, pk.Vk.Commitment, proof.LRO)
+	beta, err := deriveRandomness(fs, "beta", dataFiatShamir...)
+	if err != nil {
+		return nil, err
+	}
+	gamma, err := deriveRandomness(fs, "gamma")
+	if err != nil {
+		return nil, err
+	}
+
+	zCanonical := computeZCanonical(pk, lro, beta, gamma)
+	zBig := [2][]fr.Element{lde(pk, zCanonical[0]), lde(pk, zCanonical[1])}
+	zLeaves, zTree := commitColumns(zBig[:])
+	proof.Z = zTree.root()
+
+	// 4 - compute h
+	alpha, err := deriveRandomness(fs, "alpha", proof.Z)
+	if err != nil {
+		return nil, err
+	}
+
+	piCanonical := make([]fr.Element, pk.Domain[0].Cardinality)
+	copy(piCanonical, fw[:len(spr.Public)])
+	pk.Domain[0].FFTInverse(piCanonical)
+	piBig := lde(pk, piCanonical)
+
+	hCanonical := computeQuotientCanonical(pk, lroBig, zBig, piBig, alpha, beta, gamma)
+	hBig := make([][]fr.Element, 6)
+	for i := range hBig {
+		hBig[i] = lde(pk, hCanonical[i])
+	}
+	hLeaves, hTree := commitColumns(hBig)
+	proof.H = hTree.root()
+
+	// 5 - evaluate the polynomials at ζ
+	zeta, err := deriveRandomness(fs, "zeta", proof.H)
+	if err != nil {
+		return nil, err
+	}
+
+	var canonical [nbClaims][2][]fr.Element
+	for i := 0; i < nbPreprocessed; i++ {
+		canonical[i][0] = pk.Coefficients[i]
+	}
+	for i := 0; i < 3; i++ {
+		canonical[idL+i][0] = lroCanonical[i]
+		canonical[idH1+i] = [2][]fr.Element{hCanonical[2*i], hCanonical[2*i+1]}
+	}
+	canonical[idZ] = zCanonical
+	utils.Parallelize(nbClaims, func(start, end int) {
+		for i := start; i < end; i++ {
+			proof.Claims[i] = evaluate(canonical[i], &zeta)
+		}
+	}, nbClaims)
+	var zetaShifted E2
+	zetaShifted.MulByElement(&zeta, &pk.Vk.Generator)
+	proof.ZShifted = evaluate(zCanonical, &zetaShifted)
+
+	// 6 - DEEP composition polynomial, on the coset of the big domain
+	dataFiatShamir = dataFiatShamir[:0]
+	for i := range proof.Claims {
+		dataFiatShamir = append(dataFiatShamir, proof.Claims[i].Bytes())
+	}
+	dataFiatShamir = append(dataFiatShamir, proof.ZShifted.Bytes())
+	lambda, err := deriveRandomness(fs, "lambda", dataFiatShamir...)
+	if err != nil {
+		return nil, err
+	}
+
+	nbElmts := int(pk.Domain[1].Cardinality)
+	x := powers(pk.Domain[1].Generator, nbElmts)
+	denominators := make([]E2, 2*nbElmts)
+	utils.Parallelize(nbElmts, func(start, end int) {
+		var t E2
+		for i := start; i < end; i++ {
+			x[i].Mul(&x[i], &pk.Vk.CosetShift)
+			t.SetElement(&x[i])
+			denominators[i].Sub(&t, &zeta)
+			denominators[nbElmts+i].Sub(&t, &zetaShifted)
+		}
+	})
+	denominators = batchInvertE2(denominators)
+
+	deep := make([]E2, nbElmts)
+	half := nbElmts / 2
+	utils.Parallelize(half, func(start, end int) {
+		for i := start; i < end; i++ {
+			rows := [4][]fr.Element{pk.leaves[i], lroLeaves[i], zLeaves[i], hLeaves[i]}
+			for s, j := range [2]int{i, i + half} {
+				f := rowsToClaims(&rows, s)
+				deep[j] = deepQuotient(&f, &proof.Claims, &proof.ZShifted, &lambda, &denominators[j], &denominators[nbElmts+j])
+			}
+		}
+	})
+
+	// 7 - FRI
+	layers, final, err := friCommit(fs, pk, deep)
+	if err != nil {
+		return nil, err
+	}
+	proof.Layers = make([][]byte, len(layers))
+	for i := range layers {
+		proof.Layers[i] = layers[i].tree.root()
+	}
+	proof.Final = final
+
+	// 8 - answer the queries
+	positions, err := deriveQueries(fs, &proof.Final, uint64(half))
+	if err != nil {
+		return nil, err
+	}
+	proof.Queries = make([]Query, len(positions))
+	trees := [4]*merkleTree{pk.tree, lroTree, zTree, hTree}
+	leaves := [4][][]fr.Element{pk.leaves, lroLeaves, zLeaves, hLeaves}
+	for q, p := range positions {
+		for k := range trees {
+			proof.Queries[q].Columns[k] = ColumnsOpening{Values: leaves[k][p], Path: trees[k].open(int(p))}
+		}
+		proof.Queries[q].Layers = make([]LayerOpening, len(layers))
+		for r := range layers {
+			i := int(p) % len(layers[r].leaves)
+			var o LayerOpening
+			o.Values[0] = layers[r].evaluations[i]
+			o.Values[1] = layers[r].evaluations[i+len(layers[r].leaves)]
+			o.Path = layers[r].tree.open(i)
+			proof.Queries[q].Layers[r] = o
+		}
+	}
+
+	return &proof, nil
+}
+
+// computeZCanonical computes the canonical coefficients of the permutation
+// polynomial z, with values in E2, as two polynomials of 𝔽ₚ[X]:
+//
+//	z(1) = 1
+//	z(ωⁱ⁺¹) = z(ωⁱ) * ∏ⱼ (wⱼ(ωⁱ)+β*uʲωⁱ+γ)/(wⱼ(ωⁱ)+β*sⱼ(ωⁱ)+γ)
+func computeZCanonical(pk *ProvingKey, lro [3][]fr.Element, beta, gamma E2) [2][]fr.Element {
+	n := int(pk.Domain[0].Cardinality)
+	num := make([]E2, n)
+	den := make([]E2, n)
+
+	var cosetShiftSquare fr.Element
+	cosetShiftSquare.Square(&pk.Vk.CosetShift)
+	shifts := [3]fr.Element{fr.One(), pk.Vk.CosetShift, cosetShiftSquare}
+
+	utils.Parallelize(n, func(start, end int) {
+		var id fr.Element
+		var f, g E2
+		id = expUint64(pk.Domain[0].Generator, uint64(start))
+		for i := start; i < end; i++ {
+			num[i].SetOne()
+			den[i].SetOne()
+			for j := 0; j < 3; j++ {
+				var t fr.Element
+				t.Mul(&id, &shifts[j])
+				f.MulByElement(&beta, &t).Add(&f, &gamma).AddElement(&f, &lro[j][i])
+				g.MulByElement(&beta, &pk.LS[j][i]).Add(&g, &gamma).AddElement(&g, &lro[j][i])
+				num[i].Mul(&num[i], &f)
+				den[i].Mul(&den[i], &g)
+			}
+			id.Mul(&id, &pk.Domain[0].Generator)
+		}
+	})
+	den = batchInvertE2(den)
+
+	res := [2][]fr.Element{make([]fr.Element, n), make([]fr.Element, n)}
+	var z E2
+	z.SetOne()
+	for i := 0; i < n; i++ {
+		res[0][i], res[1][i] = z.A0, z.A1
+		z.Mul(&z, &num[i]).Mul(&z, &den[i])
+	}
+	pk.Domain[0].FFTInverse(res[0])
+	pk.Domain[0].FFTInverse(res[1])
+	return res
+}
+
+// computeQuotientCanonical computes the quotient h of
+//
+//	ql.l+qr.r+qm.l.r+qo.o+qk+pi + α*(z(ωX)∏ⱼ(wⱼ+β*sⱼ+γ) - z∏ⱼ(wⱼ+β*uʲX+γ)) + α²*L₁*(z-1)
+//
+// by Xⁿ-1, where pi interpolates the public inputs. It returns the canonical
+// coefficients of the components in 𝔽ₚ of h₁, h₂, h₃, such that h = h₁ + Xⁿh₂ + X²ⁿh₃.
+func computeQuotientCanonical(pk *ProvingKey, lro [3][]fr.Element, z [2][]fr.Element, pi []fr.Element, alpha, beta, gamma E2) [][]fr.Element {
+	n := pk.Domain[0].Cardinality
+	nbElmts := int(pk.Domain[1].Cardinality)
+
+	// x ranges over the coset shift*<ω'> of the big domain, the values of
+	// xⁿ-1 are periodic of period rho
+	x := powers(pk.Domain[1].Generator, nbElmts)
+	xMinusOne := make([]fr.Element, nbElmts)
+	utils.Parallelize(nbElmts, func(start, end int) {
+		var one fr.Element
+		one.SetOne()
+		for i := start; i < end; i++ {
+			x[i].Mul(&x[i], &pk.Vk.CosetShift)
+			xMinusOne[i].Sub(&x[i], &one)
+		}
+	})
+	xMinusOneInv := fr.BatchInvert(xMinusOne)
+	zh := make([]fr.Element, rho)
+	for i := range zh {
+		zh[i] = expUint64(x[i], n)
+		zh[i].Sub(&zh[i], new(fr.Element).SetOne())
+	}
+	zhInv := fr.BatchInvert(zh)
+
+	var alphaSquare E2
+	alphaSquare.Square(&alpha)
+	var cosetShiftSquare fr.Element
+	cosetShiftSquare.Square(&pk.Vk.CosetShift)
+	shifts := [3]fr.Element{fr.One(), pk.Vk.CosetShift, cosetShiftSquare}
+
+	h := [2][]fr.Element{make([]fr.Element, nbElmts), make([]fr.Element, nbElmts)}
+	utils.Parallelize(nbElmts, func(start, end int) {
+		var gate, t fr.Element
+		var f, g, perm, l1, zx, zwx, res E2
+		for i := start; i < end; i++ {
+			l, r, o := &lro[0][i], &lro[1][i], &lro[2][i]
+
+			// ql.l+qr.r+qm.l.r+qo.o+qk+pi
+			gate.Mul(&pk.Evaluations[idQl][i], l)
+			t.Mul(&pk.Evaluations[idQr][i], r)
+			gate.Add(&gate, &t)
+			t.Mul(&pk.Evaluations[idQm][i], l).Mul(&t, r)
+			gate.Add(&gate, &t)
+			t.Mul(&pk.Evaluations[idQo][i], o)
+			gate.Add(&gate, &t).
+				Add(&gate, &pk.Evaluations[idQk][i]).
+				Add(&gate, &pi[i])
+
+			// z(ωX)∏ⱼ(wⱼ+β*sⱼ+γ) - z∏ⱼ(wⱼ+β*uʲX+γ)
+			zx = E2{z[0][i], z[1][i]}
+			is := (i + rho) % nbElmts
+			zwx = E2{z[0][is], z[1][is]}
+			for j := 0; j < 3; j++ {
+				t.Mul(&x[i], &shifts[j])
+				f.MulByElement(&beta, &t).Add(&f, &gamma).AddElement(&f, &lro[j][i])
+				g.MulByElement(&beta, &pk.Evaluations[idS1+j][i]).Add(&g, &gamma).AddElement(&g, &lro[j][i])
+				zx.Mul(&zx, &f)
+				zwx.Mul(&zwx, &g)
+			}
+			perm.Sub(&zwx, &zx)
+
+			// L₁(X) = (Xⁿ-1)/(n(X-1))
+			t.Mul(&zh[i%rho], &pk.Vk.SizeInv).Mul(&t, &xMinusOneInv[i])
+			l1 = E2{z[0][i], z[1][i]}
+			l1.A0.Sub(&l1.A0, new(fr.Element).SetOne())
+			l1.MulByElement(&l1, &t)
+
+			res.Mul(&l1, &alpha).Add(&res, &perm).Mul(&res, &alpha).AddElement(&res, &gate)
+			res.MulByElement(&res, &zhInv[i%rho])
+			h[0][i], h[1][i] = res.A0, res.A1
+		}
+	})
+
+	pk.Domain[1].FFTInverse(h[0], pk.Vk.CosetShift)
+	pk.Domain[1].FFTInverse(h[1], pk.Vk.CosetShift)
+
+	res := make([][]fr.Element, 6)
+	for i := 0; i < 3; i++ {
+		res[2*i] = h[0][uint64(i)*n : uint64(i+1)*n]
+		res[2*i+1] = h[1][uint64(i)*n : uint64(i+1)*n]
+	}
+	return res
+}
+
+// evaluate returns the evaluation at x of the polynomial p[0]+u*p[1], where
+// p[0], p[1] are in canonical form and p[1] may be nil.
+func evaluate(p [2][]fr.Element, x *E2) E2 {
+	var res [2]E2
+	for k := range p {
+		for i := len(p[k]) - 1; i >= 0; i-- {
+			res[k].Mul(&res[k], x).AddElement(&res[k], &p[k][i])
+		}
+	}
+	// (a0+a1*u)*u = 7a1+a0*u
+	var t E2
+	t.A0.Mul(&res[1].A1, &nonResidue)
+	t.A1.Set(&res[1].A0)
+	return *res[0].Add(&res[0], &t)
+}
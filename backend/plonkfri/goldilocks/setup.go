@@ -0,0 +1,312 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonkfri
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	cs "github.com/consensys/gnark/constraint/goldilocks"
+)
+
+const (
+	// rho is the blowup factor of the low degree extensions
+	rho = 8
+
+	// nbQueries is the number of FRI queries. Each query brings log₂(rho) bits
+	// of conjectured security, so the proofs target ~120 bits.
+	nbQueries = 40
+)
+
+// indices of the polynomials opened at ζ
+const (
+	idQl = iota
+	idQr
+	idQm
+	idQo
+	idQk
+	idS1
+	idS2
+	idS3
+	idL
+	idR
+	idO
+	idZ
+	idH1
+	idH2
+	idH3
+	nbClaims
+
+	nbPreprocessed = idL
+)
+
+// ProvingKey stores the data needed to generate a proof:
+// * ql, prepended with as many -1 as there are public inputs
+// * qr, qm, qo prepended with as many zeroes as there are public inputs
+// * qk, prepended with as many zeroes as there are public inputs, to be
+// completed by the prover with the list of public inputs
+// * s₁, s₂, s₃ and the copy constraint permutation
+// * the Merkle tree of their evaluations on the low degree extension domain
+type ProvingKey struct {
+
+	// Verifying Key is embedded into the proving key (needed by Prove)
+	Vk *VerifyingKey
+
+	// Domains used for the FFTs
+	// 0 -> "small" domain, on which the execution trace is interpolated
+	// 1 -> "big" domain, of size rho*|small|, on whose coset the polynomials
+	// are committed
+	Domain [2]*domain
+
+	// canonical coefficients of ql, qr, qm, qo, qk (incomplete), s₁, s₂, s₃
+	Coefficients [nbPreprocessed][]fr.Element
+
+	// evaluations of the same polynomials on the coset of the big domain
+	Evaluations [nbPreprocessed][]fr.Element
+
+	// s₁, s₂, s₃ in Lagrange basis on the small domain
+	LS [3][]fr.Element
+
+	// position -> permuted position (position in [0,3*sizeSystem-1])
+	Permutation []int64
+
+	leaves [][]fr.Element
+	tree   *merkleTree
+}
+
+// VerifyingKey stores the data needed to verify a proof:
+// * the size of the domain and its generator
+// * the Merkle root of the evaluations of the preprocessed polynomials
+type VerifyingKey struct {
+
+	// Size circuit, that is the closest power of 2 bounding above
+	// number of constraints+number of public inputs
+	Size              uint64
+	SizeInv           fr.Element
+	Generator         fr.Element
+	NbPublicVariables uint64
+
+	// CosetShift generator of the cosets of the small domain used by the
+	// permutation, and shift of the low degree extension domain
+	CosetShift fr.Element
+
+	// Commitment Merkle root of ql, qr, qm, qo, qk, s₁, s₂, s₃
+	Commitment []byte
+}
+
+// Setup sets proving and verifying keys
+func Setup(spr *cs.SparseR1CS) (*ProvingKey, *VerifyingKey, error) {
+
+	if len(spr.GetCustomGates()) != 0 {
+		return nil, nil, errors.New("custom gates are not supported by the plonkfri backend")
+	}
+	if len(spr.GetLookupTables()) != 0 {
+		return nil, nil, errors.New("lookup tables are not supported by the plonkfri backend")
+	}
+
+	var pk ProvingKey
+	var vk VerifyingKey
+
+	// The verifying key shares data with the proving key
+	pk.Vk = &vk
+
+	// fft domains, the size of the trace matches the solver
+	sizeSystem := uint64(spr.GetNbConstraints() + len(spr.Public)) // len(spr.Public) is for the placeholder constraints
+	size := ecc.NextPowerOfTwo(sizeSystem)
+	if size*rho > 1<<maxOrderRoot {
+		return nil, nil, errors.New("circuit too large for the goldilocks plonkfri backend")
+	}
+	pk.Domain[0] = newDomain(size)
+	pk.Domain[1] = newDomain(rho * size)
+
+	vk.Size = size
+	vk.SizeInv.Set(&pk.Domain[0].CardinalityInv)
+	vk.Generator.Set(&pk.Domain[0].Generator)
+	vk.NbPublicVariables = uint64(len(spr.Public))
+	vk.CosetShift.Set(&multiplicativeGen)
+
+	// public polynomials corresponding to constraints: [ placholders | constraints | assertions ]
+	for i := 0; i < idS1; i++ {
+		pk.Coefficients[i] = make([]fr.Element, size)
+	}
+	for i := 0; i < len(spr.Public); i++ { // placeholders (-PUB_INPUT_i + qk_i = 0)
+		pk.Coefficients[idQl][i].SetOne().Neg(&pk.Coefficients[idQl][i])
+	}
+	offset := len(spr.Public)
+	j := 0
+	it := spr.GetSparseR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		pk.Coefficients[idQl][offset+j].Set(&spr.Coefficients[c.QL])
+		pk.Coefficients[idQr][offset+j].Set(&spr.Coefficients[c.QR])
+		pk.Coefficients[idQm][offset+j].Set(&spr.Coefficients[c.QM])
+		pk.Coefficients[idQo][offset+j].Set(&spr.Coefficients[c.QO])
+		pk.Coefficients[idQk][offset+j].Set(&spr.Coefficients[c.QC])
+		j++
+	}
+
+	// build permutation. Note: at this stage, the permutation takes in account the placeholders
+	buildPermutation(spr, &pk)
+	computePermutationPolynomials(&pk)
+
+	// interpolate and commit
+	for i := 0; i < nbPreprocessed; i++ {
+		pk.Domain[0].FFTInverse(pk.Coefficients[i])
+		pk.Evaluations[i] = lde(&pk, pk.Coefficients[i])
+	}
+	pk.leaves, pk.tree = commitColumns(pk.Evaluations[:])
+	vk.Commitment = pk.tree.root()
+
+	return &pk, &vk, nil
+}
+
+// buildPermutation builds the Permutation associated with a circuit.
+//
+// The permutation s is composed of cycles of maximum length such that
+//
+//	s. (l||r||o) = (l||r||o)
+//
+// , where l||r||o is the concatenation of the indices of l, r, o in
+// ql.l+qr.r+qm.l.r+qo.O+k = 0.
+//
+// The permutation is encoded as a slice s of size 3*size(l), where the
+// i-th entry of l||r||o is sent to the s[i]-th entry, so it acts on a tab
+// like this: for i in tab: tab[i] = tab[permutation[i]]
+func buildPermutation(spr *cs.SparseR1CS, pk *ProvingKey) {
+
+	nbVariables := spr.NbInternalVariables + len(spr.Public) + len(spr.Secret)
+	sizeSolution := int(pk.Domain[0].Cardinality)
+
+	// init permutation
+	pk.Permutation = make([]int64, 3*sizeSolution)
+	for i := 0; i < len(pk.Permutation); i++ {
+		pk.Permutation[i] = -1
+	}
+
+	// init LRO position -> variable_ID
+	lro := make([]int, 3*sizeSolution) // position -> variable_ID
+	for i := 0; i < len(spr.Public); i++ {
+		lro[i] = i // IDs of LRO associated to placeholders (only L needs to be taken care of)
+	}
+
+	offset := len(spr.Public)
+
+	j := 0
+	it := spr.GetSparseR1CIterator()
+	for c := it.Next(); c != nil; c = it.Next() {
+		lro[offset+j] = int(c.XA)
+		lro[sizeSolution+offset+j] = int(c.XB)
+		lro[2*sizeSolution+offset+j] = int(c.XC)
+		j++
+	}
+
+	// init cycle:
+	// map ID -> last position the ID was seen
+	cycle := make([]int64, nbVariables)
+	for i := 0; i < len(cycle); i++ {
+		cycle[i] = -1
+	}
+
+	for i := 0; i < len(lro); i++ {
+		if cycle[lro[i]] != -1 {
+			// if != -1, it means we already encountered this value
+			// so we need to set the corresponding permutation index.
+			pk.Permutation[i] = cycle[lro[i]]
+		}
+		cycle[lro[i]] = int64(i)
+	}
+
+	// complete the Permutation by filling the first IDs encountered
+	for i := 0; i < len(pk.Permutation); i++ {
+		if pk.Permutation[i] == -1 {
+			pk.Permutation[i] = cycle[lro[i]]
+		}
+	}
+}
+
+// computePermutationPolynomials sets s₁, s₂, s₃ in Lagrange basis on the small
+// domain, in pk.LS, and copies them in pk.Coefficients for the interpolation.
+func computePermutationPolynomials(pk *ProvingKey) {
+	n := int(pk.Domain[0].Cardinality)
+	id := getIDSmallDomain(pk.Domain[0], &pk.Vk.CosetShift)
+	for j := 0; j < 3; j++ {
+		pk.LS[j] = make([]fr.Element, n)
+		for i := 0; i < n; i++ {
+			pk.LS[j][i].Set(&id[pk.Permutation[j*n+i]])
+		}
+		pk.Coefficients[idS1+j] = make([]fr.Element, n)
+		copy(pk.Coefficients[idS1+j], pk.LS[j])
+	}
+}
+
+// getIDSmallDomain returns the Lagrange form of ID on the small domain:
+// [1,..,gⁿ⁻¹,u,..,u*gⁿ⁻¹,u²,..,u²*gⁿ⁻¹]
+func getIDSmallDomain(d *domain, u *fr.Element) []fr.Element {
+	n := d.Cardinality
+	res := make([]fr.Element, 3*n)
+	res[0].SetOne()
+	res[n].Set(u)
+	res[2*n].Square(u)
+	for i := uint64(1); i < n; i++ {
+		res[i].Mul(&res[i-1], &d.Generator)
+		res[n+i].Mul(&res[n+i-1], &d.Generator)
+		res[2*n+i].Mul(&res[2*n+i-1], &d.Generator)
+	}
+	return res
+}
+
+// lde returns the evaluations of the polynomial of canonical coefficients p
+// on the coset of the big domain.
+func lde(pk *ProvingKey, p []fr.Element) []fr.Element {
+	res := make([]fr.Element, pk.Domain[1].Cardinality)
+	copy(res, p)
+	pk.Domain[1].FFT(res, pk.Vk.CosetShift)
+	return res
+}
+
+// commitColumns commits to columns of evaluations on the big domain. The
+// i-th leaf holds the rows i and i+N/2, which correspond to the points x and
+// -x, so that a FRI query is answered with a single opening per tree.
+func commitColumns(columns [][]fr.Element) ([][]fr.Element, *merkleTree) {
+	half := len(columns[0]) / 2
+	leaves := make([][]fr.Element, half)
+	for i := 0; i < half; i++ {
+		leaves[i] = make([]fr.Element, 0, 2*len(columns))
+		for _, c := range columns {
+			leaves[i] = append(leaves[i], c[i])
+		}
+		for _, c := range columns {
+			leaves[i] = append(leaves[i], c[i+half])
+		}
+	}
+	return leaves, newMerkleTree(leaves)
+}
+
+// NbPublicWitness returns the expected public witness size (number of field elements)
+func (vk *VerifyingKey) NbPublicWitness() int {
+	return int(vk.NbPublicVariables)
+}
+
+// VerifyingKey returns pk.Vk
+func (pk *ProvingKey) VerifyingKey() interface{} {
+	return pk.Vk
+}
+
+// nbFolds returns the number of FRI folding rounds, after which the
+// polynomials of degree < Size are reduced to constants.
+func (vk *VerifyingKey) nbFolds() int {
+	return bits.TrailingZeros64(vk.Size)
+}
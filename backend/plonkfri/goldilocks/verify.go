@@ -0,0 +1,288 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonkfri
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/backend"
+)
+
+var (
+	ErrInvalidAlgebraicRelation = errors.New("algebraic relation does not hold")
+	ErrInvalidProximity         = errors.New("fri: invalid proof of proximity")
+	errInvalidProofShape        = errors.New("invalid proof shape")
+)
+
+func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...backend.VerifierOption) error {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("create backend config: %w", err)
+	}
+	if len(publicWitness) != int(vk.NbPublicVariables) {
+		return fmt.Errorf("invalid public witness size: expected %d, got %d", vk.NbPublicVariables, len(publicWitness))
+	}
+	if err := checkProofShape(proof, vk); err != nil {
+		return err
+	}
+
+	// 0 - derive the challenges with Fiat Shamir
+	fs := newTranscript(cfg.ChallengeHash, vk)
+
+	dataFiatShamir := make([][]byte, 0, len(publicWitness)+2)
+	for i := 0; i < len(publicWitness); i++ {
+		dataFiatShamir = append(dataFiatShamir, publicWitness[i].Marshal())
+	}
+	dataFiatShamir = append(dataFiatShamir, vk.Commitment, proof.LRO)
+	beta, err := deriveRandomness(fs, "beta", dataFiatShamir...)
+	if err != nil {
+		return err
+	}
+	gamma, err := deriveRandomness(fs, "gamma")
+	if err != nil {
+		return err
+	}
+	alpha, err := deriveRandomness(fs, "alpha", proof.Z)
+	if err != nil {
+		return err
+	}
+	zeta, err := deriveRandomness(fs, "zeta", proof.H)
+	if err != nil {
+		return err
+	}
+	dataFiatShamir = dataFiatShamir[:0]
+	for i := range proof.Claims {
+		dataFiatShamir = append(dataFiatShamir, proof.Claims[i].Bytes())
+	}
+	dataFiatShamir = append(dataFiatShamir, proof.ZShifted.Bytes())
+	lambda, err := deriveRandomness(fs, "lambda", dataFiatShamir...)
+	if err != nil {
+		return err
+	}
+	nbFolds := vk.nbFolds()
+	betas := make([]E2, nbFolds)
+	for r := range betas {
+		var data [][]byte
+		if r > 0 {
+			data = append(data, proof.Layers[r-1])
+		}
+		betas[r], err = deriveRandomness(fs, "fold_"+strconv.Itoa(r), data...)
+		if err != nil {
+			return err
+		}
+	}
+	half := rho * vk.Size / 2
+	positions, err := deriveQueries(fs, &proof.Final, half)
+	if err != nil {
+		return err
+	}
+
+	// 1 - check the algebraic relation at ζ
+	if err := checkRelation(proof, vk, publicWitness, alpha, beta, gamma, zeta); err != nil {
+		return err
+	}
+
+	// 2 - check that the claims are consistent with the commitments, and that
+	// the DEEP composition polynomial is of low degree
+	var zetaShifted E2
+	zetaShifted.MulByElement(&zeta, &vk.Generator)
+	generator := rootOfUnity(rho * vk.Size)
+	roots := [4][]byte{vk.Commitment, proof.LRO, proof.Z, proof.H}
+
+	for q, p := range positions {
+		query := &proof.Queries[q]
+		for k := range roots {
+			if err := verifyMerklePath(roots[k], query.Columns[k].Values, int(p), query.Columns[k].Path); err != nil {
+				return err
+			}
+		}
+
+		// evaluations of the DEEP composition polynomial at x and -x
+		x := expUint64(generator, p)
+		x.Mul(&x, &vk.CosetShift)
+		var points [2]fr.Element
+		points[0].Set(&x)
+		points[1].Neg(&x)
+		var denominators [4]E2
+		for s := range points {
+			var t E2
+			t.SetElement(&points[s])
+			denominators[s].Sub(&t, &zeta)
+			denominators[2+s].Sub(&t, &zetaShifted)
+		}
+		inv := batchInvertE2(denominators[:])
+		rows := [4][]fr.Element{query.Columns[0].Values, query.Columns[1].Values, query.Columns[2].Values, query.Columns[3].Values}
+		var values [2]E2
+		for s := range values {
+			f := rowsToClaims(&rows, s)
+			values[s] = deepQuotient(&f, &proof.Claims, &proof.ZShifted, &lambda, &inv[s], &inv[2+s])
+		}
+
+		// fold down to the final constant
+		i := p
+		size := 2 * half
+		for r := 0; r < nbFolds; r++ {
+			if r > 0 {
+				// the previous folding step gave the evaluation at i, which
+				// must be consistent with the committed layer
+				o := &query.Layers[r-1]
+				leaf := []fr.Element{o.Values[0].A0, o.Values[0].A1, o.Values[1].A0, o.Values[1].A1}
+				if err := verifyMerklePath(proof.Layers[r-1], leaf, int(i%(size/2)), o.Path); err != nil {
+					return err
+				}
+				if !o.Values[i/(size/2)].Equal(&values[0]) {
+					return ErrInvalidProximity
+				}
+				if i >= size/2 {
+					x.Neg(&x)
+				}
+				i %= size / 2
+				values = o.Values
+			}
+			var xInv fr.Element
+			xInv.Inverse(&x)
+			values[0] = fold(&values[0], &values[1], &xInv, &betas[r])
+			x.Square(&x)
+			size /= 2
+		}
+		if !values[0].Equal(&proof.Final) {
+			return ErrInvalidProximity
+		}
+	}
+
+	return nil
+}
+
+// checkRelation checks that
+//
+//	ql(ζ)l(ζ)+qr(ζ)r(ζ)+qm(ζ)l(ζ)r(ζ)+qo(ζ)o(ζ)+qk(ζ)+pi(ζ)
+//	 + α*(z(ωζ)∏ⱼ(wⱼ(ζ)+β*sⱼ(ζ)+γ) - z(ζ)∏ⱼ(wⱼ(ζ)+β*uʲζ+γ))
+//	 + α²*L₁(ζ)*(z(ζ)-1)
+//	= (ζⁿ-1)(h₁(ζ)+ζⁿh₂(ζ)+ζ²ⁿh₃(ζ))
+func checkRelation(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, alpha, beta, gamma, zeta E2) error {
+	c := &proof.Claims
+
+	// ζⁿ-1
+	var zetaN, zh, one E2
+	one.SetOne()
+	zetaN.Set(&zeta)
+	for i := uint64(1); i < vk.Size; i <<= 1 {
+		zetaN.Square(&zetaN)
+	}
+	zh.Sub(&zetaN, &one)
+
+	// Lᵢ(ζ) = ωⁱ(ζⁿ-1)/(n(ζ-ωⁱ)), L₁ is L₀ in 0-based indices
+	nbLagrange := len(publicWitness)
+	if nbLagrange == 0 {
+		nbLagrange = 1
+	}
+	den := make([]E2, nbLagrange)
+	w := powers(vk.Generator, nbLagrange)
+	var size fr.Element
+	size.SetUint64(vk.Size)
+	for i := range den {
+		var t E2
+		t.SetElement(&w[i])
+		den[i].Sub(&zeta, &t).MulByElement(&den[i], &size)
+	}
+	den = batchInvertE2(den)
+	var lagrange, pi, t E2
+	for i := range publicWitness {
+		lagrange.MulByElement(&zh, &w[i]).Mul(&lagrange, &den[i])
+		t.MulByElement(&lagrange, &publicWitness[i])
+		pi.Add(&pi, &t)
+	}
+	var l1 E2
+	l1.Mul(&zh, &den[0])
+
+	// gate
+	var gate E2
+	gate.Mul(&c[idQl], &c[idL])
+	t.Mul(&c[idQr], &c[idR])
+	gate.Add(&gate, &t)
+	t.Mul(&c[idQm], &c[idL]).Mul(&t, &c[idR])
+	gate.Add(&gate, &t)
+	t.Mul(&c[idQo], &c[idO])
+	gate.Add(&gate, &t).Add(&gate, &c[idQk]).Add(&gate, &pi)
+
+	// permutation
+	var cosetShiftSquare fr.Element
+	cosetShiftSquare.Square(&vk.CosetShift)
+	shifts := [3]fr.Element{fr.One(), vk.CosetShift, cosetShiftSquare}
+	var f, g, zx, zwx, perm E2
+	zx.Set(&c[idZ])
+	zwx.Set(&proof.ZShifted)
+	for j := 0; j < 3; j++ {
+		f.MulByElement(&zeta, &shifts[j]).Mul(&f, &beta).Add(&f, &gamma).Add(&f, &c[idL+j])
+		g.Mul(&beta, &c[idS1+j]).Add(&g, &gamma).Add(&g, &c[idL+j])
+		zx.Mul(&zx, &f)
+		zwx.Mul(&zwx, &g)
+	}
+	perm.Sub(&zwx, &zx)
+
+	var lhs, rhs E2
+	lhs.Sub(&c[idZ], &one).Mul(&lhs, &l1).Mul(&lhs, &alpha).Add(&lhs, &perm).Mul(&lhs, &alpha).Add(&lhs, &gate)
+
+	rhs.Mul(&c[idH3], &zetaN).Add(&rhs, &c[idH2]).Mul(&rhs, &zetaN).Add(&rhs, &c[idH1]).Mul(&rhs, &zh)
+
+	if !lhs.Equal(&rhs) {
+		return ErrInvalidAlgebraicRelation
+	}
+	return nil
+}
+
+// checkProofShape checks the sizes of the slices of the proof, so that the
+// verifier does not index out of range.
+func checkProofShape(proof *Proof, vk *VerifyingKey) error {
+	if vk.Size == 0 || vk.Size&(vk.Size-1) != 0 || bits.TrailingZeros64(rho*vk.Size) > maxOrderRoot {
+		return errors.New("invalid verifying key")
+	}
+	nbFolds := vk.nbFolds()
+	nbLayers := 0
+	if nbFolds > 0 {
+		nbLayers = nbFolds - 1
+	}
+	if len(proof.Layers) != nbLayers || len(proof.Queries) != nbQueries {
+		return errInvalidProofShape
+	}
+	depth := bits.TrailingZeros64(rho*vk.Size) - 1
+	nbColumns := [4]int{nbPreprocessed, 3, 2, 6}
+	for q := range proof.Queries {
+		query := &proof.Queries[q]
+		for k := range query.Columns {
+			if len(query.Columns[k].Values) != 2*nbColumns[k] || len(query.Columns[k].Path) != depth {
+				return errInvalidProofShape
+			}
+		}
+		if len(query.Layers) != nbLayers {
+			return errInvalidProofShape
+		}
+		for r := range query.Layers {
+			if len(query.Layers[r].Path) != depth-r-1 {
+				return errInvalidProofShape
+			}
+		}
+	}
+	return nil
+}
+
+// rootOfUnity returns the generator of the subgroup of 𝔽ₚ* of size m
+func rootOfUnity(m uint64) fr.Element {
+	return expUint64(multiplicativeGen, (fr.Modulus().Uint64()-1)/m)
+}
@@ -13,12 +13,23 @@
 // limitations under the License.
 
 // Package plonkfri implements PLONK Zero Knowledge Proof system, with FRI as commitment scheme.
+//
+// Besides the scalar fields of the supported curves, circuits can be compiled
+// over the 64-bit Goldilocks field p = 2⁶⁴-2³²+1, whose constraint systems are
+// registered by this package. The Goldilocks backend is transparent and only
+// relies on hash functions: the challenges are sampled in a quadratic
+// extension of the field, and the proofs are not zero knowledge.
 
 package plonkfri
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
 
 	"github.com/consensys/gnark/backend/witness"
 	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
@@ -28,6 +39,7 @@ import (
 	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
 	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
 	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	cs_goldilocks "github.com/consensys/gnark/constraint/goldilocks"
 
 	plonk_bls12377 "github.com/consensys/gnark/backend/plonkfri/bls12-377"
 	plonk_bls12381 "github.com/consensys/gnark/backend/plonkfri/bls12-381"
@@ -36,6 +48,7 @@ import (
 	plonk_bn254 "github.com/consensys/gnark/backend/plonkfri/bn254"
 	plonk_bw6633 "github.com/consensys/gnark/backend/plonkfri/bw6-633"
 	plonk_bw6761 "github.com/consensys/gnark/backend/plonkfri/bw6-761"
+	plonk_goldilocks "github.com/consensys/gnark/backend/plonkfri/goldilocks"
 
 	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -44,6 +57,7 @@ import (
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
 	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	fr_goldilocks "github.com/consensys/gnark-crypto/field/goldilocks"
 )
 
 // Proof represents a Plonk proof generated by plonk.Prove
@@ -73,8 +87,32 @@ type VerifyingKey interface {
 	NbPublicWitness() int // number of elements expected in the public witness
 }
 
+var (
+	errProvingKeyCurve   = fmt.Errorf("%w: the proving key does not match the constraint system curve", gnark.ErrCurveMismatch)
+	errVerifyingKeyCurve = fmt.Errorf("%w: the verifying key does not match the proof curve", gnark.ErrCurveMismatch)
+)
+
+// checkSparseR1CS returns an error if ccs was compiled for a R1CS backend.
+func checkSparseR1CS(ccs constraint.ConstraintSystem) error {
+	if ccs == nil {
+		return nil
+	}
+	if _, ok := ccs.GetCommitments().(constraint.PlonkCommitments); !ok {
+		return errors.New("the constraint system is not a SparseR1CS")
+	}
+	return nil
+}
+
 // Setup prepares the public data associated to a circuit + public inputs.
-func Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
+func Setup(ccs constraint.ConstraintSystem, opts ...backend.SetupOption) (_ ProvingKey, _ VerifyingKey, err error) {
+	cfg, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkSparseR1CS(ccs); err != nil {
+		return nil, nil, err
+	}
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
@@ -91,8 +129,10 @@ func Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
 		return plonk_bw6633.Setup(tccs)
 	case *cs_bls24317.SparseR1CS:
 		return plonk_bls24317.Setup(tccs)
+	case *cs_goldilocks.SparseR1CS:
+		return plonk_goldilocks.Setup(tccs)
 	default:
-		panic("unrecognized SparseR1CS curve type")
+		return nil, nil, errors.New("unrecognized SparseR1CS curve type")
 	}
 
 }
@@ -103,37 +143,85 @@ func Setup(ccs constraint.ConstraintSystem) (ProvingKey, VerifyingKey, error) {
 //		will executes all the prover computations, even if the witness is invalid
 //	 will produce an invalid proof
 //		internally, the solution vector to the SparseR1CS will be filled with random values which may impact benchmarking
-func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkSparseR1CS(ccs); err != nil {
+		return nil, err
+	}
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Prove(tccs, pk.(*plonk_bn254.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bn254.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bn254.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls12381.SparseR1CS:
-		return plonk_bls12381.Prove(tccs, pk.(*plonk_bls12381.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls12381.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls12381.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls12377.SparseR1CS:
-		return plonk_bls12377.Prove(tccs, pk.(*plonk_bls12377.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls12377.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls12377.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bw6761.SparseR1CS:
-		return plonk_bw6761.Prove(tccs, pk.(*plonk_bw6761.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bw6761.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bw6761.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bw6633.SparseR1CS:
-		return plonk_bw6633.Prove(tccs, pk.(*plonk_bw6633.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bw6633.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bw6633.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls24315.SparseR1CS:
-		return plonk_bls24315.Prove(tccs, pk.(*plonk_bls24315.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls24315.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls24315.Prove(tccs, _pk, fullWitness, opts...)
 
 	case *cs_bls24317.SparseR1CS:
-		return plonk_bls24317.Prove(tccs, pk.(*plonk_bls24317.ProvingKey), fullWitness, opts...)
+		_pk, ok := pk.(*plonk_bls24317.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_bls24317.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_goldilocks.SparseR1CS:
+		_pk, ok := pk.(*plonk_goldilocks.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return plonk_goldilocks.Prove(tccs, _pk, fullWitness, opts...)
 
 	default:
-		panic("unrecognized SparseR1CS curve type")
+		return nil, errors.New("unrecognized SparseR1CS curve type")
 	}
 }
 
 // Verify verifies a PLONK proof, from the proof, preprocessed public data, and public witness.
-func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) (err error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
 
 	switch _proof := proof.(type) {
 
@@ -142,50 +230,89 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bn254.Verify(_proof, vk.(*plonk_bn254.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bn254.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bn254.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bls12381.Proof:
 		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls12381.Verify(_proof, vk.(*plonk_bls12381.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls12381.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls12381.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bls12377.Proof:
 		w, ok := publicWitness.Vector().(fr_bls12377.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls12377.Verify(_proof, vk.(*plonk_bls12377.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls12377.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls12377.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bw6761.Proof:
 		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bw6761.Verify(_proof, vk.(*plonk_bw6761.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bw6761.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bw6761.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bw6633.Proof:
 		w, ok := publicWitness.Vector().(fr_bw6633.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bw6633.Verify(_proof, vk.(*plonk_bw6633.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bw6633.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bw6633.Verify(_proof, _vk, w, opts...)
 
 	case *plonk_bls24315.Proof:
 		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls24315.Verify(_proof, vk.(*plonk_bls24315.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls24315.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls24315.Verify(_proof, _vk, w, opts...)
 	case *plonk_bls24317.Proof:
 		w, ok := publicWitness.Vector().(fr_bls24317.Vector)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
-		return plonk_bls24317.Verify(_proof, vk.(*plonk_bls24317.VerifyingKey), w, opts...)
+		_vk, ok := vk.(*plonk_bls24317.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_bls24317.Verify(_proof, _vk, w, opts...)
+
+	case *plonk_goldilocks.Proof:
+		w, ok := publicWitness.Vector().(fr_goldilocks.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*plonk_goldilocks.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return plonk_goldilocks.Verify(_proof, _vk, w, opts...)
 
 	default:
-		panic("unrecognized proof type")
+		return errors.New("unrecognized proof type")
 	}
 }
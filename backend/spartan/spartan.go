@@ -0,0 +1,445 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spartan implements the Spartan Zero Knowledge Proof system, a
+// transparent SNARK for R1CS: it needs no trusted setup.
+//
+// Spartan consumes the R1CS compiled for Groth16. The prover reduces the
+// satisfiability of the R1CS to an evaluation of the multilinear extension of
+// the witness with two sumchecks, and opens a commitment to the witness. The
+// polynomial commitment scheme is chosen at setup (see WithPCS):
+//   - IPA, the default: Pedersen commitments opened with the inner product
+//     argument of Bulletproofs, whose generators are hashed to the curve.
+//     Proofs are logarithmic in the size of the circuit.
+//   - FRI: Merkle commitments to Reed-Solomon encodings, opened with a
+//     univariate sumcheck and FRI. It relies on hash functions only, but the
+//     proofs are larger.
+//
+// The proofs are zero-knowledge: the commitments are hiding, the sumchecks are
+// masked with committed random polynomials, and the setup appends blinding
+// constraints and variables to the R1CS so that the evaluations revealed at
+// the challenges are masked.
+//
+// This is the NIZK variant of the paper: the verifier evaluates the matrices
+// of the R1CS itself, in time linear in their number of non-zero entries.
+// Circuits using commitments (frontend.Committer) are not supported.
+//
+// # See also
+//
+// https://eprint.iacr.org/2019/550
+package spartan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/internal/utils"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint"
+
+	"github.com/consensys/gnark/backend/witness"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+
+	spartan_bls12377 "github.com/consensys/gnark/backend/spartan/bls12-377"
+	spartan_bls12381 "github.com/consensys/gnark/backend/spartan/bls12-381"
+	spartan_bls24315 "github.com/consensys/gnark/backend/spartan/bls24-315"
+	spartan_bls24317 "github.com/consensys/gnark/backend/spartan/bls24-317"
+	spartan_bn254 "github.com/consensys/gnark/backend/spartan/bn254"
+	spartan_bw6633 "github.com/consensys/gnark/backend/spartan/bw6-633"
+	spartan_bw6761 "github.com/consensys/gnark/backend/spartan/bw6-761"
+
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+// Proof represents a Spartan proof generated by spartan.Prove
+//
+// it's underlying implementation is curve specific (see gnark/backend/spartan)
+type Proof interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+}
+
+// ProvingKey represents a Spartan ProvingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/spartan)
+type ProvingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	VerifyingKey() interface{}
+}
+
+// VerifyingKey represents a Spartan VerifyingKey
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/backend/spartan)
+type VerifyingKey interface {
+	io.WriterTo
+	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
+	NbPublicWitness() int // number of elements expected in the public witness
+}
+
+// PCS identifies the polynomial commitment scheme of the proofs
+type PCS uint8
+
+const (
+	// IPA commits with Pedersen commitments opened with the inner product
+	// argument of Bulletproofs
+	IPA PCS = iota
+
+	// FRI commits with Merkle trees of Reed-Solomon encodings opened with FRI
+	FRI
+)
+
+// SetupOption configures the setup
+type SetupOption func(*SetupConfig) error
+
+// SetupConfig is the configuration of the setup
+type SetupConfig struct {
+	backend.SetupConfig
+	PCS PCS
+}
+
+// WithSetupOptions applies the options common to the backends, such as
+// [backend.WithSetupPanicRecovery].
+func WithSetupOptions(opts ...backend.SetupOption) SetupOption {
+	return func(cfg *SetupConfig) error {
+		for _, opt := range opts {
+			if err := opt(&cfg.SetupConfig); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithPCS sets the polynomial commitment scheme of the proofs. It defaults to
+// IPA.
+func WithPCS(pcs PCS) SetupOption {
+	return func(cfg *SetupConfig) error {
+		if pcs != IPA && pcs != FRI {
+			return fmt.Errorf("unknown commitment scheme %d", pcs)
+		}
+		cfg.PCS = pcs
+		return nil
+	}
+}
+
+var (
+	errProvingKeyCurve   = fmt.Errorf("%w: the proving key does not match the constraint system curve", gnark.ErrCurveMismatch)
+	errVerifyingKeyCurve = fmt.Errorf("%w: the verifying key does not match the proof curve", gnark.ErrCurveMismatch)
+)
+
+// checkR1CS returns an error if ccs was compiled for a PLONK backend.
+func checkR1CS(ccs constraint.ConstraintSystem) error {
+	if ccs == nil {
+		return nil
+	}
+	if _, ok := ccs.GetCommitments().(constraint.Groth16Commitments); !ok {
+		return errors.New("the constraint system is not a R1CS")
+	}
+	return nil
+}
+
+// Setup reads the matrices of the R1CS, appends the blinding constraints, and
+// derives the generators of the commitment scheme from a public seed.
+func Setup(ccs constraint.ConstraintSystem, opts ...SetupOption) (_ ProvingKey, _ VerifyingKey, err error) {
+	var cfg SetupConfig
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(ccs); err != nil {
+		return nil, nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.R1CS:
+		return spartan_bn254.Setup(tccs, spartan_bn254.PCS(cfg.PCS))
+	case *cs_bls12381.R1CS:
+		return spartan_bls12381.Setup(tccs, spartan_bls12381.PCS(cfg.PCS))
+	case *cs_bls12377.R1CS:
+		return spartan_bls12377.Setup(tccs, spartan_bls12377.PCS(cfg.PCS))
+	case *cs_bw6761.R1CS:
+		return spartan_bw6761.Setup(tccs, spartan_bw6761.PCS(cfg.PCS))
+	case *cs_bls24317.R1CS:
+		return spartan_bls24317.Setup(tccs, spartan_bls24317.PCS(cfg.PCS))
+	case *cs_bls24315.R1CS:
+		return spartan_bls24315.Setup(tccs, spartan_bls24315.PCS(cfg.PCS))
+	case *cs_bw6633.R1CS:
+		return spartan_bw6633.Setup(tccs, spartan_bw6633.PCS(cfg.PCS))
+	default:
+		return nil, nil, errors.New("unrecognized R1CS curve type")
+	}
+
+}
+
+// Prove generates a Spartan proof from a circuit, its proving key and the full
+// witness.
+func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (_ Proof, err error) {
+	cfg, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+	if err := checkR1CS(ccs); err != nil {
+		return nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.R1CS:
+		_pk, ok := pk.(*spartan_bn254.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return spartan_bn254.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls12381.R1CS:
+		_pk, ok := pk.(*spartan_bls12381.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return spartan_bls12381.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls12377.R1CS:
+		_pk, ok := pk.(*spartan_bls12377.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return spartan_bls12377.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bw6761.R1CS:
+		_pk, ok := pk.(*spartan_bw6761.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return spartan_bw6761.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls24317.R1CS:
+		_pk, ok := pk.(*spartan_bls24317.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return spartan_bls24317.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bls24315.R1CS:
+		_pk, ok := pk.(*spartan_bls24315.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return spartan_bls24315.Prove(tccs, _pk, fullWitness, opts...)
+
+	case *cs_bw6633.R1CS:
+		_pk, ok := pk.(*spartan_bw6633.ProvingKey)
+		if !ok {
+			return nil, errProvingKeyCurve
+		}
+		return spartan_bw6633.Prove(tccs, _pk, fullWitness, opts...)
+	default:
+		return nil, errors.New("unrecognized R1CS curve type")
+	}
+}
+
+// Verify verifies a Spartan proof, from the proof, the verifying key and the
+// public witness.
+func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) (err error) {
+	cfg, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return err
+	}
+	defer utils.RecoverPanic(cfg.RecoverPanics, &err)
+
+	switch _proof := proof.(type) {
+
+	case *spartan_bn254.Proof:
+		w, ok := publicWitness.Vector().(fr_bn254.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*spartan_bn254.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return spartan_bn254.Verify(_proof, _vk, w, opts...)
+
+	case *spartan_bls12381.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12381.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*spartan_bls12381.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return spartan_bls12381.Verify(_proof, _vk, w, opts...)
+
+	case *spartan_bls12377.Proof:
+		w, ok := publicWitness.Vector().(fr_bls12377.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*spartan_bls12377.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return spartan_bls12377.Verify(_proof, _vk, w, opts...)
+
+	case *spartan_bw6761.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6761.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*spartan_bw6761.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return spartan_bw6761.Verify(_proof, _vk, w, opts...)
+
+	case *spartan_bls24317.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24317.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*spartan_bls24317.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return spartan_bls24317.Verify(_proof, _vk, w, opts...)
+
+	case *spartan_bls24315.Proof:
+		w, ok := publicWitness.Vector().(fr_bls24315.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*spartan_bls24315.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return spartan_bls24315.Verify(_proof, _vk, w, opts...)
+
+	case *spartan_bw6633.Proof:
+		w, ok := publicWitness.Vector().(fr_bw6633.Vector)
+		if !ok {
+			return witness.ErrInvalidWitness
+		}
+		_vk, ok := vk.(*spartan_bw6633.VerifyingKey)
+		if !ok {
+			return errVerifyingKeyCurve
+		}
+		return spartan_bw6633.Verify(_proof, _vk, w, opts...)
+	default:
+		return errors.New("unrecognized proof type")
+	}
+}
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface
+// This function exists for serialization purposes
+func NewProvingKey(curveID ecc.ID) ProvingKey {
+	var pk ProvingKey
+	switch curveID {
+	case ecc.BN254:
+		pk = &spartan_bn254.ProvingKey{}
+	case ecc.BLS12_377:
+		pk = &spartan_bls12377.ProvingKey{}
+	case ecc.BLS12_381:
+		pk = &spartan_bls12381.ProvingKey{}
+	case ecc.BW6_761:
+		pk = &spartan_bw6761.ProvingKey{}
+	case ecc.BLS24_317:
+		pk = &spartan_bls24317.ProvingKey{}
+	case ecc.BLS24_315:
+		pk = &spartan_bls24315.ProvingKey{}
+	case ecc.BW6_633:
+		pk = &spartan_bw6633.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pk
+}
+
+// NewProof instantiates a curve-typed Proof and returns an interface
+// This function exists for serialization purposes
+func NewProof(curveID ecc.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case ecc.BN254:
+		proof = &spartan_bn254.Proof{}
+	case ecc.BLS12_377:
+		proof = &spartan_bls12377.Proof{}
+	case ecc.BLS12_381:
+		proof = &spartan_bls12381.Proof{}
+	case ecc.BW6_761:
+		proof = &spartan_bw6761.Proof{}
+	case ecc.BLS24_317:
+		proof = &spartan_bls24317.Proof{}
+	case ecc.BLS24_315:
+		proof = &spartan_bls24315.Proof{}
+	case ecc.BW6_633:
+		proof = &spartan_bw6633.Proof{}
+	default:
+		panic("not implemented")
+	}
+
+	return proof
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var vk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		vk = &spartan_bn254.VerifyingKey{}
+	case ecc.BLS12_377:
+		vk = &spartan_bls12377.VerifyingKey{}
+	case ecc.BLS12_381:
+		vk = &spartan_bls12381.VerifyingKey{}
+	case ecc.BW6_761:
+		vk = &spartan_bw6761.VerifyingKey{}
+	case ecc.BLS24_317:
+		vk = &spartan_bls24317.VerifyingKey{}
+	case ecc.BLS24_315:
+		vk = &spartan_bls24315.VerifyingKey{}
+	case ecc.BW6_633:
+		vk = &spartan_bw6633.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return vk
+}
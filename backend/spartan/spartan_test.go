@@ -0,0 +1,190 @@
+package spartan_test
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	curve_bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/spartan"
+	spartan_bn254 "github.com/consensys/gnark/backend/spartan/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type refCircuit struct {
+	nbConstraints int
+	X             frontend.Variable
+	Y             frontend.Variable `gnark:",public"`
+	Z             frontend.Variable `gnark:",public"`
+}
+
+func (circuit *refCircuit) Define(api frontend.API) error {
+	x := circuit.X
+	for i := 0; i < circuit.nbConstraints; i++ {
+		x = api.Mul(x, x)
+	}
+	api.AssertIsEqual(x, circuit.Y)
+	api.AssertIsEqual(api.Add(circuit.X, api.Mul(3, circuit.Y), 5), circuit.Z)
+	return nil
+}
+
+func referenceAssignment(curve ecc.ID, nbConstraints int) *refCircuit {
+	var good refCircuit
+	good.X = 2
+
+	// compute expected Y and Z
+	expectedY := new(big.Int).SetUint64(2)
+	exp := big.NewInt(1)
+	exp.Lsh(exp, uint(nbConstraints))
+	expectedY.Exp(expectedY, exp, curve.ScalarField())
+	expectedZ := new(big.Int).Mul(expectedY, big.NewInt(3))
+	expectedZ.Add(expectedZ, big.NewInt(7)).Mod(expectedZ, curve.ScalarField())
+
+	good.Y = expectedY
+	good.Z = expectedZ
+	return &good
+}
+
+func TestProver(t *testing.T) {
+	const nbConstraints = 100
+	for _, curve := range getCurves() {
+		for _, pcs := range []spartan.PCS{spartan.IPA, spartan.FRI} {
+			t.Run(curve.String()+"/"+pcsName(pcs), func(t *testing.T) {
+				var b1, b2 bytes.Buffer
+				assert := require.New(t)
+
+				ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+				assert.NoError(err)
+				fullWitness, err := frontend.NewWitness(referenceAssignment(curve, nbConstraints), curve.ScalarField())
+				assert.NoError(err)
+				publicWitness, err := fullWitness.Public()
+				assert.NoError(err)
+
+				pk, vk, err := spartan.Setup(ccs, spartan.WithPCS(pcs))
+				assert.NoError(err)
+
+				// write the PK to ensure it is not mutated
+				_, err = pk.WriteTo(&b1)
+				assert.NoError(err)
+
+				proof, err := spartan.Prove(ccs, pk, fullWitness)
+				assert.NoError(err)
+
+				// check pk
+				_, err = pk.WriteTo(&b2)
+				assert.NoError(err)
+
+				assert.True(bytes.Equal(b1.Bytes(), b2.Bytes()), "spartan prover mutated the proving key")
+
+				assert.NoError(spartan.Verify(proof, vk, publicWitness))
+
+				// wrong public witness
+				bad := referenceAssignment(curve, nbConstraints)
+				bad.Z = 1
+				badWitness, err := frontend.NewWitness(bad, curve.ScalarField(), frontend.PublicOnly())
+				assert.NoError(err)
+				assert.Error(spartan.Verify(proof, vk, badWitness))
+
+				// unsatisfied constraints
+				_, err = spartan.Prove(ccs, pk, badFullWitness(t, curve, nbConstraints))
+				assert.Error(err)
+			})
+		}
+	}
+}
+
+func TestTamperedProof(t *testing.T) {
+	const nbConstraints = 10
+	for _, pcs := range []spartan.PCS{spartan.IPA, spartan.FRI} {
+		t.Run(pcsName(pcs), func(t *testing.T) {
+			assert := require.New(t)
+			curve := ecc.BN254
+
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+			assert.NoError(err)
+			fullWitness, err := frontend.NewWitness(referenceAssignment(curve, nbConstraints), curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := fullWitness.Public()
+			assert.NoError(err)
+
+			pk, vk, err := spartan.Setup(ccs, spartan.WithPCS(pcs))
+			assert.NoError(err)
+			proof, err := spartan.Prove(ccs, pk, fullWitness)
+			assert.NoError(err)
+			assert.NoError(spartan.Verify(proof, vk, publicWitness))
+
+			tampered := *proof.(*spartan_bn254.Proof)
+			tampered.Claims[2].SetOne()
+			assert.Error(spartan.Verify(&tampered, vk, publicWitness))
+
+			tampered = *proof.(*spartan_bn254.Proof)
+			tampered.Sumcheck2 = append([][]fr_bn254.Element(nil), tampered.Sumcheck2...)
+			tampered.Sumcheck2[1] = []fr_bn254.Element{tampered.Sumcheck2[1][1], tampered.Sumcheck2[1][0]}
+			assert.Error(spartan.Verify(&tampered, vk, publicWitness))
+
+			tampered = *proof.(*spartan_bn254.Proof)
+			tampered.WEval.SetOne()
+			assert.Error(spartan.Verify(&tampered, vk, publicWitness))
+
+			tampered = *proof.(*spartan_bn254.Proof)
+			tampered.MaskEvals[1].SetOne()
+			assert.Error(spartan.Verify(&tampered, vk, publicWitness))
+
+			tampered = *proof.(*spartan_bn254.Proof)
+			if pcs == spartan.IPA {
+				opening := &tampered.Openings[0].IPA
+				opening.L = append([]curve_bn254.G1Affine(nil), opening.L...)
+				opening.L[0], opening.L[1] = opening.L[1], opening.L[0]
+			} else {
+				opening := &tampered.Openings[0].FRI
+				opening.Values = append([][]fr_bn254.Element(nil), opening.Values...)
+				opening.Values[0] = append([]fr_bn254.Element(nil), opening.Values[0]...)
+				opening.Values[0][0].SetOne()
+			}
+			assert.Error(spartan.Verify(&tampered, vk, publicWitness))
+
+			// the challenges depend on the hash function
+			assert.Error(spartan.Verify(proof, vk, publicWitness, backend.WithVerifierChallengeHashFunction(sha512.New())))
+		})
+	}
+}
+
+func TestCheckCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&refCircuit{nbConstraints: 5},
+		test.WithValidAssignment(referenceAssignment(ecc.BN254, 5)),
+		test.WithCurves(ecc.BN254),
+		test.WithBackends(backend.SPARTAN),
+	)
+}
+
+func badFullWitness(t *testing.T, curve ecc.ID, nbConstraints int) witness.Witness {
+	bad := referenceAssignment(curve, nbConstraints)
+	bad.X = 3
+	w, err := frontend.NewWitness(bad, curve.ScalarField())
+	require.NoError(t, err)
+	return w
+}
+
+func pcsName(pcs spartan.PCS) string {
+	if pcs == spartan.FRI {
+		return "fri"
+	}
+	return "ipa"
+}
+
+func getCurves() []ecc.ID {
+	if testing.Short() {
+		return []ecc.ID{ecc.BN254}
+	}
+	return gnark.Curves()
+}
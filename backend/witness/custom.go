@@ -0,0 +1,103 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"sync"
+)
+
+// custom vectors are the vectors of the fields registered with RegisterField.
+// They are handled through reflection, as they are not known to this package.
+var (
+	customVectors   = make(map[string]func(size int) any) // indexed by the modulus in hex
+	customTypes     = make(map[reflect.Type]struct{})
+	customVectorsMu sync.RWMutex
+)
+
+// RegisterField registers the vectors of a prime field which is not the scalar
+// field of a curve supported by gnark, so that witnesses can be defined over
+// it. newVector returns a vector of the given size. Its type must be a slice of
+// elements with a SetInterface(any) method, with WriteTo and ReadFrom methods
+// on the pointer to the slice, as the vectors generated by gnark-crypto.
+//
+// It is called by [github.com/consensys/gnark/constraint.RegisterCustomField]
+// and should not be called directly.
+func RegisterField(modulus *big.Int, newVector func(size int) any) {
+	customVectorsMu.Lock()
+	defer customVectorsMu.Unlock()
+	key := modulus.Text(16)
+	if _, ok := customVectors[key]; ok {
+		panic(fmt.Sprintf("field %s already registered", key))
+	}
+	customVectors[key] = newVector
+	customTypes[reflect.TypeOf(newVector(0))] = struct{}{}
+}
+
+func newCustomVector(field *big.Int, size int) (any, bool) {
+	customVectorsMu.RLock()
+	defer customVectorsMu.RUnlock()
+	newVector, ok := customVectors[field.Text(16)]
+	if !ok {
+		return nil, false
+	}
+	return newVector(size), true
+}
+
+func isCustomVector(v any) bool {
+	customVectorsMu.RLock()
+	defer customVectorsMu.RUnlock()
+	_, ok := customTypes[reflect.TypeOf(v)]
+	return ok
+}
+
+func customNewFrom(from any, n int) any {
+	v := reflect.ValueOf(from)
+	res := reflect.MakeSlice(v.Type(), n, n)
+	reflect.Copy(res, v)
+	return res.Interface()
+}
+
+func customSet(v any, index int, value any) error {
+	pv := reflect.ValueOf(v)
+	if index >= pv.Len() {
+		return errors.New("out of bounds")
+	}
+	res := pv.Index(index).Addr().MethodByName("SetInterface").Call([]reflect.Value{reflect.ValueOf(&value).Elem()})
+	if err, _ := res[len(res)-1].Interface().(error); err != nil {
+		return err
+	}
+	return nil
+}
+
+func customIterate(v any) chan any {
+	chValues := make(chan any)
+	pv := reflect.ValueOf(v)
+	go func() {
+		for i := 0; i < pv.Len(); i++ {
+			chValues <- pv.Index(i).Addr().Interface()
+		}
+		close(chValues)
+	}()
+	return chValues
+}
+
+// customPointer returns a pointer to a copy of the vector v, on which the
+// WriteTo and ReadFrom methods are defined.
+func customPointer(v any) reflect.Value {
+	p := reflect.New(reflect.TypeOf(v))
+	p.Elem().Set(reflect.ValueOf(v))
+	return p
+}
+
+func customWriteTo(v any, w io.Writer) (int64, error) {
+	return customPointer(v).Interface().(io.WriterTo).WriteTo(w)
+}
+
+func customReadFrom(v any, r io.Reader) (any, int64, error) {
+	p := customPointer(v)
+	n, err := p.Interface().(io.ReaderFrom).ReadFrom(r)
+	return p.Elem().Interface(), n, err
+}
@@ -0,0 +1,202 @@
+package witness
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// Migration translates the witnesses of a circuit to the witnesses of a new
+// version of the circuit, so that the witnesses created with the old version
+// don't have to be dropped when the circuit is upgraded.
+//
+// The leaves of the witnesses are matched by their full names (see
+// [schema.Schema.WriteSequence]). The leaves of the new schema which are not in
+// the old one must be either renamed leaves, see [WithRename], or new leaves
+// with a default value, see [WithDefault]. The leaves of the old schema which
+// are not in the new one are dropped. Use [schema.Compare] to list them.
+type Migration struct {
+	fromNbPublic, fromNbSecret int
+	nbPublic, nbSecret         int
+	// sources[i] is the index in the old witness of the i-th value of the new
+	// witness, or -1 if it is defaults[i].
+	sources  []int
+	defaults []any
+}
+
+// MigrationOption configures a [Migration].
+type MigrationOption func(*migrationConfig) error
+
+type migrationConfig struct {
+	renames  []rename
+	defaults []leafDefault
+}
+
+type rename struct {
+	from, to string
+}
+
+type leafDefault struct {
+	name  string
+	value any
+}
+
+// WithRename sets that the leaf, or the struct or array of leaves, with full
+// name from in the old schema is named to in the new schema.
+func WithRename(from, to string) MigrationOption {
+	return func(c *migrationConfig) error {
+		if from == "" || to == "" {
+			return fmt.Errorf("empty name in rename")
+		}
+		c.renames = append(c.renames, rename{from: from, to: to})
+		return nil
+	}
+}
+
+// WithDefault sets the value of the leaf, or of the leaves of the struct or
+// array, with full name name which are added in the new schema. The value must
+// be a valid assignment of a leaf, for example an int, a string or a *big.Int.
+func WithDefault(name string, value any) MigrationOption {
+	return func(c *migrationConfig) error {
+		if name == "" {
+			return fmt.Errorf("empty name in default")
+		}
+		if value == nil {
+			return fmt.Errorf("nil default value for %s", name)
+		}
+		c.defaults = append(c.defaults, leafDefault{name: name, value: value})
+		return nil
+	}
+}
+
+// NewMigration returns the migration of the witnesses of the schema from to the
+// witnesses of the schema to.
+func NewMigration(from, to *schema.Schema, opts ...MigrationOption) (*Migration, error) {
+	var cfg migrationConfig
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	fromPublic, fromSecret, err := from.Leaves()
+	if err != nil {
+		return nil, err
+	}
+	toPublic, toSecret, err := to.Leaves()
+	if err != nil {
+		return nil, err
+	}
+	indices := make(map[string]int, len(fromPublic)+len(fromSecret))
+	for i, n := range append(fromPublic, fromSecret...) {
+		indices[n] = i
+	}
+
+	m := &Migration{
+		fromNbPublic: len(fromPublic),
+		fromNbSecret: len(fromSecret),
+		nbPublic:     len(toPublic),
+		nbSecret:     len(toSecret),
+	}
+	m.sources = make([]int, 0, m.nbPublic+m.nbSecret)
+	m.defaults = make([]any, 0, m.nbPublic+m.nbSecret)
+	usedRenames := make([]bool, len(cfg.renames))
+	usedDefaults := make([]bool, len(cfg.defaults))
+	for _, n := range append(toPublic, toSecret...) {
+		// the last matching rename or default has precedence
+		oldName := n
+		for i := len(cfg.renames) - 1; i >= 0; i-- {
+			if suffix, ok := match(n, cfg.renames[i].to); ok {
+				oldName = cfg.renames[i].from + suffix
+				usedRenames[i] = true
+				break
+			}
+		}
+		if idx, ok := indices[oldName]; ok {
+			m.sources = append(m.sources, idx)
+			m.defaults = append(m.defaults, nil)
+			continue
+		}
+		var value any
+		for i := len(cfg.defaults) - 1; i >= 0; i-- {
+			if _, ok := match(n, cfg.defaults[i].name); ok {
+				value = cfg.defaults[i].value
+				usedDefaults[i] = true
+				break
+			}
+		}
+		if value == nil {
+			return nil, fmt.Errorf("%w: no value for the added leaf %s", gnark.ErrSchemaMismatch, n)
+		}
+		m.sources = append(m.sources, -1)
+		m.defaults = append(m.defaults, value)
+	}
+
+	// unused options are most likely typos
+	for i, used := range usedRenames {
+		if !used {
+			return nil, fmt.Errorf("%w: no leaf %s in the new schema", gnark.ErrSchemaMismatch, cfg.renames[i].to)
+		}
+	}
+	for i, used := range usedDefaults {
+		if !used {
+			return nil, fmt.Errorf("%w: no added leaf %s in the new schema", gnark.ErrSchemaMismatch, cfg.defaults[i].name)
+		}
+	}
+	return m, nil
+}
+
+// Migrate returns the witness of the new schema corresponding to the witness w
+// of the old schema. If w is a public witness, the result is the public
+// witness of the new schema.
+func (m *Migration) Migrate(w Witness) (Witness, error) {
+	wt, ok := w.(*witness)
+	if !ok {
+		return nil, fmt.Errorf("unsupported witness implementation %T", w)
+	}
+	publicOnly := wt.nbSecret == 0 && m.fromNbSecret != 0
+	if int(wt.nbPublic) != m.fromNbPublic || (!publicOnly && int(wt.nbSecret) != m.fromNbSecret) {
+		return nil, fmt.Errorf("%w: witness is inconsistent with the old schema", gnark.ErrSchemaMismatch)
+	}
+
+	values := make([]any, 0, wt.nbPublic+wt.nbSecret)
+	for v := range wt.iterate() {
+		values = append(values, v)
+	}
+
+	n := m.nbPublic + m.nbSecret
+	res := &witness{nbPublic: uint32(m.nbPublic), nbSecret: uint32(m.nbSecret)}
+	if publicOnly {
+		n = m.nbPublic
+		res.nbSecret = 0
+	}
+	res.vector = resize(wt.vector, n)
+	for i := 0; i < n; i++ {
+		var v any
+		switch src := m.sources[i]; {
+		case src < 0:
+			v = m.defaults[i]
+		case src >= len(values):
+			return nil, fmt.Errorf("%w: public leaf %d is secret in the old schema", gnark.ErrSchemaMismatch, i)
+		default:
+			v = values[src]
+		}
+		if err := set(res.vector, i, v); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// match returns the suffix of name after prefix if name is prefix or a leaf of
+// the struct or array prefix.
+func match(name, prefix string) (string, bool) {
+	if name == prefix {
+		return "", true
+	}
+	if strings.HasPrefix(name, prefix+"_") {
+		return name[len(prefix):], true
+	}
+	return "", false
+}
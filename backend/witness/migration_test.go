@@ -0,0 +1,84 @@
+package witness_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type circuitV1 struct {
+	X     frontend.Variable `gnark:",public"`
+	Inner struct {
+		A, B frontend.Variable
+	}
+	Old frontend.Variable
+}
+
+func (c *circuitV1) Define(frontend.API) error { return nil }
+
+type circuitV2 struct {
+	X       frontend.Variable `gnark:",public"`
+	Nonce   frontend.Variable `gnark:",public"`
+	Renamed struct {
+		A, B frontend.Variable
+	}
+	Salt [2]frontend.Variable
+}
+
+func (c *circuitV2) Define(frontend.API) error { return nil }
+
+func TestMigration(t *testing.T) {
+	assert := require.New(t)
+
+	from, err := frontend.NewSchema(&circuitV1{})
+	assert.NoError(err)
+	to, err := frontend.NewSchema(&circuitV2{})
+	assert.NoError(err)
+
+	// the added leaves must have a value
+	_, err = witness.NewMigration(from, to, witness.WithRename("Inner", "Renamed"))
+	assert.True(errors.Is(err, gnark.ErrSchemaMismatch))
+	// the options must match leaves
+	_, err = witness.NewMigration(from, to, witness.WithRename("Inner", "Renamed"), witness.WithDefault("Nonce", 7), witness.WithDefault("Salt", 0), witness.WithDefault("Unknown", 0))
+	assert.True(errors.Is(err, gnark.ErrSchemaMismatch))
+
+	m, err := witness.NewMigration(from, to,
+		witness.WithRename("Inner", "Renamed"),
+		witness.WithDefault("Nonce", 7),
+		witness.WithDefault("Salt", 0),
+		witness.WithDefault("Salt_1", 1),
+	)
+	assert.NoError(err)
+
+	old, err := frontend.NewWitness(&circuitV1{X: 1, Inner: struct{ A, B frontend.Variable }{2, 3}, Old: 4}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	migrated, err := m.Migrate(old)
+	assert.NoError(err)
+
+	assignment := &circuitV2{X: 1, Nonce: 7, Renamed: struct{ A, B frontend.Variable }{2, 3}, Salt: [2]frontend.Variable{0, 1}}
+	expected, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.Equal(expected.Vector(), migrated.Vector())
+	b1, err := expected.MarshalBinary()
+	assert.NoError(err)
+	b2, err := migrated.MarshalBinary()
+	assert.NoError(err)
+	assert.Equal(b1, b2)
+
+	// public witnesses migrate to public witnesses
+	oldPublic, err := old.Public()
+	assert.NoError(err)
+	migratedPublic, err := m.Migrate(oldPublic)
+	assert.NoError(err)
+	assert.Equal(fr.Vector{fr.NewElement(1), fr.NewElement(7)}, migratedPublic.Vector())
+
+	// a witness of another schema is rejected
+	_, err = m.Migrate(expected)
+	assert.True(errors.Is(err, gnark.ErrSchemaMismatch))
+}
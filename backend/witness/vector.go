@@ -37,6 +37,8 @@ func newVector(field *big.Int, size int) (any, error) {
 	default:
 		if field.Cmp(tinyfield.Modulus()) == 0 {
 			return make(tinyfield.Vector, size), nil
+		} else if v, ok := newCustomVector(field, size); ok {
+			return v, nil
 		} else {
 			return nil, errors.New("unsupported modulus")
 		}
@@ -78,6 +80,9 @@ func newFrom(from any, n int) (any, error) {
 		copy(a, wt)
 		return a, nil
 	default:
+		if isCustomVector(from) {
+			return customNewFrom(from, n), nil
+		}
 		return nil, errors.New("unsupported modulus")
 	}
 }
@@ -101,6 +106,9 @@ func leafType(v any) reflect.Type {
 	case tinyfield.Vector:
 		return reflect.TypeOf(tinyfield.Element{})
 	default:
+		if isCustomVector(v) {
+			return reflect.TypeOf(v).Elem()
+		}
 		panic("invalid input")
 	}
 }
@@ -156,6 +164,9 @@ func set(v any, index int, value any) error {
 		_, err := pv[index].SetInterface(value)
 		return err
 	default:
+		if isCustomVector(v) {
+			return customSet(v, index, value)
+		}
 		panic("invalid input")
 	}
 }
@@ -220,6 +231,9 @@ func iterate(v any) chan any {
 			close(chValues)
 		}()
 	default:
+		if isCustomVector(v) {
+			return customIterate(v)
+		}
 		panic("invalid input")
 	}
 	return chValues
@@ -244,6 +258,9 @@ func resize(v any, n int) any {
 	case tinyfield.Vector:
 		return make(tinyfield.Vector, n)
 	default:
+		if isCustomVector(v) {
+			return customNewFrom(v, n)
+		}
 		panic("invalid input")
 	}
 }
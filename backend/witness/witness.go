@@ -41,12 +41,12 @@ import (
 	"encoding"
 	"encoding/binary"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"reflect"
 
+	"github.com/consensys/gnark"
 	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
@@ -59,7 +59,9 @@ import (
 	"github.com/consensys/gnark/internal/tinyfield"
 )
 
-var ErrInvalidWitness = errors.New("invalid witness")
+// ErrInvalidWitness is returned when a witness is malformed.
+// It is the same error value as [gnark.ErrInvalidWitness].
+var ErrInvalidWitness = gnark.ErrInvalidWitness
 
 // Witness represents a zkSNARK witness.
 //
@@ -138,7 +140,7 @@ func (w *witness) Fill(nbPublic, nbSecret int, values <-chan any) error {
 	}
 
 	if i != n {
-		return fmt.Errorf("expected %d values, filled only %d", n, i)
+		return fmt.Errorf("%w: expected %d values, filled only %d", gnark.ErrSchemaMismatch, n, i)
 	}
 
 	return nil
@@ -190,7 +192,10 @@ func (w *witness) WriteTo(wr io.Writer) (n int64, err error) {
 	case tinyfield.Vector:
 		m, err = t.WriteTo(wr)
 	default:
-		panic("invalid input")
+		if !isCustomVector(t) {
+			panic("invalid input")
+		}
+		m, err = customWriteTo(t, wr)
 	}
 	n += m
 	return n, err
@@ -236,7 +241,10 @@ func (w *witness) ReadFrom(r io.Reader) (n int64, err error) {
 		m, err = t.ReadFrom(r)
 		w.vector = t
 	default:
-		panic("invalid input")
+		if !isCustomVector(t) {
+			panic("invalid input")
+		}
+		w.vector, m, err = customReadFrom(t, r)
 	}
 
 	n += m
@@ -268,7 +276,7 @@ func (w *witness) Vector() any {
 // convenience method and should be avoided in most cases.
 func (w *witness) ToJSON(s *schema.Schema) ([]byte, error) {
 	if s.NbPublic != int(w.nbPublic) || (w.nbSecret != 0 && w.nbSecret != uint32(s.NbSecret)) {
-		return nil, errors.New("schema is inconsistent with Witness")
+		return nil, fmt.Errorf("%w: schema is inconsistent with Witness", gnark.ErrSchemaMismatch)
 	}
 	typ := reflect.PtrTo(leafType(w.vector))
 	instance := s.Instantiate(typ)
@@ -323,7 +331,7 @@ func (w *witness) FromJSON(s *schema.Schema, data []byte) error {
 	}
 	// walk through the public AND secret values
 	missingAssignment := func(name string) error {
-		return fmt.Errorf("missing assignment for %s", name)
+		return fmt.Errorf("%w: missing assignment for %s", gnark.ErrSchemaMismatch, name)
 	}
 
 	// collect all public values; if any are missing, no point going further.
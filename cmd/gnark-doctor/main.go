@@ -0,0 +1,44 @@
+// Command gnark-doctor inspects the environment and the artifacts of a circuit
+// and prints actionable findings, see the package [doctor].
+//
+// For example:
+//
+//	go run github.com/consensys/gnark/cmd/gnark-doctor \
+//		-cs circuit.r1cs -pk circuit.pk -vk circuit.vk -witness witness.bin
+//
+// The files are the outputs of the WriteTo methods of the constraint system,
+// of the keys and of the witness. The command exits with status 1 if a finding
+// is an error.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/consensys/gnark/doctor"
+)
+
+var (
+	fCS      = flag.String("cs", "", "path of the constraint system")
+	fPK      = flag.String("pk", "", "path of the proving key")
+	fVK      = flag.String("vk", "", "path of the verifying key")
+	fWitness = flag.String("witness", "", "path of the full or public witness")
+)
+
+func main() {
+	flag.Parse()
+	report := doctor.Diagnose(doctor.Artifacts{
+		ConstraintSystem: *fCS,
+		ProvingKey:       *fPK,
+		VerifyingKey:     *fVK,
+		Witness:          *fWitness,
+	})
+	if _, err := report.WriteTo(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "gnark-doctor:", err)
+		os.Exit(1)
+	}
+	if report.HasErrors() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,117 @@
+// Command gnark-field generates the field arithmetic, constraint systems and
+// solver of a user-defined prime field, so that circuits can be compiled and
+// solved over it without forking gnark.
+//
+// For example, from the root of a module example.com/app:
+//
+//	go run github.com/consensys/gnark/cmd/gnark-field \
+//		-modulus 0x7fffffff -package m31 -import example.com/app/m31 -out ./m31
+//
+// generates the field package example.com/app/m31 and the constraint system
+// package example.com/app/m31/cs. Importing the latter registers the field with
+// [constraint.RegisterCustomField]:
+//
+//	import _ "example.com/app/m31/cs"
+//
+//	ccs, err := frontend.Compile(m31.Modulus(), r1cs.NewBuilder, &circuit)
+//
+// The field must fit in 6 words of 64 bits. The proof systems require a
+// pairing-friendly curve or FFT domains defined in gnark-crypto, so only the
+// constraint systems and the solver are generated.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/field/generator"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/generator/backend/template"
+)
+
+const copyrightHolder = "ConsenSys Software Inc."
+
+var (
+	fModulus = flag.String("modulus", "", "field modulus, in decimal or 0x-prefixed hexadecimal")
+	fPackage = flag.String("package", "", "name of the generated field package")
+	fImport  = flag.String("import", "", "import path of the output directory")
+	fOut     = flag.String("out", "", "output directory")
+)
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "gnark-field:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if *fModulus == "" || *fPackage == "" || *fImport == "" || *fOut == "" {
+		flag.Usage()
+		return errors.New("missing flag")
+	}
+	modulus, ok := new(big.Int).SetString(*fModulus, 0)
+	if !ok {
+		return fmt.Errorf("invalid modulus %q", *fModulus)
+	}
+	if !modulus.ProbablyPrime(20) {
+		return fmt.Errorf("modulus %s is not prime", modulus)
+	}
+	if len(modulus.Bits()) > len(constraint.Element{}) {
+		return fmt.Errorf("modulus %s does not fit in %d words", modulus, len(constraint.Element{}))
+	}
+
+	// field arithmetic
+	conf, err := config.NewFieldConfig(*fPackage, "Element", *fModulus, false)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*fOut, 0700); err != nil {
+		return err
+	}
+	if err := generator.GenerateFF(conf, *fOut); err != nil {
+		return err
+	}
+
+	// constraint systems
+	csDir := filepath.Join(*fOut, "cs")
+	if err := os.MkdirAll(csDir, 0700); err != nil {
+		return err
+	}
+	data := struct {
+		Curve       string
+		CurveID     string
+		FieldImport string
+	}{
+		Curve:       *fPackage,
+		CurveID:     "UNKNOWN",
+		FieldImport: *fImport,
+	}
+	imports, err := fs.ReadFile(template.FS, "imports.go.tmpl")
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{"system", "coeff", "solver", "custom"} {
+		tmpl, err := fs.ReadFile(template.FS, "representations/"+name+".go.tmpl")
+		if err != nil {
+			return err
+		}
+		if err := bavard.GenerateFromString(filepath.Join(csDir, name+".go"), []string{string(tmpl), string(imports)}, data,
+			bavard.Apache2(copyrightHolder, 2020),
+			bavard.GeneratedBy("gnark"),
+			bavard.Package("cs"),
+			bavard.Format(true),
+		); err != nil {
+			return fmt.Errorf("generate %s.go: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const goModTemplate = `module example.com/app
+
+go 1.19
+
+require github.com/consensys/gnark v0.0.0
+
+replace github.com/consensys/gnark => %s
+`
+
+// solveTest compiles and solves a circuit over the generated field, with both
+// builders. x³ = y holds over p = 2³¹-1 but not over the native fields.
+const solveTest = `package app
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+
+	"example.com/app/m31"
+	_ "example.com/app/m31/cs"
+)
+
+type circuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+func TestSolve(t *testing.T) {
+	const y = (1 << 60) % 0x7fffffff
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(m31.Modulus(), newBuilder, &circuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tc := range []struct {
+			y     int
+			valid bool
+		}{{y, true}, {y + 1, false}} {
+			w, err := frontend.NewWitness(&circuit{X: 1 << 20, Y: tc.y}, m31.Modulus())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ccs.Solve(w); (err == nil) != tc.valid {
+				t.Fatalf("y=%d: valid=%t, got err=%v", tc.y, tc.valid, err)
+			}
+		}
+	}
+}
+`
+
+func TestGenerateAndSolve(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping generation of a custom field in short mode")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	*fModulus = "0x7fffffff"
+	*fPackage = "m31"
+	*fImport = "example.com/app/m31"
+	*fOut = filepath.Join(dir, "m31")
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"go.mod":        fmt.Sprintf(goModTemplate, root),
+		"go.sum":        string(goSum),
+		"solve_test.go": solveTest,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test in generated module: %v\n%s", err, out)
+	}
+}
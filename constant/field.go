@@ -0,0 +1,107 @@
+package constant
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Field performs arithmetic on constants modulo the modulus of the native
+// field of the compilation. It allows to compute derived constants in Define,
+// for example 2ᵏ mod p or the inverse of a constant, without emitting any
+// constraint and without hardcoding the modulus of a specific curve.
+//
+// The operands are Go values accepted as constants by [frontend.API]:
+// integers, *big.Int, strings in base 10 and field elements. They are reduced
+// modulo p and the results are always in [0, p). Circuit variables are not
+// accepted, even if they are constant expressions.
+type Field struct {
+	modulus *big.Int
+}
+
+// NewField returns the constant arithmetic bound to the native field of api.
+func NewField(api frontend.API) *Field {
+	return &Field{modulus: new(big.Int).Set(api.Compiler().Field())}
+}
+
+// Modulus returns a copy of the modulus p of the field.
+func (f *Field) Modulus() *big.Int {
+	return new(big.Int).Set(f.modulus)
+}
+
+// ValueOf returns v reduced modulo p.
+func (f *Field) ValueOf(v any) *big.Int {
+	if frontend.IsCanonical(v) {
+		panic("circuit variables are not constants")
+	}
+	res := utils.FromInterface(v)
+	return res.Mod(&res, f.modulus)
+}
+
+// Add returns a + b mod p.
+func (f *Field) Add(a, b any) *big.Int {
+	res := f.ValueOf(a)
+	res.Add(res, f.ValueOf(b))
+	return res.Mod(res, f.modulus)
+}
+
+// Sub returns a - b mod p.
+func (f *Field) Sub(a, b any) *big.Int {
+	res := f.ValueOf(a)
+	res.Sub(res, f.ValueOf(b))
+	return res.Mod(res, f.modulus)
+}
+
+// Neg returns -a mod p.
+func (f *Field) Neg(a any) *big.Int {
+	res := f.ValueOf(a)
+	res.Sub(f.modulus, res)
+	return res.Mod(res, f.modulus)
+}
+
+// Mul returns a · b mod p.
+func (f *Field) Mul(a, b any) *big.Int {
+	res := f.ValueOf(a)
+	res.Mul(res, f.ValueOf(b))
+	return res.Mod(res, f.modulus)
+}
+
+// Inverse returns a⁻¹ mod p. It panics if a is zero modulo p.
+func (f *Field) Inverse(a any) *big.Int {
+	res := f.ValueOf(a)
+	if res.ModInverse(res, f.modulus) == nil {
+		panic(fmt.Sprintf("%v is not invertible", a))
+	}
+	return res
+}
+
+// Div returns a / b mod p. It panics if b is zero modulo p.
+func (f *Field) Div(a, b any) *big.Int {
+	return f.Mul(a, f.Inverse(b))
+}
+
+// Exp returns aᵉ mod p. A negative exponent is the exponent of the inverse.
+func (f *Field) Exp(a any, e *big.Int) *big.Int {
+	if e.Sign() < 0 {
+		return f.Exp(f.Inverse(a), new(big.Int).Neg(e))
+	}
+	res := f.ValueOf(a)
+	return res.Exp(res, e, f.modulus)
+}
+
+// Pow2 returns 2ᵏ mod p.
+func (f *Field) Pow2(k int) *big.Int {
+	return f.Exp(2, big.NewInt(int64(k)))
+}
+
+// Sqrt returns a square root of a mod p and true if a is a quadratic residue,
+// and nil and false otherwise.
+func (f *Field) Sqrt(a any) (*big.Int, bool) {
+	res := f.ValueOf(a)
+	if res.ModSqrt(res, f.modulus) == nil {
+		return nil, false
+	}
+	return res, true
+}
@@ -0,0 +1,34 @@
+package constant
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type fieldCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *fieldCircuit) Define(api frontend.API) error {
+	f := NewField(api)
+	api.AssertIsEqual(api.Mul(c.X, f.Inverse(3)), c.Y)
+	api.AssertIsEqual(api.Mul(f.Pow2(300), f.Exp(2, big.NewInt(-300))), 1)
+	api.AssertIsEqual(f.Add(f.Modulus(), 5), 5)
+	api.AssertIsEqual(f.Add(f.Neg(4), f.Sub(4, 0)), 0)
+	api.AssertIsEqual(f.Div(10, 5), 2)
+	if r, ok := f.Sqrt(f.Mul(7, 7)); !ok || f.Mul(r, r).Int64() != 49 {
+		panic("invalid square root")
+	}
+	return nil
+}
+
+func TestField(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&fieldCircuit{},
+		test.WithValidAssignment(&fieldCircuit{X: 6, Y: 2}),
+		test.WithInvalidAssignment(&fieldCircuit{X: 7, Y: 2}),
+	)
+}
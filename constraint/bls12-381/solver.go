@@ -19,6 +19,7 @@ package cs
 import (
 	"errors"
 	"fmt"
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/field/pool"
 	"github.com/consensys/gnark/constraint"
@@ -27,10 +28,12 @@ import (
 	"math"
 	"math/big"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 )
@@ -53,6 +56,47 @@ type solver struct {
 	a, b, c fr.Vector // R1CS solver will compute the a,b,c matrices
 
 	q *big.Int
+
+	// if set, panics are returned as errors (see csolver.WithPanicRecovery)
+	recoverPanics bool
+
+	// if set, the solver stops after this duration (see csolver.WithTimeout)
+	timeout             time.Duration
+	timedOut            uint32 // set to 1 when the timeout is exceeded
+	nbSolvedConstraints uint64
+	hintCalls           hintCalls
+}
+
+// nbRecentHints is the number of hint calls reported on timeout.
+const nbRecentHints = 8
+
+// hintCalls records the last hint calls when the solver has a timeout.
+type hintCalls struct {
+	sync.Mutex
+	calls [nbRecentHints]gnark.HintCall
+	n     int // total number of calls
+}
+
+func (h *hintCalls) add(name string, d time.Duration) {
+	h.Lock()
+	h.calls[h.n%nbRecentHints] = gnark.HintCall{Name: name, Duration: d}
+	h.n++
+	h.Unlock()
+}
+
+// recent returns the recorded calls from the oldest to the most recent.
+func (h *hintCalls) recent() []gnark.HintCall {
+	h.Lock()
+	defer h.Unlock()
+	start := 0
+	if h.n > nbRecentHints {
+		start = h.n - nbRecentHints
+	}
+	res := make([]gnark.HintCall, 0, h.n-start)
+	for i := start; i < h.n; i++ {
+		res = append(res, h.calls[i%nbRecentHints])
+	}
+	return res
 }
 
 func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver, error) {
@@ -72,7 +116,7 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 	expectedWitnessSize := len(cs.Public) - witnessOffset + len(cs.Secret)
 
 	if len(witness) != expectedWitnessSize {
-		return nil, fmt.Errorf("invalid witness size, got %d, expected %d", len(witness), expectedWitnessSize)
+		return nil, fmt.Errorf("%w: invalid witness size, got %d, expected %d", gnark.ErrInvalidWitness, len(witness), expectedWitnessSize)
 	}
 
 	// check all hints are there
@@ -97,6 +141,8 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 		mHintsFunctions: hintFunctions,
 		logger:          opt.Logger,
 		q:               cs.Field(),
+		recoverPanics:   opt.RecoverPanics,
+		timeout:         opt.Timeout,
 	}
 
 	// set the witness indexes as solved
@@ -158,8 +204,8 @@ func (s *solver) computeTerm(t constraint.Term) fr.Element {
 		res.Neg(&s.values[vID])
 		return res
 	default:
-		var res fr.Element
-		res.Mul(&s.Coefficients[cID], &s.values[vID])
+		res := s.values[vID]
+		s.mulByCoeff(&res, cID)
 		return res
 	}
 }
@@ -187,8 +233,8 @@ func (s *solver) accumulateInto(t constraint.Term, r *fr.Element) {
 	case constraint.CoeffIdMinusOne:
 		r.Sub(r, &s.values[vID])
 	default:
-		var res fr.Element
-		res.Mul(&s.Coefficients[cID], &s.values[vID])
+		res := s.values[vID]
+		s.mulByCoeff(&res, cID)
 		r.Add(r, &res)
 	}
 }
@@ -227,7 +273,14 @@ func (s *solver) solveWithHint(h *constraint.HintMapping) error {
 		v.BigInt(inputs[i])
 	}
 
-	err := f(q, inputs, outputs)
+	var err error
+	if s.timeout != 0 {
+		start := time.Now()
+		err = f(q, inputs, outputs)
+		s.hintCalls.add(csolver.GetHintName(f), time.Since(start))
+	} else {
+		err = f(q, inputs, outputs)
+	}
 
 	var v fr.Element
 	for i := range outputs {
@@ -292,9 +345,13 @@ func (s *solver) logValue(log constraint.LogEntry) string {
 		// after
 		if missingValue {
 			toResolve = append(toResolve, unsolvedVariable)
-		} else {
+		} else if f := log.FormatOf(j); f.IsDefault() {
 			// we have to append our accumulator
 			toResolve = append(toResolve, eval.String())
+		} else {
+			var b big.Int
+			eval.BigInt(&b)
+			toResolve = append(toResolve, f.Format(&b))
 		}
 
 	}
@@ -385,6 +442,13 @@ func (solver *solver) processInstruction(pi constraint.PackedInstruction, scratc
 	inst := pi.Unpack(&solver.System)
 	cID := inst.ConstraintOffset // here we have 1 constraint in the instruction only
 
+	if solver.timeout != 0 {
+		if atomic.LoadUint32(&solver.timedOut) != 0 {
+			return solver.timeoutError(cID)
+		}
+		defer atomic.AddUint64(&solver.nbSolvedConstraints, uint64(blueprint.NbConstraints()))
+	}
+
 	if solver.Type == constraint.SystemR1CS {
 		if bc, ok := blueprint.(constraint.BlueprintR1C); ok {
 			// TODO @gbotrel we use the solveR1C method for now, having user-defined
@@ -413,6 +477,42 @@ func (solver *solver) processInstruction(pi constraint.PackedInstruction, scratc
 	return nil
 }
 
+// processTask processes the instructions of a task of the worker pool. Panics
+// can't be recovered by the caller of a worker, so they are recovered here.
+func (solver *solver) processTask(t []int, scratch *scratch) (err error) {
+	defer solver.recoverPanic(&err)
+	for _, i := range t {
+		if err := solver.processInstruction(solver.Instructions[i], scratch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recoverPanic recovers from a panic and sets *err to an error wrapping
+// gnark.ErrPanic, if the solver was created with csolver.WithPanicRecovery.
+// It must be deferred directly.
+func (solver *solver) recoverPanic(err *error) {
+	if !solver.recoverPanics {
+		return
+	}
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v\n%s", gnark.ErrPanic, r, debug.Stack())
+	}
+}
+
+// timeoutError returns the error describing the progress of the solver when
+// the timeout was detected while solving the constraint cID.
+func (solver *solver) timeoutError(cID uint32) *SolverTimeoutError {
+	return &SolverTimeoutError{
+		Timeout:       solver.timeout,
+		NbConstraints: solver.GetNbConstraints(),
+		NbSolved:      int(atomic.LoadUint64(&solver.nbSolvedConstraints)),
+		CID:           int(cID),
+		RecentHints:   solver.hintCalls.recent(),
+	}
+}
+
 // run runs the solver. it return an error if a constraint is not satisfied or if not all wires
 // were instantiated.
 func (solver *solver) run() error {
@@ -428,6 +528,13 @@ func (solver *solver) run() error {
 	// first we solve the unsolved wire (if any)
 	// then we check that the constraint is valid
 	// if a[i] * b[i] != c[i]; it means the constraint is not satisfied
+	if solver.timeout != 0 {
+		timer := time.AfterFunc(solver.timeout, func() {
+			atomic.StoreUint32(&solver.timedOut, 1)
+		})
+		defer timer.Stop()
+	}
+
 	var wg sync.WaitGroup
 	chTasks := make(chan []int, runtime.NumCPU())
 	chError := make(chan error, runtime.NumCPU())
@@ -439,12 +546,10 @@ func (solver *solver) run() error {
 		go func() {
 			var scratch scratch
 			for t := range chTasks {
-				for _, i := range t {
-					if err := solver.processInstruction(solver.Instructions[i], &scratch); err != nil {
-						chError <- err
-						wg.Done()
-						return
-					}
+				if err := solver.processTask(t, &scratch); err != nil {
+					chError <- err
+					wg.Done()
+					return
 				}
 				wg.Done()
 			}
@@ -618,18 +723,10 @@ func (solver *solver) solveR1C(cID uint32, r *constraint.R1C) error {
 }
 
 // UnsatisfiedConstraintError wraps an error with useful metadata on the unsatisfied constraint
-type UnsatisfiedConstraintError struct {
-	Err       error
-	CID       int     // constraint ID
-	DebugInfo *string // optional debug info
-}
+type UnsatisfiedConstraintError = gnark.UnsatisfiedConstraintError
 
-func (r *UnsatisfiedConstraintError) Error() string {
-	if r.DebugInfo != nil {
-		return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, *r.DebugInfo)
-	}
-	return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, r.Err.Error())
-}
+// SolverTimeoutError describes the progress of the solver when its timeout is exceeded
+type SolverTimeoutError = gnark.SolverTimeoutError
 
 func (solver *solver) wrapErrWithDebugInfo(cID uint32, err error) *UnsatisfiedConstraintError {
 	var debugInfo *string
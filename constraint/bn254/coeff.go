@@ -28,12 +28,14 @@ import (
 type CoeffTable struct {
 	Coefficients []fr.Element
 	mCoeffs      map[fr.Element]uint32 // maps coefficient to coeffID
+	classes      []coeffClass          // classes[cID] is the class of Coefficients[cID]; not serialized
 }
 
 func newCoeffTable(capacity int) CoeffTable {
 	r := CoeffTable{
 		Coefficients: make([]fr.Element, 5, 5+capacity),
 		mCoeffs:      make(map[fr.Element]uint32, capacity),
+		classes:      make([]coeffClass, 5, 5+capacity),
 	}
 
 	r.Coefficients[constraint.CoeffIdZero].SetUint64(0)
@@ -41,11 +43,104 @@ func newCoeffTable(capacity int) CoeffTable {
 	r.Coefficients[constraint.CoeffIdTwo].SetUint64(2)
 	r.Coefficients[constraint.CoeffIdMinusOne].SetInt64(-1)
 	r.Coefficients[constraint.CoeffIdMinusTwo].SetInt64(-2)
+	r.classify()
 
 	return r
 
 }
 
+// coeffClass tags a coefficient c = ±m⋅2ᵏ for which a multiplication by c is
+// cheaper with MulBy3, MulBy5, MulBy13 or Double than with a generic field
+// multiplication. This is the case for ±2, ±3, ±4, ±5 and ±13; the other small
+// coefficients (e.g. ±6, ±8 or ±10) need several operations and are not faster.
+//
+// The powers of two ±2ᵏ are also tagged, for any k. They mostly appear when
+// recomposing a value from its bits, where the wire they multiply is boolean
+// and the product is either 0 or the coefficient itself.
+type coeffClass struct {
+	m    uint8 // 1, 3, 5 or 13; 0 if the coefficient has no fast path
+	k    uint8
+	neg  bool
+	pow2 bool // c = ±2ᵏ
+}
+
+// fastCoeffs maps the coefficients with a fast path to their class
+var fastCoeffs = make(map[fr.Element]coeffClass)
+
+func init() {
+	for _, c := range []coeffClass{{m: 1, k: 1, pow2: true}, {m: 1, k: 2, pow2: true}, {m: 3}, {m: 5}, {m: 13}} {
+		var v fr.Element
+		v.SetUint64(uint64(c.m) << c.k)
+		fastCoeffs[v] = c
+		v.Neg(&v)
+		c.neg = true
+		fastCoeffs[v] = c
+	}
+}
+
+// classOf returns the class of the coefficient c.
+func classOf(c *fr.Element) coeffClass {
+	if cl, ok := fastCoeffs[*c]; ok {
+		return cl
+	}
+	var b big.Int
+	c.BigInt(&b)
+	if b.BitLen()-1 == int(b.TrailingZeroBits()) {
+		return coeffClass{pow2: true}
+	}
+	var n fr.Element
+	n.Neg(c).BigInt(&b)
+	if b.BitLen()-1 == int(b.TrailingZeroBits()) {
+		return coeffClass{pow2: true, neg: true}
+	}
+	return coeffClass{}
+}
+
+// classify sets the classes of all the coefficients. It must be called when the
+// coefficients are not added through AddCoeff, e.g. after deserialization.
+func (ct *CoeffTable) classify() {
+	ct.classes = ct.classes[:0]
+	for i := range ct.Coefficients {
+		ct.classes = append(ct.classes, classOf(&ct.Coefficients[i]))
+	}
+}
+
+// mulByCoeff sets res = res * Coefficients[cID]
+func (ct *CoeffTable) mulByCoeff(res *fr.Element, cID int) {
+	if cID >= len(ct.classes) {
+		res.Mul(res, &ct.Coefficients[cID])
+		return
+	}
+	c := ct.classes[cID]
+	if c.pow2 {
+		if res.IsZero() {
+			return
+		}
+		if res.IsOne() {
+			*res = ct.Coefficients[cID]
+			return
+		}
+	}
+	if c.m == 0 {
+		res.Mul(res, &ct.Coefficients[cID])
+		return
+	}
+	switch c.m {
+	case 3:
+		fr.MulBy3(res)
+	case 5:
+		fr.MulBy5(res)
+	case 13:
+		fr.MulBy13(res)
+	}
+	for i := uint8(0); i < c.k; i++ {
+		res.Double(res)
+	}
+	if c.neg {
+		res.Neg(res)
+	}
+}
+
 func (ct *CoeffTable) AddCoeff(coeff constraint.Element) uint32 {
 	c := (*fr.Element)(coeff[:])
 	var cID uint32
@@ -66,6 +161,7 @@ func (ct *CoeffTable) AddCoeff(coeff constraint.Element) uint32 {
 		} else {
 			cID = uint32(len(ct.Coefficients))
 			ct.Coefficients = append(ct.Coefficients, cc)
+			ct.classes = append(ct.classes, classOf(&cc))
 			ct.mCoeffs[cc] = cID
 		}
 	}
@@ -17,10 +17,12 @@
 package cs
 
 import (
+	"fmt"
 	"github.com/fxamacker/cbor/v2"
 	"io"
 	"time"
 
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	csolver "github.com/consensys/gnark/constraint/solver"
@@ -61,11 +63,16 @@ func newSystem(capacity int, t constraint.SystemType) *system {
 // Solve solves the constraint system with provided witness.
 // If it's a R1CS returns R1CSSolution
 // If it's a SparseR1CS returns SparseR1CSSolution
-func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, error) {
+func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (_ any, err error) {
 	log := logger.Logger().With().Int("nbConstraints", cs.GetNbConstraints()).Logger()
 	start := time.Now()
 
-	v := witness.Vector().(fr.Vector)
+	v, ok := witness.Vector().(fr.Vector)
+	if !ok {
+		err := fmt.Errorf("%w: expected witness of type fr.Vector, got %T", gnark.ErrCurveMismatch, witness.Vector())
+		log.Err(err).Send()
+		return nil, err
+	}
 
 	// init the solver
 	solver, err := newSolver(cs, v, opts...)
@@ -74,6 +81,9 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 		return nil, err
 	}
 
+	// recover panics (hints, logs) if the solver was configured to do so
+	defer solver.recoverPanic(&err)
+
 	// defer log printing once all solver.values are computed
 	// (or sooner, if a constraint is not satisfied)
 	defer solver.printLogs(cs.Logs)
@@ -172,10 +182,14 @@ func (cs *system) ReadFrom(r io.Reader) (int64, error) {
 	if err := decoder.Decode(&cs); err != nil {
 		return int64(decoder.NumBytesRead()), err
 	}
+	cs.CoeffTable.classify()
 
 	if err := cs.CheckSerializationHeader(); err != nil {
 		return int64(decoder.NumBytesRead()), err
 	}
+	if cs.Field().Cmp(fr.Modulus()) != 0 {
+		return int64(decoder.NumBytesRead()), fmt.Errorf("%w: constraint system is defined over %s, expected %s", gnark.ErrCurveMismatch, cs.Field().Text(16), fr.Modulus().Text(16))
+	}
 
 	switch v := cs.CommitmentInfo.(type) {
 	case *constraint.Groth16Commitments:
@@ -366,6 +380,8 @@ func getTagSet() cbor.TagSet {
 	addType(reflect.TypeOf(constraint.BlueprintLookupHint{}))
 	addType(reflect.TypeOf(constraint.Groth16Commitments{}))
 	addType(reflect.TypeOf(constraint.PlonkCommitments{}))
+	addType(reflect.TypeOf(constraint.BlueprintCustomGate{}))
+	addType(reflect.TypeOf(constraint.BlueprintLookup{}))
 
 	return ts
 }
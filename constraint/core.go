@@ -127,6 +127,12 @@ type System struct {
 	CommitmentInfo Commitments
 	GkrInfo        GkrInfo
 
+	// custom gates of a PLONK constraint system, see [CustomGate].
+	CustomGates []CustomGate
+
+	// lookup tables of a PLONK constraint system, see [LookupTable].
+	LookupTables []LookupTable
+
 	genericHint BlueprintID
 }
 
@@ -169,6 +175,28 @@ func (system *System) AddBlueprint(b Blueprint) BlueprintID {
 	return BlueprintID(len(system.Blueprints) - 1)
 }
 
+// AddCustomGate registers the custom gate g and returns its index.
+func (system *System) AddCustomGate(g CustomGate) uint32 {
+	system.CustomGates = append(system.CustomGates, g)
+	return uint32(len(system.CustomGates) - 1)
+}
+
+// GetCustomGates returns the custom gates of the system.
+func (system *System) GetCustomGates() []CustomGate {
+	return system.CustomGates
+}
+
+// AddLookupTable registers the lookup table t and returns its index.
+func (system *System) AddLookupTable(t LookupTable) uint32 {
+	system.LookupTables = append(system.LookupTables, t)
+	return uint32(len(system.LookupTables) - 1)
+}
+
+// GetLookupTables returns the lookup tables of the system.
+func (system *System) GetLookupTables() []LookupTable {
+	return system.LookupTables
+}
+
 func (system *System) GetNbSecretVariables() int {
 	return len(system.Secret)
 }
@@ -190,6 +218,9 @@ func (system *System) CheckSerializationHeader() error {
 		return fmt.Errorf("when parsing gnark version: %w", err)
 	}
 
+	if binaryVersion.Major != objectVersion.Major {
+		return fmt.Errorf("%w: binary %s, object %s", gnark.ErrVersionMismatch, binaryVersion.String(), objectVersion.String())
+	}
 	if binaryVersion.Compare(objectVersion) != 0 {
 		log := logger.Logger()
 		log.Warn().Str("binary", binaryVersion.String()).Str("object", objectVersion.String()).Msg("gnark version (binary) mismatch with constraint system. there are no guarantees on compatibility")
@@ -204,7 +235,7 @@ func (system *System) CheckSerializationHeader() error {
 		return fmt.Errorf("when parsing serialized modulus: %s", system.ScalarField)
 	}
 	curveID := utils.FieldToCurve(scalarField)
-	if curveID == ecc.UNKNOWN && scalarField.Cmp(tinyfield.Modulus()) != 0 {
+	if _, custom := GetCustomField(scalarField); curveID == ecc.UNKNOWN && scalarField.Cmp(tinyfield.Modulus()) != 0 && !custom {
 		return fmt.Errorf("unsupported scalar field %s", scalarField.Text(16))
 	}
 	system.q = new(big.Int).Set(scalarField)
@@ -313,6 +344,7 @@ func (system *System) AddCommitment(c Commitment) error {
 }
 
 func (system *System) AddLog(l LogEntry) {
+	l.ConstraintID = system.NbConstraints
 	system.Logs = append(system.Logs, l)
 }
 
@@ -330,10 +362,16 @@ func (system *System) VariableToString(vID int) string {
 	nbSecret := system.GetNbSecretVariables()
 
 	if vID < nbPublic {
+		if system.Public[vID] == "" {
+			return fmt.Sprintf("public%d", vID)
+		}
 		return system.Public[vID]
 	}
 	vID -= nbPublic
 	if vID < nbSecret {
+		if system.Secret[vID] == "" {
+			return fmt.Sprintf("secret%d", vID)
+		}
 		return system.Secret[vID]
 	}
 	vID -= nbSecret
@@ -341,7 +379,7 @@ func (system *System) VariableToString(vID int) string {
 }
 
 func (cs *System) AddR1C(c R1C, bID BlueprintID) int {
-	profile.RecordConstraint()
+	profile.RecordConstraintID(cs.NbConstraints)
 
 	blueprint := cs.Blueprints[bID]
 
@@ -359,7 +397,7 @@ func (cs *System) AddR1C(c R1C, bID BlueprintID) int {
 }
 
 func (cs *System) AddSparseR1C(c SparseR1C, bID BlueprintID) int {
-	profile.RecordConstraint()
+	profile.RecordConstraintID(cs.NbConstraints)
 
 	blueprint := cs.Blueprints[bID]
 
@@ -405,7 +443,7 @@ func (cs *System) AddInstruction(bID BlueprintID, calldata []uint32) []uint32 {
 	cs.Instructions = append(cs.Instructions, pi)
 
 	// update the instruction dependency tree
-	cs.updateLevel(len(cs.Instructions)-1, blueprint.WireWalker(inst))
+	cs.updateLevel(len(cs.Instructions)-1, 0, blueprint.WireWalker(inst))
 
 	return wires
 }
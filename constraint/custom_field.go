@@ -0,0 +1,58 @@
+package constraint
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/internal/tinyfield"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// CustomField holds the constructors of the constraint systems and witness
+// vectors of a prime field which is not the scalar field of a curve supported
+// by gnark. The packages implementing them are generated by the
+// github.com/consensys/gnark/cmd/gnark-field tool.
+type CustomField struct {
+	// NewR1CS returns an empty R1CS with the given capacity.
+	NewR1CS func(capacity int) R1CS
+	// NewSparseR1CS returns an empty SparseR1CS with the given capacity.
+	NewSparseR1CS func(capacity int) SparseR1CS
+	// NewVector returns a vector of field elements of the given size, used to
+	// store the witness.
+	NewVector func(size int) any
+}
+
+var (
+	customFields   = make(map[string]CustomField) // indexed by the modulus in hex
+	customFieldsMu sync.RWMutex
+)
+
+// RegisterCustomField registers the constraint systems of a user-defined
+// prime field, so that circuits can be compiled and solved over it. It is
+// called from the init function of the generated constraint system package and
+// panics if the field is already supported.
+func RegisterCustomField(modulus *big.Int, field CustomField) {
+	if utils.FieldToCurve(modulus) != ecc.UNKNOWN || modulus.Cmp(tinyfield.Modulus()) == 0 {
+		panic(fmt.Sprintf("field %s is natively supported", modulus.Text(16)))
+	}
+	customFieldsMu.Lock()
+	defer customFieldsMu.Unlock()
+	key := modulus.Text(16)
+	if _, ok := customFields[key]; ok {
+		panic(fmt.Sprintf("field %s already registered", key))
+	}
+	witness.RegisterField(modulus, field.NewVector)
+	customFields[key] = field
+}
+
+// GetCustomField returns the constructors registered with
+// [RegisterCustomField] for the given modulus.
+func GetCustomField(modulus *big.Int) (CustomField, bool) {
+	customFieldsMu.RLock()
+	defer customFieldsMu.RUnlock()
+	field, ok := customFields[modulus.Text(16)]
+	return field, ok
+}
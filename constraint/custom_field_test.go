@@ -0,0 +1,17 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCustomFieldNative(t *testing.T) {
+	require.Panics(t, func() {
+		constraint.RegisterCustomField(ecc.BN254.ScalarField(), constraint.CustomField{})
+	})
+	_, ok := constraint.GetCustomField(ecc.BN254.ScalarField())
+	require.False(t, ok)
+}
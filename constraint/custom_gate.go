@@ -0,0 +1,119 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxCustomGateDegree is the maximal total degree of the polynomial of a
+// custom gate. It is bounded by the degree of the PLONK quotient polynomial.
+const MaxCustomGateDegree = 3
+
+// CustomGateTerm is the monomial Coeff⋅xaᴬ⋅xbᴮ of a custom gate, where Coeff
+// is an index in the coefficient table of the constraint system.
+type CustomGateTerm struct {
+	Coeff uint32
+	A, B  uint8
+}
+
+// CustomGate is a user-defined PLONK gate constraining
+//
+//	xc == P(xa, xb) = ∑ᵢ Coeffᵢ⋅xaᴬ⁽ⁱ⁾⋅xbᴮ⁽ⁱ⁾
+//
+// in a single constraint. Each custom gate of a system has its own selector
+// polynomial in the PLONK proving key.
+type CustomGate struct {
+	Name  string
+	Terms []CustomGateTerm
+}
+
+// Degree returns the total degree of the gate polynomial.
+func (g *CustomGate) Degree() int {
+	d := 0
+	for _, t := range g.Terms {
+		if int(t.A)+int(t.B) > d {
+			d = int(t.A) + int(t.B)
+		}
+	}
+	return d
+}
+
+// Check returns an error if the gate is empty or its degree exceeds
+// [MaxCustomGateDegree].
+func (g *CustomGate) Check() error {
+	if len(g.Terms) == 0 {
+		return errors.New("custom gate without terms")
+	}
+	if d := g.Degree(); d > MaxCustomGateDegree {
+		return fmt.Errorf("custom gate %q of degree %d, at most %d supported", g.Name, d, MaxCustomGateDegree)
+	}
+	return nil
+}
+
+// Evaluate returns P(a, b) using the arithmetic of the field f and the
+// coefficient getter coeff.
+func (g *CustomGate) Evaluate(f Field, coeff func(cID uint32) Element, a, b Element) Element {
+	var aPow, bPow [MaxCustomGateDegree + 1]Element
+	aPow[0], bPow[0] = f.One(), f.One()
+	for i := 1; i <= MaxCustomGateDegree; i++ {
+		aPow[i] = f.Mul(aPow[i-1], a)
+		bPow[i] = f.Mul(bPow[i-1], b)
+	}
+	var res Element
+	for _, t := range g.Terms {
+		m := f.Mul(aPow[t.A], bPow[t.B])
+		res = f.Add(res, f.Mul(m, coeff(t.Coeff)))
+	}
+	return res
+}
+
+// BlueprintCustomGate implements Blueprint, BlueprintSolvable and
+// BlueprintSparseR1C. Encodes
+//
+//	P(xa, xb) == xc
+//
+// where P is the polynomial of the custom gate of index Gate in the system.
+type BlueprintCustomGate struct {
+	Gate uint32
+	// Definition is a copy of the gate of the system, for solving.
+	Definition CustomGate
+}
+
+func (b *BlueprintCustomGate) CalldataSize() int {
+	return 3
+}
+func (b *BlueprintCustomGate) NbConstraints() int {
+	return 1
+}
+func (b *BlueprintCustomGate) NbOutputs(inst Instruction) int {
+	return 0
+}
+
+func (b *BlueprintCustomGate) WireWalker(inst Instruction) func(cb func(wire uint32)) {
+	return func(cb func(wire uint32)) {
+		cb(inst.Calldata[0]) // xa
+		cb(inst.Calldata[1]) // xb
+		cb(inst.Calldata[2]) // xc
+	}
+}
+
+func (b *BlueprintCustomGate) CompressSparseR1C(c *SparseR1C, to *[]uint32) {
+	*to = append(*to, c.XA, c.XB, c.XC)
+}
+
+func (b *BlueprintCustomGate) Solve(s Solver, inst Instruction) error {
+	// P(xa, xb) == xc
+	xa := s.GetValue(CoeffIdOne, inst.Calldata[0])
+	xb := s.GetValue(CoeffIdOne, inst.Calldata[1])
+	s.SetValue(inst.Calldata[2], b.Definition.Evaluate(s, s.GetCoeff, xa, xb))
+	return nil
+}
+
+func (b *BlueprintCustomGate) DecompressSparseR1C(c *SparseR1C, inst Instruction) {
+	c.Clear()
+	c.XA = inst.Calldata[0]
+	c.XB = inst.Calldata[1]
+	c.XC = inst.Calldata[2]
+	c.QO = CoeffIdMinusOne
+	c.CustomGate = b.Gate + 1
+}
@@ -40,3 +40,13 @@ func (system *System) NewDebugInfo(errName string, i ...interface{}) DebugInfo {
 
 	return DebugInfo(l)
 }
+
+// GetDebugInfo returns the debug information attached to the constraint cID,
+// if any.
+func (system *System) GetDebugInfo(cID int) (DebugInfo, bool) {
+	dID, ok := system.MDebug[cID]
+	if !ok {
+		return DebugInfo{}, false
+	}
+	return DebugInfo(system.DebugInfo[dID]), true
+}
@@ -0,0 +1,49 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintIDs(t *testing.T) {
+	assert := require.New(t)
+	r1cs := cs.NewR1CS(0)
+	blueprint := r1cs.AddBlueprint(&constraint.BlueprintGenericR1C{})
+	X := r1cs.AddSecretVariable("X")
+	cOne := r1cs.FromInterface(1)
+	square := constraint.R1C{
+		L: constraint.LinearExpression{r1cs.MakeTerm(cOne, X)},
+		R: constraint.LinearExpression{r1cs.MakeTerm(cOne, X)},
+		O: constraint.LinearExpression{r1cs.MakeTerm(cOne, X)},
+	}
+
+	c0 := r1cs.AddR1C(square, blueprint)
+	r1cs.AddLog(constraint.LogEntry{Format: "log"})
+	c1 := r1cs.AddR1C(square, blueprint)
+	r1cs.AttachDebugInfo(r1cs.NewDebugInfo("square", "X² == X"), []int{c1})
+	assert.Equal(0, c0)
+	assert.Equal(1, c1)
+
+	check := func(r1cs *cs.R1CS) {
+		assert.Equal(c1, r1cs.Logs[0].ConstraintID)
+		_, ok := r1cs.GetDebugInfo(c0)
+		assert.False(ok)
+		d, ok := r1cs.GetDebugInfo(c1)
+		assert.True(ok)
+		assert.Contains(d.Format, "[square] X² == X")
+	}
+	check(r1cs)
+
+	// the IDs are preserved by the serialization
+	var buf bytes.Buffer
+	_, err := r1cs.WriteTo(&buf)
+	assert.NoError(err)
+	var decoded cs.R1CS
+	_, err = decoded.ReadFrom(&buf)
+	assert.NoError(err)
+	check(&decoded)
+}
@@ -3,4 +3,16 @@
 // A constraint system is a list of mathematical constraints;
 //   - Each constraint is composed of LinearExpression of Term
 //   - A Term is an association between a coefficient and a Variable
+//
+// # Constraint IDs
+//
+// The constraints are identified by their index in the order in which they
+// were added to the system. The IDs are stable: they are preserved by the
+// serialization and by [System.ReorderLevels], which only changes the order in
+// which the instructions are solved. The same ID refers to the same constraint
+// in the debug information ([System.GetDebugInfo]), the logs
+// ([LogEntry.ConstraintID]), the profiles (label "constraint" of the samples
+// of [github.com/consensys/gnark/profile]), the reports
+// ([NewHotConstraintsReport]) and the errors of the solver
+// ([github.com/consensys/gnark.UnsatisfiedConstraintError]).
 package constraint
@@ -0,0 +1,45 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package cs
+
+import (
+	"io"
+
+	"github.com/consensys/gnark/constraint"
+
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+func init() {
+	constraint.RegisterCustomField(fr.Modulus(), constraint.CustomField{
+		NewR1CS:       func(capacity int) constraint.R1CS { return NewR1CS(capacity) },
+		NewSparseR1CS: func(capacity int) constraint.SparseR1CS { return NewSparseR1CS(capacity) },
+		NewVector:     func(size int) any { return make(fr.Vector, size) },
+	})
+}
+
+// writerCounter wraps an io.Writer to count the bytes written
+type writerCounter struct {
+	W io.Writer
+	N int64
+}
+
+func (w *writerCounter) Write(p []byte) (n int, err error) {
+	n, err = w.W.Write(p)
+	w.N += int64(n)
+	return
+}
@@ -8,8 +8,10 @@ package constraint
 //
 // We build a graph of dependency; we say that a wire is solved at a level l
 // --> l = max(level_of_dependencies(wire)) + 1
-func (system *System) updateLevel(iID int, walkWires func(cb func(wire uint32))) {
-	level := -1
+//
+// The instruction is placed at a level no lower than minLevel.
+func (system *System) updateLevel(iID, minLevel int, walkWires func(cb func(wire uint32))) {
+	level := minLevel - 1
 
 	// process all wires of the instruction
 	walkWires(func(wire uint32) {
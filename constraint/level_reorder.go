@@ -0,0 +1,47 @@
+package constraint
+
+import (
+	"math"
+	"sort"
+)
+
+// ReorderLevels rebuilds the levels of the solver to improve its memory
+// locality on large circuits.
+//
+// The levels built while compiling place each instruction as early as its
+// dependencies allow. In a wide circuit, each level then holds instructions
+// spread over the whole system, and the solver sweeps the entire witness once
+// per level. ReorderLevels splits the instructions in blocks of blockSize
+// consecutive instructions, and levels each block on top of the levels of the
+// previous ones, so that the solver works on one block at a time. Inside a
+// level, the instructions are grouped by blueprint.
+//
+// The instructions are added in an order which respects their dependencies,
+// so the new levels do too. The instructions, and thus the constraint IDs, are
+// unchanged: only the order in which they are solved is. If blockSize is not
+// positive, the instructions are levelled as a single block, as when
+// compiling.
+func (system *System) ReorderLevels(blockSize int) {
+	if blockSize <= 0 {
+		blockSize = math.MaxInt
+	}
+	for i := range system.lbWireLevel {
+		system.lbWireLevel[i] = -1
+	}
+	system.Levels = system.Levels[:0]
+
+	minLevel := 0
+	for iID, pi := range system.Instructions {
+		if iID%blockSize == 0 {
+			minLevel = len(system.Levels)
+		}
+		inst := pi.Unpack(system)
+		system.updateLevel(iID, minLevel, system.Blueprints[pi.BlueprintID].WireWalker(inst))
+	}
+
+	for _, level := range system.Levels {
+		sort.SliceStable(level, func(i, j int) bool {
+			return system.Instructions[level[i]].BlueprintID < system.Instructions[level[j]].BlueprintID
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package constraint_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/stretchr/testify/require"
+)
+
+// chainsCircuit computes independent chains of multiplications, which gives
+// wide and deep levels.
+type chainsCircuit struct {
+	X     []frontend.Variable
+	Depth int
+}
+
+func (c *chainsCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		x := c.X[i]
+		for j := 0; j < c.Depth; j++ {
+			x = api.Add(api.Mul(x, x), j)
+		}
+		api.AssertIsDifferent(x, 0)
+	}
+	return nil
+}
+
+// bitsCircuit decomposes its inputs in bits, which gives wide and shallow
+// levels mixing hints and constraints.
+type bitsCircuit struct {
+	X []frontend.Variable
+}
+
+func (c *bitsCircuit) Define(api frontend.API) error {
+	acc := frontend.Variable(0)
+	for i := range c.X {
+		b := api.ToBinary(c.X[i], 64)
+		acc = api.Add(acc, api.Mul(api.FromBinary(b[:32]...), api.FromBinary(b[32:]...)))
+	}
+	api.AssertIsDifferent(acc, 0)
+	return nil
+}
+
+// mimcCircuit hashes its inputs independently.
+type mimcCircuit struct {
+	X []frontend.Variable
+}
+
+func (c *mimcCircuit) Define(api frontend.API) error {
+	for i := range c.X {
+		h, err := mimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		h.Write(c.X[i], i)
+		api.AssertIsDifferent(h.Sum(), 0)
+	}
+	return nil
+}
+
+// reorderCircuits returns the circuits and assignments of the benchmark
+// corpus, with n inputs.
+func reorderCircuits(n, depth int) map[string][2]frontend.Circuit {
+	chains := [2]*chainsCircuit{{X: make([]frontend.Variable, n), Depth: depth}, {X: make([]frontend.Variable, n), Depth: depth}}
+	bits := [2]*bitsCircuit{{X: make([]frontend.Variable, n)}, {X: make([]frontend.Variable, n)}}
+	hashes := [2]*mimcCircuit{{X: make([]frontend.Variable, n)}, {X: make([]frontend.Variable, n)}}
+	for i := 0; i < n; i++ {
+		chains[1].X[i] = i + 2
+		bits[1].X[i] = (i+2)<<33 | 1
+		hashes[1].X[i] = i
+	}
+	return map[string][2]frontend.Circuit{
+		"chains": {chains[0], chains[1]},
+		"bits":   {bits[0], bits[1]},
+		"mimc":   {hashes[0], hashes[1]},
+	}
+}
+
+func TestReorderLevels(t *testing.T) {
+	for name, c := range reorderCircuits(64, 16) {
+		for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, c[0])
+			require.NoError(t, err, name)
+			reordered, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, c[0], frontend.WithLevelReordering(100))
+			require.NoError(t, err, name)
+
+			// the instructions are unchanged, and the levels hold each of them once
+			require.Equal(t, ccs.GetNbInstructions(), reordered.GetNbInstructions(), name)
+			var ids []int
+			for _, level := range systemOf(reordered).Levels {
+				ids = append(ids, level...)
+			}
+			sort.Ints(ids)
+			for i := range ids {
+				require.Equal(t, i, ids[i], name)
+			}
+			require.Greater(t, len(systemOf(reordered).Levels), len(systemOf(ccs).Levels), name)
+
+			// the solution is unchanged
+			w, err := frontend.NewWitness(c[1], ecc.BN254.ScalarField())
+			require.NoError(t, err, name)
+			expected, err := ccs.Solve(w)
+			require.NoError(t, err, name)
+			solution, err := reordered.Solve(w)
+			require.NoError(t, err, name)
+			require.Equal(t, expected, solution, name)
+		}
+	}
+
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &bitsCircuit{X: make([]frontend.Variable, 1)}, frontend.WithLevelReordering(0))
+	require.Error(t, err)
+}
+
+func systemOf(ccs constraint.ConstraintSystem) *constraint.System {
+	return &ccs.(*cs.R1CS).System
+}
+
+func BenchmarkReorderLevels(b *testing.B) {
+	for name, c := range reorderCircuits(8192, 128) {
+		w, err := frontend.NewWitness(c[1], ecc.BN254.ScalarField())
+		require.NoError(b, err)
+		for _, builder := range []struct {
+			name       string
+			newBuilder frontend.NewBuilder
+		}{
+			{"r1cs", r1cs.NewBuilder},
+			{"scs", scs.NewBuilder},
+		} {
+			for _, opt := range []struct {
+				name string
+				opts []frontend.CompileOption
+			}{
+				{"default", nil},
+				{"reordered", []frontend.CompileOption{frontend.WithLevelReordering(1 << 16)}},
+			} {
+				ccs, err := frontend.Compile(ecc.BN254.ScalarField(), builder.newBuilder, c[0], opt.opts...)
+				require.NoError(b, err)
+				b.Run(name+"/"+builder.name+"/"+opt.name, func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						if _, err := ccs.Solve(w); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package constraint
 
 import (
+	"math/big"
 	"strings"
 )
 
@@ -25,7 +26,47 @@ type LogEntry struct {
 	Caller    string
 	Format    string
 	ToResolve []LinearExpression // TODO @gbotrel we could store here a struct with a flag that says if we expand or evaluate the expression
+	Formats   []LogFormat        // Formats[i] is the format of ToResolve[i]; missing entries use the default format
 	Stack     []int
+
+	// ConstraintID is the ID of the next constraint added to the system when
+	// the log was created, which locates the log among the constraints.
+	ConstraintID int
+}
+
+// LogFormat describes how a resolved value of a LogEntry is printed.
+//
+// The zero value prints the value as a field element (small "negative" values
+// are printed as such).
+type LogFormat struct {
+	// Verb is the representation of the value: 'd' for decimal, 'x' for
+	// hexadecimal, 'b' for binary. If zero, the field element representation
+	// is used.
+	Verb byte
+	// NbBits, if non-zero, truncates the value to its NbBits least significant bits.
+	NbBits int
+}
+
+// IsDefault returns true if f is the default (field element) format.
+func (f LogFormat) IsDefault() bool {
+	return f == LogFormat{}
+}
+
+// Format returns the string representation of v (in canonical form, in [0, q)) according to f.
+func (f LogFormat) Format(v *big.Int) string {
+	if f.NbBits > 0 {
+		mask := new(big.Int).Lsh(big.NewInt(1), uint(f.NbBits))
+		mask.Sub(mask, big.NewInt(1))
+		v = new(big.Int).And(v, mask)
+	}
+	switch f.Verb {
+	case 'x':
+		return "0x" + v.Text(16)
+	case 'b':
+		return "0b" + v.Text(2)
+	default:
+		return v.Text(10)
+	}
 }
 
 func (l *LogEntry) WriteVariable(le LinearExpression, sbb *strings.Builder) {
@@ -35,3 +76,23 @@ func (l *LogEntry) WriteVariable(le LinearExpression, sbb *strings.Builder) {
 	sbb.WriteString("%s")
 	l.ToResolve = append(l.ToResolve, le)
 }
+
+// AppendToResolve adds le to the expressions to resolve, to be printed with format f.
+// Formats is only allocated when a non-default format is used.
+func (l *LogEntry) AppendToResolve(le LinearExpression, f LogFormat) {
+	if !f.IsDefault() || len(l.Formats) != 0 {
+		for len(l.Formats) < len(l.ToResolve) {
+			l.Formats = append(l.Formats, LogFormat{})
+		}
+		l.Formats = append(l.Formats, f)
+	}
+	l.ToResolve = append(l.ToResolve, le)
+}
+
+// FormatOf returns the format of the i-th expression to resolve.
+func (l *LogEntry) FormatOf(i int) LogFormat {
+	if i < len(l.Formats) {
+		return l.Formats[i]
+	}
+	return LogFormat{}
+}
@@ -0,0 +1,148 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MaxLookupTableColumns is the maximal number of columns of a lookup table.
+// Each column of a table is matched against one of the wires xa, xb, xc of the
+// PLONK constraints.
+const MaxLookupTableColumns = 3
+
+// LookupTable is a table of a PLONK lookup argument. The table is committed in
+// the proving key, so that asserting that a tuple of wires is a row of the
+// table costs a single constraint, regardless of the size of the table.
+type LookupTable struct {
+	Name string
+	// Columns of the table, as indices in the coefficient table of the system.
+	// All the columns have the same length.
+	Columns [][]uint32
+}
+
+// NbRows returns the number of rows of the table.
+func (t *LookupTable) NbRows() int {
+	if len(t.Columns) == 0 {
+		return 0
+	}
+	return len(t.Columns[0])
+}
+
+// Check returns an error if the table is empty, has too many columns or
+// columns of different lengths.
+func (t *LookupTable) Check() error {
+	if len(t.Columns) == 0 || len(t.Columns[0]) == 0 {
+		return errors.New("empty lookup table")
+	}
+	if len(t.Columns) > MaxLookupTableColumns {
+		return fmt.Errorf("lookup table %q has %d columns, at most %d supported", t.Name, len(t.Columns), MaxLookupTableColumns)
+	}
+	for i := range t.Columns {
+		if len(t.Columns[i]) != len(t.Columns[0]) {
+			return fmt.Errorf("lookup table %q has columns of different lengths", t.Name)
+		}
+	}
+	return nil
+}
+
+// BlueprintLookup implements Blueprint, BlueprintSolvable and
+// BlueprintSparseR1C. Encodes
+//
+//	(xa, xb, xc) ∈ T
+//
+// where T is the lookup table of index Table in the system, completed with
+// zero columns. If one of the wires is not solved, the solver sets it to the
+// value of the first row of the table matching the other wires.
+type BlueprintLookup struct {
+	Table uint32
+	// Definition is a copy of the table of the system, for solving.
+	Definition LookupTable
+
+	once  sync.Once
+	rows  map[[MaxLookupTableColumns]Element]struct{}
+	index [MaxLookupTableColumns]map[[MaxLookupTableColumns]Element]Element
+}
+
+func (b *BlueprintLookup) CalldataSize() int {
+	return 3
+}
+func (b *BlueprintLookup) NbConstraints() int {
+	return 1
+}
+func (b *BlueprintLookup) NbOutputs(inst Instruction) int {
+	return 0
+}
+
+func (b *BlueprintLookup) WireWalker(inst Instruction) func(cb func(wire uint32)) {
+	return func(cb func(wire uint32)) {
+		cb(inst.Calldata[0]) // xa
+		cb(inst.Calldata[1]) // xb
+		cb(inst.Calldata[2]) // xc
+	}
+}
+
+func (b *BlueprintLookup) CompressSparseR1C(c *SparseR1C, to *[]uint32) {
+	*to = append(*to, c.XA, c.XB, c.XC)
+}
+
+func (b *BlueprintLookup) Solve(s Solver, inst Instruction) error {
+	b.once.Do(func() { b.buildIndex(s) })
+
+	var row [MaxLookupTableColumns]Element
+	unsolved := -1
+	for i := 0; i < MaxLookupTableColumns; i++ {
+		if !s.IsSolved(inst.Calldata[i]) {
+			if unsolved != -1 {
+				return errors.New("lookup with more than one unsolved wire")
+			}
+			unsolved = i
+			continue
+		}
+		row[i] = s.GetValue(CoeffIdOne, inst.Calldata[i])
+	}
+	if unsolved != -1 {
+		v, ok := b.index[unsolved][row]
+		if !ok {
+			return fmt.Errorf("no row of lookup table %q matches the inputs", b.Definition.Name)
+		}
+		s.SetValue(inst.Calldata[unsolved], v)
+		return nil
+	}
+	if _, ok := b.rows[row]; !ok {
+		return fmt.Errorf("lookup table %q doesn't contain the row", b.Definition.Name)
+	}
+	return nil
+}
+
+// buildIndex indexes the rows of the table, and for each column the value of
+// the first row matching the other columns.
+func (b *BlueprintLookup) buildIndex(s Solver) {
+	t := &b.Definition
+	b.rows = make(map[[MaxLookupTableColumns]Element]struct{}, t.NbRows())
+	for k := range b.index {
+		b.index[k] = make(map[[MaxLookupTableColumns]Element]Element, t.NbRows())
+	}
+	for i := 0; i < t.NbRows(); i++ {
+		var row [MaxLookupTableColumns]Element
+		for j := range t.Columns {
+			row[j] = s.GetCoeff(t.Columns[j][i])
+		}
+		b.rows[row] = struct{}{}
+		for k := range b.index {
+			key := row
+			key[k] = Element{}
+			if _, ok := b.index[k][key]; !ok {
+				b.index[k][key] = row[k]
+			}
+		}
+	}
+}
+
+func (b *BlueprintLookup) DecompressSparseR1C(c *SparseR1C, inst Instruction) {
+	c.Clear()
+	c.XA = inst.Calldata[0]
+	c.XB = inst.Calldata[1]
+	c.XC = inst.Calldata[2]
+	c.Lookup = b.Table + 1
+}
@@ -0,0 +1,195 @@
+package constraint
+
+import (
+	"fmt"
+	"math"
+)
+
+// systemer is implemented by the constraint systems embedding [System].
+type systemer interface {
+	system() *System
+}
+
+func (system *System) system() *System {
+	return system
+}
+
+// MergeR1CS adds the constraints of a and then of b to dst, which must be an
+// empty R1CS over the same field. The inputs of b named in the keys of
+// bindings are identified with the inputs of a named by the values, the other
+// inputs of b are added as new inputs. The inputs of the merged system are
+// ordered as:
+//
+//	public:   the public inputs of a, then the unbound public inputs of b
+//	secret:   the secret inputs of a, then the unbound secret inputs of b
+//
+// Only the systems made of R1C and hint instructions can be merged, in
+// particular the systems with commitments or GKR are not supported. The debug
+// information and the logs of a and b are not kept.
+func MergeR1CS(dst, a, b R1CS, bindings map[string]string) error {
+	sDst, sA, sB, err := systemsOf(dst, a, b)
+	if err != nil {
+		return err
+	}
+	if sDst.GetNbPublicVariables() != 0 || sDst.GetNbSecretVariables() != 0 || sDst.GetNbInstructions() != 0 {
+		return fmt.Errorf("destination system is not empty")
+	}
+	if sA.Type != SystemR1CS || sB.Type != SystemR1CS || sDst.Type != SystemR1CS {
+		return fmt.Errorf("only R1CS can be merged")
+	}
+	if sA.ScalarField != sB.ScalarField || sA.ScalarField != sDst.ScalarField {
+		return fmt.Errorf("systems are defined over different fields")
+	}
+	for _, s := range []*System{sA, sB} {
+		if len(s.CommitmentInfo.CommitmentIndexes()) != 0 || len(s.GkrInfo.Circuit) != 0 {
+			return fmt.Errorf("systems with commitments or GKR can't be merged")
+		}
+	}
+
+	// index the inputs of a by name
+	inputsA := make(map[string]uint32, len(sA.Public)+len(sA.Secret))
+	for i, name := range append(append([]string{}, sA.Public...), sA.Secret...) {
+		if _, ok := inputsA[name]; ok && name != "" {
+			inputsA[name] = math.MaxUint32 // ambiguous
+			continue
+		}
+		inputsA[name] = uint32(i)
+	}
+
+	// map the inputs of b, the wire 0 of both systems being the constant 1
+	nbInputsB := len(sB.Public) + len(sB.Secret)
+	boundB := make([]uint32, nbInputsB)
+	for i := range boundB {
+		boundB[i] = math.MaxUint32
+	}
+	boundB[0] = 0
+	used := make(map[string]bool, len(bindings))
+	for i := 1; i < nbInputsB; i++ {
+		name := sB.VariableToString(i)
+		nameA, ok := bindings[name]
+		if !ok {
+			continue
+		}
+		wire, ok := inputsA[nameA]
+		if !ok {
+			return fmt.Errorf("input %q of the first system not found", nameA)
+		}
+		if wire == math.MaxUint32 {
+			return fmt.Errorf("input %q of the first system is ambiguous", nameA)
+		}
+		boundB[i] = wire
+		used[name] = true
+	}
+	for name := range bindings {
+		if !used[name] {
+			return fmt.Errorf("input %q of the second system not found", name)
+		}
+	}
+
+	// allocate the wires of the merged system
+	wiresA := make([]uint32, len(sA.Public)+len(sA.Secret)+sA.NbInternalVariables)
+	wiresB := make([]uint32, nbInputsB+sB.NbInternalVariables)
+	for i := range sA.Public {
+		wiresA[i] = uint32(sDst.AddPublicVariable(sA.Public[i]))
+	}
+	for i := 1; i < len(sB.Public); i++ {
+		if boundB[i] == math.MaxUint32 {
+			wiresB[i] = uint32(sDst.AddPublicVariable(sB.Public[i]))
+		}
+	}
+	for i := range sA.Secret {
+		wiresA[len(sA.Public)+i] = uint32(sDst.AddSecretVariable(sA.Secret[i]))
+	}
+	for i := range sB.Secret {
+		if boundB[len(sB.Public)+i] == math.MaxUint32 {
+			wiresB[len(sB.Public)+i] = uint32(sDst.AddSecretVariable(sB.Secret[i]))
+		}
+	}
+	for i := len(sA.Public) + len(sA.Secret); i < len(wiresA); i++ {
+		wiresA[i] = uint32(sDst.AddInternalVariable())
+	}
+	for i := nbInputsB; i < len(wiresB); i++ {
+		wiresB[i] = uint32(sDst.AddInternalVariable())
+	}
+	for i := range boundB {
+		if boundB[i] != math.MaxUint32 {
+			wiresB[i] = wiresA[boundB[i]]
+		}
+	}
+
+	if err := mergeInto(dst, sDst, a, sA, wiresA); err != nil {
+		return err
+	}
+	return mergeInto(dst, sDst, b, sB, wiresB)
+}
+
+// mergeInto adds the instructions of src to dst, with the wires of src mapped
+// by wires.
+func mergeInto(dst R1CS, sDst *System, src R1CS, sSrc *System, wires []uint32) error {
+	for id, name := range sSrc.MHintsDependencies {
+		if registered, ok := sDst.MHintsDependencies[id]; ok && registered != name {
+			return fmt.Errorf("hint %s registered with the same id as %s", name, registered)
+		}
+		sDst.MHintsDependencies[id] = name
+	}
+
+	remap := func(l LinearExpression) LinearExpression {
+		res := make(LinearExpression, len(l))
+		for i, t := range l {
+			res[i].CID = dst.AddCoeff(src.GetCoefficient(int(t.CID)))
+			res[i].VID = t.VID
+			if !t.IsConstant() {
+				res[i].VID = wires[t.VID]
+			}
+		}
+		return res
+	}
+
+	blueprints := make(map[BlueprintID]BlueprintID)
+	var (
+		r1c R1C
+		hm  HintMapping
+	)
+	for i, pi := range sSrc.Instructions {
+		blueprint := sSrc.Blueprints[pi.BlueprintID]
+		bID, ok := blueprints[pi.BlueprintID]
+		if !ok {
+			bID = sDst.AddBlueprint(blueprint)
+			blueprints[pi.BlueprintID] = bID
+		}
+		inst := pi.Unpack(sSrc)
+		switch bp := blueprint.(type) {
+		case BlueprintR1C:
+			bp.DecompressR1C(&r1c, inst)
+			sDst.AddR1C(R1C{L: remap(r1c.L), R: remap(r1c.R), O: remap(r1c.O)}, bID)
+		case BlueprintHint:
+			bp.DecompressHint(&hm, inst)
+			merged := HintMapping{HintID: hm.HintID, Inputs: make([]LinearExpression, len(hm.Inputs))}
+			for j := range hm.Inputs {
+				merged.Inputs[j] = remap(hm.Inputs[j])
+			}
+			// the outputs of a hint are consecutive internal wires, which
+			// remain consecutive in the merged system
+			merged.OutputRange.Start = wires[hm.OutputRange.Start]
+			merged.OutputRange.End = merged.OutputRange.Start + hm.OutputRange.End - hm.OutputRange.Start
+			var calldata []uint32
+			bp.CompressHint(merged, &calldata)
+			sDst.AddInstruction(bID, calldata)
+		default:
+			return fmt.Errorf("instruction %d: unsupported blueprint %T", i, blueprint)
+		}
+	}
+	return nil
+}
+
+func systemsOf(css ...ConstraintSystem) (s1, s2, s3 *System, err error) {
+	res := make([]*System, len(css))
+	for i := range css {
+		s, ok := css[i].(systemer)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("unsupported constraint system %T", css[i])
+		}
+		res[i] = s.system()
+	}
+	return res[0], res[1], res[2], nil
+}
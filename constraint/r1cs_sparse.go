@@ -14,6 +14,8 @@
 
 package constraint
 
+import "strconv"
+
 type SparseR1CS interface {
 	ConstraintSystem
 
@@ -27,6 +29,20 @@ type SparseR1CS interface {
 
 	// GetSparseR1CIterator returns an SparseR1CIterator to iterate on the SparseR1C constraints of the system.
 	GetSparseR1CIterator() SparseR1CIterator
+
+	// AddCustomGate registers the custom gate in the constraint system and
+	// returns its index.
+	AddCustomGate(g CustomGate) uint32
+
+	// GetCustomGates returns the custom gates of the system.
+	GetCustomGates() []CustomGate
+
+	// AddLookupTable registers the lookup table in the constraint system and
+	// returns its index.
+	AddLookupTable(t LookupTable) uint32
+
+	// GetLookupTables returns the lookup tables of the system.
+	GetLookupTables() []LookupTable
 }
 
 // SparseR1CIterator facilitates iterating through SparseR1C constraints.
@@ -144,6 +160,12 @@ type SparseR1C struct {
 	XA, XB, XC         uint32
 	QL, QR, QO, QM, QC uint32
 	Commitment         CommitmentConstraint
+	// CustomGate is 1 + the index of the custom gate P such that the constraint
+	// is qO⋅xc + P(xa, xb) == 0, or 0 if the constraint has no custom gate.
+	CustomGate uint32
+	// Lookup is 1 + the index of the lookup table T such that the constraint
+	// is (xa, xb, xc) ∈ T, or 0 if the constraint is not a lookup.
+	Lookup uint32
 }
 
 func (c *SparseR1C) Clear() {
@@ -153,6 +175,17 @@ func (c *SparseR1C) Clear() {
 // String formats the constraint as qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xaxb) + qC == 0
 func (c *SparseR1C) String(r Resolver) string {
 	sbb := NewStringBuilder(r)
+	if c.Lookup != 0 {
+		sbb.WriteByte('(')
+		sbb.WriteString(sbb.VariableToString(int(c.XA)))
+		sbb.WriteString(", ")
+		sbb.WriteString(sbb.VariableToString(int(c.XB)))
+		sbb.WriteString(", ")
+		sbb.WriteString(sbb.VariableToString(int(c.XC)))
+		sbb.WriteString(") ∈ table")
+		sbb.WriteString(strconv.Itoa(int(c.Lookup - 1)))
+		return sbb.String()
+	}
 	sbb.WriteTerm(Term{CID: c.QL, VID: c.XA})
 	sbb.WriteString(" + ")
 	sbb.WriteTerm(Term{CID: c.QR, VID: c.XB})
@@ -169,6 +202,15 @@ func (c *SparseR1C) String(r Resolver) string {
 		sbb.WriteString(xb)
 		sbb.WriteByte(')')
 	}
+	if c.CustomGate != 0 {
+		sbb.WriteString(" + gate")
+		sbb.WriteString(strconv.Itoa(int(c.CustomGate - 1)))
+		sbb.WriteString("(")
+		sbb.WriteString(sbb.VariableToString(int(c.XA)))
+		sbb.WriteString(", ")
+		sbb.WriteString(sbb.VariableToString(int(c.XB)))
+		sbb.WriteByte(')')
+	}
 	sbb.WriteString(" + ")
 	sbb.WriteString(r.CoeffToString(int(c.QC)))
 	sbb.WriteString(" == 0")
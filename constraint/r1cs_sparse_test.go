@@ -2,7 +2,10 @@ package constraint_test
 
 import (
 	"fmt"
+	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	cs "github.com/consensys/gnark/constraint/bn254"
 )
@@ -57,3 +60,41 @@ func ExampleSparseR1CS_GetSparseR1Cs() {
 	// 0 + 0 + -1⋅v0 + 1⋅(X×X) + 0 == 0
 	// 5⋅X + v0 + -1⋅Y + 5 == 0
 }
+
+func TestSparseR1CSSolveZeroByZero(t *testing.T) {
+	// X ⋅ v0 == 0, the solver divides by X to solve v0
+	scs := cs.NewSparseR1CS(0)
+	blueprint := scs.AddBlueprint(&constraint.BlueprintGenericSparseR1C{})
+	X := scs.AddSecretVariable("X")
+	v0 := scs.AddInternalVariable()
+	scs.AddSparseR1C(constraint.SparseR1C{
+		XA: uint32(v0),
+		XB: uint32(X),
+		QM: constraint.CoeffIdOne,
+	}, blueprint)
+
+	for _, tc := range []struct {
+		x       int
+		wantErr bool
+	}{
+		{1, false},
+		// 0/0: the constraint holds for any v0, but the generic solver can not
+		// choose a value
+		{0, true},
+	} {
+		w, err := witness.New(ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := make(chan any, 1)
+		values <- tc.x
+		close(values)
+		if err := w.Fill(0, 1, values); err != nil {
+			t.Fatal(err)
+		}
+		_, err = scs.Solve(w)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("X=%d: unexpected error %v", tc.x, err)
+		}
+	}
+}
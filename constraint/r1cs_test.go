@@ -103,3 +103,23 @@ func (circuit *cubic) Define(api frontend.API) error {
 	api.AssertIsEqual(circuit.Y, api.Add(x3, circuit.X, 5))
 	return nil
 }
+
+func ExampleNewHotConstraintsReport() {
+	ccs, _ := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &cubic{})
+
+	report := constraint.NewHotConstraintsReport(ccs.(constraint.R1CS), 2)
+	fmt.Print(report)
+
+	// Output:
+	// 3 constraints, 11 terms
+	//
+	// heaviest constraints:
+	//   #2	5 terms (L: 1, R: 1, O: 3)
+	// 	1 ⋅ Y == 5 + X + v1
+	//   #0	3 terms (L: 1, R: 1, O: 1)
+	// 	X ⋅ X == v0
+	//
+	// highest fan-out wires:
+	//   X	3 constraints
+	//   v0	2 constraints
+}
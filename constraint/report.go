@@ -0,0 +1,180 @@
+package constraint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HotConstraintsReport lists the constraints with the largest linear expressions
+// and the wires with the highest fan-out of a R1CS. These dominate the solver and
+// prover time and are good candidates for optimization.
+type HotConstraintsReport struct {
+	// NbConstraints is the total number of constraints in the system.
+	NbConstraints int
+	// NbTerms is the total number of terms in the linear expressions of the system.
+	NbTerms int
+
+	// Constraints are the heaviest constraints, by decreasing number of terms.
+	Constraints []HotConstraint
+	// Wires are the most referenced wires, by decreasing fan-out. The constant
+	// wire (ONE) is not included.
+	Wires []HotWire
+}
+
+// HotConstraint describes a constraint of a HotConstraintsReport.
+type HotConstraint struct {
+	ID            int    // constraint id
+	NbL, NbR, NbO int    // number of terms in L, R and O
+	Constraint    string // L⋅R == O representation of the constraint
+	CallSite      string // file:line where the constraint was added, if known
+}
+
+// NbTerms returns the total number of terms in the linear expressions of the constraint.
+func (c HotConstraint) NbTerms() int {
+	return c.NbL + c.NbR + c.NbO
+}
+
+// HotWire describes a wire of a HotConstraintsReport.
+type HotWire struct {
+	ID     int    // wire id
+	Name   string // name of the wire (input name or v<id> for internal wires)
+	FanOut int    // number of constraints referencing the wire
+}
+
+// NewHotConstraintsReport returns the k constraints with the most terms and
+// the k wires referenced by the most constraints in r1cs. Ties are broken by
+// smallest id.
+//
+// Call sites are only known for the constraints with debug information
+// attached. Most assertions attach it only when compiled with the debug tag.
+func NewHotConstraintsReport(r1cs R1CS, k int) HotConstraintsReport {
+	nbWires := r1cs.GetNbInternalVariables() + r1cs.GetNbSecretVariables() + r1cs.GetNbPublicVariables()
+
+	var (
+		report   HotConstraintsReport
+		weights  []int
+		fanOut   = make([]int, nbWires)
+		lastSeen = make([]int, nbWires) // constraint id + 1 of the last constraint referencing the wire
+	)
+	countWires := func(cID int, l LinearExpression) {
+		for _, t := range l {
+			wID := t.WireID()
+			if lastSeen[wID] != cID+1 {
+				lastSeen[wID] = cID + 1
+				fanOut[wID]++
+			}
+		}
+	}
+
+	it := r1cs.GetR1CIterator()
+	for c, cID := it.Next(), 0; c != nil; c, cID = it.Next(), cID+1 {
+		nbTerms := len(c.L) + len(c.R) + len(c.O)
+		weights = append(weights, nbTerms)
+		report.NbTerms += nbTerms
+		countWires(cID, c.L)
+		countWires(cID, c.R)
+		countWires(cID, c.O)
+	}
+	report.NbConstraints = len(weights)
+
+	hot := topK(weights, k)
+	if len(hot) != 0 {
+		// decompress only the selected constraints
+		selected := make(map[int]int, len(hot))
+		for i, cID := range hot {
+			selected[cID] = i
+		}
+		report.Constraints = make([]HotConstraint, len(hot))
+		it := r1cs.GetR1CIterator()
+		for c, cID := it.Next(), 0; c != nil; c, cID = it.Next(), cID+1 {
+			i, ok := selected[cID]
+			if !ok {
+				continue
+			}
+			report.Constraints[i] = HotConstraint{
+				ID:         cID,
+				NbL:        len(c.L),
+				NbR:        len(c.R),
+				NbO:        len(c.O),
+				Constraint: c.String(r1cs),
+			}
+			if s, ok := r1cs.(interface{ callSite(cID int) string }); ok {
+				report.Constraints[i].CallSite = s.callSite(cID)
+			}
+		}
+	}
+
+	if nbWires != 0 {
+		fanOut[0] = 0 // constant wire
+	}
+	for _, wID := range topK(fanOut, k) {
+		if fanOut[wID] == 0 {
+			break
+		}
+		report.Wires = append(report.Wires, HotWire{
+			ID:     wID,
+			Name:   r1cs.VariableToString(wID),
+			FanOut: fanOut[wID],
+		})
+	}
+
+	return report
+}
+
+// topK returns the indexes of the k largest weights, by decreasing weight and
+// then increasing index.
+func topK(weights []int, k int) []int {
+	idx := make([]int, len(weights))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return weights[idx[i]] > weights[idx[j]]
+	})
+	if k < len(idx) {
+		idx = idx[:k]
+	}
+	return idx
+}
+
+// callSite returns the location of the user code which added the constraint
+// cID, or the empty string if no debug information is attached to it.
+func (system *System) callSite(cID int) string {
+	dID, ok := system.MDebug[cID]
+	if !ok {
+		return ""
+	}
+	stack := system.DebugInfo[dID].Stack
+	if len(stack) == 0 {
+		return ""
+	}
+	location := system.SymbolTable.Locations[stack[0]]
+	function := system.SymbolTable.Functions[location.FunctionID]
+	return fmt.Sprintf("%s:%d (%s)", filepath.Base(function.Filename), location.Line, function.Name)
+}
+
+// String returns a human readable representation of the report.
+func (report HotConstraintsReport) String() string {
+	var sbb strings.Builder
+	fmt.Fprintf(&sbb, "%d constraints, %d terms\n", report.NbConstraints, report.NbTerms)
+
+	sbb.WriteString("\nheaviest constraints:\n")
+	for _, c := range report.Constraints {
+		fmt.Fprintf(&sbb, "  #%d\t%d terms (L: %d, R: %d, O: %d)", c.ID, c.NbTerms(), c.NbL, c.NbR, c.NbO)
+		if c.CallSite != "" {
+			sbb.WriteString("\t")
+			sbb.WriteString(c.CallSite)
+		}
+		sbb.WriteString("\n\t")
+		sbb.WriteString(c.Constraint)
+		sbb.WriteByte('\n')
+	}
+
+	sbb.WriteString("\nhighest fan-out wires:\n")
+	for _, w := range report.Wires {
+		fmt.Fprintf(&sbb, "  %s\t%d constraints\n", w.Name, w.FanOut)
+	}
+	return sbb.String()
+}
@@ -81,6 +81,26 @@ type HintID uint32
 //
 // In the init() method of the gadget, call the method RegisterHint(hintFn) function on
 // the hint function hintFn to register a hint function in the package registry.
+//
+// # Hint functions with multiple outputs
+//
+// A single hint function may return several values at once, for example the
+// quotient and remainder of a division, or the full byte decomposition of a
+// value:
+//
+//	func divModHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+//		outputs[0].DivMod(inputs[0], inputs[1], outputs[1])
+//		return nil
+//	}
+//
+//	res, err := api.Compiler().NewHint(divModHint, 2, a, b)
+//	q, r := res[0], res[1]
+//
+// The solver invokes the hint function exactly once per call to NewHint and
+// assigns all the outputs, so it is preferable to group related values in one
+// hint rather than to define one hint per output. The outputs slice is
+// pre-allocated with nbOutputs elements and the hint function must not resize
+// it.
 type Hint func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error
 
 // GetHintID is a reference function for computing the hint ID based on a function name
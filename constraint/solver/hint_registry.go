@@ -9,7 +9,7 @@ import (
 )
 
 func init() {
-	RegisterHint(InvZeroHint)
+	RegisterHint(InvZeroHint, DivZeroHint)
 }
 
 var (
@@ -86,3 +86,20 @@ func InvZeroHint(q *big.Int, inputs []*big.Int, results []*big.Int) error {
 	result.ModInverse(result, q)
 	return nil
 }
+
+// DivZeroHint computes the value a/b for the inputs a and b. If b == 0, returns 0.
+func DivZeroHint(q *big.Int, inputs []*big.Int, results []*big.Int) error {
+	result := results[0]
+
+	// b == 0, return
+	if inputs[1].Sign() == 0 {
+		result.SetUint64(0)
+		return nil
+	}
+
+	if result.ModInverse(inputs[1], q) == nil {
+		return fmt.Errorf("%s is not invertible", inputs[1])
+	}
+	result.Mul(result, inputs[0]).Mod(result, q)
+	return nil
+}
@@ -1,6 +1,9 @@
 package solver
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/consensys/gnark/logger"
 	"github.com/rs/zerolog"
 )
@@ -14,6 +17,8 @@ type Option func(*Config) error
 type Config struct {
 	HintFunctions map[HintID]Hint // defaults to all built-in hint functions
 	Logger        zerolog.Logger  // defaults to gnark.Logger
+	RecoverPanics bool            // defaults to false
+	Timeout       time.Duration   // defaults to 0 (no timeout)
 }
 
 // WithHints is a solver option that specifies additional hint functions to be used
@@ -53,6 +58,43 @@ func WithLogger(l zerolog.Logger) Option {
 	}
 }
 
+// WithPanicRecovery is a solver option which makes the solver return an error
+// wrapping [github.com/consensys/gnark.ErrPanic] instead of panicking, for
+// example when a hint function panics. The error holds the panic value and the
+// stack trace.
+//
+// The solver runs constraints on several goroutines, so the caller can't
+// recover these panics itself. The option can be passed to the provers with
+// [github.com/consensys/gnark/backend.WithSolverOptions], it then only covers
+// their solving step. To cover the whole call to the prover, use
+// [github.com/consensys/gnark/backend.WithProverPanicRecovery] instead.
+func WithPanicRecovery() Option {
+	return func(opt *Config) error {
+		opt.RecoverPanics = true
+		return nil
+	}
+}
+
+// WithTimeout is a solver option which makes the solver stop and return an
+// error wrapping [github.com/consensys/gnark.ErrSolverTimeout] when solving
+// takes longer than timeout. The error is a
+// [*github.com/consensys/gnark.SolverTimeoutError] reporting the number of
+// constraints solved, the constraint being solved and the last hint calls with
+// their duration, to help finding slow hints or gadgets generating more
+// constraints than expected.
+//
+// The timeout is checked between two constraints: a running hint function is
+// not interrupted.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opt *Config) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid timeout %s", timeout)
+		}
+		opt.Timeout = timeout
+		return nil
+	}
+}
+
 // NewConfig returns a default SolverConfig with given prover options opts applied.
 func NewConfig(opts ...Option) (Config, error) {
 	log := logger.Logger()
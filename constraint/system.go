@@ -42,6 +42,10 @@ type ConstraintSystem interface {
 
 	AddPublicVariable(name string) int
 	AddSecretVariable(name string) int
+
+	// SetWireNames restores the full names of the input wires, see
+	// [WireNaming].
+	SetWireNames(names WireNames) error
 	AddInternalVariable() int
 
 	// AddSolverHint adds a hint to the solver such that the output variables will be computed
@@ -82,6 +82,7 @@ func TestSerialization(t *testing.T) {
 						"System.q",
 						"field",
 						"CoeffTable.mCoeffs",
+						"CoeffTable.classes",
 						"System.lbWireLevel",
 						"System.genericHint",
 						"System.SymbolTable",
@@ -188,3 +189,50 @@ func BenchmarkSolve(b *testing.B) {
 	})
 
 }
+
+// bitsCircuit recomposes a value from its bits, the solver multiplies the bits
+// by powers of two.
+type bitsCircuit struct {
+	X frontend.Variable `gnark:",public"`
+}
+
+func (circuit *bitsCircuit) Define(api frontend.API) error {
+	bits := api.ToBinary(circuit.X, fr.Bits-1)
+	for i := 0; i < n/10; i++ {
+		api.AssertIsEqual(api.FromBinary(bits...), circuit.X)
+	}
+	return nil
+}
+
+func BenchmarkSolveBits(b *testing.B) {
+	var w bitsCircuit
+	w.X = 0xdeadbeef
+	witness, err := frontend.NewWitness(&w, fr.Modulus())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, builder := range []struct {
+		name       string
+		newBuilder frontend.NewBuilder
+	}{
+		{"scs", scs.NewBuilder},
+		{"r1cs", r1cs.NewBuilder},
+	} {
+		b.Run(builder.name, func(b *testing.B) {
+			var c bitsCircuit
+			ccs, err := frontend.Compile(fr.Modulus(), builder.newBuilder, &c)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Log(builder.name, " nbConstraints ", ccs.GetNbConstraints())
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := ccs.IsSolved(witness); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package constraint
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+)
+
+// WireNaming is the strategy used to store the names of the input wires in a
+// constraint system. The names are only used for debugging, but they are
+// serialized with the system and can dominate its size for circuits with many
+// inputs.
+type WireNaming uint8
+
+const (
+	// WireNamingFull stores the full names of the inputs, as given by the
+	// schema of the circuit. This is the default.
+	WireNamingFull WireNaming = iota
+	// WireNamingHashed stores a hash of 16 hexadecimal characters of the names
+	// longer than that, which bounds the size of each name while keeping them
+	// distinct.
+	WireNamingHashed
+	// WireNamingNone stores empty names, the inputs being identified by their
+	// index only.
+	WireNamingNone
+)
+
+// Name returns the name stored for an input wire of the given full name.
+func (n WireNaming) Name(name string) string {
+	switch n {
+	case WireNamingFull:
+		return name
+	case WireNamingHashed:
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(name))
+		if hashed := hex.EncodeToString(h.Sum(nil)); len(hashed) < len(name) {
+			return hashed
+		}
+		return name
+	case WireNamingNone:
+		return ""
+	default:
+		panic(fmt.Sprintf("unknown wire naming %d", n))
+	}
+}
+
+// WireNames are the full names of the input wires of a constraint system,
+// indexed as the wires. When the system is compiled with a [WireNaming] which
+// doesn't store the full names, they can be kept in a sidecar file and restored
+// with [System.SetWireNames] for debugging.
+type WireNames struct {
+	Public, Secret []string
+}
+
+// WriteTo writes the names in JSON to w.
+func (n *WireNames) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return 0, err
+	}
+	written, err := w.Write(b)
+	return int64(written), err
+}
+
+// ReadFrom reads the names in JSON from r.
+func (n *WireNames) ReadFrom(r io.Reader) (int64, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(b)), err
+	}
+	return int64(len(b)), json.Unmarshal(b, n)
+}
+
+// SetWireNames replaces the names of the input wires of the system by the
+// full names. It returns an error if the number of names doesn't match the
+// number of inputs, or if a name doesn't match the one stored with any of the
+// strategies of [WireNaming].
+func (system *System) SetWireNames(names WireNames) error {
+	if len(names.Public) != len(system.Public) || len(names.Secret) != len(system.Secret) {
+		return fmt.Errorf("expected %d public and %d secret names, got %d and %d", len(system.Public), len(system.Secret), len(names.Public), len(names.Secret))
+	}
+	check := func(stored, names []string) error {
+		for i := range stored {
+			if stored[i] != "" && stored[i] != names[i] && stored[i] != WireNamingHashed.Name(names[i]) {
+				return fmt.Errorf("name %q doesn't match the stored name %q", names[i], stored[i])
+			}
+		}
+		return nil
+	}
+	if err := check(system.Public, names.Public); err != nil {
+		return err
+	}
+	if err := check(system.Secret, names.Secret); err != nil {
+		return err
+	}
+	system.Public = append(system.Public[:0], names.Public...)
+	system.Secret = append(system.Secret[:0], names.Secret...)
+	return nil
+}
@@ -0,0 +1,81 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type wireNamesCircuit struct {
+	Accounts [500]struct {
+		Balance, Nonce frontend.Variable
+	}
+	Total frontend.Variable `gnark:",public"`
+}
+
+func (c *wireNamesCircuit) Define(api frontend.API) error {
+	var sum frontend.Variable = 0
+	for i := range c.Accounts {
+		sum = api.Add(sum, api.Mul(c.Accounts[i].Balance, c.Accounts[i].Nonce))
+	}
+	api.AssertIsEqual(sum, c.Total)
+	return nil
+}
+
+func TestWireNaming(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		size := make(map[constraint.WireNaming]int)
+		var full bytes.Buffer
+		for _, naming := range []constraint.WireNaming{constraint.WireNamingFull, constraint.WireNamingHashed, constraint.WireNamingNone} {
+			var sidecar constraint.WireNames
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &wireNamesCircuit{}, frontend.WithWireNaming(naming, &sidecar))
+			require.NoError(t, err)
+			require.Equal(t, ccs.GetNbPublicVariables(), len(sidecar.Public))
+			require.Equal(t, ccs.GetNbSecretVariables(), len(sidecar.Secret))
+			require.Equal(t, "Accounts_499_Nonce", sidecar.Secret[len(sidecar.Secret)-1])
+
+			var buf bytes.Buffer
+			_, err = ccs.WriteTo(&buf)
+			require.NoError(t, err)
+			size[naming] = buf.Len()
+			if naming == constraint.WireNamingFull {
+				full = buf
+				continue
+			}
+
+			// restoring the names from the sidecar gives back the full system
+			var sidecarBuf bytes.Buffer
+			_, err = sidecar.WriteTo(&sidecarBuf)
+			require.NoError(t, err)
+			var restored constraint.WireNames
+			_, err = restored.ReadFrom(&sidecarBuf)
+			require.NoError(t, err)
+			require.NoError(t, ccs.SetWireNames(restored))
+			buf.Reset()
+			_, err = ccs.WriteTo(&buf)
+			require.NoError(t, err)
+			require.Equal(t, full.Bytes(), buf.Bytes())
+		}
+		require.Less(t, size[constraint.WireNamingHashed], size[constraint.WireNamingFull])
+		require.Less(t, size[constraint.WireNamingNone], size[constraint.WireNamingHashed])
+	}
+}
+
+func TestSetWireNamesMismatch(t *testing.T) {
+	var sidecar constraint.WireNames
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &wireNamesCircuit{}, frontend.WithWireNaming(constraint.WireNamingHashed, &sidecar))
+	require.NoError(t, err)
+
+	names := constraint.WireNames{Public: sidecar.Public, Secret: sidecar.Secret[1:]}
+	require.Error(t, ccs.SetWireNames(names))
+
+	names.Secret = append([]string{}, sidecar.Secret...)
+	names.Secret[10] = "Accounts_0_Balance_renamed"
+	require.Error(t, ccs.SetWireNames(names))
+}
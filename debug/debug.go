@@ -56,7 +56,7 @@ func writeStack(sbb *strings.Builder, forceClean ...bool) {
 			if strings.Contains(frame.File, "test/engine.go") {
 				continue
 			}
-			if strings.Contains(frame.File, "gnark/frontend/cs") {
+			if strings.Contains(frame.Function, "gnark/frontend/cs") {
 				continue
 			}
 			file = filepath.Base(file)
@@ -65,6 +65,52 @@ func TestPrintln(t *testing.T) {
 	}
 }
 
+type printlnFormatCircuit struct {
+	A, B frontend.Variable
+	C    [2]frontend.Variable
+}
+
+func (circuit *printlnFormatCircuit) Define(api frontend.API) error {
+	c := api.Sub(circuit.A, circuit.B)
+	api.Println("c", c, frontend.Dec(c))
+	api.Println("hex", frontend.Hex(circuit.A), frontend.Hex(255))
+	api.Println("bin", frontend.Truncate(frontend.Bin(circuit.C[1]), 2))
+	api.Println("slice", frontend.Hex(circuit.C[:]))
+	api.Println("circuit", frontend.Hex(circuit))
+	api.Println("text", frontend.Hex("abc"))
+	api.AssertIsEqual(api.Mul(circuit.A, circuit.B, c), -6)
+	return nil
+}
+
+func TestPrintlnFormat(t *testing.T) {
+	assert := require.New(t)
+
+	var circuit, witness printlnFormatCircuit
+	witness.A = 2
+	witness.B = 3
+	witness.C = [2]frontend.Variable{10, 11}
+
+	var expected bytes.Buffer
+	expected.WriteString("debug_test.go:75 > c -1 [0-9]{70,}\n")
+	expected.WriteString("debug_test.go:76 > hex 0x2 0xff\n")
+	expected.WriteString("debug_test.go:77 > bin 0b11\n")
+	expected.WriteString("debug_test.go:78 > slice {_0: 0xa, _1: 0xb}\n")
+	expected.WriteString("debug_test.go:79 > circuit {A: 0x2, B: 0x3, C_0: 0xa, C_1: 0xb}\n")
+	expected.WriteString("debug_test.go:80 > text abc\n")
+
+	{
+		trace, err := getGroth16Trace(&circuit, &witness)
+		assert.NoError(err)
+		assert.Regexp(expected.String(), trace)
+	}
+
+	{
+		trace, err := getPlonkTrace(&circuit, &witness)
+		assert.NoError(err)
+		assert.Regexp(expected.String(), trace)
+	}
+}
+
 // -------------------------------------------------------------------------------------------------
 // Div by 0
 type divBy0Trace struct {
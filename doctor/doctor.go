@@ -0,0 +1,455 @@
+// Package doctor inspects the environment and the artifacts of a circuit (the
+// constraint system, the keys and the witness) and reports actionable
+// findings, to diagnose proofs which fail without an obvious reason.
+//
+// The checks include:
+//   - the gnark version which compiled the constraint system and its curve;
+//   - the sizes and the SHA-256 digests of the artifacts;
+//   - the consistency of the keys and of the witness with the constraint
+//     system, up to a full proof and verification when all the artifacts are
+//     given and the witness is complete;
+//   - the available memory against the estimated memory of the prover.
+//
+// The command cmd/gnark-doctor prints the report of [Diagnose].
+package doctor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Severity is the level of a finding.
+type Severity uint8
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// environment is the artifact of the findings about the host.
+const environment = "environment"
+
+// Finding is the result of a check.
+type Finding struct {
+	Severity Severity
+	// Artifact is the path of the inspected file, or "environment".
+	Artifact string
+	Message  string
+	// Hint is the suggested fix, if any.
+	Hint string
+}
+
+// Report lists the findings of [Diagnose] in the order of the checks.
+type Report struct {
+	Findings []Finding
+}
+
+// HasErrors returns true if a finding has the severity [Error].
+func (r *Report) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTo writes the findings in a human readable form, one per line followed
+// by its hint.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+	for _, f := range r.Findings {
+		fmt.Fprintf(&sb, "[%s] %s: %s\n", f.Severity, f.Artifact, f.Message)
+		if f.Hint != "" {
+			fmt.Fprintf(&sb, "\thint: %s\n", f.Hint)
+		}
+	}
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+func (r *Report) add(severity Severity, artifact, hint, format string, args ...any) {
+	r.Findings = append(r.Findings, Finding{
+		Severity: severity,
+		Artifact: artifact,
+		Message:  fmt.Sprintf(format, args...),
+		Hint:     hint,
+	})
+}
+
+// Artifacts are the paths of the files to inspect. The empty paths are
+// skipped. The keys and the witness are decoded with the curve and the backend
+// of the constraint system, which is then required to inspect them.
+type Artifacts struct {
+	ConstraintSystem string
+	ProvingKey       string
+	VerifyingKey     string
+	// Witness is either a full or a public witness.
+	Witness string
+}
+
+// Diagnose runs the checks on the environment and on the artifacts a. The
+// failures, including the I/O errors, are reported as findings.
+func Diagnose(a Artifacts) Report {
+	d := diagnosis{artifacts: a}
+	d.checkEnvironment()
+	if a.ConstraintSystem != "" {
+		d.checkConstraintSystem()
+	}
+	if d.ccs == nil {
+		for _, path := range []string{a.ProvingKey, a.VerifyingKey, a.Witness} {
+			if path != "" {
+				d.report.add(Warning, path, "pass the constraint system of the circuit", "skipped: the constraint system is needed to decode the artifact")
+			}
+		}
+		return d.report
+	}
+	if a.ProvingKey != "" {
+		d.checkProvingKey()
+	}
+	if a.VerifyingKey != "" {
+		d.checkVerifyingKey()
+	}
+	if a.Witness != "" {
+		d.checkWitness()
+	}
+	d.checkProof()
+	d.checkMemory()
+	return d.report
+}
+
+type diagnosis struct {
+	artifacts Artifacts
+	report    Report
+
+	curve   ecc.ID
+	backend backend.ID
+	ccs     constraint.ConstraintSystem
+	csSize  int64
+
+	pk     any // groth16.ProvingKey or plonk.ProvingKey
+	pkSize int64
+	vk     any // groth16.VerifyingKey or plonk.VerifyingKey
+
+	// witness is set if the witness is full and solves the constraint system
+	witness witness.Witness
+}
+
+func (d *diagnosis) checkEnvironment() {
+	d.report.add(Info, environment, "", "gnark %s, %s %s/%s", gnark.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if procs, cpus := runtime.GOMAXPROCS(0), runtime.NumCPU(); procs < cpus {
+		d.report.add(Warning, environment, "unset GOMAXPROCS to use all the CPUs", "GOMAXPROCS is %d but %d CPUs are available", procs, cpus)
+	} else {
+		d.report.add(Info, environment, "", "%d CPUs", cpus)
+	}
+}
+
+// header is the beginning of a serialized constraint system.
+type header struct {
+	GnarkVersion string
+	ScalarField  string
+	Type         constraint.SystemType
+}
+
+func (d *diagnosis) checkConstraintSystem() {
+	path := d.artifacts.ConstraintSystem
+	size, ok := d.checkFile(path)
+	if !ok {
+		return
+	}
+	d.csSize = size
+
+	var h header
+	if err := readFile(path, func(r io.Reader) error {
+		return cbor.NewDecoder(r).Decode(&h)
+	}); err != nil {
+		d.report.add(Error, path, "check that the file was written with the WriteTo method of the constraint system", "not a constraint system: %v", err)
+		return
+	}
+
+	// version
+	version, err := semver.Parse(h.GnarkVersion)
+	switch {
+	case err != nil:
+		d.report.add(Error, path, "", "invalid gnark version %q: %v", h.GnarkVersion, err)
+		return
+	case version.Major != gnark.Version.Major:
+		d.report.add(Error, path, fmt.Sprintf("compile the circuit again with gnark %s", gnark.Version), "compiled with gnark %s, incompatible with gnark %s", version, gnark.Version)
+		return
+	case version.Compare(gnark.Version) != 0:
+		d.report.add(Warning, path, fmt.Sprintf("compile the circuit again with gnark %s", gnark.Version), "compiled with gnark %s, there are no guarantees on compatibility with gnark %s", version, gnark.Version)
+	default:
+		d.report.add(Info, path, "", "compiled with gnark %s", version)
+	}
+
+	// curve and backend
+	field, ok := new(big.Int).SetString(h.ScalarField, 16)
+	if !ok {
+		d.report.add(Error, path, "", "invalid scalar field %q", h.ScalarField)
+		return
+	}
+	d.curve = utils.FieldToCurve(field)
+	if d.curve == ecc.UNKNOWN {
+		d.report.add(Error, path, "the keys and the witness can only be checked on the curves of gnark", "scalar field 0x%s is not the scalar field of a supported curve", h.ScalarField)
+		return
+	}
+	switch h.Type {
+	case constraint.SystemR1CS:
+		d.backend = backend.GROTH16
+		d.ccs = groth16.NewCS(d.curve)
+	case constraint.SystemSparseR1CS:
+		d.backend = backend.PLONK
+		d.ccs = plonk.NewCS(d.curve)
+	default:
+		d.report.add(Error, path, "", "unknown constraint system type %d", h.Type)
+		return
+	}
+
+	if err := readFile(path, func(r io.Reader) error {
+		_, err := d.ccs.ReadFrom(r)
+		return err
+	}); err != nil {
+		d.report.add(Error, path, "", "decoding the constraint system: %v", err)
+		d.ccs = nil
+		return
+	}
+	internal, secret, public := d.ccs.GetNbVariables()
+	d.report.add(Info, path, "", "%s constraint system for %s over %s: %d constraints, %d public, %d secret and %d internal wires",
+		systemName(h.Type), d.backend, d.curve, d.ccs.GetNbConstraints(), public, secret, internal)
+}
+
+func (d *diagnosis) checkProvingKey() {
+	path := d.artifacts.ProvingKey
+	size, ok := d.checkFile(path)
+	if !ok {
+		return
+	}
+	d.pkSize = size
+
+	var pk io.ReaderFrom
+	switch d.backend {
+	case backend.GROTH16:
+		pk = groth16.NewProvingKey(d.curve)
+	case backend.PLONK:
+		pk = plonk.NewProvingKey(d.curve)
+	}
+	if err := readFile(path, func(r io.Reader) error {
+		_, err := pk.ReadFrom(r)
+		return err
+	}); err != nil {
+		d.report.add(Error, path, fmt.Sprintf("check that the key was generated by the %s setup over %s", d.backend, d.curve), "decoding the proving key: %v", err)
+		return
+	}
+	d.pk = pk
+
+	if pk, ok := pk.(plonk.ProvingKey); ok {
+		vk := pk.VerifyingKey().(plonk.VerifyingKey)
+		d.checkNbPublic(path, vk.NbPublicWitness(), "run the setup of the circuit again")
+	}
+}
+
+func (d *diagnosis) checkVerifyingKey() {
+	path := d.artifacts.VerifyingKey
+	if _, ok := d.checkFile(path); !ok {
+		return
+	}
+
+	var vk interface {
+		io.ReaderFrom
+		NbPublicWitness() int
+	}
+	switch d.backend {
+	case backend.GROTH16:
+		vk = groth16.NewVerifyingKey(d.curve)
+	case backend.PLONK:
+		vk = plonk.NewVerifyingKey(d.curve)
+	}
+	if err := readFile(path, func(r io.Reader) error {
+		_, err := vk.ReadFrom(r)
+		return err
+	}); err != nil {
+		d.report.add(Error, path, fmt.Sprintf("check that the key was generated by the %s setup over %s", d.backend, d.curve), "decoding the verifying key: %v", err)
+		return
+	}
+	if d.checkNbPublic(path, vk.NbPublicWitness(), "run the setup of the circuit again") {
+		d.vk = vk
+	}
+}
+
+// nbPublicWitness returns the size of the public witness of the constraint
+// system, without the constant wire of the R1CS.
+func (d *diagnosis) nbPublicWitness() int {
+	if d.backend == backend.GROTH16 {
+		return d.ccs.GetNbPublicVariables() - 1
+	}
+	return d.ccs.GetNbPublicVariables()
+}
+
+func (d *diagnosis) checkNbPublic(path string, nbPublic int, hint string) bool {
+	if expected := d.nbPublicWitness(); nbPublic != expected {
+		d.report.add(Error, path, hint, "expects %d public inputs, the constraint system has %d", nbPublic, expected)
+		return false
+	}
+	return true
+}
+
+func (d *diagnosis) checkWitness() {
+	path := d.artifacts.Witness
+	if _, ok := d.checkFile(path); !ok {
+		return
+	}
+
+	var nbPublic, nbSecret uint32
+	w, err := witness.New(d.ccs.Field())
+	if err == nil {
+		err = readFile(path, func(r io.Reader) error {
+			var buf [8]byte
+			if _, err := io.ReadFull(r, buf[:]); err != nil {
+				return err
+			}
+			nbPublic, nbSecret = binary.BigEndian.Uint32(buf[:4]), binary.BigEndian.Uint32(buf[4:])
+			_, err := w.ReadFrom(io.MultiReader(bytes.NewReader(buf[:]), r))
+			return err
+		})
+	}
+	if err != nil {
+		d.report.add(Error, path, fmt.Sprintf("check that the witness was created over %s", d.curve), "decoding the witness: %v", err)
+		return
+	}
+	if n := reflect.ValueOf(w.Vector()).Len(); n != int(nbPublic+nbSecret) {
+		d.report.add(Error, path, "", "the header declares %d public and %d secret values, the witness has %d values", nbPublic, nbSecret, n)
+		return
+	}
+	if !d.checkNbPublic(path, int(nbPublic), "create the witness from the assignment of the circuit of the constraint system") {
+		return
+	}
+
+	if nbSecret == 0 && d.ccs.GetNbSecretVariables() != 0 {
+		d.report.add(Info, path, "", "public witness with %d values", nbPublic)
+		return
+	}
+	if int(nbSecret) != d.ccs.GetNbSecretVariables() {
+		d.report.add(Error, path, "create the witness from the assignment of the circuit of the constraint system", "has %d secret values, the constraint system has %d", nbSecret, d.ccs.GetNbSecretVariables())
+		return
+	}
+	if err := d.ccs.IsSolved(w); err != nil {
+		d.report.add(Error, path, "fix the assignment, or compile with the debug tag to locate the failing constraint", "the witness doesn't satisfy the constraint system: %v", err)
+		return
+	}
+	d.report.add(Info, path, "", "full witness with %d public and %d secret values, satisfies the constraint system", nbPublic, nbSecret)
+	d.witness = w
+}
+
+// checkProof proves with the witness and verifies the proof, when all the
+// artifacts are valid.
+func (d *diagnosis) checkProof() {
+	if d.pk == nil || d.vk == nil || d.witness == nil {
+		return
+	}
+	public, err := d.witness.Public()
+	if err != nil {
+		d.report.add(Error, d.artifacts.Witness, "", "extracting the public witness: %v", err)
+		return
+	}
+
+	switch d.backend {
+	case backend.GROTH16:
+		var proof groth16.Proof
+		if proof, err = groth16.Prove(d.ccs, d.pk.(groth16.ProvingKey), d.witness); err != nil {
+			d.report.add(Error, d.artifacts.ProvingKey, "run the setup of the circuit again", "proving failed, the proving key doesn't match the constraint system: %v", err)
+			return
+		}
+		err = groth16.Verify(proof, d.vk.(groth16.VerifyingKey), public)
+	case backend.PLONK:
+		var proof plonk.Proof
+		if proof, err = plonk.Prove(d.ccs, d.pk.(plonk.ProvingKey), d.witness); err != nil {
+			d.report.add(Error, d.artifacts.ProvingKey, "run the setup of the circuit again", "proving failed, the proving key doesn't match the constraint system: %v", err)
+			return
+		}
+		err = plonk.Verify(proof, d.vk.(plonk.VerifyingKey), public)
+	}
+	if err != nil {
+		d.report.add(Error, d.artifacts.VerifyingKey, "use the keys generated together by the same setup", "the proof of the witness doesn't verify, the keys don't match: %v", err)
+		return
+	}
+	d.report.add(Info, d.artifacts.VerifyingKey, "", "the proof of the witness with the proving key verifies")
+}
+
+// checkFile reports the size and the digest of the file at path.
+func (d *diagnosis) checkFile(path string) (int64, bool) {
+	var size int64
+	h := sha256.New()
+	if err := readFile(path, func(r io.Reader) error {
+		var err error
+		size, err = io.Copy(h, r)
+		return err
+	}); err != nil {
+		d.report.add(Error, path, "", "%v", err)
+		return 0, false
+	}
+	d.report.add(Info, path, "", "%s, sha256 %s", formatBytes(uint64(size)), hex.EncodeToString(h.Sum(nil)))
+	return size, true
+}
+
+func readFile(path string, read func(io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return read(f)
+}
+
+func systemName(t constraint.SystemType) string {
+	if t == constraint.SystemR1CS {
+		return "R1CS"
+	}
+	return "sparse R1CS"
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,138 @@
+package doctor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type circuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *circuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.X, api.Mul(c.Y, c.Y, c.Y))
+	return nil
+}
+
+type otherCircuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *otherCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.X, api.Add(c.Y, c.Y))
+	return nil
+}
+
+func writeFile(t *testing.T, name string, o io.WriterTo) string {
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = o.WriteTo(f)
+	require.NoError(t, err)
+	return path
+}
+
+func witnessFile(t *testing.T, x, y int, public bool) string {
+	w, err := frontend.NewWitness(&circuit{X: x, Y: y}, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	if public {
+		w, err = w.Public()
+		require.NoError(t, err)
+	}
+	return writeFile(t, "witness", w)
+}
+
+func requireFinding(t *testing.T, r Report, severity Severity, message string) {
+	t.Helper()
+	for _, f := range r.Findings {
+		if f.Severity == severity && strings.Contains(f.Message, message) {
+			return
+		}
+	}
+	var sb strings.Builder
+	_, _ = r.WriteTo(&sb)
+	t.Fatalf("no %s finding with %q in\n%s", severity, message, sb.String())
+}
+
+func TestDiagnoseGroth16(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit{})
+	require.NoError(t, err)
+	pk, vk, err := groth16.Setup(ccs)
+	require.NoError(t, err)
+	artifacts := Artifacts{
+		ConstraintSystem: writeFile(t, "ccs", ccs),
+		ProvingKey:       writeFile(t, "pk", pk),
+		VerifyingKey:     writeFile(t, "vk", vk),
+		Witness:          witnessFile(t, 27, 3, false),
+	}
+
+	r := Diagnose(artifacts)
+	require.False(t, r.HasErrors())
+	requireFinding(t, r, Info, "R1CS constraint system for groth16 over bn254")
+	requireFinding(t, r, Info, "the proof of the witness with the proving key verifies")
+
+	// public witness
+	public := artifacts
+	public.Witness = witnessFile(t, 27, 3, true)
+	r = Diagnose(public)
+	require.False(t, r.HasErrors())
+	requireFinding(t, r, Info, "public witness with 1 values")
+
+	// unsatisfied witness
+	invalid := artifacts
+	invalid.Witness = witnessFile(t, 28, 3, false)
+	r = Diagnose(invalid)
+	requireFinding(t, r, Error, "the witness doesn't satisfy the constraint system")
+
+	// keys of another circuit with the same inputs
+	other, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &otherCircuit{})
+	require.NoError(t, err)
+	_, otherVk, err := groth16.Setup(other)
+	require.NoError(t, err)
+	mismatch := artifacts
+	mismatch.VerifyingKey = writeFile(t, "vk", otherVk)
+	r = Diagnose(mismatch)
+	requireFinding(t, r, Error, "the keys don't match")
+
+	// missing constraint system
+	r = Diagnose(Artifacts{Witness: artifacts.Witness})
+	requireFinding(t, r, Warning, "the constraint system is needed")
+
+	// not a constraint system
+	r = Diagnose(Artifacts{ConstraintSystem: artifacts.Witness})
+	requireFinding(t, r, Error, "not a constraint system")
+}
+
+func TestDiagnosePlonk(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit{})
+	require.NoError(t, err)
+	srs, err := test.NewKZGSRS(ccs)
+	require.NoError(t, err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	require.NoError(t, err)
+
+	r := Diagnose(Artifacts{
+		ConstraintSystem: writeFile(t, "ccs", ccs),
+		ProvingKey:       writeFile(t, "pk", pk),
+		VerifyingKey:     writeFile(t, "vk", vk),
+		Witness:          witnessFile(t, 27, 3, false),
+	})
+	require.False(t, r.HasErrors())
+	requireFinding(t, r, Info, "sparse R1CS constraint system for plonk over bn254")
+	requireFinding(t, r, Info, "the proof of the witness with the proving key verifies")
+}
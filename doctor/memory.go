@@ -0,0 +1,92 @@
+package doctor
+
+import (
+	"bufio"
+	"fmt"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+)
+
+// checkMemory compares the estimated memory of the prover with the available
+// memory.
+func (d *diagnosis) checkMemory() {
+	estimate := d.estimateProverMemory()
+	available, ok := availableMemory()
+	if !ok {
+		d.report.add(Info, environment, "", "the prover needs about %s, the available memory is unknown", formatBytes(estimate))
+		return
+	}
+	switch {
+	case estimate > available:
+		d.report.add(Error, environment, "use a machine with more memory, or split the circuit",
+			"the prover needs about %s, only %s are available", formatBytes(estimate), formatBytes(available))
+	case estimate > available/10*8:
+		d.report.add(Warning, environment, "stop the other processes, or use a machine with more memory",
+			"the prover needs about %s, close to the %s available", formatBytes(estimate), formatBytes(available))
+	default:
+		d.report.add(Info, environment, "", "the prover needs about %s, %s are available", formatBytes(estimate), formatBytes(available))
+	}
+}
+
+// estimateProverMemory returns a rough estimate of the peak memory of the
+// prover: the decoded constraint system and proving key, the solution of the
+// constraint system and the buffers of the FFTs on the evaluation domain. It
+// is only meant to give an order of magnitude.
+func (d *diagnosis) estimateProverMemory() uint64 {
+	internal, secret, public := d.ccs.GetNbVariables()
+	nbWires := uint64(internal + secret + public)
+	elementSize := uint64((d.ccs.FieldBitLen()+63)/64) * 8
+
+	var domain, nbVectors uint64
+	switch d.backend {
+	case backend.GROTH16:
+		// a, b, c and their cosets
+		domain, nbVectors = nextPowerOfTwo(uint64(d.ccs.GetNbConstraints())), 6
+	case backend.PLONK:
+		// l, r, o, z, the selectors and the permutation, and the quotient on
+		// the domain of size 4n
+		domain, nbVectors = nextPowerOfTwo(uint64(d.ccs.GetNbConstraints()+public)), 32
+	}
+
+	// the keys and the constraint system are decompressed in memory
+	return 2*uint64(d.csSize) + 2*uint64(d.pkSize) + elementSize*(nbWires+nbVectors*domain)
+}
+
+func nextPowerOfTwo(n uint64) uint64 {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(n-1)
+}
+
+// availableMemory returns the memory available to the process, from the
+// memory limit of the cgroup and from /proc/meminfo on Linux.
+func availableMemory() (uint64, bool) {
+	available, ok := memInfoAvailable()
+	if b, err := os.ReadFile("/sys/fs/cgroup/memory.max"); err == nil {
+		if limit, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64); err == nil && (!ok || limit < available) {
+			available, ok = limit, true
+		}
+	}
+	return available, ok
+}
+
+func memInfoAvailable() (uint64, bool) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var kb uint64
+		if _, err := fmt.Sscanf(scanner.Text(), "MemAvailable: %d kB", &kb); err == nil {
+			return kb * 1024, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package gnark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Errors returned by the compile, solve, prove and verify steps. They are
+// wrapped with additional context, and should be tested with [errors.Is] (or
+// [errors.As] for [UnsatisfiedConstraintError]) rather than compared directly.
+var (
+	// ErrInvalidWitness is returned when a witness is malformed, for example
+	// when it doesn't have the number of values expected by the constraint
+	// system or the verifying key.
+	ErrInvalidWitness = errors.New("invalid witness")
+
+	// ErrSchemaMismatch is returned when a witness or an assignment doesn't
+	// follow the schema of the circuit (missing or extra values).
+	ErrSchemaMismatch = errors.New("schema mismatch")
+
+	// ErrUnsatisfied is returned by the solver when a constraint is not
+	// satisfied. The returned error is an [*UnsatisfiedConstraintError]
+	// holding the constraint metadata.
+	ErrUnsatisfied = errors.New("constraint not satisfied")
+
+	// ErrCurveMismatch is returned when objects defined over different curves
+	// (or scalar fields) are used together, for example a witness for BLS12-381
+	// with a BN254 constraint system.
+	ErrCurveMismatch = errors.New("curve mismatch")
+
+	// ErrKeyMismatch is returned when a proving or verifying key is used with
+	// a constraint system or a proof it wasn't generated for.
+	ErrKeyMismatch = errors.New("key mismatch")
+
+	// ErrVersionMismatch is returned when a serialized object was produced by
+	// an incompatible version of gnark.
+	ErrVersionMismatch = errors.New("gnark version mismatch")
+
+	// ErrPanic is returned in place of a panic recovered by the compiler, the
+	// solver or the backends. The wrapping error holds the panic value and the
+	// stack trace. See frontend.WithPanicRecovery, solver.WithPanicRecovery
+	// and the panic recovery options of the backend package.
+	ErrPanic = errors.New("recovered panic")
+
+	// ErrSolverTimeout is returned by the solver when it didn't complete within
+	// the time set with solver.WithTimeout. The returned error is a
+	// [*SolverTimeoutError] holding the progress of the solver.
+	ErrSolverTimeout = errors.New("solver timeout")
+)
+
+// UnsatisfiedConstraintError wraps an error with useful metadata on the unsatisfied constraint.
+//
+// errors.Is(err, ErrUnsatisfied) returns true for an UnsatisfiedConstraintError.
+type UnsatisfiedConstraintError struct {
+	Err       error
+	CID       int     // constraint ID
+	DebugInfo *string // optional debug info
+}
+
+func (r *UnsatisfiedConstraintError) Error() string {
+	if r.DebugInfo != nil {
+		return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, *r.DebugInfo)
+	}
+	return fmt.Sprintf("constraint #%d is not satisfied: %s", r.CID, r.Err.Error())
+}
+
+// Is returns true if target is ErrUnsatisfied.
+func (r *UnsatisfiedConstraintError) Is(target error) bool {
+	return target == ErrUnsatisfied
+}
+
+// Unwrap returns the underlying solver error.
+func (r *UnsatisfiedConstraintError) Unwrap() error {
+	return r.Err
+}
+
+// SolverTimeoutError is returned by the solver when the timeout set with
+// solver.WithTimeout is exceeded. It describes how far the solver went, to help
+// finding slow hints or gadgets generating more constraints than expected.
+//
+// errors.Is(err, ErrSolverTimeout) returns true for a SolverTimeoutError.
+type SolverTimeoutError struct {
+	Timeout       time.Duration // timeout set with solver.WithTimeout
+	NbConstraints int           // number of constraints of the system
+	NbSolved      int           // number of constraints solved before the timeout
+	CID           int           // constraint being solved when the timeout was detected
+	RecentHints   []HintCall    // last hint calls, from the oldest to the most recent
+}
+
+// HintCall describes a call to a hint function by the solver.
+type HintCall struct {
+	Name     string        // name of the hint function
+	Duration time.Duration // time spent in the hint function
+}
+
+func (r *SolverTimeoutError) Error() string {
+	var sbb strings.Builder
+	fmt.Fprintf(&sbb, "solver timed out after %s: %d/%d constraints solved, at constraint #%d", r.Timeout, r.NbSolved, r.NbConstraints, r.CID)
+	if len(r.RecentHints) > 0 {
+		sbb.WriteString("; recent hint calls:")
+		for _, h := range r.RecentHints {
+			fmt.Fprintf(&sbb, " %s (%s)", h.Name, h.Duration)
+		}
+	}
+	return sbb.String()
+}
+
+// Is returns true if target is ErrSolverTimeout.
+func (r *SolverTimeoutError) Is(target error) bool {
+	return target == ErrSolverTimeout
+}
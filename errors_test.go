@@ -0,0 +1,175 @@
+package gnark_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type errTaxonomyCircuit struct {
+	A frontend.Variable
+	B frontend.Variable `gnark:",public"`
+}
+
+func (circuit *errTaxonomyCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(circuit.A, circuit.A), circuit.B)
+	return nil
+}
+
+func TestErrorTaxonomy(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &errTaxonomyCircuit{})
+	assert.NoError(err)
+
+	// unsatisfied constraint
+	w, err := frontend.NewWitness(&errTaxonomyCircuit{A: 2, B: 5}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = ccs.Solve(w)
+	assert.True(errors.Is(err, gnark.ErrUnsatisfied))
+	var uErr *gnark.UnsatisfiedConstraintError
+	assert.True(errors.As(err, &uErr))
+	assert.Equal(1, uErr.CID) // constraint #0 solves A*A, #1 asserts it equals B
+
+	// witness defined over another curve
+	w, err = frontend.NewWitness(&errTaxonomyCircuit{A: 2, B: 4}, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+	_, err = ccs.Solve(w)
+	assert.True(errors.Is(err, gnark.ErrCurveMismatch))
+
+	// witness with missing values
+	w, err = frontend.NewWitness(&errTaxonomyCircuit{A: 2, B: 4}, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	assert.NoError(err)
+	_, err = ccs.Solve(w)
+	assert.True(errors.Is(err, gnark.ErrInvalidWitness))
+
+	// witness not following the schema
+	s, err := frontend.NewSchema(&errTaxonomyCircuit{})
+	assert.NoError(err)
+	err = w.FromJSON(s, []byte(`{"A":2}`))
+	assert.True(errors.Is(err, gnark.ErrSchemaMismatch))
+}
+
+type errPanicCircuit struct {
+	A frontend.Variable
+}
+
+func (circuit *errPanicCircuit) Define(api frontend.API) error {
+	// enough independent hints for the solver to run them on its worker pool
+	for i := 0; i < 200; i++ {
+		res, err := api.Compiler().NewHint(panickingHint, 1, circuit.A)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(res[0], circuit.A)
+	}
+	return nil
+}
+
+func panickingHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if inputs[0].Sign() == 0 {
+		panic("zero input")
+	}
+	outputs[0].Set(inputs[0])
+	return nil
+}
+
+type errPanicDefineCircuit struct {
+	A frontend.Variable
+}
+
+func (circuit *errPanicDefineCircuit) Define(api frontend.API) error {
+	panic("precondition failed")
+}
+
+func TestErrPanic(t *testing.T) {
+	assert := require.New(t)
+
+	// panics in Define are returned as errors
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &errPanicDefineCircuit{})
+	assert.True(errors.Is(err, gnark.ErrPanic))
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &errPanicDefineCircuit{}, frontend.WithPanicRecovery())
+	assert.True(errors.Is(err, gnark.ErrPanic))
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &errPanicCircuit{})
+		assert.NoError(err)
+		opts := []solver.Option{solver.WithHints(panickingHint)}
+
+		w, err := frontend.NewWitness(&errPanicCircuit{A: 1}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, err = ccs.Solve(w, append(opts, solver.WithPanicRecovery())...)
+		assert.NoError(err)
+
+		// a panicking hint is returned as an error
+		w, err = frontend.NewWitness(&errPanicCircuit{A: 0}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		_, err = ccs.Solve(w, append(opts, solver.WithPanicRecovery())...)
+		assert.True(errors.Is(err, gnark.ErrPanic))
+		assert.Contains(err.Error(), "zero input")
+	}
+}
+
+type errTimeoutCircuit struct {
+	A frontend.Variable
+}
+
+func (circuit *errTimeoutCircuit) Define(api frontend.API) error {
+	// a chain of dependent hints, solved sequentially
+	x := circuit.A
+	for i := 0; i < 20; i++ {
+		res, err := api.Compiler().NewHint(slowHint, 1, x)
+		if err != nil {
+			return err
+		}
+		api.AssertIsEqual(res[0], api.Add(x, 1))
+		x = res[0]
+	}
+	return nil
+}
+
+func slowHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	time.Sleep(10 * time.Millisecond)
+	outputs[0].Add(inputs[0], big.NewInt(1))
+	return nil
+}
+
+func TestErrSolverTimeout(t *testing.T) {
+	assert := require.New(t)
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &errTimeoutCircuit{})
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&errTimeoutCircuit{A: 1}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+
+		_, err = ccs.Solve(w, solver.WithHints(slowHint), solver.WithTimeout(time.Minute))
+		assert.NoError(err)
+
+		_, err = ccs.Solve(w, solver.WithHints(slowHint), solver.WithTimeout(50*time.Millisecond))
+		assert.True(errors.Is(err, gnark.ErrSolverTimeout))
+		var tErr *gnark.SolverTimeoutError
+		assert.True(errors.As(err, &tErr))
+		assert.Equal(ccs.GetNbConstraints(), tErr.NbConstraints)
+		assert.Less(tErr.NbSolved, tErr.NbConstraints)
+		assert.GreaterOrEqual(tErr.CID, tErr.NbSolved)
+		assert.NotEmpty(tErr.RecentHints)
+		for _, h := range tErr.RecentHints {
+			assert.Contains(h.Name, "slowHint")
+			assert.GreaterOrEqual(h.Duration, 10*time.Millisecond)
+		}
+		assert.Contains(err.Error(), "slowHint")
+	}
+
+	_, err := solver.NewConfig(solver.WithTimeout(0))
+	assert.Error(err)
+}
@@ -51,13 +51,32 @@ type API interface {
 	// Mul returns res = i1 * i2 * ... in
 	Mul(i1, i2 Variable, in ...Variable) Variable
 
-	// DivUnchecked returns i1 / i2 . if i1 == i2 == 0, returns 0
+	// DivUnchecked returns i1 / i2.
+	//
+	// The method does not constrain the denominator i2 to be non-zero. It only
+	// enforces res * i2 == i1. Consequently:
+	//   - if i2 != 0, then res is uniquely defined as i1 / i2;
+	//   - if i2 == 0 and i1 != 0, then the constraint system is not satisfiable;
+	//   - if i1 == i2 == 0, then the honest solver returns 0, but res is
+	//     unconstrained and a malicious prover may assign any value to it.
+	//
+	// Use DivUnchecked only when the circuit ensures otherwise that i2 is
+	// non-zero or when the result is not used in the 0/0 case. Otherwise use
+	// [API.Div] which costs an additional constraint.
 	DivUnchecked(i1, i2 Variable) Variable
 
-	// Div returns i1 / i2
+	// Div returns i1 / i2.
+	//
+	// The method constrains the denominator i2 to be non-zero, so the
+	// constraint system is not satisfiable when i2 == 0 (including the case
+	// i1 == i2 == 0). The result is always uniquely defined. Compared to
+	// [API.DivUnchecked], it costs one additional constraint when i2 is not a
+	// constant.
 	Div(i1, i2 Variable) Variable
 
-	// Inverse returns res = 1 / i1
+	// Inverse returns res = 1 / i1.
+	//
+	// The constraint system is not satisfiable when i1 == 0.
 	Inverse(i1 Variable) Variable
 
 	// ---------------------------------------------------------------------------------------------
@@ -130,7 +149,11 @@ type API interface {
 	AssertIsLessOrEqual(v Variable, bound Variable)
 
 	// Println behaves like fmt.Println but accepts cd.Variable as parameter
-	// whose value will be resolved at runtime when computed by the solver
+	// whose value will be resolved at runtime when computed by the solver.
+	//
+	// Slices and structs of variables are printed in one call. The
+	// representation of the values can be controlled by wrapping the argument
+	// with [Hex], [Dec], [Bin] or [Truncate].
 	Println(a ...Variable)
 
 	// Compiler returns the compiler object for advanced circuit development
@@ -101,6 +101,68 @@ type Rangechecker interface {
 	Check(v Variable, bits int)
 }
 
+// SetChecker allows to assert that a variable is one of the constants of a
+// set. Not all compilers implement this interface. Users should instead use
+// [github.com/consensys/gnark/std/set] package which falls back to a generic
+// check and batches the checks when it is cheaper.
+type SetChecker interface {
+	// AssertIsInSet fails if i1 is not one of the constants in set, for
+	// example an opcode in a whitelist or an enum value. It panics if set is
+	// empty or contains a non-constant.
+	//
+	// It is compiled to the product of the differences of i1 with the
+	// elements of set, which costs len(set)-1 constraints.
+	AssertIsInSet(i1 Variable, set ...Variable)
+}
+
+// GateMonomial is the monomial Coeff⋅aᴬ⋅bᴮ of the polynomial of a custom gate.
+type GateMonomial struct {
+	Coeff *big.Int
+	A, B  int
+}
+
+// CustomGater allows to define custom gates and to constrain variables with
+// them. A custom gate computes P(a, b) in a single constraint, where P is a
+// polynomial of total degree at most [constraint.MaxCustomGateDegree]. Only
+// PLONK compilers (and the test engine) implement this interface, gadgets
+// should fall back to the [API] methods otherwise.
+type CustomGater interface {
+	// DefineCustomGate defines the custom gate of the given name with the
+	// polynomial ∑ monomials and returns its identifier. Defining the same gate
+	// twice returns the same identifier, while redefining a name with another
+	// polynomial returns an error.
+	DefineCustomGate(name string, monomials ...GateMonomial) (gate int, err error)
+
+	// CustomGate returns P(a, b), where P is the polynomial of the custom gate.
+	CustomGate(gate int, a, b Variable) Variable
+}
+
+// TableLookuper allows to define lookup tables and to assert that variables
+// are rows of a table. The tables are committed at setup, so that a lookup
+// costs a single constraint regardless of the size of the table. Only PLONK
+// compilers (and the test engine) implement this interface, gadgets should
+// fall back to [github.com/consensys/gnark/std/lookup/logderivlookup]
+// otherwise.
+type TableLookuper interface {
+	// DefineLookupTable defines the lookup table of the given name with at
+	// most [constraint.MaxLookupTableColumns] columns of the same length and
+	// returns its identifier. Defining the same table twice returns the same
+	// identifier, while redefining a name with other columns returns an error.
+	DefineLookupTable(name string, columns ...[]*big.Int) (table int, err error)
+
+	// AssertIsInTable asserts that (vs[0], vs[1], ...) is a row of the table.
+	// Missing values are set to zero, so that the number of values can be
+	// smaller than the number of columns of the table only if the remaining
+	// columns of the row are zero.
+	AssertIsInTable(table int, vs ...Variable)
+
+	// Lookup returns the value in the last column of the first row of the
+	// table whose other columns are equal to keys. It adds a constraint
+	// asserting that the row is in the table, but doesn't prove that it is the
+	// first matching row.
+	Lookup(table int, keys ...Variable) Variable
+}
+
 // CanonicalVariable represents a variable that's encoded in a constraint system specific way.
 // For example a R1CS builder may represent this as a constraint.LinearExpression,
 // a PLONK builder --> constraint.Term
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	runtimedebug "runtime/debug"
 
+	"github.com/consensys/gnark"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend/schema"
@@ -33,7 +35,7 @@ import (
 //
 // initialCapacity is an optional parameter that reserves memory in slices
 // it should be set to the estimated number of constraints in the circuit, if known.
-func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (constraint.ConstraintSystem, error) {
+func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...CompileOption) (_ constraint.ConstraintSystem, err error) {
 	log := logger.Logger()
 	log.Info().Msg("compiling circuit")
 	// parse options
@@ -45,6 +47,15 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 		}
 	}
 
+	if opt.RecoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%w: %v\n%s", gnark.ErrPanic, r, runtimedebug.Stack())
+				log.Err(err).Msg("compiling circuit")
+			}
+		}()
+	}
+
 	// instantiate new builder
 	builder, err := newBuilder(field, opt)
 	if err != nil {
@@ -115,7 +126,7 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	// recover from panics to print user-friendlier messages
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("%v\n%s", r, debug.Stack())
+			err = fmt.Errorf("%w: %v\n%s", gnark.ErrPanic, r, debug.Stack())
 		}
 	}()
 
@@ -154,6 +165,11 @@ type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
 	CompressThreshold         int
+	RecoverPanics             bool
+	LevelBlockSize            int
+	DebugInfo                 bool
+	WireNaming                constraint.WireNaming
+	WireNames                 *constraint.WireNames
 }
 
 // WithCapacity is a compile option that specifies the estimated capacity needed
@@ -203,6 +219,75 @@ func WithCompressThreshold(threshold int) CompileOption {
 	}
 }
 
+// WithPanicRecovery is a compile option which makes Compile return an error
+// wrapping [gnark.ErrPanic] instead of panicking. Panics in the Define method
+// of the circuit, for example on failed gadget preconditions, are always
+// returned as errors. With this option, panics while parsing the circuit or
+// building the constraint system are also returned as errors.
+//
+// Only compilation is covered. Panics raised while solving, for example in
+// hint functions, are covered by
+// [github.com/consensys/gnark/constraint/solver.WithPanicRecovery], and panics
+// in the backends by the options of the backend package, such as
+// [github.com/consensys/gnark/backend.WithProverPanicRecovery].
+func WithPanicRecovery() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.RecoverPanics = true
+		return nil
+	}
+}
+
+// WithLevelReordering is a compile option which rebuilds the levels of the
+// solver by blocks of blockSize consecutive instructions, see
+// [constraint.System.ReorderLevels]. This improves the memory locality of the
+// solver on wide circuits, whose witness doesn't fit in the cache. Blocks of
+// 1 << 16 instructions are a good default.
+//
+// The reordering adds synchronization points to the solver, as it increases
+// the number of levels. The constraints and the witness are unchanged.
+func WithLevelReordering(blockSize int) CompileOption {
+	return func(opt *CompileConfig) error {
+		if blockSize <= 0 {
+			return fmt.Errorf("invalid block size %d", blockSize)
+		}
+		opt.LevelBlockSize = blockSize
+		return nil
+	}
+}
+
+// WithDebugInfo is a compile option which makes the builders record the debug
+// information of the assertions, as with the debug build tag, so that the
+// solver errors tell where the unsatisfied constraint was defined. Without the
+// debug build tag, the recorded call stacks are truncated to two frames.
+func WithDebugInfo() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.DebugInfo = true
+		return nil
+	}
+}
+
+// WithWireNaming is a compile option which selects how the names of the input
+// wires are stored in the constraint system, see [constraint.WireNaming].
+// Storing hashed names or no names reduces the size of the serialized system
+// for circuits with many inputs.
+//
+// If sidecar is not nil, it is filled with the full names of the inputs, which
+// can be saved separately and restored with
+// [constraint.ConstraintSystem.SetWireNames] when debugging.
+func WithWireNaming(naming constraint.WireNaming, sidecar *constraint.WireNames) CompileOption {
+	return func(opt *CompileConfig) error {
+		if naming > constraint.WireNamingNone {
+			return fmt.Errorf("unknown wire naming %d", naming)
+		}
+		opt.WireNaming = naming
+		opt.WireNames = sidecar
+		if sidecar != nil {
+			*sidecar = constraint.WireNames{}
+		}
+		return nil
+	}
+}
+
 var tVariable reflect.Type
 
 func init() {
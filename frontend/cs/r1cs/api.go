@@ -19,6 +19,7 @@ package r1cs
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -26,7 +27,6 @@ import (
 
 	"github.com/consensys/gnark/internal/utils"
 
-	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend/cs"
 
 	"github.com/consensys/gnark/constraint"
@@ -247,6 +247,9 @@ func (builder *builder) mulConstant(v1 expr.LinearExpression, lambda constraint.
 	return res
 }
 
+// DivUnchecked returns i1 / i2 . The denominator is not constrained to be
+// non-zero. If i1 == i2 == 0, the solver returns 0 but the result is
+// unconstrained. See [frontend.API.DivUnchecked].
 func (builder *builder) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable {
 	vars, _ := builder.toVariables(i1, i2)
 
@@ -256,11 +259,17 @@ func (builder *builder) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable
 	n1, v1Constant := builder.constantValue(v1)
 	n2, v2Constant := builder.constantValue(v2)
 
+	if v1Constant && n1.IsZero() && !v2Constant {
+		// 0 / i2 == 0 whenever i2 != 0, and the result is unconstrained when
+		// i2 == 0. We can return the constant directly.
+		return builder.cstZero()
+	}
+
 	if !v2Constant {
 		res := builder.newInternalVariable()
 		// note that here we don't ensure that divisor is != 0
 		cID := builder.cs.AddR1C(builder.newR1C(v2, res, v1), builder.genericGate)
-		if debug.Debug {
+		if builder.withDebugInfo() {
 			debug := builder.newDebugInfo("div", v1, "/", v2, " == ", res)
 			builder.cs.AttachDebugInfo(debug, []int{cID})
 		}
@@ -282,7 +291,7 @@ func (builder *builder) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable
 	return builder.mulConstant(v1, n2, false)
 }
 
-// Div returns res = i1 / i2
+// Div returns res = i1 / i2 . The denominator is constrained to be non-zero.
 func (builder *builder) Div(i1, i2 frontend.Variable) frontend.Variable {
 	vars, _ := builder.toVariables(i1, i2)
 
@@ -335,7 +344,7 @@ func (builder *builder) Inverse(i1 frontend.Variable) frontend.Variable {
 	res := builder.newInternalVariable()
 
 	cID := builder.cs.AddR1C(builder.newR1C(res, vars[0], builder.cstOne()), builder.genericGate)
-	if debug.Debug {
+	if builder.withDebugInfo() {
 		debug := builder.newDebugInfo("inverse", vars[0], "*", res, " == 1")
 		builder.cs.AttachDebugInfo(debug, []int{cID})
 	}
@@ -616,15 +625,19 @@ func (builder *builder) Println(a ...frontend.Variable) {
 		if i > 0 {
 			sbb.WriteByte(' ')
 		}
+		var f constraint.LogFormat
+		if lv, ok := arg.(frontend.LogValue); ok {
+			arg, f = lv.V, lv.Format
+		}
 		if v, ok := arg.(expr.LinearExpression); ok {
 			assertIsSet(v)
 
 			sbb.WriteString("%s")
 			// we set limits to the linear expression, so that the log printer
 			// can evaluate it before printing it
-			log.ToResolve = append(log.ToResolve, builder.getLinearExpression(v))
+			log.AppendToResolve(builder.getLinearExpression(v), f)
 		} else {
-			builder.printArg(&log, &sbb, arg)
+			builder.printArg(&log, &sbb, arg, f)
 		}
 	}
 
@@ -634,21 +647,38 @@ func (builder *builder) Println(a ...frontend.Variable) {
 	builder.cs.AddLog(log)
 }
 
-func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder, a frontend.Variable) {
+// printableConstant returns the value of a if it is a constant. Unlike
+// ConstantValue, it doesn't panic on values which are not numbers, such as
+// strings given to Println.
+func (builder *builder) printableConstant(a frontend.Variable) (*big.Int, bool) {
+	if _, err := utils.TryFromInterface(a); err != nil {
+		return nil, false
+	}
+	return builder.ConstantValue(a)
+}
+
+func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder, a frontend.Variable, f constraint.LogFormat) {
 
 	leafCount, err := schema.Walk(a, tVariable, nil)
 	count := leafCount.Public + leafCount.Secret
 
 	// no variables in nested struct, we use fmt std print function
 	if count == 0 || err != nil {
+		if !f.IsDefault() {
+			// formatted constant, we can print it right away
+			if c, ok := builder.printableConstant(a); ok {
+				sbb.WriteString(f.Format(c))
+				return
+			}
+		}
 		sbb.WriteString(fmt.Sprint(a))
 		return
 	}
 
 	sbb.WriteByte('{')
-	printer := func(f schema.LeafInfo, tValue reflect.Value) error {
+	printer := func(leaf schema.LeafInfo, tValue reflect.Value) error {
 		count--
-		sbb.WriteString(f.FullName())
+		sbb.WriteString(leaf.FullName())
 		sbb.WriteString(": ")
 		sbb.WriteString("%s")
 		if count != 0 {
@@ -658,7 +688,7 @@ func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder,
 		v := tValue.Interface().(expr.LinearExpression)
 		// we set limits to the linear expression, so that the log printer
 		// can evaluate it before printing it
-		log.ToResolve = append(log.ToResolve, builder.getLinearExpression(v))
+		log.AppendToResolve(builder.getLinearExpression(v), f)
 		return nil
 	}
 	// ignoring error, printer() doesn't return errors
@@ -20,7 +20,7 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/std/math/bits"
@@ -34,7 +34,7 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 
 	cID := builder.cs.AddR1C(builder.newR1C(builder.cstOne(), r, o), builder.genericGate)
 
-	if debug.Debug {
+	if builder.withDebugInfo() {
 		debug := builder.newDebugInfo("assertIsEqual", r, " == ", o)
 		builder.cs.AttachDebugInfo(debug, []int{cID})
 	}
@@ -75,12 +75,73 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 	V := builder.getLinearExpression(v)
 
 	cID := builder.cs.AddR1C(builder.newR1C(V, _v, o), builder.genericGate)
-	if debug.Debug {
+	if builder.withDebugInfo() {
 		debug := builder.newDebugInfo("assertIsBoolean", V, " == (0|1)")
 		builder.cs.AttachDebugInfo(debug, []int{cID})
 	}
 }
 
+// AssertIsInSet adds an assertion in the constraint builder (i1 ∈ set)
+func (builder *builder) AssertIsInSet(i1 frontend.Variable, set ...frontend.Variable) {
+	values := builder.setValues(set)
+
+	v := builder.toVariable(i1)
+	if c, ok := builder.constantValue(v); ok {
+		for i := range values {
+			if values[i] == c {
+				return
+			}
+		}
+		panic(fmt.Sprintf("assertIsInSet failed: constant(%s)", builder.cs.String(c)))
+	}
+
+	switch {
+	case len(values) == 1:
+		builder.AssertIsEqual(v, builder.cs.ToBigInt(values[0]))
+		return
+	case len(values) == 2 && (values[0].IsZero() && builder.isCstOne(values[1]) || values[1].IsZero() && builder.isCstOne(values[0])):
+		builder.AssertIsBoolean(v)
+		return
+	}
+
+	// ∏ (v - s) == 0, the last multiplication being the constraint
+	var prod frontend.Variable = builder.Sub(v, builder.cs.ToBigInt(values[0]))
+	for i := 1; i < len(values)-1; i++ {
+		prod = builder.Mul(prod, builder.Sub(v, builder.cs.ToBigInt(values[i])))
+	}
+	last := builder.Sub(v, builder.cs.ToBigInt(values[len(values)-1]))
+	cID := builder.cs.AddR1C(builder.newR1C(prod, last, builder.cstZero()), builder.genericGate)
+	if builder.withDebugInfo() {
+		debug := builder.newDebugInfo("assertIsInSet", v, " ∈ set")
+		builder.cs.AttachDebugInfo(debug, []int{cID})
+	}
+}
+
+// setValues returns the distinct values of the constants in set.
+func (builder *builder) setValues(set []frontend.Variable) []constraint.Element {
+	if len(set) == 0 {
+		panic("assertIsInSet: empty set")
+	}
+	values := make([]constraint.Element, 0, len(set))
+	for _, s := range set {
+		c, ok := builder.constantValue(builder.toVariable(s))
+		if !ok {
+			panic("assertIsInSet: set must contain constants only")
+		}
+		duplicate := false
+		for i := range values {
+			if values[i] == c {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			values = append(values, c)
+		}
+	}
+	return values
+}
+
 // AssertIsLessOrEqual adds assertion in constraint builder  (v ⩽ bound)
 //
 // bound can be a constant or a Variable
@@ -97,14 +158,12 @@ func (builder *builder) AssertIsLessOrEqual(v frontend.Variable, bound frontend.
 		if bv.Cmp(bb) == 1 {
 			panic(fmt.Sprintf("AssertIsLessOrEqual: %s > %s", bv.String(), bb.String()))
 		}
+		return
 	}
 
-	nbBits := builder.cs.FieldBitLen()
-	vBits := bits.ToBinary(builder, v, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
-
 	// bound is constant
 	if bConst {
-		builder.MustBeLessOrEqCst(vBits, builder.cs.ToBigInt(cb), v)
+		builder.mustBeLessOrEqCstBound(v, builder.cs.ToBigInt(cb))
 		return
 	}
 
@@ -168,27 +227,58 @@ func (builder *builder) mustBeLessOrEqVar(a, bound frontend.Variable) {
 
 }
 
+// mustBeLessOrEqCstBound asserts that v ⩽ bound for a constant bound. Instead
+// of decomposing v into field bit-length bits, we decompose it into exactly
+// bound.BitLen() bits which already range checks v and then compare the bits
+// against the bit pattern of the bound. As 2^bound.BitLen() is smaller than
+// the modulus, the decomposition is unique and we can omit the reducedness
+// check.
+func (builder *builder) mustBeLessOrEqCstBound(v frontend.Variable, bound *big.Int) {
+	if bound.Sign() == -1 {
+		panic("AssertIsLessOrEqual: bound must be positive")
+	}
+	nbBits := bound.BitLen()
+	if nbBits == 0 {
+		builder.AssertIsEqual(v, 0)
+		return
+	}
+	if nbBits >= builder.cs.FieldBitLen() {
+		// the bound is as large as the modulus, we need full decomposition
+		// with the reducedness check.
+		nbBits = builder.cs.FieldBitLen()
+	}
+	vBits := bits.ToBinary(builder, v, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
+	builder.MustBeLessOrEqCst(vBits, bound, v)
+}
+
 // MustBeLessOrEqCst asserts that value represented using its bit decomposition
 // aBits is less or equal than constant bound. The method boolean constraints
 // the bits in aBits, so the caller can provide unconstrained bits.
 func (builder *builder) MustBeLessOrEqCst(aBits []frontend.Variable, bound *big.Int, aForDebug frontend.Variable) {
 
-	nbBits := builder.cs.FieldBitLen()
-	if len(aBits) > nbBits {
+	fieldBits := builder.cs.FieldBitLen()
+	if len(aBits) > fieldBits {
 		panic("more input bits than field bit length")
 	}
-	for i := len(aBits); i < nbBits; i++ {
-		aBits = append(aBits, 0)
-	}
 
 	// ensure the bound is positive, it's bit-len doesn't matter
 	if bound.Sign() == -1 {
 		panic("AssertIsLessOrEqual: bound must be positive")
 	}
-	if bound.BitLen() > nbBits {
+	if bound.BitLen() > fieldBits {
 		panic("AssertIsLessOrEqual: bound is too large, constraint will never be satisfied")
 	}
 
+	// we only need to compare the bits up to the bit-length of the bound. If
+	// the input is longer, then the excess bits are constrained to be zero.
+	nbBits := len(aBits)
+	if bound.BitLen() > nbBits {
+		nbBits = bound.BitLen()
+	}
+	for i := len(aBits); i < nbBits; i++ {
+		aBits = append(aBits, 0)
+	}
+
 	// debug info
 	debug := builder.newDebugInfo("mustBeLessOrEq", aForDebug, " <= ", builder.toVariable(bound))
 
@@ -92,43 +92,50 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 
 	// by default the circuit is given a public wire equal to 1
 
+	builder.cs = newR1CS(field, config.Capacity)
+
+	builder.tOne = builder.cs.One()
+	builder.cs.AddPublicVariable(builder.wireName(schema.Public, "1"))
+
+	builder.genericGate = builder.cs.AddBlueprint(&constraint.BlueprintGenericR1C{})
+
+	builder.eZero = expr.NewLinearExpression(0, constraint.Element{})
+	builder.eOne = expr.NewLinearExpression(0, builder.tOne)
+
+	builder.cOne = constraint.LinearExpression{constraint.Term{VID: 0, CID: constraint.CoeffIdOne}}
+	builder.cZero = constraint.LinearExpression{constraint.Term{VID: 0, CID: constraint.CoeffIdZero}}
+
+	return &builder
+}
+
+// newR1CS returns an empty R1CS over the given field.
+func newR1CS(field *big.Int, capacity int) constraint.R1CS {
 	curve := utils.FieldToCurve(field)
 
 	switch curve {
 	case ecc.BLS12_377:
-		builder.cs = bls12377r1cs.NewR1CS(config.Capacity)
+		return bls12377r1cs.NewR1CS(capacity)
 	case ecc.BLS12_381:
-		builder.cs = bls12381r1cs.NewR1CS(config.Capacity)
+		return bls12381r1cs.NewR1CS(capacity)
 	case ecc.BN254:
-		builder.cs = bn254r1cs.NewR1CS(config.Capacity)
+		return bn254r1cs.NewR1CS(capacity)
 	case ecc.BW6_761:
-		builder.cs = bw6761r1cs.NewR1CS(config.Capacity)
+		return bw6761r1cs.NewR1CS(capacity)
 	case ecc.BW6_633:
-		builder.cs = bw6633r1cs.NewR1CS(config.Capacity)
+		return bw6633r1cs.NewR1CS(capacity)
 	case ecc.BLS24_315:
-		builder.cs = bls24315r1cs.NewR1CS(config.Capacity)
+		return bls24315r1cs.NewR1CS(capacity)
 	case ecc.BLS24_317:
-		builder.cs = bls24317r1cs.NewR1CS(config.Capacity)
+		return bls24317r1cs.NewR1CS(capacity)
 	default:
 		if field.Cmp(tinyfield.Modulus()) == 0 {
-			builder.cs = tinyfieldr1cs.NewR1CS(config.Capacity)
-			break
+			return tinyfieldr1cs.NewR1CS(capacity)
+		}
+		if custom, ok := constraint.GetCustomField(field); ok {
+			return custom.NewR1CS(capacity)
 		}
 		panic("not implemented")
 	}
-
-	builder.tOne = builder.cs.One()
-	builder.cs.AddPublicVariable("1")
-
-	builder.genericGate = builder.cs.AddBlueprint(&constraint.BlueprintGenericR1C{})
-
-	builder.eZero = expr.NewLinearExpression(0, constraint.Element{})
-	builder.eOne = expr.NewLinearExpression(0, builder.tOne)
-
-	builder.cOne = constraint.LinearExpression{constraint.Term{VID: 0, CID: constraint.CoeffIdOne}}
-	builder.cZero = constraint.LinearExpression{constraint.Term{VID: 0, CID: constraint.CoeffIdZero}}
-
-	return &builder
 }
 
 // newInternalVariable creates a new wire, appends it on the list of wires of the circuit, sets
@@ -140,16 +147,29 @@ func (builder *builder) newInternalVariable() expr.LinearExpression {
 
 // PublicVariable creates a new public Variable
 func (builder *builder) PublicVariable(f schema.LeafInfo) frontend.Variable {
-	idx := builder.cs.AddPublicVariable(f.FullName())
+	idx := builder.cs.AddPublicVariable(builder.wireName(schema.Public, f.FullName()))
 	return expr.NewLinearExpression(idx, builder.tOne)
 }
 
 // SecretVariable creates a new secret Variable
 func (builder *builder) SecretVariable(f schema.LeafInfo) frontend.Variable {
-	idx := builder.cs.AddSecretVariable(f.FullName())
+	idx := builder.cs.AddSecretVariable(builder.wireName(schema.Secret, f.FullName()))
 	return expr.NewLinearExpression(idx, builder.tOne)
 }
 
+// wireName returns the name of the input wire to store in the constraint
+// system and records its full name in the sidecar, if any.
+func (builder *builder) wireName(visibility schema.Visibility, name string) string {
+	if sidecar := builder.config.WireNames; sidecar != nil {
+		if visibility == schema.Public {
+			sidecar.Public = append(sidecar.Public, name)
+		} else {
+			sidecar.Secret = append(sidecar.Secret, name)
+		}
+	}
+	return builder.config.WireNaming.Name(name)
+}
+
 // cstOne return the one constant
 func (builder *builder) cstOne() expr.LinearExpression {
 	return builder.eOne
@@ -286,6 +306,13 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		}
 	}
 
+	if builder.config.LevelBlockSize > 0 {
+		// the curve-typed systems embed *constraint.System
+		if s, ok := builder.cs.(interface{ ReorderLevels(int) }); ok {
+			s.ReorderLevels(builder.config.LevelBlockSize)
+		}
+	}
+
 	return builder.cs, nil
 }
 
@@ -428,6 +455,12 @@ func assertIsSet(l expr.LinearExpression) {
 	}
 }
 
+// withDebugInfo returns true if the debug information of the assertions must be
+// recorded, see [frontend.WithDebugInfo].
+func (builder *builder) withDebugInfo() bool {
+	return debug.Debug || builder.config.DebugInfo
+}
+
 // newDebugInfo this is temporary to restore debug logs
 // something more like builder.sprintf("my message %le %lv", l0, l1)
 // to build logs for both debug and println
@@ -0,0 +1,39 @@
+package r1cs
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// Merge returns the R1CS made of the constraints of a and b, where the inputs
+// of b named in the keys of bindings are identified with the inputs of a named
+// by the values. It allows to compile a standard sub-circuit once and to link
+// it into larger systems without compiling it again.
+//
+// The other inputs of b are kept as new inputs of the merged system, which are
+// ordered as the public inputs of a, the unbound public inputs of b, the secret
+// inputs of a and the unbound secret inputs of b. The witness of the merged
+// system must follow this order.
+//
+// The systems must be R1CS over the same field, without commitments. The debug
+// information of a and b is not kept in the merged system.
+func Merge(a, b constraint.ConstraintSystem, bindings map[string]string) (constraint.ConstraintSystem, error) {
+	ra, ok := a.(constraint.R1CS)
+	if !ok {
+		return nil, fmt.Errorf("first system is not a R1CS")
+	}
+	rb, ok := b.(constraint.R1CS)
+	if !ok {
+		return nil, fmt.Errorf("second system is not a R1CS")
+	}
+	if a.Field().Cmp(b.Field()) != 0 {
+		return nil, fmt.Errorf("systems are defined over different fields")
+	}
+	capacity := a.GetNbConstraints() + b.GetNbConstraints()
+	res := newR1CS(a.Field(), capacity)
+	if err := constraint.MergeR1CS(res, ra, rb, bindings); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
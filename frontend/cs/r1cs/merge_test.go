@@ -0,0 +1,103 @@
+package r1cs_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type squareCircuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.X, api.Mul(c.Y, c.Y))
+	return nil
+}
+
+type smallTripleCircuit struct {
+	W frontend.Variable `gnark:",public"`
+	Z frontend.Variable
+}
+
+func (c *smallTripleCircuit) Define(api frontend.API) error {
+	api.ToBinary(c.Z, 8)
+	api.AssertIsEqual(c.W, api.Mul(c.Z, 3))
+	return nil
+}
+
+func mergedWitness(t *testing.T, x, w, y int) witness.Witness {
+	// public: X, W, secret: Y bound to Z
+	wit, err := witness.New(ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	values := make(chan any)
+	go func() {
+		defer close(values)
+		values <- x
+		values <- w
+		values <- y
+	}()
+	require.NoError(t, wit.Fill(2, 1, values))
+	return wit
+}
+
+func TestMerge(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	a, err := frontend.Compile(field, r1cs.NewBuilder, &squareCircuit{})
+	require.NoError(t, err)
+	b, err := frontend.Compile(field, r1cs.NewBuilder, &smallTripleCircuit{})
+	require.NoError(t, err)
+
+	ccs, err := r1cs.Merge(a, b, map[string]string{"Z": "Y"})
+	require.NoError(t, err)
+	require.Equal(t, a.GetNbConstraints()+b.GetNbConstraints(), ccs.GetNbConstraints())
+	require.Equal(t, 3, ccs.GetNbPublicVariables())
+	require.Equal(t, 1, ccs.GetNbSecretVariables())
+
+	require.NoError(t, ccs.IsSolved(mergedWitness(t, 25, 15, 5)))
+	require.Error(t, ccs.IsSolved(mergedWitness(t, 25, 16, 5)))
+	require.Error(t, ccs.IsSolved(mergedWitness(t, 26, 15, 5)))
+	// Z must fit in 8 bits
+	require.Error(t, ccs.IsSolved(mergedWitness(t, 256*256, 3*256, 256)))
+
+	pk, vk, err := groth16.Setup(ccs)
+	require.NoError(t, err)
+	wit := mergedWitness(t, 49, 21, 7)
+	proof, err := groth16.Prove(ccs, pk, wit)
+	require.NoError(t, err)
+	pub, err := wit.Public()
+	require.NoError(t, err)
+	require.NoError(t, groth16.Verify(proof, vk, pub))
+}
+
+func TestMergeErrors(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	a, err := frontend.Compile(field, r1cs.NewBuilder, &squareCircuit{})
+	require.NoError(t, err)
+	b, err := frontend.Compile(field, r1cs.NewBuilder, &smallTripleCircuit{})
+	require.NoError(t, err)
+
+	_, err = r1cs.Merge(a, b, map[string]string{"Z": "V"})
+	require.Error(t, err)
+	_, err = r1cs.Merge(a, b, map[string]string{"V": "Y"})
+	require.Error(t, err)
+
+	var sparse constraint.ConstraintSystem
+	sparse, err = frontend.Compile(field, scs.NewBuilder, &smallTripleCircuit{})
+	require.NoError(t, err)
+	_, err = r1cs.Merge(a, sparse, nil)
+	require.Error(t, err)
+
+	other, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, &smallTripleCircuit{})
+	require.NoError(t, err)
+	_, err = r1cs.Merge(a, other, nil)
+	require.Error(t, err)
+}
@@ -18,12 +18,12 @@ package scs
 
 import (
 	"fmt"
+	"math/big"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
 
-	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend/cs"
 
 	"github.com/consensys/gnark/constraint"
@@ -32,6 +32,7 @@ import (
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/frontendtype"
+	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/std/math/bits"
 )
 
@@ -149,7 +150,9 @@ func (builder *builder) mulConstant(t expr.Term, m constraint.Element) expr.Term
 	return t
 }
 
-// DivUnchecked returns i1 / i2 . if i1 == i2 == 0, returns 0
+// DivUnchecked returns i1 / i2 . The denominator is not constrained to be
+// non-zero. If i1 == i2 == 0, the solver returns 0 but the result is
+// unconstrained. See [frontend.API.DivUnchecked].
 func (builder *builder) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable {
 	c1, i1Constant := builder.constantValue(i1)
 	c2, i2Constant := builder.constantValue(i2)
@@ -170,12 +173,25 @@ func (builder *builder) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable
 		return builder.mulConstant(i1.(expr.Term), c2)
 	}
 	if i1Constant {
+		if c1.IsZero() {
+			// 0 / i2 == 0 whenever i2 != 0, and the result is unconstrained
+			// when i2 == 0. We can return the constant directly.
+			return builder.cs.ToBigInt(c1)
+		}
 		res := builder.Inverse(i2)
 		return builder.mulConstant(res.(expr.Term), c1)
 	}
 
+	// res = i1 / i2 		// in a hint (res == 0 if i2 == 0)
 	// res * i2 == i1
-	res := builder.newInternalVariable()
+	// the solver of the generic constraint fails on 0/0, so we compute the
+	// result in a hint and the constraint only checks it.
+	out, err := builder.NewHint(solver.DivZeroHint, 1, i1, i2)
+	if err != nil {
+		// the function errs only if the number of inputs is invalid.
+		panic(err)
+	}
+	res := out[0].(expr.Term)
 	builder.addPlonkConstraint(sparseR1C{
 		xa: res.VID,
 		xb: i2.(expr.Term).VID,
@@ -187,7 +203,7 @@ func (builder *builder) DivUnchecked(i1, i2 frontend.Variable) frontend.Variable
 	return res
 }
 
-// Div returns i1 / i2
+// Div returns i1 / i2 . The denominator is constrained to be non-zero.
 func (builder *builder) Div(i1, i2 frontend.Variable) frontend.Variable {
 	// note that here we ensure that v2 can't be 0, but it costs us one extra constraint
 	builder.Inverse(i2)
@@ -215,7 +231,7 @@ func (builder *builder) Inverse(i1 frontend.Variable) frontend.Variable {
 		qC: builder.tMinusOne,
 	}
 
-	if debug.Debug {
+	if builder.withDebugInfo() {
 		debug := builder.newDebugInfo("inverse", "1/", i1, " < ∞")
 		builder.addPlonkConstraint(constraint, debug)
 	} else {
@@ -567,14 +583,18 @@ func (builder *builder) Println(a ...frontend.Variable) {
 		if i > 0 {
 			sbb.WriteByte(' ')
 		}
+		var f constraint.LogFormat
+		if lv, ok := arg.(frontend.LogValue); ok {
+			arg, f = lv.V, lv.Format
+		}
 		if v, ok := arg.(expr.Term); ok {
 
 			sbb.WriteString("%s")
 			// we set limits to the linear expression, so that the log printer
 			// can evaluate it before printing it
-			log.ToResolve = append(log.ToResolve, constraint.LinearExpression{builder.cs.MakeTerm(v.Coeff, v.VID)})
+			log.AppendToResolve(constraint.LinearExpression{builder.cs.MakeTerm(v.Coeff, v.VID)}, f)
 		} else {
-			builder.printArg(&log, &sbb, arg)
+			builder.printArg(&log, &sbb, arg, f)
 		}
 	}
 
@@ -584,21 +604,38 @@ func (builder *builder) Println(a ...frontend.Variable) {
 	builder.cs.AddLog(log)
 }
 
-func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder, a frontend.Variable) {
+// printableConstant returns the value of a if it is a constant. Unlike
+// ConstantValue, it doesn't panic on values which are not numbers, such as
+// strings given to Println.
+func (builder *builder) printableConstant(a frontend.Variable) (*big.Int, bool) {
+	if _, err := utils.TryFromInterface(a); err != nil {
+		return nil, false
+	}
+	return builder.ConstantValue(a)
+}
+
+func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder, a frontend.Variable, f constraint.LogFormat) {
 
 	leafCount, err := schema.Walk(a, tVariable, nil)
 	count := leafCount.Public + leafCount.Secret
 
 	// no variables in nested struct, we use fmt std print function
 	if count == 0 || err != nil {
+		if !f.IsDefault() {
+			// formatted constant, we can print it right away
+			if c, ok := builder.printableConstant(a); ok {
+				sbb.WriteString(f.Format(c))
+				return
+			}
+		}
 		sbb.WriteString(fmt.Sprint(a))
 		return
 	}
 
 	sbb.WriteByte('{')
-	printer := func(f schema.LeafInfo, tValue reflect.Value) error {
+	printer := func(leaf schema.LeafInfo, tValue reflect.Value) error {
 		count--
-		sbb.WriteString(f.FullName())
+		sbb.WriteString(leaf.FullName())
 		sbb.WriteString(": ")
 		sbb.WriteString("%s")
 		if count != 0 {
@@ -608,7 +645,7 @@ func (builder *builder) printArg(log *constraint.LogEntry, sbb *strings.Builder,
 		v := tValue.Interface().(expr.Term)
 		// we set limits to the linear expression, so that the log printer
 		// can evaluate it before printing it
-		log.ToResolve = append(log.ToResolve, constraint.LinearExpression{builder.cs.MakeTerm(v.Coeff, v.VID)})
+		log.AppendToResolve(constraint.LinearExpression{builder.cs.MakeTerm(v.Coeff, v.VID)}, f)
 		return nil
 	}
 	// ignoring error, printer() doesn't return errors
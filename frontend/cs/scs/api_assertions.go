@@ -20,7 +20,7 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/std/math/bits"
@@ -54,7 +54,7 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 			qC: c2,
 		}
 
-		if debug.Debug {
+		if builder.withDebugInfo() {
 			debug := builder.newDebugInfo("assertIsEqual", xa, "==", i2)
 			builder.addPlonkConstraint(toAdd, debug)
 		} else {
@@ -74,7 +74,7 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 		qR: xb.Coeff,
 	}
 
-	if debug.Debug {
+	if builder.withDebugInfo() {
 		xb.Coeff = builder.cs.Neg(xb.Coeff)
 		debug := builder.newDebugInfo("assertIsEqual", xa, " == ", xb)
 		builder.addPlonkConstraint(toAdd, debug)
@@ -86,8 +86,11 @@ func (builder *builder) AssertIsEqual(i1, i2 frontend.Variable) {
 // AssertIsDifferent fails if i1 == i2
 func (builder *builder) AssertIsDifferent(i1, i2 frontend.Variable) {
 	s := builder.Sub(i1, i2)
-	if c, ok := builder.constantValue(s); ok && c.IsZero() {
-		panic("AssertIsDifferent(x,x) will never be satisfied")
+	if c, ok := builder.constantValue(s); ok {
+		if c.IsZero() {
+			panic("AssertIsDifferent(x,x) will never be satisfied")
+		}
+		return
 	} else if t := s.(expr.Term); t.Coeff.IsZero() {
 		panic("AssertIsDifferent(x,x) will never be satisfied")
 	}
@@ -119,7 +122,7 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 		qL: v.Coeff,
 		qM: qM,
 	}
-	if debug.Debug {
+	if builder.withDebugInfo() {
 		debug := builder.newDebugInfo("assertIsBoolean", v, " == (0|1)")
 		builder.addBoolGate(toAdd, debug)
 	} else {
@@ -128,6 +131,93 @@ func (builder *builder) AssertIsBoolean(i1 frontend.Variable) {
 
 }
 
+// AssertIsInSet fails if i1 ∉ set
+func (builder *builder) AssertIsInSet(i1 frontend.Variable, set ...frontend.Variable) {
+	values := builder.setValues(set)
+
+	if c, ok := builder.constantValue(i1); ok {
+		for i := range values {
+			if values[i] == c {
+				return
+			}
+		}
+		panic(fmt.Sprintf("assertIsInSet failed: constant(%s)", builder.cs.String(c)))
+	}
+
+	switch {
+	case len(values) == 1:
+		builder.AssertIsEqual(i1, builder.cs.ToBigInt(values[0]))
+		return
+	case len(values) == 2 && (values[0].IsZero() && builder.cs.IsOne(values[1]) || values[1].IsZero() && builder.cs.IsOne(values[0])):
+		builder.AssertIsBoolean(i1)
+		return
+	}
+
+	// ∏ (v - s) == 0 with one constraint per multiplication. The first one
+	// computes (v - s0)(v - s1) = v² - (s0+s1)v + s0s1, the next ones
+	// p(v - s) = pv - sp and the last one has no output.
+	v := i1.(expr.Term)
+	var debugInfo []constraint.DebugInfo
+	if builder.withDebugInfo() {
+		debugInfo = append(debugInfo, builder.newDebugInfo("assertIsInSet", v, " ∈ set"))
+	}
+	output := func(last bool) (int, constraint.Element) {
+		if last {
+			return 0, constraint.Element{}
+		}
+		return builder.newInternalVariable().VID, builder.tMinusOne
+	}
+
+	s0s1 := builder.cs.Add(values[0], values[1])
+	xc, qO := output(len(values) == 2)
+	builder.addPlonkConstraint(sparseR1C{
+		xa: v.VID,
+		xb: v.VID,
+		xc: xc,
+		qM: builder.cs.Mul(v.Coeff, v.Coeff),
+		qL: builder.cs.Neg(builder.cs.Mul(s0s1, v.Coeff)),
+		qO: qO,
+		qC: builder.cs.Mul(values[0], values[1]),
+	}, debugInfo...)
+	for i := 2; i < len(values); i++ {
+		p := xc
+		xc, qO = output(i == len(values)-1)
+		builder.addPlonkConstraint(sparseR1C{
+			xa: p,
+			xb: v.VID,
+			xc: xc,
+			qM: v.Coeff,
+			qL: builder.cs.Neg(values[i]),
+			qO: qO,
+		}, debugInfo...)
+	}
+}
+
+// setValues returns the distinct values of the constants in set.
+func (builder *builder) setValues(set []frontend.Variable) []constraint.Element {
+	if len(set) == 0 {
+		panic("assertIsInSet: empty set")
+	}
+	values := make([]constraint.Element, 0, len(set))
+	for _, s := range set {
+		c, ok := builder.constantValue(s)
+		if !ok {
+			panic("assertIsInSet: set must contain constants only")
+		}
+		duplicate := false
+		for i := range values {
+			if values[i] == c {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			values = append(values, c)
+		}
+	}
+	return values
+}
+
 // AssertIsLessOrEqual fails if  v > bound
 func (builder *builder) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variable) {
 	cv, vConst := builder.constantValue(v)
@@ -139,14 +229,12 @@ func (builder *builder) AssertIsLessOrEqual(v frontend.Variable, bound frontend.
 		if bv.Cmp(bb) == 1 {
 			panic(fmt.Sprintf("AssertIsLessOrEqual: %s > %s", bv.String(), bb.String()))
 		}
+		return
 	}
 
-	nbBits := builder.cs.FieldBitLen()
-	vBits := bits.ToBinary(builder, v, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
-
 	// bound is constant
 	if bConst {
-		builder.MustBeLessOrEqCst(vBits, builder.cs.ToBigInt(cb), v)
+		builder.mustBeLessOrEqCstBound(v, builder.cs.ToBigInt(cb))
 		return
 	}
 
@@ -209,27 +297,58 @@ func (builder *builder) mustBeLessOrEqVar(a frontend.Variable, bound expr.Term)
 
 }
 
+// mustBeLessOrEqCstBound asserts that v ⩽ bound for a constant bound. Instead
+// of decomposing v into field bit-length bits, we decompose it into exactly
+// bound.BitLen() bits which already range checks v and then compare the bits
+// against the bit pattern of the bound. As 2^bound.BitLen() is smaller than
+// the modulus, the decomposition is unique and we can omit the reducedness
+// check.
+func (builder *builder) mustBeLessOrEqCstBound(v frontend.Variable, bound *big.Int) {
+	if bound.Sign() == -1 {
+		panic("AssertIsLessOrEqual: bound must be positive")
+	}
+	nbBits := bound.BitLen()
+	if nbBits == 0 {
+		builder.AssertIsEqual(v, 0)
+		return
+	}
+	if nbBits >= builder.cs.FieldBitLen() {
+		// the bound is as large as the modulus, we need full decomposition
+		// with the reducedness check.
+		nbBits = builder.cs.FieldBitLen()
+	}
+	vBits := bits.ToBinary(builder, v, bits.WithNbDigits(nbBits), bits.WithUnconstrainedOutputs())
+	builder.MustBeLessOrEqCst(vBits, bound, v)
+}
+
 // MustBeLessOrEqCst asserts that value represented using its bit decomposition
 // aBits is less or equal than constant bound. The method boolean constraints
 // the bits in aBits, so the caller can provide unconstrained bits.
 func (builder *builder) MustBeLessOrEqCst(aBits []frontend.Variable, bound *big.Int, aForDebug frontend.Variable) {
 
-	nbBits := builder.cs.FieldBitLen()
-	if len(aBits) > nbBits {
+	fieldBits := builder.cs.FieldBitLen()
+	if len(aBits) > fieldBits {
 		panic("more input bits than field bit length")
 	}
-	for i := len(aBits); i < nbBits; i++ {
-		aBits = append(aBits, 0)
-	}
 
 	// ensure the bound is positive, it's bit-len doesn't matter
 	if bound.Sign() == -1 {
 		panic("AssertIsLessOrEqual: bound must be positive")
 	}
-	if bound.BitLen() > nbBits {
+	if bound.BitLen() > fieldBits {
 		panic("AssertIsLessOrEqual: bound is too large, constraint will never be satisfied")
 	}
 
+	// we only need to compare the bits up to the bit-length of the bound. If
+	// the input is longer, then the excess bits are constrained to be zero.
+	nbBits := len(aBits)
+	if bound.BitLen() > nbBits {
+		nbBits = bound.BitLen()
+	}
+	for i := len(aBits); i < nbBits; i++ {
+		aBits = append(aBits, 0)
+	}
+
 	// debug info
 	debug := builder.newDebugInfo("mustBeLessOrEq", aForDebug, " <= ", bound)
 
@@ -257,6 +376,11 @@ func (builder *builder) MustBeLessOrEqCst(aBits []frontend.Variable, bound *big.
 	for i := nbBits - 1; i >= 0; i-- {
 
 		if bound.Bit(i) == 0 {
+			if _, ok := builder.constantValue(aBits[i]); ok {
+				// (1 - p(i+1) - ai) * ai == 0 with constant ai
+				builder.AssertIsEqual(builder.Mul(builder.Sub(1, p[i+1], aBits[i]), aBits[i]), 0)
+				continue
+			}
 			// (1 - p(i+1) - ai) * ai == 0
 			l := builder.Sub(1, p[i+1], aBits[i]).(expr.Term)
 			//l = builder.Sub(l, ).(term)
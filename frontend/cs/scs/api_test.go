@@ -155,6 +155,59 @@ func TestExistDiv0(t *testing.T) {
 	_ = solution
 }
 
+type divZeroByZeroCircuit struct {
+	A, B    frontend.Variable
+	Res     frontend.Variable
+	Checked bool
+}
+
+func (c *divZeroByZeroCircuit) Define(api frontend.API) error {
+	var r frontend.Variable
+	if c.Checked {
+		r = api.Div(c.A, c.B)
+	} else {
+		r = api.DivUnchecked(c.A, c.B)
+	}
+	api.AssertIsEqual(r, c.Res)
+	return nil
+}
+
+func TestDivZeroByZero(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, checked := range []bool{false, true} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &divZeroByZeroCircuit{Checked: checked})
+		assert.NoError(err)
+		for _, res := range []int{0, 1} {
+			w, err := frontend.NewWitness(&divZeroByZeroCircuit{A: 0, B: 0, Res: res}, ecc.BN254.ScalarField())
+			assert.NoError(err)
+			_, err = ccs.Solve(w)
+			if !checked && res == 0 {
+				// DivUnchecked(0, 0) is solved to 0
+				assert.NoError(err)
+			} else {
+				// Div constrains the denominator to be non-zero
+				assert.Error(err, "checked=%t res=%d", checked, res)
+			}
+		}
+	}
+}
+
+func TestDivUncheckedByZero(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &divZeroByZeroCircuit{})
+	assert.NoError(err)
+	// the result computed in the hint is 0, but the constraint res * 0 == 1
+	// does not hold for any result
+	w, err := frontend.NewWitness(&divZeroByZeroCircuit{A: 1, B: 0, Res: 0}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = ccs.Solve(w)
+	assert.Error(err)
+	w, err = frontend.NewWitness(&divZeroByZeroCircuit{A: 6, B: 3, Res: 2}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = ccs.Solve(w)
+	assert.NoError(err)
+}
+
 type mulAccFastTrackCircuit struct {
 	A, B frontend.Variable
 	Res  frontend.Variable
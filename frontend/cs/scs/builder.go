@@ -70,6 +70,15 @@ type builder struct {
 	genericGate                constraint.BlueprintID
 	mulGate, addGate, boolGate constraint.BlueprintID
 
+	// custom gates, indexed by the identifier returned by DefineCustomGate
+	customGates   []customGate
+	mCustomGateID map[string]int
+
+	// lookup tables, indexed by the identifier returned by DefineLookupTable
+	lookupTables   []lookupTable
+	mLookupTableID map[string]int
+	lookupZero     *expr.Term // wire constrained to zero, for unset lookup columns
+
 	// used to avoid repeated allocations
 	bufL expr.LinearExpression
 	bufH []constraint.LinearExpression
@@ -82,6 +91,8 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		mtBooleans:       make(map[expr.Term]struct{}),
 		mMulInstructions: make(map[uint64]int, config.Capacity/2),
 		mAddInstructions: make(map[uint64]int, config.Capacity/2),
+		mCustomGateID:    make(map[string]int),
+		mLookupTableID:   make(map[string]int),
 		config:           config,
 		Store:            kvstore.New(),
 		bufL:             make(expr.LinearExpression, 20),
@@ -111,6 +122,10 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 			b.cs = tinyfieldr1cs.NewSparseR1CS(config.Capacity)
 			break
 		}
+		if custom, ok := constraint.GetCustomField(field); ok {
+			b.cs = custom.NewSparseR1CS(config.Capacity)
+			break
+		}
 		panic("not implemented")
 	}
 
@@ -181,7 +196,7 @@ func (builder *builder) addBoolGate(c sparseR1C, debugInfo ...constraint.DebugIn
 		QL: QL,
 		QM: QM},
 		builder.boolGate)
-	if debug.Debug && len(debugInfo) == 1 {
+	if builder.withDebugInfo() && len(debugInfo) == 1 {
 		builder.cs.AttachDebugInfo(debugInfo[0], []int{cID})
 	}
 }
@@ -209,7 +224,7 @@ func (builder *builder) addPlonkConstraint(c sparseR1C, debugInfo ...constraint.
 		QO: QO,
 		QM: QM,
 		QC: QC, Commitment: c.commitment}, builder.genericGate)
-	if debug.Debug && len(debugInfo) == 1 {
+	if builder.withDebugInfo() && len(debugInfo) == 1 {
 		builder.cs.AttachDebugInfo(debugInfo[0], []int{cID})
 	}
 }
@@ -223,16 +238,29 @@ func (builder *builder) newInternalVariable() expr.Term {
 
 // PublicVariable creates a new Public Variable
 func (builder *builder) PublicVariable(f schema.LeafInfo) frontend.Variable {
-	idx := builder.cs.AddPublicVariable(f.FullName())
+	idx := builder.cs.AddPublicVariable(builder.wireName(schema.Public, f.FullName()))
 	return expr.NewTerm(idx, builder.tOne)
 }
 
 // SecretVariable creates a new Secret Variable
 func (builder *builder) SecretVariable(f schema.LeafInfo) frontend.Variable {
-	idx := builder.cs.AddSecretVariable(f.FullName())
+	idx := builder.cs.AddSecretVariable(builder.wireName(schema.Secret, f.FullName()))
 	return expr.NewTerm(idx, builder.tOne)
 }
 
+// wireName returns the name of the input wire to store in the constraint
+// system and records its full name in the sidecar, if any.
+func (builder *builder) wireName(visibility schema.Visibility, name string) string {
+	if sidecar := builder.config.WireNames; sidecar != nil {
+		if visibility == schema.Public {
+			sidecar.Public = append(sidecar.Public, name)
+		} else {
+			sidecar.Secret = append(sidecar.Secret, name)
+		}
+	}
+	return builder.config.WireNaming.Name(name)
+}
+
 // reduces redundancy in linear expression
 // It factorizes Variable that appears multiple times with != coeff Ids
 // To ensure the determinism in the compile process, Variables are stored as public∥secret∥internal∥unset
@@ -289,6 +317,8 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		Int("nbConstraints", builder.cs.GetNbConstraints()).
 		Msg("building constraint builder")
 
+	builder.padLookupTables()
+
 	// ensure all inputs and hints are constrained
 	err := builder.cs.CheckUnconstrainedWires()
 	if err != nil {
@@ -298,6 +328,13 @@ func (builder *builder) Compile() (constraint.ConstraintSystem, error) {
 		}
 	}
 
+	if builder.config.LevelBlockSize > 0 {
+		// the curve-typed systems embed *constraint.System
+		if s, ok := builder.cs.(interface{ ReorderLevels(int) }); ok {
+			s.ReorderLevels(builder.config.LevelBlockSize)
+		}
+	}
+
 	return builder.cs, nil
 }
 
@@ -632,6 +669,12 @@ func (builder *builder) splitProd(acc expr.Term, r expr.LinearExpression) expr.T
 	return builder.splitProd(o, r[1:])
 }
 
+// withDebugInfo returns true if the debug information of the assertions must be
+// recorded, see [frontend.WithDebugInfo].
+func (builder *builder) withDebugInfo() bool {
+	return debug.Debug || builder.config.DebugInfo
+}
+
 // newDebugInfo this is temporary to restore debug logs
 // something more like builder.sprintf("my message %le %lv", l0, l1)
 // to build logs for both debug and println
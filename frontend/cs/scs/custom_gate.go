@@ -0,0 +1,126 @@
+/*
+Copyright © 2023 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/internal/expr"
+)
+
+// customGate is a custom gate defined by the user.
+type customGate struct {
+	monomials []frontend.GateMonomial
+	gate      constraint.CustomGate
+	blueprint constraint.BlueprintID
+}
+
+// DefineCustomGate implements [frontend.CustomGater].
+func (builder *builder) DefineCustomGate(name string, monomials ...frontend.GateMonomial) (int, error) {
+	g := constraint.CustomGate{Name: name, Terms: make([]constraint.CustomGateTerm, len(monomials))}
+	for i, m := range monomials {
+		if m.Coeff == nil {
+			return 0, errors.New("custom gate monomial without coefficient")
+		}
+		if m.A < 0 || m.B < 0 || m.A > math.MaxUint8 || m.B > math.MaxUint8 {
+			return 0, fmt.Errorf("custom gate %q: invalid exponents (%d, %d)", name, m.A, m.B)
+		}
+		g.Terms[i] = constraint.CustomGateTerm{
+			Coeff: builder.cs.AddCoeff(builder.cs.FromInterface(m.Coeff)),
+			A:     uint8(m.A),
+			B:     uint8(m.B),
+		}
+	}
+	if err := g.Check(); err != nil {
+		return 0, err
+	}
+
+	if id, ok := builder.mCustomGateID[name]; ok {
+		if !reflect.DeepEqual(builder.customGates[id].gate, g) {
+			return 0, fmt.Errorf("custom gate %q already defined with another polynomial", name)
+		}
+		return id, nil
+	}
+
+	idx := builder.cs.AddCustomGate(g)
+	bID := builder.cs.AddBlueprint(&constraint.BlueprintCustomGate{Gate: idx, Definition: g})
+	builder.customGates = append(builder.customGates, customGate{
+		monomials: monomials,
+		gate:      g,
+		blueprint: bID,
+	})
+	id := len(builder.customGates) - 1
+	builder.mCustomGateID[name] = id
+	return id, nil
+}
+
+// CustomGate implements [frontend.CustomGater].
+func (builder *builder) CustomGate(gate int, a, b frontend.Variable) frontend.Variable {
+	if gate < 0 || gate >= len(builder.customGates) {
+		panic("undefined custom gate")
+	}
+	g := &builder.customGates[gate]
+
+	_, aConstant := builder.constantValue(a)
+	_, bConstant := builder.constantValue(b)
+	if aConstant || bConstant {
+		// the polynomial degenerates, we evaluate it with the API.
+		var res frontend.Variable = 0
+		for _, m := range g.monomials {
+			t := frontend.Variable(m.Coeff)
+			for i := 0; i < m.A; i++ {
+				t = builder.Mul(t, a)
+			}
+			for i := 0; i < m.B; i++ {
+				t = builder.Mul(t, b)
+			}
+			res = builder.Add(res, t)
+		}
+		return res
+	}
+
+	xa := builder.wireOf(a.(expr.Term))
+	xb := builder.wireOf(b.(expr.Term))
+	res := builder.newInternalVariable()
+	builder.cs.AddSparseR1C(constraint.SparseR1C{
+		XA: uint32(xa),
+		XB: uint32(xb),
+		XC: uint32(res.VID),
+	}, g.blueprint)
+	return res
+}
+
+// wireOf returns the wire whose value is the term t, that is the wire of t if
+// its coefficient is one, and otherwise a new wire constrained to c⋅x.
+func (builder *builder) wireOf(t expr.Term) int {
+	if builder.cs.IsOne(t.Coeff) {
+		return t.VID
+	}
+	res := builder.newInternalVariable()
+	builder.addPlonkConstraint(sparseR1C{
+		xa: t.VID,
+		xc: res.VID,
+		qL: t.Coeff,
+		qO: builder.tMinusOne,
+	})
+	return res.VID
+}
@@ -0,0 +1,227 @@
+/*
+Copyright © 2023 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/internal/expr"
+)
+
+// lookupTable is a lookup table defined by the user.
+type lookupTable struct {
+	table     constraint.LookupTable
+	blueprint constraint.BlueprintID
+}
+
+// DefineLookupTable implements [frontend.TableLookuper].
+func (builder *builder) DefineLookupTable(name string, columns ...[]*big.Int) (int, error) {
+	t := constraint.LookupTable{Name: name, Columns: make([][]uint32, len(columns))}
+	for i := range columns {
+		t.Columns[i] = make([]uint32, len(columns[i]))
+		for j, v := range columns[i] {
+			if v == nil {
+				return 0, errors.New("lookup table with nil entry")
+			}
+			t.Columns[i][j] = builder.cs.AddCoeff(builder.cs.FromInterface(v))
+		}
+	}
+	if err := t.Check(); err != nil {
+		return 0, err
+	}
+
+	if id, ok := builder.mLookupTableID[name]; ok {
+		if !reflect.DeepEqual(builder.lookupTables[id].table, t) {
+			return 0, fmt.Errorf("lookup table %q already defined with other columns", name)
+		}
+		return id, nil
+	}
+
+	idx := builder.cs.AddLookupTable(t)
+	bID := builder.cs.AddBlueprint(&constraint.BlueprintLookup{Table: idx, Definition: t})
+	builder.lookupTables = append(builder.lookupTables, lookupTable{
+		table:     t,
+		blueprint: bID,
+	})
+	id := len(builder.lookupTables) - 1
+	builder.mLookupTableID[name] = id
+	return id, nil
+}
+
+// AssertIsInTable implements [frontend.TableLookuper].
+func (builder *builder) AssertIsInTable(table int, vs ...frontend.Variable) {
+	t := builder.lookupTable(table)
+	if len(vs) > len(t.table.Columns) {
+		panic(fmt.Sprintf("assertIsInTable: %d values for a table of %d columns", len(vs), len(t.table.Columns)))
+	}
+
+	if row, ok := builder.constantRow(vs); ok {
+		if builder.findRow(t, row, -1) == -1 {
+			panic(fmt.Sprintf("assertIsInTable failed: constant row not in table %q", t.table.Name))
+		}
+		return
+	}
+
+	var wires [constraint.MaxLookupTableColumns]uint32
+	for i := range wires {
+		if i < len(vs) {
+			wires[i] = builder.lookupWire(vs[i])
+		} else {
+			wires[i] = builder.zeroWire()
+		}
+	}
+	builder.cs.AddSparseR1C(constraint.SparseR1C{
+		XA: wires[0],
+		XB: wires[1],
+		XC: wires[2],
+	}, t.blueprint)
+}
+
+// Lookup implements [frontend.TableLookuper].
+func (builder *builder) Lookup(table int, keys ...frontend.Variable) frontend.Variable {
+	t := builder.lookupTable(table)
+	nbColumns := len(t.table.Columns)
+	if len(keys) != nbColumns-1 {
+		panic(fmt.Sprintf("lookup: %d keys for a table of %d columns", len(keys), nbColumns))
+	}
+
+	if row, ok := builder.constantRow(keys); ok {
+		i := builder.findRow(t, row, nbColumns-1)
+		if i == -1 {
+			panic(fmt.Sprintf("lookup failed: constant keys not in table %q", t.table.Name))
+		}
+		return builder.cs.ToBigInt(builder.cs.GetCoefficient(int(t.table.Columns[nbColumns-1][i])))
+	}
+
+	res := builder.newInternalVariable()
+	var wires [constraint.MaxLookupTableColumns]uint32
+	for i := range wires {
+		switch {
+		case i < len(keys):
+			wires[i] = builder.lookupWire(keys[i])
+		case i == len(keys):
+			wires[i] = uint32(res.VID)
+		default:
+			wires[i] = builder.zeroWire()
+		}
+	}
+	builder.cs.AddSparseR1C(constraint.SparseR1C{
+		XA: wires[0],
+		XB: wires[1],
+		XC: wires[2],
+	}, t.blueprint)
+	return res
+}
+
+func (builder *builder) lookupTable(table int) *lookupTable {
+	if table < 0 || table >= len(builder.lookupTables) {
+		panic("undefined lookup table")
+	}
+	return &builder.lookupTables[table]
+}
+
+// constantRow returns the values of vs if they are all constants.
+func (builder *builder) constantRow(vs []frontend.Variable) ([]constraint.Element, bool) {
+	row := make([]constraint.Element, len(vs))
+	for i := range vs {
+		c, ok := builder.constantValue(vs[i])
+		if !ok {
+			return nil, false
+		}
+		row[i] = c
+	}
+	return row, true
+}
+
+// findRow returns the index of the first row of the table matching the values
+// of row, ignoring the column skip, or -1 if there is none. Missing values of
+// row are zero.
+func (builder *builder) findRow(t *lookupTable, row []constraint.Element, skip int) int {
+	for i := 0; i < t.table.NbRows(); i++ {
+		match := true
+		for j := range t.table.Columns {
+			if j == skip {
+				continue
+			}
+			var v constraint.Element
+			if j < len(row) {
+				v = row[j]
+			}
+			if builder.cs.GetCoefficient(int(t.table.Columns[j][i])) != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookupWire returns a wire whose value is v.
+func (builder *builder) lookupWire(v frontend.Variable) uint32 {
+	if c, ok := builder.constantValue(v); ok {
+		res := builder.newInternalVariable()
+		builder.addPlonkConstraint(sparseR1C{
+			xa: res.VID,
+			qL: builder.tMinusOne,
+			qC: c,
+		})
+		return uint32(res.VID)
+	}
+	return uint32(builder.wireOf(v.(expr.Term)))
+}
+
+// zeroWire returns a wire constrained to be zero, used for the columns of the
+// lookups which are not set.
+func (builder *builder) zeroWire() uint32 {
+	if builder.lookupZero == nil {
+		res := builder.newInternalVariable()
+		builder.addPlonkConstraint(sparseR1C{
+			xa: res.VID,
+			qL: builder.tOne,
+		})
+		builder.lookupZero = &res
+	}
+	return uint32(builder.lookupZero.VID)
+}
+
+// padLookupTables adds empty constraints so that the PLONK domain is large
+// enough to hold the rows of all the lookup tables.
+func (builder *builder) padLookupTables() {
+	nbRows := 0
+	for i := range builder.lookupTables {
+		nbRows += builder.lookupTables[i].table.NbRows()
+	}
+	if nbRows == 0 {
+		return
+	}
+	zero := builder.zeroWire()
+	for builder.cs.GetNbConstraints()+builder.cs.GetNbPublicVariables() < nbRows {
+		builder.cs.AddSparseR1C(constraint.SparseR1C{
+			XA: zero,
+			XB: zero,
+			XC: zero,
+		}, builder.genericGate)
+	}
+}
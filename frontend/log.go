@@ -0,0 +1,65 @@
+/*
+Copyright © 2023 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import "github.com/consensys/gnark/constraint"
+
+// LogValue wraps a value given to [API.Println] with formatting metadata. V
+// may be a single variable, or a slice or a struct of variables, in which case
+// the format applies to all of them.
+type LogValue struct {
+	V      Variable
+	Format constraint.LogFormat
+}
+
+// Hex returns v formatted as a hexadecimal integer when printed with [API.Println].
+func Hex(v Variable) LogValue {
+	return withVerb(v, 'x')
+}
+
+// Dec returns v formatted as a decimal integer in [0, q) when printed with
+// [API.Println]. By default, values close to the modulus are printed as
+// negative integers.
+func Dec(v Variable) LogValue {
+	return withVerb(v, 'd')
+}
+
+// Bin returns v formatted as a binary integer when printed with [API.Println].
+func Bin(v Variable) LogValue {
+	return withVerb(v, 'b')
+}
+
+// Truncate returns v truncated to its nbBits least significant bits when
+// printed with [API.Println]. It can be combined with [Hex], [Dec] and [Bin].
+func Truncate(v Variable, nbBits int) LogValue {
+	lv := toLogValue(v)
+	lv.Format.NbBits = nbBits
+	return lv
+}
+
+func withVerb(v Variable, verb byte) LogValue {
+	lv := toLogValue(v)
+	lv.Format.Verb = verb
+	return lv
+}
+
+func toLogValue(v Variable) LogValue {
+	if lv, ok := v.(LogValue); ok {
+		return lv
+	}
+	return LogValue{V: v}
+}
@@ -0,0 +1,85 @@
+package schema
+
+import (
+	"reflect"
+)
+
+// Leaves returns the full names of the public and of the secret leaves of the
+// schema, in the order of the witness vector.
+func (s Schema) Leaves() (public, secret []string, err error) {
+	// the walker only stops on interface or pointer leaves
+	tLeaf := reflect.TypeOf((*int)(nil))
+	instance := s.Instantiate(tLeaf, false)
+	_, err = Walk(instance, tLeaf, func(f LeafInfo, _ reflect.Value) error {
+		if f.Visibility == Public {
+			public = append(public, f.FullName())
+		} else if f.Visibility == Secret {
+			secret = append(secret, f.FullName())
+		}
+		return nil
+	})
+	return public, secret, err
+}
+
+// Diff describes the differences between the leaves of two versions of a
+// schema. The leaves are identified by their full names.
+type Diff struct {
+	// Added are the leaves of the new schema which are not in the old one.
+	Added []string
+	// Removed are the leaves of the old schema which are not in the new one.
+	Removed []string
+	// Moved are the leaves in both schemas whose visibility changed.
+	Moved []string
+}
+
+// Equal returns true if the two schemas have the same leaves with the same
+// visibilities, in which case a witness of one is a witness of the other up
+// to the order of the leaves.
+func (d *Diff) Equal() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+// Compare returns the differences between the old schema from and the new
+// schema to.
+func Compare(from, to *Schema) (*Diff, error) {
+	fromPublic, fromSecret, err := from.Leaves()
+	if err != nil {
+		return nil, err
+	}
+	toPublic, toSecret, err := to.Leaves()
+	if err != nil {
+		return nil, err
+	}
+	fromVisibilities := visibilities(fromPublic, fromSecret)
+	toVisibilities := visibilities(toPublic, toSecret)
+
+	var d Diff
+	for _, names := range [][]string{toPublic, toSecret} {
+		for _, n := range names {
+			if v, ok := fromVisibilities[n]; !ok {
+				d.Added = append(d.Added, n)
+			} else if v != toVisibilities[n] {
+				d.Moved = append(d.Moved, n)
+			}
+		}
+	}
+	for _, names := range [][]string{fromPublic, fromSecret} {
+		for _, n := range names {
+			if _, ok := toVisibilities[n]; !ok {
+				d.Removed = append(d.Removed, n)
+			}
+		}
+	}
+	return &d, nil
+}
+
+func visibilities(public, secret []string) map[string]Visibility {
+	res := make(map[string]Visibility, len(public)+len(secret))
+	for _, n := range public {
+		res[n] = Public
+	}
+	for _, n := range secret {
+		res[n] = Secret
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	assert := require.New(t)
+
+	type v1 struct {
+		X variable `gnark:",public"`
+		Y variable
+		A [2]variable
+	}
+	type v2 struct {
+		X variable
+		Y variable
+		A [3]variable
+		B variable `gnark:",public"`
+	}
+	tVariable := reflect.ValueOf(struct{ A variable }{}).FieldByName("A").Type()
+	from, err := New(&v1{}, tVariable)
+	assert.NoError(err)
+	to, err := New(&v2{}, tVariable)
+	assert.NoError(err)
+
+	public, secret, err := from.Leaves()
+	assert.NoError(err)
+	assert.Equal([]string{"X"}, public)
+	assert.Equal([]string{"Y", "A_0", "A_1"}, secret)
+
+	d, err := Compare(from, to)
+	assert.NoError(err)
+	assert.False(d.Equal())
+	assert.Equal([]string{"B", "A_2"}, d.Added)
+	assert.Empty(d.Removed)
+	assert.Equal([]string{"X"}, d.Moved)
+
+	d, err = Compare(to, from)
+	assert.NoError(err)
+	assert.Empty(d.Added)
+	assert.Equal([]string{"B", "A_2"}, d.Removed)
+
+	d, err = Compare(from, from)
+	assert.NoError(err)
+	assert.True(d.Equal())
+}
@@ -78,20 +78,8 @@ func (s Schema) Instantiate(leafType reflect.Type, omitEmptyTag ...bool) interfa
 //
 // The expected sequence matches the binary encoding protocol [public | secret]
 func (s Schema) WriteSequence(w io.Writer) error {
-	var public, secret []string
-
-	var a int
-	instance := s.Instantiate(reflect.TypeOf(a), false)
-
-	collectHandler := func(f LeafInfo, _ reflect.Value) error {
-		if f.Visibility == Public {
-			public = append(public, f.FullName())
-		} else if f.Visibility == Secret {
-			secret = append(secret, f.FullName())
-		}
-		return nil
-	}
-	if _, err := Walk(instance, reflect.TypeOf(a), collectHandler); err != nil {
+	public, secret, err := s.Leaves()
+	if err != nil {
 		return err
 	}
 
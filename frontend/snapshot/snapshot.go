@@ -0,0 +1,151 @@
+// Package snapshot allows gadget authors to compare alternative implementations
+// of a sub-gadget in the context of a full circuit.
+//
+// The circuit calls [Fork] in its Define method at the point where the
+// alternatives diverge. [Compare] then compiles the circuit once per
+// alternative: the part of Define before Fork is replayed, which restores the
+// builder to the state of the snapshot, the alternative is built, and Define
+// continues as usual. As compilation is deterministic, any difference between
+// the resulting constraint systems is due to the alternative.
+//
+// When the circuit is compiled directly with [frontend.Compile] or run in the
+// test engine, Fork builds the first alternative.
+package snapshot
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+// Alternative is an implementation of a sub-gadget. Define is called by
+// [Fork] with the API of the circuit. Outputs of the sub-gadget used by the
+// rest of the circuit should be captured by the closure.
+type Alternative struct {
+	Name   string
+	Define func(api frontend.API) error
+}
+
+// Result describes the constraint system compiled with an alternative.
+type Result struct {
+	Name                string
+	NbConstraints       int
+	NbInternalVariables int
+	// Digest is the SHA-256 hash of the serialized constraint system. Two
+	// alternatives with the same digest compile to the same constraints.
+	Digest [sha256.Size]byte
+}
+
+// Comparison is the list of results returned by [Compare], in the order of
+// the alternatives given to [Fork].
+type Comparison []Result
+
+type forkKey struct{}
+
+// forkState is the state shared between Compare and Fork through the key-value
+// store of the builder.
+type forkState struct {
+	index int      // index of the alternative to build
+	names []string // names of the alternatives, set by Fork
+}
+
+// Fork marks the snapshot point in the Define method of the circuit and
+// builds one of the alternatives, as selected by [Compare]. It builds the first
+// alternative when the circuit is not compiled by Compare. Fork can be called
+// at most once per Define.
+func Fork(api frontend.API, alternatives ...Alternative) error {
+	if len(alternatives) == 0 {
+		return errors.New("no alternative to fork")
+	}
+	index := 0
+	if kv, ok := api.Compiler().(kvstore.Store); ok {
+		if state, ok := kv.GetKeyValue(forkKey{}).(*forkState); ok {
+			if state.names != nil {
+				return errors.New("fork called more than once")
+			}
+			state.names = make([]string, len(alternatives))
+			for i := range alternatives {
+				state.names[i] = alternatives[i].Name
+			}
+			if state.index >= len(alternatives) {
+				return fmt.Errorf("alternative %d requested, only %d defined", state.index, len(alternatives))
+			}
+			index = state.index
+		}
+	}
+	if err := alternatives[index].Define(api); err != nil {
+		return fmt.Errorf("alternative %q: %w", alternatives[index].Name, err)
+	}
+	return nil
+}
+
+// Compare compiles the circuit once for each alternative given to [Fork] in
+// its Define method, and returns the statistics of the compiled constraint
+// systems. The options are passed to [frontend.Compile].
+func Compare(field *big.Int, newBuilder frontend.NewBuilder, circuit frontend.Circuit, opts ...frontend.CompileOption) (Comparison, error) {
+	var res Comparison
+	var names []string
+	for i := 0; i == 0 || i < len(names); i++ {
+		state := &forkState{index: i}
+		ccs, err := frontend.Compile(field, withForkState(newBuilder, state), circuit, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("compile alternative %d: %w", i, err)
+		}
+		if state.names == nil {
+			return nil, errors.New("circuit doesn't call snapshot.Fork")
+		}
+		if i == 0 {
+			names = state.names
+		} else if len(state.names) != len(names) {
+			return nil, fmt.Errorf("non deterministic circuit: %d alternatives, previously %d", len(state.names), len(names))
+		}
+
+		h := sha256.New()
+		if _, err := ccs.WriteTo(h); err != nil {
+			return nil, fmt.Errorf("serialize alternative %d: %w", i, err)
+		}
+		r := Result{
+			Name:                names[i],
+			NbConstraints:       ccs.GetNbConstraints(),
+			NbInternalVariables: ccs.GetNbInternalVariables(),
+		}
+		copy(r.Digest[:], h.Sum(nil))
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// withForkState returns a NewBuilder which stores state in the builder.
+func withForkState(newBuilder frontend.NewBuilder, state *forkState) frontend.NewBuilder {
+	return func(field *big.Int, config frontend.CompileConfig) (frontend.Builder, error) {
+		builder, err := newBuilder(field, config)
+		if err != nil {
+			return nil, err
+		}
+		kv, ok := builder.(kvstore.Store)
+		if !ok {
+			return nil, errors.New("builder does not implement kvstore.Store")
+		}
+		kv.SetKeyValue(forkKey{}, state)
+		return builder, nil
+	}
+}
+
+// String returns a table of the results. The differences are relative to the
+// first alternative.
+func (c Comparison) String() string {
+	var sbb strings.Builder
+	sbb.WriteString("alternative\tconstraints\tinternal variables\tdigest\n")
+	for _, r := range c {
+		fmt.Fprintf(&sbb, "%s\t%d (%+d)\t%d (%+d)\t%x\n", r.Name,
+			r.NbConstraints, r.NbConstraints-c[0].NbConstraints,
+			r.NbInternalVariables, r.NbInternalVariables-c[0].NbInternalVariables,
+			r.Digest[:8])
+	}
+	return sbb.String()
+}
@@ -0,0 +1,84 @@
+package snapshot_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/snapshot"
+	"github.com/consensys/gnark/test"
+)
+
+// pow8Circuit checks that Y = X^8, computing the power with two alternatives.
+type pow8Circuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *pow8Circuit) Define(api frontend.API) error {
+	var res frontend.Variable
+	err := snapshot.Fork(api,
+		snapshot.Alternative{Name: "naive", Define: func(api frontend.API) error {
+			res = c.X
+			for i := 0; i < 7; i++ {
+				res = api.Mul(res, c.X)
+			}
+			return nil
+		}},
+		snapshot.Alternative{Name: "square", Define: func(api frontend.API) error {
+			res = c.X
+			for i := 0; i < 3; i++ {
+				res = api.Mul(res, res)
+			}
+			return nil
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res, c.Y)
+	return nil
+}
+
+func Example() {
+	cmp, err := snapshot.Compare(ecc.BN254.ScalarField(), r1cs.NewBuilder, &pow8Circuit{})
+	if err != nil {
+		panic(err)
+	}
+	for _, r := range cmp {
+		fmt.Println(r.Name, r.NbConstraints)
+	}
+	// Output:
+	// naive 8
+	// square 4
+}
+
+type noForkCircuit struct {
+	X frontend.Variable
+}
+
+func (c *noForkCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.X, 1)
+	return nil
+}
+
+func TestCompare(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	cmp, err := snapshot.Compare(ecc.BN254.ScalarField(), r1cs.NewBuilder, &pow8Circuit{})
+	assert.NoError(err)
+	assert.Equal(2, len(cmp))
+	assert.NotEqual(cmp[0].Digest, cmp[1].Digest)
+
+	// the digest is the same for identical constraint systems
+	again, err := snapshot.Compare(ecc.BN254.ScalarField(), r1cs.NewBuilder, &pow8Circuit{})
+	assert.NoError(err)
+	assert.Equal(cmp, again)
+
+	_, err = snapshot.Compare(ecc.BN254.ScalarField(), r1cs.NewBuilder, &noForkCircuit{})
+	assert.Error(err)
+
+	// outside of Compare, the first alternative is built
+	assert.CheckCircuit(&pow8Circuit{}, test.WithValidAssignment(&pow8Circuit{X: 2, Y: 256}), test.WithCurves(ecc.BN254))
+}
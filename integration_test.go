@@ -18,10 +18,15 @@ package gnark_test
 
 import (
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/internal/backend/circuits"
 	"github.com/consensys/gnark/test"
 )
@@ -74,7 +79,42 @@ func TestIntegrationAPI(t *testing.T) {
 			}
 
 			assert.CheckCircuit(tData.Circuit, opts...)
+
+			if tData.ExpectedFailures != nil {
+				checkExpectedFailures(assert, tData)
+			}
 		}, name)
 	}
 
 }
+
+// checkExpectedFailures checks that the bad witnesses fail where they declare
+// to, with the test engine and with the R1CS and SCS solvers. The systems are
+// compiled with their debug information so that the solver errors hold the
+// location of the unsatisfied constraint.
+func checkExpectedFailures(assert *test.Assert, tData circuits.TestCircuit) {
+	field := ecc.BN254.ScalarField()
+	ccsR1CS, err := frontend.Compile(field, r1cs.NewBuilder, tData.Circuit, frontend.WithDebugInfo())
+	assert.NoError(err)
+	ccsSCS, err := frontend.Compile(field, scs.NewBuilder, tData.Circuit, frontend.WithDebugInfo())
+	assert.NoError(err)
+
+	for i, location := range tData.ExpectedFailures {
+		if location == "" {
+			continue
+		}
+		check := func(backend string, err error) {
+			if err == nil {
+				assert.Fail("solving succeeded", "bad witness %d: %s: expected failure at %q", i, backend, location)
+			} else if !strings.Contains(err.Error(), location) {
+				assert.Fail("unexpected failure", "bad witness %d: %s: expected failure at %q, got %v", i, backend, location, err)
+			}
+		}
+		check("test engine", test.IsSolved(tData.Circuit, tData.InvalidAssignments[i], field))
+
+		w, err := frontend.NewWitness(tData.InvalidAssignments[i], field)
+		assert.NoError(err)
+		check("r1cs", ccsR1CS.IsSolved(w, solver.WithHints(tData.HintFunctions...)))
+		check("scs", ccsSCS.IsSolved(w, solver.WithHints(tData.HintFunctions...)))
+	}
+}
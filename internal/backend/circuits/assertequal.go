@@ -24,5 +24,5 @@ func init() {
 	bad.X = (5)
 	bad.Y = (2)
 
-	addEntry("assert_equal", &circuit, &good, &bad, nil)
+	addEntry("assert_equal", &circuit, &good, expectFailure(&bad, "[assertIsEqual]"), nil)
 }
@@ -55,36 +55,36 @@ func init() {
 	}
 
 	bad := []frontend.Circuit{
-		&checkAssertIsBooleanCircuit{
+		expectFailure(&checkAssertIsBooleanCircuit{
 			A: (1),
 			B: (1),
 			C: (0),
 			D: (0),
-		},
-		&checkAssertIsBooleanCircuit{
+		}, "[assertIsBoolean]"),
+		expectFailure(&checkAssertIsBooleanCircuit{
 			A: (0),
 			B: (1),
 			C: (0),
 			D: (0),
-		},
-		&checkAssertIsBooleanCircuit{
+		}, "[assertIsBoolean]"),
+		expectFailure(&checkAssertIsBooleanCircuit{
 			A: (0),
 			B: (0),
 			C: (3),
 			D: (0),
-		},
-		&checkAssertIsBooleanCircuit{
+		}, "[assertIsBoolean]"),
+		expectFailure(&checkAssertIsBooleanCircuit{
 			A: (1),
 			B: (0),
 			C: (3),
 			D: (0),
-		},
-		&checkAssertIsBooleanCircuit{
+		}, "[assertIsBoolean]"),
+		expectFailure(&checkAssertIsBooleanCircuit{
 			A: (1),
 			B: (0),
 			C: (0),
 			D: (1),
-		},
+		}, "[assertIsBoolean]"),
 	}
 
 	addNewEntry("assert_boolean", &checkAssertIsBooleanCircuit{}, good, bad, nil)
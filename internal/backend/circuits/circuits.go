@@ -13,21 +13,19 @@ type TestCircuit struct {
 	ValidAssignments, InvalidAssignments []frontend.Circuit // good and bad witness for the prover + public verifier data
 	HintFunctions                        []solver.Hint
 	Curves                               []ecc.ID
+
+	// ExpectedFailures[i] is the location where solving InvalidAssignments[i]
+	// is expected to fail, or empty if not declared. It is a substring of the
+	// solver error, typically the debug tag of the assertion (e.g.
+	// "[assertIsEqual]") or the name of the function which defines it.
+	ExpectedFailures []string
 }
 
 // Circuits are used for test purposes (backend.Groth16 and gnark/integration_test.go)
 var Circuits map[string]TestCircuit
 
 func addEntry(name string, circuit, proverGood, proverBad frontend.Circuit, curves []ecc.ID) {
-
-	if Circuits == nil {
-		Circuits = make(map[string]TestCircuit)
-	}
-	if _, ok := Circuits[name]; ok {
-		panic("name " + name + "already taken by another test circuit ")
-	}
-
-	Circuits[name] = TestCircuit{circuit, []frontend.Circuit{proverGood}, []frontend.Circuit{proverBad}, nil, curves}
+	addNewEntry(name, circuit, []frontend.Circuit{proverGood}, []frontend.Circuit{proverBad}, curves)
 }
 
 func addNewEntry(name string, circuit frontend.Circuit, proverGood, proverBad []frontend.Circuit, curves []ecc.ID, hintFunctions ...solver.Hint) {
@@ -39,5 +37,31 @@ func addNewEntry(name string, circuit frontend.Circuit, proverGood, proverBad []
 	}
 	solver.RegisterHint(hintFunctions...)
 
-	Circuits[name] = TestCircuit{circuit, proverGood, proverBad, hintFunctions, curves}
+	// unwrap the bad witnesses declaring where they fail
+	bad := make([]frontend.Circuit, len(proverBad))
+	var expectedFailures []string
+	for i := range proverBad {
+		bad[i] = proverBad[i]
+		if f, ok := proverBad[i].(failure); ok {
+			if expectedFailures == nil {
+				expectedFailures = make([]string, len(proverBad))
+			}
+			bad[i], expectedFailures[i] = f.Circuit, f.location
+		}
+	}
+
+	Circuits[name] = TestCircuit{circuit, proverGood, bad, hintFunctions, curves, expectedFailures}
+}
+
+// failure is a bad witness with the location where it is expected to fail.
+type failure struct {
+	frontend.Circuit
+	location string
+}
+
+// expectFailure declares that solving the bad witness must fail at location,
+// see [TestCircuit.ExpectedFailures]. The result is to be passed to addEntry
+// or addNewEntry as a bad witness.
+func expectFailure(proverBad frontend.Circuit, location string) frontend.Circuit {
+	return failure{proverBad, location}
 }
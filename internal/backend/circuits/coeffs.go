@@ -0,0 +1,47 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// coeffsCircuit uses coefficients which the solver multiplies without a
+// generic field multiplication, and a few generic ones.
+type coeffsCircuit struct {
+	A, B frontend.Variable
+	Bit  frontend.Variable
+	C    frontend.Variable `gnark:",public"`
+}
+
+func (circuit *coeffsCircuit) Define(api frontend.API) error {
+	coeffs := []int{3, -3, 4, -4, 5, -5, 6, -6, 10, -10, 7, -12, 13}
+	terms := make([]frontend.Variable, len(coeffs))
+	for i, c := range coeffs {
+		terms[i] = api.Mul(circuit.A, c)
+	}
+	sum := api.Add(terms[0], terms[1], terms[2:]...)
+	api.AssertIsEqual(api.Mul(sum, circuit.B), circuit.C)
+
+	// powers of two multiplying a boolean and a non-boolean wire
+	api.AssertIsBoolean(circuit.Bit)
+	pow2 := []int{-2, 8, -16, 1 << 20}
+	for i, c := range pow2 {
+		terms[i] = api.Add(api.Mul(circuit.Bit, c), api.Mul(circuit.A, c))
+	}
+	sum = api.Add(terms[0], terms[1], terms[2:len(pow2)]...)
+	api.AssertIsEqual(sum, api.Mul(api.Add(circuit.Bit, circuit.A), 1<<20-10))
+	return nil
+}
+
+func init() {
+	// 3 - 3 + 4 - 4 + 5 - 5 + 6 - 6 + 10 - 10 + 7 - 12 + 13 = 8
+	good := []frontend.Circuit{
+		&coeffsCircuit{A: 2, B: 3, Bit: 1, C: 48},
+		&coeffsCircuit{A: -1, B: 5, Bit: 0, C: -40},
+	}
+	bad := []frontend.Circuit{
+		&coeffsCircuit{A: 2, B: 3, Bit: 1, C: 47},
+		&coeffsCircuit{A: -1, B: 5, Bit: 0, C: 40},
+		&coeffsCircuit{A: 2, B: 3, Bit: 2, C: 48},
+	}
+	addNewEntry("coeffs", &coeffsCircuit{}, good, bad, nil)
+}
@@ -45,3 +45,53 @@ func init() {
 
 	addEntry("div", &divCircuit{}, &good, &bad, []ecc.ID{ecc.BN254})
 }
+
+type divCheckedCircuit struct {
+	A, B frontend.Variable
+	C    frontend.Variable `gnark:",public"`
+}
+
+func (circuit *divCheckedCircuit) Define(api frontend.API) error {
+	c := api.Div(circuit.A, circuit.B)
+	api.AssertIsEqual(c, circuit.C)
+	return nil
+}
+
+type divUncheckedZeroCircuit struct {
+	A, B frontend.Variable
+	C    frontend.Variable `gnark:",public"`
+}
+
+func (circuit *divUncheckedZeroCircuit) Define(api frontend.API) error {
+	c := api.DivUnchecked(circuit.A, circuit.B)
+	d := api.DivUnchecked(0, circuit.B)
+	api.AssertIsEqual(c, circuit.C)
+	api.AssertIsEqual(d, 0)
+	return nil
+}
+
+func init() {
+	// Div constrains the denominator to be non-zero, so 0/0 is not satisfiable.
+	good := []frontend.Circuit{
+		&divCheckedCircuit{A: 6, B: 3, C: 2},
+		&divCheckedCircuit{A: 0, B: 3, C: 0},
+	}
+	bad := []frontend.Circuit{
+		&divCheckedCircuit{A: 6, B: 3, C: 3},
+		&divCheckedCircuit{A: 0, B: 0, C: 0},
+		&divCheckedCircuit{A: 1, B: 0, C: 0},
+	}
+	addNewEntry("div_checked", &divCheckedCircuit{}, good, bad, nil)
+
+	// DivUnchecked does not constrain the denominator to be non-zero, so 0/0 is
+	// satisfiable.
+	good = []frontend.Circuit{
+		&divUncheckedZeroCircuit{A: 6, B: 3, C: 2},
+		&divUncheckedZeroCircuit{A: 0, B: 0, C: 0},
+	}
+	bad = []frontend.Circuit{
+		&divUncheckedZeroCircuit{A: 6, B: 3, C: 3},
+		&divUncheckedZeroCircuit{A: 1, B: 0, C: 0},
+	}
+	addNewEntry("div_unchecked_zero", &divUncheckedZeroCircuit{}, good, bad, nil)
+}
@@ -0,0 +1,113 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+type assertIsInSetCircuit struct {
+	A, B, C, D, E frontend.Variable
+}
+
+func (circuit *assertIsInSetCircuit) Define(api frontend.API) error {
+
+	// simple variable
+	assertIsInSet(api, circuit.A, 1, 5, 42)
+
+	// two elements, with a duplicate
+	assertIsInSet(api, circuit.B, 7, 9, 7)
+
+	// boolean linear expression
+	assertIsInBooleanSet(api, api.Sub(circuit.C, circuit.D))
+
+	// single element
+	assertIsInSet(api, circuit.D, -1)
+
+	// mul by constant
+	assertIsInSet(api, api.Mul(circuit.E, 3), 3, 6, 9, 12)
+
+	// constant
+	assertIsInSet(api, 2, 1, 2)
+
+	return nil
+}
+
+// assertIsInSet calls the set check of the builder, which all the builders
+// implement.
+func assertIsInSet(api frontend.API, v frontend.Variable, set ...frontend.Variable) {
+	api.(frontend.SetChecker).AssertIsInSet(v, set...)
+}
+
+// assertIsInBooleanSet is a function of its own so that its failure can be
+// told apart, as the builders turn it into a boolean assertion.
+func assertIsInBooleanSet(api frontend.API, v frontend.Variable) {
+	assertIsInSet(api, v, 0, 1)
+}
+
+func init() {
+
+	good := []frontend.Circuit{
+		&assertIsInSetCircuit{
+			A: 1,
+			B: 7,
+			C: -1,
+			D: -1,
+			E: 1,
+		},
+		&assertIsInSetCircuit{
+			A: 42,
+			B: 9,
+			C: 0,
+			D: -1,
+			E: 4,
+		},
+		&assertIsInSetCircuit{
+			A: 5,
+			B: 9,
+			C: 0,
+			D: -1,
+			E: 3,
+		},
+	}
+
+	bad := []frontend.Circuit{
+		expectFailure(&assertIsInSetCircuit{
+			A: 2,
+			B: 7,
+			C: -1,
+			D: -1,
+			E: 1,
+		}, "[assertIsInSet]"),
+		expectFailure(&assertIsInSetCircuit{
+			A: 1,
+			B: 8,
+			C: -1,
+			D: -1,
+			E: 1,
+		}, "[assertIsInSet]"),
+		expectFailure(&assertIsInSetCircuit{
+			A: 1,
+			B: 7,
+			C: 1,
+			D: -1,
+			E: 1,
+		}, "circuits.assertIsInBooleanSet"),
+		// fails both at C - D ∈ {0, 1} and at D ∈ {-1}, in an order which
+		// depends on the solver
+		&assertIsInSetCircuit{
+			A: 1,
+			B: 7,
+			C: 0,
+			D: 1,
+			E: 1,
+		},
+		expectFailure(&assertIsInSetCircuit{
+			A: 1,
+			B: 7,
+			C: -1,
+			D: -1,
+			E: 5,
+		}, "[assertIsInSet]"),
+	}
+
+	addNewEntry("assert_is_in_set", &assertIsInSetCircuit{}, good, bad, nil)
+}
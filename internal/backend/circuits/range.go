@@ -62,7 +62,38 @@ func rangeCheck() {
 	addEntry("range", &circuit, &good, &bad, nil)
 }
 
+type rangeCheckConstantPatternCircuit struct {
+	A, B, C, D frontend.Variable
+}
+
+func (circuit *rangeCheckConstantPatternCircuit) Define(api frontend.API) error {
+	api.AssertIsLessOrEqual(circuit.A, 0)      // zero bound
+	api.AssertIsLessOrEqual(circuit.B, 1)      // single bit
+	api.AssertIsLessOrEqual(circuit.C, 0xffff) // all ones
+	api.AssertIsLessOrEqual(circuit.D, 0xa5a4) // mixed bit pattern
+	return nil
+}
+
+func rangeCheckConstantPattern() {
+	var circuit rangeCheckConstantPatternCircuit
+
+	good := []frontend.Circuit{
+		&rangeCheckConstantPatternCircuit{A: 0, B: 1, C: 0xffff, D: 0xa5a4},
+		&rangeCheckConstantPatternCircuit{A: 0, B: 0, C: 0, D: 0xa5a3},
+	}
+	bad := []frontend.Circuit{
+		&rangeCheckConstantPatternCircuit{A: 1, B: 1, C: 0xffff, D: 0xa5a4},
+		&rangeCheckConstantPatternCircuit{A: 0, B: 2, C: 0xffff, D: 0xa5a4},
+		&rangeCheckConstantPatternCircuit{A: 0, B: 1, C: 0x10000, D: 0xa5a4},
+		&rangeCheckConstantPatternCircuit{A: 0, B: 1, C: 0xffff, D: 0xa5a5},
+		&rangeCheckConstantPatternCircuit{A: 0, B: 1, C: 0xffff, D: -1},
+	}
+
+	addNewEntry("range_constant_pattern", &circuit, good, bad, nil)
+}
+
 func init() {
 	rangeCheckConstant()
+	rangeCheckConstantPattern()
 	rangeCheck()
 }
@@ -68,6 +68,14 @@ func main() {
 		CurveID:   "UNKNOWN",
 		noBackend: true,
 	}
+	goldilocks := templateData{
+		RootPath:    "../../../backend/{?}/goldilocks/",
+		CSPath:      "../../../constraint/goldilocks",
+		Curve:       "goldilocks",
+		CurveID:     "UNKNOWN",
+		FieldImport: "github.com/consensys/gnark-crypto/field/goldilocks",
+		noBackend:   true,
+	}
 
 	// autogenerate tinyfield
 	tinyfieldConf, err := config.NewFieldConfig("tinyfield", "Element", "0x2f", false)
@@ -87,6 +95,7 @@ func main() {
 		bls24_317,
 		bw6_633,
 		tiny_field,
+		goldilocks,
 	}
 
 	const importCurve = "../imports.go.tmpl"
@@ -104,19 +113,14 @@ func main() {
 				groth16Dir         = strings.Replace(d.RootPath, "{?}", "groth16", 1)
 				groth16MpcSetupDir = filepath.Join(groth16Dir, "mpcsetup")
 				plonkDir           = strings.Replace(d.RootPath, "{?}", "plonk", 1)
+				plonkFflonkDir     = filepath.Join(plonkDir, "fflonk")
 				plonkFriDir        = strings.Replace(d.RootPath, "{?}", "plonkfri", 1)
+				marlinDir          = strings.Replace(d.RootPath, "{?}", "marlin", 1)
+				spartanDir         = strings.Replace(d.RootPath, "{?}", "spartan", 1)
+				bulletproofsDir    = strings.Replace(d.RootPath, "{?}", "bulletproofs", 1)
+				fftDir             = strings.Replace(d.RootPath, "{?}", "fft", 1)
 			)
 
-			if err := os.MkdirAll(groth16Dir, 0700); err != nil {
-				panic(err)
-			}
-			if err := os.MkdirAll(plonkDir, 0700); err != nil {
-				panic(err)
-			}
-			if err := os.MkdirAll(plonkFriDir, 0700); err != nil {
-				panic(err)
-			}
-
 			csDir := d.CSPath
 
 			// constraint systems
@@ -125,12 +129,16 @@ func main() {
 				{File: filepath.Join(csDir, "coeff.go"), Templates: []string{"coeff.go.tmpl", importCurve}},
 				{File: filepath.Join(csDir, "solver.go"), Templates: []string{"solver.go.tmpl", importCurve}},
 			}
+			if d.FieldImport != "" {
+				// registers the field with constraint.RegisterCustomField
+				entries = append(entries, bavard.Entry{File: filepath.Join(csDir, "custom.go"), Templates: []string{"custom.go.tmpl", importCurve}})
+			}
 			if err := bgen.Generate(d, "cs", "./template/representations/", entries...); err != nil {
 				panic(err)
 			}
 
 			// gkr backend
-			if d.Curve != "tinyfield" {
+			if d.Curve != "tinyfield" && d.FieldImport == "" {
 				entries = []bavard.Entry{{File: filepath.Join(csDir, "gkr.go"), Templates: []string{"gkr.go.tmpl", importCurve}}}
 				if err := bgen.Generate(d, "cs", "./template/representations/", entries...); err != nil {
 					panic(err)
@@ -156,12 +164,18 @@ func main() {
 			if err := os.MkdirAll(plonkDir, 0700); err != nil {
 				panic(err)
 			}
+			if err := os.MkdirAll(plonkFriDir, 0700); err != nil {
+				panic(err)
+			}
 
 			entries = []bavard.Entry{
 				{File: filepath.Join(groth16Dir, "verify.go"), Templates: []string{"groth16/groth16.verify.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "prove.go"), Templates: []string{"groth16/groth16.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), Templates: []string{"groth16/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "stream.go"), Templates: []string{"groth16/groth16.stream.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "sections.go"), Templates: []string{"groth16/groth16.sections.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "link.go"), Templates: []string{"groth16/groth16.link.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), Templates: []string{"groth16/tests/groth16.marshal.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "groth16", "./template/zkpschemes/", entries...); err != nil {
@@ -184,9 +198,25 @@ func main() {
 				{File: filepath.Join(groth16MpcSetupDir, "phase2.go"), Templates: []string{"groth16/mpcsetup/phase2.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16MpcSetupDir, "setup.go"), Templates: []string{"groth16/mpcsetup/setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16MpcSetupDir, "setup_test.go"), Templates: []string{"groth16/mpcsetup/setup_test.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16MpcSetupDir, "srs.go"), Templates: []string{"groth16/mpcsetup/srs.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16MpcSetupDir, "utils.go"), Templates: []string{"groth16/mpcsetup/utils.go.tmpl", importCurve}},
 			}
 
+			// public transcripts: snarkjs supports BN254 and BLS12-381, Aztec
+			// Ignition is on BN254
+			if d.Curve == "BN254" || d.Curve == "BLS12-381" {
+				entries = append(entries,
+					bavard.Entry{File: filepath.Join(groth16MpcSetupDir, "ptau.go"), Templates: []string{"groth16/mpcsetup/ptau.go.tmpl", importCurve}},
+					bavard.Entry{File: filepath.Join(groth16MpcSetupDir, "ptau_test.go"), Templates: []string{"groth16/mpcsetup/ptau_test.go.tmpl", importCurve}},
+				)
+			}
+			if d.Curve == "BN254" {
+				entries = append(entries,
+					bavard.Entry{File: filepath.Join(groth16MpcSetupDir, "ignition.go"), Templates: []string{"groth16/mpcsetup/ignition.go.tmpl", importCurve}},
+					bavard.Entry{File: filepath.Join(groth16MpcSetupDir, "ignition_test.go"), Templates: []string{"groth16/mpcsetup/ignition_test.go.tmpl", importCurve}},
+				)
+			}
+
 			if err := bgen.Generate(d, "mpcsetup", "./template/zkpschemes/", entries...); err != nil {
 				panic(err) // TODO handle
 			}
@@ -203,6 +233,33 @@ func main() {
 				panic(err)
 			}
 
+			// plonk fflonk
+			if err := os.MkdirAll(plonkFflonkDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(plonkFflonkDir, "verify.go"), Templates: []string{"plonk/fflonk/fflonk.verify.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFflonkDir, "prove.go"), Templates: []string{"plonk/fflonk/fflonk.prove.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFflonkDir, "setup.go"), Templates: []string{"plonk/fflonk/fflonk.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFflonkDir, "marshal.go"), Templates: []string{"plonk/fflonk/fflonk.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkFflonkDir, "marshal_test.go"), Templates: []string{"plonk/fflonk/tests/marshal.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "fflonk", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
+			// fft
+			if err := os.MkdirAll(fftDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(fftDir, "domain.go"), Templates: []string{"domain.go.tmpl", importCurve}},
+				{File: filepath.Join(fftDir, "domain_test.go"), Templates: []string{"tests/domain.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "fft", "./template/fft/", entries...); err != nil {
+				panic(err)
+			}
+
 			// plonkfri
 			entries = []bavard.Entry{
 				{File: filepath.Join(plonkFriDir, "verify.go"), Templates: []string{"plonkfri/plonk.verify.go.tmpl", importCurve}},
@@ -213,13 +270,70 @@ func main() {
 				panic(err)
 			}
 
+			// marlin
+			if err := os.MkdirAll(marlinDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(marlinDir, "verify.go"), Templates: []string{"marlin/marlin.verify.go.tmpl", importCurve}},
+				{File: filepath.Join(marlinDir, "prove.go"), Templates: []string{"marlin/marlin.prove.go.tmpl", importCurve}},
+				{File: filepath.Join(marlinDir, "setup.go"), Templates: []string{"marlin/marlin.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(marlinDir, "marshal.go"), Templates: []string{"marlin/marlin.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(marlinDir, "marshal_test.go"), Templates: []string{"marlin/tests/marshal.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "marlin", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
+			// spartan
+			if err := os.MkdirAll(spartanDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(spartanDir, "verify.go"), Templates: []string{"spartan/spartan.verify.go.tmpl", importCurve}},
+				{File: filepath.Join(spartanDir, "prove.go"), Templates: []string{"spartan/spartan.prove.go.tmpl", importCurve}},
+				{File: filepath.Join(spartanDir, "setup.go"), Templates: []string{"spartan/spartan.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(spartanDir, "pcs.go"), Templates: []string{"spartan/spartan.pcs.go.tmpl", importCurve}},
+				{File: filepath.Join(spartanDir, "ipa.go"), Templates: []string{"spartan/spartan.ipa.go.tmpl", importCurve}},
+				{File: filepath.Join(spartanDir, "fri.go"), Templates: []string{"spartan/spartan.fri.go.tmpl", importCurve}},
+				{File: filepath.Join(spartanDir, "marshal.go"), Templates: []string{"spartan/spartan.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(spartanDir, "marshal_test.go"), Templates: []string{"spartan/tests/marshal.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "spartan", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
+			// bulletproofs
+			if err := os.MkdirAll(bulletproofsDir, 0700); err != nil {
+				panic(err)
+			}
+			entries = []bavard.Entry{
+				{File: filepath.Join(bulletproofsDir, "verify.go"), Templates: []string{"bulletproofs/bulletproofs.verify.go.tmpl", importCurve}},
+				{File: filepath.Join(bulletproofsDir, "prove.go"), Templates: []string{"bulletproofs/bulletproofs.prove.go.tmpl", importCurve}},
+				{File: filepath.Join(bulletproofsDir, "setup.go"), Templates: []string{"bulletproofs/bulletproofs.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(bulletproofsDir, "ipa.go"), Templates: []string{"bulletproofs/bulletproofs.ipa.go.tmpl", importCurve}},
+				{File: filepath.Join(bulletproofsDir, "marshal.go"), Templates: []string{"bulletproofs/bulletproofs.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(bulletproofsDir, "marshal_test.go"), Templates: []string{"bulletproofs/tests/marshal.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "bulletproofs", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
 		}(d)
 
 	}
 
+	// user-defined fields have no hash to field in gnark-crypto
+	var curves []templateData
+	for _, d := range datas {
+		if d.FieldImport == "" {
+			curves = append(curves, d)
+		}
+	}
+
 	wg.Add(1)
 	go func() {
-		if err = bgen.Generate(datas, "constant", "./template/representations/",
+		if err = bgen.Generate(curves, "constant", "./template/representations/",
 			bavard.Entry{File: filepath.Join("../../../constant", "constant.go"), Templates: []string{"constant.go.tmpl"}}); err != nil {
 			panic(err)
 		}
@@ -239,9 +353,10 @@ func main() {
 }
 
 type templateData struct {
-	RootPath  string
-	CSPath    string
-	Curve     string
-	CurveID   string
-	noBackend bool
+	RootPath    string
+	CSPath      string
+	Curve       string
+	CurveID     string
+	FieldImport string // import path of a user-defined field, see cmd/gnark-field
+	noBackend   bool
 }
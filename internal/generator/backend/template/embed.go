@@ -0,0 +1,8 @@
+// Package template embeds the templates of the constraint systems, so that they
+// can be executed outside of this directory by cmd/gnark-field.
+package template
+
+import "embed"
+
+//go:embed imports.go.tmpl representations/*.go.tmpl
+var FS embed.FS
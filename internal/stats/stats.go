@@ -45,7 +45,7 @@ func init() {
 
 func NewGlobalStats() *globalStats {
 	return &globalStats{
-		Stats: make(map[string][backend.PLONKFRI + 1][nbCurves + 1]snippetStats),
+		Stats: make(map[string][backend.BULLETPROOFS + 1][nbCurves + 1]snippetStats),
 	}
 }
 
@@ -77,7 +77,7 @@ func NewSnippetStats(curve ecc.ID, backendID backend.ID, circuit frontend.Circui
 	var newCompiler frontend.NewBuilder
 
 	switch backendID {
-	case backend.GROTH16:
+	case backend.GROTH16, backend.MARLIN, backend.SPARTAN, backend.BULLETPROOFS:
 		newCompiler = r1cs.NewBuilder
 	case backend.PLONK, backend.PLONKFRI:
 		newCompiler = scs.NewBuilder
@@ -112,7 +112,7 @@ type Circuit struct {
 
 type globalStats struct {
 	sync.RWMutex
-	Stats map[string][backend.PLONKFRI + 1][nbCurves + 1]snippetStats
+	Stats map[string][backend.BULLETPROOFS + 1][nbCurves + 1]snippetStats
 }
 
 type snippetStats struct {
@@ -15,6 +15,7 @@
 package utils
 
 import (
+	"errors"
 	"math"
 	"math/big"
 	"reflect"
@@ -37,6 +38,16 @@ type toBigIntInterface interface {
 //
 // panics if the input is invalid
 func FromInterface(input interface{}) big.Int {
+	r, err := TryFromInterface(input)
+	if err != nil {
+		panic(err.Error())
+	}
+	return r
+}
+
+// TryFromInterface is as [FromInterface] but returns an error instead of
+// panicking if the input is invalid.
+func TryFromInterface(input interface{}) (big.Int, error) {
 	var r big.Int
 
 	switch v := input.(type) {
@@ -66,27 +77,27 @@ func FromInterface(input interface{}) big.Int {
 		r.SetInt64(int64(v))
 	case string:
 		if _, ok := r.SetString(v, 0); !ok {
-			panic("unable to set big.Int from string " + v)
+			return r, errors.New("unable to set big.Int from string " + v)
 		}
 	case []byte:
 		r.SetBytes(v)
 	default:
 		if v, ok := input.(toBigIntInterface); ok {
 			v.ToBigIntRegular(&r)
-			return r
+			return r, nil
 		} else if reflect.ValueOf(input).Kind() == reflect.Pointer {
 			vv := reflect.ValueOf(input)
 			if vv.CanInterface() {
 				if v, ok := vv.Interface().(toBigIntInterface); ok {
 					v.ToBigIntRegular(&r)
-					return r
+					return r, nil
 				}
 			}
 		}
-		panic(reflect.TypeOf(input).String() + " to big.Int not supported")
+		return r, errors.New(reflect.TypeOf(input).String() + " to big.Int not supported")
 	}
 
-	return r
+	return r, nil
 }
 
 func IntSliceSliceToUint64SliceSlice(in [][]int) [][]uint64 {
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/consensys/gnark"
+)
+
+// RecoverPanic recovers from a panic if enabled is true, and sets *err to an
+// error wrapping [gnark.ErrPanic] with the panic value and the stack trace.
+// It must be deferred directly.
+func RecoverPanic(enabled bool, err *error) {
+	if !enabled {
+		return
+	}
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v\n%s", gnark.ErrPanic, r, debug.Stack())
+	}
+}
@@ -6,6 +6,7 @@ package profile
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -141,6 +142,25 @@ func (p *Profile) NbConstraints() int {
 	return len(p.pprof.Sample)
 }
 
+// CallStack returns the frames of the call stack which added the constraint
+// cID, from the innermost, formatted as "function file:line". It returns nil
+// if the constraint was not recorded by the profile session.
+func (p *Profile) CallStack(cID int) []string {
+	for _, s := range p.pprof.Sample {
+		if ids := s.NumLabel["constraint"]; len(ids) != 1 || ids[0] != int64(cID) {
+			continue
+		}
+		var res []string
+		for _, l := range s.Location {
+			for _, line := range l.Line {
+				res = append(res, fmt.Sprintf("%s %s:%d", line.Function.Name, line.Function.Filename, line.Line))
+			}
+		}
+		return res
+	}
+	return nil
+}
+
 // Top return a similar output than pprof top command
 func (p *Profile) Top() string {
 	r := report.NewDefault(&p.pprof, report.Options{
@@ -158,18 +178,30 @@ func (p *Profile) Top() string {
 
 // RecordConstraint add a sample (with count == 1) to all the active profiling sessions.
 func RecordConstraint() {
+	recordConstraint(-1)
+}
+
+// RecordConstraintID is as RecordConstraint, but labels the sample with the
+// constraint ID cID (numeric label "constraint"), so that the samples can be
+// correlated with the constraint system, for example with the pprof option
+// -tagfocus=constraint=<id>.
+func RecordConstraintID(cID int) {
+	recordConstraint(cID)
+}
+
+func recordConstraint(cID int) {
 	if n := atomic.LoadUint32(&activeSessions); n == 0 {
 		return // do nothing, no active session.
 	}
 
 	// collect the stack and send it async to the worker
 	pc := make([]uintptr, 20)
-	n := runtime.Callers(3, pc)
+	n := runtime.Callers(4, pc)
 	if n == 0 {
 		return
 	}
 	pc = pc[:n]
-	chCommands <- command{pc: pc}
+	chCommands <- command{pc: pc, cID: cID}
 }
 
 func (p *Profile) getLocation(frame *runtime.Frame) *profile.Location {
@@ -4,6 +4,10 @@ package profile_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
@@ -35,7 +39,13 @@ func Example() {
 	// use pprof as usual (go tool pprof -http=:8080 gnark.pprof) to read the profile file
 	// overlapping profiles are allowed (define profiles inside Define or subfunction to profile
 	// part of the circuit only)
-	p := profile.Start()
+	// here the profile is written to a temporary directory.
+	dir, err := os.MkdirTemp("", "profile")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	p := profile.Start(profile.WithPath(filepath.Join(dir, "gnark.pprof")))
 	_, _ = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{})
 	p.Stop()
 
@@ -59,3 +69,24 @@ func Example() {
 	// Output:
 	// 2
 }
+
+func TestCallStack(t *testing.T) {
+	p := profile.Start(profile.WithNoOutput())
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &Circuit{})
+	p.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the constraints are labeled with their ID: the multiplication is the
+	// constraint 0 and the assertion the constraint 1
+	for cID, function := range []string{"Mul", "AssertIsEqual"} {
+		stack := p.CallStack(cID)
+		if len(stack) == 0 || !strings.Contains(stack[0], "(*builder)."+function) {
+			t.Fatalf("unexpected call stack of constraint %d: %v", cID, stack)
+		}
+	}
+	if stack := p.CallStack(2); stack != nil {
+		t.Fatalf("unexpected call stack of constraint 2: %v", stack)
+	}
+}
@@ -20,6 +20,7 @@ var onceInit sync.Once
 type command struct {
 	p      *Profile
 	pc     []uintptr
+	cID    int // constraint ID of the sample, -1 if unknown
 	remove bool
 }
 
@@ -45,17 +46,20 @@ func worker() {
 		}
 
 		// it's a sampling of event
-		collectSample(c.pc)
+		collectSample(c.pc, c.cID)
 	}
 
 }
 
 // collectSample must be called from the worker go routine
-func collectSample(pc []uintptr) {
+func collectSample(pc []uintptr, cID int) {
 	// for each session we may have a distinct sample, since ids of functions and locations may mismatch
 	samples := make([]*profile.Sample, len(sessions))
 	for i := 0; i < len(samples); i++ {
 		samples[i] = &profile.Sample{Value: []int64{1}} // for now, we just collect new constraints count
+		if cID >= 0 {
+			samples[i].NumLabel = map[string][]int64{"constraint": {int64(cID)}}
+		}
 	}
 
 	frames := runtime.CallersFrames(pc)
@@ -0,0 +1,101 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+)
+
+// MultiProof stores a batch opening of several leaves of the same Merkle tree.
+//
+// Verifying the openings independently costs one hash per level and per leaf.
+// However, the top levels of the tree have fewer nodes than there are
+// openings, so that the paths necessarily share nodes there. The multi-proof
+// thus contains all the nodes of the level t, where 2ᵗ is the largest power of
+// two not larger than the number of openings. The paths are verified from the
+// leaves up to this level only and the nodes reached are looked up in the
+// level, while the top of the tree is hashed once. For k openings of a tree of
+// depth d, this costs k·(d-t) + 2ᵗ hashes instead of k·d.
+type MultiProof struct {
+
+	// RootHash root of the Merkle tree
+	RootHash frontend.Variable
+
+	// Paths are the siblings of each opened leaf, from the bottom up to the
+	// level of Top excluded.
+	Paths [][]frontend.Variable
+
+	// Top are all the nodes of the tree at the level t.
+	Top []frontend.Variable
+}
+
+// PlaceholderMultiProof returns a multi-proof of nbOpenings leaves of a tree
+// of the given depth, to be used in the definition of a circuit.
+func PlaceholderMultiProof(depth, nbOpenings int) MultiProof {
+	if nbOpenings <= 0 || depth < 0 {
+		panic("invalid multi-proof size")
+	}
+	t := bits.Len(uint(nbOpenings)) - 1
+	if t > depth {
+		t = depth
+	}
+	res := MultiProof{
+		Paths: make([][]frontend.Variable, nbOpenings),
+		Top:   make([]frontend.Variable, 1<<t),
+	}
+	for i := range res.Paths {
+		res.Paths[i] = make([]frontend.Variable, depth-t)
+	}
+	return res
+}
+
+// VerifyMultiProof asserts that leaves[i] is the leaf at the position
+// indices[i] of the Merkle tree with root mp.RootHash, for all i. The leaves
+// are hashed as in [MerkleProof.VerifyProof] and the indices may be variables.
+func (mp *MultiProof) VerifyMultiProof(api frontend.API, h hash.FieldHasher, leaves, indices []frontend.Variable) {
+	if len(leaves) != len(mp.Paths) || len(indices) != len(mp.Paths) {
+		panic(fmt.Sprintf("expected %d leaves and indices, got %d and %d", len(mp.Paths), len(leaves), len(indices)))
+	}
+	if len(mp.Top) == 0 || len(mp.Top)&(len(mp.Top)-1) != 0 {
+		panic("the number of top nodes must be a power of two")
+	}
+	t := bits.Len(uint(len(mp.Top))) - 1
+
+	var top *logderivlookup.Table
+	if t > 0 {
+		top = logderivlookup.New(api)
+		for i := range mp.Top {
+			top.Insert(mp.Top[i])
+		}
+	}
+	for i := range leaves {
+		path := mp.Paths[i]
+		binIndex := api.ToBinary(indices[i], len(path)+t)
+		sum := leafSum(api, h, leaves[i])
+		for j := range path {
+			d1 := api.Select(binIndex[j], path[j], sum)
+			d2 := api.Select(binIndex[j], sum, path[j])
+			sum = nodeSum(api, h, d1, d2)
+		}
+		if t == 0 {
+			api.AssertIsEqual(sum, mp.Top[0])
+			continue
+		}
+		topIndex := api.FromBinary(binIndex[len(path):]...)
+		api.AssertIsEqual(sum, top.Lookup(topIndex)[0])
+	}
+
+	// hash the top of the tree
+	level := mp.Top
+	for len(level) > 1 {
+		next := make([]frontend.Variable, len(level)/2)
+		for i := range next {
+			next[i] = nodeSum(api, h, level[2*i], level[2*i+1])
+		}
+		level = next
+	}
+	api.AssertIsEqual(level[0], mp.RootHash)
+}
@@ -0,0 +1,81 @@
+package merkle_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/utils/witnessprep"
+	"github.com/consensys/gnark/test"
+)
+
+type multiProofCircuit struct {
+	M       merkle.MultiProof
+	Leaves  []frontend.Variable
+	Indices []frontend.Variable
+}
+
+func (c *multiProofCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.M.VerifyMultiProof(api, &h, c.Leaves, c.Indices)
+	return nil
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 5
+	mod := ecc.BN254.ScalarField()
+	leaves := make([][]byte, 1<<depth)
+	for i := range leaves {
+		v, err := rand.Int(rand.Reader, mod)
+		assert.NoError(err)
+		leaves[i] = make([]byte, 32)
+		v.FillBytes(leaves[i])
+	}
+
+	for _, indices := range [][]uint64{
+		{9},
+		{3, 17, 3},
+		{0, 1, 2, 31, 30, 12, 12, 5},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			mp, err := witnessprep.MerkleMultiProof(hash.MIMC_BN254, leaves, indices)
+			assert.NoError(err)
+
+			circuit := multiProofCircuit{
+				M:       merkle.PlaceholderMultiProof(depth, len(indices)),
+				Leaves:  make([]frontend.Variable, len(indices)),
+				Indices: make([]frontend.Variable, len(indices)),
+			}
+			witness := multiProofCircuit{
+				M:       mp,
+				Leaves:  make([]frontend.Variable, len(indices)),
+				Indices: make([]frontend.Variable, len(indices)),
+			}
+			invalid := multiProofCircuit{
+				M:       mp,
+				Leaves:  make([]frontend.Variable, len(indices)),
+				Indices: make([]frontend.Variable, len(indices)),
+			}
+			for i, idx := range indices {
+				witness.Leaves[i], witness.Indices[i] = leaves[idx], idx
+				invalid.Leaves[i], invalid.Indices[i] = leaves[idx], idx
+			}
+			invalid.Indices[0] = (indices[0] + 1) % (1 << depth)
+
+			assert.CheckCircuit(&circuit,
+				test.WithCurves(ecc.BN254),
+				test.WithValidAssignment(&witness),
+				test.WithInvalidAssignment(&invalid),
+			)
+		}, fmt.Sprintf("openings=%d", len(indices)))
+	}
+}
@@ -0,0 +1,95 @@
+// Package rsa provides ZKP-circuit functions to verify the membership and
+// non-membership witnesses of RSA accumulators.
+//
+// An RSA accumulator commits to a set of primes {x₁, ..., xₖ} with the single
+// value A = g^(x₁⋯xₖ) mod N, where N is an RSA modulus whose factorization is
+// unknown and g a generator. Contrary to a Merkle tree, the size of the
+// commitment and of the witnesses doesn't depend on the size of the set:
+//   - the membership witness of x is W = g^(∏_{xᵢ≠x} xᵢ), checked by W^x = A;
+//   - the non-membership witness of x is the pair (a, B) such that A^a·B^x = g,
+//     where a·∏xᵢ + b·x = 1 are Bézout coefficients and B = g^b.
+//
+// The members must be primes of exactly nbElementBits bits, for example
+// obtained by hashing the elements of the set to primes outside of the
+// circuit. The circuit asserts that the elements have exactly nbElementBits
+// bits, which ensures that a product of several members can't be given as an
+// element. The arithmetic modulo N uses [bigint], so the modulus can be a
+// witness.
+package rsa
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bigint"
+)
+
+// NonMembershipWitness is the witness that an element is not in the set of the
+// accumulator.
+type NonMembershipWitness struct {
+	// A is the Bézout coefficient of the product of the members, of at most
+	// nbElementBits bits.
+	A frontend.Variable
+	// B is g to the power of the Bézout coefficient of the element.
+	B bigint.Int
+}
+
+// Verifier checks the witnesses of the accumulators of a fixed modulus and
+// generator.
+type Verifier struct {
+	api           frontend.API
+	arith         *bigint.Arithmetic
+	n, g          *bigint.Int
+	nbElementBits int
+}
+
+// NewVerifier returns a verifier for the accumulators modulo n with the
+// generator g, of the members of nbElementBits bits. The limbs of n and g must
+// be well formed and g < n. The elements must fit in the native field.
+func NewVerifier(api frontend.API, n, g *bigint.Int, nbElementBits int) (*Verifier, error) {
+	if nbElementBits < 3 || nbElementBits >= api.Compiler().FieldBitLen() {
+		return nil, errors.New("invalid number of bits of the elements")
+	}
+	arith, err := bigint.New(api)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{api: api, arith: arith, n: n, g: g, nbElementBits: nbElementBits}, nil
+}
+
+// AssertMembership asserts that element is in the set of the accumulator acc,
+// that is w^element = acc mod N.
+func (v *Verifier) AssertMembership(acc *bigint.Int, element frontend.Variable, w *bigint.Int) {
+	v.arith.AssertIsWellFormed(w)
+	lhs := v.arith.ModExpBits(w, v.elementBits(element), v.n)
+	v.arith.AssertIsEqual(lhs, v.reduced(acc))
+}
+
+// AssertNonMembership asserts that element is not in the set of the
+// accumulator acc, that is acc^a·B^element = g mod N.
+func (v *Verifier) AssertNonMembership(acc *bigint.Int, element frontend.Variable, w NonMembershipWitness) {
+	v.arith.AssertIsWellFormed(&w.B)
+	aBits := v.api.ToBinary(w.A, v.nbElementBits)
+	lhs := v.arith.ModMul(
+		v.arith.ModExpBits(acc, aBits, v.n),
+		v.arith.ModExpBits(&w.B, v.elementBits(element), v.n),
+		v.n,
+	)
+	v.arith.AssertIsEqual(lhs, v.reduced(v.g))
+}
+
+// elementBits returns the bits of element, asserting that it has exactly
+// nbElementBits bits.
+func (v *Verifier) elementBits(element frontend.Variable) []frontend.Variable {
+	bits := v.api.ToBinary(element, v.nbElementBits)
+	v.api.AssertIsEqual(bits[v.nbElementBits-1], 1)
+	return bits
+}
+
+// reduced returns x, asserting that x < N so that it can be compared to the
+// reduced results.
+func (v *Verifier) reduced(x *bigint.Int) *bigint.Int {
+	v.arith.AssertIsWellFormed(x)
+	v.arith.AssertIsLess(x, v.n)
+	return x
+}
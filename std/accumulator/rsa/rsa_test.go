@@ -0,0 +1,163 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bigint"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	nbModulusBits = 512
+	nbElementBits = 32
+)
+
+type accumulator struct {
+	n, g    *big.Int
+	members []*big.Int
+}
+
+func newAccumulator(t *testing.T, nbMembers int) *accumulator {
+	p, err := rand.Prime(rand.Reader, nbModulusBits/2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := rand.Prime(rand.Reader, nbModulusBits/2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := &accumulator{n: new(big.Int).Mul(p, q), g: big.NewInt(65537)}
+	for len(acc.members) < nbMembers {
+		acc.members = append(acc.members, randPrime(t))
+	}
+	return acc
+}
+
+func randPrime(t *testing.T) *big.Int {
+	x, err := rand.Prime(rand.Reader, nbElementBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func (acc *accumulator) product(except *big.Int) *big.Int {
+	res := big.NewInt(1)
+	for _, x := range acc.members {
+		if except == nil || x.Cmp(except) != 0 {
+			res.Mul(res, x)
+		}
+	}
+	return res
+}
+
+func (acc *accumulator) value() *big.Int {
+	return new(big.Int).Exp(acc.g, acc.product(nil), acc.n)
+}
+
+func (acc *accumulator) membershipWitness(x *big.Int) *big.Int {
+	return new(big.Int).Exp(acc.g, acc.product(x), acc.n)
+}
+
+// nonMembershipWitness returns a = u⁻¹ mod x and B = g^b with a·u + b·x = 1.
+func (acc *accumulator) nonMembershipWitness(x *big.Int) (*big.Int, *big.Int) {
+	u := acc.product(nil)
+	a := new(big.Int).ModInverse(u, x)
+	b := new(big.Int).Mul(a, u)
+	b.Sub(big.NewInt(1), b).Div(b, x)
+	gInv := new(big.Int).ModInverse(acc.g, acc.n)
+	return a, new(big.Int).Exp(gInv, new(big.Int).Neg(b), acc.n)
+}
+
+type membershipCircuit struct {
+	N, G, Acc bigint.Int
+	Element   frontend.Variable
+	Witness   bigint.Int
+}
+
+func (c *membershipCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, &c.N, &c.G, nbElementBits)
+	if err != nil {
+		return err
+	}
+	v.AssertMembership(&c.Acc, c.Element, &c.Witness)
+	return nil
+}
+
+func TestMembership(t *testing.T) {
+	assert := test.NewAssert(t)
+	acc := newAccumulator(t, 3)
+	x := acc.members[1]
+
+	circuit := membershipCircuit{
+		N:       bigint.Placeholder(nbModulusBits),
+		G:       bigint.Placeholder(nbModulusBits),
+		Acc:     bigint.Placeholder(nbModulusBits),
+		Witness: bigint.Placeholder(nbModulusBits),
+	}
+	assignment := func(element, w *big.Int) *membershipCircuit {
+		return &membershipCircuit{
+			N:       bigint.ValueOf(acc.n, nbModulusBits),
+			G:       bigint.ValueOf(acc.g, nbModulusBits),
+			Acc:     bigint.ValueOf(acc.value(), nbModulusBits),
+			Element: element,
+			Witness: bigint.ValueOf(w, nbModulusBits),
+		}
+	}
+	// the product of two members is a valid witness for the equation, but has
+	// too many bits
+	pair := new(big.Int).Mul(acc.members[0], acc.members[1])
+	assert.CheckCircuit(&circuit,
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(assignment(x, acc.membershipWitness(x))),
+		test.WithInvalidAssignment(assignment(acc.members[0], acc.membershipWitness(x))),
+		test.WithInvalidAssignment(assignment(pair, acc.membershipWitness(pair))),
+	)
+}
+
+type nonMembershipCircuit struct {
+	N, G, Acc bigint.Int
+	Element   frontend.Variable
+	Witness   NonMembershipWitness
+}
+
+func (c *nonMembershipCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, &c.N, &c.G, nbElementBits)
+	if err != nil {
+		return err
+	}
+	v.AssertNonMembership(&c.Acc, c.Element, c.Witness)
+	return nil
+}
+
+func TestNonMembership(t *testing.T) {
+	assert := test.NewAssert(t)
+	acc := newAccumulator(t, 3)
+	x := randPrime(t)
+	a, b := acc.nonMembershipWitness(x)
+
+	circuit := nonMembershipCircuit{
+		N:       bigint.Placeholder(nbModulusBits),
+		G:       bigint.Placeholder(nbModulusBits),
+		Acc:     bigint.Placeholder(nbModulusBits),
+		Witness: NonMembershipWitness{B: bigint.Placeholder(nbModulusBits)},
+	}
+	assignment := func(element *big.Int) *nonMembershipCircuit {
+		return &nonMembershipCircuit{
+			N:       bigint.ValueOf(acc.n, nbModulusBits),
+			G:       bigint.ValueOf(acc.g, nbModulusBits),
+			Acc:     bigint.ValueOf(acc.value(), nbModulusBits),
+			Element: element,
+			Witness: NonMembershipWitness{A: a, B: bigint.ValueOf(b, nbModulusBits)},
+		}
+	}
+	assert.CheckCircuit(&circuit,
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(assignment(x)),
+		test.WithInvalidAssignment(assignment(acc.members[0])),
+	)
+}
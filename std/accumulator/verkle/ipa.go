@@ -0,0 +1,104 @@
+package verkle
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// IPAProof is an inner-product argument that a vector committed with the basis
+// of the parameters, seen as the evaluations of a polynomial f on the domain
+// {0, ..., n-1}, satisfies f(z) = y. There are log₂(n) rounds.
+type IPAProof struct {
+	// L, R are the commitments to the cross terms of every round.
+	L, R []edwards.Point
+	// A is the vector folded to a single scalar.
+	A Scalar
+}
+
+// VerifyIPA verifies that the polynomial committed in commitment evaluates to
+// y at z. The point z must not belong to the domain {0, ..., n-1} and z and y
+// must be reduced.
+//
+// The transcript absorbs the commitment, z and y, then squeezes the challenge
+// w binding y to the point [w]Q, and one challenge per round.
+func (v *Verifier) VerifyIPA(commitment edwards.Point, z, y *Scalar, proof IPAProof) error {
+	ts, err := v.newTranscript("verkle-ipa")
+	if err != nil {
+		return err
+	}
+	absorbPoint(ts, commitment)
+	v.absorbScalar(ts, z)
+	v.absorbScalar(ts, y)
+	return v.verifyIPA(ts, commitment, z, y, proof)
+}
+
+// verifyIPA checks the proof with the transcript ts, which must already be
+// bound to the commitment, z and y.
+func (v *Verifier) verifyIPA(ts *fiatshamir.Sponge, commitment edwards.Point, z, y *Scalar, proof IPAProof) error {
+	if len(proof.L) != v.nbRounds || len(proof.R) != v.nbRounds {
+		return fmt.Errorf("expected %d rounds, got %d and %d", v.nbRounds, len(proof.L), len(proof.R))
+	}
+	n := len(v.params.Basis)
+
+	// C' = C + [w·y]Q
+	w, _ := v.challenge(ts)
+	commitment = v.curve.Add(commitment, v.scalarMul(v.params.Q, v.fr.Mul(w, y)))
+
+	b := v.barycentricWeights(z)
+	g := make([]edwards.Point, n)
+	copy(g, v.params.Basis)
+
+	for i := range proof.L {
+		absorbPoint(ts, proof.L[i])
+		absorbPoint(ts, proof.R[i])
+		x, xNative := v.challenge(ts)
+		xInv := v.fr.Inverse(x)
+		xInvNative := bits.FromBinary(v.api, v.canonicalBits(xInv))
+
+		// C' = [x]L + C + [x⁻¹]R
+		commitment = v.curve.Add(commitment, v.curve.DoubleBaseScalarMul(proof.L[i], proof.R[i], xNative, xInvNative))
+
+		// G' = G_L + [x⁻¹]G_R and b' = b_L + x⁻¹·b_R
+		half := len(g) / 2
+		for j := 0; j < half; j++ {
+			g[j] = v.curve.Add(g[j], v.curve.ScalarMul(g[half+j], xInvNative))
+			b[j] = v.fr.Add(b[j], v.fr.Mul(xInv, b[half+j]))
+		}
+		g, b = g[:half], b[:half]
+	}
+
+	// C' = [a]G₀ + [a·b₀·w]Q
+	expected := v.curve.DoubleBaseScalarMul(g[0], v.params.Q,
+		bits.FromBinary(v.api, v.canonicalBits(&proof.A)),
+		bits.FromBinary(v.api, v.canonicalBits(v.fr.Mul(&proof.A, v.fr.Mul(b[0], w)))),
+	)
+	v.assertIsEqual(commitment, expected)
+	return nil
+}
+
+// barycentricWeights returns the vector b such that f(z) = ∑ bᵢ·f(i) for the
+// polynomials f of degree less than n, that is bᵢ = A(z) / (A'(i)·(z-i)) where
+// A is the vanishing polynomial of the domain.
+func (v *Verifier) barycentricWeights(z *Scalar) []*Scalar {
+	n := len(v.weights)
+	diffs := make([]*Scalar, n)
+	az := v.fr.One()
+	for i := range diffs {
+		diffs[i] = v.fr.Sub(z, v.fr.NewElement(i))
+		az = v.fr.Mul(az, diffs[i])
+	}
+	res := make([]*Scalar, n)
+	for i := range res {
+		res[i] = v.fr.Div(v.fr.Mul(az, v.weights[i]), diffs[i])
+	}
+	return res
+}
+
+// toScalar returns the scalar equal to the small variable x of nbBits bits.
+func (v *Verifier) toScalar(x frontend.Variable, nbBits int) *Scalar {
+	return v.fr.FromBits(bits.ToBinary(v.api, x, bits.WithNbDigits(nbBits))...)
+}
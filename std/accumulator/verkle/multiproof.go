@@ -0,0 +1,73 @@
+package verkle
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// Opening is the claim that the vector committed in Commitment has the entry
+// Value at Index, that is the child of a node of the tree.
+type Opening struct {
+	Commitment edwards.Point
+	Index      frontend.Variable
+	Value      Scalar
+}
+
+// MultiProof is a proof of several openings at once.
+type MultiProof struct {
+	// D is the commitment to the aggregated quotient polynomial
+	// g(X) = ∑ rⁱ·(fᵢ(X) - yᵢ)/(X - zᵢ).
+	D edwards.Point
+	// IPA proves the evaluation of the aggregated polynomial at the challenge t.
+	IPA IPAProof
+}
+
+// VerifyMultiProof verifies that all the openings hold. The values of the
+// openings must be reduced.
+//
+// The transcript absorbs every opening (the commitment, the index and the
+// value) and squeezes the challenge r. It absorbs D and squeezes the evaluation
+// point t. The verifier computes
+//
+//	E = ∑ [rⁱ/(t - zᵢ)]Cᵢ and g₂(t) = ∑ rⁱ·yᵢ/(t - zᵢ)
+//
+// and absorbs E, then checks the IPA proof that E - D opens to g₂(t) at t.
+func (v *Verifier) VerifyMultiProof(openings []Opening, proof MultiProof) error {
+	if len(openings) == 0 {
+		return errors.New("no opening to verify")
+	}
+	ts, err := v.newTranscript("verkle-multiproof")
+	if err != nil {
+		return err
+	}
+	for i := range openings {
+		absorbPoint(ts, openings[i].Commitment)
+		ts.Absorb(openings[i].Index)
+		v.absorbScalar(ts, &openings[i].Value)
+	}
+	r, _ := v.challenge(ts)
+	absorbPoint(ts, proof.D)
+	t, _ := v.challenge(ts)
+
+	ri := v.fr.One()
+	g2 := v.fr.Zero()
+	var e edwards.Point
+	for i := range openings {
+		// the index is range checked to the domain by its decomposition
+		z := v.toScalar(openings[i].Index, v.nbRounds)
+		coeff := v.fr.Div(ri, v.fr.Sub(t, z))
+		g2 = v.fr.Add(g2, v.fr.Mul(coeff, &openings[i].Value))
+		term := v.scalarMul(openings[i].Commitment, coeff)
+		if i == 0 {
+			e = term
+		} else {
+			e = v.curve.Add(e, term)
+		}
+		ri = v.fr.Mul(ri, r)
+	}
+	absorbPoint(ts, e)
+
+	return v.verifyIPA(ts, v.curve.Add(e, v.curve.Neg(proof.D)), t, g2, proof.IPA)
+}
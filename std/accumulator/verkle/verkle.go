@@ -0,0 +1,163 @@
+// Package verkle provides ZKP-circuit functions to verify Verkle proofs.
+//
+// A Verkle tree commits to the children of its nodes with Pedersen vector
+// commitments over the Bandersnatch curve, the twisted Edwards curve defined
+// over the scalar field of BLS12-381. A proof of a set of paths in the tree is
+// a multiproof: an opening of every commitment along the paths at the index of
+// the child, aggregated into a single inner-product argument (IPA) as in
+// [Verkle multiproofs].
+//
+// The circuit must be defined over the scalar field of BLS12-381, so that the
+// curve arithmetic is native. The arithmetic modulo the order of the curve is
+// emulated.
+//
+// The Fiat-Shamir transcript uses the SNARK-friendly hash given to
+// [NewVerifier] and not the SHA-256 transcript of the Ethereum specification,
+// the proofs must be generated with the same transcript, see [fiatshamir.Sponge].
+// As in Banderwagon, the points are compared up to the 2-torsion point (0, -1).
+//
+// [Verkle multiproofs]: https://dankradfeist.de/ethereum/2021/06/18/pcs-multiproofs.html
+package verkle
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+)
+
+// Scalar is an integer modulo the order of the Bandersnatch curve.
+type Scalar = emulated.Element[emparams.BandersnatchFr]
+
+// challengeBits is the size of the challenges, which are below the order of
+// the curve without reduction.
+const challengeBits = 252
+
+// Params are the public parameters of the vector commitment: the basis of the
+// Pedersen commitment, whose size is the width of the tree, and the point used
+// to commit to the inner products. Use [ValueOfParams] to initialize them.
+type Params struct {
+	Basis []edwards.Point
+	Q     edwards.Point
+}
+
+// ValueOfParams returns the parameters with the given basis and point Q as
+// constants of the circuit. The size of the basis must be a power of two.
+func ValueOfParams(basis []bandersnatch.PointAffine, q bandersnatch.PointAffine) Params {
+	res := Params{Basis: make([]edwards.Point, len(basis)), Q: ValueOfPoint(q)}
+	for i := range basis {
+		res.Basis[i] = ValueOfPoint(basis[i])
+	}
+	return res
+}
+
+// ValueOfPoint returns the witness assignment of the point p.
+func ValueOfPoint(p bandersnatch.PointAffine) edwards.Point {
+	var x, y big.Int
+	p.X.BigInt(&x)
+	p.Y.BigInt(&y)
+	return edwards.Point{X: &x, Y: &y}
+}
+
+// Verifier verifies IPA openings and Verkle multiproofs.
+type Verifier struct {
+	api    frontend.API
+	curve  edwards.Curve
+	fr     *emulated.Field[emparams.BandersnatchFr]
+	h      hash.FieldHasher
+	params Params
+
+	// nbRounds is log₂ of the size of the basis
+	nbRounds int
+
+	// weights are the inverses of the derivative of the vanishing polynomial
+	// of the domain at the points of the domain, for barycentric evaluation
+	weights []*Scalar
+}
+
+// NewVerifier returns a verifier with the parameters params and the hash
+// function h for the Fiat-Shamir transcript.
+func NewVerifier(api frontend.API, params Params, h hash.FieldHasher) (*Verifier, error) {
+	n := len(params.Basis)
+	if n < 2 || n&(n-1) != 0 {
+		return nil, errors.New("the size of the basis must be a power of two")
+	}
+	curve, err := edwards.NewEdCurve(api, twistededwards.BLS12_381_BANDERSNATCH)
+	if err != nil {
+		return nil, err
+	}
+	fr, err := emulated.NewField[emparams.BandersnatchFr](api)
+	if err != nil {
+		return nil, err
+	}
+
+	// A'(i) = ∏_{j≠i} (i-j) over the domain {0, ..., n-1}
+	order := emparams.BandersnatchFr{}.Modulus()
+	weights := make([]*Scalar, n)
+	for i := range weights {
+		d := big.NewInt(1)
+		for j := 0; j < n; j++ {
+			if j != i {
+				d.Mul(d, big.NewInt(int64(i-j)))
+			}
+		}
+		d.Mod(d, order).ModInverse(d, order)
+		weights[i] = fr.NewElement(d)
+	}
+
+	nbRounds := 0
+	for 1<<nbRounds < n {
+		nbRounds++
+	}
+
+	return &Verifier{api: api, curve: curve, fr: fr, h: h, params: params, nbRounds: nbRounds, weights: weights}, nil
+}
+
+// newTranscript returns a sponge with the domain separator label.
+func (v *Verifier) newTranscript(label string) (*fiatshamir.Sponge, error) {
+	return fiatshamir.NewSponge(v.api, v.h, label)
+}
+
+func absorbPoint(ts *fiatshamir.Sponge, p edwards.Point) {
+	ts.Absorb(p.X, p.Y)
+}
+
+// absorbScalar adds the canonical representation of s to the transcript. The
+// value of s must be reduced.
+func (v *Verifier) absorbScalar(ts *fiatshamir.Sponge, s *Scalar) {
+	ts.Absorb(bits.FromBinary(v.api, v.canonicalBits(s)))
+}
+
+// challenge squeezes a challenge, returned as a scalar and as a native
+// variable usable in scalar multiplications.
+func (v *Verifier) challenge(ts *fiatshamir.Sponge) (*Scalar, frontend.Variable) {
+	b := bits.ToBinary(v.api, ts.Squeeze())[:challengeBits]
+	return v.fr.FromBits(b...), bits.FromBinary(v.api, b)
+}
+
+// canonicalBits returns the bits of the representative of s in [0, ℓ), where
+// ℓ is the order of the curve.
+func (v *Verifier) canonicalBits(s *Scalar) []frontend.Variable {
+	s = v.fr.Reduce(s)
+	v.fr.AssertIsInRange(s)
+	return v.fr.ToBits(s)[:challengeBits+1]
+}
+
+// scalarMul returns [s]p.
+func (v *Verifier) scalarMul(p edwards.Point, s *Scalar) edwards.Point {
+	return v.curve.ScalarMul(p, bits.FromBinary(v.api, v.canonicalBits(s)))
+}
+
+// assertIsEqual asserts that p and q are the same element of Banderwagon, that
+// is p = q or p = q + (0, -1).
+func (v *Verifier) assertIsEqual(p, q edwards.Point) {
+	v.api.AssertIsEqual(v.api.Mul(p.X, q.Y), v.api.Mul(q.X, p.Y))
+}
@@ -0,0 +1,393 @@
+package verkle
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	edwards "github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+const testWidth = 4
+
+var order = emparams.BandersnatchFr{}.Modulus()
+
+// transcript mirrors the in-circuit sponge over MiMC.
+type transcript struct {
+	state   fr.Element
+	pending []fr.Element
+}
+
+func newTestTranscript(t *testing.T, label string) *transcript {
+	s, err := fr.Hash([]byte(label), []byte("string:"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &transcript{state: s[0]}
+}
+
+func (ts *transcript) absorbPoint(p bandersnatch.PointAffine) {
+	ts.pending = append(ts.pending, p.X, p.Y)
+}
+
+func (ts *transcript) absorb(v *big.Int) {
+	var e fr.Element
+	e.SetBigInt(v)
+	ts.pending = append(ts.pending, e)
+}
+
+func (ts *transcript) challenge() *big.Int {
+	h := hash.MIMC_BLS12_381.New()
+	b := ts.state.Bytes()
+	h.Write(b[:])
+	for i := range ts.pending {
+		b = ts.pending[i].Bytes()
+		h.Write(b[:])
+	}
+	ts.pending = ts.pending[:0]
+	ts.state.SetBytes(h.Sum(nil))
+	var res big.Int
+	ts.state.BigInt(&res)
+	mask := new(big.Int).Lsh(big.NewInt(1), challengeBits)
+	return res.Mod(&res, mask)
+}
+
+func randScalar(t *testing.T) *big.Int {
+	s, err := rand.Int(rand.Reader, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func mod(v *big.Int) *big.Int {
+	return v.Mod(v, order)
+}
+
+func inv(v *big.Int) *big.Int {
+	return new(big.Int).ModInverse(mod(new(big.Int).Set(v)), order)
+}
+
+func mul(p bandersnatch.PointAffine, s *big.Int) bandersnatch.PointAffine {
+	var res bandersnatch.PointAffine
+	res.ScalarMultiplication(&p, s)
+	return res
+}
+
+func add(p, q bandersnatch.PointAffine) bandersnatch.PointAffine {
+	var res bandersnatch.PointAffine
+	res.Add(&p, &q)
+	return res
+}
+
+func commit(basis []bandersnatch.PointAffine, a []*big.Int) bandersnatch.PointAffine {
+	var res bandersnatch.PointAffine
+	res.X.SetZero()
+	res.Y.SetOne()
+	for i := range a {
+		res = add(res, mul(basis[i], a[i]))
+	}
+	return res
+}
+
+func innerProduct(a, b []*big.Int) *big.Int {
+	res := new(big.Int)
+	for i := range a {
+		res.Add(res, new(big.Int).Mul(a[i], b[i]))
+	}
+	return mod(res)
+}
+
+type testSetup struct {
+	basis []bandersnatch.PointAffine
+	q     bandersnatch.PointAffine
+}
+
+func newTestSetup(t *testing.T) testSetup {
+	base := bandersnatch.GetEdwardsCurve().Base
+	var s testSetup
+	s.basis = make([]bandersnatch.PointAffine, testWidth)
+	for i := range s.basis {
+		s.basis[i] = mul(base, randScalar(t))
+	}
+	s.q = mul(base, randScalar(t))
+	return s
+}
+
+// barycentric returns the weights bᵢ = A(z) / (A'(i)·(z-i)).
+func barycentric(z *big.Int) []*big.Int {
+	az := big.NewInt(1)
+	for j := 0; j < testWidth; j++ {
+		mod(az.Mul(az, new(big.Int).Sub(z, big.NewInt(int64(j)))))
+	}
+	res := make([]*big.Int, testWidth)
+	for i := range res {
+		d := new(big.Int).Sub(z, big.NewInt(int64(i)))
+		for j := 0; j < testWidth; j++ {
+			if j != i {
+				d.Mul(d, big.NewInt(int64(i-j)))
+			}
+		}
+		res[i] = mod(new(big.Int).Mul(az, inv(d)))
+	}
+	return res
+}
+
+// proveIPA proves <a, b(z)> = y with the transcript bound to the claim.
+func (s testSetup) proveIPA(ts *transcript, a []*big.Int, z, y *big.Int) (l, r []bandersnatch.PointAffine, final *big.Int) {
+	q := mul(s.q, ts.challenge())
+	b := barycentric(z)
+	g := append([]bandersnatch.PointAffine{}, s.basis...)
+	a = append([]*big.Int{}, a...)
+	for len(a) > 1 {
+		h := len(a) / 2
+		cl := add(commit(g[:h], a[h:]), mul(q, innerProduct(a[h:], b[:h])))
+		cr := add(commit(g[h:], a[:h]), mul(q, innerProduct(a[:h], b[h:])))
+		l, r = append(l, cl), append(r, cr)
+		ts.absorbPoint(cl)
+		ts.absorbPoint(cr)
+		x := ts.challenge()
+		xInv := inv(x)
+		for j := 0; j < h; j++ {
+			a[j] = mod(new(big.Int).Add(a[j], new(big.Int).Mul(x, a[h+j])))
+			b[j] = mod(new(big.Int).Add(b[j], new(big.Int).Mul(xInv, b[h+j])))
+			g[j] = add(g[j], mul(g[h+j], xInv))
+		}
+		a, b, g = a[:h], b[:h], g[:h]
+	}
+	return l, r, a[0]
+}
+
+func valueOfIPAProof(l, r []bandersnatch.PointAffine, a *big.Int) IPAProof {
+	res := IPAProof{A: emulated.ValueOf[emparams.BandersnatchFr](a)}
+	for i := range l {
+		res.L = append(res.L, ValueOfPoint(l[i]))
+		res.R = append(res.R, ValueOfPoint(r[i]))
+	}
+	return res
+}
+
+func newIPAProof() IPAProof {
+	return IPAProof{L: make([]edwards.Point, 2), R: make([]edwards.Point, 2)}
+}
+
+type ipaCircuit struct {
+	Params     Params `gnark:"-"`
+	Commitment edwards.Point
+	Z, Y       Scalar
+	Proof      IPAProof
+}
+
+func (c *ipaCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	v, err := NewVerifier(api, c.Params, &h)
+	if err != nil {
+		return err
+	}
+	return v.VerifyIPA(c.Commitment, &c.Z, &c.Y, c.Proof)
+}
+
+func TestVerifyIPA(t *testing.T) {
+	assert := test.NewAssert(t)
+	s := newTestSetup(t)
+
+	a := make([]*big.Int, testWidth)
+	for i := range a {
+		a[i] = randScalar(t)
+	}
+	cmt := commit(s.basis, a)
+	z := randScalar(t)
+	y := innerProduct(a, barycentric(z))
+
+	ts := newTestTranscript(t, "verkle-ipa")
+	ts.absorbPoint(cmt)
+	ts.absorb(z)
+	ts.absorb(y)
+	l, r, final := s.proveIPA(ts, a, z, y)
+
+	params := ValueOfParams(s.basis, s.q)
+	circuit := ipaCircuit{Params: params, Proof: newIPAProof()}
+	witness := ipaCircuit{
+		Params:     params,
+		Commitment: ValueOfPoint(cmt),
+		Z:          emulated.ValueOf[emparams.BandersnatchFr](z),
+		Y:          emulated.ValueOf[emparams.BandersnatchFr](y),
+		Proof:      valueOfIPAProof(l, r, final),
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+
+	witness.Y = emulated.ValueOf[emparams.BandersnatchFr](new(big.Int).Add(y, big.NewInt(1)))
+	err = test.IsSolved(&circuit, &witness, ecc.BLS12_381.ScalarField())
+	assert.Error(err)
+}
+
+type multiProofCircuit struct {
+	Params   Params `gnark:"-"`
+	Openings [3]Opening
+	Proof    MultiProof
+}
+
+func (c *multiProofCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	v, err := NewVerifier(api, c.Params, &h)
+	if err != nil {
+		return err
+	}
+	return v.VerifyMultiProof(c.Openings[:], c.Proof)
+}
+
+// interpolate returns the coefficients of the polynomial of degree less than
+// n with the evaluations evals on the domain {0, ..., n-1}.
+func interpolate(evals []*big.Int) []*big.Int {
+	n := len(evals)
+	res := make([]*big.Int, n)
+	for i := range res {
+		res[i] = new(big.Int)
+	}
+	for i := range evals {
+		// Lᵢ(X) = ∏_{j≠i} (X - j) / (i - j)
+		basis := []*big.Int{big.NewInt(1)}
+		denom := big.NewInt(1)
+		for j := 0; j < n; j++ {
+			if j == i {
+				continue
+			}
+			next := make([]*big.Int, len(basis)+1)
+			next[0] = new(big.Int)
+			for k := range basis {
+				next[k+1] = new(big.Int).Set(basis[k])
+			}
+			for k := range basis {
+				next[k].Sub(next[k], new(big.Int).Mul(basis[k], big.NewInt(int64(j))))
+			}
+			basis = next
+			denom.Mul(denom, big.NewInt(int64(i-j)))
+		}
+		c := mod(new(big.Int).Mul(evals[i], inv(denom)))
+		for k := range basis {
+			mod(res[k].Add(res[k], new(big.Int).Mul(c, basis[k])))
+		}
+	}
+	return res
+}
+
+// quotient returns the evaluations on the domain of (f(X) - f(z)) / (X - z).
+func quotient(evals []*big.Int, z int) []*big.Int {
+	coeffs := interpolate(evals)
+	// synthetic division by X - z
+	n := len(coeffs)
+	q := make([]*big.Int, n-1)
+	carry := new(big.Int)
+	for k := n - 1; k >= 1; k-- {
+		carry = mod(new(big.Int).Add(coeffs[k], new(big.Int).Mul(carry, big.NewInt(int64(z)))))
+		q[k-1] = carry
+	}
+	res := make([]*big.Int, n)
+	for i := range res {
+		res[i] = new(big.Int)
+		for k := len(q) - 1; k >= 0; k-- {
+			mod(res[i].Add(res[i].Mul(res[i], big.NewInt(int64(i))), q[k]))
+		}
+	}
+	return res
+}
+
+func TestVerifyMultiProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	s := newTestSetup(t)
+
+	indices := []int{1, 3, 0}
+	polys := make([][]*big.Int, len(indices))
+	cmts := make([]bandersnatch.PointAffine, len(indices))
+	values := make([]*big.Int, len(indices))
+	ts := newTestTranscript(t, "verkle-multiproof")
+	for i := range polys {
+		polys[i] = make([]*big.Int, testWidth)
+		for j := range polys[i] {
+			polys[i][j] = randScalar(t)
+		}
+		cmts[i] = commit(s.basis, polys[i])
+		values[i] = polys[i][indices[i]]
+		ts.absorbPoint(cmts[i])
+		ts.absorb(big.NewInt(int64(indices[i])))
+		ts.absorb(values[i])
+	}
+	r := ts.challenge()
+
+	// g(X) = ∑ rⁱ·(fᵢ(X) - yᵢ)/(X - zᵢ)
+	g := make([]*big.Int, testWidth)
+	for j := range g {
+		g[j] = new(big.Int)
+	}
+	ri := big.NewInt(1)
+	for i := range polys {
+		q := quotient(polys[i], indices[i])
+		for j := range g {
+			mod(g[j].Add(g[j], new(big.Int).Mul(ri, q[j])))
+		}
+		ri = mod(new(big.Int).Mul(ri, r))
+	}
+	d := commit(s.basis, g)
+	ts.absorbPoint(d)
+	tc := ts.challenge()
+
+	// h(X) = ∑ rⁱ·fᵢ(X)/(t - zᵢ)
+	hEvals := make([]*big.Int, testWidth)
+	for j := range hEvals {
+		hEvals[j] = new(big.Int)
+	}
+	var e bandersnatch.PointAffine
+	e.X.SetZero()
+	e.Y.SetOne()
+	ri = big.NewInt(1)
+	for i := range polys {
+		coeff := mod(new(big.Int).Mul(ri, inv(new(big.Int).Sub(tc, big.NewInt(int64(indices[i]))))))
+		for j := range hEvals {
+			mod(hEvals[j].Add(hEvals[j], new(big.Int).Mul(coeff, polys[i][j])))
+		}
+		e = add(e, mul(cmts[i], coeff))
+		ri = mod(new(big.Int).Mul(ri, r))
+	}
+	ts.absorbPoint(e)
+	a := make([]*big.Int, testWidth)
+	for j := range a {
+		a[j] = mod(new(big.Int).Sub(hEvals[j], g[j]))
+	}
+	l, rr, final := s.proveIPA(ts, a, tc, innerProduct(a, barycentric(tc)))
+
+	params := ValueOfParams(s.basis, s.q)
+	circuit := multiProofCircuit{Params: params, Proof: MultiProof{IPA: newIPAProof()}}
+	witness := multiProofCircuit{
+		Params: params,
+		Proof:  MultiProof{D: ValueOfPoint(d), IPA: valueOfIPAProof(l, rr, final)},
+	}
+	for i := range indices {
+		witness.Openings[i] = Opening{
+			Commitment: ValueOfPoint(cmts[i]),
+			Index:      indices[i],
+			Value:      emulated.ValueOf[emparams.BandersnatchFr](values[i]),
+		}
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+
+	witness.Openings[1].Value = emulated.ValueOf[emparams.BandersnatchFr](polys[1][2])
+	err = test.IsSolved(&circuit, &witness, ecc.BLS12_381.ScalarField())
+	assert.Error(err)
+}
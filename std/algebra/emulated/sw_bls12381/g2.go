@@ -10,6 +10,8 @@ import (
 )
 
 type G2 struct {
+	api frontend.API
+	fp  *emulated.Field[emulated.BLS12381Fp]
 	*fields_bls12381.Ext2
 	u1, w *emulated.Element[emulated.BLS12381Fp]
 	v     *fields_bls12381.E2
@@ -20,6 +22,10 @@ type G2Affine struct {
 }
 
 func NewG2(api frontend.API) *G2 {
+	fp, err := emulated.NewField[emulated.BLS12381Fp](api)
+	if err != nil {
+		panic(err)
+	}
 	w := emulated.ValueOf[emulated.BLS12381Fp]("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436")
 	u1 := emulated.ValueOf[emulated.BLS12381Fp]("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939437")
 	v := fields_bls12381.E2{
@@ -27,6 +33,8 @@ func NewG2(api frontend.API) *G2 {
 		A1: emulated.ValueOf[emulated.BLS12381Fp]("1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257"),
 	}
 	return &G2{
+		api:  api,
+		fp:   fp,
 		Ext2: fields_bls12381.NewExt2(api),
 		w:    &w,
 		u1:   &u1,
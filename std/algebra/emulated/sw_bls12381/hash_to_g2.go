@@ -0,0 +1,269 @@
+package sw_bls12381
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// constants of the SSWU map to the curve E2': y² = x³ + A'x + B' which is
+// 3-isogenous to the twist, and of the isogeny, from RFC 9380 section 8.8.2
+// and appendix E.3.
+var (
+	sswuZ = [2]string{
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559785",
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559786",
+	}
+	sswuA = [2]string{"0", "240"}
+	sswuB = [2]string{"1012", "1012"}
+
+	isoXNum = [4][2]string{
+		{"889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542"},
+		{"0", "2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706522"},
+		{"2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706526", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853261"},
+		{"3557697382419259905260257622876359250272784728834673675850718343221361467102966990615722337003569479144794908942033", "0"},
+	}
+	// monic, the leading coefficient is omitted
+	isoXDen = [2][2]string{
+		{"0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559715"},
+		{"12", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559775"},
+	}
+	isoYNum = [4][2]string{
+		{"3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558", "3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558"},
+		{"0", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235518"},
+		{"2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706524", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853263"},
+		{"2816510427748580758331037284777117739799287910327449993381818688383577828123182200904113516794492504322962636245776", "0"},
+	}
+	// monic, the leading coefficient is omitted
+	isoYDen = [3][2]string{
+		{"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355"},
+		{"0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559571"},
+		{"18", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559769"},
+	}
+)
+
+func newE2(v [2]string) *fields_bls12381.E2 {
+	return &fields_bls12381.E2{
+		A0: emulated.ValueOf[emulated.BLS12381Fp](v[0]),
+		A1: emulated.ValueOf[emulated.BLS12381Fp](v[1]),
+	}
+}
+
+// HashToG2 hashes msg to a point of G2 with the domain separation tag dst,
+// using the hash-to-curve suite BLS12381G2_XMD:SHA-256_SSWU_RO_ of [RFC 9380].
+// The result is the same as bls12381.HashToG2 of gnark-crypto.
+//
+// The exceptional cases of the map to the curve, which only happen with
+// negligible probability when the input is the output of a hash function, are
+// not handled and make the circuit unsatisfiable.
+//
+// [RFC 9380]: https://www.rfc-editor.org/rfc/rfc9380
+func (g2 *G2) HashToG2(msg []uints.U8, dst []byte) (*G2Affine, error) {
+	u, err := g2.hashToField(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	q0, err := g2.mapToCurve(&u[0])
+	if err != nil {
+		return nil, err
+	}
+	q1, err := g2.mapToCurve(&u[1])
+	if err != nil {
+		return nil, err
+	}
+	// the isogeny is a group morphism and the addition formula doesn't depend
+	// on the coefficients of the curve, so we add on E2' and map only once.
+	r := g2.isogeny(g2.add(q0, q1))
+	return g2.clearCofactor(r), nil
+}
+
+// expandMsgXmd implements expand_message_xmd with SHA-256 as defined in RFC
+// 9380 section 5.3.1.
+func (g2 *G2) expandMsgXmd(msg []uints.U8, dst []byte, lenInBytes int) ([]uints.U8, error) {
+	const bInBytes, sInBytes = 32, 64
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if ell > 255 || lenInBytes > 65535 || len(dst) > 255 {
+		return nil, fmt.Errorf("invalid expand_message_xmd parameters")
+	}
+	uapi, err := uints.New[uints.U32](g2.api)
+	if err != nil {
+		return nil, err
+	}
+	dstPrime := uints.NewU8Array(append(append([]byte{}, dst...), byte(len(dst))))
+	hash := func(data ...[]uints.U8) ([]uints.U8, error) {
+		h, err := sha2.New(g2.api)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range data {
+			h.Write(d)
+		}
+		return h.Sum(), nil
+	}
+
+	b0, err := hash(
+		uints.NewU8Array(make([]byte, sInBytes)),
+		msg,
+		uints.NewU8Array([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0}),
+		dstPrime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	bi, err := hash(b0, uints.NewU8Array([]byte{1}), dstPrime)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]uints.U8, 0, ell*bInBytes)
+	res = append(res, bi...)
+	for i := 2; i <= ell; i++ {
+		// b0 ⊕ bᵢ₋₁, processed by words to use the lookup tables
+		x := make([]uints.U8, 0, bInBytes)
+		for j := 0; j < bInBytes; j += 4 {
+			w := uapi.Xor(uapi.PackLSB(b0[j:j+4]...), uapi.PackLSB(bi[j:j+4]...))
+			x = append(x, uapi.UnpackLSB(w)...)
+		}
+		if bi, err = hash(x, uints.NewU8Array([]byte{byte(i)}), dstPrime); err != nil {
+			return nil, err
+		}
+		res = append(res, bi...)
+	}
+	return res[:lenInBytes], nil
+}
+
+// hashToField implements hash_to_field for two elements of Fp2, as defined in
+// RFC 9380 section 5.2.
+func (g2 *G2) hashToField(msg []uints.U8, dst []byte) ([2]fields_bls12381.E2, error) {
+	const L = 64
+	var res [2]fields_bls12381.E2
+	uniform, err := g2.expandMsgXmd(msg, dst, 4*L)
+	if err != nil {
+		return res, fmt.Errorf("expand message: %w", err)
+	}
+	shift := emulated.ValueOf[emulated.BLS12381Fp](new(big.Int).Lsh(big.NewInt(1), 8*L/2))
+	// OS2IP of L bytes reduced modulo p
+	toField := func(b []uints.U8) *emulated.Element[emulated.BLS12381Fp] {
+		hi := g2.fp.FromBits(g2.toBitsLE(b[:L/2])...)
+		lo := g2.fp.FromBits(g2.toBitsLE(b[L/2:])...)
+		return g2.fp.Add(g2.fp.Mul(hi, &shift), lo)
+	}
+	for i := range res {
+		res[i].A0 = *toField(uniform[(2*i)*L : (2*i+1)*L])
+		res[i].A1 = *toField(uniform[(2*i+1)*L : (2*i+2)*L])
+	}
+	return res, nil
+}
+
+// toBitsLE returns the little-endian bits of a big-endian byte string.
+func (g2 *G2) toBitsLE(b []uints.U8) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(b))
+	for i := len(b) - 1; i >= 0; i-- {
+		res = append(res, g2.api.ToBinary(b[i].Val, 8)...)
+	}
+	return res
+}
+
+// mapToCurve implements the simplified SWU map to the curve E2' which is
+// 3-isogenous to the twist (RFC 9380 section 6.6.2).
+func (g2 *G2) mapToCurve(u *fields_bls12381.E2) (*G2Affine, error) {
+	A, B, Z := newE2(sswuA), newE2(sswuB), newE2(sswuZ)
+	g := func(x *fields_bls12381.E2) *fields_bls12381.E2 {
+		// x³ + A'x + B'
+		r := g2.Ext2.Square(x)
+		r = g2.Ext2.Add(r, A)
+		r = g2.Ext2.Mul(r, x)
+		return g2.Ext2.Add(r, B)
+	}
+
+	// x₁ = -B'/A' (1 + 1/(Z²u⁴ + Zu²))
+	tv1 := g2.Ext2.Mul(Z, g2.Ext2.Square(u))
+	tv2 := g2.Ext2.Add(g2.Ext2.Square(tv1), tv1)
+	num := g2.Ext2.Mul(B, g2.Ext2.Add(tv2, g2.Ext2.One()))
+	den := g2.Ext2.Neg(g2.Ext2.Mul(A, tv2))
+	x1 := g2.Ext2.DivUnchecked(num, den)
+	gx1 := g(x1)
+	// x₂ = Zu²x₁ and g(x₂) = Z³u⁶g(x₁)
+	x2 := g2.Ext2.Mul(tv1, x1)
+	gx2 := g(x2)
+
+	// Z is not a square in Fp2, so exactly one of g(x₁) and g(x₂) is a
+	// square and the prover can't choose the wrong one.
+	isSquare, err := g2.api.Compiler().NewHint(isSquareE2Hint, 1, append(gx1.A0.Limbs, gx1.A1.Limbs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("is square hint: %w", err)
+	}
+	g2.api.AssertIsBoolean(isSquare[0])
+	x := g2.Ext2.Select(isSquare[0], x1, x2)
+	gx := g2.Ext2.Select(isSquare[0], gx1, gx2)
+
+	y, err := g2.fp.NewHint(sqrtE2Hint, 2, &gx.A0, &gx.A1, &u.A0, &u.A1)
+	if err != nil {
+		return nil, fmt.Errorf("sqrt hint: %w", err)
+	}
+	res := &G2Affine{X: *x, Y: fields_bls12381.E2{A0: *y[0], A1: *y[1]}}
+	g2.Ext2.AssertIsEqual(g2.Ext2.Square(&res.Y), gx)
+	g2.api.AssertIsEqual(g2.sgn0(&res.Y), g2.sgn0(u))
+	return res, nil
+}
+
+// sgn0 returns the sign of x as defined in RFC 9380 section 4.1.
+func (g2 *G2) sgn0(x *fields_bls12381.E2) frontend.Variable {
+	s0, z0 := g2.parity(&x.A0)
+	s1, _ := g2.parity(&x.A1)
+	// s₀ ∨ (z₀ ∧ s₁), where s₀ = 0 when z₀ = 1
+	return g2.api.Add(s0, g2.api.Mul(z0, s1))
+}
+
+// parity returns the least significant bit of the canonical representation
+// of x and whether x is zero.
+func (g2 *G2) parity(x *emulated.Element[emulated.BLS12381Fp]) (bit, isZero frontend.Variable) {
+	r := g2.fp.Reduce(x)
+	g2.fp.AssertIsInRange(r)
+	bits := g2.api.ToBinary(r.Limbs[0], int(emulated.BLS12381Fp{}.BitsPerLimb()))
+	return bits[0], g2.api.IsZero(g2.api.Add(r.Limbs[0], r.Limbs[1], r.Limbs[2:]...))
+}
+
+// isogeny maps a point of E2' to the twist.
+func (g2 *G2) isogeny(p *G2Affine) *G2Affine {
+	eval := func(coeffs [][2]string, monic bool, x *fields_bls12381.E2) *fields_bls12381.E2 {
+		res := newE2(coeffs[len(coeffs)-1])
+		if monic {
+			res = g2.Ext2.Add(res, x)
+		}
+		for i := len(coeffs) - 2; i >= 0; i-- {
+			res = g2.Ext2.Mul(res, x)
+			res = g2.Ext2.Add(res, newE2(coeffs[i]))
+		}
+		return res
+	}
+	xNum := eval(isoXNum[:], false, &p.X)
+	xDen := eval(isoXDen[:], true, &p.X)
+	yNum := eval(isoYNum[:], false, &p.X)
+	yDen := eval(isoYDen[:], true, &p.X)
+	return &G2Affine{
+		X: *g2.Ext2.DivUnchecked(xNum, xDen),
+		Y: *g2.Ext2.Mul(&p.Y, g2.Ext2.DivUnchecked(yNum, yDen)),
+	}
+}
+
+// clearCofactor multiplies p by the effective cofactor h_eff of RFC 9380
+// section 8.8.2, as [x²-x-1]p + [x-1]ψ(p) + ψ²([2]p) where x is the seed of
+// the curve (Budroni-Pintore).
+func (g2 *G2) clearCofactor(p *G2Affine) *G2Affine {
+	xP := g2.scalarMulBySeed(p)
+	xxP := g2.scalarMulBySeed(xP)
+	res := g2.sub(g2.sub(xxP, xP), p)
+	res = g2.add(res, g2.psi(g2.sub(xP, p)))
+	// ψ²(Q) = (wQ.x, -Q.y) with w a primitive cube root of unity
+	p2 := g2.double(p)
+	psi2 := &G2Affine{
+		X: *g2.Ext2.MulByElement(&p2.X, g2.w),
+		Y: *g2.Ext2.Neg(&p2.Y),
+	}
+	return g2.add(res, psi2)
+}
@@ -0,0 +1,84 @@
+package sw_bls12381
+
+import (
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		isSquareE2Hint,
+		sqrtE2Hint,
+	}
+}
+
+// isSquareE2Hint returns 1 if the Fp2 element given by the limbs of its
+// coordinates is a square and 0 otherwise.
+func isSquareE2Hint(_ *big.Int, inputs, outputs []*big.Int) error {
+	var fp emulated.BLS12381Fp
+	nbLimbs := int(fp.NbLimbs())
+	if len(inputs) != 2*nbLimbs || len(outputs) != 1 {
+		return fmt.Errorf("expected %d inputs and 1 output", 2*nbLimbs)
+	}
+	var x bls12381.E2
+	var c big.Int
+	for i, a := range []*big.Int{new(big.Int), new(big.Int)} {
+		for j := nbLimbs - 1; j >= 0; j-- {
+			a.Lsh(a, fp.BitsPerLimb())
+			a.Add(a, inputs[i*nbLimbs+j])
+		}
+		c.Mod(a, fp.Modulus())
+		if i == 0 {
+			x.A0.SetBigInt(&c)
+		} else {
+			x.A1.SetBigInt(&c)
+		}
+	}
+	if x.Legendre() >= 0 {
+		outputs[0].SetUint64(1)
+	} else {
+		outputs[0].SetUint64(0)
+	}
+	return nil
+}
+
+// sqrtE2Hint returns the square root of the first Fp2 input with the same
+// sign as the second input, as in the SSWU map.
+func sqrtE2Hint(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+	return emulated.UnwrapHint(nativeInputs, nativeOutputs,
+		func(mod *big.Int, inputs, outputs []*big.Int) error {
+			var x, u, y bls12381.E2
+			x.A0.SetBigInt(inputs[0])
+			x.A1.SetBigInt(inputs[1])
+			u.A0.SetBigInt(inputs[2])
+			u.A1.SetBigInt(inputs[3])
+			if x.Legendre() < 0 {
+				return fmt.Errorf("not a square")
+			}
+			y.Sqrt(&x)
+			if sgn0(&y) != sgn0(&u) {
+				y.Neg(&y)
+			}
+			y.A0.BigInt(outputs[0])
+			y.A1.BigInt(outputs[1])
+			return nil
+		})
+}
+
+// sgn0 returns the sign of z as defined in RFC 9380 section 4.1.
+func sgn0(z *bls12381.E2) uint64 {
+	a0, a1 := z.A0.Bits(), z.A1.Bits()
+	if z.A0.IsZero() {
+		return a1[0] & 1
+	}
+	return a0[0] & 1
+}
@@ -400,6 +400,45 @@ func TestVarScalarMulBaseG1(t *testing.T) {
 	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithCurves(ecc.BW6_761))
 }
 
+// -------------------------------------------------------------------------------------------------
+// Decompress
+
+type g1Decompress struct {
+	X, Sign frontend.Variable
+	P       G1Affine
+}
+
+func (circuit *g1Decompress) Define(api frontend.API) error {
+	var p G1Affine
+	p.Decompress(api, circuit.X, circuit.Sign)
+	p.AssertIsEqual(api, circuit.P)
+	return nil
+}
+
+func TestDecompressG1(t *testing.T) {
+
+	// sample a random point
+	a := randomPointG1()
+	var aAffine bls12377.G1Affine
+	aAffine.FromJacobian(&a)
+	sign := 0
+	if aAffine.Y.LexicographicallyLargest() {
+		sign = 1
+	}
+
+	var circuit, witness, invalidWitness g1Decompress
+	witness.X = aAffine.X.String()
+	witness.Sign = sign
+	witness.P.Assign(&aAffine)
+	invalidWitness.X = aAffine.X.String()
+	invalidWitness.Sign = 1 - sign
+	invalidWitness.P.Assign(&aAffine)
+
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&witness), test.WithInvalidAssignment(&invalidWitness), test.WithCurves(ecc.BW6_761))
+
+}
+
 func randomPointG1() bls12377.G1Jac {
 
 	p1, _, _, _ := bls12377.Generators()
@@ -24,6 +24,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/sqrt"
 )
 
 // G1Jac point in Jacobian coords
@@ -417,6 +418,18 @@ func (p *G1Affine) AssertIsEqual(api frontend.API, other G1Affine) {
 	api.AssertIsEqual(p.Y, other.Y)
 }
 
+// Decompress sets p to the point of the curve with the coordinate x, whose y
+// coordinate is lexicographically largest if sign is 1 and smallest otherwise.
+// This is the compressed form of the points in gnark-crypto, out of the point
+// at infinity. It fails if there is no such point, but doesn't check that the
+// point is in G1.
+func (p *G1Affine) Decompress(api frontend.API, x, sign frontend.Variable) *G1Affine {
+	// y² = x³ + 1
+	p.X = x
+	p.Y = sqrt.SqrtWithSign(api, api.Add(api.Mul(x, x, x), 1), sign)
+	return p
+}
+
 // DoubleAndAdd computes 2*p1+p in affine coords
 func (p *G1Affine) DoubleAndAdd(api frontend.API, p1, p2 *G1Affine) *G1Affine {
 
@@ -90,6 +90,52 @@ func TestIsOnCurve(t *testing.T) {
 
 }
 
+type decompressCircuit struct {
+	curveID twistededwards.ID
+	Y, Sign frontend.Variable
+	X       frontend.Variable
+}
+
+func (circuit *decompressCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	p := curve.Decompress(circuit.Y, circuit.Sign)
+	api.AssertIsEqual(p.X, circuit.X)
+	curve.AssertIsOnCurve(p)
+	return nil
+}
+
+func TestDecompress(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range curves {
+		circuit := decompressCircuit{curveID: curve}
+		snarkField, err := GetSnarkField(curve)
+		assert.NoError(err)
+		snarkCurve := utils.FieldToCurve(snarkField)
+		params, err := GetCurveParams(curve)
+		assert.NoError(err)
+
+		// the base point and its negation
+		x, y := params.Base[0], params.Base[1]
+		negX := new(big.Int).Sub(snarkField, x)
+		half := new(big.Int).Rsh(snarkField, 1)
+		sign := 0
+		if x.Cmp(half) > 0 {
+			sign = 1
+		}
+
+		assert.CheckCircuit(&circuit,
+			test.WithValidAssignment(&decompressCircuit{Y: y, Sign: sign, X: x}),
+			test.WithValidAssignment(&decompressCircuit{Y: y, Sign: 1 - sign, X: negX}),
+			test.WithInvalidAssignment(&decompressCircuit{Y: y, Sign: 1 - sign, X: x}),
+			test.WithInvalidAssignment(&decompressCircuit{Y: y, Sign: 2, X: x}),
+			test.WithCurves(snarkCurve))
+	}
+}
+
 type addCircuit struct {
 	curveID               twistededwards.ID
 	P1, P2                Point
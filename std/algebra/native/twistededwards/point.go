@@ -18,6 +18,7 @@ package twistededwards
 
 import (
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/sqrt"
 )
 
 // neg computes the negative of a point in SNARK coordinates
@@ -44,6 +45,20 @@ func (p *Point) assertIsOnCurve(api frontend.API, curve *CurveParams) {
 
 }
 
+// decompress sets p to the point of the curve with the coordinate y, whose
+// x coordinate is lexicographically largest if sign is 1 and smallest
+// otherwise. This is the compressed form of the points in gnark-crypto, it
+// fails if there is no such point.
+func (p *Point) decompress(api frontend.API, y, sign frontend.Variable, curve *CurveParams) *Point {
+	// x² = (1 - y²) / (a - d*y²), where the denominator is never zero as a/d
+	// is not a square
+	yy := api.Mul(y, y)
+	xx := api.Div(api.Sub(1, yy), api.Sub(curve.A, api.Mul(yy, curve.D)))
+	p.X = sqrt.SqrtWithSign(api, xx, sign)
+	p.Y = y
+	return p
+}
+
 // add Adds two points on a twisted edwards curve (eg jubjub)
 // p1, p2, c are respectively: the point to add, a known base point, and the parameters of the twisted edwards curve
 func (p *Point) add(api frontend.API, p1, p2 *Point, curve *CurveParams) *Point {
@@ -24,10 +24,38 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// The scalar s is decomposed into s1 and s2 of glvNbBits bits such that
+//
+//	-s1 + λ * s2 == s mod Order
+//
+// Additionally, s2 ≡ 0 and s1 ≡ -s mod 4, so that the decomposition is also
+// correct on the points of order dividing the cofactor. The decomposition is
+// constrained by checking the integer equation
+//
+//	λ * s2 + glvOffset * Order = s + s1 + k * Order
+//
+// for 0 ≤ k < 2^glvNbBitsK, both modulo the native field and modulo 2^glvNbBitsCRT.
+// The absolute value of the difference of both sides is smaller than
+// 2^385 < r * 2^glvNbBitsCRT, so it is zero.
+const (
+	glvNbBits    = 130
+	glvNbBitsK   = 132
+	glvNbBitsCRT = 136
+	glvOffset    = 8
+	// glvLimb is the bit size of the lower limbs of s2 and k when computing
+	// modulo 2^glvNbBitsCRT, so that all products fit in the native field.
+	glvLimb = glvNbBitsCRT / 2
+	// glvNbBitsCarry bounds the quotient of the low parts by 2^glvNbBitsCRT.
+	glvNbBitsCarry = 70
 )
 
 // phi endomorphism √-2 ∈ 𝒪₋₈
 // (x,y) → λ × (x,y) s.t. λ² = -2 mod Order
+// The point must not be of low order, see [isLowOrder].
 func (p *Point) phi(api frontend.API, p1 *Point, curve *CurveParams, endo *EndoParams) *Point {
 
 	xy := api.Mul(p1.X, p1.Y)
@@ -44,38 +72,77 @@ func (p *Point) phi(api frontend.API, p1 *Point, curve *CurveParams, endo *EndoP
 	return p
 }
 
+// isLowOrder returns 1 if p1 is one of the points where phi isn't defined,
+// which are the points of order dividing 4, and 0 otherwise.
+func isLowOrder(api frontend.API, p1 *Point, endo *EndoParams) frontend.Variable {
+	xy := api.Mul(p1.X, p1.Y)
+	h := api.Sub(api.Mul(p1.Y, p1.Y), endo.Endo[0])
+	return api.IsZero(api.Mul(xy, h))
+}
+
 type glvParams struct {
 	lambda, order big.Int
 	glvBasis      ecc.Lattice
 }
 
+var (
+	bandersnatchGLV     glvParams
+	bandersnatchGLVOnce sync.Once
+)
+
+// DecomposeScalar returns s1, s2 and k of the decomposition of the scalar used
+// in the GLV scalar multiplication. The input is the scalar.
 var DecomposeScalar = func(scalarField *big.Int, inputs []*big.Int, res []*big.Int) error {
 	// the efficient endomorphism exists on Bandersnatch only
 	if scalarField.Cmp(ecc.BLS12_381.ScalarField()) != 0 {
 		return errors.New("no efficient endomorphism is available on this curve")
 	}
-	var glv glvParams
-	var init sync.Once
-	init.Do(func() {
+	glv := &bandersnatchGLV
+	bandersnatchGLVOnce.Do(func() {
 		glv.lambda.SetString("8913659658109529928382530854484400854125314752504019737736543920008458395397", 10)
 		glv.order.SetString("13108968793781547619861935127046491459309155893440570251786403306729687672801", 10)
 		ecc.PrecomputeLattice(&glv.order, &glv.lambda, &glv.glvBasis)
 	})
+	s := inputs[0]
+
+	// sp[0] + λ * sp[1] == s mod Order with sp[0], sp[1] of about 128 bits. We
+	// add small combinations of the vectors of the lattice basis to find s1 =
+	// -sp[0] and s2 = sp[1] which are positive, and have the residues modulo 4
+	// ensuring that the decomposition is correct on the low order points.
+	sp := ecc.SplitScalar(new(big.Int).Mod(s, &glv.order), &glv.glvBasis)
+	var s1, s2, t big.Int
+	check := func(c1, c2 int64) bool {
+		s1.Mul(big.NewInt(c1), &glv.glvBasis.V1[0])
+		s1.Add(&s1, t.Mul(big.NewInt(c2), &glv.glvBasis.V2[0]))
+		s1.Add(&s1, &sp[0]).Neg(&s1)
+		s2.Mul(big.NewInt(c1), &glv.glvBasis.V1[1])
+		s2.Add(&s2, t.Mul(big.NewInt(c2), &glv.glvBasis.V2[1]))
+		s2.Add(&s2, &sp[1])
+		return s1.Sign() >= 0 && s2.Sign() >= 0 &&
+			s1.BitLen() <= glvNbBits && s2.BitLen() <= glvNbBits &&
+			s2.Bit(0) == 0 && s2.Bit(1) == 0 &&
+			t.Add(&s1, s).Bit(0) == 0 && t.Bit(1) == 0
+	}
+	found := false
+	for m := int64(0); m <= 8 && !found; m++ {
+		for c1 := -m; c1 <= m && !found; c1++ {
+			for c2 := -m; c2 <= m && !found; c2++ {
+				if (c1 == m || c1 == -m || c2 == m || c2 == -m) && check(c1, c2) {
+					found = true
+				}
+			}
+		}
+	}
+	if !found {
+		return errors.New("no decomposition found")
+	}
+	res[0].Set(&s1)
+	res[1].Set(&s2)
 
-	// sp[0] is always negative because, in SplitScalar(), we always round above
-	// the determinant/2 computed in PrecomputeLattice() which is negative for Bandersnatch.
-	// Thus taking -sp[0] here and negating the point in ScalarMul().
-	// If we keep -sp[0] it will be reduced mod r (the BLS12-381 prime order)
-	// and not the Bandersnatch prime order (Order) and the result will be incorrect.
-	// Also, if we reduce it mod Order here, we can't use api.ToBinary(sp[0], 129)
-	// and hence we can't reduce optimally the number of constraints.
-	sp := ecc.SplitScalar(inputs[0], &glv.glvBasis)
-	res[0].Neg(&(sp[0]))
-	res[1].Set(&(sp[1]))
-
-	// figure out how many times we have overflowed
-	res[2].Mul(res[1], &glv.lambda).Sub(res[2], res[0])
-	res[2].Sub(res[2], inputs[0])
+	// k = (λ * s2 + glvOffset * Order - s - s1) / Order
+	res[2].Mul(&s2, &glv.lambda)
+	res[2].Add(res[2], t.Mul(big.NewInt(glvOffset), &glv.order))
+	res[2].Sub(res[2], s).Sub(res[2], &s1)
 	res[2].Div(res[2], &glv.order)
 
 	return nil
@@ -85,37 +152,109 @@ func init() {
 	solver.RegisterHint(DecomposeScalar)
 }
 
-// ScalarMul computes the scalar multiplication of a point on a twisted Edwards curve
-// p1: base point (as snark point)
-// curve: parameters of the Edwards curve
-// scal: scalar as a SNARK constraint
-// Standard left to right double and add
-func (p *Point) scalarMulGLV(api frontend.API, p1 *Point, scalar frontend.Variable, curve *CurveParams, endo *EndoParams) *Point {
-	// the hints allow to decompose the scalar s into s1 and s2 such that
-	// s1 + λ * s2 == s mod Order,
-	// with λ s.t. λ² = -2 mod Order.
-	sd, err := api.NewHint(DecomposeScalar, 3, scalar)
+// decomposeScalarGLV returns the bits of the decomposition (s1, s2) of scalar
+// and the two low bits of scalar, see [DecomposeScalar].
+func decomposeScalarGLV(api frontend.API, scalar frontend.Variable, curve *CurveParams, endo *EndoParams) (b1, b2, sLow []frontend.Variable) {
+	sd, err := api.Compiler().NewHint(DecomposeScalar, 3, scalar)
 	if err != nil {
 		// err is non-nil only for invalid number of inputs
 		panic(err)
 	}
+	s1, s2, k := sd[0], sd[1], sd[2]
+	rchecker := rangecheck.New(api)
+	b1 = api.ToBinary(s1, glvNbBits)
+	b2 = api.ToBinary(s2, glvNbBits)
+	kLo, kHi := bitslice.Partition(api, k, glvLimb, bitslice.WithNbDigits(glvNbBitsK))
 
-	s1, s2 := sd[0], sd[1]
+	// split the scalar into its canonical low and high parts, with
+	// sLo + 2^glvNbBitsCRT * sHi < r.
+	r := api.Compiler().Field()
+	fieldBits := api.Compiler().FieldBitLen()
+	sLo, sHi := bitslice.Partition(api, scalar, glvNbBitsCRT, bitslice.WithNbDigits(fieldBits))
+	rLo, rHi := new(big.Int), new(big.Int)
+	rHi.QuoRem(r, new(big.Int).Lsh(big.NewInt(1), glvNbBitsCRT), rLo)
+	rchecker.Check(api.Sub(rHi, sHi), fieldBits-glvNbBitsCRT)
+	eqHi := api.IsZero(api.Sub(rHi, sHi))
+	rchecker.Check(api.Mul(eqHi, api.Sub(rLo, 1, sLo)), glvNbBitsCRT)
 
-	// -s1 + λ * s2 == s + k*Order
-	api.AssertIsEqual(api.Sub(api.Mul(s2, endo.Lambda), s1), api.Add(scalar, api.Mul(curve.Order, sd[2])))
+	// modulo r
+	api.AssertIsEqual(
+		api.Add(api.Mul(s2, endo.Lambda), api.Mul(curve.Order, glvOffset)),
+		api.Add(scalar, s1, api.Mul(curve.Order, k)),
+	)
 
-	// Normally s1 and s2 are of the max size sqrt(Order) = 128
-	// But in a circuit, we force s1 to be negative by rounding always above.
-	// This changes the size bounds to 2*sqrt(Order) = 129.
-	n := 129
+	// modulo 2^glvNbBitsCRT
+	mod := new(big.Int).Lsh(big.NewInt(1), glvNbBitsCRT)
+	modLimb := new(big.Int).Lsh(big.NewInt(1), glvLimb)
+	lambdaLo := new(big.Int).Mod(endo.Lambda, mod)
+	orderLo := new(big.Int).Mod(curve.Order, mod)
+	offsetLo := new(big.Int).Mul(curve.Order, big.NewInt(glvOffset))
+	offsetLo.Mod(offsetLo, mod)
+	// for x = xLo + 2^glvLimb * xHi, c * x ≡ c * xLo + 2^glvLimb * (c mod 2^glvLimb) * xHi
+	mulLo := func(c *big.Int, xLo, xHi frontend.Variable) frontend.Variable {
+		cLimb := new(big.Int).Mod(c, modLimb)
+		cLimb.Mul(cLimb, modLimb)
+		return api.Add(api.Mul(xLo, c), api.Mul(xHi, cLimb))
+	}
+	s2Lo := api.FromBinary(b2[:glvLimb]...)
+	s2Hi := api.FromBinary(b2[glvLimb:]...)
+	// y = lhs - rhs + 2^(glvNbBitsCRT+glvNbBitsCarry-1) is non-negative and
+	// y = c * 2^glvNbBitsCRT
+	bias := new(big.Int).Lsh(big.NewInt(1), glvNbBitsCRT+glvNbBitsCarry-1)
+	y := api.Sub(
+		api.Add(mulLo(lambdaLo, s2Lo, s2Hi), offsetLo, bias),
+		api.Add(sLo, s1, mulLo(orderLo, kLo, kHi)),
+	)
+	carry, err := api.Compiler().NewHint(divPow2Hint, 1, y, glvNbBitsCRT)
+	if err != nil {
+		panic(err)
+	}
+	rchecker.Check(carry[0], glvNbBitsCarry)
+	api.AssertIsEqual(y, api.Mul(carry[0], mod))
 
-	b1 := api.ToBinary(s1, n)
-	b2 := api.ToBinary(s2, n)
+	sLow2, _ := bitslice.Partition(api, sLo, 2, bitslice.WithNbDigits(glvNbBitsCRT))
+	sLow = api.ToBinary(sLow2, 2)
 
+	// s2 ≡ 0 mod 4 and s1 + s ≡ 0 mod 4
+	api.AssertIsEqual(b2[0], 0)
+	api.AssertIsEqual(b2[1], 0)
+	sum := api.Add(api.FromBinary(b1[:2]...), sLow2)
+	api.AssertIsEqual(api.Mul(sum, api.Sub(sum, 4)), 0)
+
+	return b1, b2, sLow
+}
+
+// divPow2Hint returns inputs[0] / 2^inputs[1].
+func divPow2Hint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 1 {
+		return errors.New("expecting two inputs and one output")
+	}
+	outputs[0].Rsh(inputs[0], uint(inputs[1].Uint64()))
+	return nil
+}
+
+func init() {
+	solver.RegisterHint(divPow2Hint)
+}
+
+// scalarMulGLV computes the scalar multiplication of a point on a twisted
+// Edwards curve with an efficient endomorphism, using the GLV decomposition of
+// the scalar. The result is correct for all the points of the curve.
+func (p *Point) scalarMulGLV(api frontend.API, p1 *Point, scalar frontend.Variable, curve *CurveParams, endo *EndoParams) *Point {
+	b1, b2, sLow := decomposeScalarGLV(api, scalar, curve, endo)
+
+	// phi is not defined on the low order points. For them we instead compute
+	// the multiplication of the base point, and select [s mod 4] p1 at the
+	// end.
+	lowOrder := isLowOrder(api, p1, endo)
+	var q Point
+	q.X = api.Select(lowOrder, curve.Base[0], p1.X)
+	q.Y = api.Select(lowOrder, curve.Base[1], p1.Y)
+
+	n := glvNbBits
 	var res, _p1, p2, p3, tmp Point
-	_p1.neg(api, p1)
-	p2.phi(api, p1, curve, endo)
+	_p1.neg(api, &q)
+	p2.phi(api, &q, curve, endo)
 	p3.add(api, &_p1, &p2, curve)
 
 	res.X = api.Lookup2(b1[n-1], b2[n-1], 0, _p1.X, p2.X, p3.X)
@@ -128,8 +267,16 @@ func (p *Point) scalarMulGLV(api frontend.API, p1 *Point, scalar frontend.Variab
 		res.add(api, &res, &tmp, curve)
 	}
 
-	p.X = res.X
-	p.Y = res.Y
+	// [s mod 4] p1 for the low order points
+	var p1x2 Point
+	p1x2.double(api, p1, curve)
+	low := Point{
+		X: api.Lookup2(sLow[0], sLow[1], 0, p1.X, p1x2.X, api.Neg(p1.X)),
+		Y: api.Lookup2(sLow[0], sLow[1], 1, p1.Y, p1x2.Y, p1.Y),
+	}
+
+	p.X = api.Select(lowOrder, low.X, res.X)
+	p.Y = api.Select(lowOrder, low.Y, res.Y)
 
 	return p
 }
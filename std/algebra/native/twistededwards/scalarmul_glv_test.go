@@ -0,0 +1,104 @@
+package twistededwards
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type scalarMulGLVCircuit struct {
+	P, Expected Point
+	S           frontend.Variable
+}
+
+func (c *scalarMulGLVCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, twistededwards.BLS12_381_BANDERSNATCH)
+	if err != nil {
+		return err
+	}
+	res := curve.ScalarMul(c.P, c.S)
+	api.AssertIsEqual(res.X, c.Expected.X)
+	api.AssertIsEqual(res.Y, c.Expected.Y)
+	return nil
+}
+
+// scalarMulNaive returns [s]p with the double-and-add algorithm, which is
+// correct for all the points of the curve.
+func scalarMulNaive(p *bandersnatch.PointAffine, s *big.Int) bandersnatch.PointAffine {
+	var res bandersnatch.PointAffine
+	res.X.SetZero()
+	res.Y.SetOne()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res.Double(&res)
+		if s.Bit(i) == 1 {
+			res.Add(&res, p)
+		}
+	}
+	return res
+}
+
+func TestScalarMulGLV(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := bandersnatch.GetEdwardsCurve()
+	r := ecc.BLS12_381.ScalarField()
+
+	// points of all the orders dividing the cofactor, and their sums with a
+	// point of prime order
+	var identity, t2, t4 bandersnatch.PointAffine
+	identity.Y.SetOne()
+	t2.Y.SetOne()
+	t2.Y.Neg(&t2.Y)
+	points := []bandersnatch.PointAffine{params.Base, identity, t2}
+	// (±1/√a, 0) are of order 4 when a is a square
+	t4.X.Inverse(&params.A)
+	if t4.X.Sqrt(&t4.X) != nil {
+		points = append(points, t4)
+	}
+	var g bandersnatch.PointAffine
+	g.ScalarMultiplication(&params.Base, big.NewInt(1234567))
+	for _, p := range points[1:] {
+		var q bandersnatch.PointAffine
+		q.Add(&g, &p)
+		points = append(points, q)
+	}
+
+	scalars := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4),
+		new(big.Int).Set(&params.Order),
+		new(big.Int).Sub(&params.Order, big.NewInt(1)),
+		new(big.Int).Sub(r, big.NewInt(1)),
+	}
+	for i := 0; i < 4; i++ {
+		s, err := rand.Int(rand.Reader, r)
+		assert.NoError(err)
+		scalars = append(scalars, s)
+	}
+
+	for _, p := range points {
+		for _, s := range scalars {
+			expected := scalarMulNaive(&p, s)
+			err := test.IsSolved(&scalarMulGLVCircuit{}, &scalarMulGLVCircuit{
+				P:        Point{X: p.X, Y: p.Y},
+				S:        s,
+				Expected: Point{X: expected.X, Y: expected.Y},
+			}, ecc.BLS12_381.ScalarField())
+			assert.NoError(err)
+		}
+	}
+
+	var wrong fr.Element
+	wrong.SetOne()
+	err := test.IsSolved(&scalarMulGLVCircuit{}, &scalarMulGLVCircuit{
+		P:        Point{X: g.X, Y: g.Y},
+		S:        1,
+		Expected: Point{X: wrong, Y: g.Y},
+	}, ecc.BLS12_381.ScalarField())
+	assert.Error(err)
+}
@@ -41,6 +41,7 @@ type Curve interface {
 	Double(p1 Point) Point
 	Neg(p1 Point) Point
 	AssertIsOnCurve(p1 Point)
+	Decompress(y, sign frontend.Variable) Point
 	ScalarMul(p1 Point, scalar frontend.Variable) Point
 	DoubleBaseScalarMul(p1, p2 Point, s1, s2 frontend.Variable) Point
 	API() frontend.API
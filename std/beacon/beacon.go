@@ -0,0 +1,126 @@
+// Package beacon verifies the rounds of a drand public randomness beacon
+// in-circuit.
+//
+// A round of a drand chained network (pedersen-bls-chained, the default chain
+// of the drand mainnet) is a BLS signature over BLS12-381 of the message
+// sha256(previous signature ‖ round), and its randomness is the hash of the
+// signature. Verifying the round in-circuit allows to prove statements about
+// the public randomness at a given round, for example that a lottery winner
+// was drawn from it.
+//
+// The native counterpart, which fetches and verifies the rounds, is
+// [github.com/consensys/gnark/backend/beacon]. The pairing over BLS12-381 is
+// emulated, so the circuit is large (a few million constraints over BN254).
+package beacon
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend/beacon"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/bls"
+)
+
+const (
+	// RandomnessSize is the size in bytes of the randomness of a round.
+	RandomnessSize = 32
+	// SignatureSize is the size in bytes of a compressed signature.
+	SignatureSize = bls.SignatureSize
+)
+
+// Beacon is the in-circuit representation of a round of a drand chained
+// network. The signatures are compressed points of G2, as returned by the
+// drand API.
+type Beacon struct {
+	Round             frontend.Variable
+	Randomness        [RandomnessSize]uints.U8
+	Signature         [SignatureSize]uints.U8
+	PreviousSignature [SignatureSize]uints.U8
+}
+
+// ValueOf returns the witness assignment of the native beacon b.
+func ValueOf(b *beacon.Beacon) (Beacon, error) {
+	var res Beacon
+	if len(b.Randomness) != RandomnessSize {
+		return res, fmt.Errorf("invalid randomness length %d", len(b.Randomness))
+	}
+	if len(b.Signature) != SignatureSize || len(b.PreviousSignature) != SignatureSize {
+		return res, fmt.Errorf("invalid signature length")
+	}
+	res.Round = b.Round
+	copy(res.Randomness[:], uints.NewU8Array(b.Randomness))
+	copy(res.Signature[:], uints.NewU8Array(b.Signature))
+	copy(res.PreviousSignature[:], uints.NewU8Array(b.PreviousSignature))
+	return res, nil
+}
+
+// Verifier verifies the rounds of a drand network in-circuit.
+type Verifier struct {
+	api frontend.API
+	bls *bls.Verifier
+	u64 *uints.BinaryField[uints.U64]
+	pk  *sw_bls12381.G1Affine
+}
+
+// NewVerifier returns a verifier of the rounds signed by the network with the
+// given public key. The public key is trusted: it is usually a constant such
+// as [MainnetPublicKey], otherwise the caller must check that it is in G1.
+func NewVerifier(api frontend.API, publicKey *sw_bls12381.G1Affine) (*Verifier, error) {
+	verifier, err := bls.NewVerifier(api)
+	if err != nil {
+		return nil, fmt.Errorf("new bls verifier: %w", err)
+	}
+	u64, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	return &Verifier{api: api, bls: verifier, u64: u64, pk: publicKey}, nil
+}
+
+// MainnetPublicKey returns the public key of the default chain of the drand
+// mainnet, to be used as a constant in the circuit.
+func MainnetPublicKey() sw_bls12381.G1Affine {
+	b, err := hex.DecodeString(beacon.MainnetPublicKey)
+	if err != nil {
+		panic(err)
+	}
+	var pk bls12381.G1Affine
+	if _, err := pk.SetBytes(b); err != nil {
+		panic(err)
+	}
+	return sw_bls12381.NewG1Affine(pk)
+}
+
+// AssertIsValid asserts that the signature of the round b is valid and that
+// its randomness is the hash of the signature.
+func (v *Verifier) AssertIsValid(b *Beacon) error {
+	// randomness = sha256(signature)
+	h, err := sha2.New(v.api)
+	if err != nil {
+		return fmt.Errorf("new hash: %w", err)
+	}
+	h.Write(b.Signature[:])
+	randomness := h.Sum()
+	for i := range randomness {
+		v.u64.ByteAssertEq(randomness[i], b.Randomness[i])
+	}
+
+	// message = sha256(previous signature ‖ round)
+	if h, err = sha2.New(v.api); err != nil {
+		return fmt.Errorf("new hash: %w", err)
+	}
+	h.Write(b.PreviousSignature[:])
+	round := v.u64.ValueOf(b.Round)
+	v.api.AssertIsEqual(v.u64.ToValue(round), b.Round)
+	h.Write(v.u64.UnpackMSB(round))
+	sig, err := v.bls.SignatureFromBytes(&b.Signature)
+	if err != nil {
+		return fmt.Errorf("decompress signature: %w", err)
+	}
+	return v.bls.AssertIsValid(v.pk, h.Sum(), sig, []byte(beacon.DST))
+}
@@ -0,0 +1,78 @@
+package beacon
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend/beacon"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/test"
+)
+
+type verifyCircuit struct {
+	PublicKey sw_bls12381.G1Affine
+	Beacon    Beacon
+}
+
+func (c *verifyCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api, &c.PublicKey)
+	if err != nil {
+		return err
+	}
+	return v.AssertIsValid(&c.Beacon)
+}
+
+// testRounds returns chained rounds signed with a fixed secret key.
+func testRounds(t *testing.T, nbRounds int) (bls12381.G1Affine, []*beacon.Beacon) {
+	var sk big.Int
+	sk.SetString("1234567890abcdef1234567890abcdef", 16)
+	_, _, g1, _ := bls12381.Generators()
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplication(&g1, &sk)
+	var rounds []*beacon.Beacon
+	prev := make([]byte, SignatureSize)
+	for r := 1; r <= nbRounds; r++ {
+		b := &beacon.Beacon{Round: uint64(r), PreviousSignature: prev}
+		hm, err := bls12381.HashToG2(b.Message(), []byte(beacon.DST))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sig bls12381.G2Affine
+		sig.ScalarMultiplication(&hm, &sk)
+		sigBytes := sig.Bytes()
+		b.Signature = sigBytes[:]
+		randomness := sha256.Sum256(b.Signature)
+		b.Randomness = randomness[:]
+		rounds = append(rounds, b)
+		prev = b.Signature
+	}
+	return pk, rounds
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	pk, rounds := testRounds(t, 2)
+	var witness verifyCircuit
+	witness.PublicKey = sw_bls12381.NewG1Affine(pk)
+	for _, r := range rounds {
+		bcn, err := ValueOf(r)
+		assert.NoError(err)
+		witness.Beacon = bcn
+		err = test.IsSolved(&verifyCircuit{}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+
+	// the signature doesn't verify for another round
+	witness.Beacon.Round = 3
+	err := test.IsSolved(&verifyCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestMainnetPublicKey(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.NotPanics(func() { MainnetPublicKey() })
+}
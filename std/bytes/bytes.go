@@ -0,0 +1,134 @@
+// Package bytes implements comparisons of byte strings in-circuit.
+//
+// The byte strings are arrays of [uints.U8] whose length is known at circuit
+// compile time. The functions don't check that the bytes are in range, which is
+// usually already ensured by the gadget which produced them. For example, they
+// allow to prove that a decoded document contains a given field:
+//
+//	offset := bytes.Index(api, document, uints.NewU8Array([]byte(`"email":`)))
+//
+// where the offset of the field is computed by a hint and only its correctness
+// is asserted.
+package bytes
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// AssertIsEqual asserts that the byte strings a and b are equal. It panics if
+// their lengths differ.
+func AssertIsEqual(api frontend.API, a, b []uints.U8) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("lengths differ: %d != %d", len(a), len(b)))
+	}
+	for i := range a {
+		api.AssertIsEqual(a[i].Val, b[i].Val)
+	}
+}
+
+// IsEqual returns 1 if the byte strings a and b are equal and 0 otherwise.
+func IsEqual(api frontend.API, a, b []uints.U8) frontend.Variable {
+	if len(a) != len(b) {
+		return 0
+	}
+	eq := commonPrefix(api, a, b)
+	return eq[len(eq)-1]
+}
+
+// Cmp compares the byte strings a and b lexicographically and returns
+//
+//	-1 if a < b
+//	 0 if a = b
+//	+1 if a > b
+//
+// A string is smaller than the strings of which it is a proper prefix.
+func Cmp(api frontend.API, a, b []uints.U8) frontend.Variable {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	var lenCmp int
+	switch {
+	case len(a) < len(b):
+		lenCmp = -1
+	case len(a) > len(b):
+		lenCmp = 1
+	}
+	if n == 0 {
+		return lenCmp
+	}
+	eq := commonPrefix(api, a[:n], b[:n])
+	// a[i]-b[i] at the first position i where the strings differ, or 0.
+	diff := frontend.Variable(0)
+	for i := 0; i < n; i++ {
+		first := api.Sub(eq[i], eq[i+1])
+		diff = api.Add(diff, api.Mul(first, api.Sub(a[i].Val, b[i].Val)))
+	}
+	// diff is in [-255, 255] so diff+255 has 9 bits, the most significant
+	// being set iff diff > 0.
+	gt := api.ToBinary(api.Add(diff, 255), 9)[8]
+	neq := api.Sub(1, eq[n])
+	return api.Add(
+		api.Mul(neq, api.Sub(api.Mul(gt, 2), 1)),
+		api.Mul(eq[n], lenCmp),
+	)
+}
+
+// AssertIsSubslice asserts that b appears in a at the position offset, that is
+// a[offset+i] = b[i] for all i < len(b), with 0 ≤ offset ≤ len(a)-len(b). It
+// panics if b is empty or longer than a.
+func AssertIsSubslice(api frontend.API, a, b []uints.U8, offset frontend.Variable) {
+	if len(b) == 0 || len(b) > len(a) {
+		panic(fmt.Sprintf("invalid subslice length %d for length %d", len(b), len(a)))
+	}
+	t := logderivlookup.New(api)
+	for i := range a {
+		t.Insert(a[i].Val)
+	}
+	// the lookups fail for the indices outside of [0, len(a)). As the indices
+	// are consecutive, it also bounds the offset.
+	inds := make([]frontend.Variable, len(b))
+	for i := range b {
+		inds[i] = api.Add(offset, i)
+	}
+	vals := t.Lookup(inds...)
+	for i := range b {
+		api.AssertIsEqual(vals[i], b[i].Val)
+	}
+}
+
+// Index returns the offset of the first occurrence of b in a, computed by a
+// hint, and asserts that b appears in a at this offset. The circuit only
+// ensures that b appears at the returned offset, not that it is the first
+// occurrence. It panics if b is empty or longer than a.
+func Index(api frontend.API, a, b []uints.U8) frontend.Variable {
+	inputs := make([]frontend.Variable, 0, len(a)+len(b)+1)
+	inputs = append(inputs, len(a))
+	for i := range a {
+		inputs = append(inputs, a[i].Val)
+	}
+	for i := range b {
+		inputs = append(inputs, b[i].Val)
+	}
+	res, err := api.Compiler().NewHint(indexHint, 1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	AssertIsSubslice(api, a, b, res[0])
+	return res[0]
+}
+
+// commonPrefix returns eq with eq[i] = 1 if a[:i] = b[:i] and 0 otherwise, for
+// i ≤ len(a). a and b must have the same length.
+func commonPrefix(api frontend.API, a, b []uints.U8) []frontend.Variable {
+	eq := make([]frontend.Variable, len(a)+1)
+	eq[0] = 1
+	for i := range a {
+		eq[i+1] = api.Mul(eq[i], api.IsZero(api.Sub(a[i].Val, b[i].Val)))
+	}
+	return eq
+}
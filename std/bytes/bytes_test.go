@@ -0,0 +1,107 @@
+package bytes
+
+import (
+	stdbytes "bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type cmpCircuit struct {
+	A, B  []uints.U8
+	Cmp   frontend.Variable
+	Equal frontend.Variable
+}
+
+func (c *cmpCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Cmp(api, c.A, c.B), c.Cmp)
+	api.AssertIsEqual(IsEqual(api, c.A, c.B), c.Equal)
+	return nil
+}
+
+func TestCmp(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range [][2]string{
+		{"abc", "abc"},
+		{"abc", "abd"},
+		{"abd", "abc"},
+		{"ab", "abc"},
+		{"abc", "ab"},
+		{"", "a"},
+		{"\x00", ""},
+		{"\xff\x00", "\x00\xff"},
+		{"\x00\xff", "\xff\x00"},
+		{"hello world", "hello world"},
+	} {
+		a, b := []byte(tc[0]), []byte(tc[1])
+		var equal int
+		if stdbytes.Equal(a, b) {
+			equal = 1
+		}
+		circuit := &cmpCircuit{A: make([]uints.U8, len(a)), B: make([]uints.U8, len(b))}
+		assert.CheckCircuit(circuit,
+			test.WithCurves(ecc.BN254),
+			test.NoSerializationChecks(),
+			test.WithValidAssignment(&cmpCircuit{A: uints.NewU8Array(a), B: uints.NewU8Array(b), Cmp: stdbytes.Compare(a, b), Equal: equal}),
+			test.WithInvalidAssignment(&cmpCircuit{A: uints.NewU8Array(a), B: uints.NewU8Array(b), Cmp: stdbytes.Compare(a, b) + 1, Equal: equal}),
+			test.WithInvalidAssignment(&cmpCircuit{A: uints.NewU8Array(a), B: uints.NewU8Array(b), Cmp: stdbytes.Compare(a, b), Equal: 1 - equal}),
+		)
+	}
+}
+
+type subsliceCircuit struct {
+	A, B   []uints.U8
+	Offset frontend.Variable
+}
+
+func (c *subsliceCircuit) Define(api frontend.API) error {
+	AssertIsSubslice(api, c.A, c.B, c.Offset)
+	return nil
+}
+
+func TestAssertIsSubslice(t *testing.T) {
+	assert := test.NewAssert(t)
+	a, b := []byte("the quick brown fox"), []byte("quick")
+	circuit := &subsliceCircuit{A: make([]uints.U8, len(a)), B: make([]uints.U8, len(b))}
+	assignment := func(a, b []byte, offset int) *subsliceCircuit {
+		return &subsliceCircuit{A: uints.NewU8Array(a), B: uints.NewU8Array(b), Offset: offset}
+	}
+	assert.CheckCircuit(circuit,
+		test.WithCurves(ecc.BN254),
+		test.NoSerializationChecks(),
+		test.WithValidAssignment(assignment(a, b, 4)),
+		test.WithValidAssignment(assignment(a, []byte("the q"), 0)),
+		test.WithValidAssignment(assignment(a, []byte("n fox"), 14)),
+		test.WithInvalidAssignment(assignment(a, b, 5)),
+		// the subslice overflows the end of a
+		test.WithInvalidAssignment(assignment(a, []byte("foxth"), 16)),
+		// negative offset
+		test.WithInvalidAssignment(assignment(a, []byte("xthe "), -1)),
+	)
+}
+
+type indexCircuit struct {
+	A, B     []uints.U8
+	Expected frontend.Variable
+}
+
+func (c *indexCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Index(api, c.A, c.B), c.Expected)
+	return nil
+}
+
+func TestIndex(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := []byte(`{"sub":"1234","email":"alice@example.com"}`)
+	for _, b := range []string{`"email":`, `{`, `}`, `1234`} {
+		circuit := &indexCircuit{A: make([]uints.U8, len(a)), B: make([]uints.U8, len(b))}
+		err := test.IsSolved(circuit, &indexCircuit{A: uints.NewU8Array(a), B: uints.NewU8Array([]byte(b)), Expected: stdbytes.Index(a, []byte(b))}, ecc.BN254.ScalarField())
+		assert.NoError(err, b)
+	}
+	circuit := &indexCircuit{A: make([]uints.U8, len(a)), B: make([]uints.U8, 5)}
+	err := test.IsSolved(circuit, &indexCircuit{A: uints.NewU8Array(a), B: uints.NewU8Array([]byte("carol")), Expected: 0}, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,45 @@
+package bytes
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{indexHint}
+}
+
+// indexHint returns the offset of the first occurrence of the needle in the
+// haystack. The inputs are the length of the haystack, the haystack and the
+// needle.
+func indexHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 1 || len(outputs) != 1 {
+		return fmt.Errorf("expecting at least one input and one output")
+	}
+	if !inputs[0].IsUint64() || inputs[0].Uint64() > uint64(len(inputs)-1) {
+		return fmt.Errorf("invalid haystack length")
+	}
+	n := int(inputs[0].Uint64())
+	bts := make([]byte, len(inputs)-1)
+	for i := range bts {
+		if !inputs[i+1].IsUint64() || inputs[i+1].Uint64() > 0xff {
+			return fmt.Errorf("input %d is not a byte", i)
+		}
+		bts[i] = byte(inputs[i+1].Uint64())
+	}
+	idx := bytes.Index(bts[:n], bts[n:])
+	if idx < 0 {
+		return fmt.Errorf("subslice not found")
+	}
+	outputs[0].SetInt64(int64(idx))
+	return nil
+}
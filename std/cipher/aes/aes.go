@@ -0,0 +1,188 @@
+// Package aes implements the AES block cipher in-circuit, with the CTR and GCM
+// modes of operation.
+//
+// It allows to prove the knowledge of a plaintext or of a key corresponding to
+// a given ciphertext, for example in fair exchange or proxy re-encryption
+// protocols. Both the key and the data are given as bytes.
+//
+// The S-box is computed with lookups in a table of 256 entries, and the XORs
+// with the bytewise lookups of [uints]. MixColumns is merged with the S-box
+// by looking up both S(x) and 2·S(x) in GF(2⁸).
+package aes
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// BlockSize is the AES block size in bytes.
+const BlockSize = 16
+
+// Cipher is an AES block cipher with an expanded key.
+type Cipher struct {
+	api     frontend.API
+	uapi    *uints.BinaryField[uints.U32]
+	checker frontend.Rangechecker
+	sbox    *logderivlookup.Table // S(x)
+	sbox2   *logderivlookup.Table // 2·S(x)
+	// roundKeys are the columns of the round keys
+	roundKeys []uints.U32
+}
+
+// NewCipher returns a new AES cipher for the key, which must be of 16, 24 or
+// 32 bytes to select AES-128, AES-192 or AES-256.
+func NewCipher(api frontend.API, key []uints.U8) (*Cipher, error) {
+	nk := len(key) / 4
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		return nil, fmt.Errorf("invalid key size %d", len(key))
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	c := &Cipher{
+		api:     api,
+		uapi:    uapi,
+		checker: rangecheck.New(api),
+		sbox:    logderivlookup.New(api),
+		sbox2:   logderivlookup.New(api),
+	}
+	for i := range sbox {
+		c.sbox.Insert(sbox[i])
+		c.sbox2.Insert(mul2(sbox[i]))
+	}
+
+	// key expansion
+	nbRounds := nk + 6
+	w := make([]uints.U32, 4*(nbRounds+1))
+	for i := 0; i < nk; i++ {
+		copy(w[i][:], key[4*i:4*i+4])
+	}
+	rcon := byte(1)
+	for i := nk; i < len(w); i++ {
+		tmp := w[i-1]
+		if i%nk == 0 {
+			tmp = c.subWord(uints.U32{tmp[1], tmp[2], tmp[3], tmp[0]})
+			tmp = uapi.Xor(tmp, uints.U32{uints.NewU8(rcon), uints.NewU8(0), uints.NewU8(0), uints.NewU8(0)})
+			rcon = mul2(rcon)
+		} else if nk > 6 && i%nk == 4 {
+			tmp = c.subWord(tmp)
+		}
+		w[i] = uapi.Xor(w[i-nk], tmp)
+	}
+	c.roundKeys = w
+	return c, nil
+}
+
+// Encrypt returns the encryption of the block src of [BlockSize] bytes.
+func (c *Cipher) Encrypt(src []uints.U8) []uints.U8 {
+	if len(src) != BlockSize {
+		panic(fmt.Sprintf("invalid block size %d", len(src)))
+	}
+	nbRounds := len(c.roundKeys)/4 - 1
+	var state [4]uints.U32
+	for i := range state {
+		copy(state[i][:], src[4*i:4*i+4])
+		state[i] = c.uapi.Xor(state[i], c.roundKeys[i])
+	}
+	for round := 1; round <= nbRounds; round++ {
+		// SubBytes and ShiftRows
+		var s, s2 [4]uints.U32
+		for col := range s {
+			for row := 0; row < 4; row++ {
+				in := state[(col+row)%4][row].Val
+				s[col][row] = uints.U8{Val: c.sbox.Lookup(in)[0]}
+				if round < nbRounds {
+					s2[col][row] = uints.U8{Val: c.sbox2.Lookup(in)[0]}
+				}
+			}
+		}
+		key := c.roundKeys[4*round : 4*round+4]
+		for col := range state {
+			if round == nbRounds {
+				state[col] = c.uapi.Xor(s[col], key[col])
+				continue
+			}
+			// MixColumns: out_r = 2·a_r ⊕ 3·a_{r+1} ⊕ a_{r+2} ⊕ a_{r+3}
+			state[col] = c.uapi.Xor(s2[col], rotate(s2[col], 1), rotate(s[col], 1), rotate(s[col], 2), rotate(s[col], 3), key[col])
+		}
+	}
+	res := make([]uints.U8, BlockSize)
+	for i := range state {
+		copy(res[4*i:], state[i][:])
+	}
+	return res
+}
+
+func (c *Cipher) subWord(a uints.U32) uints.U32 {
+	var res uints.U32
+	for i := range a {
+		res[i] = uints.U8{Val: c.sbox.Lookup(a[i].Val)[0]}
+	}
+	return res
+}
+
+// xor returns the bytewise exclusive or of a and b, truncated to the length
+// of a.
+func (c *Cipher) xor(a, b []uints.U8) []uints.U8 {
+	res := make([]uints.U8, len(a))
+	for i := 0; i < len(a); i += 4 {
+		var x, y uints.U32
+		for j := 0; j < 4; j++ {
+			if i+j < len(a) {
+				x[j], y[j] = a[i+j], b[i+j]
+			} else {
+				x[j], y[j] = uints.NewU8(0), uints.NewU8(0)
+			}
+		}
+		z := c.uapi.Xor(x, y)
+		copy(res[i:], z[:])
+	}
+	return res
+}
+
+// rotate returns the column a rotated up by n rows.
+func rotate(a uints.U32, n int) uints.U32 {
+	var res uints.U32
+	for i := range res {
+		res[i] = a[(i+n)%4]
+	}
+	return res
+}
+
+// mul2 returns 2·x in GF(2⁸) modulo x⁸ + x⁴ + x³ + x + 1.
+func mul2(x byte) byte {
+	if x&0x80 != 0 {
+		return x<<1 ^ 0x1b
+	}
+	return x << 1
+}
+
+// sbox is the AES S-box, computed as the affine transform of the inverse in
+// GF(2⁸).
+var sbox [256]byte
+
+func init() {
+	// p and q iterate over the multiplicative group with generator 3 and its
+	// inverse, so that q = p⁻¹.
+	p, q := byte(1), byte(1)
+	for {
+		p = p ^ mul2(p)
+		q ^= q << 1
+		q ^= q << 2
+		q ^= q << 4
+		if q&0x80 != 0 {
+			q ^= 0x09
+		}
+		rotl := func(x byte, n uint) byte { return x<<n | x>>(8-n) }
+		sbox[p] = q ^ rotl(q, 1) ^ rotl(q, 2) ^ rotl(q, 3) ^ rotl(q, 4) ^ 0x63
+		if p == 1 {
+			break
+		}
+	}
+	sbox[0] = 0x63
+}
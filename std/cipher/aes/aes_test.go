@@ -0,0 +1,152 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type encryptCircuit struct {
+	Key        []uints.U8
+	Plaintext  [BlockSize]uints.U8
+	Ciphertext [BlockSize]uints.U8
+}
+
+func (c *encryptCircuit) Define(api frontend.API) error {
+	block, err := NewCipher(api, c.Key)
+	if err != nil {
+		return err
+	}
+	res := block.Encrypt(c.Plaintext[:])
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Ciphertext[i].Val)
+	}
+	return nil
+}
+
+func TestEncrypt(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, keySize := range []int{16, 24, 32} {
+		key := make([]byte, keySize)
+		var plaintext, ciphertext [BlockSize]byte
+		_, _ = rand.Read(key)
+		_, _ = rand.Read(plaintext[:])
+		block, err := aes.NewCipher(key)
+		assert.NoError(err)
+		block.Encrypt(ciphertext[:], plaintext[:])
+
+		witness := encryptCircuit{Key: uints.NewU8Array(key)}
+		copy(witness.Plaintext[:], uints.NewU8Array(plaintext[:]))
+		copy(witness.Ciphertext[:], uints.NewU8Array(ciphertext[:]))
+		invalid := encryptCircuit{Key: uints.NewU8Array(key)}
+		ciphertext[0]++
+		copy(invalid.Plaintext[:], uints.NewU8Array(plaintext[:]))
+		copy(invalid.Ciphertext[:], uints.NewU8Array(ciphertext[:]))
+		assert.CheckCircuit(&encryptCircuit{Key: make([]uints.U8, keySize)},
+			test.WithCurves(ecc.BN254),
+			test.WithValidAssignment(&witness),
+			test.WithInvalidAssignment(&invalid),
+		)
+	}
+}
+
+type ctrCircuit struct {
+	Key        [16]uints.U8
+	IV         [BlockSize]uints.U8
+	Plaintext  [40]uints.U8
+	Ciphertext [40]uints.U8
+}
+
+func (c *ctrCircuit) Define(api frontend.API) error {
+	block, err := NewCipher(api, c.Key[:])
+	if err != nil {
+		return err
+	}
+	res := block.CTR(c.IV[:], c.Plaintext[:])
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Ciphertext[i].Val)
+	}
+	return nil
+}
+
+func TestCTR(t *testing.T) {
+	assert := test.NewAssert(t)
+	var key [16]byte
+	var plaintext, ciphertext [40]byte
+	_, _ = rand.Read(key[:])
+	_, _ = rand.Read(plaintext[:])
+	// the counter wraps around in the second block
+	iv := [BlockSize]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}
+	block, err := aes.NewCipher(key[:])
+	assert.NoError(err)
+	cipher.NewCTR(block, iv[:]).XORKeyStream(ciphertext[:], plaintext[:])
+
+	var witness ctrCircuit
+	copy(witness.Key[:], uints.NewU8Array(key[:]))
+	copy(witness.IV[:], uints.NewU8Array(iv[:]))
+	copy(witness.Plaintext[:], uints.NewU8Array(plaintext[:]))
+	copy(witness.Ciphertext[:], uints.NewU8Array(ciphertext[:]))
+	invalid := witness
+	invalid.Ciphertext[39] = uints.NewU8(ciphertext[39] ^ 1)
+	assert.CheckCircuit(&ctrCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&witness),
+		test.WithInvalidAssignment(&invalid),
+	)
+}
+
+type gcmCircuit struct {
+	Key            [32]uints.U8
+	Nonce          [NonceSize]uints.U8
+	Plaintext      [20]uints.U8
+	AdditionalData [5]uints.U8
+	Ciphertext     [20 + TagSize]uints.U8
+}
+
+func (c *gcmCircuit) Define(api frontend.API) error {
+	block, err := NewCipher(api, c.Key[:])
+	if err != nil {
+		return err
+	}
+	res := block.SealGCM(c.Nonce[:], c.Plaintext[:], c.AdditionalData[:])
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Ciphertext[i].Val)
+	}
+	return nil
+}
+
+func TestSealGCM(t *testing.T) {
+	assert := test.NewAssert(t)
+	var key [32]byte
+	var nonce [NonceSize]byte
+	var plaintext [20]byte
+	var additionalData [5]byte
+	for _, b := range [][]byte{key[:], nonce[:], plaintext[:], additionalData[:]} {
+		_, _ = rand.Read(b)
+	}
+	block, err := aes.NewCipher(key[:])
+	assert.NoError(err)
+	aead, err := cipher.NewGCM(block)
+	assert.NoError(err)
+	ciphertext := aead.Seal(nil, nonce[:], plaintext[:], additionalData[:])
+
+	var witness gcmCircuit
+	copy(witness.Key[:], uints.NewU8Array(key[:]))
+	copy(witness.Nonce[:], uints.NewU8Array(nonce[:]))
+	copy(witness.Plaintext[:], uints.NewU8Array(plaintext[:]))
+	copy(witness.AdditionalData[:], uints.NewU8Array(additionalData[:]))
+	copy(witness.Ciphertext[:], uints.NewU8Array(ciphertext))
+	invalid := witness
+	invalid.AdditionalData[0] = uints.NewU8(additionalData[0] ^ 1)
+	assert.CheckCircuit(&gcmCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&witness),
+		test.WithInvalidAssignment(&invalid),
+	)
+}
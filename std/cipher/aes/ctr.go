@@ -0,0 +1,55 @@
+package aes
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// CTR returns the encryption, or equivalently the decryption, of src in the
+// counter mode with the initial counter block iv of [BlockSize] bytes. As in
+// [crypto/cipher.NewCTR], the counter block is incremented as a big-endian
+// integer of 128 bits.
+func (c *Cipher) CTR(iv, src []uints.U8) []uints.U8 {
+	if len(iv) != BlockSize {
+		panic(fmt.Sprintf("invalid iv size %d", len(iv)))
+	}
+	res := make([]uints.U8, 0, len(src))
+	for i := 0; i*BlockSize < len(src); i++ {
+		block := src[i*BlockSize:]
+		if len(block) > BlockSize {
+			block = block[:BlockSize]
+		}
+		res = append(res, c.xor(block, c.Encrypt(c.increment(iv, i)))...)
+	}
+	return res
+}
+
+// increment returns the big-endian integer counter + n modulo 2¹²⁸.
+func (c *Cipher) increment(counter []uints.U8, n int) []uints.U8 {
+	if n == 0 {
+		return counter
+	}
+	var v frontend.Variable = 0
+	for i := range counter {
+		v = c.api.Add(c.api.Mul(v, 256), counter[i].Val)
+	}
+	v = c.api.Add(v, n)
+	// v = Σ res[i]·256^(15-i) + carry·2¹²⁸
+	out, err := c.api.Compiler().NewHint(incrementHint, len(counter)+1, v)
+	if err != nil {
+		panic(err)
+	}
+	res := make([]uints.U8, len(counter))
+	var composed frontend.Variable = 0
+	for i := range res {
+		res[i] = c.uapi.ByteValueOf(out[i])
+		composed = c.api.Add(c.api.Mul(composed, 256), out[i])
+	}
+	carry := out[len(counter)]
+	c.api.AssertIsBoolean(carry)
+	c.api.AssertIsEqual(c.api.Add(composed, c.api.Mul(carry, new(big.Int).Lsh(big.NewInt(1), 8*BlockSize))), v)
+	return res
+}
@@ -0,0 +1,158 @@
+package aes
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// NonceSize is the size in bytes of the nonces of the GCM mode.
+	NonceSize = 12
+	// TagSize is the size in bytes of the authentication tags of the GCM
+	// mode.
+	TagSize = 16
+)
+
+// SealGCM returns the encryption of plaintext in the GCM mode with the given
+// nonce of [NonceSize] bytes, followed by the authentication tag of the
+// ciphertext and of additionalData, as the Seal method of
+// [crypto/cipher.NewGCM].
+//
+// The authentication uses multiplications in GF(2¹²⁸) where both operands are
+// variables, which cost about 16k constraints per block of ciphertext or
+// additional data.
+func (c *Cipher) SealGCM(nonce, plaintext, additionalData []uints.U8) []uints.U8 {
+	if len(nonce) != NonceSize {
+		panic(fmt.Sprintf("invalid nonce size %d", len(nonce)))
+	}
+	// J0 = nonce ‖ 0x00000001, and the counter blocks are incremented on the
+	// last 32 bits, which don't wrap around for the supported lengths.
+	counter := func(n uint32) []uints.U8 {
+		return append(append([]uints.U8{}, nonce...), uints.NewU8Array([]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})...)
+	}
+	ciphertext := make([]uints.U8, 0, len(plaintext)+TagSize)
+	for i := 0; i*BlockSize < len(plaintext); i++ {
+		block := plaintext[i*BlockSize:]
+		if len(block) > BlockSize {
+			block = block[:BlockSize]
+		}
+		ciphertext = append(ciphertext, c.xor(block, c.Encrypt(counter(uint32(i)+2)))...)
+	}
+
+	// S = GHASH_H(A ‖ 0* ‖ C ‖ 0* ‖ len(A) ‖ len(C)) with H = E_K(0¹²⁸)
+	h := c.toBits(c.Encrypt(uints.NewU8Array(make([]byte, BlockSize))))
+	var s []frontend.Variable
+	for _, data := range [][]uints.U8{additionalData, ciphertext} {
+		for i := 0; i < len(data); i += BlockSize {
+			block := data[i:]
+			if len(block) > BlockSize {
+				block = block[:BlockSize]
+			}
+			s = c.ghash(s, c.toBits(block), h)
+		}
+	}
+	lengths := new(big.Int).Lsh(big.NewInt(int64(8*len(additionalData))), 64)
+	lengths.Or(lengths, big.NewInt(int64(8*len(ciphertext))))
+	lengthsBlock := make([]byte, BlockSize)
+	lengths.FillBytes(lengthsBlock)
+	s = c.ghash(s, c.toBits(uints.NewU8Array(lengthsBlock)), h)
+
+	// T = E_K(J0) ⊕ S
+	tag := make([]uints.U8, TagSize)
+	for i := range tag {
+		tag[i] = uints.U8{Val: c.api.FromBinary(reverse(s[8*i : 8*i+8])...)}
+	}
+	return append(ciphertext, c.xor(tag, c.Encrypt(counter(1)))...)
+}
+
+// toBits returns the bits of the block as coefficients of a polynomial of
+// GF(2¹²⁸), where the most significant bit of the first byte is the constant
+// coefficient. A partial block is padded with zeros.
+func (c *Cipher) toBits(block []uints.U8) []frontend.Variable {
+	res := make([]frontend.Variable, 8*BlockSize)
+	for i := 0; i < BlockSize; i++ {
+		if i >= len(block) {
+			for j := 0; j < 8; j++ {
+				res[8*i+j] = 0
+			}
+			continue
+		}
+		copy(res[8*i:], reverse(c.api.ToBinary(block[i].Val, 8)))
+	}
+	return res
+}
+
+// ghash returns (x ⊕ b)·h in GF(2¹²⁸), with x the previous value or nil for
+// the first block.
+func (c *Cipher) ghash(x, b, h []frontend.Variable) []frontend.Variable {
+	if x != nil {
+		for i := range b {
+			b[i] = c.api.Xor(x[i], b[i])
+		}
+	}
+	// the coefficients of the product are the parities of the sums over the
+	// integers of the products of the bits, where the reduction of the high
+	// coefficients is linear.
+	prod := make([]frontend.Variable, 2*len(b)-1)
+	for i := range prod {
+		prod[i] = 0
+	}
+	for i := range b {
+		for j := range h {
+			prod[i+j] = c.api.Add(prod[i+j], c.api.Mul(b[i], h[j]))
+		}
+	}
+	sums := make([]frontend.Variable, len(b))
+	copy(sums, prod)
+	for k := len(b); k < len(prod); k++ {
+		for m := range sums {
+			if reductions[k-len(b)].Bit(m) == 1 {
+				sums[m] = c.api.Add(sums[m], prod[k])
+			}
+		}
+	}
+	res := make([]frontend.Variable, len(b))
+	for m := range res {
+		res[m] = c.parity(sums[m])
+	}
+	return res
+}
+
+// parity returns the parity of v < 2¹⁵.
+func (c *Cipher) parity(v frontend.Variable) frontend.Variable {
+	out, err := c.api.Compiler().NewHint(parityHint, 2, v)
+	if err != nil {
+		panic(err)
+	}
+	c.checker.Check(out[0], 14)
+	c.api.AssertIsBoolean(out[1])
+	c.api.AssertIsEqual(c.api.Add(c.api.Mul(out[0], 2), out[1]), v)
+	return out[1]
+}
+
+func reverse(v []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(v))
+	for i := range v {
+		res[len(v)-1-i] = v[i]
+	}
+	return res
+}
+
+// reductions[k] is x^(128+k) modulo x¹²⁸ + x⁷ + x² + x + 1, as a bit mask of
+// its coefficients.
+var reductions [127]*big.Int
+
+func init() {
+	v := big.NewInt(0x87)
+	for k := range reductions {
+		reductions[k] = new(big.Int).Set(v)
+		v.Lsh(v, 1)
+		if v.Bit(128) == 1 {
+			v.SetBit(v, 128, 0)
+			v.Xor(v, big.NewInt(0x87))
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package aes
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		incrementHint,
+		parityHint,
+	}
+}
+
+// incrementHint returns the big-endian bytes of the input modulo 256^n and the
+// carry, where n+1 is the number of outputs.
+func incrementHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) < 2 {
+		return fmt.Errorf("expecting one input and at least two outputs")
+	}
+	v := new(big.Int).Set(inputs[0])
+	nbBytes := len(outputs) - 1
+	for i := nbBytes - 1; i >= 0; i-- {
+		outputs[i].SetUint64(v.Uint64() & 0xff)
+		v.Rsh(v, 8)
+	}
+	outputs[nbBytes].Set(v)
+	return nil
+}
+
+// parityHint returns the input divided by two and its parity.
+func parityHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 2 {
+		return fmt.Errorf("expecting one input and two outputs")
+	}
+	outputs[0].Rsh(inputs[0], 1)
+	outputs[1].SetUint64(uint64(inputs[0].Bit(0)))
+	return nil
+}
@@ -0,0 +1,157 @@
+// Package chacha20 implements the ChaCha20 stream cipher in-circuit, with the
+// ChaCha20-Poly1305 AEAD construction, as defined in [RFC 8439].
+//
+// ChaCha20 only uses additions, rotations and XORs of 32-bit words, which are
+// cheaper than the S-box lookups of AES. Combined with the [poly1305]
+// authenticator, it allows to prove statements about TLS 1.3 records. Both
+// the key and the data are given as bytes.
+//
+// [RFC 8439]: https://www.rfc-editor.org/rfc/rfc8439
+package chacha20
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/poly1305"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// KeySize is the size of the key in bytes.
+	KeySize = 32
+	// NonceSize is the size of the nonce in bytes.
+	NonceSize = 12
+	// TagSize is the size of the authentication tag of [Cipher.Seal] in
+	// bytes.
+	TagSize = poly1305.TagSize
+
+	blockSize = 64
+)
+
+// sigma are the constant words "expand 32-byte k".
+var sigma = [4]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574}
+
+// Cipher is a ChaCha20 cipher for a fixed key.
+type Cipher struct {
+	api  frontend.API
+	uapi *uints.BinaryField[uints.U32]
+	key  [8]uints.U32
+}
+
+// NewCipher returns a new ChaCha20 cipher for the key of [KeySize] bytes.
+func NewCipher(api frontend.API, key []uints.U8) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size %d, must be %d", len(key), KeySize)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	c := &Cipher{api: api, uapi: uapi}
+	for i := range c.key {
+		c.key[i] = uapi.PackLSB(key[4*i : 4*i+4]...)
+	}
+	return c, nil
+}
+
+// XORKeyStream returns the XOR of src with the key stream for the nonce of
+// [NonceSize] bytes, starting at the block counter. Encryption and decryption
+// are the same operation.
+func (c *Cipher) XORKeyStream(nonce []uints.U8, counter uint32, src []uints.U8) []uints.U8 {
+	if len(nonce) != NonceSize {
+		panic(fmt.Sprintf("invalid nonce size %d", len(nonce)))
+	}
+	if uint64(counter)+uint64((len(src)+blockSize-1)/blockSize) > 1<<32 {
+		panic("block counter overflow")
+	}
+	var n [3]uints.U32
+	for i := range n {
+		n[i] = c.uapi.PackLSB(nonce[4*i : 4*i+4]...)
+	}
+	res := make([]uints.U8, len(src))
+	for i := 0; i < len(src); i += blockSize {
+		ks := c.block(n, counter)
+		counter++
+		for j := 0; j < blockSize/4 && i+4*j < len(src); j++ {
+			var x uints.U32
+			for k := range x {
+				if i+4*j+k < len(src) {
+					x[k] = src[i+4*j+k]
+				} else {
+					x[k] = uints.NewU8(0)
+				}
+			}
+			y := c.uapi.UnpackLSB(c.uapi.Xor(x, ks[j]))
+			copy(res[i+4*j:], y)
+		}
+	}
+	return res
+}
+
+// Seal encrypts and authenticates the plaintext and authenticates the
+// additional data with ChaCha20-Poly1305. It returns the ciphertext followed
+// by the tag of [TagSize] bytes.
+func (c *Cipher) Seal(nonce, plaintext, additionalData []uints.U8) []uints.U8 {
+	ct := c.XORKeyStream(nonce, 1, plaintext)
+
+	// the one-time key is the first half of the block 0 of the key stream
+	otk := c.XORKeyStream(nonce, 0, uints.NewU8Array(make([]byte, poly1305.KeySize)))
+	mac, err := poly1305.New(c.api, otk)
+	if err != nil {
+		panic(err)
+	}
+	pad := func(n int) []uints.U8 {
+		return uints.NewU8Array(make([]byte, (16-n%16)%16))
+	}
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData)))
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(ct)))
+	mac.Write(additionalData)
+	mac.Write(pad(len(additionalData)))
+	mac.Write(ct)
+	mac.Write(pad(len(ct)))
+	mac.Write(uints.NewU8Array(lengths[:]))
+	return append(ct, mac.Sum()...)
+}
+
+// block returns the key stream block for the nonce and the block counter.
+func (c *Cipher) block(nonce [3]uints.U32, counter uint32) [16]uints.U32 {
+	var init [16]uints.U32
+	for i := range sigma {
+		init[i] = uints.NewU32(sigma[i])
+	}
+	copy(init[4:12], c.key[:])
+	init[12] = uints.NewU32(counter)
+	copy(init[13:], nonce[:])
+
+	x := init
+	for i := 0; i < 10; i++ {
+		// column rounds
+		c.quarterRound(&x, 0, 4, 8, 12)
+		c.quarterRound(&x, 1, 5, 9, 13)
+		c.quarterRound(&x, 2, 6, 10, 14)
+		c.quarterRound(&x, 3, 7, 11, 15)
+		// diagonal rounds
+		c.quarterRound(&x, 0, 5, 10, 15)
+		c.quarterRound(&x, 1, 6, 11, 12)
+		c.quarterRound(&x, 2, 7, 8, 13)
+		c.quarterRound(&x, 3, 4, 9, 14)
+	}
+	for i := range x {
+		x[i] = c.uapi.Add(x[i], init[i])
+	}
+	return x
+}
+
+func (c *Cipher) quarterRound(x *[16]uints.U32, a, b, cc, d int) {
+	x[a] = c.uapi.Add(x[a], x[b])
+	x[d] = c.uapi.Lrot(c.uapi.Xor(x[d], x[a]), 16)
+	x[cc] = c.uapi.Add(x[cc], x[d])
+	x[b] = c.uapi.Lrot(c.uapi.Xor(x[b], x[cc]), 12)
+	x[a] = c.uapi.Add(x[a], x[b])
+	x[d] = c.uapi.Lrot(c.uapi.Xor(x[d], x[a]), 8)
+	x[cc] = c.uapi.Add(x[cc], x[d])
+	x[b] = c.uapi.Lrot(c.uapi.Xor(x[b], x[cc]), 7)
+}
@@ -0,0 +1,126 @@
+package chacha20
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+type xorKeyStreamCircuit struct {
+	Key        [KeySize]uints.U8
+	Nonce      [NonceSize]uints.U8
+	Plaintext  [100]uints.U8
+	Ciphertext [100]uints.U8
+}
+
+func (c *xorKeyStreamCircuit) Define(api frontend.API) error {
+	cipher, err := NewCipher(api, c.Key[:])
+	if err != nil {
+		return err
+	}
+	res := cipher.XORKeyStream(c.Nonce[:], 1, c.Plaintext[:])
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Ciphertext[i].Val)
+	}
+	return nil
+}
+
+func TestXORKeyStream(t *testing.T) {
+	assert := test.NewAssert(t)
+	var key [KeySize]byte
+	var nonce [NonceSize]byte
+	var plaintext, ciphertext [100]byte
+	_, _ = rand.Read(key[:])
+	_, _ = rand.Read(nonce[:])
+	_, _ = rand.Read(plaintext[:])
+	s, err := chacha20.NewUnauthenticatedCipher(key[:], nonce[:])
+	assert.NoError(err)
+	s.SetCounter(1)
+	s.XORKeyStream(ciphertext[:], plaintext[:])
+
+	var witness, invalid xorKeyStreamCircuit
+	copy(witness.Key[:], uints.NewU8Array(key[:]))
+	copy(witness.Nonce[:], uints.NewU8Array(nonce[:]))
+	copy(witness.Plaintext[:], uints.NewU8Array(plaintext[:]))
+	copy(witness.Ciphertext[:], uints.NewU8Array(ciphertext[:]))
+	invalid = witness
+	ciphertext[99]++
+	copy(invalid.Ciphertext[:], uints.NewU8Array(ciphertext[:]))
+	assert.CheckCircuit(&xorKeyStreamCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&witness),
+		test.WithInvalidAssignment(&invalid),
+	)
+}
+
+type sealCircuit struct {
+	Key            [KeySize]uints.U8
+	Nonce          [NonceSize]uints.U8
+	Plaintext      []uints.U8
+	AdditionalData []uints.U8
+	Ciphertext     []uints.U8
+}
+
+func (c *sealCircuit) Define(api frontend.API) error {
+	cipher, err := NewCipher(api, c.Key[:])
+	if err != nil {
+		return err
+	}
+	res := cipher.Seal(c.Nonce[:], c.Plaintext, c.AdditionalData)
+	if len(res) != len(c.Ciphertext) {
+		panic("invalid ciphertext length")
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Ciphertext[i].Val)
+	}
+	return nil
+}
+
+func TestSeal(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, c := range []struct {
+		ptLen, adLen int
+	}{
+		{0, 0},
+		{64, 12},
+		{115, 5},
+	} {
+		key := make([]byte, KeySize)
+		nonce := make([]byte, NonceSize)
+		plaintext := make([]byte, c.ptLen)
+		additionalData := make([]byte, c.adLen)
+		_, _ = rand.Read(key)
+		_, _ = rand.Read(nonce)
+		_, _ = rand.Read(plaintext)
+		_, _ = rand.Read(additionalData)
+		aead, err := chacha20poly1305.New(key)
+		assert.NoError(err)
+		ciphertext := aead.Seal(nil, nonce, plaintext, additionalData)
+
+		circuit := sealCircuit{
+			Plaintext:      make([]uints.U8, len(plaintext)),
+			AdditionalData: make([]uints.U8, len(additionalData)),
+			Ciphertext:     make([]uints.U8, len(ciphertext)),
+		}
+		witness := sealCircuit{
+			Plaintext:      uints.NewU8Array(plaintext),
+			AdditionalData: uints.NewU8Array(additionalData),
+			Ciphertext:     uints.NewU8Array(ciphertext),
+		}
+		copy(witness.Key[:], uints.NewU8Array(key))
+		copy(witness.Nonce[:], uints.NewU8Array(nonce))
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "plaintext length %d", c.ptLen)
+
+		ciphertext[len(ciphertext)-1]++
+		witness.Ciphertext = uints.NewU8Array(ciphertext)
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.Error(err, "plaintext length %d", c.ptLen)
+	}
+}
@@ -166,6 +166,104 @@ func ValueOfOpeningProof[S algebra.ScalarT, G1El algebra.G1ElementT](point any,
 	return ret, nil
 }
 
+// BatchOpeningProof embeds the opening proof that the polynomials evaluated at
+// Point are equal to ClaimedValues. The quotient is the combination of the
+// quotients of the polynomials by the powers of the folding challenge. Use
+// [ValueOfBatchOpeningProof] to initialize a witness from a native batch
+// opening proof.
+type BatchOpeningProof[S algebra.ScalarT, G1El algebra.G1ElementT] struct {
+	QuotientPoly  G1El
+	ClaimedValues []S
+	Point         S
+}
+
+// ValueOfBatchOpeningProof initializes a batch opening proof from the given
+// proof and point. It returns an error if there is a mismatch between the type
+// parameters and types of the provided point and proof.
+func ValueOfBatchOpeningProof[S algebra.ScalarT, G1El algebra.G1ElementT](point any, proof any) (BatchOpeningProof[S, G1El], error) {
+	var ret BatchOpeningProof[S, G1El]
+	switch s := any(&ret).(type) {
+	case *BatchOpeningProof[sw_bn254.Scalar, sw_bn254.G1Affine]:
+		tProof, ok := proof.(kzg_bn254.BatchOpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		tPoint, ok := point.(fr_bn254.Element)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, point)
+		}
+		s.QuotientPoly = sw_bn254.NewG1Affine(tProof.H)
+		s.ClaimedValues = make([]sw_bn254.Scalar, len(tProof.ClaimedValues))
+		for i := range tProof.ClaimedValues {
+			s.ClaimedValues[i] = sw_bn254.NewScalar(tProof.ClaimedValues[i])
+		}
+		s.Point = sw_bn254.NewScalar(tPoint)
+	case *BatchOpeningProof[sw_bls12377.Scalar, sw_bls12377.G1Affine]:
+		tProof, ok := proof.(kzg_bls12377.BatchOpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		tPoint, ok := point.(fr_bls12377.Element)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, point)
+		}
+		s.QuotientPoly = sw_bls12377.NewG1Affine(tProof.H)
+		s.ClaimedValues = make([]sw_bls12377.Scalar, len(tProof.ClaimedValues))
+		for i := range tProof.ClaimedValues {
+			s.ClaimedValues[i] = tProof.ClaimedValues[i].String()
+		}
+		s.Point = tPoint.String()
+	case *BatchOpeningProof[sw_bls12381.Scalar, sw_bls12381.G1Affine]:
+		tProof, ok := proof.(kzg_bls12381.BatchOpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		tPoint, ok := point.(fr_bls12381.Element)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, point)
+		}
+		s.QuotientPoly = sw_bls12381.NewG1Affine(tProof.H)
+		s.ClaimedValues = make([]sw_bls12381.Scalar, len(tProof.ClaimedValues))
+		for i := range tProof.ClaimedValues {
+			s.ClaimedValues[i] = sw_bls12381.NewScalar(tProof.ClaimedValues[i])
+		}
+		s.Point = sw_bls12381.NewScalar(tPoint)
+	case *BatchOpeningProof[sw_bw6761.Scalar, sw_bw6761.G1Affine]:
+		tProof, ok := proof.(kzg_bw6761.BatchOpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		tPoint, ok := point.(fr_bw6761.Element)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, point)
+		}
+		s.QuotientPoly = sw_bw6761.NewG1Affine(tProof.H)
+		s.ClaimedValues = make([]sw_bw6761.Scalar, len(tProof.ClaimedValues))
+		for i := range tProof.ClaimedValues {
+			s.ClaimedValues[i] = sw_bw6761.NewScalar(tProof.ClaimedValues[i])
+		}
+		s.Point = sw_bw6761.NewScalar(tPoint)
+	case *BatchOpeningProof[sw_bls24315.Scalar, sw_bls24315.G1Affine]:
+		tProof, ok := proof.(kzg_bls24315.BatchOpeningProof)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, proof)
+		}
+		tPoint, ok := point.(fr_bls24315.Element)
+		if !ok {
+			return ret, fmt.Errorf("mismatching types %T %T", ret, point)
+		}
+		s.QuotientPoly = sw_bls24315.NewG1Affine(tProof.H)
+		s.ClaimedValues = make([]sw_bls24315.Scalar, len(tProof.ClaimedValues))
+		for i := range tProof.ClaimedValues {
+			s.ClaimedValues[i] = tProof.ClaimedValues[i].String()
+		}
+		s.Point = tPoint.String()
+	default:
+		return ret, fmt.Errorf("unknown type parametrization")
+	}
+	return ret, nil
+}
+
 // VerifyingKey is the trusted setup for KZG polynomial commitment scheme. Use
 // [ValueOfVerifyingKey] to initialize a witness from the native VerifyingKey.
 type VerifyingKey[G2El algebra.G2ElementT] struct {
@@ -262,3 +360,78 @@ func (vk *Verifier[S, G1El, G2El, GtEl]) AssertProof(commitment Commitment[G1El]
 	}
 	return nil
 }
+
+// BatchVerifySinglePoint asserts the validity of the batch opening proof of
+// the commitments at a single point. The commitments are folded with the
+// powers of the challenge gamma, which must be the one used by the prover for
+// folding the quotients. For soundness, gamma must be derived from the
+// commitments, the point and the claimed values, for example with the
+// Fiat-Shamir transcript gadget.
+func (vk *Verifier[S, G1El, G2El, GtEl]) BatchVerifySinglePoint(commitments []Commitment[G1El], proof BatchOpeningProof[S, G1El], gamma S) error {
+	if len(commitments) == 0 {
+		return fmt.Errorf("no commitments")
+	}
+	if len(commitments) != len(proof.ClaimedValues) {
+		return fmt.Errorf("mismatching number of commitments and claimed values: %d != %d", len(commitments), len(proof.ClaimedValues))
+	}
+	// [∑ᵢγⁱ(fᵢ(α) - fᵢ(a))]G₁, computed with the Horner scheme
+	var folded *G1El
+	for i := len(commitments) - 1; i >= 0; i-- {
+		term := vk.curve.Add(&commitments[i].G1El, vk.curve.Neg(vk.curve.ScalarMulBase(&proof.ClaimedValues[i])))
+		if folded == nil {
+			folded = term
+		} else {
+			folded = vk.curve.Add(vk.curve.ScalarMul(folded, &gamma), term)
+		}
+	}
+
+	// [∑ᵢγⁱ(fᵢ(α) - fᵢ(a)) + a*H(α)]G₁
+	totalG1 := vk.curve.ScalarMul(&proof.QuotientPoly, &proof.Point)
+	totalG1 = vk.curve.Add(totalG1, folded)
+
+	// e([∑ᵢγⁱ(fᵢ(α)-fᵢ(a))+aH(α)]G₁], G₂).e([-H(α)]G₁, [α]G₂) == 1
+	if err := vk.pairing.PairingCheck(
+		[]*G1El{totalG1, vk.curve.Neg(&proof.QuotientPoly)},
+		[]*G2El{&vk.SRS[0], &vk.SRS[1]},
+	); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
+
+// BatchVerifyMultiPoints asserts the validity of the opening proofs of the
+// commitments, each at its own point, with a single pairing check. The proofs
+// are combined with the powers of the challenge lambda. For soundness, lambda
+// must be derived from the commitments and the proofs, for example with the
+// Fiat-Shamir transcript gadget.
+func (vk *Verifier[S, G1El, G2El, GtEl]) BatchVerifyMultiPoints(commitments []Commitment[G1El], proofs []OpeningProof[S, G1El], lambda S) error {
+	if len(commitments) == 0 {
+		return fmt.Errorf("no commitments")
+	}
+	if len(commitments) != len(proofs) {
+		return fmt.Errorf("mismatching number of commitments and proofs: %d != %d", len(commitments), len(proofs))
+	}
+	// [∑ᵢλⁱ(fᵢ(α) - fᵢ(aᵢ) + aᵢHᵢ(α))]G₁ and [∑ᵢλⁱHᵢ(α)]G₁, computed with the
+	// Horner scheme
+	var foldedDigests, foldedQuotients *G1El
+	for i := len(commitments) - 1; i >= 0; i-- {
+		term := vk.curve.Add(&commitments[i].G1El, vk.curve.Neg(vk.curve.ScalarMulBase(&proofs[i].ClaimedValue)))
+		term = vk.curve.Add(term, vk.curve.ScalarMul(&proofs[i].QuotientPoly, &proofs[i].Point))
+		if foldedDigests == nil {
+			foldedDigests = term
+			foldedQuotients = &proofs[i].QuotientPoly
+		} else {
+			foldedDigests = vk.curve.Add(vk.curve.ScalarMul(foldedDigests, &lambda), term)
+			foldedQuotients = vk.curve.Add(vk.curve.ScalarMul(foldedQuotients, &lambda), &proofs[i].QuotientPoly)
+		}
+	}
+
+	// e([∑ᵢλⁱ(fᵢ(α)-fᵢ(aᵢ)+aᵢHᵢ(α))]G₁], G₂).e([-∑ᵢλⁱHᵢ(α)]G₁, [α]G₂) == 1
+	if err := vk.pairing.PairingCheck(
+		[]*G1El{foldedDigests, vk.curve.Neg(foldedQuotients)},
+		[]*G2El{&vk.SRS[0], &vk.SRS[1]},
+	); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
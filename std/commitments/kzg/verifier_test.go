@@ -3,6 +3,7 @@ package kzg
 import (
 	"crypto/rand"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -411,3 +412,153 @@ func TestValueOfSRS(t *testing.T) {
 		_ = assignment
 	}, "bls24315")
 }
+
+type KZGBatchSinglePointCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GTEl algebra.GtElementT] struct {
+	VerifyingKey VerifyingKey[G2El]
+	Commitments  []Commitment[G1El]
+	Proof        BatchOpeningProof[S, G1El]
+	Gamma        S
+}
+
+func (c *KZGBatchSinglePointCircuit[S, G1El, G2El, GTEl]) Define(api frontend.API) error {
+	curve, err := algebra.GetCurve[S, G1El](api)
+	if err != nil {
+		return fmt.Errorf("get curve: %w", err)
+	}
+	pairing, err := algebra.GetPairing[G1El, G2El, GTEl](api)
+	if err != nil {
+		return fmt.Errorf("get pairing: %w", err)
+	}
+	verifier := NewVerifier(c.VerifyingKey, curve, pairing)
+	if err := verifier.BatchVerifySinglePoint(c.Commitments, c.Proof, c.Gamma); err != nil {
+		return fmt.Errorf("batch verify single point: %w", err)
+	}
+	return nil
+}
+
+func TestKZGBatchVerificationSinglePointTwoChain(t *testing.T) {
+	const nbPolynomials = 3
+	assert := test.NewAssert(t)
+
+	alpha, err := rand.Int(rand.Reader, ecc.BLS12_377.ScalarField())
+	assert.NoError(err)
+	srs, err := kzg_bls12377.NewSRS(kzgSize, alpha)
+	assert.NoError(err)
+
+	var point, gamma, gammaI fr_bls12377.Element
+	point.SetRandom()
+	gamma.SetRandom()
+	gammaI.SetOne()
+
+	// the quotients are folded with the powers of gamma
+	var batchProof kzg_bls12377.BatchOpeningProof
+	wCmts := make([]Commitment[sw_bls12377.G1Affine], nbPolynomials)
+	for i := 0; i < nbPolynomials; i++ {
+		f := make([]fr_bls12377.Element, polynomialSize)
+		for j := range f {
+			f[j].SetRandom()
+		}
+		com, err := kzg_bls12377.Commit(f, srs.Pk)
+		assert.NoError(err)
+		wCmts[i], err = ValueOfCommitment[sw_bls12377.G1Affine](com)
+		assert.NoError(err)
+		proof, err := kzg_bls12377.Open(f, point, srs.Pk)
+		assert.NoError(err)
+		var h bls12377.G1Affine
+		h.ScalarMultiplication(&proof.H, gammaI.BigInt(new(big.Int)))
+		batchProof.H.Add(&batchProof.H, &h)
+		batchProof.ClaimedValues = append(batchProof.ClaimedValues, proof.ClaimedValue)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	wProof, err := ValueOfBatchOpeningProof[sw_bls12377.Scalar, sw_bls12377.G1Affine](point, batchProof)
+	assert.NoError(err)
+	wVk, err := ValueOfVerifyingKey[sw_bls12377.G2Affine](srs.Vk)
+	assert.NoError(err)
+
+	assignment := KZGBatchSinglePointCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		VerifyingKey: wVk,
+		Commitments:  wCmts,
+		Proof:        wProof,
+		Gamma:        gamma.String(),
+	}
+	circuit := KZGBatchSinglePointCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Commitments: make([]Commitment[sw_bls12377.G1Affine], nbPolynomials),
+		Proof: BatchOpeningProof[sw_bls12377.Scalar, sw_bls12377.G1Affine]{
+			ClaimedValues: make([]sw_bls12377.Scalar, nbPolynomials),
+		},
+	}
+	var wrongGamma fr_bls12377.Element
+	wrongGamma.SetOne().Add(&wrongGamma, &gamma)
+	wrongAssignment := assignment
+	wrongAssignment.Gamma = wrongGamma.String()
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&assignment), test.WithInvalidAssignment(&wrongAssignment), test.WithCurves(ecc.BW6_761))
+}
+
+type KZGBatchMultiPointsCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GTEl algebra.GtElementT] struct {
+	VerifyingKey VerifyingKey[G2El]
+	Commitments  []Commitment[G1El]
+	Proofs       []OpeningProof[S, G1El]
+	Lambda       S
+}
+
+func (c *KZGBatchMultiPointsCircuit[S, G1El, G2El, GTEl]) Define(api frontend.API) error {
+	curve, err := algebra.GetCurve[S, G1El](api)
+	if err != nil {
+		return fmt.Errorf("get curve: %w", err)
+	}
+	pairing, err := algebra.GetPairing[G1El, G2El, GTEl](api)
+	if err != nil {
+		return fmt.Errorf("get pairing: %w", err)
+	}
+	verifier := NewVerifier(c.VerifyingKey, curve, pairing)
+	if err := verifier.BatchVerifyMultiPoints(c.Commitments, c.Proofs, c.Lambda); err != nil {
+		return fmt.Errorf("batch verify multi points: %w", err)
+	}
+	return nil
+}
+
+func TestKZGBatchVerificationMultiPointsTwoChain(t *testing.T) {
+	const nbPolynomials = 3
+	assert := test.NewAssert(t)
+
+	alpha, err := rand.Int(rand.Reader, ecc.BLS12_377.ScalarField())
+	assert.NoError(err)
+	srs, err := kzg_bls12377.NewSRS(kzgSize, alpha)
+	assert.NoError(err)
+
+	wCmts := make([]Commitment[sw_bls12377.G1Affine], nbPolynomials)
+	wProofs := make([]OpeningProof[sw_bls12377.Scalar, sw_bls12377.G1Affine], nbPolynomials)
+	for i := 0; i < nbPolynomials; i++ {
+		f := make([]fr_bls12377.Element, polynomialSize)
+		for j := range f {
+			f[j].SetRandom()
+		}
+		com, err := kzg_bls12377.Commit(f, srs.Pk)
+		assert.NoError(err)
+		var point fr_bls12377.Element
+		point.SetRandom()
+		proof, err := kzg_bls12377.Open(f, point, srs.Pk)
+		assert.NoError(err)
+		wCmts[i], err = ValueOfCommitment[sw_bls12377.G1Affine](com)
+		assert.NoError(err)
+		wProofs[i], err = ValueOfOpeningProof[sw_bls12377.Scalar, sw_bls12377.G1Affine](point, proof)
+		assert.NoError(err)
+	}
+	wVk, err := ValueOfVerifyingKey[sw_bls12377.G2Affine](srs.Vk)
+	assert.NoError(err)
+	var lambda fr_bls12377.Element
+	lambda.SetRandom()
+
+	assignment := KZGBatchMultiPointsCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		VerifyingKey: wVk,
+		Commitments:  wCmts,
+		Proofs:       wProofs,
+		Lambda:       lambda.String(),
+	}
+	circuit := KZGBatchMultiPointsCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT]{
+		Commitments: make([]Commitment[sw_bls12377.G1Affine], nbPolynomials),
+		Proofs:      make([]OpeningProof[sw_bls12377.Scalar, sw_bls12377.G1Affine], nbPolynomials),
+	}
+	assert.CheckCircuit(&circuit, test.WithValidAssignment(&assignment), test.WithCurves(ecc.BW6_761))
+}
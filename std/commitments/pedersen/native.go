@@ -0,0 +1,111 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+	"sync"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/hash/pedersen"
+)
+
+// Parameters define an instance of the Pedersen vector commitment. The
+// generators are derived from the domain of the underlying Pedersen hash
+// parameters.
+type Parameters struct {
+	hash *pedersen.Parameters
+
+	lock       sync.Mutex
+	generators []point // lazily derived generators of the vector components
+}
+
+// NewParameters returns the parameters of the Pedersen vector commitment over
+// the twisted Edwards curve id with the given domain separation string.
+func NewParameters(id tedwards.ID, domain string) (*Parameters, error) {
+	params, err := pedersen.NewParameters(id, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &Parameters{hash: params}, nil
+}
+
+// Order returns the order of the prime subgroup of the curve. The values and
+// the blinding factors are defined modulo this order.
+func (p *Parameters) Order() *big.Int {
+	return new(big.Int).Set(p.hash.Curve.Order)
+}
+
+// RandomBlinding returns a blinding factor sampled uniformly in [0, Order)
+// from r. If r is nil, then [crypto/rand.Reader] is used.
+func (p *Parameters) RandomBlinding(r io.Reader) (*big.Int, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	return rand.Int(r, p.hash.Curve.Order)
+}
+
+// Commit returns the commitment to values with the given blinding factor, that
+// is the sum of the values times the generators of their positions plus the
+// blinding factor times the blinding generator of the Pedersen hash. It is the
+// out-of-circuit counterpart of [Committer.Commit].
+func (p *Parameters) Commit(values []*big.Int, blinding *big.Int) (x, y *big.Int) {
+	hx, hy := p.hash.BlindingGenerator()
+	res := p.scalarMul(point{hx, hy}, blinding)
+	for i := range values {
+		res = p.add(res, p.scalarMul(p.generator(i), values[i]))
+	}
+	return res.x, res.y
+}
+
+// point is a point of the curve in affine coordinates.
+type point struct {
+	x, y *big.Int
+}
+
+// generator returns the generator of the component i.
+func (p *Parameters) generator(i int) point {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for len(p.generators) <= i {
+		x, y := p.hash.Generator("vector", uint32(len(p.generators)))
+		p.generators = append(p.generators, point{x, y})
+	}
+	return p.generators[i]
+}
+
+// add returns p1 + p2 with the unified addition formula.
+func (p *Parameters) add(p1, p2 point) point {
+	f, curve := p.hash.Field, p.hash.Curve
+	var x1y2, y1x2, y1y2, x1x2, dxy, t big.Int
+	x1y2.Mul(p1.x, p2.y)
+	y1x2.Mul(p1.y, p2.x)
+	y1y2.Mul(p1.y, p2.y)
+	x1x2.Mul(p1.x, p2.x)
+	dxy.Mul(&x1x2, &y1y2).Mul(&dxy, curve.D).Mod(&dxy, f)
+
+	x := new(big.Int).Add(&x1y2, &y1x2)
+	t.Add(big.NewInt(1), &dxy).ModInverse(&t, f)
+	x.Mul(x, &t).Mod(x, f)
+
+	y := new(big.Int).Mul(curve.A, &x1x2)
+	y.Sub(&y1y2, y)
+	t.Sub(big.NewInt(1), &dxy).Mod(&t, f).ModInverse(&t, f)
+	y.Mul(y, &t).Mod(y, f)
+	return point{x, y}
+}
+
+// scalarMul returns [s]p1, the scalar being reduced modulo the order of the
+// subgroup.
+func (p *Parameters) scalarMul(p1 point, s *big.Int) point {
+	var e big.Int
+	e.Mod(s, p.hash.Curve.Order)
+	res := point{new(big.Int), big.NewInt(1)}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = p.add(res, res)
+		if e.Bit(i) == 1 {
+			res = p.add(res, p1)
+		}
+	}
+	return res
+}
@@ -0,0 +1,42 @@
+package pedersen
+
+import (
+	stdhash "hash"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// NullifierTag is the first input of the hash computing a nullifier, which
+// separates the nullifiers from the other digests of the same hash function.
+var NullifierTag = new(big.Int).SetBytes([]byte("gnark/nullifier"))
+
+// Nullifier returns the nullifier H(NullifierTag, secret, scope) of a note
+// containing secret, where H is the hash function h.
+//
+// The nullifier is revealed when spending the note: it is deterministic, so
+// that spending the note twice is detected, while it doesn't reveal which
+// committed note is spent as long as the secret is unknown. The scope, for
+// example the identifier of the pool or of the application, makes the
+// nullifiers of the same secret unlinkable across scopes.
+func Nullifier(api frontend.API, h hash.FieldHasher, secret, scope frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(NullifierTag, secret, scope)
+	return h.Sum()
+}
+
+// NativeNullifier returns the nullifier computed as in [Nullifier] with the
+// native counterpart h of the in-circuit hash function, for example the MiMC
+// hash of gnark-crypto. The inputs are written to h as big-endian encoded field
+// elements of h.BlockSize() bytes.
+func NativeNullifier(h stdhash.Hash, secret, scope *big.Int) (*big.Int, error) {
+	h.Reset()
+	size := h.BlockSize()
+	for _, e := range []*big.Int{NullifierTag, secret, scope} {
+		if _, err := h.Write(e.FillBytes(make([]byte, size))); err != nil {
+			return nil, err
+		}
+	}
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
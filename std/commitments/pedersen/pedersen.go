@@ -0,0 +1,94 @@
+// Package pedersen implements Pedersen vector commitments and nullifiers
+// in-circuit.
+//
+// A vector (v_1, ..., v_n) is committed with the blinding factor r as
+//
+//	C = v_1⋅G_1 + ... + v_n⋅G_n + r⋅H
+//
+// over the twisted Edwards curve embedded in the SNARK field, where the
+// generators G_i and H are derived from a domain separation string as in
+// [github.com/consensys/gnark/std/hash/pedersen]. Unlike the commitment of the
+// Pedersen hash, which commits to bit strings, the vector commitment is
+// additively homomorphic: the sum of the commitments is the commitment to the
+// sum of the vectors with the sum of the blinding factors. The values are
+// defined modulo the order of the prime subgroup of the curve, so that the
+// commitment is binding only for values lower than this order. The commitment
+// is hiding when the blinding factor is sampled uniformly, see
+// [Parameters.RandomBlinding].
+//
+// Privacy-pool style circuits commit to notes containing a secret and reveal
+// the nullifier of the spent notes to prevent double spending, see
+// [Nullifier]. The wallet computes the same nullifier with [NativeNullifier]
+// before spending a note.
+package pedersen
+
+import (
+	"errors"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// Committer computes Pedersen vector commitments in-circuit.
+type Committer struct {
+	api    frontend.API
+	curve  twistededwards.Curve
+	params *Parameters
+}
+
+// New returns a Committer over the twisted Edwards curve id with the
+// generators derived from domain. The curve must be defined over the native
+// field.
+func New(api frontend.API, id tedwards.ID, domain string) (*Committer, error) {
+	params, err := NewParameters(id, domain)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithParameters(api, params)
+}
+
+// NewWithParameters returns a Committer using the given parameters. The
+// parameters must be defined over the native field.
+func NewWithParameters(api frontend.API, params *Parameters) (*Committer, error) {
+	if api.Compiler().Field().Cmp(params.hash.Field) != 0 {
+		return nil, errors.New("pedersen parameters not defined over the native field")
+	}
+	curve, err := twistededwards.NewEdCurve(api, params.hash.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Committer{api: api, curve: curve, params: params}, nil
+}
+
+// Commit returns the commitment to values with the given blinding factor. See
+// [Parameters.Commit].
+func (c *Committer) Commit(values []frontend.Variable, blinding frontend.Variable) twistededwards.Point {
+	hx, hy := c.params.hash.BlindingGenerator()
+	res := c.curve.ScalarMul(twistededwards.Point{X: hx, Y: hy}, blinding)
+	for i := range values {
+		g := c.params.generator(i)
+		res = c.curve.Add(res, c.curve.ScalarMul(twistededwards.Point{X: g.x, Y: g.y}, values[i]))
+	}
+	return res
+}
+
+// AssertOpening asserts that cm is the commitment to values with the given
+// blinding factor.
+func (c *Committer) AssertOpening(cm twistededwards.Point, values []frontend.Variable, blinding frontend.Variable) {
+	res := c.Commit(values, blinding)
+	c.api.AssertIsEqual(res.X, cm.X)
+	c.api.AssertIsEqual(res.Y, cm.Y)
+}
+
+// Add returns the commitment to the sum of the vectors committed in cm1 and
+// cm2, with the sum of their blinding factors.
+func (c *Committer) Add(cm1, cm2 twistededwards.Point) twistededwards.Point {
+	return c.curve.Add(cm1, cm2)
+}
+
+// AssertIsOnCurve asserts that the commitment cm given as witness is a point of
+// the curve.
+func (c *Committer) AssertIsOnCurve(cm twistededwards.Point) {
+	c.curve.AssertIsOnCurve(cm)
+}
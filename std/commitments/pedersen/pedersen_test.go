@@ -0,0 +1,100 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	stdmimc "github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const testDomain = "gnark/test/commitments"
+
+type noteCircuit struct {
+	Values     [3]frontend.Variable
+	Blinding   frontend.Variable
+	Commitment twistededwards.Point `gnark:",public"`
+
+	// a second note and the commitment to the sum of the notes
+	Other, Sum twistededwards.Point
+
+	Secret    frontend.Variable
+	Scope     frontend.Variable `gnark:",public"`
+	Nullifier frontend.Variable `gnark:",public"`
+}
+
+func (c *noteCircuit) Define(api frontend.API) error {
+	cm, err := New(api, tedwards.BN254, testDomain)
+	if err != nil {
+		return err
+	}
+	cm.AssertOpening(c.Commitment, c.Values[:], c.Blinding)
+	sum := cm.Add(c.Commitment, c.Other)
+	api.AssertIsEqual(sum.X, c.Sum.X)
+	api.AssertIsEqual(sum.Y, c.Sum.Y)
+
+	h, err := stdmimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(Nullifier(api, &h, c.Secret, c.Scope), c.Nullifier)
+	return nil
+}
+
+func TestCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	params, err := NewParameters(tedwards.BN254, testDomain)
+	assert.NoError(err)
+
+	values := []*big.Int{big.NewInt(1000), big.NewInt(0), big.NewInt(42)}
+	others := []*big.Int{big.NewInt(24), big.NewInt(7), big.NewInt(1)}
+	r1, err := params.RandomBlinding(nil)
+	assert.NoError(err)
+	r2, err := params.RandomBlinding(nil)
+	assert.NoError(err)
+	cx, cy := params.Commit(values, r1)
+	ox, oy := params.Commit(others, r2)
+
+	// homomorphism
+	sums := make([]*big.Int, len(values))
+	for i := range sums {
+		sums[i] = new(big.Int).Add(values[i], others[i])
+	}
+	sx, sy := params.Commit(sums, new(big.Int).Add(r1, r2))
+
+	secret, err := rand.Int(rand.Reader, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	scope := big.NewInt(0xca7)
+	nullifier, err := NativeNullifier(mimc.NewMiMC(), secret, scope)
+	assert.NoError(err)
+
+	witness := noteCircuit{
+		Values:     [3]frontend.Variable{values[0], values[1], values[2]},
+		Blinding:   r1,
+		Commitment: twistededwards.Point{X: cx, Y: cy},
+		Other:      twistededwards.Point{X: ox, Y: oy},
+		Sum:        twistededwards.Point{X: sx, Y: sy},
+		Secret:     secret,
+		Scope:      scope,
+		Nullifier:  nullifier,
+	}
+	err = test.IsSolved(&noteCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong blinding
+	witness.Blinding = r2
+	err = test.IsSolved(&noteCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// nullifier in another scope
+	witness.Blinding = r1
+	witness.Scope = 0xca8
+	err = test.IsSolved(&noteCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
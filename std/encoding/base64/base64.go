@@ -0,0 +1,142 @@
+// Package base64 implements in-circuit base64 decoding as specified by RFC
+// 4648.
+//
+// Payloads of JSON Web Tokens and signatures in DKIM headers are base64
+// encoded, so decoding them in-circuit allows to prove statements about their
+// content without trusting a decoding performed outside of the circuit.
+//
+// Every encoded character is mapped to its 6-bit value with a lookup in a
+// table containing the alphabet of the encoding, and the values are then
+// recomposed into bytes. As in the non-strict mode of the standard library,
+// the unused low bits of the last character are ignored.
+package base64
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+const (
+	encodeStd = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	encodeURL = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+	padChar = '='
+)
+
+// Encoding is a base64 encoding, defined by its alphabet and whether the
+// encoded strings are padded.
+type Encoding struct {
+	alphabet string
+	padded   bool
+}
+
+var (
+	// StdEncoding is the standard base64 encoding, with padding.
+	StdEncoding = Encoding{alphabet: encodeStd, padded: true}
+	// URLEncoding is the URL and filename safe base64 encoding, with padding.
+	URLEncoding = Encoding{alphabet: encodeURL, padded: true}
+	// RawStdEncoding is the standard base64 encoding, without padding.
+	RawStdEncoding = Encoding{alphabet: encodeStd}
+	// RawURLEncoding is the URL and filename safe base64 encoding, without
+	// padding. It is used by JSON Web Tokens.
+	RawURLEncoding = Encoding{alphabet: encodeURL}
+)
+
+// Decoder decodes base64 encoded byte arrays in-circuit.
+type Decoder struct {
+	api      frontend.API
+	enc      Encoding
+	table    *logderivlookup.Table
+	rchecker frontend.Rangechecker
+}
+
+// NewDecoder returns a decoder for the encoding enc.
+func NewDecoder(api frontend.API, enc Encoding) *Decoder {
+	table := logderivlookup.New(api)
+	for i := 0; i < len(enc.alphabet); i++ {
+		table.Insert(enc.alphabet[i])
+	}
+	if enc.padded {
+		table.Insert(padChar)
+	}
+	return &Decoder{api: api, enc: enc, table: table, rchecker: rangecheck.New(api)}
+}
+
+// Decode decodes src and returns the decoded bytes with their number. The
+// input must not be empty and its length must be a multiple of 4 for padded encodings and must not be 1
+// modulo 4 otherwise.
+//
+// The number of decoded bytes depends on the padding of the last group of 4
+// characters for padded encodings, in which case dst has 3*len(src)/4 bytes and
+// the bytes after the returned length are zero. For unpadded encodings, length
+// is the constant len(dst).
+func (d *Decoder) Decode(src []uints.U8) (dst []uints.U8, length frontend.Variable, err error) {
+	n := len(src)
+	if n == 0 {
+		return nil, nil, fmt.Errorf("empty input")
+	}
+	if d.enc.padded && n%4 != 0 {
+		return nil, nil, fmt.Errorf("padded input length %d is not a multiple of 4", n)
+	}
+	if n%4 == 1 {
+		return nil, nil, fmt.Errorf("invalid input length %d", n)
+	}
+	values := make([]frontend.Variable, n)
+	for i := range src {
+		res, err := d.api.Compiler().NewHint(decodeHint, 1, src[i].Val)
+		if err != nil {
+			return nil, nil, fmt.Errorf("new hint: %w", err)
+		}
+		d.api.AssertIsEqual(d.table.Lookup(res[0])[0], src[i].Val)
+		values[i] = res[0]
+	}
+	// only the two last characters of a padded input may be the padding
+	// character, and if the first is padding then so is the second.
+	isPad := [2]frontend.Variable{0, 0}
+	if d.enc.padded {
+		for i := range isPad {
+			isPad[i] = d.api.IsZero(d.api.Sub(values[n-2+i], padValue))
+			values[n-2+i] = d.api.Sub(values[n-2+i], d.api.Mul(isPad[i], padValue))
+		}
+		d.api.AssertIsEqual(d.api.Mul(isPad[0], d.api.Sub(1, isPad[1])), 0)
+	}
+	dst = make([]uints.U8, 0, 3*(n/4)+2)
+	for i := 0; i < n; i += 4 {
+		var w [4]frontend.Variable
+		k := copy(w[:], values[i:])
+		for j := k; j < len(w); j++ {
+			w[j] = 0
+		}
+		bts := d.decodeGroup(w)
+		if d.enc.padded && i+4 >= n {
+			// the bytes decoded from padding characters are removed
+			bts[1] = d.api.Mul(bts[1], d.api.Sub(1, isPad[0]))
+			bts[2] = d.api.Mul(bts[2], d.api.Sub(1, isPad[1]))
+		}
+		for j := 0; j < k-1; j++ {
+			dst = append(dst, uints.U8{Val: bts[j]})
+		}
+	}
+	length = d.api.Sub(len(dst), isPad[0], isPad[1])
+	return dst, length, nil
+}
+
+// decodeGroup returns the 3 bytes encoded by the 6-bit values w, asserting that
+// the values are in range.
+func (d *Decoder) decodeGroup(w [4]frontend.Variable) [3]frontend.Variable {
+	// w[0] ‖ w[1] ‖ w[2] ‖ w[3] = (w[0] ‖ u1) ‖ (l1 ‖ u2) ‖ (l2 ‖ w[3])
+	d.rchecker.Check(w[0], 6)
+	d.rchecker.Check(w[3], 6)
+	l1, u1 := bitslice.Partition(d.api, w[1], 4, bitslice.WithNbDigits(6))
+	l2, u2 := bitslice.Partition(d.api, w[2], 2, bitslice.WithNbDigits(6))
+	return [3]frontend.Variable{
+		d.api.Add(d.api.Mul(w[0], 1<<2), u1),
+		d.api.Add(d.api.Mul(l1, 1<<4), u2),
+		d.api.Add(d.api.Mul(l2, 1<<6), w[3]),
+	}
+}
@@ -0,0 +1,105 @@
+package base64
+
+import (
+	"crypto/rand"
+	stdbase64 "encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type decodeCircuit struct {
+	enc      Encoding
+	Encoded  []uints.U8
+	Decoded  []uints.U8
+	Expected frontend.Variable
+}
+
+func (c *decodeCircuit) Define(api frontend.API) error {
+	dec := NewDecoder(api, c.enc)
+	res, length, err := dec.Decode(c.Encoded)
+	if err != nil {
+		return err
+	}
+	if len(res) != len(c.Decoded) {
+		return fmt.Errorf("got %d bytes, expected %d", len(res), len(c.Decoded))
+	}
+	for i := range res {
+		api.AssertIsEqual(res[i].Val, c.Decoded[i].Val)
+	}
+	api.AssertIsEqual(length, c.Expected)
+	return nil
+}
+
+// decodedLen returns the number of bytes returned by [Decoder.Decode].
+func decodedLen(enc Encoding, n int) int {
+	if enc.padded {
+		return n / 4 * 3
+	}
+	return n/4*3 + n%4*6/8
+}
+
+func TestDecode(t *testing.T) {
+	assert := test.NewAssert(t)
+	encodings := []struct {
+		name string
+		enc  Encoding
+		std  *stdbase64.Encoding
+	}{
+		{"std", StdEncoding, stdbase64.StdEncoding},
+		{"url", URLEncoding, stdbase64.URLEncoding},
+		{"rawstd", RawStdEncoding, stdbase64.RawStdEncoding},
+		{"rawurl", RawURLEncoding, stdbase64.RawURLEncoding},
+	}
+	for _, e := range encodings {
+		for n := 1; n < 10; n++ {
+			e, n := e, n
+			assert.Run(func(assert *test.Assert) {
+				msg := make([]byte, n)
+				if _, err := rand.Read(msg); err != nil {
+					t.Fatal(err)
+				}
+				encoded := []byte(e.std.EncodeToString(msg))
+				decoded := make([]byte, decodedLen(e.enc, len(encoded)))
+				copy(decoded, msg)
+				circuit := &decodeCircuit{enc: e.enc, Encoded: make([]uints.U8, len(encoded)), Decoded: make([]uints.U8, len(decoded))}
+				witness := &decodeCircuit{Encoded: uints.NewU8Array(encoded), Decoded: uints.NewU8Array(decoded), Expected: n}
+				err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+				assert.NoError(err)
+			}, e.name, fmt.Sprintf("n=%d", n))
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range []struct {
+		enc     Encoding
+		encoded string
+	}{
+		// padding in the middle
+		{StdEncoding, "Zg==Zg=="},
+		{StdEncoding, "Z=g="},
+		// padding followed by a character
+		{StdEncoding, "Zm=v"},
+		// characters of the other alphabet
+		{StdEncoding, "-_-_"},
+		{RawURLEncoding, "+/+/"},
+		// padding in unpadded encoding
+		{RawStdEncoding, "Zg=="},
+		// invalid character
+		{RawStdEncoding, "Zm9*"},
+	} {
+		circuit := &decodeCircuit{enc: tc.enc, Encoded: make([]uints.U8, len(tc.encoded)), Decoded: make([]uints.U8, decodedLen(tc.enc, len(tc.encoded)))}
+		witness := &decodeCircuit{Encoded: uints.NewU8Array([]byte(tc.encoded)), Decoded: make([]uints.U8, len(circuit.Decoded)), Expected: 0}
+		for i := range witness.Decoded {
+			witness.Decoded[i] = uints.NewU8(0)
+		}
+		err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+		assert.Error(err, tc.encoded)
+	}
+}
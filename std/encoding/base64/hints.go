@@ -0,0 +1,53 @@
+package base64
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+// padValue is the index of the padding character in the lookup table.
+const padValue = 64
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{decodeHint}
+}
+
+// decodeHint returns the index of the character in the lookup table. The
+// characters of the standard and URL alphabets are mapped to the same value,
+// so that the hint is independent of the encoding.
+func decodeHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return fmt.Errorf("expecting one input and one output")
+	}
+	if !inputs[0].IsUint64() || inputs[0].Uint64() > 0xff {
+		return fmt.Errorf("input is not a byte")
+	}
+	var v uint64
+	switch c := inputs[0].Uint64(); {
+	case c >= 'A' && c <= 'Z':
+		v = c - 'A'
+	case c >= 'a' && c <= 'z':
+		v = c - 'a' + 26
+	case c >= '0' && c <= '9':
+		v = c - '0' + 52
+	case c == '+' || c == '-':
+		v = 62
+	case c == '/' || c == '_':
+		v = 63
+	case c == padChar:
+		v = padValue
+	default:
+		// the lookup fails for invalid characters
+		v = 0
+	}
+	outputs[0].SetUint64(v)
+	return nil
+}
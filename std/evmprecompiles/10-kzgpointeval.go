@@ -0,0 +1,133 @@
+package evmprecompiles
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/bls"
+)
+
+const (
+	// KZGPointEvaluationInputSize is the size in bytes of the input of the
+	// point evaluation precompile: the versioned hash, the evaluation point,
+	// the claimed value, the commitment and the proof.
+	KZGPointEvaluationInputSize = 32 + 32 + 32 + bls.PublicKeySize + bls.PublicKeySize
+
+	// versionedHashVersionKZG is the first byte of the versioned hash of a
+	// KZG commitment.
+	versionedHashVersionKZG = 0x01
+)
+
+// KZGPointEvaluation implements [POINT_EVALUATION] precompile contract at
+// address 0x0a, introduced by EIP-4844. The input is the concatenation of the
+// versioned hash, the evaluation point z, the claimed value y, the compressed
+// commitment and the compressed proof. It asserts that:
+//   - the versioned hash is 0x01 ‖ SHA-256(commitment)[1:];
+//   - z and y are canonical big-endian encodings of elements of the scalar
+//     field of BLS12-381;
+//   - the commitment and the proof are points of G1 or the point at infinity;
+//   - the proof is a valid KZG opening proof of the commitment at z to y.
+//
+// The verifying key vk is the G2 part of the trusted setup, which is a
+// constant of the protocol and should be given as constants of the circuit.
+// The output of the precompile doesn't depend on the input and is left to the
+// caller.
+//
+// The point at infinity is the commitment to the zero polynomial, for example
+// of an empty blob, and the proof of the constant polynomials. The pairing
+// check can't take it as input, so the check e([z]π + C - [y]G₁, G₂) =
+// e(π, [α]G₂) is reduced to [z]π + C - [y]G₁ = 0 when the proof π is the point
+// at infinity.
+//
+// [POINT_EVALUATION]: https://eips.ethereum.org/EIPS/eip-4844#point-evaluation-precompile
+func KZGPointEvaluation(api frontend.API, input *[KZGPointEvaluationInputSize]uints.U8, vk kzg.VerifyingKey[sw_bls12381.G2Affine]) {
+	var (
+		versionedHash = input[0:32]
+		z             = input[32:64]
+		y             = input[64:96]
+		commitment    = (*[bls.PublicKeySize]uints.U8)(input[96 : 96+bls.PublicKeySize])
+		proof         = (*[bls.PublicKeySize]uints.U8)(input[96+bls.PublicKeySize:])
+	)
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		panic(fmt.Sprintf("new uints: %v", err))
+	}
+	// 1- Check the versioned hash of the commitment
+	h, err := sha2.New(api)
+	if err != nil {
+		panic(fmt.Sprintf("new sha256: %v", err))
+	}
+	h.Write(commitment[:])
+	digest := h.Sum()
+	uapi.ByteAssertEq(versionedHash[0], uints.NewU8(versionedHashVersionKZG))
+	for i := 1; i < len(digest); i++ {
+		uapi.ByteAssertEq(versionedHash[i], digest[i])
+	}
+
+	// 2- Decode the evaluation point and the claimed value
+	frField, err := emulated.NewField[emulated.BLS12381Fr](api)
+	if err != nil {
+		panic(fmt.Sprintf("new field: %v", err))
+	}
+	toScalar := func(b []uints.U8) *sw_bls12381.Scalar {
+		bits := make([]frontend.Variable, 0, 8*len(b))
+		for i := len(b) - 1; i >= 0; i-- {
+			bits = append(bits, api.ToBinary(b[i].Val, 8)...)
+		}
+		s := frField.FromBits(bits...)
+		frField.AssertIsInRange(s)
+		return s
+	}
+
+	// 3- Decode the commitment and the proof and check that they are in G1
+	decoder, err := bls.NewVerifier(api)
+	if err != nil {
+		panic(fmt.Sprintf("new decoder: %v", err))
+	}
+	curve, err := sw_emulated.New[emulated.BLS12381Fp, emulated.BLS12381Fr](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		panic(fmt.Sprintf("new curve: %v", err))
+	}
+	g1 := curve.Generator()
+	com, comIsInfinity := decoder.G1FromBytes(commitment)
+	decoder.AssertIsOnG1(curve.Select(comIsInfinity, g1, com))
+	quotient, quotientIsInfinity := decoder.G1FromBytes(proof)
+	decoder.AssertIsOnG1(curve.Select(quotientIsInfinity, g1, quotient))
+
+	// 4- Check the opening proof
+	fp, err := emulated.NewField[emulated.BLS12381Fp](api)
+	if err != nil {
+		panic(fmt.Sprintf("new field: %v", err))
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		panic(fmt.Sprintf("new pairing: %v", err))
+	}
+	// [z]π + C - [y]G₁, with the complete formulas as any of the terms may be
+	// the point at infinity
+	lhs := curve.AddUnified(curve.ScalarMul(quotient, toScalar(z)), com)
+	lhs = curve.AddUnified(lhs, curve.Neg(curve.ScalarMulBase(toScalar(y))))
+	lhsIsInfinity := api.And(fp.IsZero(&lhs.X), fp.IsZero(&lhs.Y))
+	// if π = 0 the check is [z]π + C - [y]G₁ = 0. Otherwise, as G1 has prime
+	// order, e(π, [α]G₂) ≠ 1 so the pairing check fails if [z]π + C - [y]G₁ = 0.
+	api.AssertIsEqual(lhsIsInfinity, quotientIsInfinity)
+	// e([z]π + C - [y]G₁, G₂).e(-π, [α]G₂) == 1, replaced by the trivial
+	// e(G₁, G₂).e(G₁, -G₂) == 1 if π = 0.
+	g2 := sw_bls12381.NewG2(api)
+	srs1 := &sw_bls12381.G2Affine{
+		X: *g2.Ext2.Select(quotientIsInfinity, &vk.SRS[0].X, &vk.SRS[1].X),
+		Y: *g2.Ext2.Select(quotientIsInfinity, g2.Ext2.Neg(&vk.SRS[0].Y), &vk.SRS[1].Y),
+	}
+	if err := pairing.PairingCheck(
+		[]*sw_bls12381.G1Affine{curve.Select(quotientIsInfinity, g1, lhs), curve.Select(quotientIsInfinity, g1, curve.Neg(quotient))},
+		[]*sw_bls12381.G2Affine{&vk.SRS[0], srs1},
+	); err != nil {
+		panic(fmt.Sprintf("pairing check: %v", err))
+	}
+}
@@ -0,0 +1,140 @@
+package evmprecompiles
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type kzgPointEvalCircuit struct {
+	Input [KZGPointEvaluationInputSize]uints.U8
+	vk    kzg.VerifyingKey[sw_bls12381.G2Affine] `gnark:"-"`
+}
+
+func (c *kzgPointEvalCircuit) Define(api frontend.API) error {
+	KZGPointEvaluation(api, &c.Input, c.vk)
+	return nil
+}
+
+// kzgPointEvalInput returns the input of the precompile for a random
+// polynomial opened at a random point.
+func kzgPointEvalInput(t *testing.T, srs *kzg_bls12381.SRS) []byte {
+	f := make([]fr.Element, 16)
+	for i := range f {
+		f[i].SetRandom()
+	}
+	return kzgPointEvalInputOf(t, srs, f)
+}
+
+// kzgPointEvalInputOf returns the input of the precompile for the polynomial f
+// opened at a random point.
+func kzgPointEvalInputOf(t *testing.T, srs *kzg_bls12381.SRS, f []fr.Element) []byte {
+	com, err := kzg_bls12381.Commit(f, srs.Pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var z fr.Element
+	z.SetRandom()
+	proof, err := kzg_bls12381.Open(f, z, srs.Pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comBytes, proofBytes := com.Bytes(), proof.H.Bytes()
+	zBytes, yBytes := z.Bytes(), proof.ClaimedValue.Bytes()
+	versionedHash := sha256.Sum256(comBytes[:])
+	versionedHash[0] = versionedHashVersionKZG
+	input := make([]byte, 0, KZGPointEvaluationInputSize)
+	input = append(input, versionedHash[:]...)
+	input = append(input, zBytes[:]...)
+	input = append(input, yBytes[:]...)
+	input = append(input, comBytes[:]...)
+	input = append(input, proofBytes[:]...)
+	return input
+}
+
+func TestKZGPointEvaluation(t *testing.T) {
+	assert := test.NewAssert(t)
+	srs, err := kzg_bls12381.NewSRS(16, big.NewInt(-1))
+	assert.NoError(err)
+	vk, err := kzg.ValueOfVerifyingKey[sw_bls12381.G2Affine](srs.Vk)
+	assert.NoError(err)
+	input := kzgPointEvalInput(t, srs)
+
+	circuit := kzgPointEvalCircuit{vk: vk}
+	assignment := func(input []byte) *kzgPointEvalCircuit {
+		var res kzgPointEvalCircuit
+		copy(res.Input[:], uints.NewU8Array(input))
+		return &res
+	}
+	err = test.IsSolved(&circuit, assignment(input), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong claimed value
+	wrong := append([]byte{}, input...)
+	wrong[95] ^= 1
+	err = test.IsSolved(&circuit, assignment(wrong), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// wrong versioned hash
+	wrong = append([]byte{}, input...)
+	wrong[0] = 0x02
+	err = test.IsSolved(&circuit, assignment(wrong), ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+func TestKZGPointEvaluationInfinity(t *testing.T) {
+	assert := test.NewAssert(t)
+	srs, err := kzg_bls12381.NewSRS(16, big.NewInt(-1))
+	assert.NoError(err)
+	vk, err := kzg.ValueOfVerifyingKey[sw_bls12381.G2Affine](srs.Vk)
+	assert.NoError(err)
+
+	circuit := kzgPointEvalCircuit{vk: vk}
+	assignment := func(input []byte) *kzgPointEvalCircuit {
+		var res kzgPointEvalCircuit
+		copy(res.Input[:], uints.NewU8Array(input))
+		return &res
+	}
+
+	// the blob of zeros: the commitment and the proof are the point at infinity
+	zero := kzgPointEvalInputOf(t, srs, make([]fr.Element, 16))
+	assert.Equal(byte(0xc0), zero[96])
+	assert.Equal(byte(0xc0), zero[96+48])
+	err = test.IsSolved(&circuit, assignment(zero), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// constant polynomial: the proof is the point at infinity
+	constant := make([]fr.Element, 16)
+	constant[0].SetUint64(42)
+	input := kzgPointEvalInputOf(t, srs, constant)
+	assert.Equal(byte(0xc0), input[96+48])
+	err = test.IsSolved(&circuit, assignment(input), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong claimed value of the zero polynomial
+	wrong := append([]byte{}, zero...)
+	wrong[95] = 1
+	err = test.IsSolved(&circuit, assignment(wrong), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// wrong claimed value of the constant polynomial
+	wrong = append([]byte{}, input...)
+	wrong[95] ^= 1
+	err = test.IsSolved(&circuit, assignment(wrong), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// non-canonical encoding of the point at infinity in the proof
+	wrong = append([]byte{}, zero...)
+	wrong[96+48] = 0xe0
+	err = test.IsSolved(&circuit, assignment(wrong), ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,64 @@
+package fiatshamir
+
+import (
+	"github.com/consensys/gnark/constant"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Sponge is a duplex transcript which absorbs variables and squeezes
+// challenges in any order. Unlike [Transcript], the challenges don't need to
+// be declared in advance, which suits recursive verifiers and folding schemes
+// where the number of rounds depends on the instance.
+//
+// Every challenge depends on the label of the sponge and on all the variables
+// absorbed before it. The state after a squeeze is:
+//
+//	state = H(state ∥ absorbed values...)
+//
+// where the initial state is the label hashed to the field.
+type Sponge struct {
+	api     frontend.API
+	h       hash.FieldHasher
+	state   frontend.Variable
+	pending []frontend.Variable
+}
+
+// NewSponge returns a new sponge with the hash function h, which can be any
+// algebraic hash of [hash.FieldHasher]. The label is a domain separator which
+// must be unique to the protocol.
+func NewSponge(api frontend.API, h hash.FieldHasher, label string) (*Sponge, error) {
+	state, err := constant.HashedBytes(api, []byte(label))
+	if err != nil {
+		return nil, err
+	}
+	return &Sponge{api: api, h: h, state: state}, nil
+}
+
+// Absorb adds the values to the transcript. They are hashed at the next
+// squeeze.
+func (s *Sponge) Absorb(values ...frontend.Variable) {
+	s.pending = append(s.pending, values...)
+}
+
+// Squeeze returns a new challenge bound to the label and to all the values
+// absorbed so far. Successive squeezes without absorbing return distinct
+// challenges.
+func (s *Sponge) Squeeze() frontend.Variable {
+	s.h.Reset()
+	s.h.Write(s.state)
+	s.h.Write(s.pending...)
+	s.state = s.h.Sum()
+	s.pending = s.pending[:0]
+	s.h.Reset()
+	return s.state
+}
+
+// SqueezeN returns n successive challenges.
+func (s *Sponge) SqueezeN(n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = s.Squeeze()
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package fiatshamir
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type spongeCircuit struct {
+	Values     [3]frontend.Variable
+	Challenges [3]frontend.Variable
+}
+
+func (c *spongeCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	s, err := NewSponge(api, &h, "test")
+	if err != nil {
+		return err
+	}
+	s.Absorb(c.Values[0], c.Values[1])
+	challenges := s.SqueezeN(2)
+	s.Absorb(c.Values[2])
+	challenges = append(challenges, s.Squeeze())
+	for i := range challenges {
+		api.AssertIsEqual(challenges[i], c.Challenges[i])
+	}
+	return nil
+}
+
+func TestSponge(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	label, err := fr.Hash([]byte("test"), []byte("string:"), 1)
+	assert.NoError(err)
+	state := label[0]
+	squeeze := func(values ...fr.Element) fr.Element {
+		h := hash.MIMC_BN254.New()
+		b := state.Bytes()
+		h.Write(b[:])
+		for i := range values {
+			b = values[i].Bytes()
+			h.Write(b[:])
+		}
+		state.SetBytes(h.Sum(nil))
+		return state
+	}
+
+	var witness spongeCircuit
+	var values [3]fr.Element
+	for i := range values {
+		values[i].SetUint64(uint64(i + 1))
+		witness.Values[i] = values[i]
+	}
+	witness.Challenges[0] = squeeze(values[0], values[1])
+	witness.Challenges[1] = squeeze()
+	witness.Challenges[2] = squeeze(values[2])
+
+	invalid := witness
+	invalid.Values[2] = 4
+
+	assert.CheckCircuit(&spongeCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&witness),
+		test.WithInvalidAssignment(&invalid),
+	)
+}
@@ -0,0 +1,25 @@
+// Package gkr implements the GKR protocol as a sub-prover for data-parallel
+// sub-circuits.
+//
+// When a circuit evaluates many instances of the same small sub-circuit (for
+// example many MiMC permutations), the instances can be proved with a
+// sumcheck-based GKR proof instead of being arithmetized one by one. Only the
+// inputs and outputs of the instances are variables of the outer circuit, and
+// the outer SNARK only checks the GKR proof:
+//
+//  1. create the sub-circuit with [NewApi], import the inputs of all the
+//     instances with [API.Import] and combine them with the gates of the API
+//     or with [API.NamedGate]. The number of instances must be a power of 2.
+//     An input of an instance can be bound to an output of another one with
+//     [API.Series];
+//  2. [API.Solve] computes the values of all the wires in a hint, and
+//     [Solution.Export] returns the values of an output wire across the
+//     instances as variables of the outer circuit;
+//  3. [Solution.Verify] adds the verification of the GKR proof to the outer
+//     circuit. The initial challenges must bind the inputs and outputs of the
+//     sub-circuit, typically through a commitment (frontend.Committer).
+//
+// Named gates must be registered both in [Gates] for the in-circuit verifier
+// and in the gate registry of the constraint system of the curve for the
+// prover.
+package gkr
@@ -0,0 +1,153 @@
+package anemoi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type anemoiCircuit struct {
+	Data     [5]frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+	Jive     frontend.Variable `gnark:",public"`
+}
+
+func (c *anemoiCircuit) Define(api frontend.API) error {
+	h, err := NewAnemoi(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+
+	// the digest of two elements is their compression
+	h.Reset()
+	h.Write(c.Data[0], c.Data[1])
+	api.AssertIsEqual(h.Sum(), c.Jive)
+	return nil
+}
+
+func TestAnemoiAll(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761, ecc.BLS24_315} {
+		modulus := curve.ScalarField()
+		p, err := GetParameters(modulus)
+		assert.NoError(err)
+
+		var data [5]*big.Int
+		data[0] = new(big.Int).Sub(modulus, big.NewInt(1))
+		for i := 1; i < len(data); i++ {
+			data[i] = new(big.Int).Add(data[i-1], data[i-1])
+			data[i].Mod(data[i], modulus)
+		}
+
+		var validWitness, invalidWitness anemoiCircuit
+		for i := range data {
+			validWitness.Data[i] = data[i]
+			invalidWitness.Data[i] = data[i]
+		}
+		validWitness.Expected = p.HashElements(data[:]...)
+		validWitness.Jive = p.Jive(data[0], data[1])
+		invalidWitness.Expected = validWitness.Expected
+		invalidWitness.Jive = validWitness.Jive
+		invalidWitness.Data[4] = 1
+
+		assert.CheckCircuit(&anemoiCircuit{},
+			test.WithValidAssignment(&validWitness),
+			test.WithInvalidAssignment(&invalidWitness),
+			test.WithCurves(curve))
+	}
+}
+
+func TestParameters(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for curve := range generators {
+		field := curve.ScalarField()
+		p, err := GetParameters(field)
+		assert.NoError(err, curve.String())
+		assert.Equal(p.NbRounds, len(p.C))
+		assert.Equal(p.NbRounds, len(p.D))
+		var t big.Int
+		assert.Equal(int64(1), t.Mul(p.G, p.GInv).Mod(&t, field).Int64())
+
+		// first round of the reference implementation: π0^0 + π1^0 = 2, so
+		// C[0] = g + 2^alpha and D[0] = g + 2^alpha + g^-1
+		c0 := new(big.Int).Lsh(big.NewInt(1), uint(p.Alpha))
+		c0.Add(c0, p.G).Mod(c0, field)
+		d0 := new(big.Int).Add(c0, p.GInv)
+		d0.Mod(d0, field)
+		assert.Equal(0, c0.Cmp(p.C[0]), curve.String())
+		assert.Equal(0, d0.Cmp(p.D[0]), curve.String())
+	}
+	p, err := GetParameters(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.Equal(5, p.Alpha)
+	assert.Equal(21, p.NbRounds)
+
+	_, err = GetParameters(big.NewInt(101))
+	assert.Error(err)
+}
+
+func TestJive(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// regression values of the Jive compression
+	vectors := []struct {
+		curve    ecc.ID
+		a, b     int64
+		expected string
+	}{
+		{ecc.BN254, 0, 0, "3ac86d4802bf9297d832bd77544b9bc456db3a8cb4cab3c3cff1427f3d9d47"},
+		{ecc.BN254, 1, 2, "1858ff7072240adc41b63d1bef2acdc623fea99100cfabed2f283c98a7d80470"},
+		{ecc.BLS12_381, 0, 0, "2d12dade62a1b26aa94d8c45a15cbec205364fd3d8a308608506d7590c797d8e"},
+		{ecc.BLS12_381, 1, 2, "6c34d9c952c2ee12fb288a6948119198c8157a24fcc2886c3bc88a7b47f074c1"},
+	}
+	for _, v := range vectors {
+		p, err := GetParameters(v.curve.ScalarField())
+		assert.NoError(err)
+		assert.Equal(v.expected, p.Jive(big.NewInt(v.a), big.NewInt(v.b)).Text(16), v.curve.String())
+	}
+}
+
+type compressCircuit struct {
+	A, B frontend.Variable
+	Res  frontend.Variable `gnark:",public"`
+
+	mimc bool
+}
+
+func (c *compressCircuit) Define(api frontend.API) error {
+	if c.mimc {
+		h, err := mimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		h.Write(c.A, c.B)
+		api.AssertIsEqual(h.Sum(), c.Res)
+		return nil
+	}
+	h, err := NewAnemoi(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.A, c.B)
+	api.AssertIsEqual(h.Sum(), c.Res)
+	return nil
+}
+
+func TestFewerConstraintsThanMiMC(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	anemoiCS, err := frontend.Compile(field, r1cs.NewBuilder, &compressCircuit{})
+	assert.NoError(err)
+	mimcCS, err := frontend.Compile(field, r1cs.NewBuilder, &compressCircuit{mimc: true})
+	assert.NoError(err)
+	assert.Less(2*anemoiCS.GetNbConstraints(), mimcCS.GetNbConstraints())
+}
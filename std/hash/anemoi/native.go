@@ -0,0 +1,67 @@
+package anemoi
+
+import (
+	"math/big"
+)
+
+// Permute applies the Anemoi permutation to the state (x, y) in place. x and y
+// must be reduced modulo p.Field.
+func (p *Parameters) Permute(x, y *big.Int) {
+	f := p.Field
+	var t big.Int
+	for r := 0; r < p.NbRounds; r++ {
+		x.Add(x, p.C[r])
+		y.Add(y, p.D[r])
+		p.linearLayer(x, y)
+
+		// open Flystel
+		t.Mul(y, y).Mul(&t, p.G)
+		x.Sub(x, &t).Mod(x, f)
+		t.Exp(x, p.AlphaInv, f)
+		y.Sub(y, &t).Mod(y, f)
+		t.Mul(y, y).Mul(&t, p.G).Add(&t, p.GInv)
+		x.Add(x, &t).Mod(x, f)
+	}
+	p.linearLayer(x, y)
+}
+
+// linearLayer applies the pseudo-Hadamard transform, the linear layer of a
+// single column.
+func (p *Parameters) linearLayer(x, y *big.Int) {
+	y.Add(y, x).Mod(y, p.Field)
+	x.Add(x, y).Mod(x, p.Field)
+}
+
+// Jive returns the Jive compression of a and b: a + b + u + v where (u, v) is
+// the Anemoi permutation of (a, b). It is the out-of-circuit counterpart of
+// [Jive].
+func (p *Parameters) Jive(a, b *big.Int) *big.Int {
+	u := new(big.Int).Mod(a, p.Field)
+	v := new(big.Int).Mod(b, p.Field)
+	res := new(big.Int).Add(u, v)
+	p.Permute(u, v)
+	res.Add(res, u).Add(res, v)
+	return res.Mod(res, p.Field)
+}
+
+// HashElements returns the digest of the given field elements. It is the
+// out-of-circuit counterpart of [Anemoi.Sum].
+//
+// The elements are chained with the Jive compression: the digest of d0, d1,
+// ..., dn is Jive(...Jive(Jive(d0, d1), d2)..., dn), so that the digest of two
+// elements is their Jive compression. A single element d0 is compressed with
+// zero and the digest of no element is Jive(0, 0). The inputs are not padded,
+// the length of the input should be fixed by the application.
+func (p *Parameters) HashElements(data ...*big.Int) *big.Int {
+	switch len(data) {
+	case 0:
+		return p.Jive(new(big.Int), new(big.Int))
+	case 1:
+		return p.Jive(data[0], new(big.Int))
+	}
+	res := p.Jive(data[0], data[1])
+	for _, d := range data[2:] {
+		res = p.Jive(res, d)
+	}
+	return res
+}
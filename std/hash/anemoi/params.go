@@ -0,0 +1,153 @@
+package anemoi
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/params"
+)
+
+// Parameters define an instance of the Anemoi permutation with a single column
+// (a state of two field elements) over a prime field.
+type Parameters struct {
+	Field    *big.Int // modulus of the field
+	Alpha    int      // exponent of the Flystel S-box
+	AlphaInv *big.Int // inverse of Alpha modulo Field-1
+	G        *big.Int // generator of the multiplicative group, the β of the Flystel
+	GInv     *big.Int // inverse of G, the δ of the Flystel
+	NbRounds int      // number of rounds
+
+	// C and D are the round constants added to the x and y coordinates.
+	C, D []*big.Int
+}
+
+// generators are the generators of the multiplicative groups of the scalar
+// fields used by gnark-crypto.
+var generators = map[ecc.ID]int64{
+	ecc.BN254:     5,
+	ecc.BLS12_377: 22,
+	ecc.BLS12_381: 7,
+	ecc.BLS24_315: 7,
+	ecc.BLS24_317: 7,
+	ecc.BW6_633:   13,
+	ecc.BW6_761:   15,
+}
+
+// nbRounds is the number of rounds for 128 bits of security with a single
+// column, indexed by the S-box exponent.
+var nbRounds = map[int]int{3: 21, 5: 21, 7: 20, 11: 19}
+
+// pi0 and pi1 are the first and the next 100 decimals of π, from which the
+// round constants are derived.
+const (
+	pi0 = "1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679"
+	pi1 = "8214808651328230664709384460955058223172535940812848111745028410270193852110555964462294895493038196"
+)
+
+var (
+	parametersCache   = make(map[string]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// GetParameters returns the parameters of the Anemoi permutation with a single
+// column over the given field, targeting 128 bits of security. The field must
+// be the scalar field of one of the curves supported by gnark.
+//
+// The S-box exponent is the smallest alpha such that gcd(alpha, p-1) = 1, the
+// number of rounds is the one of the specification for this exponent and the
+// round constants are the ones of the reference implementation for the column
+// i = 0,
+//
+//	C[r] = g⋅π0^(2r) + (π0^r + π1^i)^alpha = g⋅π0^(2r) + (π0^r + 1)^alpha
+//	D[r] = g⋅π1^(2i) + (π0^r + π1^i)^alpha + g^-1 = g + (π0^r + 1)^alpha + g^-1
+//
+// where g is the generator of the multiplicative group used by gnark-crypto.
+//
+// The returned parameters are shared and must not be modified.
+func GetParameters(field *big.Int) (*Parameters, error) {
+	key := field.Text(16)
+
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[key]; ok {
+		return p, nil
+	}
+
+	g, ok := generators[utils.FieldToCurve(field)]
+	if !ok {
+		return nil, fmt.Errorf("no generator known for field %s", field.Text(16))
+	}
+	alpha, alphaInv, err := sboxExponents(field)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := nbRounds[alpha]
+	if !ok {
+		return nil, fmt.Errorf("no number of rounds for the S-box exponent %d", alpha)
+	}
+	p := &Parameters{
+		Field:    new(big.Int).Set(field),
+		Alpha:    alpha,
+		AlphaInv: alphaInv,
+		G:        big.NewInt(g),
+		GInv:     new(big.Int).ModInverse(big.NewInt(g), field),
+		NbRounds: n,
+		C:        make([]*big.Int, n),
+		D:        make([]*big.Int, n),
+	}
+
+	p0, _ := new(big.Int).SetString(pi0, 10)
+	p0.Mod(p0, field)
+	// π1^i for the single column i = 0
+	p1i := big.NewInt(1)
+	e := big.NewInt(int64(alpha))
+	// g⋅π1^(2i) + g^-1
+	d := new(big.Int).Mul(p1i, p1i)
+	d.Mul(d, p.G).Add(d, p.GInv).Mod(d, field)
+	p0r := big.NewInt(1)
+	for r := 0; r < n; r++ {
+		t := new(big.Int).Add(p0r, p1i)
+		t.Exp(t, e, field)
+		p.C[r] = new(big.Int).Mul(p0r, p0r)
+		p.C[r].Mul(p.C[r], p.G).Add(p.C[r], t).Mod(p.C[r], field)
+		p.D[r] = new(big.Int).Add(d, t)
+		p.D[r].Mod(p.D[r], field)
+		p0r.Mul(p0r, p0).Mod(p0r, field)
+	}
+
+	parametersCache[key] = p
+	return p, nil
+}
+
+// sboxExponents returns the smallest alpha >= 3 such that x -> x^alpha is a
+// permutation of the field, and its inverse modulo p-1.
+func sboxExponents(field *big.Int) (int, *big.Int, error) {
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	var gcd big.Int
+	for alpha := 3; alpha < 256; alpha++ {
+		a := big.NewInt(int64(alpha))
+		if gcd.GCD(nil, nil, a, pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return alpha, new(big.Int).ModInverse(a, pMinusOne), nil
+		}
+	}
+	return 0, nil, fmt.Errorf("no suitable S-box exponent for field %s", field.Text(16))
+}
+
+func init() {
+	for id := range generators {
+		id := id
+		name := fmt.Sprintf("anemoi/%s/l=1", id)
+		seed := fmt.Sprintf("pi0=%s, pi1=%s", pi0, pi1)
+		derivation := fmt.Sprintf("round constants C then D of the column i = 0, C[r] = g⋅pi0^(2r) + (pi0^r + pi1^i)^alpha and D[r] = g⋅pi1^(2i) + (pi0^r + pi1^i)^alpha + g^-1 modulo the scalar field of %s, where g is the generator of the multiplicative group used by gnark-crypto", id)
+		params.Register(name, 2, seed, derivation, func() ([]*big.Int, error) {
+			p, err := GetParameters(id.ScalarField())
+			if err != nil {
+				return nil, err
+			}
+			return append(append([]*big.Int{}, p.C...), p.D...), nil
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package blake2
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// Size2b is the maximal digest size of BLAKE2b in bytes.
+	Size2b = 64
+	// Size2s is the maximal digest size of BLAKE2s in bytes.
+	Size2s = 32
+)
+
+// params define an instance of the BLAKE2 family.
+type params struct {
+	blockSize int       // size of a block in bytes
+	wordSize  int       // size of a word in bytes
+	rounds    int       // number of rounds of the compression function
+	iv        [8]uint64 // initialization vector
+	rot       [4]int    // right rotations of the mixing function
+}
+
+var params2b = params{
+	blockSize: 128,
+	wordSize:  8,
+	rounds:    12,
+	iv: [8]uint64{
+		0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+		0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+	},
+	rot: [4]int{32, 24, 16, 63},
+}
+
+var params2s = params{
+	blockSize: 64,
+	wordSize:  4,
+	rounds:    10,
+	iv: [8]uint64{
+		0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+		0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+	},
+	rot: [4]int{16, 12, 8, 7},
+}
+
+// sigma are the message word permutations of the rounds
+var sigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+type digest[T uints.Long] struct {
+	uapi *uints.BinaryField[T]
+	p    *params
+	size int        // digest size in bytes
+	key  []uints.U8 // optional key
+	in   []uints.U8 // input to be digested
+}
+
+// NewBlake2b returns a new BLAKE2b hash with a digest of size bytes. If key is
+// not empty, the hash is keyed (MAC mode). The size must be between 1 and
+// [Size2b] and the key must be at most [Size2b] bytes long.
+func NewBlake2b(api frontend.API, size int, key []uints.U8) (hash.BinaryHasher, error) {
+	if size < 1 || size > Size2b {
+		return nil, fmt.Errorf("invalid digest size %d, must be in [1, %d]", size, Size2b)
+	}
+	if len(key) > Size2b {
+		return nil, fmt.Errorf("invalid key size %d, must be at most %d", len(key), Size2b)
+	}
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest[uints.U64]{uapi: uapi, p: &params2b, size: size, key: key}, nil
+}
+
+// NewBlake2s returns a new BLAKE2s hash with a digest of size bytes. If key is
+// not empty, the hash is keyed (MAC mode). The size must be between 1 and
+// [Size2s] and the key must be at most [Size2s] bytes long.
+func NewBlake2s(api frontend.API, size int, key []uints.U8) (hash.BinaryHasher, error) {
+	if size < 1 || size > Size2s {
+		return nil, fmt.Errorf("invalid digest size %d, must be in [1, %d]", size, Size2s)
+	}
+	if len(key) > Size2s {
+		return nil, fmt.Errorf("invalid key size %d, must be at most %d", len(key), Size2s)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest[uints.U32]{uapi: uapi, p: &params2s, size: size, key: key}, nil
+}
+
+func (d *digest[T]) Write(in []uints.U8) {
+	d.in = append(d.in, in...)
+}
+
+func (d *digest[T]) Size() int { return d.size }
+
+func (d *digest[T]) Reset() {
+	d.in = nil
+}
+
+func (d *digest[T]) Sum() []uints.U8 {
+	bb := d.p.blockSize
+
+	// parameter block: digest size, key size, fanout = depth = 1
+	var h [8]T
+	for i := range h {
+		iv := d.p.iv[i]
+		if i == 0 {
+			iv ^= 0x01010000 ^ uint64(len(d.key))<<8 ^ uint64(d.size)
+		}
+		h[i] = d.word(iv)
+	}
+
+	// the key is padded to a full block and prepended to the message
+	var data []uints.U8
+	if len(d.key) > 0 {
+		data = append(data, d.key...)
+		data = append(data, uints.NewU8Array(make([]uint8, bb-len(d.key)))...)
+	}
+	data = append(data, d.in...)
+	length := len(data)
+
+	nbBlocks := (length + bb - 1) / bb
+	if nbBlocks == 0 {
+		nbBlocks = 1
+	}
+	data = append(data, uints.NewU8Array(make([]uint8, nbBlocks*bb-length))...)
+
+	for i := 0; i < nbBlocks; i++ {
+		last := i == nbBlocks-1
+		t := uint64((i + 1) * bb)
+		if last {
+			t = uint64(length)
+		}
+		d.compress(&h, data[i*bb:(i+1)*bb], t, last)
+	}
+
+	var out []uints.U8
+	for i := range h {
+		out = append(out, d.uapi.UnpackLSB(h[i])...)
+	}
+	return out[:d.size]
+}
+
+// compress applies the compression function F on h with the message block m,
+// the number of bytes t processed so far and the final block flag.
+func (d *digest[T]) compress(h *[8]T, block []uints.U8, t uint64, last bool) {
+	ws := d.p.wordSize
+	var m [16]T
+	for i := range m {
+		m[i] = d.uapi.PackLSB(block[i*ws : (i+1)*ws]...)
+	}
+
+	// the counter and the flag are constants, so they are applied to the IV
+	// directly.
+	var v [16]T
+	copy(v[:8], h[:])
+	for i := 0; i < 8; i++ {
+		iv := d.p.iv[i]
+		switch {
+		case i == 4:
+			iv ^= t & d.mask()
+		case i == 5 && ws < 8:
+			iv ^= t >> (8 * ws)
+		case i == 6 && last:
+			iv ^= d.mask()
+		}
+		v[8+i] = d.word(iv)
+	}
+
+	for r := 0; r < d.p.rounds; r++ {
+		s := &sigma[r%10]
+		d.g(&v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+		d.g(&v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+		d.g(&v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+		d.g(&v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+		d.g(&v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+		d.g(&v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+		d.g(&v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+		d.g(&v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+
+	for i := range h {
+		h[i] = d.uapi.Xor(h[i], v[i], v[i+8])
+	}
+}
+
+// g is the mixing function G.
+func (d *digest[T]) g(v *[16]T, a, b, c, e int, x, y T) {
+	u := d.uapi
+	rot := d.p.rot
+	v[a] = u.Add(v[a], v[b], x)
+	v[e] = u.Lrot(u.Xor(v[e], v[a]), -rot[0])
+	v[c] = u.Add(v[c], v[e])
+	v[b] = u.Lrot(u.Xor(v[b], v[c]), -rot[1])
+	v[a] = u.Add(v[a], v[b], y)
+	v[e] = u.Lrot(u.Xor(v[e], v[a]), -rot[2])
+	v[c] = u.Add(v[c], v[e])
+	v[b] = u.Lrot(u.Xor(v[b], v[c]), -rot[3])
+}
+
+// word returns the constant word v.
+func (d *digest[T]) word(v uint64) T {
+	bts := make([]uint8, d.p.wordSize)
+	for i := range bts {
+		bts[i] = uint8(v >> (8 * i))
+	}
+	return d.uapi.PackLSB(uints.NewU8Array(bts)...)
+}
+
+// mask returns the word with all bits set.
+func (d *digest[T]) mask() uint64 {
+	return ^uint64(0) >> (64 - 8*d.p.wordSize)
+}
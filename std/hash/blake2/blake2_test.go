@@ -0,0 +1,140 @@
+package blake2
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+)
+
+type blake2Circuit struct {
+	In       []uints.U8
+	Key      []uints.U8
+	Expected []uints.U8
+
+	variant string
+}
+
+func (c *blake2Circuit) Define(api frontend.API) error {
+	var newHasher = NewBlake2b
+	switch c.variant {
+	case "BLAKE2b":
+	case "BLAKE2s":
+		newHasher = NewBlake2s
+	default:
+		return fmt.Errorf("hash function unknown: %s", c.variant)
+	}
+	h, err := newHasher(api, len(c.Expected), c.Key)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != len(c.Expected) {
+		return fmt.Errorf("expected %d bytes, got %d", len(c.Expected), len(res))
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func nativeBlake2(variant string, size int, key, in []byte) ([]byte, error) {
+	switch variant {
+	case "BLAKE2b":
+		h, err := blake2b.New(size, key)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(in)
+		return h.Sum(nil), nil
+	case "BLAKE2s":
+		// x/crypto only exposes BLAKE2s with 16 or 32 bytes digests
+		var h interface {
+			Write([]byte) (int, error)
+			Sum([]byte) []byte
+		}
+		var err error
+		if size == 16 {
+			h, err = blake2s.New128(key)
+		} else {
+			h, err = blake2s.New256(key)
+		}
+		if err != nil {
+			return nil, err
+		}
+		h.Write(in)
+		return h.Sum(nil), nil
+	}
+	return nil, fmt.Errorf("hash function unknown: %s", variant)
+}
+
+func TestBlake2(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := make([]byte, 200)
+	_, err := rand.Reader.Read(in)
+	assert.NoError(err)
+	key := make([]byte, 32)
+	_, err = rand.Reader.Read(key)
+	assert.NoError(err)
+
+	for _, variant := range []string{"BLAKE2b", "BLAKE2s"} {
+		sizes := []int{Size2b, 20}
+		if variant == "BLAKE2s" {
+			sizes = []int{Size2s, 16}
+		}
+		for _, size := range sizes {
+			for _, keyLen := range []int{0, 32} {
+				// lengths around the block boundaries of both variants
+				for _, inLen := range []int{0, 64, 65, 129} {
+					variant, size, keyLen, inLen := variant, size, keyLen, inLen
+					if keyLen == 0 && variant == "BLAKE2s" && size == 16 {
+						// x/crypto only implements the keyed BLAKE2s-128
+						continue
+					}
+					assert.Run(func(assert *test.Assert) {
+						expected, err := nativeBlake2(variant, size, key[:keyLen], in[:inLen])
+						assert.NoError(err)
+
+						circuit := &blake2Circuit{
+							In:       make([]uints.U8, inLen),
+							Key:      make([]uints.U8, keyLen),
+							Expected: make([]uints.U8, len(expected)),
+							variant:  variant,
+						}
+						witness := &blake2Circuit{
+							In:       uints.NewU8Array(in[:inLen]),
+							Key:      uints.NewU8Array(key[:keyLen]),
+							Expected: uints.NewU8Array(expected),
+						}
+						err = test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+						assert.NoError(err)
+					}, variant, fmt.Sprintf("size=%d", size), fmt.Sprintf("key=%d", keyLen), fmt.Sprintf("in=%d", inLen))
+				}
+			}
+		}
+	}
+}
+
+func TestBlake2Params(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := NewBlake2b(nil, 0, nil)
+	assert.Error(err)
+	_, err = NewBlake2b(nil, Size2b+1, nil)
+	assert.Error(err)
+	_, err = NewBlake2s(nil, Size2s+1, nil)
+	assert.Error(err)
+	_, err = NewBlake2s(nil, Size2s, make([]uints.U8, Size2s+1))
+	assert.Error(err)
+}
@@ -0,0 +1,11 @@
+// Package blake2 implements the BLAKE2b and BLAKE2s hash functions.
+//
+// Both functions support a configurable digest size and an optional key (for
+// use as a MAC), as defined in [RFC 7693]. The instances are compatible with
+// golang.org/x/crypto/blake2b and golang.org/x/crypto/blake2s.
+//
+// The length of the message and of the key must be known at compile time. The
+// operations on words use the lookup-based [uints] package.
+//
+// [RFC 7693]: https://www.rfc-editor.org/rfc/rfc7693
+package blake2
@@ -0,0 +1,241 @@
+package blake3
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// Size is the default digest size of BLAKE3 in bytes.
+	Size = 32
+	// KeySize is the size of the key in the keyed hash mode, in bytes.
+	KeySize = 32
+
+	blockSize = 64
+	chunkSize = 1024
+	nbRounds  = 7
+)
+
+// domain separation flags
+const (
+	flagChunkStart = 1 << iota
+	flagChunkEnd
+	flagParent
+	flagRoot
+	flagKeyedHash
+)
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+	0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var msgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+type digest struct {
+	uapi  *uints.BinaryField[uints.U32]
+	size  int          // digest size in bytes
+	key   [8]uints.U32 // key words (IV in the hash mode)
+	flags uint32       // flags of the mode (hash or keyed hash)
+	in    []uints.U8   // input to be digested
+}
+
+// New returns a new BLAKE3 hash with a digest of size bytes. As BLAKE3 is an
+// extendable output function, any positive size is accepted; use [Size] for
+// the standard digest. If key is not empty, the hash is in the keyed hash mode
+// and the key must be [KeySize] bytes long.
+func New(api frontend.API, size int, key []uints.U8) (hash.BinaryHasher, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid digest size %d, must be positive", size)
+	}
+	if len(key) != 0 && len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size %d, must be %d", len(key), KeySize)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	d := &digest{uapi: uapi, size: size}
+	if len(key) == 0 {
+		for i := range d.key {
+			d.key[i] = uints.NewU32(iv[i])
+		}
+	} else {
+		for i := range d.key {
+			d.key[i] = uapi.PackLSB(key[4*i : 4*i+4]...)
+		}
+		d.flags = flagKeyedHash
+	}
+	return d, nil
+}
+
+func (d *digest) Write(in []uints.U8) {
+	d.in = append(d.in, in...)
+}
+
+func (d *digest) Size() int { return d.size }
+
+func (d *digest) Reset() {
+	d.in = nil
+}
+
+// output is the input of a compression, from which either the chaining value
+// of a node or the root output bytes are derived.
+type output struct {
+	cv       [8]uints.U32
+	block    [16]uints.U32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+// Sum returns the digest of the written data. The data is split in chunks of
+// 1024 bytes which are the leaves of a binary tree. The left subtree of each
+// node holds the largest power of two number of chunks, as in the tree hashing
+// mode of the specification, so the digest matches the native BLAKE3.
+func (d *digest) Sum() []uints.U8 {
+	in := d.in
+
+	// the chaining values of the complete subtrees, merged as soon as
+	// possible. The last chunk is never merged, as it may be the root.
+	var stack [][8]uints.U32
+	var chunkCounter uint64
+	for len(in) > chunkSize {
+		cv := d.chainingValue(d.chunkOutput(in[:chunkSize], chunkCounter))
+		in = in[chunkSize:]
+		chunkCounter++
+		for total := chunkCounter; total&1 == 0; total >>= 1 {
+			cv = d.chainingValue(d.parentOutput(stack[len(stack)-1], cv))
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, cv)
+	}
+	o := d.chunkOutput(in, chunkCounter)
+	for i := len(stack) - 1; i >= 0; i-- {
+		o = d.parentOutput(stack[i], d.chainingValue(o))
+	}
+
+	// root output bytes
+	var res []uints.U8
+	for counter := uint64(0); len(res) < d.size; counter++ {
+		words := d.compress(&o.cv, &o.block, counter, o.blockLen, o.flags|flagRoot)
+		for i := range words {
+			res = append(res, d.uapi.UnpackLSB(words[i])...)
+		}
+	}
+	return res[:d.size]
+}
+
+// chunkOutput compresses all the blocks of chunk but the last one, and returns
+// the output of the last block.
+func (d *digest) chunkOutput(chunk []uints.U8, counter uint64) output {
+	cv := d.key
+	start := uint32(flagChunkStart)
+	for len(chunk) > blockSize {
+		cv = d.chainingValue(output{
+			cv:       cv,
+			block:    d.block(chunk[:blockSize]),
+			counter:  counter,
+			blockLen: blockSize,
+			flags:    d.flags | start,
+		})
+		chunk = chunk[blockSize:]
+		start = 0
+	}
+	return output{
+		cv:       cv,
+		block:    d.block(chunk),
+		counter:  counter,
+		blockLen: uint32(len(chunk)),
+		flags:    d.flags | start | flagChunkEnd,
+	}
+}
+
+// parentOutput returns the output of the parent node of the left and right
+// chaining values.
+func (d *digest) parentOutput(left, right [8]uints.U32) output {
+	o := output{
+		cv:       d.key,
+		blockLen: blockSize,
+		flags:    d.flags | flagParent,
+	}
+	copy(o.block[:8], left[:])
+	copy(o.block[8:], right[:])
+	return o
+}
+
+// chainingValue returns the chaining value of a non-root node.
+func (d *digest) chainingValue(o output) [8]uints.U32 {
+	var cv [8]uints.U32
+	words := d.compress(&o.cv, &o.block, o.counter, o.blockLen, o.flags)
+	copy(cv[:], words[:8])
+	return cv
+}
+
+// block returns the words of the message block b, padded with zeros.
+func (d *digest) block(b []uints.U8) [16]uints.U32 {
+	padded := make([]uints.U8, blockSize)
+	copy(padded, b)
+	for i := len(b); i < blockSize; i++ {
+		padded[i] = uints.NewU8(0)
+	}
+	var m [16]uints.U32
+	for i := range m {
+		m[i] = d.uapi.PackLSB(padded[4*i : 4*i+4]...)
+	}
+	return m
+}
+
+// compress is the compression function of BLAKE3. The counter, the block
+// length and the flags are known at compile time.
+func (d *digest) compress(cv *[8]uints.U32, block *[16]uints.U32, counter uint64, blockLen, flags uint32) [16]uints.U32 {
+	u := d.uapi
+	var v [16]uints.U32
+	copy(v[:8], cv[:])
+	for i := 0; i < 4; i++ {
+		v[8+i] = uints.NewU32(iv[i])
+	}
+	v[12] = uints.NewU32(uint32(counter))
+	v[13] = uints.NewU32(uint32(counter >> 32))
+	v[14] = uints.NewU32(blockLen)
+	v[15] = uints.NewU32(flags)
+
+	m := *block
+	for r := 0; r < nbRounds; r++ {
+		d.g(&v, 0, 4, 8, 12, m[0], m[1])
+		d.g(&v, 1, 5, 9, 13, m[2], m[3])
+		d.g(&v, 2, 6, 10, 14, m[4], m[5])
+		d.g(&v, 3, 7, 11, 15, m[6], m[7])
+		d.g(&v, 0, 5, 10, 15, m[8], m[9])
+		d.g(&v, 1, 6, 11, 12, m[10], m[11])
+		d.g(&v, 2, 7, 8, 13, m[12], m[13])
+		d.g(&v, 3, 4, 9, 14, m[14], m[15])
+		var p [16]uints.U32
+		for i := range p {
+			p[i] = m[msgPermutation[i]]
+		}
+		m = p
+	}
+
+	for i := 0; i < 8; i++ {
+		v[i] = u.Xor(v[i], v[i+8])
+		v[i+8] = u.Xor(v[i+8], cv[i])
+	}
+	return v
+}
+
+// g is the mixing function G.
+func (d *digest) g(v *[16]uints.U32, a, b, c, e int, x, y uints.U32) {
+	u := d.uapi
+	v[a] = u.Add(v[a], v[b], x)
+	v[e] = u.Lrot(u.Xor(v[e], v[a]), -16)
+	v[c] = u.Add(v[c], v[e])
+	v[b] = u.Lrot(u.Xor(v[b], v[c]), -12)
+	v[a] = u.Add(v[a], v[b], y)
+	v[e] = u.Lrot(u.Xor(v[e], v[a]), -8)
+	v[c] = u.Add(v[c], v[e])
+	v[b] = u.Lrot(u.Xor(v[b], v[c]), -7)
+}
@@ -0,0 +1,105 @@
+package blake3
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type blake3Circuit struct {
+	In       []uints.U8
+	Key      []uints.U8
+	Expected []uints.U8
+}
+
+func (c *blake3Circuit) Define(api frontend.API) error {
+	h, err := New(api, len(c.Expected), c.Key)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != len(c.Expected) {
+		return fmt.Errorf("expected %d bytes, got %d", len(c.Expected), len(res))
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestBlake3(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := make([]byte, 3*chunkSize+1)
+	_, err := rand.Reader.Read(in)
+	assert.NoError(err)
+	key := make([]byte, KeySize)
+	_, err = rand.Reader.Read(key)
+	assert.NoError(err)
+
+	// lengths around the block and chunk boundaries. 3 full chunks and a
+	// partial one exercise a tree which is not complete.
+	for _, inLen := range []int{0, 64, 65, chunkSize, chunkSize + 1, 3*chunkSize + 1} {
+		for _, keyLen := range []int{0, KeySize} {
+			for _, size := range []int{Size, 100} {
+				inLen, keyLen, size := inLen, keyLen, size
+				assert.Run(func(assert *test.Assert) {
+					expected := Sum(in[:inLen], key[:keyLen], size)
+					circuit := &blake3Circuit{
+						In:       make([]uints.U8, inLen),
+						Key:      make([]uints.U8, keyLen),
+						Expected: make([]uints.U8, size),
+					}
+					witness := &blake3Circuit{
+						In:       uints.NewU8Array(in[:inLen]),
+						Key:      uints.NewU8Array(key[:keyLen]),
+						Expected: uints.NewU8Array(expected),
+					}
+					err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+					assert.NoError(err)
+				}, fmt.Sprintf("in=%d", inLen), fmt.Sprintf("key=%d", keyLen), fmt.Sprintf("size=%d", size))
+			}
+		}
+	}
+}
+
+func TestBlake3Params(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := New(nil, 0, nil)
+	assert.Error(err)
+	_, err = New(nil, Size, make([]uints.U8, KeySize-1))
+	assert.Error(err)
+}
+
+func TestSum(t *testing.T) {
+	assert := test.NewAssert(t)
+	// digests of the BLAKE3 test vectors, where the input of length n is the
+	// sequence 0, 1, ..., 250, 0, 1, ... of n bytes.
+	vectors := []struct {
+		n        int
+		expected string
+	}{
+		{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+	}
+	for _, v := range vectors {
+		in := make([]byte, v.n)
+		for i := range in {
+			in[i] = byte(i % 251)
+		}
+		assert.Equal(v.expected, hex.EncodeToString(Sum(in, nil, Size)), v.n)
+	}
+	assert.Equal("6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85", hex.EncodeToString(Sum([]byte("abc"), nil, Size)))
+}
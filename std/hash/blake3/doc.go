@@ -0,0 +1,16 @@
+// Package blake3 implements the BLAKE3 hash function.
+//
+// The input is split in chunks of 1024 bytes which are hashed independently
+// and then merged in a binary tree, as defined in the [specification]. This
+// tree hashing mode is always used for inputs larger than one chunk, so the
+// digest of the circuit matches the one computed by the native implementations
+// (for example b3sum) on the same data. The hash mode and the keyed hash mode
+// are supported, with an arbitrary output length. The key derivation mode is
+// not implemented.
+//
+// The length of the message must be known at compile time. The operations on
+// words use the lookup-based [uints] package. [Sum] is the out-of-circuit
+// implementation for computing the witness.
+//
+// [specification]: https://github.com/BLAKE3-team/BLAKE3-specs/blob/master/blake3.pdf
+package blake3
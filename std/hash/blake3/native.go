@@ -0,0 +1,122 @@
+package blake3
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Sum returns the BLAKE3 digest of size bytes of in. If key is not empty, the
+// digest is computed in the keyed hash mode and key must be [KeySize] bytes
+// long. It is the out-of-circuit counterpart of [New], following the reference
+// implementation of the specification.
+func Sum(in, key []byte, size int) []byte {
+	cvKey := iv
+	flags := uint32(0)
+	if len(key) > 0 {
+		for i := range cvKey {
+			cvKey[i] = binary.LittleEndian.Uint32(key[4*i:])
+		}
+		flags = flagKeyedHash
+	}
+
+	compress := func(cv [8]uint32, block []byte, counter uint64, blockLen, flags uint32) [16]uint32 {
+		var m [16]uint32
+		var buf [blockSize]byte
+		copy(buf[:], block)
+		for i := range m {
+			m[i] = binary.LittleEndian.Uint32(buf[4*i:])
+		}
+		v := [16]uint32{
+			cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+			iv[0], iv[1], iv[2], iv[3],
+			uint32(counter), uint32(counter >> 32), blockLen, flags,
+		}
+		g := func(a, b, c, d int, x, y uint32) {
+			v[a] += v[b] + x
+			v[d] = bits.RotateLeft32(v[d]^v[a], -16)
+			v[c] += v[d]
+			v[b] = bits.RotateLeft32(v[b]^v[c], -12)
+			v[a] += v[b] + y
+			v[d] = bits.RotateLeft32(v[d]^v[a], -8)
+			v[c] += v[d]
+			v[b] = bits.RotateLeft32(v[b]^v[c], -7)
+		}
+		for r := 0; r < nbRounds; r++ {
+			g(0, 4, 8, 12, m[0], m[1])
+			g(1, 5, 9, 13, m[2], m[3])
+			g(2, 6, 10, 14, m[4], m[5])
+			g(3, 7, 11, 15, m[6], m[7])
+			g(0, 5, 10, 15, m[8], m[9])
+			g(1, 6, 11, 12, m[10], m[11])
+			g(2, 7, 8, 13, m[12], m[13])
+			g(3, 4, 9, 14, m[14], m[15])
+			var p [16]uint32
+			for i := range p {
+				p[i] = m[msgPermutation[i]]
+			}
+			m = p
+		}
+		for i := 0; i < 8; i++ {
+			v[i] ^= v[i+8]
+			v[i+8] ^= cv[i]
+		}
+		return v
+	}
+	type output struct {
+		cv       [8]uint32
+		block    []byte
+		counter  uint64
+		blockLen uint32
+		flags    uint32
+	}
+	chainingValue := func(o output) (cv [8]uint32) {
+		words := compress(o.cv, o.block, o.counter, o.blockLen, o.flags)
+		copy(cv[:], words[:8])
+		return
+	}
+	chunkOutput := func(chunk []byte, counter uint64) output {
+		cv := cvKey
+		start := uint32(flagChunkStart)
+		for len(chunk) > blockSize {
+			cv = chainingValue(output{cv, chunk[:blockSize], counter, blockSize, flags | start})
+			chunk = chunk[blockSize:]
+			start = 0
+		}
+		return output{cv, chunk, counter, uint32(len(chunk)), flags | start | flagChunkEnd}
+	}
+	parentOutput := func(left, right [8]uint32) output {
+		block := make([]byte, blockSize)
+		for i := 0; i < 8; i++ {
+			binary.LittleEndian.PutUint32(block[4*i:], left[i])
+			binary.LittleEndian.PutUint32(block[32+4*i:], right[i])
+		}
+		return output{cvKey, block, 0, blockSize, flags | flagParent}
+	}
+
+	// chunks are merged as soon as possible, except the last one.
+	var stack [][8]uint32
+	var chunkCounter uint64
+	for len(in) > chunkSize {
+		cv := chainingValue(chunkOutput(in[:chunkSize], chunkCounter))
+		in = in[chunkSize:]
+		chunkCounter++
+		for total := chunkCounter; total&1 == 0; total >>= 1 {
+			cv = chainingValue(parentOutput(stack[len(stack)-1], cv))
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, cv)
+	}
+	o := chunkOutput(in, chunkCounter)
+	for i := len(stack) - 1; i >= 0; i-- {
+		o = parentOutput(stack[i], chainingValue(o))
+	}
+
+	var res []byte
+	for counter := uint64(0); len(res) < size; counter++ {
+		words := compress(o.cv, o.block, counter, o.blockLen, o.flags|flagRoot)
+		for _, w := range words {
+			res = binary.LittleEndian.AppendUint32(res, w)
+		}
+	}
+	return res[:size]
+}
@@ -0,0 +1,111 @@
+// Package gmimc provides a ZKP-circuit function to compute a GMiMC hash.
+//
+// GMiMC, described in [Feistel Structures for MPC, and More], generalizes MiMC
+// to a state of several field elements with an unbalanced Feistel network.
+// The round function is either expanding ([ERF]) or contracting ([CRF]), each
+// round costing a single S-box x -> x^d. With a wide state, many elements are
+// absorbed by a single permutation, instead of one permutation per element
+// with the Miyaguchi–Preneel chaining of MiMC.
+//
+// [Feistel Structures for MPC, and More]: https://eprint.iacr.org/2019/397
+package gmimc
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// GMiMC computes the GMiMC sponge hash of field elements in-circuit. It
+// implements [github.com/consensys/gnark/std/hash.FieldHasher].
+type GMiMC struct {
+	api    frontend.API
+	params *Parameters
+	data   []frontend.Variable
+}
+
+// NewGMiMC returns a GMiMC instance with the default parameters for the given
+// round function over the native field (see [GetDefaultParameters]), than can
+// be used in a gnark circuit.
+func NewGMiMC(api frontend.API, mode Mode) (GMiMC, error) {
+	params, err := GetDefaultParameters(api.Compiler().Field(), mode)
+	if err != nil {
+		return GMiMC{}, err
+	}
+	return NewGMiMCWithParameters(api, params), nil
+}
+
+// NewGMiMCWithParameters returns a GMiMC instance using the given parameters.
+// The parameters must be defined over the native field.
+func NewGMiMCWithParameters(api frontend.API, params *Parameters) GMiMC {
+	return GMiMC{api: api, params: params}
+}
+
+// Write adds more data to the running hash.
+func (h *GMiMC) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *GMiMC) Reset() {
+	h.data = nil
+}
+
+// Sum returns the digest of the data written since the last reset. See
+// [Parameters.HashElements] for the absorption of the inputs.
+func (h *GMiMC) Sum() frontend.Variable {
+	rate := h.params.T - 1
+	state := make([]frontend.Variable, h.params.T)
+	for i := range state {
+		state[i] = 0
+	}
+	state[h.params.T-1] = len(h.data)
+	if len(h.data) == 0 {
+		state = Permutation(h.api, h.params, state)
+	}
+	for i := 0; i < len(h.data); i += rate {
+		for j := 0; j < rate && i+j < len(h.data); j++ {
+			state[j] = h.api.Add(state[j], h.data[i+j])
+		}
+		state = Permutation(h.api, h.params, state)
+	}
+	return state[0]
+}
+
+// Permutation returns the GMiMC permutation of state. The length of state must
+// be params.T.
+func Permutation(api frontend.API, params *Parameters, state []frontend.Variable) []frontend.Variable {
+	if len(state) != params.T {
+		panic("state length does not match the permutation width")
+	}
+	res := make([]frontend.Variable, params.T)
+	copy(res, state)
+	for r := 0; r < params.NbRounds; r++ {
+		switch params.Mode {
+		case ERF:
+			s := pow(api, api.Add(res[0], params.RoundConstants[r]), params.D)
+			for i := 1; i < len(res); i++ {
+				res[i] = api.Add(res[i], s)
+			}
+		case CRF:
+			s := pow(api, api.Add(params.RoundConstants[r], res[1], res[2:]...), params.D)
+			res[0] = api.Add(res[0], s)
+		default:
+			panic("invalid mode")
+		}
+		res = append(res[1:], res[0])
+	}
+	return res
+}
+
+// pow returns x^d.
+func pow(api frontend.API, x frontend.Variable, d int) frontend.Variable {
+	res := x
+	for i := bits.Len(uint(d)) - 2; i >= 0; i-- {
+		res = api.Mul(res, res)
+		if (d>>i)&1 == 1 {
+			res = api.Mul(res, x)
+		}
+	}
+	return res
+}
@@ -0,0 +1,176 @@
+package gmimc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bn254mimc "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type gmimcCircuit struct {
+	Data     [10]frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+
+	mode Mode
+}
+
+func (c *gmimcCircuit) Define(api frontend.API) error {
+	h, err := NewGMiMC(api, c.mode)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestGMiMCAll(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761, ecc.BLS24_315} {
+		for _, mode := range []Mode{ERF, CRF} {
+			modulus := curve.ScalarField()
+			p, err := GetDefaultParameters(modulus, mode)
+			assert.NoError(err)
+
+			var data [10]*big.Int
+			data[0] = new(big.Int).Sub(modulus, big.NewInt(1))
+			for i := 1; i < len(data); i++ {
+				data[i] = new(big.Int).Add(data[i-1], data[i-1])
+				data[i].Mod(data[i], modulus)
+			}
+
+			var validWitness, invalidWitness gmimcCircuit
+			for i := range data {
+				validWitness.Data[i] = data[i]
+				invalidWitness.Data[i] = data[i]
+			}
+			validWitness.Expected = p.HashElements(data[:]...)
+			invalidWitness.Expected = validWitness.Expected
+			invalidWitness.Data[9] = 1
+
+			assert.Run(func(assert *test.Assert) {
+				assert.CheckCircuit(&gmimcCircuit{mode: mode},
+					test.WithValidAssignment(&validWitness),
+					test.WithInvalidAssignment(&invalidWitness),
+					test.WithCurves(curve))
+			}, curve.String(), mode.String())
+		}
+	}
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+
+	// the first round, by hand
+	for _, mode := range []Mode{ERF, CRF} {
+		p, err := NewParameters(modulus, 3, mode)
+		assert.NoError(err)
+		state := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+		var expected []*big.Int
+		s := new(big.Int)
+		if mode == ERF {
+			s.Add(state[0], p.RoundConstants[0]).Exp(s, big.NewInt(int64(p.D)), modulus)
+			expected = []*big.Int{new(big.Int).Add(state[1], s), new(big.Int).Add(state[2], s), state[0]}
+		} else {
+			s.Add(big.NewInt(5), p.RoundConstants[0]).Exp(s, big.NewInt(int64(p.D)), modulus)
+			expected = []*big.Int{state[1], state[2], new(big.Int).Add(state[0], s)}
+		}
+		p.NbRounds = 1
+		p.Permute(state)
+		for i := range state {
+			assert.Equal(0, expected[i].Cmp(state[i]), "%s: element %d", mode, i)
+		}
+	}
+}
+
+func TestParameters(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	p, err := GetDefaultParameters(ecc.BN254.ScalarField(), ERF)
+	assert.NoError(err)
+	assert.Equal(5, p.D)
+	// 5^109 < r < 5^110
+	assert.Equal(2*110+DefaultWidth, p.NbRounds)
+	assert.Equal(p.NbRounds, len(p.RoundConstants))
+
+	_, err = NewParameters(ecc.BN254.ScalarField(), 1, ERF)
+	assert.Error(err)
+	_, err = NewParameters(ecc.BN254.ScalarField(), 3, Mode(2))
+	assert.Error(err)
+}
+
+func TestRoundConstants(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the derivation is the one of the MiMC constants of gnark-crypto, which
+	// use the seed "seed"
+	expected := bn254mimc.GetConstants()
+	constants := roundConstants(ecc.BN254.ScalarField(), "seed", len(expected))
+	for i := range expected {
+		assert.Equal(0, expected[i].Cmp(constants[i]), "constant %d", i)
+	}
+}
+
+func TestKnownAnswers(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// regression values of the digest of (1, 2)
+	vectors := []struct {
+		curve    ecc.ID
+		mode     Mode
+		expected string
+	}{
+		{ecc.BN254, ERF, "2732b474b23cfad56d97e9d015a3898af3d717c2c34502077ee435d485277839"},
+		{ecc.BN254, CRF, "ec4572b95323587ebf0f07a5e4495daed14afd4096e2350ed210d502b305f96"},
+		{ecc.BLS12_381, ERF, "700eedd603219f3e9e19b32d9c8bb8e1117668a4908db5783678a88de542aa7c"},
+		{ecc.BLS12_381, CRF, "5ef2c370fbc88676db6b584279ed2480260a95ddb7ee342d2fc88cd70a4fc3e5"},
+	}
+	for _, v := range vectors {
+		p, err := GetDefaultParameters(v.curve.ScalarField(), v.mode)
+		assert.NoError(err)
+		assert.Equal(v.expected, p.HashElements(big.NewInt(1), big.NewInt(2)).Text(16), "%s %s", v.curve, v.mode)
+	}
+}
+
+type multiInputCircuit struct {
+	In  [DefaultWidth - 1]frontend.Variable
+	Res frontend.Variable `gnark:",public"`
+
+	mimc bool
+}
+
+func (c *multiInputCircuit) Define(api frontend.API) error {
+	if c.mimc {
+		h, err := mimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		h.Write(c.In[:]...)
+		api.AssertIsEqual(h.Sum(), c.Res)
+		return nil
+	}
+	h, err := NewGMiMC(api, ERF)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In[:]...)
+	api.AssertIsEqual(h.Sum(), c.Res)
+	return nil
+}
+
+func TestFewerConstraintsThanMiMC(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	gmimcCS, err := frontend.Compile(field, r1cs.NewBuilder, &multiInputCircuit{})
+	assert.NoError(err)
+	mimcCS, err := frontend.Compile(field, r1cs.NewBuilder, &multiInputCircuit{mimc: true})
+	assert.NoError(err)
+	assert.Less(2*gmimcCS.GetNbConstraints(), mimcCS.GetNbConstraints())
+}
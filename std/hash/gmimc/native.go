@@ -0,0 +1,67 @@
+package gmimc
+
+import (
+	"math/big"
+)
+
+// Permute applies the GMiMC permutation to state in place. The length of state
+// must be p.T and its elements must be reduced modulo p.Field.
+//
+// Each round applies the round function of p.Mode and rotates the state by one
+// element to the left, the first element becoming the last one.
+func (p *Parameters) Permute(state []*big.Int) {
+	if len(state) != p.T {
+		panic("state length does not match the permutation width")
+	}
+	d := big.NewInt(int64(p.D))
+	var s big.Int
+	for r := 0; r < p.NbRounds; r++ {
+		switch p.Mode {
+		case ERF:
+			s.Add(state[0], p.RoundConstants[r]).Exp(&s, d, p.Field)
+			for i := 1; i < len(state); i++ {
+				state[i].Add(state[i], &s).Mod(state[i], p.Field)
+			}
+		case CRF:
+			s.Set(p.RoundConstants[r])
+			for i := 1; i < len(state); i++ {
+				s.Add(&s, state[i])
+			}
+			s.Exp(&s, d, p.Field)
+			state[0].Add(state[0], &s).Mod(state[0], p.Field)
+		default:
+			panic("invalid mode")
+		}
+		first := state[0]
+		copy(state, state[1:])
+		state[len(state)-1] = first
+	}
+}
+
+// HashElements returns the GMiMC digest of the given field elements. It is the
+// out-of-circuit counterpart of [GMiMC.Sum].
+//
+// The sponge has a capacity of one element, the last one of the state, which
+// is initialized with the number of elements. The elements are absorbed in the
+// first T-1 elements of the state, T-1 elements per permutation and the last
+// block being completed with zeros, and the digest is the first element of the
+// state. The inputs of different lengths are separated by the capacity, so that
+// no padding is needed. No input is hashed with a single permutation.
+func (p *Parameters) HashElements(data ...*big.Int) *big.Int {
+	rate := p.T - 1
+	state := make([]*big.Int, p.T)
+	for i := range state {
+		state[i] = new(big.Int)
+	}
+	state[p.T-1].SetInt64(int64(len(data))).Mod(state[p.T-1], p.Field)
+	if len(data) == 0 {
+		p.Permute(state)
+	}
+	for i := 0; i < len(data); i += rate {
+		for j := 0; j < rate && i+j < len(data); j++ {
+			state[j].Add(state[j], data[i+j]).Mod(state[j], p.Field)
+		}
+		p.Permute(state)
+	}
+	return state[0]
+}
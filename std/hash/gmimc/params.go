@@ -0,0 +1,174 @@
+package gmimc
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/params"
+	"golang.org/x/crypto/sha3"
+)
+
+// DefaultWidth is the width (number of field elements in the state) of the
+// permutation used by [NewGMiMC]. The sponge has a capacity of one element, so
+// that up to DefaultWidth-1 elements are hashed with a single permutation.
+const DefaultWidth = 8
+
+// seed is the seed of the round constants.
+const seed = "GMiMC"
+
+// Mode is the round function of the unbalanced Feistel network of GMiMC.
+type Mode int
+
+const (
+	// ERF is the expanding round function: the S-box is applied to the first
+	// element of the state and the result is added to all the others.
+	ERF Mode = iota
+	// CRF is the contracting round function: the S-box is applied to the sum
+	// of all the elements of the state but the first one, and the result is
+	// added to the first one.
+	CRF
+)
+
+// String returns "erf" or "crf".
+func (m Mode) String() string {
+	switch m {
+	case ERF:
+		return "erf"
+	case CRF:
+		return "crf"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// Parameters define an instance of the GMiMC permutation over a prime field.
+type Parameters struct {
+	Field    *big.Int // modulus of the field
+	T        int      // width of the permutation (number of field elements in the state)
+	Mode     Mode     // round function
+	D        int      // exponent of the S-box x -> x^D
+	NbRounds int      // number of rounds
+
+	// RoundConstants are the NbRounds constants, one per round, added to the
+	// input of the S-box.
+	RoundConstants []*big.Int
+}
+
+var (
+	parametersCache   = make(map[string]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// GetDefaultParameters returns the parameters of the GMiMC permutation of width
+// [DefaultWidth] with the round function mode over the given field.
+//
+// The returned parameters are shared and must not be modified.
+func GetDefaultParameters(field *big.Int, mode Mode) (*Parameters, error) {
+	key := fmt.Sprintf("%s/%s", field.Text(16), mode)
+
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[key]; ok {
+		return p, nil
+	}
+	p, err := NewParameters(field, DefaultWidth, mode)
+	if err != nil {
+		return nil, err
+	}
+	parametersCache[key] = p
+	return p, nil
+}
+
+// NewParameters returns the parameters of the GMiMC permutation of width t,
+// at least 2, with the round function mode over the given field, targeting 128
+// bits of security.
+//
+// The exponent d is the smallest d >= 3 such that gcd(d, p-1) = 1. The number
+// of rounds is 2⋅⌈log_d(p)⌉ + t: the interpolation and the Gröbner basis
+// attacks require the degree d^R to exceed p^2, and the Feistel network needs t
+// more rounds for the full diffusion. The round constants are
+// c_i = keccak256^(i+2)("GMiMC") mod p, where keccak256^k is the legacy
+// Keccak-256 applied k times, as for MiMC.
+func NewParameters(field *big.Int, t int, mode Mode) (*Parameters, error) {
+	if t < 2 {
+		return nil, fmt.Errorf("invalid width %d, must be at least 2", t)
+	}
+	if mode != ERF && mode != CRF {
+		return nil, fmt.Errorf("invalid mode %s", mode)
+	}
+	d, err := exponent(field)
+	if err != nil {
+		return nil, err
+	}
+	p := &Parameters{
+		Field:    new(big.Int).Set(field),
+		T:        t,
+		Mode:     mode,
+		D:        d,
+		NbRounds: 2*logCeil(field, d) + t,
+	}
+	p.RoundConstants = roundConstants(field, seed, p.NbRounds)
+	return p, nil
+}
+
+// exponent returns the smallest d >= 3 such that x -> x^d is a permutation of
+// the field.
+func exponent(field *big.Int) (int, error) {
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	var gcd big.Int
+	for d := 3; d < 256; d++ {
+		if gcd.GCD(nil, nil, big.NewInt(int64(d)), pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("no suitable S-box exponent for field %s", field.Text(16))
+}
+
+// logCeil returns the smallest k such that d^k >= p.
+func logCeil(field *big.Int, d int) int {
+	k := 0
+	for x := big.NewInt(1); x.Cmp(field) < 0; k++ {
+		x.Mul(x, big.NewInt(int64(d)))
+	}
+	return k
+}
+
+// roundConstants derives the round constants from the seed with a chain of
+// Keccak-256 hashes, the first hash of the seed being discarded.
+func roundConstants(modulus *big.Int, seed string, n int) []*big.Int {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(seed))
+	rnd := h.Sum(nil)
+	res := make([]*big.Int, n)
+	for i := range res {
+		h.Reset()
+		h.Write(rnd)
+		rnd = h.Sum(nil)
+		res[i] = new(big.Int).SetBytes(rnd)
+		res[i].Mod(res[i], modulus)
+	}
+	return res
+}
+
+// registeredCurves are the curves for which the default parameters are
+// registered in the [params] registry.
+var registeredCurves = []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_315, ecc.BLS24_317, ecc.BW6_633}
+
+func init() {
+	for _, id := range registeredCurves {
+		for _, mode := range []Mode{ERF, CRF} {
+			id, mode := id, mode
+			name := fmt.Sprintf("gmimc-%s/%s/t=%d", mode, id, DefaultWidth)
+			derivation := fmt.Sprintf("round constants c_i = keccak256^(i+2)(seed) mod r, where keccak256^k is the legacy Keccak-256 applied k times and r the scalar field of %s", id)
+			params.Register(name, 1, seed, derivation, func() ([]*big.Int, error) {
+				p, err := GetDefaultParameters(id.ScalarField(), mode)
+				if err != nil {
+					return nil, err
+				}
+				return p.RoundConstants, nil
+			})
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package griffin provides a ZKP-circuit function to compute the Griffin-π
+// permutation and a sponge hash built on it.
+//
+// The permutation is described in [Horst Meets Fluid-SPN: Griffin for
+// Zero-Knowledge Applications]. Its non-linear layer applies the inverse power
+// map x -> x^(1/d) to a single element of the state, computed with a hint and
+// checked with the direct power, so that Griffin needs fewer rounds than
+// Poseidon with few constraints per round. With the default width 3, a node of
+// a binary Merkle tree is hashed with a single permutation of 12 rounds
+// (BN254, BLS12-381), for about 110 constraints against 240 for Poseidon.
+//
+// The instances are derived from the field as in the reference implementation
+// of the authors (see [NewParameters]), but they have not been checked against
+// its test vectors: the round constants, alpha and beta, and so the hashes,
+// are specific to gnark until they are. TestKnownAnswers pins the values of
+// the BN254 instance of width 3, so that they don't change silently.
+//
+// [Horst Meets Fluid-SPN: Griffin for Zero-Knowledge Applications]: https://eprint.iacr.org/2022/403
+package griffin
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{invPowHint}
+}
+
+// Griffin computes the Griffin sponge hash of field elements in-circuit. It
+// implements [github.com/consensys/gnark/std/hash.FieldHasher].
+type Griffin struct {
+	api    frontend.API
+	params *Parameters
+	data   []frontend.Variable
+}
+
+// NewGriffin returns a Griffin instance with the default parameters over the
+// native field (see [GetDefaultParameters]), than can be used in a gnark
+// circuit.
+func NewGriffin(api frontend.API) (Griffin, error) {
+	params, err := GetDefaultParameters(api.Compiler().Field())
+	if err != nil {
+		return Griffin{}, err
+	}
+	return NewGriffinWithParameters(api, params), nil
+}
+
+// NewGriffinWithParameters returns a Griffin instance using the given
+// parameters. The parameters must be defined over the native field.
+func NewGriffinWithParameters(api frontend.API, params *Parameters) Griffin {
+	return Griffin{api: api, params: params}
+}
+
+// Write adds more data to the running hash.
+func (h *Griffin) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Griffin) Reset() {
+	h.data = nil
+}
+
+// Sum returns the digest of the data written since the last reset. See
+// [Parameters.HashElements] for the absorption of the inputs.
+func (h *Griffin) Sum() frontend.Variable {
+	rate := h.params.T - 1
+	state := make([]frontend.Variable, h.params.T)
+	for i := range state {
+		state[i] = 0
+	}
+	state[h.params.T-1] = len(h.data)
+	if len(h.data) == 0 {
+		state = Permutation(h.api, h.params, state)
+	}
+	for i := 0; i < len(h.data); i += rate {
+		for j := 0; j < rate && i+j < len(h.data); j++ {
+			state[j] = h.api.Add(state[j], h.data[i+j])
+		}
+		state = Permutation(h.api, h.params, state)
+	}
+	return state[0]
+}
+
+// Permutation returns the Griffin-π permutation of state. The length of state
+// must be params.T.
+func Permutation(api frontend.API, params *Parameters, state []frontend.Variable) []frontend.Variable {
+	if len(state) != params.T {
+		panic("state length does not match the permutation width")
+	}
+	res := linearLayer(api, state)
+	for r := 0; r < params.NbRounds; r++ {
+		res = nonLinearLayer(api, params, res)
+		res = linearLayer(api, res)
+		if r < params.NbRounds-1 {
+			for i := range res {
+				res[i] = api.Add(res[i], params.RoundConstants[r][i])
+			}
+		}
+	}
+	return res
+}
+
+// nonLinearLayer returns the non-linear layer of Griffin-π applied to state,
+// see [Parameters.Permute].
+func nonLinearLayer(api frontend.API, params *Parameters, state []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(state))
+	res[0] = invPow(api, state[0], params.D)
+	res[1] = pow(api, state[1], params.D)
+	for i := 2; i < len(state); i++ {
+		l := api.Add(api.Mul(i-1, res[0]), res[1])
+		if i > 2 {
+			l = api.Add(l, res[i-1])
+		}
+		t := api.Add(api.Mul(api.Add(l, params.Alpha[i-2]), l), params.Beta[i-2])
+		res[i] = api.Mul(state[i], t)
+	}
+	return res
+}
+
+// linearLayer returns the product of the linear layer matrix of Griffin-π and
+// state, see [Parameters.Permute].
+func linearLayer(api frontend.API, state []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(state))
+	if len(state) == 3 {
+		sum := api.Add(state[0], state[1], state[2])
+		for i := range res {
+			res[i] = api.Add(state[i], sum)
+		}
+		return res
+	}
+	for i := 0; i < len(state); i += 4 {
+		copy(res[i:i+4], mulM4(api, state[i:i+4]))
+	}
+	for j := 0; j < 4; j++ {
+		sum := res[j]
+		for i := j + 4; i < len(res); i += 4 {
+			sum = api.Add(sum, res[i])
+		}
+		for i := j; i < len(res); i += 4 {
+			res[i] = api.Add(res[i], sum)
+		}
+	}
+	return res
+}
+
+// mulM4 returns the product of the 4×4 matrix of Griffin and x.
+func mulM4(api frontend.API, x []frontend.Variable) []frontend.Variable {
+	t0 := api.Add(x[0], x[1])
+	t1 := api.Add(x[2], x[3])
+	t2 := api.Add(api.Mul(x[1], 2), t1)
+	t3 := api.Add(api.Mul(x[3], 2), t0)
+	t4 := api.Add(api.Mul(t1, 4), t3)
+	t5 := api.Add(api.Mul(t0, 4), t2)
+	return []frontend.Variable{api.Add(t3, t5), t5, api.Add(t2, t4), t4}
+}
+
+// invPow returns x^(1/d).
+func invPow(api frontend.API, x frontend.Variable, d int) frontend.Variable {
+	res, err := api.Compiler().NewHint(invPowHint, 1, x, d)
+	if err != nil {
+		panic(err)
+	}
+	api.AssertIsEqual(pow(api, res[0], d), x)
+	return res[0]
+}
+
+// pow returns x^d.
+func pow(api frontend.API, x frontend.Variable, d int) frontend.Variable {
+	res := x
+	for i := bits.Len(uint(d)) - 2; i >= 0; i-- {
+		res = api.Mul(res, res)
+		if (d>>i)&1 == 1 {
+			res = api.Mul(res, x)
+		}
+	}
+	return res
+}
+
+func invPowHint(field *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 1 {
+		return errors.New("expecting two inputs and one output")
+	}
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	e := new(big.Int).ModInverse(inputs[1], pMinusOne)
+	if e == nil {
+		return errors.New("exponent is not invertible")
+	}
+	outputs[0].Exp(inputs[0], e, field)
+	return nil
+}
@@ -0,0 +1,243 @@
+package griffin
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/test"
+)
+
+type griffinCircuit struct {
+	Data     [5]frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+}
+
+func (c *griffinCircuit) Define(api frontend.API) error {
+	h, err := NewGriffin(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestGriffinAll(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761, ecc.BLS24_315} {
+		modulus := curve.ScalarField()
+		p, err := GetDefaultParameters(modulus)
+		assert.NoError(err)
+
+		var data [5]*big.Int
+		data[0] = new(big.Int).Sub(modulus, big.NewInt(1))
+		for i := 1; i < len(data); i++ {
+			data[i] = new(big.Int).Add(data[i-1], data[i-1])
+			data[i].Mod(data[i], modulus)
+		}
+
+		var validWitness, invalidWitness griffinCircuit
+		for i := range data {
+			validWitness.Data[i] = data[i]
+			invalidWitness.Data[i] = data[i]
+		}
+		validWitness.Expected = p.HashElements(data[:]...)
+		invalidWitness.Expected = validWitness.Expected
+		invalidWitness.Data[4] = 1
+
+		assert.CheckCircuit(&griffinCircuit{},
+			test.WithValidAssignment(&validWitness),
+			test.WithInvalidAssignment(&invalidWitness),
+			test.WithCurves(curve))
+	}
+}
+
+type permutationCircuit struct {
+	In, Out []frontend.Variable
+
+	t int
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	p, err := NewParameters(api.Compiler().Field(), c.t)
+	if err != nil {
+		return err
+	}
+	res := Permutation(api, p, c.In)
+	for i := range res {
+		api.AssertIsEqual(res[i], c.Out[i])
+	}
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	for _, width := range []int{3, 4, 8, 12} {
+		p, err := NewParameters(modulus, width)
+		assert.NoError(err)
+		in := make([]frontend.Variable, width)
+		out := make([]frontend.Variable, width)
+		state := make([]*big.Int, width)
+		for i := range state {
+			state[i] = big.NewInt(int64(i))
+			in[i] = i
+		}
+		p.Permute(state)
+		for i := range state {
+			out[i] = state[i]
+		}
+		assert.Run(func(assert *test.Assert) {
+			assert.CheckCircuit(&permutationCircuit{In: make([]frontend.Variable, width), Out: make([]frontend.Variable, width), t: width},
+				test.WithValidAssignment(&permutationCircuit{In: in, Out: out}),
+				test.WithCurves(ecc.BN254))
+		}, fmt.Sprintf("t=%d", width))
+	}
+}
+
+func TestLinearLayer(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	m4 := [4][4]int64{{5, 7, 1, 3}, {4, 6, 1, 1}, {1, 3, 5, 7}, {1, 1, 4, 6}}
+	for _, width := range []int{3, 8} {
+		p, err := NewParameters(modulus, width)
+		assert.NoError(err)
+		// the matrix circ(2, 1, 1) or circ(2⋅M4, M4, ...)
+		m := func(i, j int) int64 {
+			if width == 3 {
+				if i == j {
+					return 2
+				}
+				return 1
+			}
+			if i/4 == j/4 {
+				return 2 * m4[i%4][j%4]
+			}
+			return m4[i%4][j%4]
+		}
+		state := make([]*big.Int, width)
+		for i := range state {
+			state[i] = big.NewInt(int64(3*i + 1))
+		}
+		expected := make([]*big.Int, width)
+		for i := range expected {
+			expected[i] = new(big.Int)
+			for j := range state {
+				expected[i].Add(expected[i], new(big.Int).Mul(big.NewInt(m(i, j)), state[j]))
+			}
+		}
+		p.linearLayer(state)
+		for i := range state {
+			assert.Equal(0, expected[i].Cmp(state[i]), "t=%d, element %d", width, i)
+		}
+	}
+}
+
+func TestParameters(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range registeredCurves {
+		field := curve.ScalarField()
+		p, err := GetDefaultParameters(field)
+		assert.NoError(err, curve.String())
+		assert.Equal(p.NbRounds-1, len(p.RoundConstants))
+
+		// d⋅dInv = 1 mod p-1
+		pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+		e := new(big.Int).Mul(big.NewInt(int64(p.D)), p.DInv)
+		assert.Equal(int64(1), e.Mod(e, pMinusOne).Int64(), curve.String())
+
+		// alpha^2 - 4⋅beta is not a square
+		delta := new(big.Int).Mul(p.Alpha[0], p.Alpha[0])
+		delta.Sub(delta, new(big.Int).Lsh(p.Beta[0], 2)).Mod(delta, field)
+		assert.Equal(-1, big.Jacobi(delta, field), curve.String())
+	}
+	p, err := GetDefaultParameters(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	assert.Equal(5, p.D)
+	assert.Equal(12, p.NbRounds)
+
+	for _, width := range []int{0, 2, 5, 28} {
+		_, err = NewParameters(ecc.BN254.ScalarField(), width)
+		assert.Error(err, "t=%d", width)
+	}
+}
+
+// TestKnownAnswers pins the BN254 instance of width 3: the first round
+// constants, alpha, beta and the permutation of (0, 1, 2). The values were
+// produced by this package and are not taken from the reference
+// implementation, see the package documentation.
+func TestKnownAnswers(t *testing.T) {
+	assert := test.NewAssert(t)
+	hex := func(s string) *big.Int {
+		v, ok := new(big.Int).SetString(s, 0)
+		assert.True(ok, s)
+		return v
+	}
+	p, err := GetDefaultParameters(ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	roundConstants := []*big.Int{
+		hex("0x2fb30cafdb1f76156dfabf0cd0af4b895e764ac2a84386c9d0d7aed6a7f4eac9"),
+		hex("0x282927892ce324572f19abb14871d2b539a80d8a5800cdb87a81e1697a94b6c9"),
+		hex("0x03d0f3f2711dd59e3d97fc797261300cd3fee33b95cf710a32edf42aa2bc0905"),
+	}
+	for i, c := range roundConstants {
+		assert.Equal(0, c.Cmp(p.RoundConstants[0][i]), "round constant %d", i)
+	}
+	assert.Equal(0, hex("0x146ecffb34a66316fae66609f78d1310bc14ad7208082ca7943afebb1da4aa4a").Cmp(p.Alpha[0]), "alpha")
+	assert.Equal(0, hex("0x2b568115d544c7e941eff6ccc935384619b0fb7d2c5ba6c078c34cf81697ee1c").Cmp(p.Beta[0]), "beta")
+
+	state := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+	p.Permute(state)
+	expected := []*big.Int{
+		hex("0x2311cdb3076c3a7ee37fd5a271e0f3a8a3cc38057d0cea37b78951f43b1b6ff6"),
+		hex("0x1d3aaed9ea361e899e667abd18e5328555b97b5c3890d52b261f940d6ab4df58"),
+		hex("0x22614a0ac719cb623a636adac3bac1b85b5a7a418fcf8ab3a3ae0787fb4bed9d"),
+	}
+	for i := range expected {
+		assert.Equal(0, expected[i].Cmp(state[i]), "element %d", i)
+	}
+}
+
+type compressCircuit struct {
+	A, B frontend.Variable
+	Res  frontend.Variable `gnark:",public"`
+
+	poseidon bool
+}
+
+func (c *compressCircuit) Define(api frontend.API) error {
+	if c.poseidon {
+		h, err := poseidon.NewPoseidon(api)
+		if err != nil {
+			return err
+		}
+		h.Write(c.A, c.B)
+		api.AssertIsEqual(h.Sum(), c.Res)
+		return nil
+	}
+	h, err := NewGriffin(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.A, c.B)
+	api.AssertIsEqual(h.Sum(), c.Res)
+	return nil
+}
+
+func TestFewerConstraintsThanPoseidon(t *testing.T) {
+	assert := test.NewAssert(t)
+	field := ecc.BN254.ScalarField()
+	griffinCS, err := frontend.Compile(field, r1cs.NewBuilder, &compressCircuit{})
+	assert.NoError(err)
+	poseidonCS, err := frontend.Compile(field, r1cs.NewBuilder, &compressCircuit{poseidon: true})
+	assert.NoError(err)
+	assert.Less(griffinCS.GetNbConstraints(), poseidonCS.GetNbConstraints())
+}
@@ -0,0 +1,123 @@
+package griffin
+
+import (
+	"math/big"
+)
+
+// Permute applies the Griffin-π permutation to state in place. The length of
+// state must be p.T and its elements must be reduced modulo p.Field.
+func (p *Parameters) Permute(state []*big.Int) {
+	if len(state) != p.T {
+		panic("state length does not match the permutation width")
+	}
+	p.linearLayer(state)
+	for r := 0; r < p.NbRounds; r++ {
+		p.nonLinearLayer(state)
+		p.linearLayer(state)
+		if r < p.NbRounds-1 {
+			for i := range state {
+				state[i].Add(state[i], p.RoundConstants[r][i]).Mod(state[i], p.Field)
+			}
+		}
+	}
+}
+
+// nonLinearLayer applies the non-linear layer of Griffin-π:
+//
+//	y0 = x0^(1/d)
+//	y1 = x1^d
+//	yi = xi⋅(Li^2 + Alpha[i-2]⋅Li + Beta[i-2])
+//
+// where L2 = y0 + y1 and Li = (i-1)⋅y0 + y1 + y(i-1) for i > 2.
+func (p *Parameters) nonLinearLayer(state []*big.Int) {
+	f := p.Field
+	state[0].Exp(state[0], p.DInv, f)
+	state[1].Exp(state[1], big.NewInt(int64(p.D)), f)
+	var l, t big.Int
+	for i := 2; i < len(state); i++ {
+		l.Mul(big.NewInt(int64(i-1)), state[0]).Add(&l, state[1])
+		if i > 2 {
+			l.Add(&l, state[i-1])
+		}
+		l.Mod(&l, f)
+		t.Add(&l, p.Alpha[i-2]).Mul(&t, &l).Add(&t, p.Beta[i-2])
+		state[i].Mul(state[i], &t).Mod(state[i], f)
+	}
+}
+
+// linearLayer multiplies the state by the matrix circ(2, 1, 1) for the width
+// 3, and by circ(2⋅M4, M4, ..., M4) for the widths multiple of 4, where M4 is
+// the 4×4 matrix of Griffin.
+func (p *Parameters) linearLayer(state []*big.Int) {
+	sum := new(big.Int)
+	if len(state) == 3 {
+		for i := range state {
+			sum.Add(sum, state[i])
+		}
+		for i := range state {
+			state[i].Add(state[i], sum).Mod(state[i], p.Field)
+		}
+		return
+	}
+	var sums [4]big.Int
+	for i := 0; i < len(state); i += 4 {
+		mulM4Native(state[i : i+4])
+		for j := range sums {
+			sums[j].Add(&sums[j], state[i+j])
+		}
+	}
+	for i := range state {
+		state[i].Add(state[i], &sums[i%4]).Mod(state[i], p.Field)
+	}
+}
+
+// mulM4Native multiplies x by the matrix
+//
+//	5 7 1 3
+//	4 6 1 1
+//	1 3 5 7
+//	1 1 4 6
+//
+// without reducing the result.
+func mulM4Native(x []*big.Int) {
+	var t0, t1, t2, t3, t4, t5 big.Int
+	t0.Add(x[0], x[1])
+	t1.Add(x[2], x[3])
+	t2.Lsh(x[1], 1).Add(&t2, &t1)
+	t3.Lsh(x[3], 1).Add(&t3, &t0)
+	t4.Lsh(&t1, 2).Add(&t4, &t3)
+	t5.Lsh(&t0, 2).Add(&t5, &t2)
+	x[0].Add(&t3, &t5)
+	x[1].Set(&t5)
+	x[2].Add(&t2, &t4)
+	x[3].Set(&t4)
+}
+
+// HashElements returns the Griffin digest of the given field elements. It is
+// the out-of-circuit counterpart of [Griffin.Sum].
+//
+// The sponge has a capacity of one element, the last one of the state, which
+// is initialized with the number of elements. The elements are absorbed in the
+// first T-1 elements of the state, T-1 elements per permutation and the last
+// block being completed with zeros, and the digest is the first element of
+// the state. The inputs of different lengths are separated by the capacity, so
+// that no padding is needed and that two elements are hashed with a single
+// permutation of width 3. No input is hashed with a single permutation.
+func (p *Parameters) HashElements(data ...*big.Int) *big.Int {
+	rate := p.T - 1
+	state := make([]*big.Int, p.T)
+	for i := range state {
+		state[i] = new(big.Int)
+	}
+	state[p.T-1].SetInt64(int64(len(data))).Mod(state[p.T-1], p.Field)
+	if len(data) == 0 {
+		p.Permute(state)
+	}
+	for i := 0; i < len(data); i += rate {
+		for j := 0; j < rate && i+j < len(data); j++ {
+			state[j].Add(state[j], data[i+j]).Mod(state[j], p.Field)
+		}
+		p.Permute(state)
+	}
+	return state[0]
+}
@@ -0,0 +1,223 @@
+package griffin
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/params"
+	"golang.org/x/crypto/sha3"
+)
+
+// DefaultWidth is the width (number of field elements in the state) of the
+// permutation used by [NewGriffin]. The sponge has a capacity of one element,
+// so that a node of a binary Merkle tree is hashed with a single permutation.
+const DefaultWidth = 3
+
+// Parameters define an instance of the Griffin-π permutation over a prime
+// field.
+type Parameters struct {
+	Field    *big.Int // modulus of the field
+	T        int      // width of the permutation (number of field elements in the state)
+	D        int      // exponent of the power maps
+	DInv     *big.Int // inverse of D modulo Field-1
+	NbRounds int      // number of rounds
+
+	// RoundConstants are the T constants added to the state after the linear
+	// layer of each round but the last one.
+	RoundConstants [][]*big.Int
+	// Alpha and Beta are the coefficients of the quadratic functions of the
+	// non-linear layer, Alpha[i-2] and Beta[i-2] being those of the element i
+	// of the state.
+	Alpha, Beta []*big.Int
+}
+
+// nbRounds is the number of rounds for 128 bits of security, indexed by the
+// exponent (3 or 5) and the width. The larger exponents use the number of
+// rounds of the exponent 5, which is conservative.
+var nbRounds = map[int]map[int]int{
+	3: {3: 16, 4: 14, 8: 11, 12: 10, 16: 10, 20: 10, 24: 10},
+	5: {3: 12, 4: 11, 8: 9, 12: 9, 16: 9, 20: 9, 24: 9},
+}
+
+var (
+	parametersCache   = make(map[string]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// GetDefaultParameters returns the parameters of the Griffin-π permutation of
+// width [DefaultWidth] over the given field.
+//
+// The returned parameters are shared and must not be modified.
+func GetDefaultParameters(field *big.Int) (*Parameters, error) {
+	key := field.Text(16)
+
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[key]; ok {
+		return p, nil
+	}
+	p, err := NewParameters(field, DefaultWidth)
+	if err != nil {
+		return nil, err
+	}
+	parametersCache[key] = p
+	return p, nil
+}
+
+// NewParameters returns the parameters of the Griffin-π permutation of width t
+// over the given field, targeting 128 bits of security. The width must be 3 or
+// a multiple of 4 up to 24.
+//
+// The exponent d is the smallest d >= 3 such that gcd(d, p-1) = 1 and the
+// number of rounds is the one of the specification. The round constants, then
+// alpha and beta, are sampled from the output of SHAKE128 on "Griffin"
+// followed by the little-endian 64-bit words of the modulus, by rejection of
+// the little-endian integers larger than the modulus. alpha and beta are
+// sampled as in the reference implementation: zeros are skipped, beta is
+// resampled while it is equal to alpha, and the pair is sampled again until
+// alpha^2 - 4⋅beta is not a square. The coefficients of the element i are
+// Alpha[i-2] = (i-1)⋅alpha and Beta[i-2] = (i-1)^2⋅beta.
+func NewParameters(field *big.Int, t int) (*Parameters, error) {
+	if t != 3 && (t%4 != 0 || t > 24 || t == 0) {
+		return nil, fmt.Errorf("invalid width %d, must be 3 or a multiple of 4 up to 24", t)
+	}
+	d, dInv, err := exponents(field)
+	if err != nil {
+		return nil, err
+	}
+	rounds := nbRounds[3]
+	if d != 3 {
+		rounds = nbRounds[5]
+	}
+	p := &Parameters{
+		Field:    new(big.Int).Set(field),
+		T:        t,
+		D:        d,
+		DInv:     dInv,
+		NbRounds: rounds[t],
+	}
+
+	s := newSampler(field)
+	p.RoundConstants = make([][]*big.Int, p.NbRounds-1)
+	for r := range p.RoundConstants {
+		p.RoundConstants[r] = make([]*big.Int, t)
+		for i := range p.RoundConstants[r] {
+			p.RoundConstants[r][i] = s.element()
+		}
+	}
+
+	var alpha, beta *big.Int
+	for {
+		alpha, beta = s.nonZeroElement(), s.nonZeroElement()
+		for alpha.Cmp(beta) == 0 {
+			beta = s.nonZeroElement()
+		}
+		// alpha^2 - 4⋅beta must not be a square, so that the quadratic
+		// functions have no root
+		delta := new(big.Int).Mul(alpha, alpha)
+		delta.Sub(delta, new(big.Int).Lsh(beta, 2)).Mod(delta, field)
+		if big.Jacobi(delta, field) == -1 {
+			break
+		}
+	}
+	p.Alpha = make([]*big.Int, t-2)
+	p.Beta = make([]*big.Int, t-2)
+	for i := range p.Alpha {
+		k := big.NewInt(int64(i + 1))
+		p.Alpha[i] = new(big.Int).Mul(alpha, k)
+		p.Alpha[i].Mod(p.Alpha[i], field)
+		p.Beta[i] = new(big.Int).Mul(beta, k.Mul(k, k))
+		p.Beta[i].Mod(p.Beta[i], field)
+	}
+	return p, nil
+}
+
+// exponents returns the smallest d >= 3 such that x -> x^d is a permutation of
+// the field, and its inverse modulo p-1.
+func exponents(field *big.Int) (int, *big.Int, error) {
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	var gcd big.Int
+	for d := 3; d < 256; d++ {
+		a := big.NewInt(int64(d))
+		if gcd.GCD(nil, nil, a, pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return d, new(big.Int).ModInverse(a, pMinusOne), nil
+		}
+	}
+	return 0, nil, fmt.Errorf("no suitable exponent for field %s", field.Text(16))
+}
+
+// sampler samples field elements from the output of SHAKE128.
+type sampler struct {
+	field *big.Int
+	shake sha3.ShakeHash
+	buf   []byte
+}
+
+func newSampler(field *big.Int) *sampler {
+	shake := sha3.NewShake128()
+	shake.Write([]byte("Griffin"))
+	modulus := field.Bytes()
+	for len(modulus)%8 != 0 {
+		modulus = append([]byte{0}, modulus...)
+	}
+	var word [8]byte
+	for i := len(modulus) - 8; i >= 0; i -= 8 {
+		binary.LittleEndian.PutUint64(word[:], binary.BigEndian.Uint64(modulus[i:i+8]))
+		shake.Write(word[:])
+	}
+	return &sampler{field: field, shake: shake, buf: make([]byte, (field.BitLen()+7)/8)}
+}
+
+// element returns the next little-endian integer, masked to the bit size of
+// the field, which is smaller than the modulus.
+func (s *sampler) element() *big.Int {
+	res := new(big.Int)
+	for {
+		_, _ = s.shake.Read(s.buf)
+		for l, r := 0, len(s.buf)-1; l < r; l, r = l+1, r-1 {
+			s.buf[l], s.buf[r] = s.buf[r], s.buf[l]
+		}
+		if extra := 8*len(s.buf) - s.field.BitLen(); extra > 0 {
+			s.buf[0] &= 0xff >> extra
+		}
+		if res.SetBytes(s.buf).Cmp(s.field) < 0 {
+			return res
+		}
+	}
+}
+
+// nonZeroElement returns the next element which is not zero.
+func (s *sampler) nonZeroElement() *big.Int {
+	for {
+		if res := s.element(); res.Sign() != 0 {
+			return res
+		}
+	}
+}
+
+// registeredCurves are the curves for which the default parameters are
+// registered in the [params] registry.
+var registeredCurves = []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_315, ecc.BLS24_317, ecc.BW6_633}
+
+func init() {
+	for _, id := range registeredCurves {
+		id := id
+		name := fmt.Sprintf("griffin/%s/t=%d", id, DefaultWidth)
+		seed := fmt.Sprintf("SHAKE128(\"Griffin\" || modulus) with the modulus the scalar field of %s", id)
+		derivation := "round constants (one row of t elements per round but the last) then alpha and beta, sampled by rejection from SHAKE128 of the seed"
+		params.Register(name, 1, seed, derivation, func() ([]*big.Int, error) {
+			p, err := GetDefaultParameters(id.ScalarField())
+			if err != nil {
+				return nil, err
+			}
+			var res []*big.Int
+			for _, row := range p.RoundConstants {
+				res = append(res, row...)
+			}
+			return append(res, p.Alpha[0], p.Beta[0]), nil
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package hmac implements the keyed-hash message authentication code (HMAC)
+// over any in-circuit binary hash, as defined in [RFC 2104].
+//
+// The construction is compatible with [crypto/hmac] when instantiated with the
+// equivalent hash function. For example, the signature of a JWT HS256 token is
+// HMAC over [github.com/consensys/gnark/std/hash/sha2]:
+//
+//	mac, err := hmac.New(api, sha2.New, 64, key)
+//	mac.Write(payload)
+//	tag := mac.Sum()
+//
+// The lengths of the key and of the message must be known at compile time.
+//
+// [RFC 2104]: https://www.rfc-editor.org/rfc/rfc2104
+package hmac
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	ipad = 0x36363636
+	opad = 0x5c5c5c5c
+)
+
+type mac struct {
+	uapi      *uints.BinaryField[uints.U32]
+	api       frontend.API
+	newHash   func(frontend.API) (hash.BinaryHasher, error)
+	blockSize int
+	size      int
+	key       []uints.U8 // key padded to the block size
+	in        []uints.U8
+}
+
+// New returns a new HMAC hasher over the hash function returned by newHash,
+// which has a block size of blockSize bytes. Keys longer than the block size
+// are first hashed, as in the standard. The block size must be a multiple of
+// 4 bytes, which is the case for all the hash functions in std/hash.
+func New(api frontend.API, newHash func(frontend.API) (hash.BinaryHasher, error), blockSize int, key []uints.U8) (hash.BinaryHasher, error) {
+	if blockSize <= 0 || blockSize%4 != 0 {
+		return nil, fmt.Errorf("invalid block size %d, must be a positive multiple of 4", blockSize)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	h, err := newHash(api)
+	if err != nil {
+		return nil, fmt.Errorf("new hash: %w", err)
+	}
+	if h.Size() > blockSize {
+		return nil, fmt.Errorf("digest size %d larger than the block size %d", h.Size(), blockSize)
+	}
+	if len(key) > blockSize {
+		h.Write(key)
+		key = h.Sum()
+	}
+	padded := make([]uints.U8, blockSize)
+	copy(padded, key)
+	for i := len(key); i < blockSize; i++ {
+		padded[i] = uints.NewU8(0)
+	}
+	return &mac{
+		uapi:      uapi,
+		api:       api,
+		newHash:   newHash,
+		blockSize: blockSize,
+		size:      h.Size(),
+		key:       padded,
+	}, nil
+}
+
+func (m *mac) Write(data []uints.U8) {
+	m.in = append(m.in, data...)
+}
+
+func (m *mac) Size() int { return m.size }
+
+func (m *mac) Reset() {
+	m.in = nil
+}
+
+func (m *mac) Sum() []uints.U8 {
+	inner := m.hash(m.xorKey(ipad), m.in)
+	return m.hash(m.xorKey(opad), inner)
+}
+
+// xorKey returns the padded key XOR-ed with the repeated pad byte. The key is
+// processed by words to use the lookup tables of [uints.BinaryField].
+func (m *mac) xorKey(pad uint32) []uints.U8 {
+	res := make([]uints.U8, 0, m.blockSize)
+	for i := 0; i < m.blockSize; i += 4 {
+		w := m.uapi.Xor(m.uapi.PackLSB(m.key[i:i+4]...), uints.NewU32(pad))
+		res = append(res, m.uapi.UnpackLSB(w)...)
+	}
+	return res
+}
+
+func (m *mac) hash(prefix, data []uints.U8) []uints.U8 {
+	h, err := m.newHash(m.api)
+	if err != nil {
+		// the same constructor succeeded in New
+		panic(err)
+	}
+	h.Write(prefix)
+	h.Write(data)
+	return h.Sum()
+}
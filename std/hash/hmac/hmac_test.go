@@ -0,0 +1,118 @@
+package hmac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	gohash "hash"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	gosha3 "golang.org/x/crypto/sha3"
+)
+
+type hmacCircuit struct {
+	Key      []uints.U8
+	In       []uints.U8
+	Expected []uints.U8
+
+	hashName string
+}
+
+var hashes = map[string]struct {
+	newHash   func(frontend.API) (hash.BinaryHasher, error)
+	blockSize int
+}{
+	"sha256":   {sha2.New, 64},
+	"sha3-256": {sha3.New256, 136},
+}
+
+func (c *hmacCircuit) Define(api frontend.API) error {
+	hh := hashes[c.hashName]
+	h, err := New(api, hh.newHash, hh.blockSize, c.Key)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestHMAC(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, c := range []struct {
+		hashName string
+		newHash  func() gohash.Hash
+		keyLen   int
+		msgLen   int
+	}{
+		{"sha256", sha256.New, 32, 100},
+		{"sha256", sha256.New, 64, 0},
+		{"sha256", sha256.New, 100, 10},
+		{"sha3-256", gosha3.New256, 20, 50},
+	} {
+		key := make([]byte, c.keyLen)
+		for i := range key {
+			key[i] = byte(3*i + 1)
+		}
+		msg := make([]byte, c.msgLen)
+		for i := range msg {
+			msg[i] = byte(i)
+		}
+		mac := hmac.New(c.newHash, key)
+		mac.Write(msg)
+		expected := mac.Sum(nil)
+
+		circuit := hmacCircuit{
+			Key:      make([]uints.U8, len(key)),
+			In:       make([]uints.U8, len(msg)),
+			Expected: make([]uints.U8, len(expected)),
+			hashName: c.hashName,
+		}
+		witness := hmacCircuit{
+			Key:      uints.NewU8Array(key),
+			In:       uints.NewU8Array(msg),
+			Expected: uints.NewU8Array(expected),
+		}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "%s key length %d", c.hashName, c.keyLen)
+	}
+}
+
+func TestJWTHS256(t *testing.T) {
+	// token of https://jwt.io signed with the secret "your-256-bit-secret"
+	const (
+		signingInput = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiaWF0IjoxNTE2MjM5MDIyfQ"
+		signature    = "SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+		secret       = "your-256-bit-secret"
+	)
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	test.NewAssert(t).NoError(err)
+
+	circuit := hmacCircuit{
+		Key:      make([]uints.U8, len(secret)),
+		In:       make([]uints.U8, len(signingInput)),
+		Expected: make([]uints.U8, len(expected)),
+		hashName: "sha256",
+	}
+	witness := hmacCircuit{
+		Key:      uints.NewU8Array([]byte(secret)),
+		In:       uints.NewU8Array([]byte(signingInput)),
+		Expected: uints.NewU8Array(expected),
+	}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	test.NewAssert(t).NoError(err)
+}
@@ -17,14 +17,9 @@ limitations under the License.
 package mimc
 
 import (
+	"math/bits"
+
 	"github.com/consensys/gnark-crypto/ecc"
-	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
-	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
-	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/mimc"
-	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/mimc"
-	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
-	bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
-	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
 
 	"github.com/consensys/gnark/frontend"
 )
@@ -57,7 +52,7 @@ func init() {
 
 func newMimcBLS377(api frontend.API) MiMC {
 	res := MiMC{}
-	res.params = bls12377.GetConstants()
+	res.params = getConstants(ecc.BLS12_377)
 	res.id = ecc.BLS12_377
 	res.h = 0
 	res.api = api
@@ -66,7 +61,7 @@ func newMimcBLS377(api frontend.API) MiMC {
 
 func newMimcBLS381(api frontend.API) MiMC {
 	res := MiMC{}
-	res.params = bls12381.GetConstants()
+	res.params = getConstants(ecc.BLS12_381)
 	res.id = ecc.BLS12_381
 	res.h = 0
 	res.api = api
@@ -75,7 +70,7 @@ func newMimcBLS381(api frontend.API) MiMC {
 
 func newMimcBN254(api frontend.API) MiMC {
 	res := MiMC{}
-	res.params = bn254.GetConstants()
+	res.params = getConstants(ecc.BN254)
 	res.id = ecc.BN254
 	res.h = 0
 	res.api = api
@@ -84,7 +79,7 @@ func newMimcBN254(api frontend.API) MiMC {
 
 func newMimcBW761(api frontend.API) MiMC {
 	res := MiMC{}
-	res.params = bw6761.GetConstants()
+	res.params = getConstants(ecc.BW6_761)
 	res.id = ecc.BW6_761
 	res.h = 0
 	res.api = api
@@ -93,7 +88,7 @@ func newMimcBW761(api frontend.API) MiMC {
 
 func newMimcBLS317(api frontend.API) MiMC {
 	res := MiMC{}
-	res.params = bls24317.GetConstants()
+	res.params = getConstants(ecc.BLS24_317)
 	res.id = ecc.BLS24_317
 	res.h = 0
 	res.api = api
@@ -102,7 +97,7 @@ func newMimcBLS317(api frontend.API) MiMC {
 
 func newMimcBLS315(api frontend.API) MiMC {
 	res := MiMC{}
-	res.params = bls24315.GetConstants()
+	res.params = getConstants(ecc.BLS24_315)
 	res.id = ecc.BLS24_315
 	res.h = 0
 	res.api = api
@@ -111,7 +106,7 @@ func newMimcBLS315(api frontend.API) MiMC {
 
 func newMimcBW633(api frontend.API) MiMC {
 	res := MiMC{}
-	res.params = bw6633.GetConstants()
+	res.params = getConstants(ecc.BW6_633)
 	res.id = ecc.BW6_633
 	res.h = 0
 	res.api = api
@@ -173,3 +168,26 @@ func encryptPow17(h MiMC, m frontend.Variable) frontend.Variable {
 	return h.api.Add(x, h.h)
 
 }
+
+// encryptPowN of a mimc run expressed as r1cs, with the exponent set with
+// [WithExponent]
+// m is the message, k the key
+func encryptPowN(h MiMC, m frontend.Variable) frontend.Variable {
+	x := m
+	for i := 0; i < len(h.params); i++ {
+		x = powN(h.api, h.api.Add(x, h.h, h.params[i]), h.exp)
+	}
+	return h.api.Add(x, h.h)
+}
+
+// powN returns x^e with square-and-multiply
+func powN(api frontend.API, x frontend.Variable, e int) frontend.Variable {
+	r := x
+	for i := bits.Len(uint(e)) - 2; i >= 0; i-- {
+		r = api.Mul(r, r)
+		if (e>>i)&1 == 1 {
+			r = api.Mul(r, x)
+		}
+	}
+	return r
+}
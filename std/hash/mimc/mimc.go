@@ -30,13 +30,29 @@ import (
 type MiMC struct {
 	params []big.Int           // slice containing constants for the encryption rounds
 	id     ecc.ID              // id needed to know which encryption function to use
+	exp    int                 // exponent of the S-box if set with options, 0 otherwise
 	h      frontend.Variable   // current vector in the Miyaguchi–Preneel scheme
 	data   []frontend.Variable // state storage. data is updated when Write() is called. Sum sums the data.
 	api    frontend.API        // underlying constraint system
 }
 
-// NewMiMC returns a MiMC instance, than can be used in a gnark circuit
-func NewMiMC(api frontend.API) (MiMC, error) {
+// NewMiMC returns a MiMC instance, than can be used in a gnark circuit. Without
+// options, it is the instance of gnark-crypto for the native field. The
+// options [WithRounds], [WithExponent] and [WithSeed] define another instance,
+// possibly over a field without a default instance.
+func NewMiMC(api frontend.API, opts ...Option) (MiMC, error) {
+	if len(opts) > 0 {
+		inst, err := newInstance(api.Compiler().Field(), opts...)
+		if err != nil {
+			return MiMC{}, err
+		}
+		res := MiMC{api: api, h: 0, exp: inst.exponent}
+		res.params = make([]big.Int, len(inst.constants))
+		for i := range inst.constants {
+			res.params[i].Set(inst.constants[i])
+		}
+		return res, nil
+	}
 	// TODO @gbotrel use field
 	if constructor, ok := newMimc[utils.FieldToCurve(api.Compiler().Field())]; ok {
 		return constructor(api), nil
@@ -63,7 +79,12 @@ func (h *MiMC) Sum() frontend.Variable {
 
 	//h.Write(data...)s
 	for _, stream := range h.data {
-		r := encryptFuncs[h.id](*h, stream)
+		var r frontend.Variable
+		if h.exp != 0 {
+			r = encryptPowN(*h, stream)
+		} else {
+			r = encryptFuncs[h.id](*h, stream)
+		}
 		h.h = h.api.Add(h.h, r, stream)
 	}
 
@@ -21,8 +21,16 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/mimc"
+	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/mimc"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
 	"github.com/consensys/gnark-crypto/hash"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/params"
 	"github.com/consensys/gnark/test"
 )
 
@@ -93,3 +101,77 @@ func TestMimcAll(t *testing.T) {
 	}
 
 }
+
+func TestRegisteredConstants(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	expected := map[ecc.ID][]big.Int{
+		ecc.BN254:     bn254.GetConstants(),
+		ecc.BLS12_381: bls12381.GetConstants(),
+		ecc.BLS12_377: bls12377.GetConstants(),
+		ecc.BW6_761:   bw6761.GetConstants(),
+		ecc.BW6_633:   bw6633.GetConstants(),
+		ecc.BLS24_315: bls24315.GetConstants(),
+		ecc.BLS24_317: bls24317.GetConstants(),
+	}
+	for curve, constants := range expected {
+		s, err := params.Get(paramsName(curve))
+		assert.NoError(err)
+		registered, err := s.Constants()
+		assert.NoError(err)
+		assert.Equal(len(constants), len(registered), curve.String())
+		// the derivation used with options gives the same constants
+		derived := roundConstants(curve.ScalarField(), seed, nbRounds[curve])
+		for i := range constants {
+			assert.Equal(0, constants[i].Cmp(registered[i]), "%s: constant %d", curve, i)
+			assert.Equal(0, constants[i].Cmp(derived[i]), "%s: derived constant %d", curve, i)
+		}
+	}
+}
+
+type mimcOptionsCircuit struct {
+	ExpectedResult frontend.Variable `gnark:"data,public"`
+	Data           [4]frontend.Variable
+}
+
+func (circuit *mimcOptionsCircuit) Define(api frontend.API) error {
+	mimc, err := NewMiMC(api, WithRounds(91), WithExponent(7), WithSeed("custom seed"))
+	if err != nil {
+		return err
+	}
+	mimc.Write(circuit.Data[:]...)
+	api.AssertIsEqual(mimc.Sum(), circuit.ExpectedResult)
+	return nil
+}
+
+func TestMimcOptions(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.ScalarField()
+	data := make([]*big.Int, 4)
+	for i := range data {
+		data[i] = new(big.Int).Sub(modulus, big.NewInt(int64(i+1)))
+	}
+
+	// without options, the native implementation matches gnark-crypto
+	expected, err := HashElements(modulus, data)
+	assert.NoError(err)
+	goMimc := hash.MIMC_BN254.New()
+	for i := range data {
+		goMimc.Write(data[i].FillBytes(make([]byte, 32)))
+	}
+	assert.Equal(0, expected.Cmp(new(big.Int).SetBytes(goMimc.Sum(nil))))
+
+	// with options, the circuit matches the native implementation
+	expected, err = HashElements(modulus, data, WithRounds(91), WithExponent(7), WithSeed("custom seed"))
+	assert.NoError(err)
+	var witness mimcOptionsCircuit
+	for i := range data {
+		witness.Data[i] = data[i]
+	}
+	witness.ExpectedResult = expected
+	assert.CheckCircuit(&mimcOptionsCircuit{}, test.WithValidAssignment(&witness), test.WithCurves(ecc.BN254))
+
+	// x -> x^3 is not a permutation of the scalar field of BN254
+	_, err = HashElements(modulus, data, WithExponent(3))
+	assert.Error(err)
+}
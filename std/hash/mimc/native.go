@@ -0,0 +1,29 @@
+package mimc
+
+import (
+	"math/big"
+)
+
+// HashElements returns the MiMC digest of the given field elements, computed
+// with the Miyaguchi–Preneel construction. It is the out-of-circuit
+// counterpart of [MiMC.Sum] with the same options. Without options, the
+// digest is the one of the MiMC implementation of gnark-crypto.
+func HashElements(field *big.Int, data []*big.Int, opts ...Option) (*big.Int, error) {
+	inst, err := newInstance(field, opts...)
+	if err != nil {
+		return nil, err
+	}
+	h := new(big.Int)
+	e := big.NewInt(int64(inst.exponent))
+	for _, m := range data {
+		// x = (x + h + c_i)^e for each round, then r = x + h
+		x := new(big.Int).Mod(m, field)
+		for _, c := range inst.constants {
+			x.Add(x, h).Add(x, c).Exp(x, e, field)
+		}
+		x.Add(x, h)
+		// h = h + r + m
+		h.Add(h, x).Add(h, m).Mod(h, field)
+	}
+	return h, nil
+}
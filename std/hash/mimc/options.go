@@ -0,0 +1,99 @@
+package mimc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/params"
+)
+
+// Option allows to use a MiMC instance other than the one of gnark-crypto,
+// for interoperability with external systems. The options are accepted by
+// [NewMiMC] and by the native [HashElements].
+type Option func(*config) error
+
+type config struct {
+	nbRounds int
+	exponent int
+	seed     string
+}
+
+// WithRounds sets the number of rounds of the encryption function. By
+// default, it is the number of rounds of gnark-crypto for the field.
+func WithRounds(nbRounds int) Option {
+	return func(c *config) error {
+		if nbRounds < 1 {
+			return fmt.Errorf("invalid number of rounds %d", nbRounds)
+		}
+		c.nbRounds = nbRounds
+		return nil
+	}
+}
+
+// WithExponent sets the exponent e of the S-box x -> x^e. It must be at least
+// 3 and coprime with p-1, for the S-box to be a permutation of the field. By
+// default, it is the exponent of gnark-crypto for the field.
+func WithExponent(e int) Option {
+	return func(c *config) error {
+		if e < 3 {
+			return fmt.Errorf("invalid exponent %d", e)
+		}
+		c.exponent = e
+		return nil
+	}
+}
+
+// WithSeed sets the seed from which the round constants are derived with a
+// chain of Keccak-256 hashes. By default, it is "seed", as in gnark-crypto.
+func WithSeed(seed string) Option {
+	return func(c *config) error {
+		c.seed = seed
+		return nil
+	}
+}
+
+// instance is a MiMC instance over a given field.
+type instance struct {
+	exponent  int
+	constants []*big.Int
+}
+
+// newInstance returns the MiMC instance over field defined by the options,
+// the missing parameters being the ones of gnark-crypto for the field.
+func newInstance(field *big.Int, opts ...Option) (*instance, error) {
+	id := utils.FieldToCurve(field)
+	cfg := config{
+		nbRounds: nbRounds[id],
+		exponent: exponents[id],
+		seed:     seed,
+	}
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.nbRounds == 0 || cfg.exponent == 0 {
+		return nil, errors.New("no default MiMC instance for the field, the number of rounds and the exponent must be set")
+	}
+	var gcd big.Int
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	if gcd.GCD(nil, nil, big.NewInt(int64(cfg.exponent)), pMinusOne).Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("exponent %d is not coprime with p-1", cfg.exponent)
+	}
+	inst := &instance{exponent: cfg.exponent}
+	if cfg.seed == seed && cfg.nbRounds == nbRounds[id] {
+		// the constants of gnark-crypto
+		s, err := params.Get(paramsName(id))
+		if err != nil {
+			return nil, err
+		}
+		if inst.constants, err = s.Constants(); err != nil {
+			return nil, err
+		}
+	} else {
+		inst.constants = roundConstants(field, cfg.seed, cfg.nbRounds)
+	}
+	return inst, nil
+}
@@ -0,0 +1,111 @@
+package mimc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
+	bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/mimc"
+	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/mimc"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+	"github.com/consensys/gnark/std/params"
+	"golang.org/x/crypto/sha3"
+)
+
+// seed is the seed of the round constants, as in gnark-crypto.
+const seed = "seed"
+
+// nbRounds is the number of rounds of MiMC for each curve, as in gnark-crypto.
+var nbRounds = map[ecc.ID]int{
+	ecc.BN254:     110,
+	ecc.BLS12_381: 111,
+	ecc.BLS12_377: 62,
+	ecc.BW6_761:   163,
+	ecc.BW6_633:   136,
+	ecc.BLS24_315: 109,
+	ecc.BLS24_317: 91,
+}
+
+// exponents is the exponent of the S-box x -> x^e of MiMC for each curve, as
+// in gnark-crypto.
+var exponents = map[ecc.ID]int{
+	ecc.BN254:     5,
+	ecc.BLS12_381: 5,
+	ecc.BLS12_377: 17,
+	ecc.BW6_761:   5,
+	ecc.BW6_633:   5,
+	ecc.BLS24_315: 5,
+	ecc.BLS24_317: 7,
+}
+
+// nativeConstants returns the round constants of the MiMC implementation of
+// gnark-crypto for each curve.
+var nativeConstants = map[ecc.ID]func() []big.Int{
+	ecc.BN254:     bn254.GetConstants,
+	ecc.BLS12_381: bls12381.GetConstants,
+	ecc.BLS12_377: bls12377.GetConstants,
+	ecc.BW6_761:   bw6761.GetConstants,
+	ecc.BW6_633:   bw6633.GetConstants,
+	ecc.BLS24_315: bls24315.GetConstants,
+	ecc.BLS24_317: bls24317.GetConstants,
+}
+
+func init() {
+	for id, constants := range nativeConstants {
+		constants := constants
+		derivation := fmt.Sprintf("round constants of gnark-crypto ecc/%s/fr/mimc, derived from the seed with a chain of Keccak-256 hashes", id)
+		params.Register(paramsName(id), 1, seed, derivation, func() ([]*big.Int, error) {
+			c := constants()
+			res := make([]*big.Int, len(c))
+			for i := range c {
+				res[i] = new(big.Int).Set(&c[i])
+			}
+			return res, nil
+		})
+	}
+}
+
+// paramsName returns the name of the round constants of the curve in the
+// [params] registry.
+func paramsName(id ecc.ID) string {
+	return "mimc/" + id.String()
+}
+
+// roundConstants derives the round constants from the seed with a chain of
+// Keccak-256 hashes, the first hash of the seed being discarded, as
+// gnark-crypto does. It is used for the instances defined with options.
+func roundConstants(modulus *big.Int, seed string, n int) []*big.Int {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(seed))
+	rnd := h.Sum(nil)
+	res := make([]*big.Int, n)
+	for i := range res {
+		h.Reset()
+		h.Write(rnd)
+		rnd = h.Sum(nil)
+		res[i] = new(big.Int).SetBytes(rnd)
+		res[i].Mod(res[i], modulus)
+	}
+	return res
+}
+
+// getConstants returns the round constants of the curve from the registry.
+func getConstants(id ecc.ID) []big.Int {
+	s, err := params.Get(paramsName(id))
+	if err != nil {
+		panic(err)
+	}
+	constants, err := s.Constants()
+	if err != nil {
+		panic(err)
+	}
+	res := make([]big.Int, len(constants))
+	for i := range constants {
+		res[i].Set(constants[i])
+	}
+	return res
+}
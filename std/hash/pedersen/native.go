@@ -0,0 +1,75 @@
+package pedersen
+
+import (
+	"math/big"
+)
+
+// HashBits returns the Pedersen hash of bits as a point of the curve. It is
+// the out-of-circuit counterpart of [Pedersen.SumPoint].
+//
+// The bits are padded with zeros to a multiple of 3 and split in chunks
+// (b0, b1, b2) encoded as (1 - 2⋅b2)⋅(1 + b0 + 2⋅b1). The chunks are grouped in
+// segments of [Parameters.ChunksPerSegment] chunks, the j-th chunk of a segment
+// being weighted by 16^j. The hash is the sum of the segment encodings
+// multiplied by the generators of the segments.
+func (p *Parameters) HashBits(bits []bool) (x, y *big.Int) {
+	res := p.hashBits(bits)
+	return res.x, res.y
+}
+
+func (p *Parameters) hashBits(bits []bool) point {
+	res := p.identity()
+	nbChunks := (len(bits) + 2) / 3
+	bit := func(i int) int64 {
+		if i < len(bits) && bits[i] {
+			return 1
+		}
+		return 0
+	}
+	for seg := 0; seg*p.ChunksPerSegment < nbChunks; seg++ {
+		var scalar, t big.Int
+		for j := 0; j < p.ChunksPerSegment && seg*p.ChunksPerSegment+j < nbChunks; j++ {
+			c := 3 * (seg*p.ChunksPerSegment + j)
+			enc := (1 - 2*bit(c+2)) * (1 + bit(c) + 2*bit(c+1))
+			t.Lsh(big.NewInt(enc), uint(4*j))
+			scalar.Add(&scalar, &t)
+		}
+		g := p.generator(seg)
+		if scalar.Sign() < 0 {
+			g = p.neg(g)
+			scalar.Neg(&scalar)
+		}
+		res = p.add(res, p.scalarMul(g, &scalar))
+	}
+	return res
+}
+
+// HashElements returns the x coordinate of the Pedersen hash of the field
+// elements. Each element is decomposed in the number of bits of the field,
+// least significant bit first. It is the out-of-circuit counterpart of
+// [Pedersen.Sum].
+func (p *Parameters) HashElements(data ...*big.Int) *big.Int {
+	return p.hashBits(p.elementsToBits(data)).x
+}
+
+// Commit returns the Pedersen commitment to bits with the given randomness,
+// that is the hash of bits plus the randomness times a dedicated generator. It
+// is the out-of-circuit counterpart of [Pedersen.Commit].
+func (p *Parameters) Commit(bits []bool, randomness *big.Int) (x, y *big.Int) {
+	r := new(big.Int).Mod(randomness, p.Curve.Order)
+	res := p.add(p.hashBits(bits), p.scalarMul(p.blindingGenerator(), r))
+	return res.x, res.y
+}
+
+func (p *Parameters) elementsToBits(data []*big.Int) []bool {
+	nbBits := p.Field.BitLen()
+	bits := make([]bool, 0, len(data)*nbBits)
+	var e big.Int
+	for _, d := range data {
+		e.Mod(d, p.Field)
+		for i := 0; i < nbBits; i++ {
+			bits = append(bits, e.Bit(i) == 1)
+		}
+	}
+	return bits
+}
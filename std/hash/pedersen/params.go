@@ -0,0 +1,222 @@
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sync"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// Parameters define an instance of the Pedersen hash over a twisted Edwards
+// curve. The generators are derived from the domain, so that instances with
+// different domains are independent.
+type Parameters struct {
+	ID     tedwards.ID
+	Domain string
+
+	// Curve are the parameters of the twisted Edwards curve and Field its base
+	// field (the scalar field of the SNARK curve).
+	Curve *twistededwards.CurveParams
+	Field *big.Int
+
+	// ChunksPerSegment is the number of 3-bit chunks encoded with the same
+	// generator. It is the largest number for which the encoding of a segment
+	// is injective modulo the order of the subgroup.
+	ChunksPerSegment int
+
+	lock       sync.Mutex
+	generators []point // lazily derived generators of the segments
+	blinding   *point  // lazily derived generator of the commitment randomness
+}
+
+// NewParameters returns the parameters of the Pedersen hash over the twisted
+// Edwards curve id with the given domain separation string.
+func NewParameters(id tedwards.ID, domain string) (*Parameters, error) {
+	curve, err := twistededwards.GetCurveParams(id)
+	if err != nil {
+		return nil, err
+	}
+	field, err := twistededwards.GetSnarkField(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the encoding of a segment of c chunks is in [-M, M] with
+	// M = 4 * (16^c - 1) / 15, it must not wrap around (Order-1)/2.
+	bound := new(big.Int).Rsh(curve.Order, 1)
+	c := 0
+	for m := big.NewInt(4); m.Cmp(bound) <= 0; m.Lsh(m, 4).Add(m, big.NewInt(4)) {
+		c++
+	}
+	if c == 0 {
+		return nil, errors.New("subgroup order too small")
+	}
+
+	return &Parameters{
+		ID:               id,
+		Domain:           domain,
+		Curve:            curve,
+		Field:            field,
+		ChunksPerSegment: c,
+	}, nil
+}
+
+// point is a point of the curve in affine coordinates.
+type point struct {
+	x, y *big.Int
+}
+
+func (p *Parameters) identity() point {
+	return point{new(big.Int), big.NewInt(1)}
+}
+
+// add returns p1 + p2 with the unified addition formula.
+func (p *Parameters) add(p1, p2 point) point {
+	f := p.Field
+	var x1y2, y1x2, y1y2, x1x2, dxy, t big.Int
+	x1y2.Mul(p1.x, p2.y)
+	y1x2.Mul(p1.y, p2.x)
+	y1y2.Mul(p1.y, p2.y)
+	x1x2.Mul(p1.x, p2.x)
+	dxy.Mul(&x1x2, &y1y2).Mul(&dxy, p.Curve.D).Mod(&dxy, f)
+
+	x := new(big.Int).Add(&x1y2, &y1x2)
+	t.Add(big.NewInt(1), &dxy).ModInverse(&t, f)
+	x.Mul(x, &t).Mod(x, f)
+
+	y := new(big.Int).Mul(p.Curve.A, &x1x2)
+	y.Sub(&y1y2, y)
+	t.Sub(big.NewInt(1), &dxy).Mod(&t, f).ModInverse(&t, f)
+	y.Mul(y, &t).Mod(y, f)
+	return point{x, y}
+}
+
+// neg returns -p1.
+func (p *Parameters) neg(p1 point) point {
+	x := new(big.Int).Neg(p1.x)
+	return point{x.Mod(x, p.Field), new(big.Int).Set(p1.y)}
+}
+
+// scalarMul returns [s]p1 for s >= 0.
+func (p *Parameters) scalarMul(p1 point, s *big.Int) point {
+	res := p.identity()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = p.add(res, res)
+		if s.Bit(i) == 1 {
+			res = p.add(res, p1)
+		}
+	}
+	return res
+}
+
+// generator returns the generator of the segment i.
+func (p *Parameters) generator(i int) point {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for len(p.generators) <= i {
+		p.generators = append(p.generators, p.hashToPoint("segment", uint32(len(p.generators))))
+	}
+	return p.generators[i]
+}
+
+// blindingGenerator returns the generator of the commitment randomness.
+func (p *Parameters) blindingGenerator() point {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.blinding == nil {
+		g := p.hashToPoint("blinding", 0)
+		p.blinding = &g
+	}
+	return *p.blinding
+}
+
+// BlindingGenerator returns the generator of the commitment randomness used by
+// [Parameters.Commit].
+func (p *Parameters) BlindingGenerator() (x, y *big.Int) {
+	g := p.blindingGenerator()
+	return new(big.Int).Set(g.x), new(big.Int).Set(g.y)
+}
+
+// Generator returns the point derived from the domain, tag and index as the
+// generators of the hash, so that protocols built over the same parameters get
+// independent generators. The tags "segment" and "blinding" are reserved for
+// the hash and the commitment.
+func (p *Parameters) Generator(tag string, index uint32) (x, y *big.Int) {
+	if tag == "segment" || tag == "blinding" {
+		panic("reserved generator tag " + tag)
+	}
+	g := p.hashToPoint(tag, index)
+	return g.x, g.y
+}
+
+// hashToPoint derives a point of the prime order subgroup with an unknown
+// discrete logarithm with respect to the other generators. The candidate y
+// coordinates are SHA-256(domain || 0 || tag || 0 || index || counter) for
+// increasing counters, until the corresponding x exists and the point
+// multiplied by the cofactor is not the identity.
+func (p *Parameters) hashToPoint(tag string, index uint32) point {
+	f := p.Field
+	one := big.NewInt(1)
+	for counter := uint32(0); ; counter++ {
+		h := sha256.New()
+		h.Write([]byte(p.Domain))
+		h.Write([]byte{0})
+		h.Write([]byte(tag))
+		h.Write([]byte{0})
+		var buf [8]byte
+		binary.BigEndian.PutUint32(buf[:4], index)
+		binary.BigEndian.PutUint32(buf[4:], counter)
+		h.Write(buf[:])
+
+		y := new(big.Int).SetBytes(h.Sum(nil))
+		y.Mod(y, f)
+
+		// x² = (1 - y²) / (a - d y²)
+		var y2, num, den big.Int
+		y2.Mul(y, y).Mod(&y2, f)
+		num.Sub(one, &y2).Mod(&num, f)
+		den.Mul(p.Curve.D, &y2).Sub(p.Curve.A, &den).Mod(&den, f)
+		if den.Sign() == 0 {
+			continue
+		}
+		den.ModInverse(&den, f)
+		num.Mul(&num, &den).Mod(&num, f)
+		x := new(big.Int).ModSqrt(&num, f)
+		if x == nil {
+			continue
+		}
+
+		g := p.scalarMul(point{x, y}, p.Curve.Cofactor)
+		if g.x.Sign() == 0 {
+			// identity or point of order 2
+			continue
+		}
+		return g
+	}
+}
+
+// chunkTables returns for each of the nbChunks chunks the points [k⋅16^j]G for
+// k in 1..4, where j is the index of the chunk in its segment and G the
+// generator of the segment.
+func (p *Parameters) chunkTables(nbChunks int) [][4]point {
+	tables := make([][4]point, nbChunks)
+	var base point
+	for i := range tables {
+		if i%p.ChunksPerSegment == 0 {
+			base = p.generator(i / p.ChunksPerSegment)
+		} else {
+			for k := 0; k < 4; k++ {
+				base = p.add(base, base)
+			}
+		}
+		tables[i][0] = base
+		for k := 1; k < 4; k++ {
+			tables[i][k] = p.add(tables[i][k-1], base)
+		}
+	}
+	return tables
+}
@@ -0,0 +1,130 @@
+// Package pedersen provides a ZKP-circuit function to compute a Pedersen hash
+// over the twisted Edwards curve embedded in the SNARK field.
+//
+// The hash follows the construction of the Zcash Sapling protocol: the input
+// bits are split in 3-bit signed chunks, grouped in segments, and each segment
+// is encoded as a scalar multiple of its own generator. The chunks are windowed:
+// the multiples of the generators are precomputed at compile time and selected
+// with the bits of the chunk, so that each chunk costs a lookup and a point
+// addition. The generators are derived from a domain separation string (see
+// [NewParameters]), which replaces the personalization of Zcash. The digests
+// are thus not compatible with Zcash.
+//
+// [Pedersen.Commit] adds a blinding term for hiding commitments, as in the
+// Zcash note commitments.
+//
+// The Pedersen hash is collision resistant, but not a random oracle: it
+// shouldn't be used where a pseudo-random output is needed.
+package pedersen
+
+import (
+	"errors"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+)
+
+// Pedersen computes the Pedersen hash of bits or field elements in-circuit. It
+// implements [github.com/consensys/gnark/std/hash.FieldHasher].
+type Pedersen struct {
+	api    frontend.API
+	curve  twistededwards.Curve
+	params *Parameters
+	bits   []frontend.Variable
+}
+
+// New returns a Pedersen instance over the twisted Edwards curve id with the
+// generators derived from domain. The curve must be defined over the native
+// field.
+func New(api frontend.API, id tedwards.ID, domain string) (*Pedersen, error) {
+	params, err := NewParameters(id, domain)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithParameters(api, params)
+}
+
+// NewWithParameters returns a Pedersen instance using the given parameters.
+// The parameters must be defined over the native field.
+func NewWithParameters(api frontend.API, params *Parameters) (*Pedersen, error) {
+	if api.Compiler().Field().Cmp(params.Field) != 0 {
+		return nil, errors.New("pedersen parameters not defined over the native field")
+	}
+	curve, err := twistededwards.NewEdCurve(api, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Pedersen{api: api, curve: curve, params: params}, nil
+}
+
+// Write adds field elements to the running hash. Each element is decomposed in
+// the number of bits of the field, least significant bit first.
+func (h *Pedersen) Write(data ...frontend.Variable) {
+	for _, d := range data {
+		h.bits = append(h.bits, h.api.ToBinary(d)...)
+	}
+}
+
+// WriteBits adds bits to the running hash. The bits are constrained to be
+// boolean.
+func (h *Pedersen) WriteBits(bits ...frontend.Variable) {
+	for _, b := range bits {
+		h.api.AssertIsBoolean(b)
+	}
+	h.bits = append(h.bits, bits...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Pedersen) Reset() {
+	h.bits = nil
+}
+
+// Sum returns the x coordinate of the hash of the data written since the last
+// reset. See [Parameters.HashElements].
+func (h *Pedersen) Sum() frontend.Variable {
+	return h.SumPoint().X
+}
+
+// SumPoint returns the hash of the data written since the last reset as a
+// point of the curve. See [Parameters.HashBits].
+func (h *Pedersen) SumPoint() twistededwards.Point {
+	api := h.api
+	nbChunks := (len(h.bits) + 2) / 3
+	if nbChunks == 0 {
+		return twistededwards.Point{X: 0, Y: 1}
+	}
+	bit := func(i int) frontend.Variable {
+		if i < len(h.bits) {
+			return h.bits[i]
+		}
+		return 0
+	}
+
+	tables := h.params.chunkTables(nbChunks)
+	var res twistededwards.Point
+	for i := range tables {
+		t := &tables[i]
+		s0, s1, s2 := bit(3*i), bit(3*i+1), bit(3*i+2)
+		// [1 + s0 + 2⋅s1]P, then conditional negation
+		x := api.Lookup2(s0, s1, t[0].x, t[1].x, t[2].x, t[3].x)
+		y := api.Lookup2(s0, s1, t[0].y, t[1].y, t[2].y, t[3].y)
+		x = api.Select(s2, api.Neg(x), x)
+		p := twistededwards.Point{X: x, Y: y}
+		if i == 0 {
+			res = p
+		} else {
+			res = h.curve.Add(res, p)
+		}
+	}
+	return res
+}
+
+// Commit returns the commitment to the data written since the last reset with
+// the given randomness. It is the hash of the data plus the randomness times a
+// dedicated generator. See [Parameters.Commit].
+func (h *Pedersen) Commit(randomness frontend.Variable) twistededwards.Point {
+	g := h.params.blindingGenerator()
+	blinding := h.curve.ScalarMul(twistededwards.Point{X: g.x, Y: g.y}, randomness)
+	return h.curve.Add(h.SumPoint(), blinding)
+}
@@ -0,0 +1,122 @@
+package pedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+const testDomain = "gnark/pedersen/test"
+
+type pedersenCircuit struct {
+	Data     [2]frontend.Variable
+	Bits     [10]frontend.Variable
+	Expected frontend.Variable `gnark:",public"`
+
+	// commitment to Bits
+	Randomness frontend.Variable
+	Commitment twistededwards.Point `gnark:",public"`
+
+	id tedwards.ID
+}
+
+func (c *pedersenCircuit) Define(api frontend.API) error {
+	h, err := New(api, c.id, testDomain)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+
+	h.Reset()
+	h.WriteBits(c.Bits[:]...)
+	cm := h.Commit(c.Randomness)
+	api.AssertIsEqual(cm.X, c.Commitment.X)
+	api.AssertIsEqual(cm.Y, c.Commitment.Y)
+	return nil
+}
+
+func TestPedersen(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for name, id := range map[string]tedwards.ID{
+		"bn254":        tedwards.BN254,
+		"bls12-381":    tedwards.BLS12_381,
+		"bandersnatch": tedwards.BLS12_381_BANDERSNATCH,
+	} {
+		id := id
+		assert.Run(func(assert *test.Assert) {
+			params, err := NewParameters(id, testDomain)
+			assert.NoError(err)
+
+			// two elements span several segments
+			var data [2]*big.Int
+			for i := range data {
+				data[i], err = rand.Int(rand.Reader, params.Field)
+				assert.NoError(err)
+			}
+			bits := []bool{true, false, true, true, true, true, false, false, true, true}
+			randomness, err := rand.Int(rand.Reader, params.Curve.Order)
+			assert.NoError(err)
+			cx, cy := params.Commit(bits, randomness)
+
+			witness := pedersenCircuit{
+				Data:       [2]frontend.Variable{data[0], data[1]},
+				Expected:   params.HashElements(data[:]...),
+				Randomness: randomness,
+				Commitment: twistededwards.Point{X: cx, Y: cy},
+			}
+			for i := range bits {
+				witness.Bits[i] = 0
+				if bits[i] {
+					witness.Bits[i] = 1
+				}
+			}
+			field, err := twistededwards.GetSnarkField(id)
+			assert.NoError(err)
+			err = test.IsSolved(&pedersenCircuit{id: id}, &witness, field)
+			assert.NoError(err)
+
+			// wrong digest
+			witness.Expected = new(big.Int).Add(params.HashElements(data[:]...), big.NewInt(1))
+			err = test.IsSolved(&pedersenCircuit{id: id}, &witness, field)
+			assert.Error(err)
+		}, name)
+	}
+}
+
+func TestHashBits(t *testing.T) {
+	assert := test.NewAssert(t)
+	params, err := NewParameters(tedwards.BN254, testDomain)
+	assert.NoError(err)
+
+	// the hash of a single chunk is ±k times the first generator
+	g := params.generator(0)
+	for k := 0; k < 8; k++ {
+		bits := []bool{k&1 == 1, k&2 == 2, k&4 == 4}
+		enc := int64(1 + k&3)
+		expected := params.scalarMul(g, big.NewInt(enc))
+		if k&4 == 4 {
+			expected = params.neg(expected)
+		}
+		x, y := params.HashBits(bits)
+		assert.Equal(expected.x, x)
+		assert.Equal(expected.y, y)
+	}
+
+	// trailing zero bits are padding
+	x1, y1 := params.HashBits([]bool{true})
+	x2, y2 := params.HashBits([]bool{true, false, false})
+	assert.Equal(x1, x2)
+	assert.Equal(y1, y2)
+
+	// the domain separates the generators
+	other, err := NewParameters(tedwards.BN254, testDomain+"/other")
+	assert.NoError(err)
+	assert.NotEqual(params.generator(0).x, other.generator(0).x)
+}
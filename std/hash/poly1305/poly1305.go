@@ -0,0 +1,137 @@
+// Package poly1305 implements the Poly1305 one-time authenticator in-circuit,
+// as defined in [RFC 8439].
+//
+// The message is split into blocks of 16 bytes which are evaluated as a
+// polynomial at the clamped first half r of the key, modulo the prime
+// 2¹³⁰ - 5, and the second half s of the key is added to the result. The
+// arithmetic modulo 2¹³⁰ - 5 is emulated with [emulated.Field], so that the
+// cost is about one emulated multiplication per block.
+//
+// The lengths of the key and of the message must be known at compile time.
+//
+// [RFC 8439]: https://www.rfc-editor.org/rfc/rfc8439
+package poly1305
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// KeySize is the size of the one-time key in bytes.
+	KeySize = 32
+	// TagSize is the size of the authentication tag in bytes.
+	TagSize = 16
+
+	blockSize = 16
+)
+
+// clamp is the mask applied to r, in little-endian words.
+var clamp = [4]uint32{0x0fffffff, 0x0ffffffc, 0x0ffffffc, 0x0ffffffc}
+
+// fieldParams are the emulation parameters of the field modulo 2¹³⁰ - 5.
+type fieldParams struct{}
+
+func (fieldParams) NbLimbs() uint     { return 3 }
+func (fieldParams) BitsPerLimb() uint { return 64 }
+func (fieldParams) IsPrime() bool     { return true }
+func (fieldParams) Modulus() *big.Int { return modulus }
+
+var modulus = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 130), big.NewInt(5))
+
+type mac struct {
+	api frontend.API
+	f   *emulated.Field[fieldParams]
+	r   *emulated.Element[fieldParams]
+	s   frontend.Variable
+	in  []uints.U8
+}
+
+// New returns a new Poly1305 authenticator with the one-time key of [KeySize]
+// bytes. The key must not be used for more than one message.
+func New(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size %d, must be %d", len(key), KeySize)
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	f, err := emulated.NewField[fieldParams](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	r := make([]uints.U8, 0, blockSize)
+	for i := range clamp {
+		w := uapi.And(uapi.PackLSB(key[4*i:4*i+4]...), uints.NewU32(clamp[i]))
+		r = append(r, uapi.UnpackLSB(w)...)
+	}
+	var s frontend.Variable = 0
+	for i := KeySize - 1; i >= blockSize; i-- {
+		s = api.Add(api.Mul(s, 256), key[i].Val)
+	}
+	return &mac{
+		api: api,
+		f:   f,
+		r:   f.NewElement(limbs(api, r, false)),
+		s:   s,
+	}, nil
+}
+
+func (m *mac) Write(data []uints.U8) {
+	m.in = append(m.in, data...)
+}
+
+func (m *mac) Size() int { return TagSize }
+
+func (m *mac) Reset() {
+	m.in = nil
+}
+
+func (m *mac) Sum() []uints.U8 {
+	// acc = (acc + block)·r for each block, where a one byte is appended to
+	// the blocks
+	acc := m.f.Zero()
+	for i := 0; i < len(m.in); i += blockSize {
+		block := m.in[i:]
+		if len(block) > blockSize {
+			block = block[:blockSize]
+		}
+		acc = m.f.Mul(m.f.Add(acc, m.f.NewElement(limbs(m.api, block, true))), m.r)
+	}
+
+	// tag = (acc mod 2¹³⁰ - 5) + s mod 2¹²⁸, where the sum of the low 128 bits
+	// of acc and of s fits in the native field
+	acc = m.f.Reduce(acc)
+	m.f.AssertIsInRange(acc)
+	accBits := m.f.ToBits(acc)
+	sum := m.api.Add(bits.FromBinary(m.api, accBits[:8*TagSize]), m.s)
+	sumBits := bits.ToBinary(m.api, sum, bits.WithNbDigits(8*TagSize+1))
+	tag := make([]uints.U8, TagSize)
+	for i := range tag {
+		tag[i] = uints.U8{Val: bits.FromBinary(m.api, sumBits[8*i:8*i+8])}
+	}
+	return tag
+}
+
+// limbs returns the limbs of 64 bits of the little-endian integer b, followed
+// by a one byte if pad is set.
+func limbs(api frontend.API, b []uints.U8, pad bool) []frontend.Variable {
+	res := make([]frontend.Variable, 3)
+	for i := range res {
+		res[i] = 0
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		res[i/8] = api.Add(api.Mul(res[i/8], 256), b[i].Val)
+	}
+	if pad {
+		res[len(b)/8] = api.Add(res[len(b)/8], new(big.Int).Lsh(big.NewInt(1), uint(8*(len(b)%8))))
+	}
+	return res
+}
@@ -0,0 +1,91 @@
+package poly1305
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type poly1305Circuit struct {
+	Key      []uints.U8
+	In       []uints.U8
+	Expected []uints.U8
+}
+
+func (c *poly1305Circuit) Define(api frontend.API) error {
+	h, err := New(api, c.Key)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestPoly1305(t *testing.T) {
+	assert := test.NewAssert(t)
+	// test vectors of RFC 8439, sections 2.5.2 and A.3
+	for _, c := range []struct {
+		key, msg, tag string
+	}{
+		{
+			"85d6be7857556d337f4452fe42d506a80103808afb0db2fd4abff6af4149f51b",
+			hex.EncodeToString([]byte("Cryptographic Forum Research Group")),
+			"a8061dc1305136c6c22b8baf0c0127a9",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000000",
+			"00000000000000000000000000000000",
+		},
+		{
+			"0200000000000000000000000000000000000000000000000000000000000000",
+			"ffffffffffffffffffffffffffffffff",
+			"03000000000000000000000000000000",
+		},
+		{
+			"02000000000000000000000000000000ffffffffffffffffffffffffffffffff",
+			"02000000000000000000000000000000",
+			"03000000000000000000000000000000",
+		},
+		{
+			"0100000000000000000000000000000000000000000000000000000000000000",
+			"fffffffffffffffffffffffffffffffff0ffffffffffffffffffffffffffffff" +
+				"11000000000000000000000000000000",
+			"05000000000000000000000000000000",
+		},
+	} {
+		key, _ := hex.DecodeString(c.key)
+		msg, _ := hex.DecodeString(c.msg)
+		tag, _ := hex.DecodeString(c.tag)
+		circuit := poly1305Circuit{
+			Key:      make([]uints.U8, len(key)),
+			In:       make([]uints.U8, len(msg)),
+			Expected: make([]uints.U8, len(tag)),
+		}
+		witness := poly1305Circuit{
+			Key:      uints.NewU8Array(key),
+			In:       uints.NewU8Array(msg),
+			Expected: uints.NewU8Array(tag),
+		}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "tag %s", c.tag)
+
+		tag[0] ^= 1
+		witness.Expected = uints.NewU8Array(tag)
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.Error(err, "tag %s", c.tag)
+	}
+}
@@ -0,0 +1,125 @@
+package poseidon
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+)
+
+// Permute applies the Poseidon permutation to state in place. The length of
+// state must be p.T and its elements must be reduced modulo p.Field.
+func (p *Parameters) Permute(state []*big.Int) {
+	if len(state) != p.T {
+		panic("state length does not match the permutation width")
+	}
+	tmp := make([]*big.Int, p.T)
+	for i := range tmp {
+		tmp[i] = new(big.Int)
+	}
+	exp := big.NewInt(int64(p.Alpha))
+	for r := 0; r < p.RF+p.RP; r++ {
+		for i := range state {
+			state[i].Add(state[i], p.RoundConstants[r][i]).Mod(state[i], p.Field)
+		}
+		if p.isFullRound(r) {
+			for i := range state {
+				state[i].Exp(state[i], exp, p.Field)
+			}
+		} else {
+			state[0].Exp(state[0], exp, p.Field)
+		}
+		var t big.Int
+		for i := range tmp {
+			tmp[i].SetUint64(0)
+			for j := range state {
+				tmp[i].Add(tmp[i], t.Mul(p.MDS[i][j], state[j]))
+			}
+			tmp[i].Mod(tmp[i], p.Field)
+		}
+		for i := range state {
+			state[i].Set(tmp[i])
+		}
+	}
+}
+
+func (p *Parameters) isFullRound(r int) bool {
+	return r < p.RF/2 || r >= p.RF/2+p.RP
+}
+
+// HashElements returns the Poseidon digest of the given field elements. It is
+// the out-of-circuit counterpart of [Poseidon.Sum].
+//
+// The inputs are absorbed in chunks of T-1 elements into the last T-1
+// elements of the state and the first element of the state is returned. In
+// particular, the digest of exactly T-1 elements is the same as circomlib's
+// Poseidon over those elements. The inputs are not padded, the length of the
+// input should be fixed by the application.
+func (p *Parameters) HashElements(data ...*big.Int) *big.Int {
+	state := make([]*big.Int, p.T)
+	for i := range state {
+		state[i] = new(big.Int)
+	}
+	rate := p.T - 1
+	for i := 0; i == 0 || i < len(data); i += rate {
+		for j := 0; j < rate && i+j < len(data); j++ {
+			state[j+1].Add(state[j+1], data[i+j]).Mod(state[j+1], p.Field)
+		}
+		p.Permute(state)
+	}
+	return state[0]
+}
+
+var errNotReduced = errors.New("input is not a reduced field element")
+
+type nativeHasher struct {
+	params *Parameters
+	data   []*big.Int
+}
+
+// NewNativeHasher returns a [hash.Hash] computing the same digest as
+// [Poseidon] with the default parameters of width [DefaultWidth] over the given
+// field. It is meant to compute the witness values of circuits using the
+// in-circuit hasher.
+//
+// Write expects the concatenation of big-endian encoded field elements, each
+// one of the size of the field modulus in bytes and strictly lower than the
+// modulus. Sum appends the big-endian encoding of the digest.
+func NewNativeHasher(field *big.Int) (hash.Hash, error) {
+	params, err := GetDefaultParameters(field, DefaultWidth)
+	if err != nil {
+		return nil, err
+	}
+	return &nativeHasher{params: params}, nil
+}
+
+func (h *nativeHasher) Write(b []byte) (int, error) {
+	size := h.Size()
+	if len(b)%size != 0 {
+		return 0, errors.New("input length must be a multiple of the field element size")
+	}
+	for i := 0; i < len(b); i += size {
+		e := new(big.Int).SetBytes(b[i : i+size])
+		if e.Cmp(h.params.Field) >= 0 {
+			return i, errNotReduced
+		}
+		h.data = append(h.data, e)
+	}
+	return len(b), nil
+}
+
+func (h *nativeHasher) Sum(b []byte) []byte {
+	res := h.params.HashElements(h.data...)
+	return append(b, res.FillBytes(make([]byte, h.Size()))...)
+}
+
+func (h *nativeHasher) Reset() {
+	h.data = nil
+}
+
+func (h *nativeHasher) Size() int {
+	return (h.params.Field.BitLen() + 7) / 8
+}
+
+func (h *nativeHasher) BlockSize() int {
+	return h.Size()
+}
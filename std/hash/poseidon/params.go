@@ -0,0 +1,247 @@
+package poseidon
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/internal/grain"
+	"github.com/consensys/gnark/std/params"
+)
+
+// DefaultWidth is the width (number of field elements in the state) of the
+// permutation used by [NewPoseidon] and [NewNativeHasher]. It allows to hash
+// two field elements with a single permutation.
+const DefaultWidth = 3
+
+// Parameters define an instance of the Poseidon permutation over a prime field.
+type Parameters struct {
+	Field *big.Int // modulus of the field
+	T     int      // width of the permutation (number of field elements in the state)
+	RF    int      // number of full rounds, half of them at the beginning and half at the end
+	RP    int      // number of partial rounds
+	Alpha int      // exponent of the S-box x -> x^Alpha
+
+	// RoundConstants[r] are the constants added to the state at round r.
+	RoundConstants [][]*big.Int
+	// MDS is the t×t matrix applied to the state at the end of each round.
+	MDS [][]*big.Int
+}
+
+var (
+	parametersCache   = make(map[string]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// GetDefaultParameters returns the parameters of the Poseidon permutation of
+// width t over the given field, targeting 128 bits of security.
+//
+// The S-box exponent is the smallest alpha such that gcd(alpha, p-1) = 1 and
+// the number of rounds is obtained with the round numbers script of the
+// reference implementation (including its security margin), the number of
+// partial rounds being rounded up to a multiple of t. Over BN254 and
+// BLS12-381, the instances match the test vectors of the reference
+// implementation, and over BN254 the ones of circomlib for t in [2, 17], so
+// that digests are compatible.
+//
+// The returned parameters are shared and must not be modified.
+func GetDefaultParameters(field *big.Int, t int) (*Parameters, error) {
+	if t < 2 {
+		return nil, fmt.Errorf("invalid width %d, must be at least 2", t)
+	}
+	key := field.Text(16) + "/" + fmt.Sprint(t)
+
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[key]; ok {
+		return p, nil
+	}
+
+	alpha, err := sboxExponent(field)
+	if err != nil {
+		return nil, err
+	}
+	rf, rp := roundNumbers(field, t, alpha)
+	p, err := NewParameters(field, t, rf, rp, alpha)
+	if err != nil {
+		return nil, err
+	}
+	parametersCache[key] = p
+	return p, nil
+}
+
+// NewParameters returns the parameters of the Poseidon permutation with the
+// given width, number of rounds and S-box exponent. The round constants and
+// the MDS matrix are derived from the Grain LFSR as in the reference
+// implementation [generate_parameters_grain.sage].
+//
+// The MDS matrix is not checked against the subspace trail attacks; the caller
+// is responsible for choosing parameters for which the first generated matrix is
+// secure.
+//
+// [generate_parameters_grain.sage]: https://extgit.iaik.tugraz.at/krypto/hadeshash
+func NewParameters(field *big.Int, t, rf, rp, alpha int) (*Parameters, error) {
+	if t < 2 {
+		return nil, fmt.Errorf("invalid width %d, must be at least 2", t)
+	}
+	if rf <= 0 || rf%2 != 0 {
+		return nil, fmt.Errorf("invalid number of full rounds %d, must be positive and even", rf)
+	}
+	if rp < 0 {
+		return nil, fmt.Errorf("invalid number of partial rounds %d", rp)
+	}
+	if alpha < 3 || new(big.Int).GCD(nil, nil, big.NewInt(int64(alpha)), new(big.Int).Sub(field, big.NewInt(1))).Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("invalid S-box exponent %d, x^%d is not a permutation of the field", alpha, alpha)
+	}
+	n := field.BitLen()
+	g := grain.New(n, t, rf, rp)
+
+	p := &Parameters{
+		Field: new(big.Int).Set(field),
+		T:     t,
+		RF:    rf,
+		RP:    rp,
+		Alpha: alpha,
+	}
+
+	// round constants
+	p.RoundConstants = make([][]*big.Int, rf+rp)
+	for r := range p.RoundConstants {
+		p.RoundConstants[r] = make([]*big.Int, t)
+		for i := range p.RoundConstants[r] {
+			p.RoundConstants[r][i] = g.NextFieldElement(field)
+		}
+	}
+
+	// Cauchy MDS matrix M[i][j] = 1 / (x_i + y_j)
+	for {
+		xy := sampleDistinct(g, n, field, 2*t)
+		xs, ys := xy[:t], xy[t:]
+		mds, ok := cauchyMatrix(xs, ys, field)
+		if ok {
+			p.MDS = mds
+			break
+		}
+	}
+	return p, nil
+}
+
+func sampleDistinct(g *grain.Grain, n int, field *big.Int, nb int) []*big.Int {
+	for {
+		res := make([]*big.Int, nb)
+		seen := make(map[string]struct{}, nb)
+		for i := range res {
+			res[i] = g.NextInt(n)
+			res[i].Mod(res[i], field)
+			seen[string(res[i].Bytes())] = struct{}{}
+		}
+		if len(seen) == nb {
+			return res
+		}
+	}
+}
+
+func cauchyMatrix(xs, ys []*big.Int, field *big.Int) ([][]*big.Int, bool) {
+	m := make([][]*big.Int, len(xs))
+	for i := range xs {
+		m[i] = make([]*big.Int, len(ys))
+		for j := range ys {
+			s := new(big.Int).Add(xs[i], ys[j])
+			s.Mod(s, field)
+			if s.Sign() == 0 {
+				return nil, false
+			}
+			m[i][j] = s.ModInverse(s, field)
+		}
+	}
+	return m, true
+}
+
+// sboxExponent returns the smallest alpha >= 3 such that x -> x^alpha is a
+// permutation of the field.
+func sboxExponent(field *big.Int) (int, error) {
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	var gcd big.Int
+	for alpha := 3; alpha < 256; alpha += 2 {
+		if gcd.GCD(nil, nil, big.NewInt(int64(alpha)), pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return alpha, nil
+		}
+	}
+	return 0, fmt.Errorf("no suitable S-box exponent for field %s", field.Text(16))
+}
+
+// roundNumbers returns the number of full and partial rounds for 128 bits of
+// security, following calc_round_numbers.py of the reference implementation.
+// The number of partial rounds is then rounded up to a multiple of t, which
+// gives the instances of the test vectors of the reference implementation.
+func roundNumbers(field *big.Int, t, alpha int) (rf, rp int) {
+	const m = 128.0
+	fp, _ := new(big.Float).SetInt(field).Float64()
+	log2p := math.Log2(fp)
+	n := float64(field.BitLen())
+	a := float64(alpha)
+	ft := float64(t)
+	logA := func(x float64) float64 { return math.Log(x) / math.Log(a) }
+
+	secure := func(rf, rp int) bool {
+		frp := float64(rp)
+		rf1 := 10.0
+		if m <= math.Floor(log2p-(a-1)/2)*(ft+1) {
+			rf1 = 6
+		}
+		rf2 := 1 + math.Ceil(logA(2)*math.Min(m, n)) + math.Ceil(logA(ft)) - frp
+		rf3 := logA(2)*math.Min(m, log2p) - frp
+		rf4 := ft - 1 + logA(2)*math.Min(m/(ft+1), log2p/2) - frp
+		rf5 := (ft - 2 + m/(2*math.Log2(a)) - frp) / (ft - 1)
+		max := math.Max(math.Max(math.Ceil(rf1), math.Ceil(rf2)), math.Max(math.Max(math.Ceil(rf3), math.Ceil(rf4)), math.Ceil(rf5)))
+		return float64(rf) >= max
+	}
+
+	minCost := math.MaxInt
+	for rpT := 1; rpT < 500; rpT++ {
+		for rfT := 4; rfT < 100; rfT += 2 {
+			if !secure(rfT, rpT) {
+				continue
+			}
+			// security margin
+			rfM, rpM := rfT+2, int(math.Ceil(float64(rpT)*1.075))
+			cost := t*rfM + rpM
+			if cost < minCost || (cost == minCost && rfM < rf) {
+				rf, rp, minCost = rfM, rpM, cost
+			}
+		}
+	}
+	// as for the instances of the reference implementation and of circomlib,
+	// the number of partial rounds is rounded up to a multiple of t.
+	rp = (rp + t - 1) / t * t
+	return rf, rp
+}
+
+// registeredCurves are the curves for which the default parameters of width
+// [DefaultWidth] are registered in the [params] registry.
+var registeredCurves = []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761, ecc.BLS24_315, ecc.BLS24_317, ecc.BW6_633}
+
+func init() {
+	for _, id := range registeredCurves {
+		id := id
+		name := fmt.Sprintf("poseidon/%s/t=%d", id, DefaultWidth)
+		seed := fmt.Sprintf("grain(field=%s, t=%d)", id, DefaultWidth)
+		derivation := "round constants (one row of t elements per round) then the t×t Cauchy MDS matrix in row-major order, sampled from the Grain LFSR of the reference implementation initialized with the bit size of the scalar field, t and the number of rounds of GetDefaultParameters"
+		params.Register(name, 1, seed, derivation, func() ([]*big.Int, error) {
+			p, err := GetDefaultParameters(id.ScalarField(), DefaultWidth)
+			if err != nil {
+				return nil, err
+			}
+			var res []*big.Int
+			for _, rc := range p.RoundConstants {
+				res = append(res, rc...)
+			}
+			for _, row := range p.MDS {
+				res = append(res, row...)
+			}
+			return res, nil
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package poseidon provides a ZKP-circuit function to compute a Poseidon hash.
+//
+// The permutation is described in [Poseidon: A New Hash Function for
+// Zero-Knowledge Proof Systems]. The round constants and the MDS matrices are
+// derived per field with the Grain LFSR of the reference implementation (see
+// [GetDefaultParameters]). Over BN254, the digests are compatible with
+// circomlib: the hash of two elements computed by [NewNativeHasher] is the
+// value of poseidon([a, b]) in circom.
+//
+// [Poseidon: A New Hash Function for Zero-Knowledge Proof Systems]: https://eprint.iacr.org/2019/458
+package poseidon
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Poseidon computes the Poseidon hash of field elements in-circuit. It
+// implements [github.com/consensys/gnark/std/hash.FieldHasher].
+type Poseidon struct {
+	api    frontend.API
+	params *Parameters
+	data   []frontend.Variable
+}
+
+// NewPoseidon returns a Poseidon instance with the default parameters of width
+// [DefaultWidth] over the native field, than can be used in a gnark circuit.
+func NewPoseidon(api frontend.API) (Poseidon, error) {
+	params, err := GetDefaultParameters(api.Compiler().Field(), DefaultWidth)
+	if err != nil {
+		return Poseidon{}, err
+	}
+	return NewPoseidonWithParameters(api, params), nil
+}
+
+// NewPoseidonWithParameters returns a Poseidon instance using the given
+// parameters. The parameters must be defined over the native field.
+func NewPoseidonWithParameters(api frontend.API, params *Parameters) Poseidon {
+	return Poseidon{api: api, params: params}
+}
+
+// Write adds more data to the running hash.
+func (h *Poseidon) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Poseidon) Reset() {
+	h.data = nil
+}
+
+// Sum returns the digest of the data written since the last reset. See
+// [Parameters.HashElements] for the absorption of the inputs.
+func (h *Poseidon) Sum() frontend.Variable {
+	state := make([]frontend.Variable, h.params.T)
+	for i := range state {
+		state[i] = 0
+	}
+	rate := h.params.T - 1
+	for i := 0; i == 0 || i < len(h.data); i += rate {
+		for j := 0; j < rate && i+j < len(h.data); j++ {
+			state[j+1] = h.api.Add(state[j+1], h.data[i+j])
+		}
+		state = Permutation(h.api, h.params, state)
+	}
+	return state[0]
+}
+
+// Permutation returns the Poseidon permutation of state. The length of state
+// must be params.T.
+func Permutation(api frontend.API, params *Parameters, state []frontend.Variable) []frontend.Variable {
+	if len(state) != params.T {
+		panic("state length does not match the permutation width")
+	}
+	res := make([]frontend.Variable, params.T)
+	copy(res, state)
+	for r := 0; r < params.RF+params.RP; r++ {
+		for i := range res {
+			res[i] = api.Add(res[i], params.RoundConstants[r][i])
+		}
+		if params.isFullRound(r) {
+			for i := range res {
+				res[i] = sbox(api, res[i], params.Alpha)
+			}
+		} else {
+			res[0] = sbox(api, res[0], params.Alpha)
+		}
+		res = mulMDS(api, params, res)
+	}
+	return res
+}
+
+// sbox returns x^alpha.
+func sbox(api frontend.API, x frontend.Variable, alpha int) frontend.Variable {
+	res := x
+	for i := bits.Len(uint(alpha)) - 2; i >= 0; i-- {
+		res = api.Mul(res, res)
+		if (alpha>>i)&1 == 1 {
+			res = api.Mul(res, x)
+		}
+	}
+	return res
+}
+
+func mulMDS(api frontend.API, params *Parameters, state []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(state))
+	for i := range res {
+		terms := make([]frontend.Variable, len(state))
+		for j := range state {
+			terms[j] = api.Mul(params.MDS[i][j], state[j])
+		}
+		res[i] = api.Add(terms[0], terms[1], terms[2:]...)
+	}
+	return res
+}
@@ -0,0 +1,148 @@
+package poseidon
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestCircomlibVectors(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	field := ecc.BN254.ScalarField()
+	vectors := []struct {
+		inputs   []int64
+		expected string
+	}{
+		{[]int64{1}, "29176100eaa962bdc1fe6c654d6a3c130e96a4d1168b33848b897dc502820133"},
+		{[]int64{1, 2}, "115cc0f5e7d690413df64c6b9662e9cf2a3617f2743245519e19607a4417189a"},
+	}
+	for _, v := range vectors {
+		params, err := GetDefaultParameters(field, len(v.inputs)+1)
+		assert.NoError(err)
+		inputs := make([]*big.Int, len(v.inputs))
+		for i := range inputs {
+			inputs[i] = big.NewInt(v.inputs[i])
+		}
+		expected, _ := new(big.Int).SetString(v.expected, 16)
+		assert.Equal(expected, params.HashElements(inputs...))
+	}
+}
+
+func TestReferenceVectors(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// test vectors of the reference implementation (hadeshash), permutation
+	// of the state (0, 1, ..., t-1) for the instances poseidonperm_x5_254_t
+	// (BN254) and poseidonperm_x5_255_t (BLS12-381).
+	vectors := []struct {
+		curve    ecc.ID
+		expected []string
+	}{
+		{ecc.BN254, []string{
+			"115cc0f5e7d690413df64c6b9662e9cf2a3617f2743245519e19607a4417189a",
+			"0fca49b798923ab0239de1c9e7a4a9a2210312b6a2f616d18b5a87f9b628ae29",
+			"0e7ae82e40091e63cbd4f16a6d16310b3729d4b6e138fcf54110e2867045a30c",
+		}},
+		{ecc.BN254, []string{
+			"299c867db6c1fdd79dcefa40e4510b9837e60ebb1ce0663dbaa525df65250465",
+			"1148aaef609aa338b27dafd89bb98862d8bb2b429aceac47d86206154ffe053d",
+			"24febb87fed7462e23f6665ff9a0111f4044c38ee1672c1ac6b0637d34f24907",
+			"0eb08f6d809668a981c186beaf6110060707059576406b248e5d9cf6e78b3d3e",
+			"07748bc6877c9b82c8b98666ee9d0626ec7f5be4205f79ee8528ef1c4a376fc7",
+		}},
+		{ecc.BLS12_381, []string{
+			"28ce19420fc246a05553ad1e8c98f5c9d67166be2c18e9e4cb4b4e317dd2a78a",
+			"51f3e312c95343a896cfd8945ea82ba956c1118ce9b9859b6ea56637b4b1ddc4",
+			"3b2b69139b235626a0bfb56c9527ae66a7bf486ad8c11c14d1da0c69bbe0f79a",
+		}},
+		{ecc.BLS12_381, []string{
+			"2a918b9c9f9bd7bb509331c81e297b5707f6fc7393dcee1b13901a0b22202e18",
+			"65ebf8671739eeb11fb217f2d5c5bf4a0c3f210e3f3cd3b08b5db75675d797f7",
+			"2cc176fc26bc70737a696a9dfd1b636ce360ee76926d182390cdb7459cf585ce",
+			"4dc4e29d283afd2a491fe6aef122b9a968e74eff05341f3cc23fda1781dcb566",
+			"03ff622da276830b9451b88b85e6184fd6ae15c8ab3ee25a5667be8592cce3b1",
+		}},
+	}
+	for _, v := range vectors {
+		params, err := GetDefaultParameters(v.curve.ScalarField(), len(v.expected))
+		assert.NoError(err)
+		state := make([]*big.Int, len(v.expected))
+		for i := range state {
+			state[i] = big.NewInt(int64(i))
+		}
+		params.Permute(state)
+		for i := range state {
+			expected, _ := new(big.Int).SetString(v.expected[i], 16)
+			assert.Equal(expected, state[i], "%s t=%d", v.curve, len(v.expected))
+		}
+	}
+}
+
+func TestCircomlibRoundNumbers(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// N_ROUNDS_P of circomlib, indexed by t-2
+	roundsP := []int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+	for i, rp := range roundsP {
+		params, err := GetDefaultParameters(ecc.BN254.ScalarField(), i+2)
+		assert.NoError(err)
+		assert.Equal(8, params.RF)
+		assert.Equal(rp, params.RP, "t=%d", i+2)
+	}
+}
+
+type poseidonCircuit struct {
+	ExpectedResult frontend.Variable `gnark:"data,public"`
+	Data           [5]frontend.Variable
+}
+
+func (circuit *poseidonCircuit) Define(api frontend.API) error {
+	h, err := NewPoseidon(api)
+	if err != nil {
+		return err
+	}
+	h.Write(circuit.Data[:]...)
+	api.AssertIsEqual(h.Sum(), circuit.ExpectedResult)
+	return nil
+}
+
+func TestPoseidonAll(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761, ecc.BLS24_315} {
+		modulus := curve.ScalarField()
+		size := (modulus.BitLen() + 7) / 8
+
+		var data [5]big.Int
+		data[0].Sub(modulus, big.NewInt(1))
+		for i := 1; i < len(data); i++ {
+			data[i].Add(&data[i-1], &data[i-1]).Mod(&data[i], modulus)
+		}
+
+		h, err := NewNativeHasher(modulus)
+		assert.NoError(err)
+		for i := range data {
+			_, err := h.Write(data[i].FillBytes(make([]byte, size)))
+			assert.NoError(err)
+		}
+		expected := h.Sum(nil)
+
+		var validWitness, invalidWitness poseidonCircuit
+		for i := range data {
+			validWitness.Data[i] = data[i].String()
+			invalidWitness.Data[i] = data[i].String()
+		}
+		validWitness.ExpectedResult = expected
+		invalidWitness.Data[0] = 1
+		invalidWitness.ExpectedResult = expected
+
+		assert.CheckCircuit(&poseidonCircuit{},
+			test.WithValidAssignment(&validWitness),
+			test.WithInvalidAssignment(&invalidWitness),
+			test.WithCurves(curve))
+	}
+}
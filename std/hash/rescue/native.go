@@ -0,0 +1,73 @@
+package rescue
+
+import (
+	"math/big"
+)
+
+// Permute applies the Rescue-Prime permutation to state in place. The length
+// of state must be p.M and its elements must be reduced modulo p.Field.
+func (p *Parameters) Permute(state []*big.Int) {
+	if len(state) != p.M {
+		panic("state length does not match the permutation width")
+	}
+	alpha := big.NewInt(int64(p.Alpha))
+	for r := 0; r < p.N; r++ {
+		for i := range state {
+			state[i].Exp(state[i], alpha, p.Field)
+		}
+		p.mulMDS(state)
+		p.addConstants(state, 2*r*p.M)
+		for i := range state {
+			state[i].Exp(state[i], p.AlphaInv, p.Field)
+		}
+		p.mulMDS(state)
+		p.addConstants(state, (2*r+1)*p.M)
+	}
+}
+
+func (p *Parameters) mulMDS(state []*big.Int) {
+	tmp := make([]big.Int, len(state))
+	var t big.Int
+	for i := range tmp {
+		for j := range state {
+			tmp[i].Add(&tmp[i], t.Mul(p.MDS[i][j], state[j]))
+		}
+	}
+	for i := range state {
+		state[i].Mod(&tmp[i], p.Field)
+	}
+}
+
+func (p *Parameters) addConstants(state []*big.Int, offset int) {
+	for i := range state {
+		state[i].Add(state[i], p.RoundConstants[offset+i]).Mod(state[i], p.Field)
+	}
+}
+
+// HashElements returns the Rescue-Prime digest of the given field elements,
+// made of M-Capacity field elements. It is the out-of-circuit counterpart of
+// [Rescue.SumAll].
+//
+// As in the reference implementation, the input is padded with a one and zeros
+// up to a multiple of the rate, absorbed in the first M-Capacity elements of
+// the state and the digest is the first M-Capacity elements of the state.
+func (p *Parameters) HashElements(data ...*big.Int) []*big.Int {
+	rate := p.M - p.Capacity
+	state := make([]*big.Int, p.M)
+	for i := range state {
+		state[i] = new(big.Int)
+	}
+	padded := make([]*big.Int, 0, len(data)+rate)
+	padded = append(padded, data...)
+	padded = append(padded, big.NewInt(1))
+	for len(padded)%rate != 0 {
+		padded = append(padded, new(big.Int))
+	}
+	for i := 0; i < len(padded); i += rate {
+		for j := 0; j < rate; j++ {
+			state[j].Add(state[j], padded[i+j]).Mod(state[j], p.Field)
+		}
+		p.Permute(state)
+	}
+	return state[:rate]
+}
@@ -0,0 +1,248 @@
+package rescue
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/params"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// DefaultWidth is the width (number of field elements in the state) of the
+	// permutation used by [NewRescue].
+	DefaultWidth = 3
+	// DefaultCapacity is the capacity of the sponge used by [NewRescue]. The
+	// rate is DefaultWidth - DefaultCapacity.
+	DefaultCapacity = 1
+	// DefaultSecurityLevel is the security level in bits targeted by the
+	// default parameters.
+	DefaultSecurityLevel = 128
+)
+
+// Parameters define an instance of the Rescue-Prime permutation over a prime
+// field.
+type Parameters struct {
+	Field         *big.Int // modulus of the field
+	M             int      // width of the permutation (number of field elements in the state)
+	Capacity      int      // capacity of the sponge
+	SecurityLevel int      // targeted security level in bits
+	N             int      // number of rounds
+	Alpha         int      // exponent of the S-box x -> x^Alpha
+	AlphaInv      *big.Int // exponent of the inverse S-box, inverse of Alpha modulo Field-1
+
+	// MDS is the M×M matrix applied to the state after each S-box layer.
+	MDS [][]*big.Int
+	// RoundConstants are the 2⋅M⋅N constants, M of them being added to the
+	// state after each MDS layer.
+	RoundConstants []*big.Int
+}
+
+// primitiveElements are the generators of the multiplicative groups of the
+// scalar fields used by gnark-crypto.
+var primitiveElements = map[ecc.ID]int64{
+	ecc.BN254:     5,
+	ecc.BLS12_377: 22,
+	ecc.BLS12_381: 7,
+	ecc.BLS24_315: 7,
+	ecc.BLS24_317: 7,
+	ecc.BW6_633:   13,
+	ecc.BW6_761:   15,
+}
+
+var (
+	parametersCache   = make(map[string]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// GetDefaultParameters returns the parameters of the Rescue-Prime permutation
+// of width [DefaultWidth] and capacity [DefaultCapacity] over the given field,
+// targeting [DefaultSecurityLevel] bits of security.
+//
+// The returned parameters are shared and must not be modified.
+func GetDefaultParameters(field *big.Int) (*Parameters, error) {
+	key := field.Text(16)
+
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[key]; ok {
+		return p, nil
+	}
+	p, err := NewParameters(field, DefaultWidth, DefaultCapacity, DefaultSecurityLevel)
+	if err != nil {
+		return nil, err
+	}
+	parametersCache[key] = p
+	return p, nil
+}
+
+// NewParameters returns the parameters of the Rescue-Prime permutation with the
+// given width, capacity and security level. The S-box exponent, the number of
+// rounds, the MDS matrix and the round constants are derived as in the
+// reference implementation [rescue_prime.sage], the primitive element of the
+// MDS matrix being the generator of the multiplicative group used by
+// gnark-crypto. The field must be the scalar field of one of the curves
+// supported by gnark.
+//
+// [rescue_prime.sage]: https://github.com/KULeuven-COSIC/Marvellous
+func NewParameters(field *big.Int, m, capacity, securityLevel int) (*Parameters, error) {
+	if m < 2 {
+		return nil, fmt.Errorf("invalid width %d, must be at least 2", m)
+	}
+	if capacity < 1 || capacity >= m {
+		return nil, fmt.Errorf("invalid capacity %d, must be in [1, %d]", capacity, m-1)
+	}
+	if securityLevel < 1 {
+		return nil, fmt.Errorf("invalid security level %d", securityLevel)
+	}
+	g, ok := primitiveElements[utils.FieldToCurve(field)]
+	if !ok {
+		return nil, fmt.Errorf("no primitive element known for field %s", field.Text(16))
+	}
+
+	alpha, alphaInv, err := sboxExponents(field)
+	if err != nil {
+		return nil, err
+	}
+	p := &Parameters{
+		Field:         new(big.Int).Set(field),
+		M:             m,
+		Capacity:      capacity,
+		SecurityLevel: securityLevel,
+		N:             nbRounds(m, capacity, securityLevel, alpha),
+		Alpha:         alpha,
+		AlphaInv:      alphaInv,
+	}
+	p.MDS = mdsMatrix(field, m, big.NewInt(g))
+	p.RoundConstants = roundConstants(field, m, capacity, securityLevel, p.N)
+	return p, nil
+}
+
+// sboxExponents returns the smallest alpha >= 3 such that x -> x^alpha is a
+// permutation of the field, and its inverse modulo p-1.
+func sboxExponents(field *big.Int) (int, *big.Int, error) {
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	var gcd big.Int
+	for alpha := 3; alpha < 256; alpha++ {
+		a := big.NewInt(int64(alpha))
+		if gcd.GCD(nil, nil, a, pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			return alpha, new(big.Int).ModInverse(a, pMinusOne), nil
+		}
+	}
+	return 0, nil, fmt.Errorf("no suitable S-box exponent for field %s", field.Text(16))
+}
+
+// nbRounds returns the number of rounds resisting the Gröbner basis attacks
+// with a security margin of 50%, as get_number_of_rounds of the reference
+// implementation.
+func nbRounds(m, capacity, securityLevel, alpha int) int {
+	rate := m - capacity
+	target := new(big.Int).Lsh(big.NewInt(1), uint(securityLevel))
+	l1 := 1
+	for ; l1 < 25; l1++ {
+		dcon := (alpha-1)*m*(l1-1)/2 + 2
+		v := m*(l1-1) + rate
+		b := new(big.Int).Binomial(int64(v+dcon), int64(v))
+		if b.Mul(b, b).Cmp(target) > 0 {
+			break
+		}
+	}
+	if l1 == 25 {
+		l1 = 24
+	}
+	if l1 < 5 {
+		l1 = 5
+	}
+	return (3*l1 + 1) / 2
+}
+
+// mdsMatrix returns the transpose of the right half of the reduced echelon
+// form of the m×2m matrix V[i][j] = g^(i⋅j), where g is a primitive element.
+func mdsMatrix(field *big.Int, m int, g *big.Int) [][]*big.Int {
+	v := make([][]*big.Int, m)
+	for i := range v {
+		v[i] = make([]*big.Int, 2*m)
+		for j := range v[i] {
+			v[i][j] = new(big.Int).Exp(g, big.NewInt(int64(i*j)), field)
+		}
+	}
+
+	// Gauss-Jordan elimination. The left half is a Vandermonde matrix with
+	// distinct nodes, hence invertible.
+	var t big.Int
+	for c := 0; c < m; c++ {
+		pivot := c
+		for v[pivot][c].Sign() == 0 {
+			pivot++
+		}
+		v[c], v[pivot] = v[pivot], v[c]
+		inv := new(big.Int).ModInverse(v[c][c], field)
+		for j := range v[c] {
+			v[c][j].Mul(v[c][j], inv).Mod(v[c][j], field)
+		}
+		for i := range v {
+			if i == c || v[i][c].Sign() == 0 {
+				continue
+			}
+			f := new(big.Int).Set(v[i][c])
+			for j := range v[i] {
+				v[i][j].Sub(v[i][j], t.Mul(f, v[c][j])).Mod(v[i][j], field)
+			}
+		}
+	}
+
+	mds := make([][]*big.Int, m)
+	for i := range mds {
+		mds[i] = make([]*big.Int, m)
+		for j := range mds[i] {
+			mds[i][j] = v[j][m+i]
+		}
+	}
+	return mds
+}
+
+// roundConstants returns the 2⋅m⋅n round constants, obtained from the output
+// of SHAKE256 on the seed string "Rescue-XLIX(p,m,capacity,securityLevel)" split
+// in little-endian integers of one byte more than the field elements.
+func roundConstants(field *big.Int, m, capacity, securityLevel, n int) []*big.Int {
+	bytesPerInt := (field.BitLen()+7)/8 + 1
+	seed := fmt.Sprintf("Rescue-XLIX(%s,%d,%d,%d)", field.String(), m, capacity, securityLevel)
+	shake := sha3.NewShake256()
+	shake.Write([]byte(seed))
+
+	res := make([]*big.Int, 2*m*n)
+	buf := make([]byte, bytesPerInt)
+	for i := range res {
+		_, _ = shake.Read(buf)
+		// little-endian
+		for l, r := 0, len(buf)-1; l < r; l, r = l+1, r-1 {
+			buf[l], buf[r] = buf[r], buf[l]
+		}
+		res[i] = new(big.Int).SetBytes(buf)
+		res[i].Mod(res[i], field)
+	}
+	return res
+}
+
+func init() {
+	for id := range primitiveElements {
+		id := id
+		name := fmt.Sprintf("rescue-prime/%s/m=%d", id, DefaultWidth)
+		seed := fmt.Sprintf("Rescue-XLIX(r,%d,%d,%d) with r the scalar field of %s", DefaultWidth, DefaultCapacity, DefaultSecurityLevel, id)
+		derivation := "round constants from SHAKE256 of the seed, then the m×m MDS matrix in row-major order, derived as in rescue_prime.sage from the Vandermonde matrix of the generator of the multiplicative group used by gnark-crypto"
+		params.Register(name, 1, seed, derivation, func() ([]*big.Int, error) {
+			p, err := GetDefaultParameters(id.ScalarField())
+			if err != nil {
+				return nil, err
+			}
+			res := append([]*big.Int{}, p.RoundConstants...)
+			for _, row := range p.MDS {
+				res = append(res, row...)
+			}
+			return res, nil
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Package rescue provides a ZKP-circuit function to compute a Rescue-Prime
+// hash.
+//
+// The permutation and the sponge are described in [Rescue-Prime: a Standard
+// Specification]. The parameters are derived per field as in the reference
+// implementation (see [NewParameters]). The inverse S-box x -> x^(1/alpha) is
+// computed with a hint and checked with the S-box, so that a round costs about
+// the same as two full rounds of Poseidon.
+//
+// The sponge pads the input with a single 1 and zeros to a multiple of the
+// rate, so that inputs of different lengths have different digests. [Rescue.Sum]
+// returns the first element of the rate; [Rescue.SumAll] returns all of them
+// for the constructions which need a digest larger than a field element.
+//
+// [Rescue-Prime: a Standard Specification]: https://eprint.iacr.org/2020/1143
+package rescue
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{invSboxHint}
+}
+
+// Rescue computes the Rescue-Prime hash of field elements in-circuit. It
+// implements [github.com/consensys/gnark/std/hash.FieldHasher].
+type Rescue struct {
+	api    frontend.API
+	params *Parameters
+	data   []frontend.Variable
+}
+
+// NewRescue returns a Rescue instance with the default parameters over the
+// native field (see [GetDefaultParameters]), than can be used in a gnark
+// circuit.
+func NewRescue(api frontend.API) (Rescue, error) {
+	params, err := GetDefaultParameters(api.Compiler().Field())
+	if err != nil {
+		return Rescue{}, err
+	}
+	return NewRescueWithParameters(api, params), nil
+}
+
+// NewRescueWithParameters returns a Rescue instance using the given
+// parameters. The parameters must be defined over the native field.
+func NewRescueWithParameters(api frontend.API, params *Parameters) Rescue {
+	return Rescue{api: api, params: params}
+}
+
+// Write adds more data to the running hash.
+func (h *Rescue) Write(data ...frontend.Variable) {
+	h.data = append(h.data, data...)
+}
+
+// Reset resets the Hash to its initial state.
+func (h *Rescue) Reset() {
+	h.data = nil
+}
+
+// Sum returns the first element of the digest of the data written since the
+// last reset.
+func (h *Rescue) Sum() frontend.Variable {
+	return h.SumAll()[0]
+}
+
+// SumAll returns the digest of the data written since the last reset, made of
+// M-Capacity field elements. See [Parameters.HashElements] for the padding and
+// the absorption of the inputs.
+func (h *Rescue) SumAll() []frontend.Variable {
+	rate := h.params.M - h.params.Capacity
+	state := make([]frontend.Variable, h.params.M)
+	for i := range state {
+		state[i] = 0
+	}
+	padded := make([]frontend.Variable, 0, len(h.data)+rate)
+	padded = append(padded, h.data...)
+	padded = append(padded, 1)
+	for len(padded)%rate != 0 {
+		padded = append(padded, 0)
+	}
+	for i := 0; i < len(padded); i += rate {
+		for j := 0; j < rate; j++ {
+			state[j] = h.api.Add(state[j], padded[i+j])
+		}
+		state = Permutation(h.api, h.params, state)
+	}
+	return state[:rate]
+}
+
+// Permutation returns the Rescue-Prime permutation of state. The length of
+// state must be params.M.
+func Permutation(api frontend.API, params *Parameters, state []frontend.Variable) []frontend.Variable {
+	if len(state) != params.M {
+		panic("state length does not match the permutation width")
+	}
+	res := make([]frontend.Variable, params.M)
+	copy(res, state)
+	for r := 0; r < params.N; r++ {
+		for i := range res {
+			res[i] = sbox(api, res[i], params.Alpha)
+		}
+		res = mulMDS(api, params, res, 2*r*params.M)
+		for i := range res {
+			res[i] = invSbox(api, res[i], params.Alpha)
+		}
+		res = mulMDS(api, params, res, (2*r+1)*params.M)
+	}
+	return res
+}
+
+// sbox returns x^alpha.
+func sbox(api frontend.API, x frontend.Variable, alpha int) frontend.Variable {
+	res := x
+	for i := bits.Len(uint(alpha)) - 2; i >= 0; i-- {
+		res = api.Mul(res, res)
+		if (alpha>>i)&1 == 1 {
+			res = api.Mul(res, x)
+		}
+	}
+	return res
+}
+
+// invSbox returns x^(1/alpha).
+func invSbox(api frontend.API, x frontend.Variable, alpha int) frontend.Variable {
+	res, err := api.Compiler().NewHint(invSboxHint, 1, x, alpha)
+	if err != nil {
+		panic(err)
+	}
+	api.AssertIsEqual(sbox(api, res[0], alpha), x)
+	return res[0]
+}
+
+// mulMDS returns the product of the MDS matrix with state plus the round
+// constants starting at offset.
+func mulMDS(api frontend.API, params *Parameters, state []frontend.Variable, offset int) []frontend.Variable {
+	res := make([]frontend.Variable, len(state))
+	for i := range res {
+		terms := make([]frontend.Variable, len(state))
+		for j := range state {
+			terms[j] = api.Mul(params.MDS[i][j], state[j])
+		}
+		res[i] = api.Add(params.RoundConstants[offset+i], terms[0], terms[1:]...)
+	}
+	return res
+}
+
+func invSboxHint(field *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 1 {
+		return errors.New("expecting two inputs and one output")
+	}
+	pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+	e := new(big.Int).ModInverse(inputs[1], pMinusOne)
+	if e == nil {
+		return errors.New("S-box exponent is not invertible")
+	}
+	outputs[0].Exp(inputs[0], e, field)
+	return nil
+}
@@ -0,0 +1,155 @@
+package rescue
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestParameters(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for curve, g := range primitiveElements {
+		field := curve.ScalarField()
+		p, err := GetDefaultParameters(field)
+		assert.NoError(err)
+		assert.Equal(2*p.M*p.N, len(p.RoundConstants))
+
+		// alpha⋅alphaInv = 1 mod p-1
+		pMinusOne := new(big.Int).Sub(field, big.NewInt(1))
+		e := new(big.Int).Mul(big.NewInt(int64(p.Alpha)), p.AlphaInv)
+		assert.Equal(int64(1), e.Mod(e, pMinusOne).Int64(), curve.String())
+
+		// [I | MDSᵀ] is row equivalent to V = (g^(i⋅j)): the right half of V is
+		// the left half times MDSᵀ.
+		v := func(i, j int) *big.Int {
+			return new(big.Int).Exp(big.NewInt(g), big.NewInt(int64(i*j)), field)
+		}
+		for i := 0; i < p.M; i++ {
+			for j := 0; j < p.M; j++ {
+				var s, t big.Int
+				for k := 0; k < p.M; k++ {
+					s.Add(&s, t.Mul(v(i, k), p.MDS[j][k]))
+				}
+				assert.Equal(0, s.Mod(&s, field).Cmp(v(i, p.M+j)), "%s: MDS[%d][%d]", curve, j, i)
+			}
+		}
+	}
+
+	_, err := NewParameters(ecc.BN254.ScalarField(), 3, 3, 128)
+	assert.Error(err)
+	_, err = NewParameters(big.NewInt(101), 3, 1, 128)
+	assert.Error(err)
+}
+
+func TestRoundConstants(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// get_round_constants of the reference implementation: the output of
+	// SHAKE256 is split in chunks of bytes_per_int bytes, each interpreted as
+	// sum(256^j⋅chunk[j]).
+	for curve := range primitiveElements {
+		field := curve.ScalarField()
+		p, err := GetDefaultParameters(field)
+		assert.NoError(err)
+		bytesPerInt := (field.BitLen()+7)/8 + 1
+		bytes := make([]byte, bytesPerInt*2*p.M*p.N)
+		sha3.ShakeSum256(bytes, []byte(fmt.Sprintf("Rescue-XLIX(%s,%d,%d,%d)", field, p.M, p.Capacity, p.SecurityLevel)))
+		for i := range p.RoundConstants {
+			chunk := bytes[i*bytesPerInt : (i+1)*bytesPerInt]
+			var c, t big.Int
+			for j := len(chunk) - 1; j >= 0; j-- {
+				c.Lsh(&c, 8).Add(&c, t.SetUint64(uint64(chunk[j])))
+			}
+			assert.Equal(0, c.Mod(&c, field).Cmp(p.RoundConstants[i]), "%s: constant %d", curve, i)
+		}
+	}
+}
+
+func TestKnownAnswers(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// regression values of the sponge over (1, 2)
+	vectors := []struct {
+		curve    ecc.ID
+		expected [DefaultWidth - DefaultCapacity]string
+	}{
+		{ecc.BN254, [2]string{"2c1e496c13685c6475ced933fa87ee0fec53d9d9f99442a0342c488237287659", "16fbd8288c90c86c88836d4a36935d3a760aac69ed6951f660dbd07419f454b"}},
+		{ecc.BLS12_381, [2]string{"5d87015dfb62279a3dd4b271658e028e7d2a971fa2588b12b600d8d2f439aebb", "c4fd77e3245d00d08c0330314630b4ca7dfbd65c6256093b95276fa5334061b"}},
+	}
+	for _, v := range vectors {
+		p, err := GetDefaultParameters(v.curve.ScalarField())
+		assert.NoError(err)
+		res := p.HashElements(big.NewInt(1), big.NewInt(2))
+		for i := range v.expected {
+			assert.Equal(v.expected[i], res[i].Text(16), "%s: output %d", v.curve, i)
+		}
+	}
+}
+
+func TestNbRounds(t *testing.T) {
+	assert := test.NewAssert(t)
+	// values of get_number_of_rounds of the reference implementation
+	assert.Equal(27, nbRounds(2, 1, 128, 3))
+	assert.Equal(8, nbRounds(12, 4, 128, 7))
+}
+
+type rescueCircuit struct {
+	Expected [DefaultWidth - DefaultCapacity]frontend.Variable `gnark:",public"`
+	Data     [5]frontend.Variable
+}
+
+func (c *rescueCircuit) Define(api frontend.API) error {
+	h, err := NewRescue(api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.Data[:]...)
+	res := h.SumAll()
+	for i := range c.Expected {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	h.Reset()
+	h.Write(c.Data[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected[0])
+	return nil
+}
+
+func TestRescueAll(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_381, ecc.BLS12_377, ecc.BW6_761, ecc.BLS24_315} {
+		modulus := curve.ScalarField()
+		p, err := GetDefaultParameters(modulus)
+		assert.NoError(err)
+
+		var data [5]*big.Int
+		data[0] = new(big.Int).Sub(modulus, big.NewInt(1))
+		for i := 1; i < len(data); i++ {
+			data[i] = new(big.Int).Add(data[i-1], data[i-1])
+			data[i].Mod(data[i], modulus)
+		}
+		expected := p.HashElements(data[:]...)
+
+		var validWitness, invalidWitness rescueCircuit
+		for i := range data {
+			validWitness.Data[i] = data[i]
+			invalidWitness.Data[i] = data[i]
+		}
+		for i := range expected {
+			validWitness.Expected[i] = expected[i]
+			invalidWitness.Expected[i] = expected[i]
+		}
+		invalidWitness.Data[0] = 1
+
+		assert.CheckCircuit(&rescueCircuit{},
+			test.WithValidAssignment(&validWitness),
+			test.WithInvalidAssignment(&invalidWitness),
+			test.WithCurves(curve))
+	}
+}
@@ -0,0 +1,179 @@
+// Package ripemd160 implements the RIPEMD-160 hash function.
+//
+// The instance is compatible with golang.org/x/crypto/ripemd160. Together with
+// the SHA-256 gadget in [github.com/consensys/gnark/std/hash/sha2], it allows
+// computing the HASH160 digest of Bitcoin addresses in-circuit.
+//
+// The inputs and the digest are byte arrays ([uints.U8]), the compression
+// function operates on 32-bit little-endian words ([uints.U32]) and the
+// padding is computed at compile time from the number of bytes written.
+package ripemd160
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Size is the size of a RIPEMD-160 digest in bytes.
+const Size = 20
+
+// BlockSize is the block size of RIPEMD-160 in bytes.
+const BlockSize = 64
+
+var _iv = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+
+// round constants of the left and the right lines
+var (
+	_k  = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+	_kp = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+)
+
+// message word selection of the left and the right lines
+var (
+	_r = [80]int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	_rp = [80]int{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+)
+
+// left rotations of the left and the right lines
+var (
+	_s = [80]int{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	_sp = [80]int{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+)
+
+type digest struct {
+	uapi *uints.BinaryField[uints.U32]
+	in   []uints.U8
+}
+
+// New returns a new RIPEMD-160 hasher. The digest is computed over all the
+// bytes written with [hash.BinaryHasher.Write] and has [Size] bytes.
+func New(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest{uapi: uapi}, nil
+}
+
+func (d *digest) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *digest) Size() int { return Size }
+
+func (d *digest) Reset() {
+	d.in = nil
+}
+
+// padded returns the input with the MD4-style padding, with the length encoded
+// in little-endian.
+func (d *digest) padded() []uints.U8 {
+	bytesLen := len(d.in)
+	zeroPadLen := 55 - bytesLen%BlockSize
+	if zeroPadLen < 0 {
+		zeroPadLen += BlockSize
+	}
+	buf := make([]uints.U8, 0, bytesLen+9+zeroPadLen)
+	buf = append(buf, d.in...)
+	buf = append(buf, uints.NewU8(0x80))
+	buf = append(buf, uints.NewU8Array(make([]uint8, zeroPadLen))...)
+	lenbuf := make([]uint8, 8)
+	binary.LittleEndian.PutUint64(lenbuf, uint64(8*bytesLen))
+	buf = append(buf, uints.NewU8Array(lenbuf)...)
+	return buf
+}
+
+func (d *digest) Sum() []uints.U8 {
+	var h [5]uints.U32
+	copy(h[:], uints.NewU32Array(_iv[:]))
+	padded := d.padded()
+	for i := 0; i < len(padded)/BlockSize; i++ {
+		var x [16]uints.U32
+		for j := range x {
+			x[j] = d.uapi.PackLSB(padded[i*BlockSize+4*j : i*BlockSize+4*(j+1)]...)
+		}
+		h = d.compress(h, x)
+	}
+	ret := make([]uints.U8, 0, Size)
+	for i := range h {
+		ret = append(ret, d.uapi.UnpackLSB(h[i])...)
+	}
+	return ret
+}
+
+// compress runs the left and the right lines over the block x and returns the
+// updated chaining value.
+func (d *digest) compress(h [5]uints.U32, x [16]uints.U32) [5]uints.U32 {
+	a, b, c, dd, e := h[0], h[1], h[2], h[3], h[4]
+	ap, bp, cp, dp, ep := h[0], h[1], h[2], h[3], h[4]
+	for j := 0; j < 80; j++ {
+		round := j / 16
+		t := d.uapi.Add(a, d.f(round, b, c, dd), x[_r[j]], uints.NewU32(_k[round]))
+		t = d.uapi.Add(d.uapi.Lrot(t, _s[j]), e)
+		a, e, dd, c, b = e, dd, d.uapi.Lrot(c, 10), b, t
+
+		t = d.uapi.Add(ap, d.f(4-round, bp, cp, dp), x[_rp[j]], uints.NewU32(_kp[round]))
+		t = d.uapi.Add(d.uapi.Lrot(t, _sp[j]), ep)
+		ap, ep, dp, cp, bp = ep, dp, d.uapi.Lrot(cp, 10), bp, t
+	}
+	return [5]uints.U32{
+		d.uapi.Add(h[1], c, dp),
+		d.uapi.Add(h[2], dd, ep),
+		d.uapi.Add(h[3], e, ap),
+		d.uapi.Add(h[4], a, bp),
+		d.uapi.Add(h[0], b, cp),
+	}
+}
+
+// f returns the boolean function of the given round of the left line. The
+// right line uses the functions in reverse order.
+func (d *digest) f(round int, x, y, z uints.U32) uints.U32 {
+	switch round {
+	case 0:
+		// x ^ y ^ z
+		return d.uapi.Xor(x, y, z)
+	case 1:
+		// (x & y) | (^x & z), the operands of the OR have disjoint bits
+		return d.uapi.Xor(d.uapi.And(x, y), d.uapi.And(d.uapi.Not(x), z))
+	case 2:
+		// (x | ^y) ^ z
+		return d.uapi.Xor(d.or(x, d.uapi.Not(y)), z)
+	case 3:
+		// (x & z) | (y & ^z), the operands of the OR have disjoint bits
+		return d.uapi.Xor(d.uapi.And(x, z), d.uapi.And(y, d.uapi.Not(z)))
+	default:
+		// x ^ (y | ^z)
+		return d.uapi.Xor(x, d.or(y, d.uapi.Not(z)))
+	}
+}
+
+func (d *digest) or(x, y uints.U32) uints.U32 {
+	return d.uapi.Xor(x, y, d.uapi.And(x, y))
+}
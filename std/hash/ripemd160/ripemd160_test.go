@@ -0,0 +1,106 @@
+package ripemd160
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // reference implementation
+)
+
+type ripemd160Circuit struct {
+	In       []uints.U8
+	Expected [Size]uints.U8
+}
+
+func (c *ripemd160Circuit) Define(api frontend.API) error {
+	h, err := New(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != Size {
+		return fmt.Errorf("not %d bytes", Size)
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestRIPEMD160(t *testing.T) {
+	assert := test.NewAssert(t)
+	// lengths around the block boundaries, where the padding spans one or two blocks
+	for _, l := range []int{0, 3, 55, 56, 64, 119, 130} {
+		bts := make([]byte, l)
+		for i := range bts {
+			bts[i] = byte(i * 7)
+		}
+		h := ripemd160.New()
+		h.Write(bts)
+		witness := ripemd160Circuit{In: uints.NewU8Array(bts)}
+		copy(witness.Expected[:], uints.NewU8Array(h.Sum(nil)))
+		err := test.IsSolved(&ripemd160Circuit{In: make([]uints.U8, l)}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "length %d", l)
+	}
+}
+
+type hash160Circuit struct {
+	PubKey   [33]uints.U8
+	Expected [Size]uints.U8
+}
+
+func (c *hash160Circuit) Define(api frontend.API) error {
+	sha, err := sha2.New(api)
+	if err != nil {
+		return err
+	}
+	rmd, err := New(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	sha.Write(c.PubKey[:])
+	rmd.Write(sha.Sum())
+	res := rmd.Sum()
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestHASH160(t *testing.T) {
+	// compressed public key of the P2PKH address 1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH
+	pubKey := []byte{
+		0x02, 0x79, 0xbe, 0x66, 0x7e, 0xf9, 0xdc, 0xbb, 0xac, 0x55, 0xa0, 0x62, 0x95, 0xce, 0x87, 0x0b,
+		0x07, 0x02, 0x9b, 0xfc, 0xdb, 0x2d, 0xce, 0x28, 0xd9, 0x59, 0xf2, 0x81, 0x5b, 0x16, 0xf8, 0x17,
+		0x98,
+	}
+	expected := []byte{
+		0x75, 0x1e, 0x76, 0xe8, 0x19, 0x91, 0x96, 0xd4, 0x54, 0x94,
+		0x1c, 0x45, 0xd1, 0xb3, 0xa3, 0x23, 0xf1, 0x43, 0x3b, 0xd6,
+	}
+	sum := sha256.Sum256(pubKey)
+	h := ripemd160.New()
+	h.Write(sum[:])
+	test.NewAssert(t).Equal(expected, h.Sum(nil))
+
+	var witness hash160Circuit
+	copy(witness.PubKey[:], uints.NewU8Array(pubKey))
+	copy(witness.Expected[:], uints.NewU8Array(expected))
+	err := test.IsSolved(&hash160Circuit{}, &witness, ecc.BN254.ScalarField())
+	test.NewAssert(t).NoError(err)
+}
@@ -0,0 +1,130 @@
+// Package sha1 implements the SHA-1 hash function.
+//
+// The instance is compatible with [crypto/sha1]. SHA-1 is not collision
+// resistant and should only be used to verify legacy constructions, such as
+// HMAC-SHA1 in the one-time passwords of [RFC 6238].
+//
+// The inputs and the digest are byte arrays ([uints.U8]), the compression
+// function operates on 32-bit big-endian words ([uints.U32]) and the padding
+// is computed at compile time from the number of bytes written.
+//
+// [RFC 6238]: https://www.rfc-editor.org/rfc/rfc6238
+package sha1
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Size is the size of a SHA-1 digest in bytes.
+const Size = 20
+
+// BlockSize is the block size of SHA-1 in bytes.
+const BlockSize = 64
+
+var _iv = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+
+// round constants, one per group of 20 rounds
+var _k = [4]uint32{0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xca62c1d6}
+
+type digest struct {
+	uapi *uints.BinaryField[uints.U32]
+	in   []uints.U8
+}
+
+// New returns a new SHA-1 hasher. The digest is computed over all the bytes
+// written with [hash.BinaryHasher.Write] and has [Size] bytes.
+func New(api frontend.API) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest{uapi: uapi}, nil
+}
+
+func (d *digest) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *digest) Size() int { return Size }
+
+func (d *digest) Reset() {
+	d.in = nil
+}
+
+// padded returns the input with the MD4-style padding, with the length encoded
+// in big-endian.
+func (d *digest) padded() []uints.U8 {
+	bytesLen := len(d.in)
+	zeroPadLen := 55 - bytesLen%BlockSize
+	if zeroPadLen < 0 {
+		zeroPadLen += BlockSize
+	}
+	buf := make([]uints.U8, 0, bytesLen+9+zeroPadLen)
+	buf = append(buf, d.in...)
+	buf = append(buf, uints.NewU8(0x80))
+	buf = append(buf, uints.NewU8Array(make([]uint8, zeroPadLen))...)
+	lenbuf := make([]uint8, 8)
+	binary.BigEndian.PutUint64(lenbuf, uint64(8*bytesLen))
+	buf = append(buf, uints.NewU8Array(lenbuf)...)
+	return buf
+}
+
+func (d *digest) Sum() []uints.U8 {
+	var h [5]uints.U32
+	copy(h[:], uints.NewU32Array(_iv[:]))
+	padded := d.padded()
+	for i := 0; i < len(padded)/BlockSize; i++ {
+		var x [16]uints.U32
+		for j := range x {
+			x[j] = d.uapi.PackMSB(padded[i*BlockSize+4*j : i*BlockSize+4*(j+1)]...)
+		}
+		h = d.compress(h, x)
+	}
+	ret := make([]uints.U8, 0, Size)
+	for i := range h {
+		ret = append(ret, d.uapi.UnpackMSB(h[i])...)
+	}
+	return ret
+}
+
+// compress runs the 80 rounds over the block x and returns the updated
+// chaining value.
+func (d *digest) compress(h [5]uints.U32, x [16]uints.U32) [5]uints.U32 {
+	var w [80]uints.U32
+	copy(w[:], x[:])
+	for j := 16; j < len(w); j++ {
+		w[j] = d.uapi.Lrot(d.uapi.Xor(w[j-3], w[j-8], w[j-14], w[j-16]), 1)
+	}
+	a, b, c, dd, e := h[0], h[1], h[2], h[3], h[4]
+	for j := range w {
+		round := j / 20
+		t := d.uapi.Add(d.uapi.Lrot(a, 5), d.f(round, b, c, dd), e, w[j], uints.NewU32(_k[round]))
+		a, b, c, dd, e = t, a, d.uapi.Lrot(b, 30), c, dd
+	}
+	return [5]uints.U32{
+		d.uapi.Add(h[0], a),
+		d.uapi.Add(h[1], b),
+		d.uapi.Add(h[2], c),
+		d.uapi.Add(h[3], dd),
+		d.uapi.Add(h[4], e),
+	}
+}
+
+// f returns the boolean function of the given group of 20 rounds.
+func (d *digest) f(round int, x, y, z uints.U32) uints.U32 {
+	switch round {
+	case 0:
+		// (x & y) | (^x & z), the operands of the OR have disjoint bits
+		return d.uapi.Xor(d.uapi.And(x, y), d.uapi.And(d.uapi.Not(x), z))
+	case 2:
+		// majority (x & y) | (x & z) | (y & z) = (x & y) ^ (z & (x ^ y))
+		return d.uapi.Xor(d.uapi.And(x, y), d.uapi.And(z, d.uapi.Xor(x, y)))
+	default:
+		// x ^ y ^ z
+		return d.uapi.Xor(x, y, z)
+	}
+}
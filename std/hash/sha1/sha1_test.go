@@ -0,0 +1,57 @@
+package sha1
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type sha1Circuit struct {
+	In       []uints.U8
+	Expected [Size]uints.U8
+}
+
+func (c *sha1Circuit) Define(api frontend.API) error {
+	h, err := New(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != Size {
+		return fmt.Errorf("not %d bytes", Size)
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA1(t *testing.T) {
+	assert := test.NewAssert(t)
+	// lengths around the block boundaries, where the padding spans one or two blocks
+	for _, l := range []int{0, 3, 55, 56, 64, 119, 130} {
+		bts := make([]byte, l)
+		for i := range bts {
+			bts[i] = byte(i * 7)
+		}
+		dgst := sha1.Sum(bts)
+		witness := sha1Circuit{In: uints.NewU8Array(bts)}
+		copy(witness.Expected[:], uints.NewU8Array(dgst[:]))
+		err := test.IsSolved(&sha1Circuit{In: make([]uints.U8, l)}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "length %d", l)
+
+		witness.Expected[0] = uints.NewU8(dgst[0] ^ 1)
+		err = test.IsSolved(&sha1Circuit{In: make([]uints.U8, l)}, &witness, ecc.BN254.ScalarField())
+		assert.Error(err, "length %d", l)
+	}
+}
@@ -1,7 +1,13 @@
 // Package sha2 implements SHA2 hash computation.
 //
 // This package extends the SHA2 permutation function [sha2] into a full SHA2
-// hash.
+// hash. SHA-256 is compatible with [crypto/sha256], SHA-384 and SHA-512 with
+// [crypto/sha512].
+//
+// The inputs and the digest are byte arrays ([uints.U8]), the compression
+// function operates on 32-bit words ([uints.U32]) for SHA-256 and on 64-bit
+// words ([uints.U64]) for SHA-384 and SHA-512. The padding is computed at
+// compile time from the number of bytes written.
 package sha2
 
 import (
@@ -22,6 +28,8 @@ type digest struct {
 	in   []uints.U8
 }
 
+// New returns a new SHA-256 hasher. The digest is computed over all the bytes
+// written with [hash.BinaryHasher.Write] and has 32 bytes.
 func New(api frontend.API) (hash.BinaryHasher, error) {
 	uapi, err := uints.New[uints.U32](api)
 	if err != nil {
@@ -48,3 +48,22 @@ func TestSHA2(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSHA2Padding(t *testing.T) {
+	// lengths around the block boundaries, where the padding spans one or two blocks
+	for _, l := range []int{0, 1, 55, 56, 63, 64, 65, 119, 120} {
+		bts := make([]byte, l)
+		for i := range bts {
+			bts[i] = byte(i)
+		}
+		dgst := sha256.Sum256(bts)
+		witness := sha2Circuit{
+			In: uints.NewU8Array(bts),
+		}
+		copy(witness.Expected[:], uints.NewU8Array(dgst[:]))
+		err := test.IsSolved(&sha2Circuit{In: make([]uints.U8, len(bts))}, &witness, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatalf("length %d: %v", l, err)
+		}
+	}
+}
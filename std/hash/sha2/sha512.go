@@ -0,0 +1,174 @@
+package sha2
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/permutation/sha2"
+)
+
+var _seed512 = uints.NewU64Array([]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+})
+
+var _seed384 = uints.NewU64Array([]uint64{
+	0xcbbb9d5dc1059ed8, 0x629a292a367cd507, 0x9159015a3070dd17, 0x152fecd8f70e5939,
+	0x67332667ffc00b31, 0x8eb44a8768581511, 0xdb0c2e0d64f98fa7, 0x47b5481dbefa4fa4,
+})
+
+type digest512 struct {
+	api  frontend.API
+	uapi *uints.BinaryField[uints.U64]
+	seed []uints.U64
+	size int
+	in   []uints.U8
+}
+
+// New512 returns a new SHA-512 hasher, compatible with [crypto/sha512.New].
+// The digest has 64 bytes.
+func New512(api frontend.API) (hash.BinaryHasher, error) {
+	return newDigest512(api, _seed512, 64)
+}
+
+// New384 returns a new SHA-384 hasher, compatible with [crypto/sha512.New384].
+// The digest has 48 bytes.
+func New384(api frontend.API) (hash.BinaryHasher, error) {
+	return newDigest512(api, _seed384, 48)
+}
+
+func newDigest512(api frontend.API, seed []uints.U64, size int) (hash.BinaryHasher, error) {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest512{api: api, uapi: uapi, seed: seed, size: size}, nil
+}
+
+func (d *digest512) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *digest512) padded(bytesLen int) []uints.U8 {
+	zeroPadLen := 111 - bytesLen%128
+	if zeroPadLen < 0 {
+		zeroPadLen += 128
+	}
+	buf := make([]uints.U8, 0, bytesLen+17+zeroPadLen)
+	buf = append(buf, d.in...)
+	buf = append(buf, uints.NewU8(0x80))
+	buf = append(buf, uints.NewU8Array(make([]uint8, zeroPadLen))...)
+	// the length is encoded on 128 bits, the upper half is always zero
+	lenbuf := make([]uint8, 16)
+	binary.BigEndian.PutUint64(lenbuf[8:], uint64(8*bytesLen))
+	buf = append(buf, uints.NewU8Array(lenbuf)...)
+	return buf
+}
+
+func (d *digest512) Sum() []uints.U8 {
+	var runningDigest [8]uints.U64
+	var buf [128]uints.U8
+	copy(runningDigest[:], d.seed)
+	padded := d.padded(len(d.in))
+	for i := 0; i < len(padded)/128; i++ {
+		copy(buf[:], padded[i*128:(i+1)*128])
+		runningDigest = sha2.Permute512(d.uapi, runningDigest, buf)
+	}
+	var ret []uints.U8
+	for i := range runningDigest {
+		ret = append(ret, d.uapi.UnpackMSB(runningDigest[i])...)
+	}
+	return ret[:d.size]
+}
+
+// FixedLengthSum returns the digest of the first length bytes written to the
+// hasher. The length is a variable in [0, n], where n is the number of bytes
+// written, and the circuit fails otherwise.
+//
+// All the blocks needed for n bytes are permuted. The padding is placed at
+// the position given by the length, and the digest is taken after the block
+// which contains the end of the padding.
+func (d *digest512) FixedLengthSum(length frontend.Variable) []uints.U8 {
+	n := len(d.in)
+	nbBlocks := (n+16)/128 + 1
+
+	// isEnd[i] = 1 iff i == length. Exactly one of them is set, which bounds
+	// the length.
+	isEnd := make([]frontend.Variable, n+1)
+	for i := range isEnd {
+		isEnd[i] = d.api.IsZero(d.api.Sub(length, i))
+	}
+	d.api.AssertIsEqual(d.api.Add(0, 0, isEnd...), 1)
+
+	// isLast[b] = 1 iff the padding of length bytes ends in block b
+	isLast := make([]frontend.Variable, nbBlocks)
+	for b := range isLast {
+		isLast[b] = 0
+	}
+	for i := range isEnd {
+		b := (i + 16) / 128
+		isLast[b] = d.api.Add(isLast[b], isEnd[i])
+	}
+
+	// big-endian bytes of the length in bits, the upper ones are always zero.
+	// One more bit keeps the decomposition non-empty when nothing is written.
+	lenBits := d.api.ToBinary(d.api.Mul(length, 8), bits.Len(uint(8*n))+1)
+	var lenBytes [16]frontend.Variable
+	for i := range lenBytes {
+		lenBytes[i] = 0
+	}
+	for i := 0; i < len(lenBits); i += 8 {
+		end := i + 8
+		if end > len(lenBits) {
+			end = len(lenBits)
+		}
+		lenBytes[15-i/8] = d.api.FromBinary(lenBits[i:end]...)
+	}
+
+	// the message bytes are kept before the length, the padding byte is put at
+	// the length and the length is encoded at the end of the last block. The
+	// terms are exclusive, so the values are bytes.
+	padded := make([]uints.U8, nbBlocks*128)
+	inMessage := frontend.Variable(1)
+	for i := range padded {
+		v := frontend.Variable(0)
+		if i <= n {
+			inMessage = d.api.Sub(inMessage, isEnd[i])
+			v = d.api.Mul(0x80, isEnd[i])
+			if i < n {
+				v = d.api.MulAcc(v, inMessage, d.in[i].Val)
+			}
+		}
+		if k := i%128 - 112; k >= 0 {
+			v = d.api.MulAcc(v, isLast[i/128], lenBytes[k])
+		}
+		padded[i] = uints.U8{Val: v}
+	}
+
+	var runningDigest [8]uints.U64
+	var buf [128]uints.U8
+	copy(runningDigest[:], d.seed)
+	ret := make([]uints.U8, 64)
+	for i := range ret {
+		ret[i] = uints.NewU8(0)
+	}
+	for b := 0; b < nbBlocks; b++ {
+		copy(buf[:], padded[b*128:(b+1)*128])
+		runningDigest = sha2.Permute512(d.uapi, runningDigest, buf)
+		for i := range runningDigest {
+			for j, v := range d.uapi.UnpackMSB(runningDigest[i]) {
+				ret[8*i+j].Val = d.api.MulAcc(ret[8*i+j].Val, isLast[b], v.Val)
+			}
+		}
+	}
+	return ret[:d.size]
+}
+
+func (d *digest512) Reset() {
+	d.in = nil
+}
+
+func (d *digest512) Size() int { return d.size }
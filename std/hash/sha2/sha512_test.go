@@ -0,0 +1,125 @@
+package sha2
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type sha512Circuit struct {
+	In       []uints.U8
+	Expected []uints.U8
+}
+
+func (c *sha512Circuit) Define(api frontend.API) error {
+	var h hash.BinaryHasher
+	var err error
+	switch len(c.Expected) {
+	case 64:
+		h, err = New512(api)
+	case 48:
+		h, err = New384(api)
+	default:
+		return fmt.Errorf("unexpected digest size %d", len(c.Expected))
+	}
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != len(c.Expected) {
+		return fmt.Errorf("not %d bytes", len(c.Expected))
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA512(t *testing.T) {
+	// lengths around the block boundaries, where the padding spans one or two blocks
+	for _, l := range []int{0, 3, 111, 112, 128, 250} {
+		bts := make([]byte, l)
+		for i := range bts {
+			bts[i] = byte(i)
+		}
+		dgst512 := sha512.Sum512(bts)
+		dgst384 := sha512.Sum384(bts)
+		for _, dgst := range [][]byte{dgst512[:], dgst384[:]} {
+			witness := sha512Circuit{
+				In:       uints.NewU8Array(bts),
+				Expected: uints.NewU8Array(dgst),
+			}
+			circuit := sha512Circuit{In: make([]uints.U8, len(bts)), Expected: make([]uints.U8, len(dgst))}
+			err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+			if err != nil {
+				t.Fatalf("length %d, digest size %d: %v", l, len(dgst), err)
+			}
+		}
+	}
+}
+
+type sha512FixedLengthCircuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected [64]uints.U8
+}
+
+func (c *sha512FixedLengthCircuit) Define(api frontend.API) error {
+	h, err := New512(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.(hash.BinaryFixedLengthHasher).FixedLengthSum(c.Length)
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA512FixedLength(t *testing.T) {
+	// the maximal length and the hashed prefix cross the block boundaries
+	for _, tc := range []struct{ max, length int }{
+		{0, 0}, {5, 0}, {5, 5}, {130, 3}, {130, 111}, {130, 112}, {130, 128}, {250, 240},
+	} {
+		bts := make([]byte, tc.max)
+		for i := range bts {
+			bts[i] = byte(i)
+		}
+		dgst := sha512.Sum512(bts[:tc.length])
+		circuit := sha512FixedLengthCircuit{In: make([]uints.U8, tc.max)}
+		witness := sha512FixedLengthCircuit{In: uints.NewU8Array(bts), Length: tc.length}
+		copy(witness.Expected[:], uints.NewU8Array(dgst[:]))
+		if err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()); err != nil {
+			t.Fatalf("max %d, length %d: %v", tc.max, tc.length, err)
+		}
+	}
+}
+
+func TestSHA512FixedLengthOutOfRange(t *testing.T) {
+	bts := make([]byte, 10)
+	dgst := sha512.Sum512(bts)
+	circuit := sha512FixedLengthCircuit{In: make([]uints.U8, len(bts))}
+	for _, length := range []int{11, -1} {
+		witness := sha512FixedLengthCircuit{In: uints.NewU8Array(bts), Length: length}
+		copy(witness.Expected[:], uints.NewU8Array(dgst[:]))
+		if err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()); err == nil {
+			t.Fatalf("length %d: expected an error", length)
+		}
+	}
+}
@@ -2,4 +2,8 @@
 // Keccak f-[1600] permutation function.
 //
 // Instances correspond golang.org/x/crypto/sha3, except SHA224, which is not x64 compatible.
+//
+// The hash function used by Ethereum (keccak256 in Solidity) is the legacy
+// Keccak-256 returned by [NewLegacyKeccak256]. The underlying permutation is
+// available in [github.com/consensys/gnark/std/permutation/keccakf].
 package sha3
@@ -2,6 +2,7 @@ package sha3
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"hash"
 	"testing"
@@ -88,3 +89,32 @@ func TestSHA3(t *testing.T) {
 		}, name)
 	}
 }
+
+func TestKeccak256Ethereum(t *testing.T) {
+	assert := test.NewAssert(t)
+	// well-known Ethereum digests: empty input and the topic of the ERC-20
+	// Transfer event.
+	vectors := []struct {
+		in       string
+		expected string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"Transfer(address,address,uint256)", "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+	}
+	for _, v := range vectors {
+		expected, err := hex.DecodeString(v.expected)
+		assert.NoError(err)
+		circuit := &sha3Circuit{
+			In:       make([]uints.U8, len(v.in)),
+			Expected: make([]uints.U8, len(expected)),
+			hasher:   "Keccak-256",
+		}
+		witness := &sha3Circuit{
+			In:       uints.NewU8Array([]byte(v.in)),
+			Expected: uints.NewU8Array(expected),
+		}
+		if err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField()); err != nil {
+			t.Fatalf("%q: %s", v.in, err)
+		}
+	}
+}
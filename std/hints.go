@@ -7,13 +7,29 @@ import (
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/native/sw_bls24315"
+	"github.com/consensys/gnark/std/bytes"
+	"github.com/consensys/gnark/std/cipher/aes"
+	"github.com/consensys/gnark/std/encoding/base64"
 	"github.com/consensys/gnark/std/evmprecompiles"
+	"github.com/consensys/gnark/std/hash/anemoi"
+	"github.com/consensys/gnark/std/hash/griffin"
+	"github.com/consensys/gnark/std/hash/rescue"
 	"github.com/consensys/gnark/std/internal/logderivarg"
+	"github.com/consensys/gnark/std/math/bigint"
 	"github.com/consensys/gnark/std/math/bits"
 	"github.com/consensys/gnark/std/math/bitslice"
 	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/fixed"
+	"github.com/consensys/gnark/std/math/float"
+	"github.com/consensys/gnark/std/math/sqrt"
+	"github.com/consensys/gnark/std/memory"
+	"github.com/consensys/gnark/std/otp"
 	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/regex"
 	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/gnark/std/signature/bls"
+	"github.com/consensys/gnark/std/signature/eddsa"
+	"github.com/consensys/gnark/std/sort"
 )
 
 var registerOnce sync.Once
@@ -40,4 +56,20 @@ func registerHints() {
 	solver.RegisterHint(evmprecompiles.GetHints()...)
 	solver.RegisterHint(logderivarg.GetHints()...)
 	solver.RegisterHint(bitslice.GetHints()...)
+	solver.RegisterHint(otp.GetHints()...)
+	solver.RegisterHint(rescue.GetHints()...)
+	solver.RegisterHint(anemoi.GetHints()...)
+	solver.RegisterHint(griffin.GetHints()...)
+	solver.RegisterHint(bls.GetHints()...)
+	solver.RegisterHint(eddsa.GetHints()...)
+	solver.RegisterHint(sqrt.GetHints()...)
+	solver.RegisterHint(bigint.GetHints()...)
+	solver.RegisterHint(aes.GetHints()...)
+	solver.RegisterHint(fixed.GetHints()...)
+	solver.RegisterHint(float.GetHints()...)
+	solver.RegisterHint(sort.GetHints()...)
+	solver.RegisterHint(memory.GetHints()...)
+	solver.RegisterHint(base64.GetHints()...)
+	solver.RegisterHint(bytes.GetHints()...)
+	solver.RegisterHint(regex.GetHints()...)
 }
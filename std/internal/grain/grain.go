@@ -0,0 +1,83 @@
+// Package grain implements the self-shrinking Grain LFSR used by the reference
+// implementations of Poseidon and Poseidon2 to derive the round constants.
+package grain
+
+import "math/big"
+
+// Grain is a Grain LFSR initialized with the parameters of a permutation.
+type Grain struct {
+	state [80]bool
+}
+
+// New returns a Grain LFSR initialized for a permutation over a prime field of
+// fieldSize bits, with width t, rf full rounds and rp partial rounds and the
+// S-box x -> x^alpha.
+func New(fieldSize, t, rf, rp int) *Grain {
+	var g Grain
+	i := 0
+	write := func(v, nbBits int) {
+		for j := nbBits - 1; j >= 0; j-- {
+			g.state[i] = (v>>j)&1 == 1
+			i++
+		}
+	}
+	write(1, 2)          // prime field
+	write(0, 4)          // S-box x^alpha
+	write(fieldSize, 12) // field size
+	write(t, 12)
+	write(rf, 10)
+	write(rp, 10)
+	for ; i < len(g.state); i++ {
+		g.state[i] = true
+	}
+	// discard the first 160 bits
+	for j := 0; j < 160; j++ {
+		g.step()
+	}
+	return &g
+}
+
+func (g *Grain) step() bool {
+	b := g.state[62] != g.state[51]
+	b = b != g.state[38]
+	b = b != g.state[23]
+	b = b != g.state[13]
+	b = b != g.state[0]
+	copy(g.state[:], g.state[1:])
+	g.state[len(g.state)-1] = b
+	return b
+}
+
+// NextBit returns the next output bit of the self-shrinking generator.
+func (g *Grain) NextBit() bool {
+	for {
+		b1, b2 := g.step(), g.step()
+		if b1 {
+			return b2
+		}
+	}
+}
+
+// NextInt returns the integer made of the next nbBits output bits, most
+// significant bit first.
+func (g *Grain) NextInt(nbBits int) *big.Int {
+	res := new(big.Int)
+	for i := 0; i < nbBits; i++ {
+		res.Lsh(res, 1)
+		if g.NextBit() {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res
+}
+
+// NextFieldElement returns the next integer of field.BitLen() bits which is
+// strictly lower than field, discarding the larger ones.
+func (g *Grain) NextFieldElement(field *big.Int) *big.Int {
+	n := field.BitLen()
+	res := g.NextInt(n)
+	for res.Cmp(field) >= 0 {
+		res = g.NextInt(n)
+	}
+	return res
+}
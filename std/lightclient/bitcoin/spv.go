@@ -0,0 +1,181 @@
+// Package bitcoin implements the verification of Bitcoin simplified payment
+// verification (SPV) proofs in-circuit.
+//
+// An SPV proof shows that a transaction is included in a block of a chain of
+// block headers with enough proof of work:
+//   - the hash of each header, the double SHA-256 of its 80 bytes, must be
+//     below the target encoded in the header;
+//   - each header must commit to the hash of the previous one;
+//   - the transaction identifier must be in the Merkle tree of the
+//     transactions of the block, whose root is in the header.
+//
+// The hashes are in the internal byte order of Bitcoin, which is the reverse
+// of the order in which they are usually displayed. The verification of the
+// difficulty adjustments and of the accumulated work is left to the caller,
+// who can read the target of the headers with [SPV.Target].
+package bitcoin
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// HeaderSize is the size in bytes of a block header.
+	HeaderSize = 80
+	// HashSize is the size in bytes of a block hash or a transaction
+	// identifier.
+	HashSize = 32
+)
+
+// BlockHeader is the serialized block header, as in the block messages of the
+// Bitcoin protocol.
+type BlockHeader [HeaderSize]uints.U8
+
+// NewBlockHeader returns the witness assignment of the serialized block
+// header b.
+func NewBlockHeader(b []byte) (BlockHeader, error) {
+	var res BlockHeader
+	if len(b) != HeaderSize {
+		return res, fmt.Errorf("invalid header length %d", len(b))
+	}
+	copy(res[:], uints.NewU8Array(b))
+	return res, nil
+}
+
+// PreviousBlockHash returns the hash of the previous block in the header.
+func (h *BlockHeader) PreviousBlockHash() []uints.U8 { return h[4:36] }
+
+// MerkleRoot returns the root of the Merkle tree of the transactions in the
+// header.
+func (h *BlockHeader) MerkleRoot() []uints.U8 { return h[36:68] }
+
+// SPV verifies SPV proofs in-circuit.
+type SPV struct {
+	api  frontend.API
+	uapi *uints.BinaryField[uints.U32]
+}
+
+// New returns a new SPV proof verifier.
+func New(api frontend.API) (*SPV, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	return &SPV{api: api, uapi: uapi}, nil
+}
+
+// BlockHash returns the hash of the header h.
+func (s *SPV) BlockHash(h *BlockHeader) []uints.U8 {
+	return s.doubleSHA256(h[:])
+}
+
+// TxID returns the identifier of the serialized transaction tx. It returns an
+// error for transactions of 64 bytes, which can't be distinguished from the
+// inner nodes of the Merkle tree.
+func (s *SPV) TxID(tx []uints.U8) ([]uints.U8, error) {
+	if len(tx) == 2*HashSize {
+		return nil, fmt.Errorf("ambiguous transaction of %d bytes", len(tx))
+	}
+	return s.doubleSHA256(tx), nil
+}
+
+// Target returns the bytes of the target encoded in the compact form in the
+// header h, in little-endian. It asserts that the target is positive and
+// fits in 256 bits.
+func (s *SPV) Target(h *BlockHeader) []uints.U8 {
+	// bits = mantissa (3 bytes) ‖ exponent, in little-endian, and the target
+	// is mantissa × 256^(exponent-3)
+	mantissa := h[72:75]
+	exponent := h[75].Val
+	s.api.AssertIsEqual(s.api.ToBinary(mantissa[2].Val, 8)[7], 0)
+	s.api.AssertIsLessOrEqual(3, exponent)
+	s.api.AssertIsLessOrEqual(exponent, HashSize)
+
+	offset := s.api.Sub(exponent, 3)
+	res := make([]uints.U8, HashSize)
+	for k := range res {
+		var v frontend.Variable = 0
+		for j := range mantissa {
+			v = s.api.Add(v, s.api.Mul(mantissa[j].Val, s.api.IsZero(s.api.Sub(k-j, offset))))
+		}
+		res[k] = uints.U8{Val: v}
+	}
+	return res
+}
+
+// AssertProofOfWork asserts that the hash of the header h is lower than or
+// equal to its target.
+func (s *SPV) AssertProofOfWork(h *BlockHeader) {
+	hash := s.BlockHash(h)
+	target := s.Target(h)
+	// compare the 256 bits integers in little-endian by halves of 128 bits,
+	// which fit in the native field
+	half := func(b []uints.U8) frontend.Variable {
+		var v frontend.Variable = 0
+		for i := len(b) - 1; i >= 0; i-- {
+			v = s.api.Add(s.api.Mul(v, 256), b[i].Val)
+		}
+		return v
+	}
+	hi := s.api.Cmp(half(hash[16:]), half(target[16:]))
+	lo := s.api.Cmp(half(hash[:16]), half(target[:16]))
+	// hi < 0 or (hi = 0 and lo ≤ 0)
+	less := s.api.IsZero(s.api.Add(hi, 1))
+	equal := s.api.IsZero(hi)
+	loLE := s.api.Sub(1, s.api.IsZero(s.api.Sub(lo, 1)))
+	s.api.AssertIsEqual(s.api.Add(less, s.api.Mul(equal, loLE)), 1)
+}
+
+// AssertHeaderChain asserts that each header has enough proof of work and
+// that it is the parent of the next one.
+func (s *SPV) AssertHeaderChain(headers []BlockHeader) {
+	for i := range headers {
+		s.AssertProofOfWork(&headers[i])
+		if i > 0 {
+			s.assertHashesEqual(s.BlockHash(&headers[i-1]), headers[i].PreviousBlockHash())
+		}
+	}
+}
+
+// AssertMerkleInclusion asserts that txid is the leaf at the given index of
+// the Merkle tree of the transactions of the block with header h. The branch
+// contains the siblings from the leaf to the root, and its length is the
+// depth of the tree.
+func (s *SPV) AssertMerkleInclusion(txid []uints.U8, branch [][HashSize]uints.U8, index frontend.Variable, h *BlockHeader) {
+	path := s.api.ToBinary(index, len(branch))
+	cur := txid
+	for i := range branch {
+		// the current node is on the right when the bit is set
+		left := make([]uints.U8, HashSize)
+		right := make([]uints.U8, HashSize)
+		for j := 0; j < HashSize; j++ {
+			left[j] = uints.U8{Val: s.api.Select(path[i], branch[i][j].Val, cur[j].Val)}
+			right[j] = uints.U8{Val: s.api.Select(path[i], cur[j].Val, branch[i][j].Val)}
+		}
+		cur = s.doubleSHA256(append(left, right...))
+	}
+	s.assertHashesEqual(cur, h.MerkleRoot())
+}
+
+func (s *SPV) assertHashesEqual(a, b []uints.U8) {
+	for i := range a {
+		s.uapi.ByteAssertEq(a[i], b[i])
+	}
+}
+
+func (s *SPV) doubleSHA256(data []uints.U8) []uints.U8 {
+	res := data
+	for i := 0; i < 2; i++ {
+		h, err := sha2.New(s.api)
+		if err != nil {
+			panic(err)
+		}
+		h.Write(res)
+		res = h.Sum()
+	}
+	return res
+}
@@ -0,0 +1,148 @@
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+func doubleSHA256(b []byte) [HashSize]byte {
+	h := sha256.Sum256(b)
+	return sha256.Sum256(h[:])
+}
+
+// mine returns a header on top of prev with the given Merkle root, mined for
+// the compact target bits.
+func mine(t *testing.T, prev, merkleRoot [HashSize]byte, bits uint32) []byte {
+	target := new(big.Int).Lsh(big.NewInt(int64(bits&0x7fffff)), 8*uint(bits>>24-3))
+	h := make([]byte, HeaderSize)
+	binary.LittleEndian.PutUint32(h[0:], 2)
+	copy(h[4:], prev[:])
+	copy(h[36:], merkleRoot[:])
+	binary.LittleEndian.PutUint32(h[68:], 1700000000)
+	binary.LittleEndian.PutUint32(h[72:], bits)
+	for nonce := uint32(0); ; nonce++ {
+		binary.LittleEndian.PutUint32(h[76:], nonce)
+		hash := doubleSHA256(h)
+		// the hash is a little-endian integer
+		for i, j := 0, len(hash)-1; i < j; i, j = i+1, j-1 {
+			hash[i], hash[j] = hash[j], hash[i]
+		}
+		if new(big.Int).SetBytes(hash[:]).Cmp(target) <= 0 {
+			return h
+		}
+		if nonce == 1<<20 {
+			t.Fatal("target too hard")
+		}
+	}
+}
+
+type headerChainCircuit struct {
+	Headers []BlockHeader
+}
+
+func (c *headerChainCircuit) Define(api frontend.API) error {
+	s, err := New(api)
+	if err != nil {
+		return err
+	}
+	s.AssertHeaderChain(c.Headers)
+	return nil
+}
+
+func TestHeaderChain(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the genesis block of the mainnet, with the real difficulty
+	genesis, err := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c")
+	assert.NoError(err)
+	raw := [][]byte{genesis}
+	for i := 0; i < 2; i++ {
+		var root [HashSize]byte
+		root[0] = byte(i)
+		raw = append(raw, mine(t, doubleSHA256(raw[len(raw)-1]), root, 0x1f00ffff))
+	}
+
+	witness := headerChainCircuit{Headers: make([]BlockHeader, len(raw))}
+	for i := range raw {
+		witness.Headers[i], err = NewBlockHeader(raw[i])
+		assert.NoError(err)
+	}
+	circuit := headerChainCircuit{Headers: make([]BlockHeader, len(raw))}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the hash of the header is above the target
+	witness.Headers[0][76] = uints.NewU8(0)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type inclusionCircuit struct {
+	Tx     []uints.U8
+	Branch [3][HashSize]uints.U8
+	Index  frontend.Variable
+	Header BlockHeader
+}
+
+func (c *inclusionCircuit) Define(api frontend.API) error {
+	s, err := New(api)
+	if err != nil {
+		return err
+	}
+	txid, err := s.TxID(c.Tx)
+	if err != nil {
+		return err
+	}
+	s.AssertMerkleInclusion(txid, c.Branch[:], c.Index, &c.Header)
+	return nil
+}
+
+func TestMerkleInclusion(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// 5 transactions, the last node of the odd levels is duplicated
+	txs := make([][]byte, 5)
+	leaves := make([][HashSize]byte, len(txs))
+	for i := range txs {
+		txs[i] = make([]byte, 100+i)
+		txs[i][0] = byte(i)
+		leaves[i] = doubleSHA256(txs[i])
+	}
+	const index = 4
+	var branch [][HashSize]byte
+	level, idx := leaves, index
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		branch = append(branch, level[idx^1])
+		next := make([][HashSize]byte, len(level)/2)
+		for i := range next {
+			next[i] = doubleSHA256(append(level[2*i][:], level[2*i+1][:]...))
+		}
+		level, idx = next, idx/2
+	}
+	header, err := NewBlockHeader(mine(t, [HashSize]byte{}, level[0], 0x207fffff))
+	assert.NoError(err)
+
+	witness := inclusionCircuit{Tx: uints.NewU8Array(txs[index]), Index: index, Header: header}
+	for i := range branch {
+		copy(witness.Branch[i][:], uints.NewU8Array(branch[i][:]))
+	}
+	circuit := inclusionCircuit{Tx: make([]uints.U8, len(txs[index]))}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// wrong position
+	witness.Index = 3
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
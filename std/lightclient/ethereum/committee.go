@@ -0,0 +1,85 @@
+package ethereum
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/bls"
+)
+
+// DomainSyncCommittee is the domain type of the signatures of the sync
+// committee.
+var DomainSyncCommittee = [4]byte{0x07, 0x00, 0x00, 0x00}
+
+// SyncCommittee is the in-circuit representation of a sync committee. The
+// public keys are compressed points of G1. The number of public keys is 512
+// on the Ethereum networks.
+type SyncCommittee struct {
+	PublicKeys         [][PublicKeySize]uints.U8
+	AggregatePublicKey [PublicKeySize]uints.U8
+}
+
+// SyncAggregate is the aggregate signature of the participants of the sync
+// committee. Participation[i] is 1 if the i-th member of the committee
+// signed and 0 otherwise.
+type SyncAggregate struct {
+	Participation []frontend.Variable
+	Signature     [SignatureSize]uints.U8
+}
+
+// ComputeDomain returns the signature domain of the given domain type, fork
+// version and genesis validators root, as compute_domain of the consensus
+// specifications. It is computed out of the circuit.
+func ComputeDomain(domainType [4]byte, forkVersion [4]byte, genesisValidatorsRoot [RootSize]byte) [RootSize]byte {
+	var chunk [RootSize]byte
+	copy(chunk[:], forkVersion[:])
+	forkDataRoot := sha256.Sum256(append(chunk[:], genesisValidatorsRoot[:]...))
+	var domain [RootSize]byte
+	copy(domain[:], domainType[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return domain
+}
+
+// SyncCommitteeRoot returns the hash tree root of the sync committee c. The
+// number of public keys must be a power of two.
+func (lc *LightClient) SyncCommitteeRoot(c *SyncCommittee) []uints.U8 {
+	pks := make([][]uints.U8, len(c.PublicKeys))
+	for i := range c.PublicKeys {
+		pks[i] = lc.publicKeyRoot(&c.PublicKeys[i])
+	}
+	return lc.hash(lc.merkleize(pks), lc.publicKeyRoot(&c.AggregatePublicKey))
+}
+
+func (lc *LightClient) publicKeyRoot(pk *[PublicKeySize]uints.U8) []uints.U8 {
+	return lc.hash(pk[:], uints.NewU8Array(make([]byte, 2*RootSize-PublicKeySize)))
+}
+
+// AssertSyncAggregate asserts that the aggregate signature is a valid
+// signature of the signing root by the participants of the committee, and
+// that at least two thirds of the committee participated. The public keys of
+// the committee are assumed to be valid, which is the case for the committees
+// of the beacon state.
+func (lc *LightClient) AssertSyncAggregate(c *SyncCommittee, aggregate *SyncAggregate, signingRoot []uints.U8) error {
+	if len(aggregate.Participation) != len(c.PublicKeys) {
+		return fmt.Errorf("participation of %d members in a committee of %d", len(aggregate.Participation), len(c.PublicKeys))
+	}
+	// 3 × participants ≥ 2 × size
+	var count frontend.Variable = 0
+	for _, b := range aggregate.Participation {
+		count = lc.api.Add(count, b)
+	}
+	lc.api.AssertIsLessOrEqual(2*len(c.PublicKeys), lc.api.Mul(count, 3))
+
+	pks := make([]*bls.PublicKey, len(c.PublicKeys))
+	for i := range c.PublicKeys {
+		pks[i] = lc.bls.PublicKeyFromBytes(&c.PublicKeys[i])
+	}
+	pk := lc.bls.Aggregate(pks, aggregate.Participation)
+	sig, err := lc.bls.SignatureFromBytes(&aggregate.Signature)
+	if err != nil {
+		return fmt.Errorf("decompress signature: %w", err)
+	}
+	return lc.bls.AssertIsValid(pk, signingRoot, sig, []byte(DST))
+}
@@ -0,0 +1,180 @@
+// Package ethereum implements the core components of a zk light client of the
+// Ethereum beacon chain.
+//
+// A light client follows the chain by verifying the aggregate BLS signatures
+// of the sync committee, a set of 512 validators which is renewed every 256
+// epochs, over the beacon block headers. The components of this package
+// allow to verify in-circuit:
+//   - the SSZ hash tree roots of the beacon block headers and of the sync
+//     committees, and the Merkle branches of the beacon state, for example to
+//     prove the next sync committee or the finalized header;
+//   - the chain of headers by their parent roots;
+//   - the sync aggregate signing a header, including the hash to G2 and the
+//     pairing check over the emulated BLS12-381.
+//
+// The generalized indices of the beacon state are the ones of the forks from
+// Altair to Deneb.
+package ethereum
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/bls"
+)
+
+const (
+	// RootSize is the size in bytes of a hash tree root.
+	RootSize = 32
+	// PublicKeySize is the size in bytes of a compressed public key.
+	PublicKeySize = bls.PublicKeySize
+	// SignatureSize is the size in bytes of a compressed signature.
+	SignatureSize = bls.SignatureSize
+
+	// DST is the domain separation tag of the BLS signatures of Ethereum
+	// (proof of possession scheme).
+	DST = bls.DSTProofOfPossession
+)
+
+// Indices and depths of the Merkle branches of the beacon state to the
+// sync committees and to the finalized checkpoint root, to be used with
+// [LightClient.AssertMerkleBranch]. The index is the generalized index without
+// its leading bit.
+const (
+	CurrentSyncCommitteeIndex = 22
+	CurrentSyncCommitteeDepth = 5
+	NextSyncCommitteeIndex    = 23
+	NextSyncCommitteeDepth    = 5
+	FinalizedRootIndex        = 41
+	FinalizedRootDepth        = 6
+)
+
+// BeaconBlockHeader is the in-circuit representation of a beacon block header.
+type BeaconBlockHeader struct {
+	Slot          frontend.Variable
+	ProposerIndex frontend.Variable
+	ParentRoot    [RootSize]uints.U8
+	StateRoot     [RootSize]uints.U8
+	BodyRoot      [RootSize]uints.U8
+}
+
+// LightClient verifies the components of a light client update in-circuit.
+type LightClient struct {
+	api frontend.API
+	u64 *uints.BinaryField[uints.U64]
+	bls *bls.Verifier
+}
+
+// New returns a new light client verifier.
+func New(api frontend.API) (*LightClient, error) {
+	u64, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	verifier, err := bls.NewVerifier(api)
+	if err != nil {
+		return nil, fmt.Errorf("new bls verifier: %w", err)
+	}
+	return &LightClient{api: api, u64: u64, bls: verifier}, nil
+}
+
+// HeaderRoot returns the hash tree root of the header h.
+func (lc *LightClient) HeaderRoot(h *BeaconBlockHeader) []uints.U8 {
+	return lc.merkleize([][]uints.U8{
+		lc.uint64Chunk(h.Slot),
+		lc.uint64Chunk(h.ProposerIndex),
+		h.ParentRoot[:],
+		h.StateRoot[:],
+		h.BodyRoot[:],
+	})
+}
+
+// SigningRoot returns the root signed by the sync committee for the header h
+// in the given domain, see [ComputeDomain].
+func (lc *LightClient) SigningRoot(h *BeaconBlockHeader, domain [RootSize]byte) []uints.U8 {
+	return lc.hash(lc.HeaderRoot(h), uints.NewU8Array(domain[:]))
+}
+
+// AssertHeaderChain asserts that each header is the parent of the next one,
+// that is that its hash tree root is the parent root of the next header, and
+// that the slots are increasing.
+func (lc *LightClient) AssertHeaderChain(headers []BeaconBlockHeader) {
+	for i := 1; i < len(headers); i++ {
+		lc.assertRootsEqual(lc.HeaderRoot(&headers[i-1]), headers[i].ParentRoot[:])
+		lc.api.AssertIsLessOrEqual(lc.api.Add(headers[i-1].Slot, 1), headers[i].Slot)
+	}
+}
+
+// AssertMerkleBranch asserts that leaf is at the given index of the Merkle
+// tree of depth len(branch) with the given root, as is_valid_merkle_branch of
+// the consensus specifications.
+func (lc *LightClient) AssertMerkleBranch(leaf []uints.U8, branch [][RootSize]uints.U8, index uint64, root []uints.U8) {
+	value := leaf
+	for i := range branch {
+		if (index>>i)&1 == 1 {
+			value = lc.hash(branch[i][:], value)
+		} else {
+			value = lc.hash(value, branch[i][:])
+		}
+	}
+	lc.assertRootsEqual(value, root)
+}
+
+func (lc *LightClient) assertRootsEqual(a, b []uints.U8) {
+	for i := range a {
+		lc.u64.ByteAssertEq(a[i], b[i])
+	}
+}
+
+func (lc *LightClient) hash(data ...[]uints.U8) []uints.U8 {
+	h, err := sha2.New(lc.api)
+	if err != nil {
+		panic(err)
+	}
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum()
+}
+
+// uint64Chunk returns the SSZ chunk of the 64 bits integer v, in little-endian
+// and padded with zeros.
+func (lc *LightClient) uint64Chunk(v frontend.Variable) []uints.U8 {
+	b := lc.u64.ValueOf(v)
+	lc.api.AssertIsEqual(lc.u64.ToValue(b), v)
+	res := make([]uints.U8, RootSize)
+	copy(res, lc.u64.UnpackLSB(b))
+	for i := 8; i < RootSize; i++ {
+		res[i] = uints.NewU8(0)
+	}
+	return res
+}
+
+// merkleize returns the root of the Merkle tree of the chunks, padded with
+// zero chunks to the next power of two. There must be at least one chunk.
+func (lc *LightClient) merkleize(chunks [][]uints.U8) []uints.U8 {
+	layer := chunks
+	for depth := 0; len(layer) > 1; depth++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, uints.NewU8Array(zeroHashes[depth][:]))
+		}
+		next := make([][]uints.U8, len(layer)/2)
+		for i := range next {
+			next[i] = lc.hash(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	return layer[0]
+}
+
+// zeroHashes[i] is the root of the Merkle tree of depth i with zero leaves.
+var zeroHashes [64][RootSize]byte
+
+func init() {
+	for i := 1; i < len(zeroHashes); i++ {
+		zeroHashes[i] = sha256.Sum256(append(zeroHashes[i-1][:], zeroHashes[i-1][:]...))
+	}
+}
@@ -0,0 +1,212 @@
+package ethereum
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type header struct {
+	slot, proposerIndex             uint64
+	parentRoot, stateRoot, bodyRoot [RootSize]byte
+}
+
+func (h *header) root() [RootSize]byte {
+	var slot, proposerIndex [RootSize]byte
+	binary.LittleEndian.PutUint64(slot[:], h.slot)
+	binary.LittleEndian.PutUint64(proposerIndex[:], h.proposerIndex)
+	return merkleize([][RootSize]byte{slot, proposerIndex, h.parentRoot, h.stateRoot, h.bodyRoot})
+}
+
+func (h *header) assignment() BeaconBlockHeader {
+	res := BeaconBlockHeader{Slot: h.slot, ProposerIndex: h.proposerIndex}
+	copy(res.ParentRoot[:], uints.NewU8Array(h.parentRoot[:]))
+	copy(res.StateRoot[:], uints.NewU8Array(h.stateRoot[:]))
+	copy(res.BodyRoot[:], uints.NewU8Array(h.bodyRoot[:]))
+	return res
+}
+
+func merkleize(chunks [][RootSize]byte) [RootSize]byte {
+	for depth := 0; len(chunks) > 1; depth++ {
+		if len(chunks)%2 == 1 {
+			chunks = append(chunks, zeroHashes[depth])
+		}
+		next := make([][RootSize]byte, len(chunks)/2)
+		for i := range next {
+			next[i] = sha256.Sum256(append(chunks[2*i][:], chunks[2*i+1][:]...))
+		}
+		chunks = next
+	}
+	return chunks[0]
+}
+
+// branch returns the root of the tree with the given leaves and the Merkle
+// branch of the leaf at index.
+func branch(leaves [][RootSize]byte, index int) ([RootSize]byte, [][RootSize]byte) {
+	var res [][RootSize]byte
+	for len(leaves) > 1 {
+		res = append(res, leaves[index^1])
+		next := make([][RootSize]byte, len(leaves)/2)
+		for i := range next {
+			next[i] = sha256.Sum256(append(leaves[2*i][:], leaves[2*i+1][:]...))
+		}
+		leaves, index = next, index/2
+	}
+	return leaves[0], res
+}
+
+func toU8(b [RootSize]byte) [RootSize]uints.U8 {
+	var res [RootSize]uints.U8
+	copy(res[:], uints.NewU8Array(b[:]))
+	return res
+}
+
+type headerChainCircuit struct {
+	Headers       []BeaconBlockHeader
+	FinalizedRoot [RootSize]uints.U8
+	Branch        [FinalizedRootDepth][RootSize]uints.U8
+}
+
+func (c *headerChainCircuit) Define(api frontend.API) error {
+	lc, err := New(api)
+	if err != nil {
+		return err
+	}
+	lc.AssertHeaderChain(c.Headers)
+	last := &c.Headers[len(c.Headers)-1]
+	lc.AssertMerkleBranch(c.FinalizedRoot[:], c.Branch[:], FinalizedRootIndex, last.StateRoot[:])
+	return nil
+}
+
+func TestHeaderChain(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// the finalized root in the beacon state of the last header
+	leaves := make([][RootSize]byte, 1<<FinalizedRootDepth)
+	for i := range leaves {
+		leaves[i][0] = byte(i)
+	}
+	stateRoot, proof := branch(leaves, FinalizedRootIndex)
+
+	headers := []header{{slot: 100, proposerIndex: 7}, {slot: 101, proposerIndex: 12}, {slot: 103, proposerIndex: 3, stateRoot: stateRoot}}
+	headers[0].bodyRoot[0] = 1
+	for i := 1; i < len(headers); i++ {
+		headers[i].parentRoot = headers[i-1].root()
+	}
+	witness := headerChainCircuit{
+		Headers:       make([]BeaconBlockHeader, len(headers)),
+		FinalizedRoot: toU8(leaves[FinalizedRootIndex]),
+	}
+	for i := range headers {
+		witness.Headers[i] = headers[i].assignment()
+	}
+	for i := range proof {
+		witness.Branch[i] = toU8(proof[i])
+	}
+	circuit := headerChainCircuit{Headers: make([]BeaconBlockHeader, len(headers))}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the chain is broken
+	witness.Headers[1].ProposerIndex = 13
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// wrong finalized root
+	witness.Headers[1].ProposerIndex = 12
+	witness.FinalizedRoot = toU8(leaves[0])
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type syncAggregateCircuit struct {
+	Header        BeaconBlockHeader
+	Committee     SyncCommittee
+	CommitteeRoot [RootSize]uints.U8
+	Aggregate     SyncAggregate
+	domain        [RootSize]byte
+}
+
+func (c *syncAggregateCircuit) Define(api frontend.API) error {
+	lc, err := New(api)
+	if err != nil {
+		return err
+	}
+	lc.assertRootsEqual(lc.SyncCommitteeRoot(&c.Committee), c.CommitteeRoot[:])
+	return lc.AssertSyncAggregate(&c.Committee, &c.Aggregate, lc.SigningRoot(&c.Header, c.domain))
+}
+
+func TestSyncAggregate(t *testing.T) {
+	assert := test.NewAssert(t)
+	const size = 4
+	participation := []uint64{1, 0, 1, 1}
+
+	var gvr [RootSize]byte
+	gvr[0] = 0x4b
+	domain := ComputeDomain(DomainSyncCommittee, [4]byte{0x04, 0, 0, 0}, gvr)
+	h := header{slot: 1 << 40, proposerIndex: 42}
+	h.stateRoot[0] = 1
+	root := h.root()
+	signingRoot := sha256.Sum256(append(root[:], domain[:]...))
+	hm, err := bls12381.HashToG2(signingRoot[:], []byte(DST))
+	assert.NoError(err)
+
+	_, _, g1, _ := bls12381.Generators()
+	var aggSk, sk big.Int
+	var aggPk bls12381.G1Affine
+	committee := SyncCommittee{PublicKeys: make([][PublicKeySize]uints.U8, size)}
+	pkLeaves := make([][RootSize]byte, size)
+	pkRoot := func(b [PublicKeySize]byte) [RootSize]byte {
+		return sha256.Sum256(append(b[:], make([]byte, 2*RootSize-PublicKeySize)...))
+	}
+	for i := 0; i < size; i++ {
+		sk.SetInt64(int64(1000 + 17*i))
+		var pk bls12381.G1Affine
+		pk.ScalarMultiplication(&g1, &sk)
+		aggPk.Add(&aggPk, &pk)
+		b := pk.Bytes()
+		copy(committee.PublicKeys[i][:], uints.NewU8Array(b[:]))
+		pkLeaves[i] = pkRoot(b)
+		if participation[i] == 1 {
+			aggSk.Add(&aggSk, &sk)
+		}
+	}
+	b := aggPk.Bytes()
+	copy(committee.AggregatePublicKey[:], uints.NewU8Array(b[:]))
+	pks := merkleize(pkLeaves)
+	aggPkRoot := pkRoot(b)
+	committeeRoot := sha256.Sum256(append(pks[:], aggPkRoot[:]...))
+
+	var sig bls12381.G2Affine
+	sig.ScalarMultiplication(&hm, &aggSk)
+	sigBytes := sig.Bytes()
+	witness := syncAggregateCircuit{
+		Header:        h.assignment(),
+		Committee:     committee,
+		CommitteeRoot: toU8(committeeRoot),
+		Aggregate:     SyncAggregate{Participation: make([]frontend.Variable, size)},
+	}
+	copy(witness.Aggregate.Signature[:], uints.NewU8Array(sigBytes[:]))
+	for i := range participation {
+		witness.Aggregate.Participation[i] = participation[i]
+	}
+	circuit := syncAggregateCircuit{
+		Committee: SyncCommittee{PublicKeys: make([][PublicKeySize]uints.U8, size)},
+		Aggregate: SyncAggregate{Participation: make([]frontend.Variable, size)},
+		domain:    domain,
+	}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// not enough participants
+	witness.Aggregate.Participation[0] = 0
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,343 @@
+// Package bigint implements the arithmetic of large non-negative integers
+// in-circuit, modulo a modulus which is itself a circuit variable.
+//
+// Contrary to [emulated], where the modulus is fixed when defining the
+// circuit, the modulus here can be a witness, for example an RSA public key.
+// The integers are represented by limbs of [LimbBits] bits, and each
+// multiplication is checked by the schoolbook product of the limbs followed by
+// a carry chain.
+//
+// The limbs of the integers given as witness must be range checked with
+// [Arithmetic.AssertIsWellFormed] before being used, the integers returned by
+// the methods are always well formed.
+//
+// [emulated]: https://pkg.go.dev/github.com/consensys/gnark/std/math/emulated
+package bigint
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// LimbBits is the number of bits of the limbs of the integers.
+const LimbBits = 120
+
+// Int is a non-negative integer, in limbs of [LimbBits] bits in little-endian.
+type Int struct {
+	Limbs []frontend.Variable
+}
+
+// NbLimbs returns the number of limbs of an integer of nbBits bits.
+func NbLimbs(nbBits int) int {
+	return (nbBits + LimbBits - 1) / LimbBits
+}
+
+// Placeholder returns an integer of nbBits bits to be used in the definition
+// of a circuit.
+func Placeholder(nbBits int) Int {
+	return Int{Limbs: make([]frontend.Variable, NbLimbs(nbBits))}
+}
+
+// ValueOf returns the witness assignment of v as an integer of nbBits bits. It
+// panics if v is negative or larger than nbBits bits.
+func ValueOf(v *big.Int, nbBits int) Int {
+	if v.Sign() < 0 || v.BitLen() > nbBits {
+		panic(fmt.Sprintf("value does not fit in %d bits", nbBits))
+	}
+	limbs := make([]*big.Int, NbLimbs(nbBits))
+	for i := range limbs {
+		limbs[i] = new(big.Int)
+	}
+	if err := decompose(v, limbs); err != nil {
+		panic(err)
+	}
+	res := Int{Limbs: make([]frontend.Variable, len(limbs))}
+	for i := range limbs {
+		res.Limbs[i] = limbs[i]
+	}
+	return res
+}
+
+// Arithmetic performs the operations on the integers.
+type Arithmetic struct {
+	api     frontend.API
+	checker frontend.Rangechecker
+}
+
+// New returns a new integer arithmetic. The native field must be of at least
+// 253 bits.
+func New(api frontend.API) (*Arithmetic, error) {
+	if api.Compiler().FieldBitLen() < 2*LimbBits+13 {
+		return nil, fmt.Errorf("native field of %d bits too small", api.Compiler().FieldBitLen())
+	}
+	return &Arithmetic{api: api, checker: rangecheck.New(api)}, nil
+}
+
+// AssertIsWellFormed asserts that the limbs of x are of [LimbBits] bits.
+func (a *Arithmetic) AssertIsWellFormed(x *Int) {
+	for i := range x.Limbs {
+		a.checker.Check(x.Limbs[i], LimbBits)
+	}
+}
+
+// AssertIsEqual asserts that x and y are equal.
+func (a *Arithmetic) AssertIsEqual(x, y *Int) {
+	for i := 0; i < len(x.Limbs) || i < len(y.Limbs); i++ {
+		a.api.AssertIsEqual(limb(x, i), limb(y, i))
+	}
+}
+
+// AssertIsLess asserts that x < y.
+func (a *Arithmetic) AssertIsLess(x, y *Int) {
+	// d = y - x - 1 is non-negative and x + d + 1 - y = 0
+	d := a.newHint(subHint, len(y.Limbs), x, y)
+	t := make([]frontend.Variable, len(y.Limbs))
+	for i := range t {
+		t[i] = a.api.Sub(a.api.Add(limb(x, i), d.Limbs[i]), y.Limbs[i])
+	}
+	if len(x.Limbs) > len(y.Limbs) {
+		for i := len(y.Limbs); i < len(x.Limbs); i++ {
+			a.api.AssertIsEqual(x.Limbs[i], 0)
+		}
+	}
+	t[0] = a.api.Add(t[0], 1)
+	a.assertIsZero(t, LimbBits+2)
+}
+
+// ModMul returns x·y mod n, with the result in [0, n).
+func (a *Arithmetic) ModMul(x, y, n *Int) *Int {
+	r := a.modMul(x, y, n)
+	a.AssertIsLess(r, n)
+	return r
+}
+
+// ModExp returns x^e mod n, with the result in [0, n), for a constant positive
+// exponent e. It costs e.BitLen()-1 squarings and as many multiplications as
+// there are bits set in e, besides the leading one.
+func (a *Arithmetic) ModExp(x *Int, e *big.Int, n *Int) *Int {
+	if e.Sign() <= 0 {
+		panic("exponent must be positive")
+	}
+	// the intermediate results are only reduced to len(n.Limbs) limbs, the
+	// final one is fully reduced.
+	res := x
+	for i := e.BitLen() - 2; i >= 0; i-- {
+		res = a.modMul(res, res, n)
+		if e.Bit(i) == 1 {
+			res = a.modMul(res, x, n)
+		}
+	}
+	if res == x {
+		res = a.modMul(x, &Int{Limbs: []frontend.Variable{1}}, n)
+	}
+	a.AssertIsLess(res, n)
+	return res
+}
+
+// ModExpBits returns x^e mod n, with the result in [0, n), for a variable
+// exponent e given by its bits in little-endian. The bits must be boolean. It
+// costs two multiplications per bit.
+func (a *Arithmetic) ModExpBits(x *Int, e []frontend.Variable, n *Int) *Int {
+	res := &Int{Limbs: []frontend.Variable{1}}
+	for i := len(e) - 1; i >= 0; i-- {
+		if i < len(e)-1 {
+			res = a.modMul(res, res, n)
+		}
+		res = a.selectInt(e[i], a.modMul(res, x, n), res)
+	}
+	res = a.modMul(res, &Int{Limbs: []frontend.Variable{1}}, n)
+	a.AssertIsLess(res, n)
+	return res
+}
+
+// selectInt returns x if b is 1 and y if b is 0.
+func (a *Arithmetic) selectInt(b frontend.Variable, x, y *Int) *Int {
+	nbLimbs := len(x.Limbs)
+	if len(y.Limbs) > nbLimbs {
+		nbLimbs = len(y.Limbs)
+	}
+	res := &Int{Limbs: make([]frontend.Variable, nbLimbs)}
+	for i := range res.Limbs {
+		res.Limbs[i] = a.api.Select(b, limb(x, i), limb(y, i))
+	}
+	return res
+}
+
+// FromBytes returns the integer whose big-endian encoding is b.
+func (a *Arithmetic) FromBytes(b []uints.U8) *Int {
+	const nbLimbBytes = LimbBits / 8
+	res := &Int{Limbs: make([]frontend.Variable, NbLimbs(8*len(b)))}
+	for i := range res.Limbs {
+		var limb frontend.Variable = 0
+		for j := nbLimbBytes - 1; j >= 0; j-- {
+			if k := len(b) - 1 - nbLimbBytes*i - j; k >= 0 {
+				limb = a.api.Add(a.api.Mul(limb, 256), b[k].Val)
+			}
+		}
+		res.Limbs[i] = limb
+	}
+	return res
+}
+
+// ToBytes returns the big-endian encoding of x on nbBytes bytes. It asserts
+// that x < 256^nbBytes.
+func (a *Arithmetic) ToBytes(x *Int, nbBytes int) []uints.U8 {
+	const nbLimbBytes = LimbBits / 8
+	res := make([]uints.U8, nbBytes)
+	for i := range x.Limbs {
+		n := nbBytes - nbLimbBytes*i
+		if n <= 0 {
+			a.api.AssertIsEqual(x.Limbs[i], 0)
+			continue
+		}
+		if n > nbLimbBytes {
+			n = nbLimbBytes
+		}
+		bts, err := a.api.Compiler().NewHint(toBytesHint, n, n, x.Limbs[i])
+		if err != nil {
+			panic(err)
+		}
+		var limb frontend.Variable = 0
+		for j := n - 1; j >= 0; j-- {
+			a.checker.Check(bts[j], 8)
+			limb = a.api.Add(a.api.Mul(limb, 256), bts[j])
+			res[nbBytes-1-nbLimbBytes*i-j] = uints.U8{Val: bts[j]}
+		}
+		a.api.AssertIsEqual(limb, x.Limbs[i])
+	}
+	for i := 0; i < nbBytes-nbLimbBytes*len(x.Limbs); i++ {
+		res[i] = uints.NewU8(0)
+	}
+	return res
+}
+
+// modMul returns r = x·y mod n, with r < 2^(LimbBits·len(n.Limbs)) but not
+// necessarily r < n.
+func (a *Arithmetic) modMul(x, y, n *Int) *Int {
+	nbQuoLimbs := len(x.Limbs) + len(y.Limbs) - len(n.Limbs) + 1
+	if nbQuoLimbs < 1 {
+		nbQuoLimbs = 1
+	}
+	res := a.newHint(modMulHint, nbQuoLimbs+len(n.Limbs), x, y, n)
+	q := &Int{Limbs: res.Limbs[:nbQuoLimbs]}
+	r := &Int{Limbs: res.Limbs[nbQuoLimbs:]}
+
+	// x·y - q·n - r = 0, where the coefficients of the products are bounded
+	// by their number of terms times 2^(2·LimbBits).
+	xy := a.mulLimbs(x, y)
+	qn := a.mulLimbs(q, n)
+	nbTerms := len(x.Limbs)
+	for _, l := range []int{len(y.Limbs), len(q.Limbs), len(n.Limbs)} {
+		if l > nbTerms {
+			nbTerms = l
+		}
+	}
+	nbCoeffs := len(xy)
+	if len(qn) > nbCoeffs {
+		nbCoeffs = len(qn)
+	}
+	t := make([]frontend.Variable, nbCoeffs)
+	for i := range t {
+		t[i] = a.api.Sub(coeff(xy, i), coeff(qn, i))
+		if i < len(r.Limbs) {
+			t[i] = a.api.Sub(t[i], r.Limbs[i])
+		}
+	}
+	a.assertIsZero(t, 2*LimbBits+bits.Len(uint(nbTerms))+1)
+	return r
+}
+
+// mulLimbs returns the coefficients of the product of the polynomials whose
+// coefficients are the limbs of x and y.
+func (a *Arithmetic) mulLimbs(x, y *Int) []frontend.Variable {
+	res := make([]frontend.Variable, len(x.Limbs)+len(y.Limbs)-1)
+	for i := range res {
+		res[i] = 0
+	}
+	if x == y {
+		// squaring, the cross products are computed once
+		for i := range x.Limbs {
+			res[2*i] = a.api.Add(res[2*i], a.api.Mul(x.Limbs[i], x.Limbs[i]))
+			for j := i + 1; j < len(x.Limbs); j++ {
+				res[i+j] = a.api.Add(res[i+j], a.api.Mul(x.Limbs[i], x.Limbs[j], 2))
+			}
+		}
+		return res
+	}
+	for i := range x.Limbs {
+		for j := range y.Limbs {
+			res[i+j] = a.api.Add(res[i+j], a.api.Mul(x.Limbs[i], y.Limbs[j]))
+		}
+	}
+	return res
+}
+
+// assertIsZero asserts that Σ t[i]·2^(LimbBits·i) = 0 over the integers,
+// where the coefficients t[i] are signed with |t[i]| < 2^nbBits. The carries
+// are given by a hint and range checked.
+func (a *Arithmetic) assertIsZero(t []frontend.Variable, nbBits int) {
+	if nbBits+3 >= a.api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("coefficients of %d bits too large for the native field", nbBits))
+	}
+	if len(t) == 1 {
+		a.api.AssertIsEqual(t[0], 0)
+		return
+	}
+	// |carry| < 2^nbCarryBits, and the carries are shifted to be positive
+	nbCarryBits := nbBits - LimbBits + 1
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(nbCarryBits))
+	inputs := make([]frontend.Variable, 0, len(t)+2)
+	inputs = append(inputs, LimbBits, nbCarryBits)
+	inputs = append(inputs, t...)
+	carries, err := a.api.Compiler().NewHint(carryHint, len(t)-1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	shift := new(big.Int).Lsh(big.NewInt(1), LimbBits)
+	var carry frontend.Variable = 0
+	for i := 0; i < len(t)-1; i++ {
+		a.checker.Check(carries[i], nbCarryBits+1)
+		next := a.api.Sub(carries[i], offset)
+		a.api.AssertIsEqual(a.api.Add(t[i], carry), a.api.Mul(next, shift))
+		carry = next
+	}
+	a.api.AssertIsEqual(a.api.Add(t[len(t)-1], carry), 0)
+}
+
+// newHint calls the hint hf with the limbs of the inputs, and returns its
+// nbOutputs outputs as the limbs of a well formed integer.
+func (a *Arithmetic) newHint(hf func(*big.Int, []*big.Int, []*big.Int) error, nbOutputs int, inputs ...*Int) *Int {
+	hInputs := []frontend.Variable{LimbBits, len(inputs)}
+	for _, in := range inputs {
+		hInputs = append(hInputs, len(in.Limbs))
+	}
+	for _, in := range inputs {
+		hInputs = append(hInputs, in.Limbs...)
+	}
+	limbs, err := a.api.Compiler().NewHint(hf, nbOutputs, hInputs...)
+	if err != nil {
+		panic(err)
+	}
+	res := &Int{Limbs: limbs}
+	a.AssertIsWellFormed(res)
+	return res
+}
+
+func limb(x *Int, i int) frontend.Variable {
+	if i < len(x.Limbs) {
+		return x.Limbs[i]
+	}
+	return 0
+}
+
+func coeff(c []frontend.Variable, i int) frontend.Variable {
+	if i < len(c) {
+		return c[i]
+	}
+	return 0
+}
@@ -0,0 +1,160 @@
+package bigint
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type modMulCircuit struct {
+	X, Y, N, Expected Int
+}
+
+func (c *modMulCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	a.AssertIsWellFormed(&c.X)
+	a.AssertIsWellFormed(&c.Y)
+	a.AssertIsWellFormed(&c.N)
+	a.AssertIsEqual(a.ModMul(&c.X, &c.Y, &c.N), &c.Expected)
+	a.AssertIsEqual(a.ModMul(&c.X, &c.X, &c.N), a.ModExp(&c.X, big.NewInt(2), &c.N))
+	return nil
+}
+
+func TestModMul(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbBits = 512
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), nbBits))
+	n.SetBit(n, nbBits-1, 1)
+	x, _ := rand.Int(rand.Reader, n)
+	y, _ := rand.Int(rand.Reader, n)
+	expected := new(big.Int).Mul(x, y)
+	expected.Mod(expected, n)
+	wrong := new(big.Int).Add(expected, n)
+
+	circuit := modMulCircuit{X: Placeholder(nbBits), Y: Placeholder(nbBits), N: Placeholder(nbBits), Expected: Placeholder(nbBits + 1)}
+	assert.CheckCircuit(&circuit,
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&modMulCircuit{X: ValueOf(x, nbBits), Y: ValueOf(y, nbBits), N: ValueOf(n, nbBits), Expected: ValueOf(expected, nbBits+1)}),
+		test.WithInvalidAssignment(&modMulCircuit{X: ValueOf(x, nbBits), Y: ValueOf(y, nbBits), N: ValueOf(n, nbBits), Expected: ValueOf(wrong, nbBits+1)}),
+	)
+}
+
+type modExpCircuit struct {
+	E              *big.Int `gnark:"-"`
+	X, N, Expected Int
+}
+
+func (c *modExpCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	a.AssertIsWellFormed(&c.X)
+	a.AssertIsWellFormed(&c.N)
+	a.AssertIsEqual(a.ModExp(&c.X, c.E, &c.N), &c.Expected)
+	return nil
+}
+
+func TestModExp(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbBits = 1024
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), nbBits))
+	n.SetBit(n, nbBits-1, 1)
+	x, _ := rand.Int(rand.Reader, n)
+	for _, e := range []int64{1, 3, 65537} {
+		expected := new(big.Int).Exp(x, big.NewInt(e), n)
+		wrong := new(big.Int).Exp(x, big.NewInt(e+1), n)
+		circuit := modExpCircuit{E: big.NewInt(e), X: Placeholder(nbBits), N: Placeholder(nbBits), Expected: Placeholder(nbBits)}
+		assert.CheckCircuit(&circuit,
+			test.WithCurves(ecc.BN254),
+			test.WithValidAssignment(&modExpCircuit{X: ValueOf(x, nbBits), N: ValueOf(n, nbBits), Expected: ValueOf(expected, nbBits)}),
+			test.WithInvalidAssignment(&modExpCircuit{X: ValueOf(x, nbBits), N: ValueOf(n, nbBits), Expected: ValueOf(wrong, nbBits)}),
+		)
+	}
+}
+
+type modExpBitsCircuit struct {
+	E              [16]frontend.Variable
+	X, N, Expected Int
+}
+
+func (c *modExpBitsCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	a.AssertIsWellFormed(&c.X)
+	a.AssertIsWellFormed(&c.N)
+	a.AssertIsEqual(a.ModExpBits(&c.X, c.E[:], &c.N), &c.Expected)
+	return nil
+}
+
+func TestModExpBits(t *testing.T) {
+	assert := test.NewAssert(t)
+	const nbBits = 512
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), nbBits))
+	n.SetBit(n, nbBits-1, 1)
+	x, _ := rand.Int(rand.Reader, n)
+	for _, e := range []int64{0, 1, 2, 65535, 40961} {
+		expected := new(big.Int).Exp(x, big.NewInt(e), n)
+		wrong := new(big.Int).Exp(x, big.NewInt(e+1), n)
+		valid := modExpBitsCircuit{X: ValueOf(x, nbBits), N: ValueOf(n, nbBits), Expected: ValueOf(expected, nbBits)}
+		invalid := modExpBitsCircuit{X: ValueOf(x, nbBits), N: ValueOf(n, nbBits), Expected: ValueOf(wrong, nbBits)}
+		for i := range valid.E {
+			valid.E[i] = (e >> i) & 1
+			invalid.E[i] = (e >> i) & 1
+		}
+		circuit := modExpBitsCircuit{X: Placeholder(nbBits), N: Placeholder(nbBits), Expected: Placeholder(nbBits)}
+		assert.CheckCircuit(&circuit,
+			test.WithCurves(ecc.BN254),
+			test.WithValidAssignment(&valid),
+			test.WithInvalidAssignment(&invalid),
+		)
+	}
+}
+
+type bytesCircuit struct {
+	X     Int
+	Bytes [40]uints.U8
+}
+
+func (c *bytesCircuit) Define(api frontend.API) error {
+	a, err := New(api)
+	if err != nil {
+		return err
+	}
+	a.AssertIsWellFormed(&c.X)
+	a.AssertIsEqual(a.FromBytes(c.Bytes[:]), &c.X)
+	bts := a.ToBytes(&c.X, len(c.Bytes))
+	for i := range bts {
+		api.AssertIsEqual(bts[i].Val, c.Bytes[i].Val)
+	}
+	a.AssertIsLess(&c.X, a.FromBytes(uints.NewU8Array(append([]byte{1}, make([]byte, len(c.Bytes))...))))
+	return nil
+}
+
+func TestBytes(t *testing.T) {
+	assert := test.NewAssert(t)
+	var b [40]byte
+	_, _ = rand.Read(b[:])
+	x := new(big.Int).SetBytes(b[:])
+	var valid, invalid bytesCircuit
+	valid.X = ValueOf(x, 8*len(b))
+	copy(valid.Bytes[:], uints.NewU8Array(b[:]))
+	b[0]++
+	invalid.X = ValueOf(x, 8*len(b))
+	copy(invalid.Bytes[:], uints.NewU8Array(b[:]))
+	assert.CheckCircuit(&bytesCircuit{X: Placeholder(8 * len(b))},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&valid),
+		test.WithInvalidAssignment(&invalid),
+	)
+}
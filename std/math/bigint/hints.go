@@ -0,0 +1,163 @@
+package bigint
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		modMulHint,
+		subHint,
+		carryHint,
+		toBytesHint,
+	}
+}
+
+// parseInputs returns the integers given as inputs by [Arithmetic.newHint].
+// The inputs are the limb width, the number of integers, the number of limbs
+// of each integer and then their limbs.
+func parseInputs(inputs []*big.Int) ([]*big.Int, error) {
+	if len(inputs) < 2 || !inputs[1].IsUint64() {
+		return nil, fmt.Errorf("missing number of integers")
+	}
+	nbInts := int(inputs[1].Uint64())
+	if len(inputs) < 2+nbInts {
+		return nil, fmt.Errorf("missing number of limbs")
+	}
+	res := make([]*big.Int, nbInts)
+	offset := 2 + nbInts
+	for i := range res {
+		nbLimbs := int(inputs[2+i].Uint64())
+		if len(inputs) < offset+nbLimbs {
+			return nil, fmt.Errorf("missing limbs")
+		}
+		res[i] = new(big.Int)
+		if err := recompose(inputs[offset:offset+nbLimbs], res[i]); err != nil {
+			return nil, err
+		}
+		offset += nbLimbs
+	}
+	return res, nil
+}
+
+// modMulHint returns the quotient and the remainder of the product of the
+// first two integers by the third one.
+func modMulHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	ints, err := parseInputs(inputs)
+	if err != nil {
+		return err
+	}
+	if len(ints) != 3 {
+		return fmt.Errorf("expecting three integers")
+	}
+	if ints[2].Sign() == 0 {
+		return fmt.Errorf("zero modulus")
+	}
+	nbQuoLimbs := len(outputs) - int(inputs[4].Uint64())
+	if nbQuoLimbs < 0 {
+		return fmt.Errorf("not enough outputs")
+	}
+	q, r := new(big.Int), new(big.Int)
+	q.QuoRem(new(big.Int).Mul(ints[0], ints[1]), ints[2], r)
+	if err := decompose(q, outputs[:nbQuoLimbs]); err != nil {
+		return fmt.Errorf("quotient: %w", err)
+	}
+	return decompose(r, outputs[nbQuoLimbs:])
+}
+
+// subHint returns y - x - 1 for the two integers x and y.
+func subHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	ints, err := parseInputs(inputs)
+	if err != nil {
+		return err
+	}
+	if len(ints) != 2 {
+		return fmt.Errorf("expecting two integers")
+	}
+	d := new(big.Int).Sub(ints[1], ints[0])
+	d.Sub(d, big.NewInt(1))
+	if d.Sign() < 0 {
+		return fmt.Errorf("%s is not less than %s", ints[0], ints[1])
+	}
+	return decompose(d, outputs)
+}
+
+// carryHint returns the carries of the sum of the signed coefficients times
+// powers of 2^LimbBits, shifted by 2^nbCarryBits.
+func carryHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != len(outputs)+3 {
+		return fmt.Errorf("expecting %d inputs", len(outputs)+3)
+	}
+	nbBits := uint(inputs[0].Uint64())
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(inputs[1].Uint64()))
+	half := new(big.Int).Rsh(mod, 1)
+	mask := new(big.Int).Lsh(big.NewInt(1), nbBits)
+	mask.Sub(mask, big.NewInt(1))
+	carry := new(big.Int)
+	for i := range outputs {
+		t := new(big.Int).Set(inputs[2+i])
+		if t.Cmp(half) > 0 {
+			t.Sub(t, mod)
+		}
+		t.Add(t, carry)
+		if new(big.Int).And(t, mask).Sign() != 0 {
+			return fmt.Errorf("coefficient %d not divisible", i)
+		}
+		carry.Rsh(t, nbBits)
+		outputs[i].Add(carry, offset)
+	}
+	return nil
+}
+
+// toBytesHint returns the first bytes of the second input, in little-endian.
+func toBytesHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expecting two inputs")
+	}
+	if int(inputs[0].Uint64()) != len(outputs) {
+		return fmt.Errorf("expecting %d outputs", inputs[0].Uint64())
+	}
+	v := new(big.Int).Set(inputs[1])
+	for i := range outputs {
+		outputs[i].SetUint64(v.Uint64() & 0xff)
+		v.Rsh(v, 8)
+	}
+	return nil
+}
+
+// recompose sets res to the integer of the given limbs.
+func recompose(limbs []*big.Int, res *big.Int) error {
+	res.SetUint64(0)
+	for i := len(limbs) - 1; i >= 0; i-- {
+		if limbs[i].BitLen() > LimbBits {
+			return fmt.Errorf("limb %d larger than %d bits", i, LimbBits)
+		}
+		res.Lsh(res, LimbBits)
+		res.Add(res, limbs[i])
+	}
+	return nil
+}
+
+// decompose sets the limbs of v in res. It returns an error if v doesn't fit.
+func decompose(v *big.Int, res []*big.Int) error {
+	if v.BitLen() > LimbBits*len(res) {
+		return fmt.Errorf("value does not fit in %d limbs", len(res))
+	}
+	mask := new(big.Int).Lsh(big.NewInt(1), LimbBits)
+	mask.Sub(mask, big.NewInt(1))
+	tmp := new(big.Int).Set(v)
+	for i := range res {
+		res[i].And(tmp, mask)
+		tmp.Rsh(tmp, LimbBits)
+	}
+	return nil
+}
@@ -150,6 +150,23 @@ type BLS12381Fr struct{ fourLimbPrimeField }
 
 func (fp BLS12381Fr) Modulus() *big.Int { return ecc.BLS12_381.ScalarField() }
 
+// BandersnatchFr provides type parametrization for field emulation:
+//   - limbs: 4
+//   - limb width: 64 bits
+//
+// The prime modulus for type parametrisation is:
+//
+//	0x1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1 (base 16)
+//	13108968793781547619861935127046491459309155893440570251786403306729687672801 (base 10)
+//
+// This is the scalar field of the Bandersnatch curve, the twisted Edwards curve
+// defined over the scalar field of BLS12-381.
+type BandersnatchFr struct{ fourLimbPrimeField }
+
+func (fp BandersnatchFr) Modulus() *big.Int { return bandersnatchFr }
+
+var bandersnatchFr, _ = new(big.Int).SetString("13108968793781547619861935127046491459309155893440570251786403306729687672801", 10)
+
 // P256Fp provides type parametrization for field emulation:
 //   - limbs: 4
 //   - limb width: 64 bits
@@ -0,0 +1,291 @@
+// Package fixed implements signed fixed-point arithmetic in-circuit.
+//
+// A number x is represented by the native field element x·2^F rounded to an
+// integer, where F is the number of fractional bits. With n bits in total the
+// representable numbers are the multiples of 2^-F in [-2^(n-F-1), 2^(n-F-1)),
+// the negative numbers being represented by their opposite in the native field.
+//
+// The results of the operations are rounded with the [RoundingMode] of the
+// arithmetic. By default, an operation whose result doesn't fit in n bits makes
+// the circuit unsatisfiable. With [WithOverflowTracking] the results wrap
+// around instead, as in two's complement, and [Arithmetic.Overflow] tells
+// whether any operation overflowed.
+//
+// The operations assume that their operands fit in n bits. The results of the
+// operations always do, but the numbers given as witness must be checked with
+// [Arithmetic.AssertIsInRange] before being used.
+package fixed
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/sqrt"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Number is a fixed-point number.
+type Number struct {
+	V frontend.Variable
+}
+
+// ValueOf returns the witness assignment of x with nbFractionalBits fractional
+// bits, rounded to the nearest representable number with ties away from zero.
+func ValueOf(x float64, nbFractionalBits int) Number {
+	r := new(big.Rat).SetFloat64(x)
+	if r == nil {
+		panic("x is not finite")
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(nbFractionalBits))))
+	num := new(big.Int).Lsh(r.Num(), 1)
+	if r.Sign() < 0 {
+		num.Sub(num, r.Denom())
+	} else {
+		num.Add(num, r.Denom())
+	}
+	return Number{V: num.Quo(num, new(big.Int).Lsh(r.Denom(), 1))}
+}
+
+// RoundingMode is the rounding of the results which aren't representable.
+type RoundingMode int
+
+const (
+	// RoundDown rounds toward -∞.
+	RoundDown RoundingMode = iota
+	// RoundUp rounds toward +∞.
+	RoundUp
+	// RoundNearest rounds to the nearest representable number, with ties
+	// rounded up.
+	RoundNearest
+)
+
+type config struct {
+	mode     RoundingMode
+	tracking bool
+}
+
+// Option configures the arithmetic.
+type Option func(*config) error
+
+// WithRoundingMode sets the rounding of the results. The default is
+// [RoundDown].
+func WithRoundingMode(mode RoundingMode) Option {
+	return func(c *config) error {
+		if mode < RoundDown || mode > RoundNearest {
+			return errors.New("unknown rounding mode")
+		}
+		c.mode = mode
+		return nil
+	}
+}
+
+// WithOverflowTracking makes the results which don't fit wrap around instead
+// of making the circuit unsatisfiable, see [Arithmetic.Overflow].
+func WithOverflowTracking() Option {
+	return func(c *config) error {
+		c.tracking = true
+		return nil
+	}
+}
+
+// Arithmetic performs the operations on the fixed-point numbers.
+type Arithmetic struct {
+	api              frontend.API
+	checker          frontend.Rangechecker
+	nbBits           int
+	nbFractionalBits int
+	config
+	overflow frontend.Variable
+}
+
+// New returns the arithmetic of the numbers of nbBits bits, of which
+// nbFractionalBits are fractional. The native field must be at least
+// 3·nbBits+4 bits long.
+func New(api frontend.API, nbBits, nbFractionalBits int, opts ...Option) (*Arithmetic, error) {
+	if nbFractionalBits < 0 || nbFractionalBits >= nbBits {
+		return nil, errors.New("invalid number of fractional bits")
+	}
+	if 3*nbBits+4 > api.Compiler().FieldBitLen() {
+		return nil, errors.New("native field too small for the number of bits")
+	}
+	a := &Arithmetic{
+		api:              api,
+		checker:          rangecheck.New(api),
+		nbBits:           nbBits,
+		nbFractionalBits: nbFractionalBits,
+		overflow:         0,
+	}
+	for _, opt := range opts {
+		if err := opt(&a.config); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Constant returns x rounded to the nearest representable number, with ties
+// away from zero.
+func (a *Arithmetic) Constant(x float64) Number {
+	return ValueOf(x, a.nbFractionalBits)
+}
+
+// Overflow returns 1 if the result of an operation didn't fit since the
+// creation of the arithmetic, and 0 otherwise. It is always 0 without
+// [WithOverflowTracking], as the overflows make the circuit unsatisfiable.
+func (a *Arithmetic) Overflow() frontend.Variable {
+	return a.overflow
+}
+
+// AssertIsInRange asserts that x fits in the number of bits of the arithmetic.
+func (a *Arithmetic) AssertIsInRange(x Number) {
+	a.checker.Check(a.api.Add(x.V, a.pow2(a.nbBits-1)), a.nbBits)
+}
+
+// AssertIsEqual asserts that x and y are equal.
+func (a *Arithmetic) AssertIsEqual(x, y Number) {
+	a.api.AssertIsEqual(x.V, y.V)
+}
+
+// Add returns x+y.
+func (a *Arithmetic) Add(x, y Number) Number {
+	return a.fit(a.api.Add(x.V, y.V), a.nbBits+1)
+}
+
+// Sub returns x-y.
+func (a *Arithmetic) Sub(x, y Number) Number {
+	return a.fit(a.api.Sub(x.V, y.V), a.nbBits+1)
+}
+
+// Neg returns -x.
+func (a *Arithmetic) Neg(x Number) Number {
+	return a.fit(a.api.Neg(x.V), a.nbBits+1)
+}
+
+// IsNegative returns 1 if x < 0 and 0 otherwise.
+func (a *Arithmetic) IsNegative(x Number) frontend.Variable {
+	_, isNeg := a.abs(x.V)
+	return isNeg
+}
+
+// Abs returns |x|.
+func (a *Arithmetic) Abs(x Number) Number {
+	abs, _ := a.abs(x.V)
+	return a.fit(abs, a.nbBits+1)
+}
+
+// Mul returns x·y, rounded.
+func (a *Arithmetic) Mul(x, y Number) Number {
+	q, nbBits := a.divRound(a.api.Mul(x.V, y.V), 2*a.nbBits, a.pow2(a.nbFractionalBits), a.nbFractionalBits+1, a.mode)
+	return a.fit(q, nbBits)
+}
+
+// Div returns x/y, rounded. The circuit is unsatisfiable if y is zero.
+func (a *Arithmetic) Div(x, y Number) Number {
+	abs, isNeg := a.abs(y.V)
+	// x/y = (±x)/|y| with the sign of y
+	num := a.api.Mul(x.V, a.pow2(a.nbFractionalBits), a.api.Sub(1, a.api.Mul(isNeg, 2)))
+	q, nbBits := a.divRound(num, a.nbBits+a.nbFractionalBits+1, abs, a.nbBits, a.mode)
+	return a.fit(q, nbBits)
+}
+
+// Sqrt returns √x, rounded. The circuit is unsatisfiable if x is negative.
+func (a *Arithmetic) Sqrt(x Number) Number {
+	a.checker.Check(x.V, a.nbBits-1)
+	// the representation of √x is √(x·2^F)
+	nbBits := a.nbBits - 1 + a.nbFractionalBits
+	v := a.api.Mul(x.V, a.pow2(a.nbFractionalBits))
+	var res frontend.Variable
+	switch a.mode {
+	case RoundDown:
+		res = sqrt.Isqrt(a.api, v, nbBits)
+	case RoundUp:
+		s := sqrt.Isqrt(a.api, v, nbBits)
+		res = a.api.Sub(a.api.Add(s, 1), a.api.IsZero(a.api.Sub(v, a.api.Mul(s, s))))
+	case RoundNearest:
+		// ⌊√v + 1/2⌋ = ⌈⌊√(4v)⌋/2⌉
+		s := sqrt.Isqrt(a.api, a.api.Mul(v, 4), nbBits+2)
+		res, _ = a.divRound(s, nbBits/2+4, 2, 2, RoundUp)
+	}
+	nbRootBits := nbBits/2 + 5
+	if nbRootBits <= a.nbBits {
+		nbRootBits = a.nbBits + 1
+	}
+	return a.fit(res, nbRootBits)
+}
+
+// abs returns |x| and whether x is negative.
+func (a *Arithmetic) abs(x frontend.Variable) (abs, isNeg frontend.Variable) {
+	res, err := a.api.Compiler().NewHint(isNegativeHint, 1, x)
+	if err != nil {
+		panic(err)
+	}
+	isNeg = res[0]
+	a.api.AssertIsBoolean(isNeg)
+	abs = a.api.Select(isNeg, a.api.Neg(x), x)
+	// abs - isNeg is in [0, 2^(n-1)) only if isNeg is the sign of x, and also
+	// excludes the negative zero.
+	a.checker.Check(a.api.Sub(abs, isNeg), a.nbBits-1)
+	return abs, isNeg
+}
+
+// divRound returns num/d rounded with mode and the bound of its bit-length,
+// sign included, where num is signed with |num| < 2^(nbNumBits-1) and d is in
+// [1, 2^nbDenBits). nbDenBits must be smaller than nbNumBits.
+func (a *Arithmetic) divRound(num frontend.Variable, nbNumBits int, d frontend.Variable, nbDenBits int, mode RoundingMode) (frontend.Variable, int) {
+	// reduce the rounding to a floor division num'/d' with
+	// |num'| < 2^(nbNumBits+1) and d' in [1, 2^(nbDenBits+1))
+	switch mode {
+	case RoundUp:
+		num = a.api.Sub(a.api.Add(num, d), 1)
+	case RoundNearest:
+		num = a.api.Add(a.api.Mul(num, 2), d)
+		d = a.api.Mul(d, 2)
+	}
+	// shift the numerator so that it is non-negative. Then the quotient is
+	// shifted by bias too.
+	bias := a.pow2(nbNumBits + 1)
+	num = a.api.Add(num, a.api.Mul(d, bias))
+	res, err := a.api.Compiler().NewHint(divHint, 2, num, d)
+	if err != nil {
+		panic(err)
+	}
+	q, r := res[0], res[1]
+	// 0 ≤ q < 2^(nbNumBits+2) and 0 ≤ r < d, which also excludes d = 0.
+	a.checker.Check(q, nbNumBits+2)
+	a.checker.Check(r, nbDenBits+1)
+	a.checker.Check(a.api.Sub(a.api.Sub(d, 1), r), nbDenBits+1)
+	a.api.AssertIsEqual(num, a.api.Add(a.api.Mul(q, d), r))
+	return a.api.Sub(q, bias), nbNumBits + 2
+}
+
+// fit returns the number x where x is signed with |x| ≤ 2^(nbBits-1), nbBits
+// being larger than the number of bits of the arithmetic. If x doesn't fit, then
+// the circuit is unsatisfiable or the result wraps around and the overflow is
+// recorded.
+func (a *Arithmetic) fit(x frontend.Variable, nbBits int) Number {
+	n := a.nbBits
+	if !a.tracking {
+		a.AssertIsInRange(Number{V: x})
+		return Number{V: x}
+	}
+	// w = x + 2^(n-1) + m·2^n with m = 2^(nbBits-n-1) is non-negative and less
+	// than 2^(nbBits+1). x fits iff the quotient of w by 2^n is m.
+	m := a.pow2(nbBits - n - 1)
+	w := a.api.Add(x, a.pow2(n-1), a.api.Mul(m, a.pow2(n)))
+	res, err := a.api.Compiler().NewHint(divHint, 2, w, a.pow2(n))
+	if err != nil {
+		panic(err)
+	}
+	hi, lo := res[0], res[1]
+	a.checker.Check(lo, n)
+	a.checker.Check(hi, nbBits-n+1)
+	a.api.AssertIsEqual(w, a.api.Add(lo, a.api.Mul(hi, a.pow2(n))))
+	overflow := a.api.Sub(1, a.api.IsZero(a.api.Sub(hi, m)))
+	a.overflow = a.api.Or(a.overflow, overflow)
+	return Number{V: a.api.Sub(lo, a.pow2(n-1))}
+}
+
+func (a *Arithmetic) pow2(e int) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(e))
+}
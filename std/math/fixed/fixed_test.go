@@ -0,0 +1,201 @@
+package fixed
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	testBits           = 32
+	testFractionalBits = 16
+)
+
+// reference implementation of the operations on the integer representations.
+
+func floorDiv(num, d *big.Int, mode RoundingMode) *big.Int {
+	num, d = new(big.Int).Set(num), new(big.Int).Set(d)
+	if d.Sign() < 0 {
+		num.Neg(num)
+		d.Neg(d)
+	}
+	switch mode {
+	case RoundUp:
+		num.Add(num, d).Sub(num, big.NewInt(1))
+	case RoundNearest:
+		num.Lsh(num, 1).Add(num, d)
+		d.Lsh(d, 1)
+	}
+	// Div is the Euclidean division, which is the floor division for d > 0
+	return num.Div(num, d)
+}
+
+func refMul(x, y *big.Int, mode RoundingMode) *big.Int {
+	return floorDiv(new(big.Int).Mul(x, y), big.NewInt(1<<testFractionalBits), mode)
+}
+
+func refDiv(x, y *big.Int, mode RoundingMode) *big.Int {
+	return floorDiv(new(big.Int).Lsh(x, testFractionalBits), y, mode)
+}
+
+func refSqrt(x *big.Int, mode RoundingMode) *big.Int {
+	v := new(big.Int).Lsh(x, testFractionalBits)
+	s := new(big.Int).Sqrt(v)
+	switch mode {
+	case RoundUp:
+		if new(big.Int).Mul(s, s).Cmp(v) != 0 {
+			s.Add(s, big.NewInt(1))
+		}
+	case RoundNearest:
+		s.Sqrt(v.Lsh(v, 2))
+		s.Add(s, big.NewInt(1)).Rsh(s, 1)
+	}
+	return s
+}
+
+// number returns the witness assignment of the integer representation v,
+// reduced modulo the BN254 scalar field as the test engine doesn't reduce the
+// negative values.
+func number(v *big.Int) Number {
+	return Number{V: new(big.Int).Mod(v, ecc.BN254.ScalarField())}
+}
+
+type opsCircuit struct {
+	X, Y                       Number
+	Sum, Diff, Prod, Quo, Root Number
+	IsNeg                      frontend.Variable
+	mode                       RoundingMode
+}
+
+func (c *opsCircuit) Define(api frontend.API) error {
+	f, err := New(api, testBits, testFractionalBits, WithRoundingMode(c.mode))
+	if err != nil {
+		return err
+	}
+	f.AssertIsInRange(c.X)
+	f.AssertIsInRange(c.Y)
+	f.AssertIsEqual(f.Add(c.X, c.Y), c.Sum)
+	f.AssertIsEqual(f.Sub(c.X, c.Y), c.Diff)
+	f.AssertIsEqual(f.Mul(c.X, c.Y), c.Prod)
+	f.AssertIsEqual(f.Div(c.X, c.Y), c.Quo)
+	f.AssertIsEqual(f.Sqrt(f.Abs(c.X)), c.Root)
+	api.AssertIsEqual(f.IsNegative(c.X), c.IsNeg)
+	api.AssertIsEqual(f.Overflow(), 0)
+	return nil
+}
+
+func opsAssignment(x, y float64, mode RoundingMode) *opsCircuit {
+	xv := ValueOf(x, testFractionalBits).V.(*big.Int)
+	yv := ValueOf(y, testFractionalBits).V.(*big.Int)
+	isNeg := 0
+	if xv.Sign() < 0 {
+		isNeg = 1
+	}
+	return &opsCircuit{
+		X:     number(xv),
+		Y:     number(yv),
+		Sum:   number(new(big.Int).Add(xv, yv)),
+		Diff:  number(new(big.Int).Sub(xv, yv)),
+		Prod:  number(refMul(xv, yv, mode)),
+		Quo:   number(refDiv(xv, yv, mode)),
+		Root:  number(refSqrt(new(big.Int).Abs(xv), mode)),
+		IsNeg: isNeg,
+	}
+}
+
+func TestOperations(t *testing.T) {
+	assert := test.NewAssert(t)
+	values := [][2]float64{
+		{3.25, 1.7},
+		{-3.25, 1.7},
+		{3.25, -1.7},
+		{-0.001, -7.5},
+		{0, 2},
+		{1.5, 1.5},
+		{123.456, 0.01},
+	}
+	for _, mode := range []RoundingMode{RoundDown, RoundUp, RoundNearest} {
+		for _, v := range values {
+			assert.Run(func(assert *test.Assert) {
+				circuit := &opsCircuit{mode: mode}
+				assignment := opsAssignment(v[0], v[1], mode)
+				assert.NoError(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()))
+			}, fmt.Sprintf("mode=%d/x=%v/y=%v", mode, v[0], v[1]))
+		}
+	}
+	// the rounding is enforced
+	offByOne := opsAssignment(3.25, 1.7, RoundNearest)
+	offByOne.Quo.V = new(big.Int).Add(offByOne.Quo.V.(*big.Int), big.NewInt(1))
+	assert.CheckCircuit(&opsCircuit{mode: RoundNearest},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(opsAssignment(3.25, 1.7, RoundNearest)),
+		test.WithInvalidAssignment(opsAssignment(3.25, 1.7, RoundDown)),
+		test.WithInvalidAssignment(offByOne),
+	)
+}
+
+type divCircuit struct {
+	X, Y, Quo Number
+}
+
+func (c *divCircuit) Define(api frontend.API) error {
+	f, err := New(api, testBits, testFractionalBits)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(f.Div(c.X, c.Y), c.Quo)
+	return nil
+}
+
+func TestDivisionByZero(t *testing.T) {
+	assert := test.NewAssert(t)
+	err := test.IsSolved(&divCircuit{}, &divCircuit{
+		X:   ValueOf(1, testFractionalBits),
+		Y:   ValueOf(0, testFractionalBits),
+		Quo: ValueOf(0, testFractionalBits),
+	}, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type overflowCircuit struct {
+	X, Y, Prod Number
+	Overflow   frontend.Variable
+	tracking   bool
+}
+
+func (c *overflowCircuit) Define(api frontend.API) error {
+	var opts []Option
+	if c.tracking {
+		opts = append(opts, WithOverflowTracking())
+	}
+	f, err := New(api, testBits, testFractionalBits, opts...)
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(f.Mul(c.X, c.Y), c.Prod)
+	api.AssertIsEqual(f.Overflow(), c.Overflow)
+	return nil
+}
+
+func TestOverflow(t *testing.T) {
+	assert := test.NewAssert(t)
+	// 300·-200 = -60000 doesn't fit in 16 integer bits
+	x, y := ValueOf(300, testFractionalBits).V.(*big.Int), ValueOf(-200, testFractionalBits).V.(*big.Int)
+	prod := refMul(x, y, RoundDown)
+	wrapped := new(big.Int).Add(prod, big.NewInt(1<<(testBits-1)))
+	wrapped.Mod(wrapped, big.NewInt(1<<testBits)).Sub(wrapped, big.NewInt(1<<(testBits-1)))
+	small := ValueOf(-0.025, testFractionalBits).V.(*big.Int)
+
+	assert.CheckCircuit(&overflowCircuit{tracking: true},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&overflowCircuit{X: number(x), Y: number(y), Prod: number(wrapped), Overflow: 1}),
+		test.WithValidAssignment(&overflowCircuit{X: number(x), Y: number(small), Prod: number(refMul(x, small, RoundDown)), Overflow: 0}),
+		test.WithInvalidAssignment(&overflowCircuit{X: number(x), Y: number(y), Prod: number(wrapped), Overflow: 0}),
+	)
+	err := test.IsSolved(&overflowCircuit{}, &overflowCircuit{X: number(x), Y: number(y), Prod: number(prod), Overflow: 0}, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,49 @@
+package fixed
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		divHint,
+		isNegativeHint,
+	}
+}
+
+// divHint returns the quotient and the remainder of the division of the
+// non-negative inputs.
+func divHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 2 {
+		return fmt.Errorf("expecting two inputs and two outputs")
+	}
+	if inputs[1].Sign() == 0 {
+		return fmt.Errorf("division by zero")
+	}
+	outputs[0].QuoRem(inputs[0], inputs[1], outputs[1])
+	return nil
+}
+
+// isNegativeHint returns 1 if the input is larger than half of the modulus,
+// that is it represents a negative number, and 0 otherwise.
+func isNegativeHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return fmt.Errorf("expecting one input and one output")
+	}
+	half := new(big.Int).Rsh(mod, 1)
+	if inputs[0].Cmp(half) > 0 {
+		outputs[0].SetUint64(1)
+	} else {
+		outputs[0].SetUint64(0)
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+// Package float implements the IEEE-754 binary floating-point arithmetic
+// in-circuit, bit-exactly.
+//
+// A number is given by its encoding, for example math.Float32bits(x) for a
+// float32. The operations are correctly rounded to nearest with ties to even,
+// as the default rounding of IEEE-754, and handle the signed zeros, the
+// subnormal numbers, the infinities and NaN. The only freedom IEEE-754 leaves,
+// the payload of the NaN results, is fixed to the canonical quiet NaN with a
+// zero payload and a positive sign.
+//
+// The encodings are range checked when the numbers are unpacked, so the
+// numbers given as witness don't need to be checked.
+package float
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Format is a binary interchange format of IEEE-754.
+type Format struct {
+	// NbExponentBits is the number of bits of the biased exponent.
+	NbExponentBits int
+	// NbMantissaBits is the number of bits of the trailing significand.
+	NbMantissaBits int
+}
+
+var (
+	// Float32 is the binary32 format.
+	Float32 = Format{NbExponentBits: 8, NbMantissaBits: 23}
+	// Float64 is the binary64 format.
+	Float64 = Format{NbExponentBits: 11, NbMantissaBits: 52}
+)
+
+// nbBits returns the number of bits of the encodings.
+func (f Format) nbBits() int {
+	return 1 + f.NbExponentBits + f.NbMantissaBits
+}
+
+// bias returns the bias of the exponent.
+func (f Format) bias() int {
+	return 1<<(f.NbExponentBits-1) - 1
+}
+
+// Float is the encoding of a floating-point number.
+type Float struct {
+	V frontend.Variable
+}
+
+// ValueOf32 returns the witness assignment of x in [Float32].
+func ValueOf32(x float32) Float {
+	return Float{V: math.Float32bits(x)}
+}
+
+// ValueOf64 returns the witness assignment of x in [Float64].
+func ValueOf64(x float64) Float {
+	return Float{V: math.Float64bits(x)}
+}
+
+// Arithmetic performs the operations on the numbers of a format.
+type Arithmetic struct {
+	api     frontend.API
+	checker frontend.Rangechecker
+	f       Format
+}
+
+// New returns the arithmetic of the numbers in format f. The bias of the
+// exponent must be larger than NbMantissaBits+3, which holds for the standard
+// formats, and the native field must be at least 3·NbMantissaBits+10 bits long.
+func New(api frontend.API, f Format) (*Arithmetic, error) {
+	if f.NbExponentBits < 2 || f.NbMantissaBits < 1 || f.NbMantissaBits+3 >= f.bias() {
+		return nil, errors.New("unsupported format")
+	}
+	if 3*f.NbMantissaBits+10 > api.Compiler().FieldBitLen() {
+		return nil, errors.New("native field too small for the format")
+	}
+	return &Arithmetic{api: api, checker: rangecheck.New(api), f: f}, nil
+}
+
+// unpacked is a number split into the fields of its encoding.
+type unpacked struct {
+	sign, exp, mant frontend.Variable
+	nan, inf, zero  frontend.Variable
+	// sig is the significand, with the implicit leading bit of the normal
+	// numbers.
+	sig frontend.Variable
+	// ex is the biased exponent of the significand, 1 for the subnormal
+	// numbers.
+	ex frontend.Variable
+}
+
+func (a *Arithmetic) unpack(x Float) unpacked {
+	api := a.api
+	m, e := a.f.NbMantissaBits, a.f.NbExponentBits
+	res, err := api.Compiler().NewHint(unpackHint, 3, x.V, m, e)
+	if err != nil {
+		panic(err)
+	}
+	u := unpacked{mant: res[0], exp: res[1], sign: res[2]}
+	a.checker.Check(u.mant, m)
+	a.checker.Check(u.exp, e)
+	api.AssertIsBoolean(u.sign)
+	api.AssertIsEqual(x.V, api.Add(u.mant, api.Mul(u.exp, pow2(m)), api.Mul(u.sign, pow2(m+e))))
+
+	expZero := api.IsZero(u.exp)
+	expMax := api.IsZero(api.Sub(u.exp, 1<<e-1))
+	mantZero := api.IsZero(u.mant)
+	u.nan = api.Mul(expMax, api.Sub(1, mantZero))
+	u.inf = api.Mul(expMax, mantZero)
+	u.zero = api.Mul(expZero, mantZero)
+	u.sig = api.Add(u.mant, api.Mul(api.Sub(1, expZero), pow2(m)))
+	u.ex = api.Add(u.exp, expZero)
+	return u
+}
+
+// pack returns the encoding of the number of sign and magnitude, the
+// magnitude being the encoding without the sign.
+func (a *Arithmetic) pack(sign, magnitude frontend.Variable) Float {
+	return Float{V: a.api.Add(magnitude, a.api.Mul(sign, pow2(a.f.nbBits()-1)))}
+}
+
+// infinity returns the magnitude of the infinities.
+func (a *Arithmetic) infinity() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1<<a.f.NbExponentBits-1), uint(a.f.NbMantissaBits))
+}
+
+// nan returns the canonical quiet NaN.
+func (a *Arithmetic) nan() Float {
+	return Float{V: new(big.Int).Add(a.infinity(), pow2(a.f.NbMantissaBits-1))}
+}
+
+// Neg returns -x.
+func (a *Arithmetic) Neg(x Float) Float {
+	u := a.unpack(x)
+	return a.pack(a.api.Sub(1, u.sign), a.api.Sub(x.V, a.api.Mul(u.sign, pow2(a.f.nbBits()-1))))
+}
+
+// Add returns x+y.
+func (a *Arithmetic) Add(x, y Float) Float {
+	return a.add(a.unpack(x), a.unpack(y))
+}
+
+// Sub returns x-y.
+func (a *Arithmetic) Sub(x, y Float) Float {
+	uy := a.unpack(y)
+	uy.sign = a.api.Sub(1, uy.sign)
+	return a.add(a.unpack(x), uy)
+}
+
+func (a *Arithmetic) add(ux, uy unpacked) Float {
+	api := a.api
+	m, e := a.f.NbMantissaBits, a.f.NbExponentBits
+	// order the operands by exponent
+	swap := a.isNegative(api.Sub(ux.ex, uy.ex), e+1)
+	bigSig, smallSig := api.Select(swap, uy.sig, ux.sig), api.Select(swap, ux.sig, uy.sig)
+	bigEx, smallEx := api.Select(swap, uy.ex, ux.ex), api.Select(swap, ux.ex, uy.ex)
+	bigSign, smallSign := api.Select(swap, uy.sign, ux.sign), api.Select(swap, ux.sign, uy.sign)
+
+	// align the significands. If the exponents differ by more than m+3, then
+	// the smaller operand is less than a quarter of the unit in the last place
+	// of the larger one and only matters for the rounding. In that case it is
+	// replaced by a sticky unit, which is rounded the same way.
+	d := api.Sub(bigEx, smallEx)
+	far := api.Sub(1, a.isNegative(api.Sub(d, m+4), e+1))
+	shift := api.Select(far, m+3, d)
+	small := api.Select(far, api.Sub(1, api.IsZero(smallSig)), smallSig)
+	bigAligned := api.Mul(bigSig, a.pow2(shift, mbits.Len(uint(m+3))))
+
+	// the exact sum is p·2^k
+	subtract := api.Xor(bigSign, smallSign)
+	p := api.Add(bigAligned, api.Mul(small, api.Sub(1, api.Mul(subtract, 2))))
+	k := api.Sub(bigEx, shift, a.f.bias()+m)
+	nbBits := 2*m + 5
+	neg := a.isNegative(p, nbBits)
+	abs := api.Select(neg, api.Neg(p), p)
+	// an exact zero is positive unless both operands are negative
+	sign := api.Select(api.IsZero(p), api.And(ux.sign, uy.sign), api.Xor(bigSign, neg))
+	res := a.pack(sign, a.round(abs, nbBits, k, e+1))
+
+	// the infinities have the sign of the infinite operand, and opposite
+	// infinities sum to NaN.
+	inf := api.Or(ux.inf, uy.inf)
+	res = a.selectFloat(inf, a.pack(api.Select(ux.inf, ux.sign, uy.sign), a.infinity()), res)
+	nan := api.Or(api.Or(ux.nan, uy.nan), api.And(api.And(ux.inf, uy.inf), api.Xor(ux.sign, uy.sign)))
+	return a.selectFloat(nan, a.nan(), res)
+}
+
+// Mul returns x·y.
+func (a *Arithmetic) Mul(x, y Float) Float {
+	api := a.api
+	m, e := a.f.NbMantissaBits, a.f.NbExponentBits
+	ux, uy := a.unpack(x), a.unpack(y)
+	sign := api.Xor(ux.sign, uy.sign)
+	// the exact product is p·2^k
+	p := api.Mul(ux.sig, uy.sig)
+	k := api.Sub(api.Add(ux.ex, uy.ex), 2*(a.f.bias()+m))
+	res := a.pack(sign, a.round(p, 2*m+2, k, e+1))
+
+	res = a.selectFloat(api.Or(ux.inf, uy.inf), a.pack(sign, a.infinity()), res)
+	nan := api.Or(api.Or(ux.nan, uy.nan), api.Or(api.And(ux.inf, uy.zero), api.And(ux.zero, uy.inf)))
+	return a.selectFloat(nan, a.nan(), res)
+}
+
+// IsNaN returns 1 if x is NaN and 0 otherwise.
+func (a *Arithmetic) IsNaN(x Float) frontend.Variable {
+	return a.unpack(x).nan
+}
+
+// IsLess returns 1 if x < y and 0 otherwise. As in IEEE-754, the comparisons
+// with NaN are false.
+func (a *Arithmetic) IsLess(x, y Float) frontend.Variable {
+	kx, ky, ordered := a.keys(x, y)
+	return a.api.And(ordered, a.isNegative(a.api.Sub(kx, ky), a.f.nbBits()))
+}
+
+// IsEqual returns 1 if x = y and 0 otherwise. As in IEEE-754, +0 and -0 are
+// equal and NaN isn't equal to itself. Use [frontend.API.AssertIsEqual] on the
+// encodings to compare the numbers bit-exactly.
+func (a *Arithmetic) IsEqual(x, y Float) frontend.Variable {
+	kx, ky, ordered := a.keys(x, y)
+	return a.api.And(ordered, a.api.IsZero(a.api.Sub(kx, ky)))
+}
+
+// keys returns integers ordered as x and y, and whether none of them is NaN.
+// The key of x is its signed magnitude.
+func (a *Arithmetic) keys(x, y Float) (kx, ky, ordered frontend.Variable) {
+	ux, uy := a.unpack(x), a.unpack(y)
+	return a.key(ux), a.key(uy), a.api.Sub(1, a.api.Or(ux.nan, uy.nan))
+}
+
+func (a *Arithmetic) key(u unpacked) frontend.Variable {
+	magnitude := a.api.Add(u.mant, a.api.Mul(u.exp, pow2(a.f.NbMantissaBits)))
+	return a.api.Select(u.sign, a.api.Neg(magnitude), magnitude)
+}
+
+// round returns the magnitude of the number p·2^k rounded to nearest with ties
+// to even, where 0 ≤ p < 2^nbBits and |k| < 2^nbExpBits, with nbBits+bias <
+// 2^nbExpBits.
+func (a *Arithmetic) round(p frontend.Variable, nbBits int, k frontend.Variable, nbExpBits int) frontend.Variable {
+	api := a.api
+	m := a.f.NbMantissaBits
+	bias := a.f.bias()
+
+	// normalize: 2^(l-1) ≤ p < 2^l. p is replaced by 1 when it is zero, which
+	// is handled at the end.
+	pZero := api.IsZero(p)
+	p = api.Add(p, pZero)
+	res, err := api.Compiler().NewHint(bitLenHint, 1, p)
+	if err != nil {
+		panic(err)
+	}
+	l := res[0]
+	nbLenBits := mbits.Len(uint(nbBits))
+	lead := a.pow2(api.Sub(l, 1), nbLenBits)
+	a.checker.Check(api.Sub(p, lead), nbBits+1)
+	a.checker.Check(api.Sub(api.Sub(api.Mul(lead, 2), 1), p), nbBits+1)
+
+	// the biased exponent of the leading bit. The result is subnormal if it is
+	// smaller than 1, and then the exponent is the one of the subnormals.
+	eb := api.Add(l, k, bias-1)
+	subnormal := a.isNegative(api.Sub(eb, 1), nbExpBits+2)
+	ex := api.Select(subnormal, 1, eb)
+	// the number of bits to drop to keep m+1 significant bits, or to keep the
+	// bits down to 2^(1-bias-m) for the subnormals. When negative, the
+	// significand is shifted left.
+	s := api.Select(subnormal, api.Sub(1-bias-m, k), api.Sub(l, m+1))
+	left := a.isNegative(s, nbExpBits+2)
+	p = api.Mul(p, a.pow2(api.Select(left, api.Neg(s), 0), mbits.Len(uint(m))))
+	s = api.Select(left, 0, s)
+	// dropping more than nbBits+1 bits always rounds to zero
+	maxShift := nbBits + 1
+	s = api.Select(a.isNegative(api.Sub(s, maxShift), nbExpBits+2), s, maxShift)
+
+	// p = q·2^s + r with 0 ≤ r < 2^s
+	div := a.pow2(s, mbits.Len(uint(maxShift)))
+	res, err = api.Compiler().NewHint(divHint, 2, p, div)
+	if err != nil {
+		panic(err)
+	}
+	q, r := res[0], res[1]
+	a.checker.Check(q, nbBits+m)
+	a.checker.Check(r, maxShift)
+	a.checker.Check(api.Sub(api.Sub(div, 1), r), maxShift)
+	api.AssertIsEqual(p, api.Add(api.Mul(q, div), r))
+	// the parity of q for the ties
+	res, err = api.Compiler().NewHint(divHint, 2, q, 2)
+	if err != nil {
+		panic(err)
+	}
+	half, odd := res[0], res[1]
+	api.AssertIsBoolean(odd)
+	a.checker.Check(half, nbBits+m)
+	api.AssertIsEqual(q, api.Add(api.Mul(half, 2), odd))
+	// round up if r > 2^(s-1), or r = 2^(s-1) and q is odd
+	diff := api.Sub(api.Mul(r, 2), div)
+	tie := api.IsZero(diff)
+	above := api.Sub(1, api.Add(tie, a.isNegative(diff, maxShift+1)))
+	sig := api.Add(q, above, api.Mul(tie, odd))
+
+	// the carry of the rounding into the exponent is handled by the addition,
+	// the overflows give the infinity.
+	magnitude := api.Add(api.Mul(api.Sub(ex, 1), pow2(m)), sig)
+	overflow := api.Sub(1, a.isNegative(api.Sub(magnitude, a.infinity()), nbExpBits+m+3))
+	magnitude = api.Select(overflow, a.infinity(), magnitude)
+	return api.Select(pZero, 0, magnitude)
+}
+
+// isNegative returns 1 if x < 0 and 0 otherwise, where |x| < 2^nbBits.
+func (a *Arithmetic) isNegative(x frontend.Variable, nbBits int) frontend.Variable {
+	res, err := a.api.Compiler().NewHint(isNegativeHint, 1, x)
+	if err != nil {
+		panic(err)
+	}
+	neg := res[0]
+	a.api.AssertIsBoolean(neg)
+	// -x-1 is non-negative if x is negative
+	a.checker.Check(a.api.Select(neg, a.api.Sub(-1, x), x), nbBits)
+	return neg
+}
+
+// pow2 returns 2^x where 0 ≤ x < 2^nbBits.
+func (a *Arithmetic) pow2(x frontend.Variable, nbBits int) frontend.Variable {
+	xBits := bits.ToBinary(a.api, x, bits.WithNbDigits(nbBits))
+	var res frontend.Variable = 1
+	for i := range xBits {
+		factor := new(big.Int).Sub(pow2(1<<i), big.NewInt(1))
+		res = a.api.Mul(res, a.api.Add(1, a.api.Mul(xBits[i], factor)))
+	}
+	return res
+}
+
+func (a *Arithmetic) selectFloat(cond frontend.Variable, x, y Float) Float {
+	return Float{V: a.api.Select(cond, x.V, y.V)}
+}
+
+func pow2(e int) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(e))
+}
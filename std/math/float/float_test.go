@@ -0,0 +1,133 @@
+package float
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type opsCircuit struct {
+	X, Y            Float
+	Sum, Diff, Prod Float
+	Less, Equal     frontend.Variable
+	format          Format
+}
+
+func (c *opsCircuit) Define(api frontend.API) error {
+	f, err := New(api, c.format)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(f.Add(c.X, c.Y).V, c.Sum.V)
+	api.AssertIsEqual(f.Sub(c.X, c.Y).V, c.Diff.V)
+	api.AssertIsEqual(f.Mul(c.X, c.Y).V, c.Prod.V)
+	api.AssertIsEqual(f.IsLess(c.X, c.Y), c.Less)
+	api.AssertIsEqual(f.IsEqual(c.X, c.Y), c.Equal)
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func canonical32(x float32) Float {
+	if x != x {
+		return ValueOf32(float32(math.NaN()))
+	}
+	return ValueOf32(x)
+}
+
+func canonical64(x float64) Float {
+	if x != x {
+		return Float{V: uint64(0x7ff8000000000000)}
+	}
+	return ValueOf64(x)
+}
+
+func assignment32(x, y float32) *opsCircuit {
+	return &opsCircuit{
+		X: ValueOf32(x), Y: ValueOf32(y),
+		Sum: canonical32(x + y), Diff: canonical32(x - y), Prod: canonical32(x * y),
+		Less: boolToInt(x < y), Equal: boolToInt(x == y),
+	}
+}
+
+func assignment64(x, y float64) *opsCircuit {
+	return &opsCircuit{
+		X: ValueOf64(x), Y: ValueOf64(y),
+		Sum: canonical64(x + y), Diff: canonical64(x - y), Prod: canonical64(x * y),
+		Less: boolToInt(x < y), Equal: boolToInt(x == y),
+	}
+}
+
+func specials32() []float32 {
+	inf := float32(math.Inf(1))
+	return []float32{
+		0, float32(math.Copysign(0, -1)), 1, -1, 1.5, 3, 0.1, -2.75,
+		inf, -inf, float32(math.NaN()),
+		math.MaxFloat32, -math.MaxFloat32, math.SmallestNonzeroFloat32,
+		-math.SmallestNonzeroFloat32, math.Float32frombits(0x00800000), math.Float32frombits(0x007fffff),
+		1 + 1.0/(1<<23), 1 << 24, 1<<24 + 2, 3.0 / (1 << 25),
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	assert := test.NewAssert(t)
+	values := specials32()
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 -- test only
+	for i := 0; i < 20; i++ {
+		values = append(values, math.Float32frombits(rng.Uint32()))
+	}
+	for _, x := range values {
+		for _, y := range values {
+			assert.Run(func(assert *test.Assert) {
+				err := test.IsSolved(&opsCircuit{format: Float32}, assignment32(x, y), ecc.BN254.ScalarField())
+				assert.NoError(err)
+			}, fmt.Sprintf("%x/%x", math.Float32bits(x), math.Float32bits(y)))
+		}
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	assert := test.NewAssert(t)
+	inf := math.Inf(1)
+	values := []float64{
+		0, math.Copysign(0, -1), 1, -0.1, 0.3, inf, -inf, math.NaN(),
+		math.MaxFloat64, math.SmallestNonzeroFloat64, math.Float64frombits(0x000fffffffffffff),
+		1 + 1.0/(1<<52), 1 << 53,
+	}
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 -- test only
+	for i := 0; i < 10; i++ {
+		values = append(values, math.Float64frombits(rng.Uint64()))
+	}
+	for _, x := range values {
+		for _, y := range values {
+			assert.Run(func(assert *test.Assert) {
+				err := test.IsSolved(&opsCircuit{format: Float64}, assignment64(x, y), ecc.BN254.ScalarField())
+				assert.NoError(err)
+			}, fmt.Sprintf("%x/%x", math.Float64bits(x), math.Float64bits(y)))
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	assert := test.NewAssert(t)
+	// 1 + 2^-24 is a tie rounded to the even 1, 1 + 3·2^-24 to 1 + 2^-22
+	valid := assignment32(1, 1.0/(1<<24))
+	invalid := assignment32(1, 1.0/(1<<24))
+	invalid.Sum = ValueOf32(1 + 1.0/(1<<23))
+	assert.CheckCircuit(&opsCircuit{format: Float32},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(valid),
+		test.WithValidAssignment(assignment32(1, 3.0/(1<<24))),
+		test.WithInvalidAssignment(invalid),
+	)
+}
@@ -0,0 +1,82 @@
+package float
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		unpackHint,
+		divHint,
+		bitLenHint,
+		isNegativeHint,
+	}
+}
+
+// unpackHint returns the mantissa, the exponent and the sign of the encoding
+// given as first input, the numbers of bits of the mantissa and of the
+// exponent being the second and third inputs.
+func unpackHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 3 || len(outputs) != 3 {
+		return fmt.Errorf("expecting three inputs and three outputs")
+	}
+	if !inputs[1].IsUint64() || !inputs[2].IsUint64() {
+		return fmt.Errorf("invalid numbers of bits")
+	}
+	nbMantissaBits, nbExponentBits := uint(inputs[1].Uint64()), uint(inputs[2].Uint64())
+	x := new(big.Int).Set(inputs[0])
+	mask := func(n uint) *big.Int {
+		return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), n), big.NewInt(1))
+	}
+	outputs[0].And(x, mask(nbMantissaBits))
+	x.Rsh(x, nbMantissaBits)
+	outputs[1].And(x, mask(nbExponentBits))
+	outputs[2].Rsh(x, nbExponentBits)
+	return nil
+}
+
+// divHint returns the quotient and the remainder of the division of the
+// non-negative inputs.
+func divHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 2 {
+		return fmt.Errorf("expecting two inputs and two outputs")
+	}
+	if inputs[1].Sign() == 0 {
+		return fmt.Errorf("division by zero")
+	}
+	outputs[0].QuoRem(inputs[0], inputs[1], outputs[1])
+	return nil
+}
+
+// bitLenHint returns the bit-length of the input.
+func bitLenHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return fmt.Errorf("expecting one input and one output")
+	}
+	outputs[0].SetInt64(int64(inputs[0].BitLen()))
+	return nil
+}
+
+// isNegativeHint returns 1 if the input is larger than half of the modulus,
+// that is it represents a negative number, and 0 otherwise.
+func isNegativeHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return fmt.Errorf("expecting one input and one output")
+	}
+	half := new(big.Int).Rsh(mod, 1)
+	if inputs[0].Cmp(half) > 0 {
+		outputs[0].SetUint64(1)
+	} else {
+		outputs[0].SetUint64(0)
+	}
+	return nil
+}
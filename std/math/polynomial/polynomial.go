@@ -0,0 +1,174 @@
+// Package polynomial implements evaluation and interpolation of univariate and
+// multilinear polynomials over emulated fields.
+//
+// The package mirrors the native [github.com/consensys/gnark/std/polynomial]
+// package, but the coefficients and evaluation points are elements of an
+// emulated field. This allows to verify proof systems whose challenges live in
+// a field different from the native scalar field of the SNARK curve.
+package polynomial
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Univariate defines a univariate polynomial by its coefficients in the
+// monomial basis, starting from the constant term.
+type Univariate[FR emulated.FieldParams] []emulated.Element[FR]
+
+// Multilinear defines a multilinear polynomial by its evaluations on the
+// boolean hypercube. The first variable is the most significant bit of the
+// index.
+type Multilinear[FR emulated.FieldParams] []emulated.Element[FR]
+
+// FromSlice maps the slice of emulated elements to a slice of pointers to
+// emulated elements. It is a convenience method to be used with the methods
+// of [Polynomial].
+func FromSlice[FR emulated.FieldParams](in []emulated.Element[FR]) []*emulated.Element[FR] {
+	r := make([]*emulated.Element[FR], len(in))
+	for i := range in {
+		r[i] = &in[i]
+	}
+	return r
+}
+
+// ValueOf returns a slice of emulated elements initialized from the constant
+// values. It is a convenience method for assigning the witness.
+func ValueOf[FR emulated.FieldParams](in []*big.Int) []emulated.Element[FR] {
+	r := make([]emulated.Element[FR], len(in))
+	for i := range in {
+		r[i] = emulated.ValueOf[FR](in[i])
+	}
+	return r
+}
+
+// Polynomial implements polynomial operations over the emulated field FR.
+type Polynomial[FR emulated.FieldParams] struct {
+	api frontend.API
+	f   *emulated.Field[FR]
+}
+
+// New returns a new [Polynomial] instance for the emulated field FR.
+func New[FR emulated.FieldParams](api frontend.API) (*Polynomial[FR], error) {
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	return &Polynomial[FR]{
+		api: api,
+		f:   f,
+	}, nil
+}
+
+// EvalUnivariate evaluates the univariate polynomial P at the point at using
+// Horner's rule.
+func (p *Polynomial[FR]) EvalUnivariate(P Univariate[FR], at *emulated.Element[FR]) *emulated.Element[FR] {
+	if len(P) == 0 {
+		return p.f.Zero()
+	}
+	res := &P[len(P)-1]
+	for i := len(P) - 2; i >= 0; i-- {
+		res = p.f.MulMod(res, at)
+		res = p.f.Add(res, &P[i])
+	}
+	return p.f.Reduce(res)
+}
+
+// EvalMultilinear evaluates the multilinear polynomial M at the point at. It
+// returns an error if len(M) != 2^len(at).
+func (p *Polynomial[FR]) EvalMultilinear(M Multilinear[FR], at []*emulated.Element[FR]) (*emulated.Element[FR], error) {
+	if len(M) != 1<<len(at) {
+		return nil, fmt.Errorf("incompatible evaluation vector size: %d != 2^%d", len(M), len(at))
+	}
+	m := FromSlice([]emulated.Element[FR](M))
+	for _, x := range at {
+		half := len(m) / 2
+		folded := make([]*emulated.Element[FR], half)
+		for j := 0; j < half; j++ {
+			// m[j] + (m[j+half] - m[j]) * x
+			diff := p.f.Sub(m[j+half], m[j])
+			folded[j] = p.f.Add(m[j], p.f.MulMod(diff, x))
+		}
+		m = folded
+	}
+	return p.f.Reduce(m[0]), nil
+}
+
+// EvalEqual returns Πᵢ Eq(xᵢ, yᵢ) = Πᵢ (xᵢyᵢ + (1-xᵢ)(1-yᵢ)). It returns an
+// error if the lengths of x and y do not match.
+func (p *Polynomial[FR]) EvalEqual(x, y []*emulated.Element[FR]) (*emulated.Element[FR], error) {
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("mismatching input lengths %d != %d", len(x), len(y))
+	}
+	eq := p.f.One()
+	for i := range x {
+		// 1 + 2xy - x - y
+		xy := p.f.MulMod(x[i], y[i])
+		next := p.f.Add(xy, xy)
+		next = p.f.Add(next, p.f.One())
+		next = p.f.Sub(next, x[i])
+		next = p.f.Sub(next, y[i])
+		eq = p.f.MulMod(eq, next)
+	}
+	return eq, nil
+}
+
+// InterpolateLDE fits a polynomial f of degree len(values)-1 such that f(i) =
+// values[i] for i ∈ [0, len(values)) and returns f(at).
+func (p *Polynomial[FR]) InterpolateLDE(at *emulated.Element[FR], values []*emulated.Element[FR]) *emulated.Element[FR] {
+	n := len(values)
+	if n == 0 {
+		return p.f.Zero()
+	}
+	// atMinus[i] = at - i
+	atMinus := make([]*emulated.Element[FR], n)
+	for i := range atMinus {
+		atMinus[i] = p.f.Sub(at, p.f.NewElement(i))
+	}
+	// prefix[i] = Π_{j<i} (at - j) and suffix[i] = Π_{j>i} (at - j)
+	prefix := make([]*emulated.Element[FR], n)
+	suffix := make([]*emulated.Element[FR], n)
+	prefix[0] = p.f.One()
+	for i := 1; i < n; i++ {
+		prefix[i] = p.f.MulMod(prefix[i-1], atMinus[i-1])
+	}
+	suffix[n-1] = p.f.One()
+	for i := n - 2; i >= 0; i-- {
+		suffix[i] = p.f.MulMod(suffix[i+1], atMinus[i+1])
+	}
+	// the Lagrange denominators Π_{j≠i} (i - j) are constants and we compute
+	// their inverses out-of-circuit.
+	var fr FR
+	denoms := lagrangeDenominatorsInv(n, fr.Modulus())
+	res := p.f.Zero()
+	for i := range values {
+		term := p.f.MulMod(prefix[i], suffix[i])
+		term = p.f.MulMod(term, p.f.NewElement(denoms[i]))
+		term = p.f.MulMod(term, values[i])
+		res = p.f.Add(res, term)
+	}
+	return p.f.Reduce(res)
+}
+
+// lagrangeDenominatorsInv returns the inverses of Π_{j≠i} (i - j) modulo the
+// modulus for i ∈ [0, n).
+func lagrangeDenominatorsInv(n int, modulus *big.Int) []*big.Int {
+	res := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		d := big.NewInt(1)
+		for j := 0; j < n; j++ {
+			if i != j {
+				d.Mul(d, big.NewInt(int64(i-j)))
+			}
+		}
+		d.Mod(d, modulus)
+		if d.ModInverse(d, modulus) == nil {
+			panic("interpolation domain not invertible in the field")
+		}
+		res[i] = d
+	}
+	return res
+}
@@ -0,0 +1,221 @@
+package polynomial
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+func randElements[FR emulated.FieldParams](n int) []*big.Int {
+	var fr FR
+	res := make([]*big.Int, n)
+	for i := range res {
+		v, err := rand.Int(rand.Reader, fr.Modulus())
+		if err != nil {
+			panic(err)
+		}
+		res[i] = v
+	}
+	return res
+}
+
+type univariateCircuit[FR emulated.FieldParams] struct {
+	P        Univariate[FR]
+	At       emulated.Element[FR]
+	Expected emulated.Element[FR]
+}
+
+func (c *univariateCircuit[FR]) Define(api frontend.API) error {
+	p, err := New[FR](api)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return err
+	}
+	res := p.EvalUnivariate(c.P, &c.At)
+	f.AssertIsEqual(res, &c.Expected)
+	return nil
+}
+
+func testUnivariate[FR emulated.FieldParams](t *testing.T) {
+	assert := test.NewAssert(t)
+	var fr FR
+	coeffs := randElements[FR](5)
+	at := randElements[FR](1)[0]
+	expected := new(big.Int)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		expected.Mul(expected, at)
+		expected.Add(expected, coeffs[i])
+		expected.Mod(expected, fr.Modulus())
+	}
+	circuit := univariateCircuit[FR]{P: make(Univariate[FR], len(coeffs))}
+	assignment := univariateCircuit[FR]{
+		P:        ValueOf[FR](coeffs),
+		At:       emulated.ValueOf[FR](at),
+		Expected: emulated.ValueOf[FR](expected),
+	}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+func TestEvalUnivariate(t *testing.T) {
+	testUnivariate[emulated.Secp256k1Fr](t)
+	testUnivariate[emulated.BLS12381Fr](t)
+}
+
+type multilinearCircuit[FR emulated.FieldParams] struct {
+	M        Multilinear[FR]
+	At       []emulated.Element[FR]
+	Expected emulated.Element[FR]
+}
+
+func (c *multilinearCircuit[FR]) Define(api frontend.API) error {
+	p, err := New[FR](api)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return err
+	}
+	res, err := p.EvalMultilinear(c.M, FromSlice(c.At))
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(res, &c.Expected)
+	return nil
+}
+
+func TestEvalMultilinear(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fr emulated.Secp256k1Fr
+	mod := fr.Modulus()
+	m := randElements[emulated.Secp256k1Fr](8)
+	at := randElements[emulated.Secp256k1Fr](3)
+	// fold the evaluations out-of-circuit
+	folded := make([]*big.Int, len(m))
+	copy(folded, m)
+	for _, x := range at {
+		half := len(folded) / 2
+		for j := 0; j < half; j++ {
+			diff := new(big.Int).Sub(folded[j+half], folded[j])
+			diff.Mul(diff, x)
+			folded[j] = diff.Add(diff, folded[j]).Mod(diff, mod)
+		}
+		folded = folded[:half]
+	}
+	circuit := multilinearCircuit[emulated.Secp256k1Fr]{
+		M:  make(Multilinear[emulated.Secp256k1Fr], len(m)),
+		At: make([]emulated.Element[emulated.Secp256k1Fr], len(at)),
+	}
+	assignment := multilinearCircuit[emulated.Secp256k1Fr]{
+		M:        ValueOf[emulated.Secp256k1Fr](m),
+		At:       ValueOf[emulated.Secp256k1Fr](at),
+		Expected: emulated.ValueOf[emulated.Secp256k1Fr](folded[0]),
+	}
+	err := test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type equalCircuit[FR emulated.FieldParams] struct {
+	X, Y     []emulated.Element[FR]
+	Expected emulated.Element[FR]
+}
+
+func (c *equalCircuit[FR]) Define(api frontend.API) error {
+	p, err := New[FR](api)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return err
+	}
+	res, err := p.EvalEqual(FromSlice(c.X), FromSlice(c.Y))
+	if err != nil {
+		return err
+	}
+	f.AssertIsEqual(res, &c.Expected)
+	return nil
+}
+
+func TestEvalEqual(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := equalCircuit[emulated.Secp256k1Fr]{
+		X: make([]emulated.Element[emulated.Secp256k1Fr], 3),
+		Y: make([]emulated.Element[emulated.Secp256k1Fr], 3),
+	}
+	bits := func(v ...int64) []*big.Int {
+		r := make([]*big.Int, len(v))
+		for i := range v {
+			r[i] = big.NewInt(v[i])
+		}
+		return r
+	}
+	assignment := equalCircuit[emulated.Secp256k1Fr]{
+		X:        ValueOf[emulated.Secp256k1Fr](bits(1, 0, 1)),
+		Y:        ValueOf[emulated.Secp256k1Fr](bits(1, 0, 1)),
+		Expected: emulated.ValueOf[emulated.Secp256k1Fr](1),
+	}
+	assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField()))
+	assignment.Y = ValueOf[emulated.Secp256k1Fr](bits(1, 1, 1))
+	assignment.Expected = emulated.ValueOf[emulated.Secp256k1Fr](0)
+	assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField()))
+}
+
+type interpolateCircuit[FR emulated.FieldParams] struct {
+	Values   []emulated.Element[FR]
+	At       emulated.Element[FR]
+	Expected emulated.Element[FR]
+}
+
+func (c *interpolateCircuit[FR]) Define(api frontend.API) error {
+	p, err := New[FR](api)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return err
+	}
+	res := p.InterpolateLDE(&c.At, FromSlice(c.Values))
+	f.AssertIsEqual(res, &c.Expected)
+	return nil
+}
+
+func TestInterpolateLDE(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fr emulated.Secp256k1Fr
+	mod := fr.Modulus()
+	// f(x) = 3x² + 2x + 1
+	eval := func(x *big.Int) *big.Int {
+		r := new(big.Int).Mul(x, x)
+		r.Mul(r, big.NewInt(3))
+		r.Add(r, new(big.Int).Mul(x, big.NewInt(2)))
+		r.Add(r, big.NewInt(1))
+		return r.Mod(r, mod)
+	}
+	values := make([]*big.Int, 3)
+	for i := range values {
+		values[i] = eval(big.NewInt(int64(i)))
+	}
+	at := randElements[emulated.Secp256k1Fr](1)[0]
+	circuit := interpolateCircuit[emulated.Secp256k1Fr]{
+		Values: make([]emulated.Element[emulated.Secp256k1Fr], len(values)),
+	}
+	assignment := interpolateCircuit[emulated.Secp256k1Fr]{
+		Values:   ValueOf[emulated.Secp256k1Fr](values),
+		At:       emulated.ValueOf[emulated.Secp256k1Fr](at),
+		Expected: emulated.ValueOf[emulated.Secp256k1Fr](eval(at)),
+	}
+	assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField()))
+	assignment.Expected = emulated.ValueOf[emulated.Secp256k1Fr](eval(new(big.Int).Add(at, big.NewInt(1))))
+	assert.Error(test.IsSolved(&circuit, &assignment, ecc.BN254.ScalarField()))
+}
@@ -0,0 +1,60 @@
+package sqrt
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		isqrtHint,
+		sqrtHint,
+		sqrtWithSignHint,
+	}
+}
+
+func isqrtHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return fmt.Errorf("expecting one input and one output")
+	}
+	outputs[0].Sqrt(inputs[0])
+	return nil
+}
+
+func sqrtHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 2 {
+		return fmt.Errorf("expecting one input and two outputs")
+	}
+	root, ok := fieldSqrt(mod, inputs[0], nonResidue(mod))
+	outputs[0].Set(root)
+	if ok {
+		outputs[1].SetUint64(1)
+	} else {
+		outputs[1].SetUint64(0)
+	}
+	return nil
+}
+
+func sqrtWithSignHint(mod *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 || len(outputs) != 1 {
+		return fmt.Errorf("expecting two inputs and one output")
+	}
+	root, ok := fieldSqrt(mod, inputs[0], nonResidue(mod))
+	if !ok {
+		return fmt.Errorf("%s is not a quadratic residue", inputs[0])
+	}
+	half := new(big.Int).Rsh(mod, 1)
+	if (root.Cmp(half) > 0) != (inputs[1].Sign() != 0) {
+		root.Sub(mod, root).Mod(root, mod)
+	}
+	outputs[0].Set(root)
+	return nil
+}
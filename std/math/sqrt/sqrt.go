@@ -0,0 +1,136 @@
+// Package sqrt implements square roots in-circuit.
+//
+// [Isqrt] computes the integer square root of a bounded integer and [Sqrt] the
+// square root of a native field element. In both cases the root is computed
+// out of the circuit by a hint and only its correctness is constrained.
+package sqrt
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Isqrt returns the integer square root of x, that is the largest s such that
+// s² ≤ x. The caller must ensure that x < 2^nbBits, nbBits must be at least
+// two bits shorter than the native field.
+//
+// The root s is given by a hint and the method enforces that s < 2^⌈nbBits/2⌉
+// and 0 ≤ x - s² ≤ 2s, which is equivalent to s² ≤ x < (s+1)².
+func Isqrt(api frontend.API, x frontend.Variable, nbBits int) frontend.Variable {
+	if nbBits <= 0 || nbBits+2 >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("invalid bound of %d bits", nbBits))
+	}
+	// handle constant case
+	if xc, ok := api.Compiler().ConstantValue(x); ok {
+		if xc.BitLen() > nbBits {
+			panic("input larger than bound")
+		}
+		return new(big.Int).Sqrt(xc)
+	}
+	res, err := api.Compiler().NewHint(isqrtHint, 1, x)
+	if err != nil {
+		panic(err)
+	}
+	s := res[0]
+	nbRootBits := (nbBits + 1) / 2
+
+	rh := rangecheck.New(api)
+	rh.Check(s, nbRootBits)
+	// the remainder r = x - s² is in [0, 2s]. As s² < 2^(nbBits+1), a
+	// negative remainder would wrap around the field and fail the range check.
+	r := api.Sub(x, api.Mul(s, s))
+	rh.Check(r, nbRootBits+1)
+	rh.Check(api.Sub(api.Mul(s, 2), r), nbRootBits+1)
+	return s
+}
+
+// Sqrt returns a square root of x in the native field and whether x is a
+// quadratic residue. When x is not a quadratic residue, isQR is 0 and root is
+// a square root of g·x instead, where g is the smallest quadratic non-residue
+// of the field. Any of the two roots may be returned, the caller must
+// constrain the sign of the root when it matters.
+//
+// The method enforces that isQR is boolean and that root² = x if isQR is 1
+// and root² = g·x otherwise. As g·x is a quadratic non-residue for any
+// non-zero quadratic residue x, the prover can't claim that a square is not.
+func Sqrt(api frontend.API, x frontend.Variable) (root, isQR frontend.Variable) {
+	g := nonResidue(api.Compiler().Field())
+	// handle constant case
+	if xc, ok := api.Compiler().ConstantValue(x); ok {
+		r, ok := fieldSqrt(api.Compiler().Field(), xc, g)
+		if ok {
+			return r, 1
+		}
+		return r, 0
+	}
+	res, err := api.Compiler().NewHint(sqrtHint, 2, x)
+	if err != nil {
+		panic(err)
+	}
+	root, isQR = res[0], res[1]
+	api.AssertIsBoolean(isQR)
+	api.AssertIsEqual(api.Mul(root, root), api.Select(isQR, x, api.Mul(x, g)))
+	return root, isQR
+}
+
+// AssertIsQuadraticResidue asserts that x is a square in the native field and
+// returns one of its square roots.
+func AssertIsQuadraticResidue(api frontend.API, x frontend.Variable) frontend.Variable {
+	root, isQR := Sqrt(api, x)
+	api.AssertIsEqual(isQR, 1)
+	return root
+}
+
+// SqrtWithSign returns the square root of x in the native field which is
+// lexicographically largest if sign is 1 and smallest otherwise, see
+// [IsLexicographicallyLargest]. It asserts that x is a quadratic residue and
+// that sign is boolean. This is the convention of the compressed points of
+// gnark-crypto.
+func SqrtWithSign(api frontend.API, x, sign frontend.Variable) frontend.Variable {
+	res, err := api.Compiler().NewHint(sqrtWithSignHint, 1, x, sign)
+	if err != nil {
+		panic(err)
+	}
+	root := res[0]
+	api.AssertIsEqual(api.Mul(root, root), x)
+	api.AssertIsEqual(IsLexicographicallyLargest(api, root), sign)
+	return root
+}
+
+// IsLexicographicallyLargest returns 1 if x > (p-1)/2, where p is the modulus
+// of the native field, and 0 otherwise. Except for 0, exactly one of x and -x
+// is lexicographically largest.
+//
+// As x ≤ (p-1)/2 if and only if 2x < p, the result is the parity of the
+// canonical representative of 2x, which costs a single binary decomposition.
+func IsLexicographicallyLargest(api frontend.API, x frontend.Variable) frontend.Variable {
+	return bits.ToBinary(api, api.Mul(x, 2))[0]
+}
+
+// nonResidue returns the smallest quadratic non-residue modulo the prime p.
+func nonResidue(p *big.Int) *big.Int {
+	g := big.NewInt(2)
+	for big.Jacobi(g, p) != -1 {
+		g.Add(g, big.NewInt(1))
+	}
+	return g
+}
+
+// fieldSqrt returns a square root of x modulo p and true if x is a quadratic
+// residue, and a square root of g·x and false otherwise.
+func fieldSqrt(p, x, g *big.Int) (*big.Int, bool) {
+	x = new(big.Int).Mod(x, p)
+	res := new(big.Int)
+	if res.ModSqrt(x, p) != nil {
+		return res, true
+	}
+	x.Mul(x, g).Mod(x, p)
+	if res.ModSqrt(x, p) == nil {
+		panic("no square root of the non-residue")
+	}
+	return res, false
+}
@@ -0,0 +1,77 @@
+package sqrt
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type isqrtCircuit struct {
+	X, Expected frontend.Variable
+}
+
+func (c *isqrtCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Isqrt(api, c.X, 64), c.Expected)
+	api.AssertIsEqual(Isqrt(api, 17, 8), 4)
+	return nil
+}
+
+func TestIsqrt(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&isqrtCircuit{},
+		test.WithValidAssignment(&isqrtCircuit{X: 0, Expected: 0}),
+		test.WithValidAssignment(&isqrtCircuit{X: 99, Expected: 9}),
+		test.WithValidAssignment(&isqrtCircuit{X: 100, Expected: 10}),
+		test.WithValidAssignment(&isqrtCircuit{X: uint64(1<<64 - 1), Expected: uint64(1<<32 - 1)}),
+		test.WithInvalidAssignment(&isqrtCircuit{X: 99, Expected: 10}),
+		test.WithInvalidAssignment(&isqrtCircuit{X: 100, Expected: 9}),
+	)
+}
+
+type sqrtCircuit struct {
+	X, IsQR frontend.Variable
+}
+
+func (c *sqrtCircuit) Define(api frontend.API) error {
+	root, isQR := Sqrt(api, c.X)
+	api.AssertIsEqual(isQR, c.IsQR)
+	api.AssertIsEqual(api.Mul(api.Mul(root, root), isQR), api.Mul(c.X, isQR))
+	return nil
+}
+
+func TestSqrt(t *testing.T) {
+	assert := test.NewAssert(t)
+	g := nonResidue(ecc.BN254.ScalarField())
+	assert.CheckCircuit(&sqrtCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&sqrtCircuit{X: 0, IsQR: 1}),
+		test.WithValidAssignment(&sqrtCircuit{X: 16, IsQR: 1}),
+		test.WithValidAssignment(&sqrtCircuit{X: g, IsQR: 0}),
+		test.WithInvalidAssignment(&sqrtCircuit{X: 16, IsQR: 0}),
+		test.WithInvalidAssignment(&sqrtCircuit{X: g, IsQR: 1}),
+	)
+}
+
+type sqrtWithSignCircuit struct {
+	X, Sign, Root frontend.Variable
+}
+
+func (c *sqrtWithSignCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(SqrtWithSign(api, c.X, c.Sign), c.Root)
+	return nil
+}
+
+func TestSqrtWithSign(t *testing.T) {
+	assert := test.NewAssert(t)
+	neg := new(big.Int).Sub(ecc.BN254.ScalarField(), big.NewInt(4))
+	assert.CheckCircuit(&sqrtWithSignCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&sqrtWithSignCircuit{X: 16, Sign: 0, Root: 4}),
+		test.WithValidAssignment(&sqrtWithSignCircuit{X: 16, Sign: 1, Root: neg}),
+		test.WithInvalidAssignment(&sqrtWithSignCircuit{X: 16, Sign: 1, Root: 4}),
+		test.WithInvalidAssignment(&sqrtWithSignCircuit{X: 16, Sign: 0, Root: neg}),
+	)
+}
@@ -37,10 +37,11 @@ func toBytes(m *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 		return fmt.Errorf("first input must be uint64")
 	}
 	nbLimbs := int(inputs[0].Uint64())
-	if len(outputs) != nbLimbs {
-		return fmt.Errorf("output must be 8 elements")
+	// an additional output receives the remaining high part of the input
+	if len(outputs) != nbLimbs && len(outputs) != nbLimbs+1 {
+		return fmt.Errorf("output must be %d or %d elements", nbLimbs, nbLimbs+1)
 	}
-	if !inputs[1].IsUint64() {
+	if len(outputs) == nbLimbs && !inputs[1].IsUint64() {
 		return fmt.Errorf("input must be 64 bits")
 	}
 	base := new(big.Int).Lsh(big.NewInt(1), uint(8))
@@ -49,5 +50,8 @@ func toBytes(m *big.Int, inputs []*big.Int, outputs []*big.Int) error {
 		outputs[i].Mod(tmp, base)
 		tmp.Rsh(tmp, 8)
 	}
+	if len(outputs) > nbLimbs {
+		outputs[nbLimbs].Set(tmp)
+	}
 	return nil
 }
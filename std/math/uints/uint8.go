@@ -24,6 +24,8 @@ package uints
 
 import (
 	"fmt"
+	"math/big"
+	"math/bits"
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/internal/logderivprecomp"
@@ -254,17 +256,43 @@ func (bf *BinaryField[T]) Not(a T) T {
 	return r
 }
 
+// Add returns the sum of a modulo 2^(8*len(T)). At least two inputs are required.
 func (bf *BinaryField[T]) Add(a ...T) T {
 	va := make([]frontend.Variable, len(a))
 	for i := range a {
 		va[i] = bf.ToValue(a[i])
 	}
-	vres := bf.api.Add(va[0], va[1], va[2:]...)
-	res := bf.ValueOf(vres)
-	// TODO: should also check the that carry we omitted is correct.
+	res, _ := bf.add(va)
 	return res
 }
 
+// AddWithCarry returns the sum a+b+carryIn modulo 2^(8*len(T)) and the
+// outgoing carry bit. The incoming carry carryIn must be boolean, which allows
+// to chain the additions of multi-word integers.
+func (bf *BinaryField[T]) AddWithCarry(a, b T, carryIn frontend.Variable) (sum T, carryOut frontend.Variable) {
+	bf.api.AssertIsBoolean(carryIn)
+	return bf.add([]frontend.Variable{bf.ToValue(a), bf.ToValue(b), carryIn})
+}
+
+// add decomposes the sum of va into len(T) bytes and a carry, which is smaller
+// than len(va).
+func (bf *BinaryField[T]) add(va []frontend.Variable) (T, frontend.Variable) {
+	vres := bf.api.Add(va[0], va[1], va[2:]...)
+	var res T
+	bts, err := bf.api.Compiler().NewHint(toBytes, len(res)+1, len(res), vres)
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < len(res); i++ {
+		res[i] = bf.ByteValueOf(bts[i])
+	}
+	carry := bts[len(res)]
+	bf.rchecker.Check(carry, bits.Len(uint(len(va)-1)))
+	base := new(big.Int).Lsh(big.NewInt(1), uint(8*len(res)))
+	bf.api.AssertIsEqual(vres, bf.api.Add(bf.ToValue(res), bf.api.Mul(carry, base)))
+	return res, carry
+}
+
 func (bf *BinaryField[T]) Lrot(a T, c int) T {
 	l := len(a)
 	if c < 0 {
@@ -292,6 +320,41 @@ func (bf *BinaryField[T]) Lrot(a T, c int) T {
 	return ret
 }
 
+// Rrot returns a rotated right by c bits. Negative c rotates left.
+func (bf *BinaryField[T]) Rrot(a T, c int) T {
+	return bf.Lrot(a, -c)
+}
+
+// Lshift returns a shifted left by c bits, the shifted out bits are discarded.
+func (bf *BinaryField[T]) Lshift(a T, c int) T {
+	l := len(a)
+	shiftBl := c / 8
+	shiftBt := c % 8
+	var ret T
+	for i := 0; i < shiftBl && i < l; i++ {
+		ret[i] = NewU8(0)
+	}
+	if shiftBl >= l {
+		return ret
+	}
+	if shiftBt == 0 {
+		for i := shiftBl; i < l; i++ {
+			ret[i] = a[i-shiftBl]
+		}
+		return ret
+	}
+	partitioned := make([][2]frontend.Variable, l-shiftBl)
+	for i := range partitioned {
+		lower, upper := bitslice.Partition(bf.api, a[i].Val, uint(8-shiftBt), bitslice.WithNbDigits(8))
+		partitioned[i] = [2]frontend.Variable{lower, upper}
+	}
+	ret[shiftBl].Val = bf.api.Mul(1<<shiftBt, partitioned[0][0])
+	for i := 1; i < len(partitioned); i++ {
+		ret[i+shiftBl].Val = bf.api.Add(bf.api.Mul(1<<shiftBt, partitioned[i][0]), partitioned[i-1][1])
+	}
+	return ret
+}
+
 func (bf *BinaryField[T]) Rshift(a T, c int) T {
 	shiftBl := c / 8
 	shiftBt := c % 8
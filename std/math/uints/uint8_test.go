@@ -52,6 +52,45 @@ func TestLeftRotation(t *testing.T) {
 	assert.NoError(err)
 }
 
+type addCircuit struct {
+	In       []U32
+	Expected U32
+}
+
+func (c *addCircuit) Define(api frontend.API) error {
+	uapi, err := New[U32](api)
+	if err != nil {
+		return err
+	}
+	res := uapi.Add(c.In...)
+	uapi.AssertEq(res, c.Expected)
+	return nil
+}
+
+func TestAdd(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, in := range [][]uint32{
+		{0x12345678, 0x9abcdef0},
+		{0xffffffff, 0x00000001},
+		{0xffffffff, 0xffffffff, 0xffffffff},
+		{0xffffffff, 0xffffffff, 0xffffffff, 0xffffffff, 0xffffffff},
+		{0, 0},
+	} {
+		var sum uint32
+		witness := addCircuit{In: make([]U32, len(in))}
+		for i := range in {
+			witness.In[i] = NewU32(in[i])
+			sum += in[i]
+		}
+		witness.Expected = NewU32(sum)
+		err := test.IsSolved(&addCircuit{In: make([]U32, len(in))}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, in)
+	}
+	// the carry is checked, so a result off by a multiple of 2^32 is rejected
+	err := test.IsSolved(&addCircuit{In: make([]U32, 2)}, &addCircuit{In: []U32{NewU32(0xffffffff), NewU32(2)}, Expected: NewU32(0xffffffff)}, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
 type rshiftCircuit struct {
 	In, Expected U32
 	Shift        int
@@ -79,3 +118,87 @@ func TestRshift(t *testing.T) {
 	err = test.IsSolved(&rshiftCircuit{Shift: 11}, &rshiftCircuit{Shift: 11, In: NewU32(0x12345678), Expected: NewU32(0x12345678 >> 11)}, ecc.BN254.ScalarField())
 	assert.NoError(err)
 }
+
+type lshiftCircuit struct {
+	In, Expected U32
+	Shift        int
+}
+
+func (c *lshiftCircuit) Define(api frontend.API) error {
+	uapi, err := New[U32](api)
+	if err != nil {
+		return err
+	}
+	res := uapi.Lshift(c.In, c.Shift)
+	uapi.AssertEq(res, c.Expected)
+	return nil
+}
+
+func TestLshift(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, shift := range []int{0, 3, 4, 8, 11, 12, 16, 31, 32} {
+		err := test.IsSolved(&lshiftCircuit{Shift: shift}, &lshiftCircuit{Shift: shift, In: NewU32(0x12345678), Expected: NewU32(0x12345678 << shift)}, ecc.BN254.ScalarField())
+		assert.NoError(err, shift)
+	}
+}
+
+type rrotCircuit struct {
+	In, Expected U64
+	Shift        int
+}
+
+func (c *rrotCircuit) Define(api frontend.API) error {
+	uapi, err := New[U64](api)
+	if err != nil {
+		return err
+	}
+	res := uapi.Rrot(c.In, c.Shift)
+	uapi.AssertEq(res, c.Expected)
+	return nil
+}
+
+func TestRightRotation(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, shift := range []int{1, 8, 14, 28, 41, -3} {
+		err := test.IsSolved(&rrotCircuit{Shift: shift}, &rrotCircuit{Shift: shift, In: NewU64(0x0123456789abcdef), Expected: NewU64(bits.RotateLeft64(0x0123456789abcdef, -shift))}, ecc.BN254.ScalarField())
+		assert.NoError(err, shift)
+	}
+}
+
+type addWithCarryCircuit struct {
+	A, B, Sum [2]U32
+	CarryOut  frontend.Variable
+}
+
+func (c *addWithCarryCircuit) Define(api frontend.API) error {
+	uapi, err := New[U32](api)
+	if err != nil {
+		return err
+	}
+	// 64-bit addition from 32-bit words
+	lo, carry := uapi.AddWithCarry(c.A[0], c.B[0], 0)
+	hi, carry := uapi.AddWithCarry(c.A[1], c.B[1], carry)
+	uapi.AssertEq(lo, c.Sum[0])
+	uapi.AssertEq(hi, c.Sum[1])
+	api.AssertIsEqual(carry, c.CarryOut)
+	return nil
+}
+
+func TestAddWithCarry(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range [][2]uint64{
+		{0x0123456789abcdef, 0xfedcba9876543210},
+		{0x00000000ffffffff, 0x0000000000000001},
+		{0xffffffffffffffff, 0xffffffffffffffff},
+		{0, 0},
+	} {
+		sum, carry := bits.Add64(tc[0], tc[1], 0)
+		split := func(v uint64) [2]U32 { return [2]U32{NewU32(uint32(v)), NewU32(uint32(v >> 32))} }
+		err := test.IsSolved(&addWithCarryCircuit{}, &addWithCarryCircuit{A: split(tc[0]), B: split(tc[1]), Sum: split(sum), CarryOut: carry}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+	err := test.IsSolved(&addWithCarryCircuit{}, &addWithCarryCircuit{
+		A: [2]U32{NewU32(0xffffffff), NewU32(0)}, B: [2]U32{NewU32(1), NewU32(0)}, Sum: [2]U32{NewU32(0), NewU32(0)}, CarryOut: 0,
+	}, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
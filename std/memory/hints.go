@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{loadHint}
+}
+
+// loadHint returns the value of the last store at the address given as first
+// input, or zero. The other inputs are the pairs (address, value) of the
+// stores in order.
+func loadHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs)%2 != 1 || len(outputs) != 1 {
+		return fmt.Errorf("expecting an address, pairs of inputs and one output")
+	}
+	outputs[0].SetUint64(0)
+	for i := len(inputs) - 2; i > 0; i -= 2 {
+		if inputs[i].Cmp(inputs[0]) == 0 {
+			outputs[0].Set(inputs[i+1])
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package memory implements a read-write memory in-circuit.
+//
+// The circuit issues [Memory.Load] and [Memory.Store] operations on variable
+// addresses, as a virtual machine would. The loaded values are given by a hint
+// and their consistency is proven once the circuit is defined, with the
+// offline memory checking of [BCG+13]: the trace of the operations is sorted
+// by address and then by time (see [sort.SortBy]) and every load in the
+// sorted trace must return the value of the previous operation on the same
+// address, or zero if there is none.
+//
+// The consistency check is deferred, and uses range checks and a commitment.
+// As the deferred functions are called in order, the memory must be created
+// before using the gadgets which also defer range checks or commitments, for
+// example at the beginning of Define.
+//
+// [BCG+13]: https://eprint.iacr.org/2013/507
+package memory
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/sort"
+)
+
+// Memory is a read-write memory of native field elements, addressed by
+// integers of nbAddressBits bits. All the words are initially zero.
+type Memory struct {
+	api           frontend.API
+	nbAddressBits int
+	checker       frontend.Rangechecker
+	// ops is the trace of the operations, in rows (address, value, isStore).
+	ops    [][]frontend.Variable
+	stores []frontend.Variable
+}
+
+// New returns a new memory with addresses of nbAddressBits bits, and defers
+// the consistency check.
+func New(api frontend.API, nbAddressBits int) *Memory {
+	m := &Memory{api: api, nbAddressBits: nbAddressBits}
+	// the check must be deferred before the range checker commits.
+	api.Compiler().Defer(m.check)
+	m.checker = rangecheck.New(api)
+	return m
+}
+
+// Load returns the value stored at address addr.
+//
+// The value is computed by a hint whose inputs are all the previous stores, so
+// the size of the hint inputs is quadratic in the number of operations. The
+// number of constraints is linear.
+func (m *Memory) Load(addr frontend.Variable) frontend.Variable {
+	m.checker.Check(addr, m.nbAddressBits)
+	res, err := m.api.Compiler().NewHint(loadHint, 1, append([]frontend.Variable{addr}, m.stores...)...)
+	if err != nil {
+		panic(err)
+	}
+	m.ops = append(m.ops, []frontend.Variable{addr, res[0], 0})
+	return res[0]
+}
+
+// Store stores value at address addr.
+func (m *Memory) Store(addr, value frontend.Variable) {
+	m.checker.Check(addr, m.nbAddressBits)
+	m.stores = append(m.stores, addr, value)
+	m.ops = append(m.ops, []frontend.Variable{addr, value, 1})
+}
+
+func (m *Memory) check(api frontend.API) error {
+	if len(m.ops) == 0 {
+		return nil
+	}
+	sorted, _ := sort.SortBy(api, m.ops, 0, m.nbAddressBits)
+	// a load returns the value of the previous operation on the same address,
+	// or zero for the first operation on an address.
+	api.AssertIsEqual(api.Mul(api.Sub(1, sorted[0][2]), sorted[0][1]), 0)
+	for i := 1; i < len(sorted); i++ {
+		same := api.IsZero(api.Sub(sorted[i][0], sorted[i-1][0]))
+		expected := api.Mul(same, sorted[i-1][1])
+		api.AssertIsEqual(api.Mul(api.Sub(1, sorted[i][2]), api.Sub(sorted[i][1], expected)), 0)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// programCircuit runs a small program: it stores the values at the addresses,
+// then sums the values at the addresses to load, overwriting the first store.
+type programCircuit struct {
+	StoreAddrs, StoreValues []frontend.Variable
+	LoadAddrs               []frontend.Variable
+	Loaded                  []frontend.Variable
+}
+
+func (c *programCircuit) Define(api frontend.API) error {
+	m := New(api, 8)
+	for i := range c.StoreAddrs {
+		m.Store(c.StoreAddrs[i], c.StoreValues[i])
+	}
+	for i := range c.LoadAddrs {
+		api.AssertIsEqual(m.Load(c.LoadAddrs[i]), c.Loaded[i])
+	}
+	m.Store(c.StoreAddrs[0], 42)
+	api.AssertIsEqual(m.Load(c.StoreAddrs[0]), 42)
+	return nil
+}
+
+func TestMemory(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := programCircuit{
+		StoreAddrs: make([]frontend.Variable, 4), StoreValues: make([]frontend.Variable, 4),
+		LoadAddrs: make([]frontend.Variable, 5), Loaded: make([]frontend.Variable, 5),
+	}
+	assert.CheckCircuit(&circuit,
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&programCircuit{
+			StoreAddrs:  []frontend.Variable{3, 200, 3, 7},
+			StoreValues: []frontend.Variable{10, 20, 30, 40},
+			LoadAddrs:   []frontend.Variable{3, 7, 200, 5, 255},
+			Loaded:      []frontend.Variable{30, 40, 20, 0, 0},
+		}),
+		test.WithInvalidAssignment(&programCircuit{
+			StoreAddrs:  []frontend.Variable{3, 200, 3, 7},
+			StoreValues: []frontend.Variable{10, 20, 30, 40},
+			LoadAddrs:   []frontend.Variable{3, 7, 200, 5, 255},
+			Loaded:      []frontend.Variable{10, 40, 20, 0, 0},
+		}),
+		// the address doesn't fit
+		test.WithInvalidAssignment(&programCircuit{
+			StoreAddrs:  []frontend.Variable{3, 256, 3, 7},
+			StoreValues: []frontend.Variable{10, 20, 30, 40},
+			LoadAddrs:   []frontend.Variable{3, 7, 256, 5, 255},
+			Loaded:      []frontend.Variable{30, 40, 20, 0, 0},
+		}),
+	)
+}
+
+// traceCircuit checks a trace given as witness instead of by the hints.
+type traceCircuit struct {
+	Values [4]frontend.Variable
+}
+
+func (c *traceCircuit) Define(api frontend.API) error {
+	m := New(api, 4)
+	m.ops = [][]frontend.Variable{
+		{2, c.Values[0], 0},
+		{1, c.Values[1], 1},
+		{2, c.Values[2], 1},
+		{1, c.Values[3], 0},
+	}
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&traceCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&traceCircuit{Values: [4]frontend.Variable{0, 5, 6, 5}}),
+		test.WithInvalidAssignment(&traceCircuit{Values: [4]frontend.Variable{0, 5, 6, 6}}),
+		// the load happens before the store
+		test.WithInvalidAssignment(&traceCircuit{Values: [4]frontend.Variable{6, 5, 6, 5}}),
+	)
+}
@@ -135,3 +135,15 @@ func WithCommitment(api frontend.API, cb WithCommitmentFn, committedVariables ..
 	mct.vars = append(mct.vars, committedVariables...)
 	mct.cbs = append(mct.cbs, cb)
 }
+
+// AppendCommitted appends the variables committedVariables to be committed to,
+// without scheduling a callback. As the commitment is computed once the
+// circuit is defined, it allows a gadget which scheduled a callback with
+// [WithCommitment] to extend the variables it depends on.
+func AppendCommitted(api frontend.API, committedVariables ...frontend.Variable) {
+	mct := getCached(api)
+	if mct.closed {
+		panic("called AppendCommitted recursively")
+	}
+	mct.vars = append(mct.vars, committedVariables...)
+}
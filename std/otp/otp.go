@@ -0,0 +1,207 @@
+// Package otp implements in-circuit verification of HMAC-based one-time
+// passwords.
+//
+// The package allows to prove knowledge of a shared secret which yields a
+// given one-time password without revealing the secret. It implements the
+// HOTP algorithm as defined in [RFC 4226] and the time-based TOTP extension as
+// defined in [RFC 6238]. The moving factor (the counter for HOTP or the time
+// step window for TOTP) and the resulting code are usually public inputs, and
+// the secret key is private.
+//
+// The underlying hash function is configurable using [WithHash]. By default
+// HMAC-SHA256 is used. HMAC-SHA1, which most authenticator applications use,
+// is obtained with WithHash(sha1.New, sha1.BlockSize) and the gadget of
+// [github.com/consensys/gnark/std/hash/sha1].
+//
+// [RFC 4226]: https://www.rfc-editor.org/rfc/rfc4226
+// [RFC 6238]: https://www.rfc-editor.org/rfc/rfc6238
+package otp
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/hmac"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bitslice"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{divModHint}
+}
+
+// Constructor returns a new hasher instance for the given API.
+type Constructor func(api frontend.API) (hash.BinaryHasher, error)
+
+type config struct {
+	newHash   Constructor
+	blockSize int
+	digits    int
+}
+
+// Option allows to configure the OTP verifier.
+type Option func(*config) error
+
+// WithHash sets the hash function used in HMAC computation. blockSize is the
+// internal block size of the hash function in bytes (64 for SHA-1 and SHA-256).
+func WithHash(newHash Constructor, blockSize int) Option {
+	return func(c *config) error {
+		if newHash == nil {
+			return fmt.Errorf("nil hash constructor")
+		}
+		if blockSize <= 0 || blockSize%4 != 0 {
+			return fmt.Errorf("block size must be a positive multiple of 4")
+		}
+		c.newHash = newHash
+		c.blockSize = blockSize
+		return nil
+	}
+}
+
+// WithDigits sets the number of decimal digits in the one-time password. The
+// default is 6 and RFC 4226 requires at least 6 digits.
+func WithDigits(digits int) Option {
+	return func(c *config) error {
+		if digits < 1 || digits > 9 {
+			return fmt.Errorf("number of digits %d not in range [1,9]", digits)
+		}
+		c.digits = digits
+		return nil
+	}
+}
+
+// Verifier computes and verifies one-time passwords in-circuit.
+type Verifier struct {
+	api       frontend.API
+	bf        *uints.BinaryField[uints.U64]
+	newHash   Constructor
+	blockSize int
+	modulus   *big.Int
+}
+
+// New returns a new one-time password verifier.
+func New(api frontend.API, opts ...Option) (*Verifier, error) {
+	cfg := config{
+		newHash:   sha2.New,
+		blockSize: 64,
+		digits:    6,
+	}
+	for _, o := range opts {
+		if err := o(&cfg); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+	bf, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, fmt.Errorf("new binary field: %w", err)
+	}
+	modulus := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(cfg.digits)), nil)
+	return &Verifier{
+		api:       api,
+		bf:        bf,
+		newHash:   cfg.newHash,
+		blockSize: cfg.blockSize,
+		modulus:   modulus,
+	}, nil
+}
+
+// HOTP returns the one-time password for the secret key and the counter as
+// defined in RFC 4226. The counter is assumed to fit in 64 bits.
+func (v *Verifier) HOTP(key []uints.U8, counter frontend.Variable) (frontend.Variable, error) {
+	cnt := v.bf.ValueOf(counter)
+	v.api.AssertIsEqual(v.bf.ToValue(cnt), counter)
+	msg := v.bf.UnpackMSB(cnt)
+	mac, err := hmac.New(v.api, v.newHash, v.blockSize, key)
+	if err != nil {
+		return nil, fmt.Errorf("new hmac: %w", err)
+	}
+	mac.Write(msg)
+	return v.truncate(mac.Sum())
+}
+
+// TOTP returns the time-based one-time password for the secret key and the
+// time step window as defined in RFC 6238. The caller should compute the time
+// step window out-of-circuit using [TimeStep].
+func (v *Verifier) TOTP(key []uints.U8, window frontend.Variable) (frontend.Variable, error) {
+	return v.HOTP(key, window)
+}
+
+// AssertIsValid asserts that the secret key yields the one-time password code
+// at the given counter or time step window.
+func (v *Verifier) AssertIsValid(key []uints.U8, counter, code frontend.Variable) error {
+	res, err := v.HOTP(key, counter)
+	if err != nil {
+		return err
+	}
+	v.api.AssertIsEqual(res, code)
+	return nil
+}
+
+// truncate performs the dynamic truncation of the MAC and reduces the result
+// modulo 10^digits. The MAC must be at least 20 bytes long.
+func (v *Verifier) truncate(mac []uints.U8) (frontend.Variable, error) {
+	api := v.api
+	if len(mac) < 20 {
+		return nil, fmt.Errorf("MAC of %d bytes too short for dynamic truncation", len(mac))
+	}
+	// offset is the low 4 bits of the last byte
+	offset, _ := bitslice.Partition(api, mac[len(mac)-1].Val, 4, bitslice.WithNbDigits(8))
+	var selected [4]frontend.Variable
+	for j := range selected {
+		inputs := make([]frontend.Variable, 16)
+		for i := range inputs {
+			inputs[i] = mac[i+j].Val
+		}
+		selected[j] = selector.Mux(api, offset, inputs...)
+	}
+	// mask the most significant bit
+	msb, _ := bitslice.Partition(api, selected[0], 7, bitslice.WithNbDigits(8))
+	bin := api.Add(
+		api.Mul(msb, 1<<24),
+		api.Mul(selected[1], 1<<16),
+		api.Mul(selected[2], 1<<8),
+		selected[3],
+	)
+	res, err := api.Compiler().NewHint(divModHint, 2, bin, v.modulus)
+	if err != nil {
+		return nil, fmt.Errorf("new hint: %w", err)
+	}
+	quo, rem := res[0], res[1]
+	rc := rangecheck.New(api)
+	rc.Check(quo, 31)
+	rc.Check(rem, v.modulus.BitLen())
+	api.AssertIsLessOrEqual(rem, new(big.Int).Sub(v.modulus, big.NewInt(1)))
+	api.AssertIsEqual(bin, api.Add(api.Mul(quo, v.modulus), rem))
+	return rem, nil
+}
+
+func divModHint(_ *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expected 2 inputs, got %d", len(inputs))
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expected 2 outputs, got %d", len(outputs))
+	}
+	if inputs[1].Sign() == 0 {
+		return fmt.Errorf("division by zero")
+	}
+	outputs[0].QuoRem(inputs[0], inputs[1], outputs[1])
+	return nil
+}
+
+// TimeStep returns the TOTP time step window for the Unix timestamp, the
+// initial time t0 and the step size (30 seconds in RFC 6238).
+func TimeStep(timestamp, t0, step int64) uint64 {
+	return uint64((timestamp - t0) / step)
+}
@@ -0,0 +1,138 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha1"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type totpCircuit struct {
+	Key    []uints.U8
+	Window frontend.Variable `gnark:",public"`
+	Code   frontend.Variable `gnark:",public"`
+
+	digits int
+	sha1   bool
+}
+
+func (c *totpCircuit) Define(api frontend.API) error {
+	opts := []Option{WithDigits(c.digits)}
+	if c.sha1 {
+		opts = append(opts, WithHash(sha1.New, sha1.BlockSize))
+	}
+	v, err := New(api, opts...)
+	if err != nil {
+		return err
+	}
+	return v.AssertIsValid(c.Key, c.Window, c.Code)
+}
+
+func TestTOTPRFC6238(t *testing.T) {
+	assert := test.NewAssert(t)
+	// RFC 6238 Appendix B test vectors for HMAC-SHA256
+	key := []byte("12345678901234567890123456789012")
+	for _, tc := range []struct {
+		timestamp int64
+		code      uint64
+	}{
+		{59, 46119246},
+		{1111111109, 68084774},
+		{2000000000, 90698825},
+	} {
+		window := TimeStep(tc.timestamp, 0, 30)
+		circuit := totpCircuit{Key: make([]uints.U8, len(key)), digits: 8}
+		witness := totpCircuit{Key: uints.NewU8Array(key), Window: window, Code: tc.code}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		witness.Code = tc.code + 1
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.Error(err)
+	}
+}
+
+func TestTOTPRFC6238SHA1(t *testing.T) {
+	assert := test.NewAssert(t)
+	// RFC 6238 Appendix B test vectors for HMAC-SHA1
+	key := []byte("12345678901234567890")
+	for _, tc := range []struct {
+		timestamp int64
+		code      uint64
+	}{
+		{59, 94287082},
+		{1111111109, 7081804},
+		{2000000000, 69279037},
+	} {
+		window := TimeStep(tc.timestamp, 0, 30)
+		circuit := totpCircuit{Key: make([]uints.U8, len(key)), digits: 8, sha1: true}
+		witness := totpCircuit{Key: uints.NewU8Array(key), Window: window, Code: tc.code}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		witness.Code = tc.code + 1
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.Error(err)
+	}
+}
+
+func TestHOTPLongKey(t *testing.T) {
+	assert := test.NewAssert(t)
+	key := make([]byte, 100)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	var counter uint64 = 0xdeadbeef
+	mac := hmac.New(sha256.New, key)
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	code := bin % 1000000
+
+	circuit := totpCircuit{Key: make([]uints.U8, len(key)), digits: 6}
+	witness := totpCircuit{Key: uints.NewU8Array(key), Window: counter, Code: code}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+// shortHasher truncates the digest of the underlying hasher to 16 bytes.
+type shortHasher struct {
+	hash.BinaryHasher
+}
+
+func (h shortHasher) Sum() []uints.U8 { return h.BinaryHasher.Sum()[:16] }
+
+func (h shortHasher) Size() int { return 16 }
+
+type shortMACCircuit struct {
+	Key     [16]uints.U8
+	Counter frontend.Variable
+}
+
+func (c *shortMACCircuit) Define(api frontend.API) error {
+	v, err := New(api, WithHash(func(api frontend.API) (hash.BinaryHasher, error) {
+		h, err := sha2.New(api)
+		return shortHasher{h}, err
+	}, 64))
+	if err != nil {
+		return err
+	}
+	_, err = v.HOTP(c.Key[:], c.Counter)
+	return err
+}
+
+func TestHOTPShortMAC(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &shortMACCircuit{})
+	assert.Error(err)
+}
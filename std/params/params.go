@@ -0,0 +1,202 @@
+// Package params is the registry of the public constants used by the gadgets
+// of gnark/std, such as the round constants of MiMC and Poseidon.
+//
+// Each set of constants is registered by the gadget package with a unique
+// name, a version, the seed string it is derived from and a description of the
+// derivation. The constants are generated on first use and cached, and the
+// gadgets read them from the registry.
+//
+// The registry allows external auditors to regenerate the constants from their
+// seeds and to compare them with a previous export: [Set.WriteTo] serializes a
+// set to JSON and [Verify] checks a serialized set against the registered one.
+// The version of a set is incremented whenever its derivation changes, so that
+// digests computed by different versions of gnark are not mixed up silently.
+//
+// Sets are registered when the gadget package is imported, for example
+// importing github.com/consensys/gnark/std/hash/mimc registers the "mimc/<curve>"
+// sets.
+package params
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// Set is a registered set of public constants.
+type Set struct {
+	// Name uniquely identifies the set, for example "mimc/bn254".
+	Name string
+	// Version is incremented when the derivation of the constants changes.
+	Version uint32
+	// Seed is the seed string (or domain separation string) the constants are
+	// derived from.
+	Seed string
+	// Derivation describes how the constants are derived from the seed.
+	Derivation string
+
+	generate  func() ([]*big.Int, error)
+	once      sync.Once
+	constants []*big.Int
+	err       error
+}
+
+var (
+	registry   = make(map[string]*Set)
+	registryMu sync.Mutex
+)
+
+// Register registers a set of constants. The constants are computed with
+// generate on first use. It panics if a set with the same name is already
+// registered.
+func Register(name string, version uint32, seed, derivation string, generate func() ([]*big.Int, error)) *Set {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("parameters %q already registered", name))
+	}
+	s := &Set{
+		Name:       name,
+		Version:    version,
+		Seed:       seed,
+		Derivation: derivation,
+		generate:   generate,
+	}
+	registry[name] = s
+	return s
+}
+
+// Get returns the registered set with the given name.
+func Get(name string) (*Set, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	s, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameters %q", name)
+	}
+	return s, nil
+}
+
+// Names returns the names of the registered sets, sorted.
+func Names() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Constants returns the constants of the set, generating them on the first
+// call. The returned slice is shared and must not be modified.
+func (s *Set) Constants() ([]*big.Int, error) {
+	s.once.Do(func() {
+		s.constants, s.err = s.generate()
+		if s.err != nil {
+			s.err = fmt.Errorf("generate parameters %q: %w", s.Name, s.err)
+		}
+	})
+	return s.constants, s.err
+}
+
+// Digest returns the SHA-256 digest of the name, the version, the seed and the
+// constants of the set. It allows to pin the constants in a short form.
+func (s *Set) Digest() ([]byte, error) {
+	constants, err := s.Constants()
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	var buf [4]byte
+	writeString := func(str string) {
+		binary.BigEndian.PutUint32(buf[:], uint32(len(str)))
+		h.Write(buf[:])
+		h.Write([]byte(str))
+	}
+	writeString(s.Name)
+	binary.BigEndian.PutUint32(buf[:], s.Version)
+	h.Write(buf[:])
+	writeString(s.Seed)
+	binary.BigEndian.PutUint32(buf[:], uint32(len(constants)))
+	h.Write(buf[:])
+	for _, c := range constants {
+		writeString(c.Text(16))
+	}
+	return h.Sum(nil), nil
+}
+
+// serializedSet is the JSON representation of a Set.
+type serializedSet struct {
+	Name       string   `json:"name"`
+	Version    uint32   `json:"version"`
+	Seed       string   `json:"seed"`
+	Derivation string   `json:"derivation"`
+	Constants  []string `json:"constants"` // hexadecimal, 0x prefixed
+}
+
+// WriteTo writes the set and its constants to w in JSON.
+func (s *Set) WriteTo(w io.Writer) (int64, error) {
+	constants, err := s.Constants()
+	if err != nil {
+		return 0, err
+	}
+	ss := serializedSet{
+		Name:       s.Name,
+		Version:    s.Version,
+		Seed:       s.Seed,
+		Derivation: s.Derivation,
+		Constants:  make([]string, len(constants)),
+	}
+	for i, c := range constants {
+		ss.Constants[i] = "0x" + c.Text(16)
+	}
+	b, err := json.MarshalIndent(&ss, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
+
+// Verify reads a set serialized with [Set.WriteTo] and checks that it matches
+// the registered set of the same name, regenerating the constants if needed.
+func Verify(r io.Reader) error {
+	var ss serializedSet
+	if err := json.NewDecoder(r).Decode(&ss); err != nil {
+		return fmt.Errorf("decode parameters: %w", err)
+	}
+	s, err := Get(ss.Name)
+	if err != nil {
+		return err
+	}
+	if ss.Version != s.Version {
+		return fmt.Errorf("parameters %q: version %d, registered version %d", s.Name, ss.Version, s.Version)
+	}
+	if ss.Seed != s.Seed {
+		return fmt.Errorf("parameters %q: seed %q, registered seed %q", s.Name, ss.Seed, s.Seed)
+	}
+	constants, err := s.Constants()
+	if err != nil {
+		return err
+	}
+	if len(ss.Constants) != len(constants) {
+		return fmt.Errorf("parameters %q: %d constants, registered %d", s.Name, len(ss.Constants), len(constants))
+	}
+	for i := range constants {
+		var c big.Int
+		if _, ok := c.SetString(ss.Constants[i], 0); !ok {
+			return fmt.Errorf("parameters %q: invalid constant %d", s.Name, i)
+		}
+		if c.Cmp(constants[i]) != 0 {
+			return fmt.Errorf("parameters %q: constant %d mismatch", s.Name, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package params
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/test"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	calls := 0
+	s := Register("test/registry", 1, "test seed", "the first integers", func() ([]*big.Int, error) {
+		calls++
+		return []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, nil
+	})
+	assert.Panics(func() { Register("test/registry", 2, "", "", nil) }, "duplicate registration")
+
+	got, err := Get("test/registry")
+	assert.NoError(err)
+	assert.Equal(s, got)
+	_, err = Get("test/unknown")
+	assert.Error(err)
+	assert.Contains(Names(), "test/registry")
+
+	// constants are generated once
+	_, err = s.Constants()
+	assert.NoError(err)
+	_, err = s.Constants()
+	assert.NoError(err)
+	assert.Equal(1, calls)
+
+	// round trip
+	var buf bytes.Buffer
+	_, err = s.WriteTo(&buf)
+	assert.NoError(err)
+	serialized := buf.String()
+	assert.NoError(Verify(strings.NewReader(serialized)))
+
+	// altered constant, seed or version
+	for _, alter := range [][2]string{
+		{`"0x3"`, `"0x4"`},
+		{`"test seed"`, `"other seed"`},
+		{`"version": 1`, `"version": 2`},
+	} {
+		altered := strings.Replace(serialized, alter[0], alter[1], 1)
+		assert.NotEqual(serialized, altered)
+		assert.Error(Verify(strings.NewReader(altered)))
+	}
+
+	// the digest depends on the constants
+	d1, err := s.Digest()
+	assert.NoError(err)
+	other := Register("test/registry/other", 1, "test seed", "", func() ([]*big.Int, error) {
+		return []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(4)}, nil
+	})
+	d2, err := other.Digest()
+	assert.NoError(err)
+	assert.NotEqual(d1, d2)
+
+	// generation errors are reported
+	failing := Register("test/registry/failing", 1, "", "", func() ([]*big.Int, error) {
+		return nil, errors.New("failure")
+	})
+	_, err = failing.Digest()
+	assert.Error(err)
+}
@@ -0,0 +1,95 @@
+// Package permutation implements the check that two vectors are permutations
+// of each other.
+//
+// The check is the randomized grand product: a and b are permutations of each
+// other if and only if the polynomials ∏(X - aᵢ) and ∏(X - bᵢ) are equal, which
+// is checked at a random point r derived from a commitment to a and b (see
+// [multicommit]). The cost is two multiplications per element. The vectors
+// of tuples are first compressed with a random linear combination. Thus, the
+// builder must implement [frontend.Committer].
+//
+// This is a primitive for memory checking and shuffle arguments. The
+// subpackages implement cryptographic permutations, which are unrelated.
+//
+// [multicommit]: https://pkg.go.dev/github.com/consensys/gnark/std/multicommit
+package permutation
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/multicommit"
+)
+
+// AssertIsPermutation asserts that b is a permutation of a.
+func AssertIsPermutation(api frontend.API, a, b []frontend.Variable) {
+	ra, rb := make([][]frontend.Variable, len(a)), make([][]frontend.Variable, len(b))
+	for i := range a {
+		ra[i] = []frontend.Variable{a[i]}
+	}
+	for i := range b {
+		rb[i] = []frontend.Variable{b[i]}
+	}
+	AssertIsRowPermutation(api, ra, rb)
+}
+
+// AssertIsRowPermutation asserts that the rows of b are a permutation of the
+// rows of a. All the rows must have the same length.
+func AssertIsRowPermutation(api frontend.API, a, b [][]frontend.Variable) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("length mismatch: %d != %d", len(a), len(b)))
+	}
+	if len(a) == 0 {
+		return
+	}
+	nbCols := len(a[0])
+	var toCommit []frontend.Variable
+	for i := range a {
+		if len(a[i]) != nbCols || len(b[i]) != nbCols {
+			panic("row length mismatch")
+		}
+		toCommit = append(toCommit, a[i]...)
+		toCommit = append(toCommit, b[i]...)
+	}
+	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+		coeffs, err := rowCoefficients(api, nbCols, commitment)
+		if err != nil {
+			return err
+		}
+		var pa, pb frontend.Variable = 1, 1
+		for i := range a {
+			pa = api.Mul(pa, api.Sub(commitment, combine(api, coeffs, a[i])))
+			pb = api.Mul(pb, api.Sub(commitment, combine(api, coeffs, b[i])))
+		}
+		api.AssertIsEqual(pa, pb)
+		return nil
+	}, toCommit...)
+}
+
+// rowCoefficients returns the coefficients of the random linear combination of
+// the rows, derived from the commitment.
+func rowCoefficients(api frontend.API, nbCols int, commitment frontend.Variable) ([]frontend.Variable, error) {
+	if nbCols == 1 {
+		return []frontend.Variable{1}, nil
+	}
+	hasher, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	coeffs := make([]frontend.Variable, nbCols)
+	for i := range coeffs {
+		hasher.Reset()
+		hasher.Write(i+1, commitment)
+		coeffs[i] = hasher.Sum()
+	}
+	return coeffs, nil
+}
+
+func combine(api frontend.API, coeffs, row []frontend.Variable) frontend.Variable {
+	var res frontend.Variable = 0
+	for i := range row {
+		res = api.Add(res, api.Mul(coeffs[i], row[i]))
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package permutation
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type permutationCircuit struct {
+	A, B []frontend.Variable
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	AssertIsPermutation(api, c.A, c.B)
+	return nil
+}
+
+func TestAssertIsPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&permutationCircuit{A: make([]frontend.Variable, 4), B: make([]frontend.Variable, 4)},
+		test.WithValidAssignment(&permutationCircuit{A: []frontend.Variable{1, 2, 2, 3}, B: []frontend.Variable{2, 3, 1, 2}}),
+		test.WithInvalidAssignment(&permutationCircuit{A: []frontend.Variable{1, 2, 2, 3}, B: []frontend.Variable{2, 3, 1, 1}}),
+		test.WithInvalidAssignment(&permutationCircuit{A: []frontend.Variable{0, 0, 0, 0}, B: []frontend.Variable{0, 0, 0, 1}}),
+	)
+}
+
+type rowPermutationCircuit struct {
+	A, B [3][2]frontend.Variable
+}
+
+func (c *rowPermutationCircuit) Define(api frontend.API) error {
+	a, b := make([][]frontend.Variable, 3), make([][]frontend.Variable, 3)
+	for i := range a {
+		a[i], b[i] = c.A[i][:], c.B[i][:]
+	}
+	AssertIsRowPermutation(api, a, b)
+	return nil
+}
+
+func TestAssertIsRowPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := [3][2]frontend.Variable{{1, 10}, {2, 20}, {3, 30}}
+	assert.CheckCircuit(&rowPermutationCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&rowPermutationCircuit{A: a, B: [3][2]frontend.Variable{{3, 30}, {1, 10}, {2, 20}}}),
+		// the columns are permutations, but not the rows
+		test.WithInvalidAssignment(&rowPermutationCircuit{A: a, B: [3][2]frontend.Variable{{3, 10}, {1, 30}, {2, 20}}}),
+	)
+}
@@ -0,0 +1,232 @@
+// Package poseidon2 implements the Poseidon2 permutation function.
+//
+// Poseidon2 is described in [Poseidon2: A Faster Version of the Poseidon Hash
+// Function]. Compared to Poseidon, the linear layers are cheap matrices, so
+// that the permutation costs mostly the S-boxes.
+//
+// This package exposes only the permutation primitive and its out-of-circuit
+// counterpart ([Parameters.Permute]). The standard parameter sets for the BN254
+// and BLS12-377 scalar fields are returned by [GetDefaultParameters].
+//
+// [Poseidon2: A Faster Version of the Poseidon Hash Function]: https://eprint.iacr.org/2023/323
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/internal/grain"
+)
+
+// Parameters define an instance of the Poseidon2 permutation over a prime field.
+type Parameters struct {
+	Field *big.Int // modulus of the field
+	T     int      // width of the permutation, 2 or 3
+	RF    int      // number of full rounds, half of them at the beginning and half at the end
+	RP    int      // number of partial rounds
+	Alpha int      // exponent of the S-box x -> x^Alpha
+
+	// RoundConstants[r] are the constants added to the state at round r. They
+	// have T elements for the full rounds and a single element for the partial
+	// rounds.
+	RoundConstants [][]*big.Int
+}
+
+// standard parameter sets, width 3
+var defaultRounds = map[ecc.ID]struct{ rf, rp, alpha int }{
+	ecc.BN254:     {8, 56, 5},
+	ecc.BLS12_377: {8, 31, 17},
+}
+
+var (
+	parametersCache   = make(map[ecc.ID]*Parameters)
+	parametersCacheMu sync.Mutex
+)
+
+// GetDefaultParameters returns the standard parameters of width 3 over the
+// given field, targeting 128 bits of security:
+//   - BN254: RF=8, RP=56, alpha=5, compatible with the reference implementation;
+//   - BLS12-377: RF=8, RP=31, alpha=17.
+//
+// The returned parameters are shared and must not be modified.
+func GetDefaultParameters(field *big.Int) (*Parameters, error) {
+	curve := utils.FieldToCurve(field)
+	rounds, ok := defaultRounds[curve]
+	if !ok {
+		return nil, fmt.Errorf("no default Poseidon2 parameters for field %s", field.Text(16))
+	}
+
+	parametersCacheMu.Lock()
+	defer parametersCacheMu.Unlock()
+	if p, ok := parametersCache[curve]; ok {
+		return p, nil
+	}
+	p, err := NewParameters(field, 3, rounds.rf, rounds.rp, rounds.alpha)
+	if err != nil {
+		return nil, err
+	}
+	parametersCache[curve] = p
+	return p, nil
+}
+
+// NewParameters returns the parameters of the Poseidon2 permutation with the
+// given width, number of rounds and S-box exponent. The round constants are
+// derived from the Grain LFSR as in the reference implementation. Only the
+// widths 2 and 3 are supported.
+func NewParameters(field *big.Int, t, rf, rp, alpha int) (*Parameters, error) {
+	if t != 2 && t != 3 {
+		return nil, fmt.Errorf("unsupported width %d, must be 2 or 3", t)
+	}
+	if rf <= 0 || rf%2 != 0 {
+		return nil, fmt.Errorf("invalid number of full rounds %d, must be positive and even", rf)
+	}
+	if rp < 0 {
+		return nil, fmt.Errorf("invalid number of partial rounds %d", rp)
+	}
+	if alpha < 3 || new(big.Int).GCD(nil, nil, big.NewInt(int64(alpha)), new(big.Int).Sub(field, big.NewInt(1))).Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("invalid S-box exponent %d, x^%d is not a permutation of the field", alpha, alpha)
+	}
+
+	g := grain.New(field.BitLen(), t, rf, rp)
+	p := &Parameters{
+		Field:          new(big.Int).Set(field),
+		T:              t,
+		RF:             rf,
+		RP:             rp,
+		Alpha:          alpha,
+		RoundConstants: make([][]*big.Int, rf+rp),
+	}
+	for r := range p.RoundConstants {
+		nb := t
+		if !p.isFullRound(r) {
+			nb = 1
+		}
+		p.RoundConstants[r] = make([]*big.Int, nb)
+		for i := range p.RoundConstants[r] {
+			p.RoundConstants[r][i] = g.NextFieldElement(field)
+		}
+	}
+	return p, nil
+}
+
+func (p *Parameters) isFullRound(r int) bool {
+	return r < p.RF/2 || r >= p.RF/2+p.RP
+}
+
+// internalDiag returns the diagonal of the internal matrix M_I = J + diag(d).
+func (p *Parameters) internalDiag() []int64 {
+	if p.T == 2 {
+		return []int64{1, 2}
+	}
+	return []int64{1, 1, 2}
+}
+
+var errStateLength = errors.New("state length does not match the permutation width")
+
+// Permute applies the Poseidon2 permutation to state in place. The length of
+// state must be p.T and its elements must be reduced modulo p.Field.
+func (p *Parameters) Permute(state []*big.Int) error {
+	if len(state) != p.T {
+		return errStateLength
+	}
+	exp := big.NewInt(int64(p.Alpha))
+	diag := p.internalDiag()
+	var sum, tmp big.Int
+	linear := func(full bool) {
+		sum.SetUint64(0)
+		for i := range state {
+			sum.Add(&sum, state[i])
+		}
+		for i := range state {
+			if full {
+				// M_E = J + I
+				state[i].Add(state[i], &sum)
+			} else {
+				state[i].Mul(state[i], tmp.SetInt64(diag[i])).Add(state[i], &sum)
+			}
+			state[i].Mod(state[i], p.Field)
+		}
+	}
+
+	linear(true)
+	for r := 0; r < p.RF+p.RP; r++ {
+		full := p.isFullRound(r)
+		for i := range p.RoundConstants[r] {
+			state[i].Add(state[i], p.RoundConstants[r][i]).Exp(state[i], exp, p.Field)
+		}
+		linear(full)
+	}
+	return nil
+}
+
+// Permutation computes the Poseidon2 permutation in-circuit.
+type Permutation struct {
+	api    frontend.API
+	params *Parameters
+}
+
+// NewPoseidon2 returns a Poseidon2 permutation with the standard parameters
+// for the native field. See [GetDefaultParameters].
+func NewPoseidon2(api frontend.API) (*Permutation, error) {
+	params, err := GetDefaultParameters(api.Compiler().Field())
+	if err != nil {
+		return nil, err
+	}
+	return NewPoseidon2FromParameters(api, params), nil
+}
+
+// NewPoseidon2FromParameters returns a Poseidon2 permutation with the given
+// parameters. The parameters must be defined over the native field.
+func NewPoseidon2FromParameters(api frontend.API, params *Parameters) *Permutation {
+	return &Permutation{api: api, params: params}
+}
+
+// Permutation applies the permutation on state in place. The length of state
+// must be the width of the permutation.
+func (h *Permutation) Permutation(state []frontend.Variable) error {
+	if len(state) != h.params.T {
+		return errStateLength
+	}
+	h.linear(state, true)
+	for r := 0; r < h.params.RF+h.params.RP; r++ {
+		full := h.params.isFullRound(r)
+		for i := range h.params.RoundConstants[r] {
+			state[i] = h.sbox(h.api.Add(state[i], h.params.RoundConstants[r][i]))
+		}
+		h.linear(state, full)
+	}
+	return nil
+}
+
+// linear applies the external matrix M_E (full rounds) or the internal matrix
+// M_I (partial rounds) to state. Both are of the form J + diag(d).
+func (h *Permutation) linear(state []frontend.Variable, full bool) {
+	sum := h.api.Add(state[0], state[1], state[2:]...)
+	diag := h.params.internalDiag()
+	for i := range state {
+		if full || diag[i] == 1 {
+			state[i] = h.api.Add(state[i], sum)
+		} else {
+			state[i] = h.api.Add(sum, h.api.Mul(state[i], diag[i]))
+		}
+	}
+}
+
+// sbox returns x^alpha.
+func (h *Permutation) sbox(x frontend.Variable) frontend.Variable {
+	alpha := h.params.Alpha
+	res := x
+	for i := bits.Len(uint(alpha)) - 2; i >= 0; i-- {
+		res = h.api.Mul(res, res)
+		if (alpha>>i)&1 == 1 {
+			res = h.api.Mul(res, x)
+		}
+	}
+	return res
+}
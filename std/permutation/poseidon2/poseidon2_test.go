@@ -0,0 +1,75 @@
+package poseidon2
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestReferenceVector(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	params, err := GetDefaultParameters(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	state := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+	assert.NoError(params.Permute(state))
+	expected := []string{
+		"0bb61d24daca55eebcb1929a82650f328134334da98ea4f847f760054f4a3033",
+		"303b6f7c86d043bfcbcc80214f26a30277a15d3f74ca654992defe7ff8d03570",
+		"1ed25194542b12eef8617361c3ba7c52e660b145994427cc86296242cf766ec8",
+	}
+	for i := range expected {
+		e, _ := new(big.Int).SetString(expected[i], 16)
+		assert.Equal(e, state[i])
+	}
+}
+
+type poseidon2Circuit struct {
+	Input    [3]frontend.Variable
+	Expected [3]frontend.Variable `gnark:",public"`
+}
+
+func (c *poseidon2Circuit) Define(api frontend.API) error {
+	p, err := NewPoseidon2(api)
+	if err != nil {
+		return err
+	}
+	state := c.Input
+	if err := p.Permutation(state[:]); err != nil {
+		return err
+	}
+	for i := range state {
+		api.AssertIsEqual(state[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestPoseidon2(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BLS12_377} {
+		params, err := GetDefaultParameters(curve.ScalarField())
+		assert.NoError(err)
+
+		var valid, invalid poseidon2Circuit
+		state := []*big.Int{big.NewInt(1), big.NewInt(2), new(big.Int).Sub(curve.ScalarField(), big.NewInt(1))}
+		for i := range state {
+			valid.Input[i] = new(big.Int).Set(state[i])
+			invalid.Input[i] = new(big.Int).Set(state[i])
+		}
+		assert.NoError(params.Permute(state))
+		for i := range state {
+			valid.Expected[i] = state[i]
+			invalid.Expected[i] = state[i]
+		}
+		invalid.Input[0] = 0
+
+		assert.CheckCircuit(&poseidon2Circuit{},
+			test.WithValidAssignment(&valid),
+			test.WithInvalidAssignment(&invalid),
+			test.WithCurves(curve))
+	}
+}
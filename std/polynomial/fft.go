@@ -0,0 +1,85 @@
+package polynomial
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// FFT returns the evaluations p(genⁱ) for i < len(p), where gen is a primitive
+// root of unity of order len(p), computed with the radix-2 Cooley-Tukey
+// algorithm. The evaluations are in natural order. The length of p must be a
+// power of two.
+//
+// As the twiddle factors are constants, the FFT only uses linear operations
+// which are free in R1CS. It allows for example to check Reed-Solomon encodings
+// or to build FRI verifiers. It panics if gen is not a primitive root of unity
+// of order len(p).
+func FFT(api frontend.API, p []frontend.Variable, gen *big.Int) []frontend.Variable {
+	q := api.Compiler().Field()
+	checkRootOfUnity(q, gen, len(p))
+	return radix2FFT(api, q, p, new(big.Int).Mod(gen, q))
+}
+
+// IFFT returns the coefficients of the polynomial p of degree less than
+// len(evaluations) such that p(genⁱ) = evaluations[i]. It is the inverse of
+// [FFT] with the same root of unity gen.
+func IFFT(api frontend.API, evaluations []frontend.Variable, gen *big.Int) []frontend.Variable {
+	q := api.Compiler().Field()
+	checkRootOfUnity(q, gen, len(evaluations))
+	genInv := new(big.Int).ModInverse(gen, q)
+	res := radix2FFT(api, q, evaluations, genInv)
+	nInv := new(big.Int).ModInverse(big.NewInt(int64(len(evaluations))), q)
+	for i := range res {
+		res[i] = api.Mul(res[i], nInv)
+	}
+	return res
+}
+
+// radix2FFT returns the evaluations of p at the powers of gen, recursing on the even
+// and odd coefficients.
+func radix2FFT(api frontend.API, q *big.Int, p []frontend.Variable, gen *big.Int) []frontend.Variable {
+	n := len(p)
+	if n == 1 {
+		return []frontend.Variable{p[0]}
+	}
+	even := make([]frontend.Variable, n/2)
+	odd := make([]frontend.Variable, n/2)
+	for i := 0; i < n/2; i++ {
+		even[i] = p[2*i]
+		odd[i] = p[2*i+1]
+	}
+	gen2 := new(big.Int).Mul(gen, gen)
+	gen2.Mod(gen2, q)
+	even = radix2FFT(api, q, even, gen2)
+	odd = radix2FFT(api, q, odd, gen2)
+
+	res := make([]frontend.Variable, n)
+	twiddle := big.NewInt(1)
+	for k := 0; k < n/2; k++ {
+		t := api.Mul(odd[k], twiddle)
+		res[k] = api.Add(even[k], t)
+		res[k+n/2] = api.Sub(even[k], t)
+		twiddle = new(big.Int).Mul(twiddle, gen)
+		twiddle.Mod(twiddle, q)
+	}
+	return res
+}
+
+// checkRootOfUnity panics if n is not a power of two or if gen is not a
+// primitive n-th root of unity modulo q.
+func checkRootOfUnity(q, gen *big.Int, n int) {
+	if n == 0 || n&(n-1) != 0 {
+		panic(fmt.Sprintf("size %d is not a power of two", n))
+	}
+	one := big.NewInt(1)
+	// for a power of two n, an n-th root of unity is primitive iff its
+	// (n/2)-th power is not 1
+	isRoot := new(big.Int).Exp(gen, big.NewInt(int64(n)), q).Cmp(one) == 0
+	isPrimitive := n == 1 || new(big.Int).Exp(gen, big.NewInt(int64(n/2)), q).Cmp(one) != 0
+	if !isRoot || !isPrimitive {
+		panic(fmt.Sprintf("generator is not a primitive root of unity of order 2^%d", bits.TrailingZeros(uint(n))))
+	}
+}
@@ -0,0 +1,82 @@
+package polynomial
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type fftCircuit struct {
+	gen          *big.Int
+	Coefficients []frontend.Variable
+	Evaluations  []frontend.Variable
+}
+
+func (c *fftCircuit) Define(api frontend.API) error {
+	evaluations := FFT(api, c.Coefficients, c.gen)
+	coefficients := IFFT(api, c.Evaluations, c.gen)
+	for i := range evaluations {
+		api.AssertIsEqual(evaluations[i], c.Evaluations[i])
+		api.AssertIsEqual(coefficients[i], c.Coefficients[i])
+	}
+	return nil
+}
+
+func TestFFT(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, n := range []int{1, 2, 8, 32} {
+		domain := fft.NewDomain(uint64(n))
+		var gen big.Int
+		domain.Generator.BigInt(&gen)
+
+		coefficients := make([]fr.Element, n)
+		evaluations := make([]fr.Element, n)
+		for i := range coefficients {
+			coefficients[i].SetRandom()
+		}
+		// naive evaluation at the powers of the generator
+		var x fr.Element
+		x.SetOne()
+		for i := range evaluations {
+			for j := n - 1; j >= 0; j-- {
+				evaluations[i].Mul(&evaluations[i], &x).Add(&evaluations[i], &coefficients[j])
+			}
+			x.Mul(&x, &domain.Generator)
+		}
+
+		assignment := &fftCircuit{Coefficients: make([]frontend.Variable, n), Evaluations: make([]frontend.Variable, n)}
+		for i := range coefficients {
+			assignment.Coefficients[i] = coefficients[i]
+			assignment.Evaluations[i] = evaluations[i]
+		}
+		invalid := &fftCircuit{Coefficients: assignment.Coefficients, Evaluations: make([]frontend.Variable, n)}
+		copy(invalid.Evaluations, assignment.Evaluations)
+		invalid.Evaluations[n-1] = 0
+
+		assert.CheckCircuit(
+			&fftCircuit{gen: &gen, Coefficients: make([]frontend.Variable, n), Evaluations: make([]frontend.Variable, n)},
+			test.WithCurves(ecc.BN254),
+			test.WithValidAssignment(assignment),
+			test.WithInvalidAssignment(invalid),
+		)
+	}
+}
+
+func TestCheckRootOfUnity(t *testing.T) {
+	assert := test.NewAssert(t)
+	q := ecc.BN254.ScalarField()
+	domain := fft.NewDomain(16)
+	var gen big.Int
+	domain.Generator.BigInt(&gen)
+	assert.NotPanics(func() { checkRootOfUnity(q, &gen, 16) })
+	// gen is a root of unity of order 32, but not primitive
+	assert.Panics(func() { checkRootOfUnity(q, &gen, 32) })
+	// gen is not a root of unity of order 8
+	assert.Panics(func() { checkRootOfUnity(q, &gen, 8) })
+	assert.Panics(func() { checkRootOfUnity(q, &gen, 12) })
+}
@@ -1,6 +1,8 @@
 package polynomial
 
 import (
+	"fmt"
+	"math/big"
 	"math/bits"
 
 	"github.com/consensys/gnark/frontend"
@@ -74,6 +76,8 @@ func (m MultiLin) Clone() MultiLin {
 	return clone
 }
 
+// Eval returns p(at), computed using Horner's rule. The coefficients of p are
+// in increasing degree order.
 func (p Polynomial) Eval(api frontend.API, at frontend.Variable) (pAt frontend.Variable) {
 	pAt = 0
 
@@ -138,6 +142,54 @@ func InterpolateLDE(api frontend.API, at frontend.Variable, values []frontend.Va
 	return res
 }
 
+// InterpolateBarycentric fits the polynomial f of degree len(nodes)-1 such that
+// f(nodes[i]) = values[i] and returns f(at), using the barycentric Lagrange
+// formula
+//
+//	f(at) = L(at) Σᵢ wᵢ values[i] / (at - nodes[i])
+//
+// where L(at) = Πᵢ (at - nodes[i]) and wᵢ = 1 / Πⱼ≠ᵢ (nodes[i] - nodes[j]) are
+// precomputed at compile time. The cost is linear in the number of nodes, but
+// at must not be one of the nodes, which is the case with overwhelming
+// probability when at is a random challenge. It panics if the nodes are not
+// distinct.
+func InterpolateBarycentric(api frontend.API, at frontend.Variable, nodes []*big.Int, values []frontend.Variable) frontend.Variable {
+	if len(nodes) != len(values) {
+		panic("number of nodes and values differ")
+	}
+	weights := barycentricWeights(api.Compiler().Field(), nodes)
+
+	lAt := frontend.Variable(1)
+	res := frontend.Variable(0)
+	for i := range nodes {
+		atMinus := api.Sub(at, nodes[i])
+		lAt = api.Mul(lAt, atMinus)
+		res = api.Add(res, api.Mul(values[i], weights[i], api.Inverse(atMinus)))
+	}
+	return api.Mul(res, lAt)
+}
+
+// barycentricWeights returns wᵢ = 1 / Πⱼ≠ᵢ (nodes[i] - nodes[j]) mod q.
+func barycentricWeights(q *big.Int, nodes []*big.Int) []*big.Int {
+	weights := make([]*big.Int, len(nodes))
+	diff := new(big.Int)
+	for i := range nodes {
+		w := big.NewInt(1)
+		for j := range nodes {
+			if i == j {
+				continue
+			}
+			diff.Sub(nodes[i], nodes[j])
+			w.Mul(w, diff).Mod(w, q)
+		}
+		if w.ModInverse(w, q) == nil {
+			panic(fmt.Sprintf("node %d is not distinct", i))
+		}
+		weights[i] = w
+	}
+	return weights
+}
+
 // EvalEq returns Πⁿ₁ Eq(xᵢ, yᵢ) = Πⁿ₁ xᵢyᵢ + (1-xᵢ)(1-yᵢ) = Πⁿ₁ (1 + 2xᵢyᵢ - xᵢ - yᵢ). Is assumes len(x) = len(y) =: n
 func EvalEq(api frontend.API, x, y []frontend.Variable) (eq frontend.Variable) {
 
@@ -3,6 +3,7 @@ package polynomial
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -215,6 +216,40 @@ func TestInterpolateQuadraticExtension(t *testing.T) {
 	)
 }
 
+type interpolateBarycentricCircuit struct {
+	nodes    []*big.Int
+	At       frontend.Variable
+	Values   []frontend.Variable
+	Expected frontend.Variable
+}
+
+func (c *interpolateBarycentricCircuit) Define(api frontend.API) error {
+	evaluation := InterpolateBarycentric(api, c.At, c.nodes, c.Values)
+	api.AssertIsEqual(evaluation, c.Expected)
+	return nil
+}
+
+func TestInterpolateBarycentric(t *testing.T) {
+	assert := test.NewAssert(t)
+	// The polynomial is 2 X⁴ - X³ - 9 X² + 9 X - 6
+	p := func(x int64) int64 { return 2*x*x*x*x - x*x*x - 9*x*x + 9*x - 6 }
+	nodesInt := []int64{-3, 0, 2, 7, 11}
+	nodes := make([]*big.Int, len(nodesInt))
+	values := make([]frontend.Variable, len(nodesInt))
+	for i, x := range nodesInt {
+		nodes[i] = big.NewInt(x)
+		values[i] = p(x)
+	}
+	assert.CheckCircuit(
+		&interpolateBarycentricCircuit{nodes: nodes, Values: make([]frontend.Variable, len(nodes))},
+		test.WithValidAssignment(&interpolateBarycentricCircuit{At: 5, Values: values, Expected: p(5)}),
+		test.WithValidAssignment(&interpolateBarycentricCircuit{At: -1, Values: values, Expected: p(-1)}),
+		test.WithInvalidAssignment(&interpolateBarycentricCircuit{At: 5, Values: values, Expected: p(5) + 1}),
+		// the evaluation point can't be a node
+		test.WithInvalidAssignment(&interpolateBarycentricCircuit{At: 2, Values: values, Expected: p(2)}),
+	)
+}
+
 func TestNegFactorial(t *testing.T) {
 	for n, expected := range []int{0, -1, 2, -6, 24} {
 
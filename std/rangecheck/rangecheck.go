@@ -5,11 +5,18 @@
 //   - if the backend supports creating a commitment of variables by implementing [frontend.Committer], then we use the log-derivative variant [[Haböck22]] of the product argument as in [[BCG+18]] . [r1cs.NewBuilder] returns a builder which implements this interface;
 //   - lacking these, we perform binary decomposition of variable into bits.
 //
+// The range checker is shared by all the gadgets of a circuit, so that the
+// checks registered anywhere in the circuit are batched into a single argument
+// when the circuit is finalized. Checking the same variable several times adds
+// constraints only for the tightest bound.
+//
 // [BCG+18]: https://eprint.iacr.org/2018/380
 // [Haböck22]: https://eprint.iacr.org/2022/1530
 package rangecheck
 
 import (
+	"encoding/binary"
+
 	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
@@ -28,7 +35,25 @@ func New(api frontend.API) frontend.Rangechecker {
 	if _, ok := api.(frontend.Committer); ok {
 		return newCommitRangechecker(api)
 	}
-	return plainChecker{api: api}
+	return newPlainChecker(api)
+}
+
+// Check asserts that v has bit-length at most bits using the range checker
+// shared by the circuit. It is equivalent to New(api).Check(v, bits).
+func Check(api frontend.API, v frontend.Variable, bits int) {
+	New(api).Check(v, bits)
+}
+
+// variableKey returns a key identifying the variable v in the constraint
+// system, used to deduplicate the checks of the same variable.
+func variableKey(api frontend.API, v frontend.Variable) string {
+	var compressed []uint32
+	api.Compiler().ToCanonicalVariable(v).Compress(&compressed)
+	buf := make([]byte, 4*len(compressed))
+	for i := range compressed {
+		binary.LittleEndian.PutUint32(buf[4*i:], compressed[i])
+	}
+	return string(buf)
 }
 
 // GetHints returns all hints used in this package
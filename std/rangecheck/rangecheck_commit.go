@@ -24,8 +24,11 @@ type checkedVariable struct {
 }
 
 type commitChecker struct {
+	api       frontend.API
 	collected []checkedVariable
-	closed    bool
+	// index maps the key of a collected variable to its position in collected.
+	index  map[string]int
+	closed bool
 }
 
 func newCommitRangechecker(api frontend.API) *commitChecker {
@@ -41,7 +44,7 @@ func newCommitRangechecker(api frontend.API) *commitChecker {
 			panic("stored rangechecker is not valid")
 		}
 	}
-	cht := &commitChecker{}
+	cht := &commitChecker{api: api, index: make(map[string]int)}
 	kv.SetKeyValue(ctxCheckerKey{}, cht)
 	api.Compiler().Defer(cht.commit)
 	return cht
@@ -51,6 +54,14 @@ func (c *commitChecker) Check(in frontend.Variable, bits int) {
 	if c.closed {
 		panic("checker already closed")
 	}
+	key := variableKey(c.api, in)
+	if i, ok := c.index[key]; ok {
+		if bits < c.collected[i].bits {
+			c.collected[i].bits = bits
+		}
+		return
+	}
+	c.index[key] = len(c.collected)
 	c.collected = append(c.collected, checkedVariable{v: in, bits: bits})
 }
 
@@ -86,6 +97,13 @@ func (c *commitChecker) commit(api frontend.API) error {
 		}
 		// store all limbs for counting
 		decomposed = append(decomposed, limbs...)
+		// if the width is not a multiple of the base length, then the most
+		// significant limb is also checked after shifting it to the top of the
+		// base length.
+		if r := c.collected[i].bits % baseLength; r != 0 {
+			shift := new(big.Int).Lsh(big.NewInt(1), uint(baseLength-r))
+			decomposed = append(decomposed, api.Mul(limbs[nbLimbs-1], shift))
+		}
 		// check that limbs are correct. We check the sizes of the limbs later
 		var composed frontend.Variable = 0
 		for j := range limbs {
@@ -156,6 +174,9 @@ func nbR1CSConstraints(baseLength int, collected []checkedVariable) int {
 	nbDecomposed := 0
 	for i := range collected {
 		nbDecomposed += int(decompSize(collected[i].bits, baseLength))
+		if collected[i].bits%baseLength != 0 {
+			nbDecomposed++
+		}
 	}
 	eqs := len(collected)       // correctness of decomposition
 	nbRight := nbDecomposed     // inverse per decomposed
@@ -167,6 +188,9 @@ func nbPLONKConstraints(baseLength int, collected []checkedVariable) int {
 	nbDecomposed := 0
 	for i := range collected {
 		nbDecomposed += int(decompSize(collected[i].bits, baseLength))
+		if collected[i].bits%baseLength != 0 {
+			nbDecomposed++
+		}
 	}
 	eqs := nbDecomposed               // check correctness of every decomposition. this is nbDecomp adds + eq cost per collected
 	nbRight := 3 * nbDecomposed       // denominator sub, inv and large sum per table entry
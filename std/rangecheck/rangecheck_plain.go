@@ -2,13 +2,42 @@ package rangecheck
 
 import (
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
 	"github.com/consensys/gnark/std/math/bits"
 )
 
+type ctxPlainCheckerKey struct{}
+
 type plainChecker struct {
 	api frontend.API
+	// checked maps the key of a checked variable to the tightest bound it
+	// has been decomposed with.
+	checked map[string]int
+}
+
+func newPlainChecker(api frontend.API) *plainChecker {
+	kv, ok := api.Compiler().(kvstore.Store)
+	if !ok {
+		return &plainChecker{api: api, checked: make(map[string]int)}
+	}
+	ch := kv.GetKeyValue(ctxPlainCheckerKey{})
+	if ch != nil {
+		if cht, ok := ch.(*plainChecker); ok {
+			return cht
+		} else {
+			panic("stored rangechecker is not valid")
+		}
+	}
+	cht := &plainChecker{api: api, checked: make(map[string]int)}
+	kv.SetKeyValue(ctxPlainCheckerKey{}, cht)
+	return cht
 }
 
-func (pl plainChecker) Check(v frontend.Variable, nbBits int) {
+func (pl *plainChecker) Check(v frontend.Variable, nbBits int) {
+	key := variableKey(pl.api, v)
+	if checked, ok := pl.checked[key]; ok && checked <= nbBits {
+		return
+	}
+	pl.checked[key] = nbBits
 	bits.ToBinary(pl.api, v, bits.WithNbDigits(nbBits))
 }
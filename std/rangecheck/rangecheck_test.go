@@ -44,3 +44,34 @@ func TestCheck(t *testing.T) {
 	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &circuit, frontend.WithCompressThreshold(100))
 	assert.NoError(err)
 }
+
+type sharedCheckCircuit struct {
+	X, Y    frontend.Variable
+	repeats int
+}
+
+func (c *sharedCheckCircuit) Define(api frontend.API) error {
+	for i := 0; i < c.repeats; i++ {
+		Check(api, c.X, 64)
+		Check(api, c.Y, 32)
+	}
+	// the tightest bound is kept
+	Check(api, c.X, 16)
+	return nil
+}
+
+func TestCheckShared(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&sharedCheckCircuit{repeats: 3},
+		test.WithValidAssignment(&sharedCheckCircuit{X: 1 << 15, Y: 1 << 31}),
+		test.WithInvalidAssignment(&sharedCheckCircuit{X: 1 << 16, Y: 1 << 31}),
+		test.WithInvalidAssignment(&sharedCheckCircuit{X: 1 << 15, Y: uint64(1) << 32}),
+	)
+
+	// repeated checks of the same variables are deduplicated
+	once, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &sharedCheckCircuit{repeats: 1})
+	assert.NoError(err)
+	many, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &sharedCheckCircuit{repeats: 10})
+	assert.NoError(err)
+	assert.Equal(once.GetNbConstraints(), many.GetNbConstraints())
+}
@@ -0,0 +1,164 @@
+package groth16
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+)
+
+// VerifierCircuit is an outer circuit which verifies a Groth16 proof of an
+// inner circuit. The verifying key of the inner circuit is a secret witness of
+// the outer circuit, so that the same outer circuit verifies the proofs of any
+// inner circuit with the same number of public inputs. Use [FixedVerifierCircuit]
+// to bake the verifying key in the outer circuit instead.
+//
+// The public inputs of the inner circuit are the public inputs of the outer
+// circuit. Use [NewVerifierCircuit] to create the circuit for compilation and
+// [ValueOfVerifierCircuit] to assign it.
+//
+// When the outer curve is the companion curve of the inner curve in a 2-chain
+// (BW6-761 for BLS12-377, BW6-633 for BLS24-315), the verification is done
+// with native arithmetic and costs a few tens of thousands of constraints.
+// Otherwise, the type parameters must be the emulated ones.
+type VerifierCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Proof        Proof[G1El, G2El]
+	VerifyingKey VerifyingKey[G1El, G2El, GtEl]
+	InnerWitness Witness[S] `gnark:",public"`
+}
+
+// Define verifies the inner proof.
+func (c *VerifierCircuit[S, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	return assertProof(api, c.VerifyingKey, c.Proof, c.InnerWitness)
+}
+
+// NewVerifierCircuit returns the outer circuit verifying the proofs of
+// innerCcs, to be compiled. It returns an error if innerCcs uses commitments,
+// which are not supported by the in-circuit verifier.
+func NewVerifierCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](innerCcs constraint.ConstraintSystem) (*VerifierCircuit[S, G1El, G2El, GtEl], error) {
+	if err := checkInnerCircuit(innerCcs); err != nil {
+		return nil, err
+	}
+	return &VerifierCircuit[S, G1El, G2El, GtEl]{
+		VerifyingKey: PlaceholderVerifyingKey[G1El, G2El, GtEl](innerCcs),
+		InnerWitness: PlaceholderWitness[S](innerCcs),
+	}, nil
+}
+
+// ValueOfVerifierCircuit returns the assignment of the outer circuit from the
+// verifying key of the inner circuit, an inner proof and the inner witness. Only
+// the public part of the inner witness is used.
+func ValueOfVerifierCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](innerVk groth16.VerifyingKey, innerProof groth16.Proof, innerWitness witness.Witness) (*VerifierCircuit[S, G1El, G2El, GtEl], error) {
+	vk, err := ValueOfVerifyingKey[G1El, G2El, GtEl](innerVk)
+	if err != nil {
+		return nil, fmt.Errorf("verifying key: %w", err)
+	}
+	proof, err := ValueOfProof[G1El, G2El](innerProof)
+	if err != nil {
+		return nil, fmt.Errorf("proof: %w", err)
+	}
+	w, err := ValueOfWitness[S, G1El](innerWitness)
+	if err != nil {
+		return nil, fmt.Errorf("witness: %w", err)
+	}
+	return &VerifierCircuit[S, G1El, G2El, GtEl]{
+		Proof:        proof,
+		VerifyingKey: vk,
+		InnerWitness: w,
+	}, nil
+}
+
+// FixedVerifierCircuit is an outer circuit which verifies a Groth16 proof of
+// an inner circuit against a verifying key baked in the outer circuit as
+// constants. It is smaller than [VerifierCircuit], and the outer circuit is
+// bound to a single inner circuit.
+//
+// Use [NewFixedVerifierCircuit] to create the circuit for compilation and
+// [ValueOfFixedVerifierCircuit] to assign it.
+type FixedVerifierCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Proof        Proof[G1El, G2El]
+	InnerWitness Witness[S] `gnark:",public"`
+
+	// verifyingKey is not part of the witness, it is used as constants in
+	// the circuit.
+	verifyingKey *VerifyingKey[G1El, G2El, GtEl] `gnark:"-"`
+}
+
+// Define verifies the inner proof.
+func (c *FixedVerifierCircuit[S, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	if c.verifyingKey == nil {
+		return errors.New("the verifying key is not set, use NewFixedVerifierCircuit")
+	}
+	return assertProof(api, *c.verifyingKey, c.Proof, c.InnerWitness)
+}
+
+// NewFixedVerifierCircuit returns the outer circuit verifying the proofs of
+// innerCcs against innerVk, to be compiled. It returns an error if innerCcs
+// uses commitments, which are not supported by the in-circuit verifier.
+func NewFixedVerifierCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](innerCcs constraint.ConstraintSystem, innerVk groth16.VerifyingKey) (*FixedVerifierCircuit[S, G1El, G2El, GtEl], error) {
+	if err := checkInnerCircuit(innerCcs); err != nil {
+		return nil, err
+	}
+	vk, err := ValueOfVerifyingKey[G1El, G2El, GtEl](innerVk)
+	if err != nil {
+		return nil, fmt.Errorf("verifying key: %w", err)
+	}
+	if len(vk.G1.K) != innerCcs.GetNbPublicVariables() {
+		return nil, errors.New("the verifying key does not match the inner circuit")
+	}
+	return &FixedVerifierCircuit[S, G1El, G2El, GtEl]{
+		InnerWitness: PlaceholderWitness[S](innerCcs),
+		verifyingKey: &vk,
+	}, nil
+}
+
+// ValueOfFixedVerifierCircuit returns the assignment of the outer circuit from
+// the verifying key of the inner circuit, an inner proof and the inner witness.
+// The verifying key is not part of the outer witness, it is only kept so that
+// the assignment can be used as a circuit by the test engine. Only the public
+// part of the inner witness is used.
+func ValueOfFixedVerifierCircuit[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](innerVk groth16.VerifyingKey, innerProof groth16.Proof, innerWitness witness.Witness) (*FixedVerifierCircuit[S, G1El, G2El, GtEl], error) {
+	vk, err := ValueOfVerifyingKey[G1El, G2El, GtEl](innerVk)
+	if err != nil {
+		return nil, fmt.Errorf("verifying key: %w", err)
+	}
+	proof, err := ValueOfProof[G1El, G2El](innerProof)
+	if err != nil {
+		return nil, fmt.Errorf("proof: %w", err)
+	}
+	w, err := ValueOfWitness[S, G1El](innerWitness)
+	if err != nil {
+		return nil, fmt.Errorf("witness: %w", err)
+	}
+	return &FixedVerifierCircuit[S, G1El, G2El, GtEl]{
+		Proof:        proof,
+		InnerWitness: w,
+		verifyingKey: &vk,
+	}, nil
+}
+
+func assertProof[S algebra.ScalarT, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](api frontend.API, vk VerifyingKey[G1El, G2El, GtEl], proof Proof[G1El, G2El], w Witness[S]) error {
+	curve, err := algebra.GetCurve[S, G1El](api)
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	pairing, err := algebra.GetPairing[G1El, G2El, GtEl](api)
+	if err != nil {
+		return fmt.Errorf("get pairing: %w", err)
+	}
+	verifier := NewVerifier(curve, pairing)
+	return verifier.AssertProof(vk, proof, w)
+}
+
+// checkInnerCircuit returns an error if the proofs of ccs can not be verified
+// in-circuit.
+func checkInnerCircuit(ccs constraint.ConstraintSystem) error {
+	if len(ccs.GetCommitments().CommitmentIndexes()) != 0 {
+		return errors.New("the in-circuit verifier does not support commitments in the inner circuit")
+	}
+	return nil
+}
@@ -1,2 +1,11 @@
 // Package groth16 provides in-circuit Groth16 verifier.
+//
+// The [Verifier] can be used in any circuit. For the common case of an outer
+// circuit which only verifies an inner proof, [VerifierCircuit] (verifying key
+// as witness) and [FixedVerifierCircuit] (verifying key as constants) are
+// ready to compile from the inner constraint system, and are assigned directly
+// from the inner proof, verifying key and witness. The most efficient setting
+// uses a 2-chain of curves, for example an inner circuit on BLS12-377 and an
+// outer circuit on BW6-761, where the verification is done with native
+// arithmetic.
 package groth16
@@ -83,3 +83,52 @@ func Example_native() {
 		panic("circuit verification failed: " + err.Error())
 	}
 }
+
+// Example of verifying recursively BLS12-377 Groth16 proof in BW6-761 Groth16
+// circuit with the verifying key of the inner circuit baked into the outer
+// circuit. The outer circuit is generated from the inner constraint system and
+// the outer public inputs are the inner public inputs.
+func Example_fixedVerifierCircuit() {
+	// compute the proof which we want to verify recursively
+	innerCcs, innerVK, innerWitness, innerProof := computeInnerProof(ecc.BLS12_377.ScalarField())
+
+	// generate the verifier circuit of the inner circuit
+	outerCircuit, err := stdgroth16.NewFixedVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerCcs, innerVK)
+	if err != nil {
+		panic(err)
+	}
+	ccs, err := frontend.Compile(ecc.BW6_761.ScalarField(), r1cs.NewBuilder, outerCircuit)
+	if err != nil {
+		panic("compile failed: " + err.Error())
+	}
+
+	// create Groth16 setup. NB! UNSAFE
+	pk, vk, err := groth16.Setup(ccs) // UNSAFE! Use MPC
+	if err != nil {
+		panic("setup failed: " + err.Error())
+	}
+
+	// convert the inner proof and witness to the outer witness
+	outerAssignment, err := stdgroth16.ValueOfFixedVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerVK, innerProof, innerWitness)
+	if err != nil {
+		panic(err)
+	}
+	secretWitness, err := frontend.NewWitness(outerAssignment, ecc.BW6_761.ScalarField())
+	if err != nil {
+		panic("secret witness failed: " + err.Error())
+	}
+	publicWitness, err := secretWitness.Public()
+	if err != nil {
+		panic("public witness failed: " + err.Error())
+	}
+
+	// construct and verify the groth16 proof of verifying Groth16 proof in-circuit
+	outerProof, err := groth16.Prove(ccs, pk, secretWitness)
+	if err != nil {
+		panic("proving failed: " + err.Error())
+	}
+	err = groth16.Verify(outerProof, vk, publicWitness)
+	if err != nil {
+		panic("circuit verification failed: " + err.Error())
+	}
+}
@@ -309,3 +309,33 @@ func TestValueOfVerifyingKey(t *testing.T) {
 		_ = vvk
 	}, "bls24315")
 }
+
+func TestVerifierCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerCcs, innerVK, innerWitness, innerProof := getInner(assert, ecc.BLS12_377.ScalarField())
+
+	outerCircuit, err := NewVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerCcs)
+	assert.NoError(err)
+	outerAssignment, err := ValueOfVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerVK, innerProof, innerWitness)
+	assert.NoError(err)
+	assert.CheckCircuit(outerCircuit, test.WithValidAssignment(outerAssignment), test.WithCurves(ecc.BW6_761))
+}
+
+func TestFixedVerifierCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerCcs, innerVK, innerWitness, innerProof := getInner(assert, ecc.BLS12_377.ScalarField())
+
+	outerCircuit, err := NewFixedVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerCcs, innerVK)
+	assert.NoError(err)
+	outerAssignment, err := ValueOfFixedVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerVK, innerProof, innerWitness)
+	assert.NoError(err)
+	assert.CheckCircuit(outerCircuit, test.WithValidAssignment(outerAssignment), test.WithCurves(ecc.BW6_761))
+
+	// the proof does not verify against the verifying key of another setup
+	_, otherVK, _, _ := getInner(assert, ecc.BLS12_377.ScalarField())
+	otherCircuit, err := NewFixedVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](innerCcs, otherVK)
+	assert.NoError(err)
+	otherAssignment, err := ValueOfFixedVerifierCircuit[sw_bls12377.Scalar, sw_bls12377.G1Affine, sw_bls12377.G2Affine, sw_bls12377.GT](otherVK, innerProof, innerWitness)
+	assert.NoError(err)
+	assert.Error(test.IsSolved(otherCircuit, otherAssignment, ecc.BW6_761.ScalarField()))
+}
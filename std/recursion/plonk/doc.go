@@ -0,0 +1,14 @@
+// Package plonk provides in-circuit PLONK verifier.
+//
+// The verifier checks the proofs of the PLONK backend with the KZG polynomial
+// commitment scheme, including the proofs of circuits using commitments. As
+// the challenges are recomputed in-circuit, the inner proofs must be created
+// with the hash functions of [recursion.NewShort] over the field of the outer
+// circuit, which are given to the prover with the options
+// [backend.WithProverChallengeHashFunction],
+// [backend.WithProverHashToFieldFunction] and
+// [backend.WithProverKZGFoldingHashFunction].
+//
+// The scalar field of the inner curve is emulated, which allows to verify
+// proofs over BN254 and BLS12-381.
+package plonk
@@ -0,0 +1,709 @@
+package plonk
+
+import (
+	"fmt"
+	gobits "math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	plonkbackend_bls12381 "github.com/consensys/gnark/backend/plonk/bls12-381"
+	plonkbackend_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/commitments/kzg"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/recursion"
+)
+
+// OpeningProof is the opening proof of a single polynomial at a point known to
+// the verifier.
+type OpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	Quotient     G1El
+	ClaimedValue emulated.Element[FR]
+}
+
+// BatchOpeningProof is the opening proof of several polynomials at a single
+// point known to the verifier.
+type BatchOpeningProof[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	Quotient      G1El
+	ClaimedValues []emulated.Element[FR]
+}
+
+// Proof is a typed PLONK proof of SNARK. Use [ValueOfProof] to initialize the
+// witness from the native proof and [PlaceholderProof] to initialize the
+// placeholder witness for compiling the circuit.
+type Proof[FR emulated.FieldParams, G1El algebra.G1ElementT] struct {
+	// Commitments to the solution vectors
+	LRO [3]kzg.Commitment[G1El]
+	// Commitment to Z, the permutation polynomial
+	Z kzg.Commitment[G1El]
+	// Commitments to h1, h2, h3 such that h = h1 + Xⁿ⁺²h2 + X²⁽ⁿ⁺²⁾h3 is the
+	// quotient polynomial
+	H [3]kzg.Commitment[G1El]
+
+	Bsb22Commitments []kzg.Commitment[G1El]
+
+	// Batch opening proof at ζ of the folded h, the linearized polynomial, l,
+	// r, o, s1, s2 and the qcp
+	BatchedProof BatchOpeningProof[FR, G1El]
+
+	// Opening proof of Z at ζω
+	ZShiftedOpening OpeningProof[FR, G1El]
+}
+
+// ValueOfProof returns the typed witness of the native proof. It returns an
+// error if there is a mismatch between the type parameters and the provided
+// native proof.
+func ValueOfProof[FR emulated.FieldParams, G1El algebra.G1ElementT](proof plonk.Proof) (Proof[FR, G1El], error) {
+	var ret Proof[FR, G1El]
+	switch r := any(&ret).(type) {
+	case *Proof[emparams.BN254Fr, sw_bn254.G1Affine]:
+		tProof, ok := proof.(*plonkbackend_bn254.Proof)
+		if !ok {
+			return ret, fmt.Errorf("expected bn254.Proof, got %T", proof)
+		}
+		for i := range r.LRO {
+			r.LRO[i].G1El = sw_bn254.NewG1Affine(tProof.LRO[i])
+			r.H[i].G1El = sw_bn254.NewG1Affine(tProof.H[i])
+		}
+		r.Z.G1El = sw_bn254.NewG1Affine(tProof.Z)
+		r.Bsb22Commitments = make([]kzg.Commitment[sw_bn254.G1Affine], len(tProof.Bsb22Commitments))
+		for i := range r.Bsb22Commitments {
+			r.Bsb22Commitments[i].G1El = sw_bn254.NewG1Affine(tProof.Bsb22Commitments[i])
+		}
+		r.BatchedProof.Quotient = sw_bn254.NewG1Affine(tProof.BatchedProof.H)
+		r.BatchedProof.ClaimedValues = make([]sw_bn254.Scalar, len(tProof.BatchedProof.ClaimedValues))
+		for i := range r.BatchedProof.ClaimedValues {
+			r.BatchedProof.ClaimedValues[i] = sw_bn254.NewScalar(tProof.BatchedProof.ClaimedValues[i])
+		}
+		r.ZShiftedOpening.Quotient = sw_bn254.NewG1Affine(tProof.ZShiftedOpening.H)
+		r.ZShiftedOpening.ClaimedValue = sw_bn254.NewScalar(tProof.ZShiftedOpening.ClaimedValue)
+	case *Proof[emparams.BLS12381Fr, sw_bls12381.G1Affine]:
+		tProof, ok := proof.(*plonkbackend_bls12381.Proof)
+		if !ok {
+			return ret, fmt.Errorf("expected bls12381.Proof, got %T", proof)
+		}
+		for i := range r.LRO {
+			r.LRO[i].G1El = sw_bls12381.NewG1Affine(tProof.LRO[i])
+			r.H[i].G1El = sw_bls12381.NewG1Affine(tProof.H[i])
+		}
+		r.Z.G1El = sw_bls12381.NewG1Affine(tProof.Z)
+		r.Bsb22Commitments = make([]kzg.Commitment[sw_bls12381.G1Affine], len(tProof.Bsb22Commitments))
+		for i := range r.Bsb22Commitments {
+			r.Bsb22Commitments[i].G1El = sw_bls12381.NewG1Affine(tProof.Bsb22Commitments[i])
+		}
+		r.BatchedProof.Quotient = sw_bls12381.NewG1Affine(tProof.BatchedProof.H)
+		r.BatchedProof.ClaimedValues = make([]sw_bls12381.Scalar, len(tProof.BatchedProof.ClaimedValues))
+		for i := range r.BatchedProof.ClaimedValues {
+			r.BatchedProof.ClaimedValues[i] = sw_bls12381.NewScalar(tProof.BatchedProof.ClaimedValues[i])
+		}
+		r.ZShiftedOpening.Quotient = sw_bls12381.NewG1Affine(tProof.ZShiftedOpening.H)
+		r.ZShiftedOpening.ClaimedValue = sw_bls12381.NewScalar(tProof.ZShiftedOpening.ClaimedValue)
+	default:
+		return ret, fmt.Errorf("unknown parametric type combination")
+	}
+	return ret, nil
+}
+
+// PlaceholderProof returns a placeholder proof witness to be used for
+// compiling the outer circuit for witness alignment. For actual witness
+// assignment use [ValueOfProof].
+func PlaceholderProof[FR emulated.FieldParams, G1El algebra.G1ElementT](ccs constraint.ConstraintSystem) Proof[FR, G1El] {
+	nbCommitments := len(commitmentIndexes(ccs))
+	return Proof[FR, G1El]{
+		Bsb22Commitments: make([]kzg.Commitment[G1El], nbCommitments),
+		BatchedProof: BatchOpeningProof[FR, G1El]{
+			ClaimedValues: make([]emulated.Element[FR], 7+nbCommitments),
+		},
+	}
+}
+
+// VerifyingKey is a typed PLONK verifying key for checking SNARK proofs. For
+// witness creation use the method [ValueOfVerifyingKey] and for stub
+// placeholder use [PlaceholderVerifyingKey].
+//
+// The size of the domain, the number of public variables and the indexes of
+// the commitment constraints define the shape of the verification, they are
+// not part of the witness.
+type VerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT] struct {
+	Size                        uint64   `gnark:"-"`
+	NbPublicVariables           uint64   `gnark:"-"`
+	CommitmentConstraintIndexes []uint64 `gnark:"-"`
+
+	SizeInv    emulated.Element[FR]
+	Generator  emulated.Element[FR]
+	CosetShift emulated.Element[FR]
+
+	Kzg kzg.VerifyingKey[G2El]
+
+	// Commitments to S1, S2, S3
+	S [3]kzg.Commitment[G1El]
+
+	// Commitments to ql, qr, qm, qo, qk and the qcp
+	Ql, Qr, Qm, Qo, Qk kzg.Commitment[G1El]
+	Qcp                []kzg.Commitment[G1El]
+}
+
+// ValueOfVerifyingKey initializes witness from the given PLONK verifying key.
+// It returns an error if there is a mismatch between the type parameters and
+// the provided native verifying key.
+func ValueOfVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT](vk plonk.VerifyingKey) (VerifyingKey[FR, G1El, G2El], error) {
+	var ret VerifyingKey[FR, G1El, G2El]
+	switch r := any(&ret).(type) {
+	case *VerifyingKey[emparams.BN254Fr, sw_bn254.G1Affine, sw_bn254.G2Affine]:
+		tVk, ok := vk.(*plonkbackend_bn254.VerifyingKey)
+		if !ok {
+			return ret, fmt.Errorf("expected bn254.VerifyingKey, got %T", vk)
+		}
+		if len(tVk.CustomGates) != 0 {
+			return ret, fmt.Errorf("custom gates not supported")
+		}
+		if tVk.NbLookupTables != 0 {
+			return ret, fmt.Errorf("lookup tables not supported")
+		}
+		r.Size = tVk.Size
+		r.NbPublicVariables = tVk.NbPublicVariables
+		r.CommitmentConstraintIndexes = append([]uint64{}, tVk.CommitmentConstraintIndexes...)
+		r.SizeInv = sw_bn254.NewScalar(tVk.SizeInv)
+		r.Generator = sw_bn254.NewScalar(tVk.Generator)
+		r.CosetShift = sw_bn254.NewScalar(tVk.CosetShift)
+		kzgVk, err := kzg.ValueOfVerifyingKey[sw_bn254.G2Affine](tVk.Kzg)
+		if err != nil {
+			return ret, fmt.Errorf("kzg verifying key: %w", err)
+		}
+		r.Kzg = kzgVk
+		for i := range r.S {
+			r.S[i].G1El = sw_bn254.NewG1Affine(tVk.S[i])
+		}
+		r.Ql.G1El = sw_bn254.NewG1Affine(tVk.Ql)
+		r.Qr.G1El = sw_bn254.NewG1Affine(tVk.Qr)
+		r.Qm.G1El = sw_bn254.NewG1Affine(tVk.Qm)
+		r.Qo.G1El = sw_bn254.NewG1Affine(tVk.Qo)
+		r.Qk.G1El = sw_bn254.NewG1Affine(tVk.Qk)
+		r.Qcp = make([]kzg.Commitment[sw_bn254.G1Affine], len(tVk.Qcp))
+		for i := range r.Qcp {
+			r.Qcp[i].G1El = sw_bn254.NewG1Affine(tVk.Qcp[i])
+		}
+	case *VerifyingKey[emparams.BLS12381Fr, sw_bls12381.G1Affine, sw_bls12381.G2Affine]:
+		tVk, ok := vk.(*plonkbackend_bls12381.VerifyingKey)
+		if !ok {
+			return ret, fmt.Errorf("expected bls12381.VerifyingKey, got %T", vk)
+		}
+		if len(tVk.CustomGates) != 0 {
+			return ret, fmt.Errorf("custom gates not supported")
+		}
+		if tVk.NbLookupTables != 0 {
+			return ret, fmt.Errorf("lookup tables not supported")
+		}
+		r.Size = tVk.Size
+		r.NbPublicVariables = tVk.NbPublicVariables
+		r.CommitmentConstraintIndexes = append([]uint64{}, tVk.CommitmentConstraintIndexes...)
+		r.SizeInv = sw_bls12381.NewScalar(tVk.SizeInv)
+		r.Generator = sw_bls12381.NewScalar(tVk.Generator)
+		r.CosetShift = sw_bls12381.NewScalar(tVk.CosetShift)
+		kzgVk, err := kzg.ValueOfVerifyingKey[sw_bls12381.G2Affine](tVk.Kzg)
+		if err != nil {
+			return ret, fmt.Errorf("kzg verifying key: %w", err)
+		}
+		r.Kzg = kzgVk
+		for i := range r.S {
+			r.S[i].G1El = sw_bls12381.NewG1Affine(tVk.S[i])
+		}
+		r.Ql.G1El = sw_bls12381.NewG1Affine(tVk.Ql)
+		r.Qr.G1El = sw_bls12381.NewG1Affine(tVk.Qr)
+		r.Qm.G1El = sw_bls12381.NewG1Affine(tVk.Qm)
+		r.Qo.G1El = sw_bls12381.NewG1Affine(tVk.Qo)
+		r.Qk.G1El = sw_bls12381.NewG1Affine(tVk.Qk)
+		r.Qcp = make([]kzg.Commitment[sw_bls12381.G1Affine], len(tVk.Qcp))
+		for i := range r.Qcp {
+			r.Qcp[i].G1El = sw_bls12381.NewG1Affine(tVk.Qcp[i])
+		}
+	default:
+		return ret, fmt.Errorf("unknown parametric type combination")
+	}
+	return ret, nil
+}
+
+// PlaceholderVerifyingKey returns a placeholder verifying key for the given
+// compiled constraint system. It sets the parameters which define the shape of
+// the verification, which must be the same in the verifying key assigned to
+// the witness.
+func PlaceholderVerifyingKey[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT](ccs constraint.ConstraintSystem) VerifyingKey[FR, G1El, G2El] {
+	nbPublic := ccs.GetNbPublicVariables()
+	indexes := commitmentIndexes(ccs)
+	ret := VerifyingKey[FR, G1El, G2El]{
+		Size:                        ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints() + nbPublic)),
+		NbPublicVariables:           uint64(nbPublic),
+		CommitmentConstraintIndexes: make([]uint64, len(indexes)),
+		Qcp:                         make([]kzg.Commitment[G1El], len(indexes)),
+	}
+	for i := range indexes {
+		ret.CommitmentConstraintIndexes[i] = uint64(indexes[i])
+	}
+	return ret
+}
+
+func commitmentIndexes(ccs constraint.ConstraintSystem) []int {
+	commitments, ok := ccs.GetCommitments().(constraint.PlonkCommitments)
+	if !ok {
+		return nil
+	}
+	return commitments.CommitmentIndexes()
+}
+
+// Witness is a public witness to verify the SNARK proof against. For assigning
+// witness use [ValueOfWitness] and to create stub witness for compiling use
+// [PlaceholderWitness].
+type Witness[FR emulated.FieldParams] struct {
+	// Public is the public inputs.
+	Public []emulated.Element[FR]
+}
+
+// PlaceholderWitness creates a stub witness which can be used to allocate the
+// variables in the circuit if the actual witness is not yet known.
+func PlaceholderWitness[FR emulated.FieldParams](ccs constraint.ConstraintSystem) Witness[FR] {
+	return Witness[FR]{
+		Public: make([]emulated.Element[FR], ccs.GetNbPublicVariables()),
+	}
+}
+
+// ValueOfWitness assigns a outer-circuit witness from the inner circuit
+// witness. It returns an error if there is a mismatch between the type
+// parameters and provided witness.
+func ValueOfWitness[FR emulated.FieldParams](w witness.Witness) (Witness[FR], error) {
+	var ret Witness[FR]
+	pubw, err := w.Public()
+	if err != nil {
+		return ret, fmt.Errorf("get public witness: %w", err)
+	}
+	vec := pubw.Vector()
+	switch s := any(&ret).(type) {
+	case *Witness[emparams.BN254Fr]:
+		vect, ok := vec.(fr_bn254.Vector)
+		if !ok {
+			return ret, fmt.Errorf("expected fr_bn254.Vector, got %T", vec)
+		}
+		for i := range vect {
+			s.Public = append(s.Public, sw_bn254.NewScalar(vect[i]))
+		}
+	case *Witness[emparams.BLS12381Fr]:
+		vect, ok := vec.(fr_bls12381.Vector)
+		if !ok {
+			return ret, fmt.Errorf("expected fr_bls12381.Vector, got %T", vec)
+		}
+		for i := range vect {
+			s.Public = append(s.Public, sw_bls12381.NewScalar(vect[i]))
+		}
+	default:
+		return ret, fmt.Errorf("unknown parametric type combination")
+	}
+	return ret, nil
+}
+
+// Verifier verifies PLONK proofs.
+type Verifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	api     frontend.API
+	f       *emulated.Field[FR]
+	curve   algebra.Curve[emulated.Element[FR], G1El]
+	pairing algebra.Pairing[G1El, G2El, GtEl]
+}
+
+// NewVerifier returns a new [Verifier] instance. It returns an error if the
+// type parameters are not those of a supported inner curve.
+func NewVerifier[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT](api frontend.API) (*Verifier[FR, G1El, G2El, GtEl], error) {
+	switch any((*G1El)(nil)).(type) {
+	case *sw_bn254.G1Affine, *sw_bls12381.G1Affine:
+	default:
+		return nil, fmt.Errorf("unsupported G1 element %T", (*G1El)(nil))
+	}
+	f, err := emulated.NewField[FR](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	curve, err := algebra.GetCurve[emulated.Element[FR], G1El](api)
+	if err != nil {
+		return nil, fmt.Errorf("get curve: %w", err)
+	}
+	pairing, err := algebra.GetPairing[G1El, G2El, GtEl](api)
+	if err != nil {
+		return nil, fmt.Errorf("get pairing: %w", err)
+	}
+	return &Verifier[FR, G1El, G2El, GtEl]{
+		api:     api,
+		f:       f,
+		curve:   curve,
+		pairing: pairing,
+	}, nil
+}
+
+// AssertProof asserts that the SNARK proof holds for the given witness and
+// verifying key.
+func (v *Verifier[FR, G1El, G2El, GtEl]) AssertProof(vk VerifyingKey[FR, G1El, G2El], proof Proof[FR, G1El], witness Witness[FR]) error {
+	nbCommitments := len(vk.CommitmentConstraintIndexes)
+	if len(proof.Bsb22Commitments) != nbCommitments || len(vk.Qcp) != nbCommitments {
+		return fmt.Errorf("BSB22 commitment number mismatch")
+	}
+	if len(proof.BatchedProof.ClaimedValues) != 7+nbCommitments {
+		return fmt.Errorf("expected %d claimed values, got %d", 7+nbCommitments, len(proof.BatchedProof.ClaimedValues))
+	}
+	if len(witness.Public) != int(vk.NbPublicVariables) {
+		return fmt.Errorf("expected %d public inputs, got %d", vk.NbPublicVariables, len(witness.Public))
+	}
+	if vk.Size == 0 || vk.Size&(vk.Size-1) != 0 {
+		return fmt.Errorf("domain size %d is not a power of two", vk.Size)
+	}
+	var fr FR
+	f := v.f
+
+	// transcript to derive the challenges
+	fsHash, err := recursion.NewHash(v.api, fr.Modulus())
+	if err != nil {
+		return fmt.Errorf("new transcript hash: %w", err)
+	}
+	fs := fiatshamir.NewTranscript(v.api, fsHash, "gamma", "beta", "alpha", "zeta")
+
+	// The first challenge is derived using the public data: the commitments to the permutation,
+	// the coefficients of the circuit, and the public inputs.
+	publicData := []*kzg.Commitment[G1El]{&vk.S[0], &vk.S[1], &vk.S[2], &vk.Ql, &vk.Qr, &vk.Qm, &vk.Qo, &vk.Qk}
+	for i := range vk.Qcp {
+		publicData = append(publicData, &vk.Qcp[i])
+	}
+	for _, c := range publicData {
+		if err := fs.Bind("gamma", v.g1Chunks(&c.G1El)); err != nil {
+			return err
+		}
+	}
+	for i := range witness.Public {
+		if err := fs.Bind("gamma", v.scalarChunks(&witness.Public[i])); err != nil {
+			return err
+		}
+	}
+	// derive gamma from the Comm(blinded cl), Comm(blinded cr), Comm(blinded co)
+	gamma, _, err := v.deriveRandomness(&fs, "gamma", &proof.LRO[0], &proof.LRO[1], &proof.LRO[2])
+	if err != nil {
+		return err
+	}
+	// derive beta from Comm(l), Comm(r), Comm(o)
+	beta, _, err := v.deriveRandomness(&fs, "beta")
+	if err != nil {
+		return err
+	}
+	// derive alpha from Comm(l), Comm(r), Comm(o), Com(Z), Bsb22Commitments
+	alphaDeps := make([]*kzg.Commitment[G1El], nbCommitments+1)
+	for i := range proof.Bsb22Commitments {
+		alphaDeps[i] = &proof.Bsb22Commitments[i]
+	}
+	alphaDeps[nbCommitments] = &proof.Z
+	alpha, _, err := v.deriveRandomness(&fs, "alpha", alphaDeps...)
+	if err != nil {
+		return err
+	}
+	// derive zeta, the point of evaluation
+	zeta, _, err := v.deriveRandomness(&fs, "zeta", &proof.H[0], &proof.H[1], &proof.H[2])
+	if err != nil {
+		return err
+	}
+
+	// evaluation of Z=Xⁿ-1 at ζ
+	one := f.One()
+	zetaPowerM := zeta
+	for i := uint64(1); i < vk.Size; i <<= 1 {
+		zetaPowerM = f.Mul(zetaPowerM, zetaPowerM)
+	}
+	zhZeta := f.Sub(zetaPowerM, one)
+
+	// compute PI = ∑_{i<n} Lᵢ*wᵢ
+	den := f.Sub(zeta, one)
+	lagrange := f.Div(f.Mul(zhZeta, &vk.SizeInv), den) // (1/n)(ζⁿ-1)/(ζ-1)
+	lagrangeOne := lagrange
+	pi := f.Zero()
+	wPowI := one
+	for i := range witness.Public {
+		pi = f.Add(pi, f.Mul(lagrange, &witness.Public[i]))
+		// use Lᵢ₊₁ = w×Lᵢ(ζ-wⁱ)/(ζ-wⁱ⁺¹)
+		if i+1 != len(witness.Public) {
+			lagrange = f.Mul(f.Mul(lagrange, &vk.Generator), den)
+			wPowI = f.Mul(wPowI, &vk.Generator)
+			den = f.Sub(zeta, wPowI)
+			lagrange = f.Div(lagrange, den)
+		}
+	}
+	if nbCommitments > 0 {
+		htf, err := recursion.NewHash(v.api, fr.Modulus())
+		if err != nil {
+			return fmt.Errorf("new hash to field: %w", err)
+		}
+		for i := range vk.CommitmentConstraintIndexes {
+			htf.Write(v.g1Chunks(&proof.Bsb22Commitments[i].G1El)...)
+			hashedCmt := v.toScalar(htf.Sum())
+			htf.Reset()
+
+			// Lᵢ(ζ) = wⁱ/n (ζⁿ-1)/(ζ-wⁱ) for i the index of the commitment constraint
+			wPowI := v.exp(&vk.Generator, vk.NbPublicVariables+vk.CommitmentConstraintIndexes[i])
+			lagrange := f.Div(f.Mul(f.Sub(zeta, one), wPowI), f.Sub(zeta, wPowI))
+			lagrange = f.Mul(lagrange, lagrangeOne)
+			pi = f.Add(pi, f.Mul(lagrange, hashedCmt))
+		}
+	}
+
+	zu := &proof.ZShiftedOpening.ClaimedValue
+	claimedQuotient := &proof.BatchedProof.ClaimedValues[0]
+	linearizedPolynomialZeta := &proof.BatchedProof.ClaimedValues[1]
+	l := &proof.BatchedProof.ClaimedValues[2]
+	r := &proof.BatchedProof.ClaimedValues[3]
+	o := &proof.BatchedProof.ClaimedValues[4]
+	s1 := &proof.BatchedProof.ClaimedValues[5]
+	s2 := &proof.BatchedProof.ClaimedValues[6]
+
+	// check that H(ζ)(ζⁿ-1) = linearizedpolynomial + pi(ζ) + α*(Z(μζ))*(l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*(o(ζ)+γ) - α²*L₁(ζ)
+	ls1 := f.Add(f.Add(f.Mul(s1, beta), l), gamma) // (l(ζ)+β*s1(ζ)+γ)
+	rs2 := f.Add(f.Add(f.Mul(s2, beta), r), gamma) // (r(ζ)+β*s2(ζ)+γ)
+	og := f.Add(o, gamma)                          // (o(ζ)+γ)
+	prod := f.Mul(f.Mul(f.Mul(f.Mul(ls1, rs2), og), alpha), zu)
+	alphaSquareLagrange := f.Mul(f.Mul(lagrangeOne, alpha), alpha) // α²*L₁(ζ)
+	rhs := f.Sub(f.Add(f.Add(linearizedPolynomialZeta, pi), prod), alphaSquareLagrange)
+	f.AssertIsEqual(f.Mul(claimedQuotient, zhZeta), rhs)
+
+	// compute the folded commitment to H: Comm(h₁) + ζⁿ⁺²*Comm(h₂) + ζ²⁽ⁿ⁺²⁾*Comm(h₃)
+	zetaMPlusTwo := f.Mul(f.Mul(zetaPowerM, zeta), zeta)
+	foldedH := v.curve.ScalarMul(&proof.H[2].G1El, zetaMPlusTwo)
+	foldedH = v.curve.Add(foldedH, &proof.H[1].G1El)
+	foldedH = v.curve.ScalarMul(foldedH, zetaMPlusTwo)
+	foldedH = v.curve.Add(foldedH, &proof.H[0].G1El)
+
+	// Compute the commitment to the linearized polynomial
+	// linearizedPolynomialDigest =
+	// 		l(ζ)*ql+r(ζ)*qr+r(ζ)l(ζ)*qm+o(ζ)*qo+qk+Σᵢqc'ᵢ(ζ)*BsbCommitmentᵢ +
+	// 		α*( Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*s₃(X)-Z(X)(l(ζ)+β*id_1(ζ)+γ)*(r(ζ)+β*id_2(ζ)+γ)*(o(ζ)+β*id_3(ζ)+γ) ) +
+	// 		α²*L₁(ζ)*Z
+	rl := f.Mul(l, r)
+	s3Coeff := f.Mul(f.Mul(f.Mul(f.Mul(zu, beta), ls1), rs2), alpha) // α*Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*β
+	cosetSquare := f.Mul(&vk.CosetShift, &vk.CosetShift)
+	betaZeta := f.Mul(beta, zeta)
+	u := f.Add(f.Add(betaZeta, l), gamma)                        // (l(ζ)+β*ζ+γ)
+	w := f.Add(f.Add(f.Mul(betaZeta, &vk.CosetShift), r), gamma) // (r(ζ)+β*μ*ζ+γ)
+	x := f.Add(f.Add(f.Mul(betaZeta, cosetSquare), o), gamma)    // (o(ζ)+β*μ²*ζ+γ)
+	zCoeff := f.Neg(f.Mul(f.Mul(f.Mul(u, w), x), alpha))         // -α*(l(ζ)+β*ζ+γ)*(r(ζ)+β*u*ζ+γ)*(o(ζ)+β*u²*ζ+γ)
+	zCoeff = f.Add(zCoeff, alphaSquareLagrange)                  // -α*(l(ζ)+β*ζ+γ)*(r(ζ)+β*u*ζ+γ)*(o(ζ)+β*u²*ζ+γ) + α²*L₁(ζ)
+	points := make([]*G1El, 0, nbCommitments+6)
+	scalars := make([]*emulated.Element[FR], 0, nbCommitments+6)
+	for i := range proof.Bsb22Commitments {
+		points = append(points, &proof.Bsb22Commitments[i].G1El)
+		scalars = append(scalars, &proof.BatchedProof.ClaimedValues[7+i])
+	}
+	points = append(points, &vk.Ql.G1El, &vk.Qr.G1El, &vk.Qm.G1El, &vk.Qo.G1El, &vk.S[2].G1El, &proof.Z.G1El)
+	scalars = append(scalars, l, r, rl, o, s3Coeff, zCoeff)
+	linearizedPolynomialDigest, err := v.curve.MultiScalarMul(points, scalars)
+	if err != nil {
+		return fmt.Errorf("linearized polynomial digest: %w", err)
+	}
+	linearizedPolynomialDigest = v.curve.Add(linearizedPolynomialDigest, &vk.Qk.G1El)
+
+	// Fold the first proof
+	digestsToFold := []*G1El{
+		foldedH, linearizedPolynomialDigest,
+		&proof.LRO[0].G1El, &proof.LRO[1].G1El, &proof.LRO[2].G1El,
+		&vk.S[0].G1El, &vk.S[1].G1El,
+	}
+	for i := range vk.Qcp {
+		digestsToFold = append(digestsToFold, &vk.Qcp[i].G1El)
+	}
+	foldedDigest, foldedEval, gammaFold, err := v.foldProof(digestsToFold, &proof.BatchedProof, zeta, zu)
+	if err != nil {
+		return err
+	}
+
+	// Batch verify the folded proof at ζ and the opening of Z at ζω, with the
+	// random combination e([∑ᵢλᵢ(fᵢ(α) - fᵢ(pᵢ) + pᵢHᵢ(α))]G₁, G₂).e([-∑ᵢλᵢ[Hᵢ(α)]G₁), [α]G₂) = 1
+	kzgHash, err := recursion.NewHash(v.api, fr.Modulus())
+	if err != nil {
+		return fmt.Errorf("new batching hash: %w", err)
+	}
+	bfs := fiatshamir.NewTranscript(v.api, kzgHash, "lambda")
+	if err := bfs.Bind("lambda", []frontend.Variable{gammaFold}); err != nil {
+		return err
+	}
+	if err := bfs.Bind("lambda", v.g1Chunks(&proof.BatchedProof.Quotient)); err != nil {
+		return err
+	}
+	if err := bfs.Bind("lambda", v.g1Chunks(&proof.ZShiftedOpening.Quotient)); err != nil {
+		return err
+	}
+	lambdaVar, err := bfs.ComputeChallenge("lambda")
+	if err != nil {
+		return err
+	}
+	lambda := v.toScalar(lambdaVar)
+	shiftedZeta := f.Mul(zeta, &vk.Generator)
+	foldedQuotients, err := v.curve.MultiScalarMul(
+		[]*G1El{&proof.ZShiftedOpening.Quotient},
+		[]*emulated.Element[FR]{lambda},
+	)
+	if err != nil {
+		return err
+	}
+	foldedQuotients = v.curve.Add(foldedQuotients, &proof.BatchedProof.Quotient)
+	foldedEvals := f.Add(foldedEval, f.Mul(lambda, zu))
+	foldedDigests, err := v.curve.MultiScalarMul(
+		[]*G1El{&proof.Z.G1El, &proof.BatchedProof.Quotient, &proof.ZShiftedOpening.Quotient},
+		[]*emulated.Element[FR]{lambda, zeta, f.Mul(lambda, shiftedZeta)},
+	)
+	if err != nil {
+		return err
+	}
+	foldedDigests = v.curve.Add(foldedDigests, foldedDigest)
+	foldedDigests = v.curve.Add(foldedDigests, v.curve.Neg(v.curve.ScalarMulBase(foldedEvals)))
+	if err := v.pairing.PairingCheck(
+		[]*G1El{foldedDigests, v.curve.Neg(foldedQuotients)},
+		[]*G2El{&vk.Kzg.SRS[0], &vk.Kzg.SRS[1]},
+	); err != nil {
+		return fmt.Errorf("pairing check: %w", err)
+	}
+	return nil
+}
+
+// foldProof folds the digests and the claimed values of the batch opening
+// proof at point with the challenge γ of the KZG folding, which it returns. It
+// returns the folded digest and the folded evaluation.
+func (v *Verifier[FR, G1El, G2El, GtEl]) foldProof(digests []*G1El, proof *BatchOpeningProof[FR, G1El], point, zu *emulated.Element[FR]) (*G1El, *emulated.Element[FR], frontend.Variable, error) {
+	var fr FR
+	h, err := recursion.NewHash(v.api, fr.Modulus())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("new folding hash: %w", err)
+	}
+	// derive the challenge gamma, binded to the point and the commitments
+	fs := fiatshamir.NewTranscript(v.api, h, "gamma")
+	if err := fs.Bind("gamma", v.scalarChunks(point)); err != nil {
+		return nil, nil, nil, err
+	}
+	for i := range digests {
+		if err := fs.Bind("gamma", v.g1Chunks(digests[i])); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	for i := range proof.ClaimedValues {
+		if err := fs.Bind("gamma", v.scalarChunks(&proof.ClaimedValues[i])); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if err := fs.Bind("gamma", v.scalarChunks(zu)); err != nil {
+		return nil, nil, nil, err
+	}
+	gamma, gammaVar, err := v.deriveRandomness(&fs, "gamma")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// gammai = [1,γ,γ²,..,γⁿ⁻¹]
+	gammai := make([]*emulated.Element[FR], len(digests))
+	gammai[0] = v.f.One()
+	for i := 1; i < len(digests); i++ {
+		gammai[i] = v.f.Mul(gammai[i-1], gamma)
+	}
+	foldedEval := v.f.Zero()
+	for i := range proof.ClaimedValues {
+		foldedEval = v.f.Add(foldedEval, v.f.Mul(gammai[i], &proof.ClaimedValues[i]))
+	}
+	foldedDigest, err := v.curve.MultiScalarMul(digests[1:], gammai[1:])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("fold digests: %w", err)
+	}
+	foldedDigest = v.curve.Add(foldedDigest, digests[0])
+	return foldedDigest, foldedEval, gammaVar, nil
+}
+
+// deriveRandomness binds the points to the challenge and returns the challenge
+// as a scalar and as a native variable.
+func (v *Verifier[FR, G1El, G2El, GtEl]) deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*kzg.Commitment[G1El]) (*emulated.Element[FR], frontend.Variable, error) {
+	for _, p := range points {
+		if err := fs.Bind(challenge, v.g1Chunks(&p.G1El)); err != nil {
+			return nil, nil, err
+		}
+	}
+	res, err := fs.ComputeChallenge(challenge)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.toScalar(res), res, nil
+}
+
+// toScalar returns the challenge c as a scalar. The challenges are smaller
+// than the scalar field modulus.
+func (v *Verifier[FR, G1El, G2El, GtEl]) toScalar(c frontend.Variable) *emulated.Element[FR] {
+	var fr FR
+	nbBits := fr.Modulus().BitLen() - 1
+	if n := v.api.Compiler().FieldBitLen(); n < nbBits {
+		nbBits = n
+	}
+	return v.f.FromBits(bits.ToBinary(v.api, c, bits.WithNbDigits(nbBits))...)
+}
+
+// exp returns x^e.
+func (v *Verifier[FR, G1El, G2El, GtEl]) exp(x *emulated.Element[FR], e uint64) *emulated.Element[FR] {
+	res := v.f.One()
+	for i := gobits.Len64(e) - 1; i >= 0; i-- {
+		res = v.f.Mul(res, res)
+		if (e>>i)&1 == 1 {
+			res = v.f.Mul(res, x)
+		}
+	}
+	return res
+}
+
+// scalarChunks returns the chunks of the big-endian encoding of the scalar s.
+func (v *Verifier[FR, G1El, G2El, GtEl]) scalarChunks(s *emulated.Element[FR]) []frontend.Variable {
+	return recursion.PackBits(v.api, elementBits(v.f, s))
+}
+
+// g1Chunks returns the chunks of the uncompressed encoding of the point p.
+func (v *Verifier[FR, G1El, G2El, GtEl]) g1Chunks(p *G1El) []frontend.Variable {
+	var bs []frontend.Variable
+	switch p := any(p).(type) {
+	case *sw_bn254.G1Affine:
+		fp, err := emulated.NewField[emparams.BN254Fp](v.api)
+		if err != nil {
+			panic(err)
+		}
+		bs = append(elementBits(fp, &p.X), elementBits(fp, &p.Y)...)
+	case *sw_bls12381.G1Affine:
+		fp, err := emulated.NewField[emparams.BLS12381Fp](v.api)
+		if err != nil {
+			panic(err)
+		}
+		bs = append(elementBits(fp, &p.X), elementBits(fp, &p.Y)...)
+		// the point at infinity (0,0) is encoded with the second most
+		// significant bit set, which is zero for the other points
+		bs[1] = v.api.And(fp.IsZero(&p.X), fp.IsZero(&p.Y))
+	default:
+		panic(fmt.Sprintf("unsupported G1 element %T", p))
+	}
+	return recursion.PackBits(v.api, bs)
+}
+
+// elementBits returns the bits of the big-endian encoding of e, most
+// significant first, asserting that e is reduced.
+func elementBits[T emulated.FieldParams](f *emulated.Field[T], e *emulated.Element[T]) []frontend.Variable {
+	var params T
+	nbBits := 8 * ((params.Modulus().BitLen() + 7) / 8)
+	r := f.Reduce(e)
+	f.AssertIsInRange(r)
+	le := f.ToBits(r)
+	res := make([]frontend.Variable, nbBits)
+	for i := range res {
+		if i < len(le) {
+			res[nbBits-1-i] = le[i]
+		} else {
+			res[nbBits-1-i] = 0
+		}
+	}
+	return res
+}
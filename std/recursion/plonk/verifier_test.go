@@ -0,0 +1,166 @@
+package plonk
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/algebra"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/std/recursion"
+	"github.com/consensys/gnark/test"
+)
+
+type InnerCircuitNative struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *InnerCircuitNative) Define(api frontend.API) error {
+	res := api.Mul(c.P, c.Q)
+	api.AssertIsEqual(res, c.N)
+	return nil
+}
+
+type InnerCircuitCommitment struct {
+	P, Q frontend.Variable
+	N    frontend.Variable `gnark:",public"`
+}
+
+func (c *InnerCircuitCommitment) Define(api frontend.API) error {
+	res := api.Mul(c.P, c.Q)
+	api.AssertIsEqual(res, c.N)
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		return fmt.Errorf("builder does not implement frontend.Committer")
+	}
+	cmt, err := committer.Commit(c.P, c.Q)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return nil
+}
+
+func getInner(assert *test.Assert, circuit, assignment frontend.Circuit, field, outer *big.Int) (constraint.ConstraintSystem, plonk.VerifyingKey, witness.Witness, plonk.Proof) {
+	innerCcs, err := frontend.Compile(field, scs.NewBuilder, circuit)
+	assert.NoError(err)
+	srs, err := test.NewKZGSRS(innerCcs)
+	assert.NoError(err)
+	innerPK, innerVK, err := plonk.Setup(innerCcs, srs)
+	assert.NoError(err)
+
+	// inner proof with the hash functions which can be recomputed in-circuit
+	fsHash, err := recursion.NewShort(outer, field)
+	assert.NoError(err)
+	htfHash, err := recursion.NewShort(outer, field)
+	assert.NoError(err)
+	kzgHash, err := recursion.NewShort(outer, field)
+	assert.NoError(err)
+	innerWitness, err := frontend.NewWitness(assignment, field)
+	assert.NoError(err)
+	innerProof, err := plonk.Prove(innerCcs, innerPK, innerWitness,
+		backend.WithProverChallengeHashFunction(fsHash),
+		backend.WithProverHashToFieldFunction(htfHash),
+		backend.WithProverKZGFoldingHashFunction(kzgHash),
+	)
+	assert.NoError(err)
+	innerPubWitness, err := innerWitness.Public()
+	assert.NoError(err)
+	err = plonk.Verify(innerProof, innerVK, innerPubWitness,
+		backend.WithVerifierChallengeHashFunction(fsHash),
+		backend.WithVerifierHashToFieldFunction(htfHash),
+		backend.WithVerifierKZGFoldingHashFunction(kzgHash),
+	)
+	assert.NoError(err)
+	return innerCcs, innerVK, innerPubWitness, innerProof
+}
+
+type OuterCircuit[FR emulated.FieldParams, G1El algebra.G1ElementT, G2El algebra.G2ElementT, GtEl algebra.GtElementT] struct {
+	Proof        Proof[FR, G1El]
+	VerifyingKey VerifyingKey[FR, G1El, G2El]
+	InnerWitness Witness[FR]
+}
+
+func (c *OuterCircuit[FR, G1El, G2El, GtEl]) Define(api frontend.API) error {
+	verifier, err := NewVerifier[FR, G1El, G2El, GtEl](api)
+	if err != nil {
+		return fmt.Errorf("new verifier: %w", err)
+	}
+	return verifier.AssertProof(c.VerifyingKey, c.Proof, c.InnerWitness)
+}
+
+func TestBN254InBN254(t *testing.T) {
+	for _, inner := range []struct {
+		name                string
+		circuit, assignment frontend.Circuit
+	}{
+		{"native", &InnerCircuitNative{}, &InnerCircuitNative{P: 3, Q: 5, N: 15}},
+		{"commitment", &InnerCircuitCommitment{}, &InnerCircuitCommitment{P: 3, Q: 5, N: 15}},
+	} {
+		t.Run(inner.name, func(t *testing.T) {
+			assert := test.NewAssert(t)
+			innerCcs, innerVK, innerWitness, innerProof := getInner(assert, inner.circuit, inner.assignment, ecc.BN254.ScalarField(), ecc.BN254.ScalarField())
+
+			circuitVk, err := ValueOfVerifyingKey[emparams.BN254Fr, sw_bn254.G1Affine, sw_bn254.G2Affine](innerVK)
+			assert.NoError(err)
+			circuitWitness, err := ValueOfWitness[emparams.BN254Fr](innerWitness)
+			assert.NoError(err)
+			circuitProof, err := ValueOfProof[emparams.BN254Fr, sw_bn254.G1Affine](innerProof)
+			assert.NoError(err)
+
+			outerCircuit := &OuterCircuit[emparams.BN254Fr, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl]{
+				InnerWitness: PlaceholderWitness[emparams.BN254Fr](innerCcs),
+				VerifyingKey: PlaceholderVerifyingKey[emparams.BN254Fr, sw_bn254.G1Affine, sw_bn254.G2Affine](innerCcs),
+				Proof:        PlaceholderProof[emparams.BN254Fr, sw_bn254.G1Affine](innerCcs),
+			}
+			outerAssignment := &OuterCircuit[emparams.BN254Fr, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl]{
+				InnerWitness: circuitWitness,
+				Proof:        circuitProof,
+				VerifyingKey: circuitVk,
+			}
+			err = test.IsSolved(outerCircuit, outerAssignment, ecc.BN254.ScalarField())
+			assert.NoError(err)
+
+			// wrong public input
+			circuitWitness.Public[0] = emulated.ValueOf[emparams.BN254Fr](16)
+			err = test.IsSolved(outerCircuit, outerAssignment, ecc.BN254.ScalarField())
+			assert.Error(err)
+		})
+	}
+}
+
+func TestBLS12381InBN254(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerCcs, innerVK, innerWitness, innerProof := getInner(assert, &InnerCircuitNative{}, &InnerCircuitNative{P: 3, Q: 5, N: 15}, ecc.BLS12_381.ScalarField(), ecc.BN254.ScalarField())
+
+	circuitVk, err := ValueOfVerifyingKey[emparams.BLS12381Fr, sw_bls12381.G1Affine, sw_bls12381.G2Affine](innerVK)
+	assert.NoError(err)
+	circuitWitness, err := ValueOfWitness[emparams.BLS12381Fr](innerWitness)
+	assert.NoError(err)
+	circuitProof, err := ValueOfProof[emparams.BLS12381Fr, sw_bls12381.G1Affine](innerProof)
+	assert.NoError(err)
+
+	outerCircuit := &OuterCircuit[emparams.BLS12381Fr, sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		InnerWitness: PlaceholderWitness[emparams.BLS12381Fr](innerCcs),
+		VerifyingKey: PlaceholderVerifyingKey[emparams.BLS12381Fr, sw_bls12381.G1Affine, sw_bls12381.G2Affine](innerCcs),
+		Proof:        PlaceholderProof[emparams.BLS12381Fr, sw_bls12381.G1Affine](innerCcs),
+	}
+	outerAssignment := &OuterCircuit[emparams.BLS12381Fr, sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]{
+		InnerWitness: circuitWitness,
+		Proof:        circuitProof,
+		VerifyingKey: circuitVk,
+	}
+	err = test.IsSolved(outerCircuit, outerAssignment, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
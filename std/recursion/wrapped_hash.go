@@ -0,0 +1,197 @@
+package recursion
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	stdhash "github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// The hash functions of the package are MiMC over the scalar field of the
+// outer curve, the curve of the circuit verifying the proof, adapted to the
+// byte-oriented interface of the native provers:
+//   - every call to Write splits the bytes into chunks of nbChunkBytes bytes,
+//     the last one possibly shorter, which are absorbed as big-endian integers;
+//   - the strings written with WriteString, as the names of the challenges of
+//     the Fiat-Shamir transcripts, are absorbed as their hash to field;
+//   - the digest is the MiMC output truncated to its nbOutBytes least
+//     significant bytes, so that it is an element of both the scalar fields of
+//     the inner and outer curves and that it can be absorbed again as a
+//     single chunk.
+//
+// In-circuit, the bytes are packed with [PackBits] and the strings are
+// absorbed by the Fiat-Shamir transcript gadget as their hash to field.
+
+// nbChunkBytes returns the number of bytes absorbed at once by the hash over
+// the field of modulus outer.
+func nbChunkBytes(outer *big.Int) int {
+	return (outer.BitLen() - 1) / 8
+}
+
+// nbOutBytes returns the size of the digests of the hash over the field of
+// modulus outer for challenges in the field of modulus inner.
+func nbOutBytes(outer, inner *big.Int) int {
+	res := nbChunkBytes(outer)
+	if n := (inner.BitLen() - 1) / 8; n < res {
+		res = n
+	}
+	return res
+}
+
+func mimcOf(outer *big.Int) (cryptohash.Hash, error) {
+	switch utils.FieldToCurve(outer) {
+	case ecc.BN254:
+		return cryptohash.MIMC_BN254, nil
+	case ecc.BLS12_381:
+		return cryptohash.MIMC_BLS12_381, nil
+	case ecc.BLS12_377:
+		return cryptohash.MIMC_BLS12_377, nil
+	case ecc.BW6_761:
+		return cryptohash.MIMC_BW6_761, nil
+	case ecc.BLS24_315:
+		return cryptohash.MIMC_BLS24_315, nil
+	case ecc.BLS24_317:
+		return cryptohash.MIMC_BLS24_317, nil
+	case ecc.BW6_633:
+		return cryptohash.MIMC_BW6_633, nil
+	default:
+		return 0, fmt.Errorf("no MiMC over the field of modulus %s", outer.String())
+	}
+}
+
+type shortNativeHash struct {
+	wrapped      hash.Hash
+	nbChunkBytes int
+	nbOutBytes   int
+}
+
+// NewShort returns the native hash function whose digests can be recomputed
+// in a circuit over the scalar field of modulus outer, for challenges in the
+// scalar field of modulus inner. It is to be given to the native prover and
+// verifier of the inner proof, for example with
+// [backend.WithProverChallengeHashFunction], and its in-circuit counterpart is
+// [NewHash].
+func NewShort(outer, inner *big.Int) (hash.Hash, error) {
+	h, err := mimcOf(outer)
+	if err != nil {
+		return nil, err
+	}
+	return &shortNativeHash{
+		wrapped:      h.New(),
+		nbChunkBytes: nbChunkBytes(outer),
+		nbOutBytes:   nbOutBytes(outer, inner),
+	}, nil
+}
+
+func (h *shortNativeHash) Write(p []byte) (int, error) {
+	block := make([]byte, h.wrapped.BlockSize())
+	for start := 0; start < len(p); start += h.nbChunkBytes {
+		end := start + h.nbChunkBytes
+		if end > len(p) {
+			end = len(p)
+		}
+		for i := range block {
+			block[i] = 0
+		}
+		copy(block[len(block)-(end-start):], p[start:end])
+		if _, err := h.wrapped.Write(block); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteString absorbs the hash to field of rawBytes, as the Fiat-Shamir
+// transcript gadget does for the names of the challenges.
+func (h *shortNativeHash) WriteString(rawBytes []byte) {
+	w, ok := h.wrapped.(interface{ WriteString(rawBytes []byte) })
+	if !ok {
+		panic("wrapped hash doesn't hash strings to field")
+	}
+	w.WriteString(rawBytes)
+}
+
+func (h *shortNativeHash) Sum(b []byte) []byte {
+	res := h.wrapped.Sum(nil)
+	return append(b, res[len(res)-h.nbOutBytes:]...)
+}
+
+func (h *shortNativeHash) Reset() {
+	h.wrapped.Reset()
+}
+
+func (h *shortNativeHash) Size() int {
+	return h.nbOutBytes
+}
+
+func (h *shortNativeHash) BlockSize() int {
+	return h.nbChunkBytes
+}
+
+type shortCircuitHash struct {
+	api        frontend.API
+	wrapped    stdhash.FieldHasher
+	nbOutBytes int
+}
+
+// NewHash returns the in-circuit counterpart of the hash function returned by
+// [NewShort] for the native field and the inner field of modulus inner. The
+// bytes written natively are to be packed with [PackBits] before being written
+// to the hash.
+func NewHash(api frontend.API, inner *big.Int) (stdhash.FieldHasher, error) {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	outer := api.Compiler().Field()
+	if nbOutBytes(outer, inner) <= 0 {
+		return nil, errors.New("inner field too small")
+	}
+	return &shortCircuitHash{api: api, wrapped: &h, nbOutBytes: nbOutBytes(outer, inner)}, nil
+}
+
+func (h *shortCircuitHash) Write(data ...frontend.Variable) {
+	h.wrapped.Write(data...)
+}
+
+func (h *shortCircuitHash) Sum() frontend.Variable {
+	res := h.wrapped.Sum()
+	bs := h.api.ToBinary(res, h.api.Compiler().FieldBitLen())
+	return bits.FromBinary(h.api, bs[:8*h.nbOutBytes], bits.WithUnconstrainedInputs())
+}
+
+func (h *shortCircuitHash) Reset() {
+	h.wrapped.Reset()
+}
+
+// PackBits returns the chunks absorbed by the native hash function returned by
+// [NewShort] when writing the bytes whose bits are given, from the most
+// significant bit of the first byte to the least significant bit of the last
+// byte. The bits must be boolean, which is not checked.
+func PackBits(api frontend.API, bs []frontend.Variable) []frontend.Variable {
+	if len(bs)%8 != 0 {
+		panic("number of bits not a multiple of 8")
+	}
+	chunkSize := 8 * nbChunkBytes(api.Compiler().Field())
+	res := make([]frontend.Variable, 0, (len(bs)+chunkSize-1)/chunkSize)
+	for start := 0; start < len(bs); start += chunkSize {
+		end := start + chunkSize
+		if end > len(bs) {
+			end = len(bs)
+		}
+		le := make([]frontend.Variable, end-start)
+		for i := range le {
+			le[i] = bs[end-1-i]
+		}
+		res = append(res, bits.FromBinary(api, le, bits.WithUnconstrainedInputs()))
+	}
+	return res
+}
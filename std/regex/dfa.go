@@ -0,0 +1,220 @@
+package regex
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// maxNbStates bounds the number of states of the compiled automata, as the
+// subset construction is exponential in the worst case.
+const maxNbStates = 1 << 12
+
+// endOfInput is the symbol read by the automaton after the last byte of the
+// input. Only the accepting states have a transition on it.
+const endOfInput = 256
+
+// transition is a transition of the automaton from the state from to the state
+// to when reading the symbol, which is either a byte or [endOfInput].
+type transition struct {
+	from, symbol, to int
+}
+
+// dfa is a deterministic finite automaton on bytes. The state nbStates-1 is the
+// accepting state reached on [endOfInput]. The missing transitions go to an
+// implicit dead state.
+type dfa struct {
+	nbStates    int
+	start       int
+	transitions []transition
+}
+
+// compile returns the automaton accepting the byte strings which match the
+// pattern entirely. The bytes of the input are interpreted as the runes 0 to
+// 255, so only the ASCII subset of UTF-8 patterns is meaningful. The assertions
+// other than the beginning and the end of the text are not supported.
+func compile(pattern string) (*dfa, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	c := &compiler{prog: prog, index: make(map[string]int)}
+	start, err := c.closure([]uint32{uint32(prog.Start)}, syntax.EmptyBeginText)
+	if err != nil {
+		return nil, err
+	}
+	// the start state is kept separate as the beginning of text assertions only
+	// hold in it.
+	d := &dfa{start: c.state(start, true)}
+	// the states are appended while being explored
+	for i := 0; i < len(c.states); i++ {
+		at := syntax.EmptyOp(0)
+		if i == d.start {
+			at = syntax.EmptyBeginText
+		}
+		for b := 0; b < 256; b++ {
+			next, err := c.step(c.states[i], rune(b))
+			if err != nil {
+				return nil, err
+			}
+			if len(next) == 0 {
+				continue
+			}
+			d.transitions = append(d.transitions, transition{from: i, symbol: b, to: c.state(next, false)})
+		}
+		accept, err := c.accepts(c.states[i], at)
+		if err != nil {
+			return nil, err
+		}
+		if accept {
+			d.transitions = append(d.transitions, transition{from: i, symbol: endOfInput, to: -1})
+		}
+		if len(c.states) > maxNbStates {
+			return nil, fmt.Errorf("automaton has more than %d states", maxNbStates)
+		}
+	}
+	d.nbStates = len(c.states) + 1
+	for i := range d.transitions {
+		if d.transitions[i].to < 0 {
+			d.transitions[i].to = d.nbStates - 1
+		}
+	}
+	return d, nil
+}
+
+// match returns true if the automaton accepts in.
+func (d *dfa) match(in []byte) bool {
+	next := make(map[[2]int]int, len(d.transitions))
+	for _, t := range d.transitions {
+		next[[2]int{t.from, t.symbol}] = t.to
+	}
+	s := d.start
+	for _, b := range in {
+		var ok bool
+		if s, ok = next[[2]int{s, int(b)}]; !ok {
+			return false
+		}
+	}
+	_, ok := next[[2]int{s, endOfInput}]
+	return ok
+}
+
+// compiler performs the subset construction of the automaton from the
+// instructions of a compiled regular expression. A state of the automaton is
+// the set of instructions which are reachable after reading the input so far.
+type compiler struct {
+	prog   *syntax.Prog
+	states [][]uint32
+	index  map[string]int
+}
+
+// state returns the index of the state with the instructions pcs, adding it if
+// new.
+func (c *compiler) state(pcs []uint32, start bool) int {
+	var sb strings.Builder
+	if start {
+		sb.WriteString("start:")
+	}
+	for _, pc := range pcs {
+		fmt.Fprintf(&sb, "%d,", pc)
+	}
+	key := sb.String()
+	if i, ok := c.index[key]; ok {
+		return i
+	}
+	c.index[key] = len(c.states)
+	c.states = append(c.states, pcs)
+	return len(c.states) - 1
+}
+
+// closure returns the sorted instructions reachable from pcs without consuming
+// input when the assertions at hold. The instructions consuming input, the
+// matches and the end of text assertions which don't hold yet are kept.
+func (c *compiler) closure(pcs []uint32, at syntax.EmptyOp) ([]uint32, error) {
+	seen := make(map[uint32]bool)
+	var res []uint32
+	var visit func(pc uint32) error
+	visit = func(pc uint32) error {
+		if seen[pc] {
+			return nil
+		}
+		seen[pc] = true
+		inst := &c.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			if err := visit(inst.Out); err != nil {
+				return err
+			}
+			return visit(inst.Arg)
+		case syntax.InstCapture, syntax.InstNop:
+			return visit(inst.Out)
+		case syntax.InstEmptyWidth:
+			cond := syntax.EmptyOp(inst.Arg)
+			if cond&^(syntax.EmptyBeginText|syntax.EmptyEndText) != 0 {
+				return errors.New("only the beginning and end of text assertions are supported")
+			}
+			switch {
+			case cond&^at == 0:
+				return visit(inst.Out)
+			case cond&syntax.EmptyBeginText == 0 || at&syntax.EmptyBeginText != 0:
+				// the end of text assertion may hold later
+				res = append(res, pc)
+			}
+		case syntax.InstMatch, syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			res = append(res, pc)
+		}
+		return nil
+	}
+	for _, pc := range pcs {
+		if err := visit(pc); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res, nil
+}
+
+// step returns the state reached from pcs when reading r.
+func (c *compiler) step(pcs []uint32, r rune) ([]uint32, error) {
+	var next []uint32
+	for _, pc := range pcs {
+		inst := &c.prog.Inst[pc]
+		var ok bool
+		switch inst.Op {
+		case syntax.InstRuneAny:
+			ok = true
+		case syntax.InstRuneAnyNotNL:
+			ok = r != '\n'
+		case syntax.InstRune, syntax.InstRune1:
+			ok = inst.MatchRune(r)
+		}
+		if ok {
+			next = append(next, inst.Out)
+		}
+	}
+	if len(next) == 0 {
+		return nil, nil
+	}
+	return c.closure(next, 0)
+}
+
+// accepts returns true if pcs matches at the end of the input, the assertions
+// at holding at the current position.
+func (c *compiler) accepts(pcs []uint32, at syntax.EmptyOp) (bool, error) {
+	end, err := c.closure(pcs, at|syntax.EmptyEndText)
+	if err != nil {
+		return false, err
+	}
+	for _, pc := range end {
+		if c.prog.Inst[pc].Op == syntax.InstMatch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,48 @@
+package regex
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{statesHint}
+}
+
+// statesHint returns the states of the automaton after reading every byte of
+// the input. The inputs are the start state, the number of transitions, the
+// transitions as triples (from, symbol, to) and the input bytes.
+func statesHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 2 {
+		return fmt.Errorf("expecting at least two inputs")
+	}
+	nbTransitions := int(inputs[1].Int64())
+	if len(inputs) != 2+3*nbTransitions+len(outputs) {
+		return fmt.Errorf("expecting %d inputs", 2+3*nbTransitions+len(outputs))
+	}
+	next := make(map[[2]int64]int64, nbTransitions)
+	for i := 0; i < nbTransitions; i++ {
+		t := inputs[2+3*i : 5+3*i]
+		next[[2]int64{t[0].Int64(), t[1].Int64()}] = t[2].Int64()
+	}
+	s := inputs[0].Int64()
+	for i, b := range inputs[2+3*nbTransitions:] {
+		if !b.IsInt64() {
+			return fmt.Errorf("input %d is not a byte", i)
+		}
+		var ok bool
+		if s, ok = next[[2]int64{s, b.Int64()}]; !ok {
+			return fmt.Errorf("input doesn't match at byte %d", i)
+		}
+		outputs[i].SetInt64(s)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package regex implements the verification that byte strings match a regular
+// expression.
+//
+// The regular expression is compiled into a deterministic finite automaton
+// (DFA) at circuit compile time. To prove that an input matches, the prover
+// provides the sequence of states of the automaton when reading the input and
+// the circuit checks that every step is a transition of the automaton using a
+// log-derivative lookup argument. The final state must be accepting. So the
+// cost is linear in the length of the input and in the number of transitions of
+// the automaton, which is shared by all the inputs matched against the same
+// [Matcher].
+//
+// The syntax of the regular expressions is the one of the [regexp] package of
+// the standard library. The whole input must match the expression, the
+// expression `.*e.*` matches the inputs which contain a match of e. The bytes
+// of the input are interpreted as the runes 0 to 255, so only the ASCII subset
+// of UTF-8 patterns is meaningful. Only the `^` and `$` assertions are
+// supported, in single-line mode.
+package regex
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/internal/logderivarg"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Matcher checks that the inputs match a regular expression.
+type Matcher struct {
+	api      frontend.API
+	dfa      *dfa
+	rchecker frontend.Rangechecker
+	queries  []frontend.Variable
+}
+
+// New compiles the regular expression pattern and returns a [Matcher]. It
+// defers the lookup argument checking the transitions.
+func New(api frontend.API, pattern string) (*Matcher, error) {
+	d, err := compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile pattern: %w", err)
+	}
+	m := &Matcher{api: api, dfa: d}
+	api.Compiler().Defer(m.build)
+	m.rchecker = rangecheck.New(api)
+	return m, nil
+}
+
+// AssertMatch asserts that in matches the regular expression. The bytes of in
+// are assumed to be in range.
+func (m *Matcher) AssertMatch(in []uints.U8) {
+	inputs := make([]frontend.Variable, 0, 3+3*len(m.dfa.transitions)+len(in))
+	inputs = append(inputs, m.dfa.start, len(m.dfa.transitions))
+	for _, t := range m.dfa.transitions {
+		inputs = append(inputs, t.from, t.symbol, t.to)
+	}
+	for i := range in {
+		inputs = append(inputs, in[i].Val)
+	}
+	states, err := m.api.Compiler().NewHint(statesHint, len(in), inputs...)
+	if err != nil {
+		panic(err)
+	}
+	nbBits := bits.Len(uint(m.dfa.nbStates - 1))
+	from := frontend.Variable(m.dfa.start)
+	for i := range in {
+		m.rchecker.Check(states[i], nbBits)
+		m.queries = append(m.queries, m.pack(from, in[i].Val, states[i]))
+		from = states[i]
+	}
+	m.queries = append(m.queries, m.pack(from, endOfInput, m.dfa.nbStates-1))
+}
+
+// pack returns the transition as a single value. It is injective as the states
+// are range checked and the symbols are at most endOfInput.
+func (m *Matcher) pack(from, symbol, to frontend.Variable) frontend.Variable {
+	return m.api.Add(
+		m.api.Mul(from, (endOfInput+1)*m.dfa.nbStates),
+		m.api.Mul(symbol, m.dfa.nbStates),
+		to,
+	)
+}
+
+func (m *Matcher) build(api frontend.API) error {
+	if len(m.queries) == 0 {
+		return nil
+	}
+	table := make([]frontend.Variable, len(m.dfa.transitions))
+	for i, t := range m.dfa.transitions {
+		table[i] = (t.from*(endOfInput+1)+t.symbol)*m.dfa.nbStates + t.to
+	}
+	return logderivarg.Build(api, logderivarg.AsTable(table), logderivarg.AsTable(m.queries))
+}
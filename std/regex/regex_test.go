@@ -0,0 +1,106 @@
+package regex
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+var patterns = []string{
+	`abc`,
+	`a*b+c?`,
+	`(ab|cd)*e`,
+	`^[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,4}$`,
+	`https?://[^/]+/.*`,
+	`.*from:alice@example\.com.*`,
+	`(?s).*from:alice@example\.com.*`,
+	`(?i)hello`,
+	`x{3,5}`,
+	`[^\n]*`,
+	`^$`,
+	``,
+}
+
+var inputs = []string{
+	"", "abc", "ab", "abcc", "aaab", "bc", "ababcde", "abcde", "e", "cde",
+	"alice@example.com", "Alice@example.com", "alice@example", "bob@mail.example.org",
+	"http://example.com/", "https://example.com/path", "ftp://example.com/", "https://",
+	"subject: hi\r\nfrom:alice@example.com\r\n", "from:bob@example.com",
+	"hello", "HeLLo", "hello!", "xx", "xxx", "xxxxx", "xxxxxx", "\n", "a\nb",
+}
+
+func TestCompile(t *testing.T) {
+	for _, p := range patterns {
+		d, err := compile(p)
+		if err != nil {
+			t.Fatalf("compile %q: %v", p, err)
+		}
+		re := regexp.MustCompile(`^(?:` + p + `)$`)
+		for _, in := range inputs {
+			if got, want := d.match([]byte(in)), re.MatchString(in); got != want {
+				t.Errorf("pattern %q input %q: got %t, expected %t", p, in, got, want)
+			}
+		}
+	}
+}
+
+func TestCompileUnsupported(t *testing.T) {
+	for _, p := range []string{`\bword\b`, `(?m)^a$`, `a(`} {
+		if _, err := compile(p); err == nil {
+			t.Errorf("pattern %q: expected error", p)
+		}
+	}
+}
+
+type matchCircuit struct {
+	pattern string
+	In      []uints.U8
+}
+
+func (c *matchCircuit) Define(api frontend.API) error {
+	m, err := New(api, c.pattern)
+	if err != nil {
+		return err
+	}
+	m.AssertMatch(c.In)
+	return nil
+}
+
+func TestAssertMatch(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, tc := range []struct {
+		pattern string
+		valid   []string
+		invalid []string
+	}{
+		{`^[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,4}$`, []string{"alice@example.com", "bob00@mail.example.org"}, []string{"Alice@example.com", "alice.example.com", "alice@example.c0m"}},
+		{`(?s).*from:alice@example\.com.*`, []string{"subject: hi\r\nfrom:alice@example.com\r\n", "xxxfrom:alice@example.com"}, []string{"subject: hi\r\nfrom:carol@example.com\r\n", "from:alice@example.org..."}},
+		{`(ab|cd)*e`, []string{"ababcde", "cdcde"}, []string{"abcdee", "abcdef"}},
+	} {
+		for _, valid := range tc.valid {
+			circuit := &matchCircuit{pattern: tc.pattern, In: make([]uints.U8, len(valid))}
+			err := test.IsSolved(circuit, &matchCircuit{In: uints.NewU8Array([]byte(valid))}, ecc.BN254.ScalarField())
+			assert.NoError(err, valid)
+		}
+		for _, invalid := range tc.invalid {
+			circuit := &matchCircuit{pattern: tc.pattern, In: make([]uints.U8, len(invalid))}
+			err := test.IsSolved(circuit, &matchCircuit{In: uints.NewU8Array([]byte(invalid))}, ecc.BN254.ScalarField())
+			assert.Error(err, invalid)
+		}
+	}
+}
+
+func TestAssertMatchProve(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := []byte("https://example.com/path")
+	assert.CheckCircuit(&matchCircuit{pattern: `https?://[^/]+/.*`, In: make([]uints.U8, len(in))},
+		test.WithCurves(ecc.BN254),
+		test.NoSerializationChecks(),
+		test.WithValidAssignment(&matchCircuit{In: uints.NewU8Array(in)}),
+		test.WithInvalidAssignment(&matchCircuit{In: uints.NewU8Array([]byte("https://example.com_path"))}),
+	)
+}
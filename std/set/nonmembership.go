@@ -0,0 +1,76 @@
+package set
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// NonMembershipProof proves that a value is not an element of a set committed
+// as the root of a Merkle tree whose leaves are the elements of the set sorted
+// in strictly increasing order. It opens the two consecutive leaves Low < High
+// surrounding the value, so that Low < v < High implies that v is not a leaf.
+//
+// To be able to exclude the values smaller than the smallest element or larger
+// than the largest one, the tree must start with the leaf 0 and end with the
+// leaf 2ⁿ-1, where n is the bit-length bound given to
+// [NonMembershipProof.AssertIsNotMember]. The unused leaves of the tree are
+// padded with 2ⁿ-1 as well, which keeps the leaves sorted but never opens as
+// a pair of consecutive distinct leaves.
+//
+// The soundness relies on the tree being correctly built, which is the
+// responsibility of the party publishing the root, e.g. a denylist maintainer.
+type NonMembershipProof struct {
+	// RootHash is the root of the Merkle tree of the sorted set.
+	RootHash frontend.Variable
+
+	// LowIndex is the position of the leaf Low in the tree. The leaf High is
+	// at the position LowIndex+1.
+	LowIndex frontend.Variable
+
+	// Low and High are the Merkle paths of the two consecutive leaves, the
+	// leaves being the first elements of the paths as in [merkle.MerkleProof].
+	Low, High []frontend.Variable
+}
+
+// PlaceholderNonMembershipProof returns a non-membership proof in a tree of the
+// given depth, to be used in the definition of a circuit.
+func PlaceholderNonMembershipProof(depth int) NonMembershipProof {
+	if depth < 1 {
+		panic("invalid tree depth")
+	}
+	return NonMembershipProof{
+		Low:  make([]frontend.Variable, depth+1),
+		High: make([]frontend.Variable, depth+1),
+	}
+}
+
+// AssertIsNotMember asserts that v is not a leaf of the Merkle tree with root
+// p.RootHash, by verifying the openings of Low and High at consecutive positions
+// and asserting Low < v < High. The leaves are hashed with h as in
+// [merkle.MerkleProof.VerifyProof]. The value v and the leaves must be less than
+// 2^nbBits, with nbBits at most the bit-length of the native modulus minus two.
+func (p *NonMembershipProof) AssertIsNotMember(api frontend.API, h hash.FieldHasher, v frontend.Variable, nbBits int) {
+	if len(p.Low) != len(p.High) || len(p.Low) < 2 {
+		panic(fmt.Sprintf("invalid paths of length %d and %d", len(p.Low), len(p.High)))
+	}
+	if nbBits+2 > api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("bound of %d bits too large for the native field", nbBits))
+	}
+	low := merkle.MerkleProof{RootHash: p.RootHash, Path: p.Low}
+	low.VerifyProof(api, h, p.LowIndex)
+	// the index of High is decomposed on depth bits when verifying the path,
+	// so LowIndex+1 doesn't wrap around the last leaf.
+	high := merkle.MerkleProof{RootHash: p.RootHash, Path: p.High}
+	high.VerifyProof(api, h, api.Add(p.LowIndex, 1))
+
+	// Low < v ⟺ v-Low-1 ≥ 0 and v < High ⟺ High-v-1 ≥ 0. As Low and High are
+	// less than 2^nbBits, the range checks on nbBits bits don't wrap around
+	// the modulus.
+	rc := rangecheck.New(api)
+	rc.Check(api.Sub(v, p.Low[0], 1), nbBits)
+	rc.Check(api.Sub(p.High[0], v, 1), nbBits)
+}
@@ -0,0 +1,73 @@
+package set
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type nonMemberCircuit struct {
+	Proof NonMembershipProof
+	V     frontend.Variable
+}
+
+func (c *nonMemberCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.Proof.AssertIsNotMember(api, &h, c.V, 32)
+	return nil
+}
+
+// sortedTree returns the non-membership proof opening the leaves at lowIndex
+// and lowIndex+1 of the tree of depth 3 of the sorted denylist.
+func sortedTree(assert *test.Assert, lowIndex uint64) NonMembershipProof {
+	const depth = 3
+	max := uint64(1<<32 - 1)
+	leaves := []uint64{0, 17, 42, 1000, 65537, max, max, max}
+	modNbBytes := len(ecc.BN254.ScalarField().Bytes())
+	res := PlaceholderNonMembershipProof(depth)
+	res.LowIndex = lowIndex
+	for k, path := range [][]frontend.Variable{res.Low, res.High} {
+		var buf bytes.Buffer
+		for i := range leaves {
+			b := new(big.Int).SetUint64(leaves[i]).Bytes()
+			buf.Write(make([]byte, modNbBytes-len(b)))
+			buf.Write(b)
+		}
+		root, proofPath, _, err := merkletree.BuildReaderProof(&buf, hash.MIMC_BN254.New(), modNbBytes, lowIndex+uint64(k))
+		assert.NoError(err)
+		res.RootHash = root
+		for i := range path {
+			path[i] = proofPath[i]
+		}
+	}
+	return res
+}
+
+func TestAssertIsNotMember(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := nonMemberCircuit{Proof: PlaceholderNonMembershipProof(3)}
+	assert.CheckCircuit(&circuit,
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 2), V: 43}),
+		test.WithValidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 2), V: 999}),
+		test.WithValidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 0), V: 1}),
+		test.WithValidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 4), V: 1 << 31}),
+		// member of the set
+		test.WithInvalidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 2), V: 42}),
+		test.WithInvalidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 2), V: 1000}),
+		// not between the opened leaves
+		test.WithInvalidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 1), V: 43}),
+		// padding leaves
+		test.WithInvalidAssignment(&nonMemberCircuit{Proof: sortedTree(assert, 5), V: 1<<32 - 1}),
+	)
+}
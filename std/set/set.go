@@ -0,0 +1,151 @@
+// Package set implements membership and non-membership checks.
+//
+// The elements of a [Set] are constants known when compiling the circuit, for
+// example the opcodes of a virtual machine or an allowlist of token
+// identifiers. The membership checks are collected during the circuit
+// definition and built when the circuit is finalized:
+//   - if the builder supports creating a commitment of variables by
+//     implementing [frontend.Committer], then the checks are batched into a
+//     single log-derivative lookup argument [[Haböck22]] where the set is the
+//     table and the checked variables are the queries;
+//   - lacking this, or when the set is so small that it is cheaper, every
+//     check asserts that the product of the differences of the variable with
+//     the elements of the set is zero, using [frontend.SetChecker] if the
+//     builder implements it.
+//
+// The lookup argument costs roughly one constraint per element of the set and
+// two constraints per check, while the product costs len(set)-1 constraints
+// per check.
+//
+// For the sets which are only known at proving time, for example a denylist
+// updated independently of the circuit, [NonMembershipProof] proves that a
+// value is not in a set committed as the Merkle root of its sorted elements.
+//
+// [Haböck22]: https://eprint.iacr.org/2022/1530
+package set
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/internal/logderivarg"
+)
+
+// Set is a set of constants against which the membership of variables is
+// asserted.
+type Set struct {
+	api      frontend.API
+	elements []frontend.Variable
+	queries  []frontend.Variable
+	closed   bool
+}
+
+// New returns a new [*Set] of the given elements and defers building the
+// membership checks. It panics if elements is empty or contains a
+// non-constant. Duplicate elements are ignored.
+func New(api frontend.API, elements ...frontend.Variable) *Set {
+	if len(elements) == 0 {
+		panic("set: empty set")
+	}
+	s := &Set{api: api}
+	seen := make(map[string]struct{}, len(elements))
+	for i := range elements {
+		c := constantValue(api, elements[i])
+		k := c.String()
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		s.elements = append(s.elements, c)
+	}
+	api.Compiler().Defer(s.commit)
+	return s
+}
+
+// constantValue returns the value of the constant v reduced modulo the native
+// field. It panics if v is not a constant.
+func constantValue(api frontend.API, v frontend.Variable) *big.Int {
+	if c, ok := api.Compiler().ConstantValue(v); ok {
+		return new(big.Int).Set(c)
+	}
+	// the test engine doesn't distinguish the constants from the variables,
+	// but then the value must be given as Go value.
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, string, big.Int, *big.Int:
+	default:
+		panic(fmt.Sprintf("set: element %v is not a constant", v))
+	}
+	c := utils.FromInterface(v)
+	return c.Mod(&c, api.Compiler().Field())
+}
+
+// Len returns the number of distinct elements of the set.
+func (s *Set) Len() int {
+	return len(s.elements)
+}
+
+// AssertIsMember asserts that every variable in vs is an element of the set. It
+// panics if the set is already committed.
+func (s *Set) AssertIsMember(vs ...frontend.Variable) {
+	if s.closed {
+		panic("set: asserting membership in committed set")
+	}
+	for _, v := range vs {
+		if _, isConst := s.api.Compiler().ConstantValue(v); isConst {
+			// checked and discarded at compile time
+			assertIsInSet(s.api, v, s.elements)
+			continue
+		}
+		s.queries = append(s.queries, v)
+	}
+}
+
+// AssertIsMember asserts that v is one of the constants in set. It is
+// equivalent to New(api, set...).AssertIsMember(v), so when checking several
+// variables against the same set, prefer building the [Set] once.
+func AssertIsMember(api frontend.API, v frontend.Variable, set ...frontend.Variable) {
+	New(api, set...).AssertIsMember(v)
+}
+
+func (s *Set) commit(api frontend.API) error {
+	if s.closed {
+		return nil
+	}
+	defer func() { s.closed = true }()
+	if len(s.queries) == 0 {
+		return nil
+	}
+	if !s.useLookup(api) {
+		for _, q := range s.queries {
+			assertIsInSet(api, q, s.elements)
+		}
+		return nil
+	}
+	return logderivarg.Build(api, logderivarg.AsTable(s.elements), logderivarg.AsTable(s.queries))
+}
+
+// assertIsInSet asserts that v is one of the constants in elements, with the
+// native check of the builder if it implements it.
+func assertIsInSet(api frontend.API, v frontend.Variable, elements []frontend.Variable) {
+	if sc, ok := api.(frontend.SetChecker); ok {
+		sc.AssertIsInSet(v, elements...)
+		return
+	}
+	prod := api.Sub(v, elements[0])
+	for _, e := range elements[1:] {
+		prod = api.Mul(prod, api.Sub(v, e))
+	}
+	api.AssertIsEqual(prod, 0)
+}
+
+// useLookup returns true if the builder supports the lookup argument and it is
+// cheaper than the product of differences.
+func (s *Set) useLookup(api frontend.API) bool {
+	if _, ok := api.(frontend.Committer); !ok {
+		return false
+	}
+	nbQueries, nbElements := len(s.queries), len(s.elements)
+	return nbQueries*(nbElements-1) > nbElements+2*nbQueries
+}
@@ -0,0 +1,83 @@
+package set
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+var opcodes = []frontend.Variable{0x00, 0x01, 0x02, 0x03, 0x10, 0x11, 0x14, 0x20, 0x35, 0x51, 0x52, 0x54, 0x55, 0x56, 0x57, 0xf3}
+
+type memberCircuit struct {
+	Ops   [8]frontend.Variable
+	Small frontend.Variable
+}
+
+func (c *memberCircuit) Define(api frontend.API) error {
+	// large set with many queries: lookup argument when supported
+	s := New(api, opcodes...)
+	s.AssertIsMember(c.Ops[:]...)
+	s.AssertIsMember(0x20)
+	// small set with a single query: product of differences
+	AssertIsMember(api, c.Small, 3, 5, 7, 5)
+	return nil
+}
+
+func TestAssertIsMember(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&memberCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&memberCircuit{
+			Ops:   [8]frontend.Variable{0x00, 0x52, 0x52, 0xf3, 0x35, 0x01, 0x57, 0x14},
+			Small: 7,
+		}),
+		test.WithInvalidAssignment(&memberCircuit{
+			Ops:   [8]frontend.Variable{0x00, 0x52, 0x53, 0xf3, 0x35, 0x01, 0x57, 0x14},
+			Small: 7,
+		}),
+		test.WithInvalidAssignment(&memberCircuit{
+			Ops:   [8]frontend.Variable{0x00, 0x52, 0x52, 0xf3, 0x35, 0x01, 0x57, 0x14},
+			Small: 4,
+		}),
+	)
+}
+
+type constantMemberCircuit struct {
+	X frontend.Variable
+}
+
+func (c *constantMemberCircuit) Define(api frontend.API) error {
+	New(api, opcodes...).AssertIsMember(0x21)
+	return nil
+}
+
+func TestAssertIsMemberConstant(t *testing.T) {
+	assert := test.NewAssert(t)
+	err := test.IsSolved(&constantMemberCircuit{}, &constantMemberCircuit{X: 0}, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+// noSetChecker hides the set check of the builder.
+type noSetChecker struct {
+	frontend.API
+}
+
+type genericInSetCircuit struct {
+	X frontend.Variable
+}
+
+func (c *genericInSetCircuit) Define(api frontend.API) error {
+	assertIsInSet(noSetChecker{api}, c.X, []frontend.Variable{3, 5, 7})
+	return nil
+}
+
+func TestAssertIsInSetGeneric(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&genericInSetCircuit{},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&genericInSetCircuit{X: 5}),
+		test.WithInvalidAssignment(&genericInSetCircuit{X: 4}),
+	)
+}
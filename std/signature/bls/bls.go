@@ -0,0 +1,254 @@
+// Package bls implements the verification of BLS signatures over BLS12-381
+// in-circuit.
+//
+// The signatures are in the minimal-pubkey-size variant of the IETF draft
+// [BLS signatures], used by Ethereum and drand: the public keys are in G1, the
+// signatures in G2 and the messages are hashed to G2 with the hash to curve
+// suite BLS12381G2_XMD:SHA-256_SSWU_RO_. The points are given either as
+// emulated points or in the compressed ZCash encoding, as they appear in the
+// messages of these protocols.
+//
+// The pairing over BLS12-381 is emulated, so a verification costs a few million
+// constraints over BN254. Aggregating the signatures of many signers of the
+// same message costs a point addition per signer only.
+//
+// [BLS signatures]: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05
+package bls
+
+import (
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// PublicKeySize is the size in bytes of a compressed public key.
+	PublicKeySize = bls12381.SizeOfG1AffineCompressed
+	// SignatureSize is the size in bytes of a compressed signature.
+	SignatureSize = bls12381.SizeOfG2AffineCompressed
+
+	// DSTBasic is the domain separation tag of the basic scheme.
+	DSTBasic = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+	// DSTAugmented is the domain separation tag of the message augmentation
+	// scheme.
+	DSTAugmented = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_"
+	// DSTProofOfPossession is the domain separation tag of the proof of
+	// possession scheme, used by Ethereum.
+	DSTProofOfPossession = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+// PublicKey is a public key, a point of G1.
+type PublicKey = sw_bls12381.G1Affine
+
+// Signature is a signature, a point of G2.
+type Signature = sw_bls12381.G2Affine
+
+// Verifier verifies BLS signatures in-circuit.
+type Verifier struct {
+	api     frontend.API
+	fp      *emulated.Field[emulated.BLS12381Fp]
+	g1      *sw_emulated.Curve[emulated.BLS12381Fp, emulated.BLS12381Fr]
+	g2      *sw_bls12381.G2
+	pairing *sw_bls12381.Pairing
+}
+
+// NewVerifier returns a new verifier of BLS signatures.
+func NewVerifier(api frontend.API) (*Verifier, error) {
+	fp, err := emulated.NewField[emulated.BLS12381Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	g1, err := sw_emulated.New[emulated.BLS12381Fp, emulated.BLS12381Fr](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return nil, fmt.Errorf("new curve: %w", err)
+	}
+	pairing, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return nil, fmt.Errorf("new pairing: %w", err)
+	}
+	return &Verifier{
+		api:     api,
+		fp:      fp,
+		g1:      g1,
+		g2:      sw_bls12381.NewG2(api),
+		pairing: pairing,
+	}, nil
+}
+
+// AssertIsValid asserts that sig is a valid signature of msg by pk with the
+// domain separation tag dst. It asserts that the signature is in G2, but the
+// public key is trusted to be in G1, see [Verifier.AssertIsOnG1].
+func (v *Verifier) AssertIsValid(pk *PublicKey, msg []uints.U8, sig *Signature, dst []byte) error {
+	return v.AssertAggregateIsValid([]*PublicKey{pk}, [][]uints.U8{msg}, sig, dst)
+}
+
+// AssertFastAggregateIsValid asserts that sig is a valid aggregate signature
+// of msg by all the public keys pks, that is a signature by their sum. It is
+// secure only if the possession of the keys was proven, in the proof of
+// possession scheme.
+func (v *Verifier) AssertFastAggregateIsValid(pks []*PublicKey, msg []uints.U8, sig *Signature, dst []byte) error {
+	if len(pks) == 0 {
+		return fmt.Errorf("no public key")
+	}
+	return v.AssertIsValid(v.Aggregate(pks, nil), msg, sig, dst)
+}
+
+// AssertAggregateIsValid asserts that sig is a valid aggregate signature of
+// the messages msgs by the respective public keys pks. In the basic scheme,
+// the messages must be distinct, which is not checked.
+func (v *Verifier) AssertAggregateIsValid(pks []*PublicKey, msgs [][]uints.U8, sig *Signature, dst []byte) error {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return fmt.Errorf("%d public keys for %d messages", len(pks), len(msgs))
+	}
+	v.pairing.AssertIsOnG2(sig)
+
+	// e(-g₁, σ) ∏ e(pkᵢ, H(mᵢ)) = 1
+	_, _, g1, _ := bls12381.Generators()
+	g1.Neg(&g1)
+	negG1 := sw_bls12381.NewG1Affine(g1)
+	P := []*sw_bls12381.G1Affine{&negG1}
+	Q := []*sw_bls12381.G2Affine{sig}
+	for i := range pks {
+		hm, err := v.g2.HashToG2(msgs[i], dst)
+		if err != nil {
+			return fmt.Errorf("hash to G2: %w", err)
+		}
+		P = append(P, pks[i])
+		Q = append(Q, hm)
+	}
+	return v.pairing.PairingCheck(P, Q)
+}
+
+// Aggregate returns the sum of the public keys pks. If participation is not
+// nil, it must have the length of pks and the key pks[i] is added only if
+// participation[i] is 1, which allows to aggregate a variable subset of a
+// fixed set of keys. At least one key must be added.
+func (v *Verifier) Aggregate(pks []*PublicKey, participation []frontend.Variable) *PublicKey {
+	if participation != nil && len(participation) != len(pks) {
+		panic("participation and public keys of different lengths")
+	}
+	infinity := &PublicKey{X: *v.fp.Zero(), Y: *v.fp.Zero()}
+	res := infinity
+	for i := range pks {
+		pk := pks[i]
+		if participation != nil {
+			v.api.AssertIsBoolean(participation[i])
+			pk = v.g1.Select(participation[i], pk, infinity)
+		}
+		res = v.g1.AddUnified(res, pk)
+	}
+	return res
+}
+
+// AssertIsOnG1 asserts that the public key pk is in G1, for the keys which are
+// not trusted.
+func (v *Verifier) AssertIsOnG1(pk *PublicKey) {
+	v.pairing.AssertIsOnG1(pk)
+}
+
+// PublicKeyFromBytes returns the public key encoded by the compressed bytes b.
+// It asserts that the point is on the curve but not that it is in G1, see
+// [Verifier.AssertIsOnG1]. The encoding of the point at infinity is rejected.
+func (v *Verifier) PublicKeyFromBytes(b *[PublicKeySize]uints.U8) *PublicKey {
+	head := v.flags(b[0])
+	return v.g1FromBytes(b, head)
+}
+
+// G1FromBytes returns the point of G1 encoded by the compressed bytes b and 1
+// if it is the point at infinity, in which case the returned point is (0,0).
+// Unlike [Verifier.PublicKeyFromBytes], it accepts the encoding of the point
+// at infinity, 0xc0 followed by zeros. It asserts that the other points are on
+// the curve but not that they are in G1, see [Verifier.AssertIsOnG1].
+func (v *Verifier) G1FromBytes(b *[PublicKeySize]uints.U8) (p *PublicKey, isInfinity frontend.Variable) {
+	head := v.api.ToBinary(b[0].Val, 8)
+	v.api.AssertIsEqual(head[7], 1)
+	isInfinity = head[6]
+	p = v.g1FromBytes(b, head)
+	// the point at infinity is encoded with x = 0 and no sign. Then y = ±2, p
+	// is on the curve and is replaced by (0,0).
+	v.api.AssertIsEqual(v.api.Mul(isInfinity, head[5]), 0)
+	v.api.AssertIsEqual(v.api.Mul(isInfinity, v.api.Sub(1, v.fp.IsZero(&p.X))), 0)
+	zero := v.fp.Zero()
+	return v.g1.Select(isInfinity, &PublicKey{X: *zero, Y: *zero}, p), isInfinity
+}
+
+// g1FromBytes returns the point on the curve encoded by the compressed bytes b,
+// whose first byte has the bits head.
+func (v *Verifier) g1FromBytes(b *[PublicKeySize]uints.U8, head []frontend.Variable) *PublicKey {
+	x := v.toElement(b[:], head[:5])
+	// y² = x³ + 4
+	y2 := v.fp.Mul(v.fp.Mul(x, x), x)
+	y2 = v.fp.Add(y2, v.fp.NewElement(4))
+	y := v.fp.Sqrt(y2)
+	large, _ := v.isLargest(y)
+	neg := v.api.Xor(head[5], large)
+	return &PublicKey{X: *x, Y: *v.fp.Select(neg, v.fp.Neg(y), y)}
+}
+
+// SignatureFromBytes returns the signature encoded by the compressed bytes b.
+// It asserts that the point is on the twist, the verification asserting that
+// it is in G2. The encoding of the point at infinity is rejected.
+func (v *Verifier) SignatureFromBytes(b *[SignatureSize]uints.U8) (*Signature, error) {
+	const size = SignatureSize / 2
+	head := v.flags(b[0])
+	x := fields_bls12381.E2{
+		A1: *v.toElement(b[:size], head[:5]),
+		A0: *v.toElement(b[size:], nil),
+	}
+	// y² = x³ + 4(1+u)
+	y, err := v.fp.NewHint(sqrtTwistHint, 2, &x.A0, &x.A1)
+	if err != nil {
+		return nil, err
+	}
+	res := &Signature{X: x, Y: fields_bls12381.E2{A0: *y[0], A1: *y[1]}}
+	v.pairing.AssertIsOnTwist(res)
+	// y is the lexicographically largest root when the flag is set
+	large0, _ := v.isLargest(&res.Y.A0)
+	large1, zero1 := v.isLargest(&res.Y.A1)
+	neg := v.api.Xor(head[5], v.api.Select(zero1, large0, large1))
+	res.Y = *v.g2.Ext2.Select(neg, v.g2.Ext2.Neg(&res.Y), &res.Y)
+	return res, nil
+}
+
+// flags returns the bits of the first byte of a compressed point, whose three
+// most significant bits are the flags compressed, infinity and sign. It
+// asserts that the point is compressed and not the infinity.
+func (v *Verifier) flags(b uints.U8) []frontend.Variable {
+	head := v.api.ToBinary(b.Val, 8)
+	v.api.AssertIsEqual(head[7], 1)
+	v.api.AssertIsEqual(head[6], 0)
+	return head
+}
+
+// toElement returns the element encoded in big-endian by b, where the bits of
+// the first byte are given by head if not nil. It asserts that the encoding is
+// canonical.
+func (v *Verifier) toElement(b []uints.U8, head []frontend.Variable) *emulated.Element[emulated.BLS12381Fp] {
+	bits := make([]frontend.Variable, 0, 8*len(b))
+	for i := len(b) - 1; i > 0; i-- {
+		bits = append(bits, v.api.ToBinary(b[i].Val, 8)...)
+	}
+	if head == nil {
+		head = v.api.ToBinary(b[0].Val, 8)
+	}
+	bits = append(bits, head...)
+	e := v.fp.FromBits(bits...)
+	v.fp.AssertIsInRange(e)
+	return e
+}
+
+// isLargest returns 1 if a is larger than (p-1)/2 and whether a is zero. An
+// element a ≠ 0 is larger than (p-1)/2 if and only if the canonical
+// representation of 2a is odd.
+func (v *Verifier) isLargest(a *emulated.Element[emulated.BLS12381Fp]) (large, isZero frontend.Variable) {
+	r := v.fp.Reduce(v.fp.Add(a, a))
+	v.fp.AssertIsInRange(r)
+	bits := v.api.ToBinary(r.Limbs[0], int(emulated.BLS12381Fp{}.BitsPerLimb()))
+	return bits[0], v.api.IsZero(v.api.Add(r.Limbs[0], r.Limbs[1], r.Limbs[2:]...))
+}
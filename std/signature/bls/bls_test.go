@@ -0,0 +1,153 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// sign returns the public key of the secret key sk and the signature of msg.
+func sign(t *testing.T, sk int64, msg []byte, dst string) (bls12381.G1Affine, bls12381.G2Affine) {
+	_, _, g1, _ := bls12381.Generators()
+	s := big.NewInt(sk)
+	var pk bls12381.G1Affine
+	pk.ScalarMultiplication(&g1, s)
+	hm, err := bls12381.HashToG2(msg, []byte(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig bls12381.G2Affine
+	sig.ScalarMultiplication(&hm, s)
+	return pk, sig
+}
+
+type verifyCircuit struct {
+	PublicKey [PublicKeySize]uints.U8
+	Msg       []uints.U8
+	Signature [SignatureSize]uints.U8
+}
+
+func (c *verifyCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	pk := v.PublicKeyFromBytes(&c.PublicKey)
+	v.AssertIsOnG1(pk)
+	sig, err := v.SignatureFromBytes(&c.Signature)
+	if err != nil {
+		return err
+	}
+	return v.AssertIsValid(pk, c.Msg, sig, []byte(DSTBasic))
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := []byte("hello")
+	pk, sig := sign(t, 123456789, msg, DSTBasic)
+	pkBytes, sigBytes := pk.Bytes(), sig.Bytes()
+	var witness verifyCircuit
+	copy(witness.PublicKey[:], uints.NewU8Array(pkBytes[:]))
+	copy(witness.Signature[:], uints.NewU8Array(sigBytes[:]))
+	witness.Msg = uints.NewU8Array(msg)
+	circuit := verifyCircuit{Msg: make([]uints.U8, len(msg))}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// another message
+	witness.Msg = uints.NewU8Array([]byte("hellO"))
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type fastAggregateCircuit struct {
+	PublicKeys    []PublicKey
+	Participation []frontend.Variable
+	Msg           []uints.U8
+	Signature     Signature
+}
+
+func (c *fastAggregateCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	pks := make([]*PublicKey, len(c.PublicKeys))
+	for i := range pks {
+		pks[i] = &c.PublicKeys[i]
+	}
+	return v.AssertIsValid(v.Aggregate(pks, c.Participation), c.Msg, &c.Signature, []byte(DSTProofOfPossession))
+}
+
+func TestFastAggregate(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := []byte("block root")
+	participation := []int{1, 0, 1}
+	witness := fastAggregateCircuit{Msg: uints.NewU8Array(msg)}
+	var aggSig bls12381.G2Affine
+	for i := range participation {
+		pk, sig := sign(t, int64(1000+i), msg, DSTProofOfPossession)
+		witness.PublicKeys = append(witness.PublicKeys, sw_bls12381.NewG1Affine(pk))
+		witness.Participation = append(witness.Participation, participation[i])
+		if participation[i] == 1 {
+			aggSig.Add(&aggSig, &sig)
+		}
+	}
+	witness.Signature = sw_bls12381.NewG2Affine(aggSig)
+	circuit := fastAggregateCircuit{
+		PublicKeys:    make([]PublicKey, len(participation)),
+		Participation: make([]frontend.Variable, len(participation)),
+		Msg:           make([]uints.U8, len(msg)),
+	}
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// another set of signers
+	witness.Participation[1] = 1
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type aggregateCircuit struct {
+	PublicKeys [2]PublicKey
+	Msgs       [2][4]uints.U8
+	Signature  Signature
+}
+
+func (c *aggregateCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	return v.AssertAggregateIsValid(
+		[]*PublicKey{&c.PublicKeys[0], &c.PublicKeys[1]},
+		[][]uints.U8{c.Msgs[0][:], c.Msgs[1][:]},
+		&c.Signature, []byte(DSTBasic))
+}
+
+func TestAggregate(t *testing.T) {
+	assert := test.NewAssert(t)
+	msgs := [2][]byte{[]byte("msg0"), []byte("msg1")}
+	var witness aggregateCircuit
+	var aggSig bls12381.G2Affine
+	for i := range msgs {
+		pk, sig := sign(t, int64(42+i), msgs[i], DSTBasic)
+		witness.PublicKeys[i] = sw_bls12381.NewG1Affine(pk)
+		copy(witness.Msgs[i][:], uints.NewU8Array(msgs[i]))
+		aggSig.Add(&aggSig, &sig)
+	}
+	witness.Signature = sw_bls12381.NewG2Affine(aggSig)
+	err := test.IsSolved(&aggregateCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the messages signed by the other keys
+	witness.PublicKeys[0], witness.PublicKeys[1] = witness.PublicKeys[1], witness.PublicKeys[0]
+	err = test.IsSolved(&aggregateCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
@@ -0,0 +1,41 @@
+package bls
+
+import (
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		sqrtTwistHint,
+	}
+}
+
+// sqrtTwistHint returns a square root of x³ + 4(1+u).
+func sqrtTwistHint(nativeMod *big.Int, nativeInputs, nativeOutputs []*big.Int) error {
+	return emulated.UnwrapHint(nativeInputs, nativeOutputs,
+		func(mod *big.Int, inputs, outputs []*big.Int) error {
+			var x, y, b bls12381.E2
+			x.A0.SetBigInt(inputs[0])
+			x.A1.SetBigInt(inputs[1])
+			b.A0.SetUint64(4)
+			b.A1.SetUint64(4)
+			y.Square(&x).Mul(&y, &x).Add(&y, &b)
+			if y.Legendre() < 0 {
+				return fmt.Errorf("x is not the abscissa of a point")
+			}
+			y.Sqrt(&y)
+			y.A0.BigInt(outputs[0])
+			y.A1.BigInt(outputs[1])
+			return nil
+		})
+}
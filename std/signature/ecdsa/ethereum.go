@@ -0,0 +1,84 @@
+package ecdsa
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Secp256k1PublicKey is the public key of an Ethereum account.
+type Secp256k1PublicKey = PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+
+// Secp256k1Signature is the signature of an Ethereum account.
+type Secp256k1Signature = Signature[emulated.Secp256k1Fr]
+
+// EthereumAddress returns the Ethereum address of the public key pk, that is
+// the last 20 bytes of the Keccak-256 hash of its coordinates. The address is
+// returned as a big-endian integer, so that it can be compared to an address
+// given as a public input. The native field must have more than 160 bits.
+func EthereumAddress(api frontend.API, pk *Secp256k1PublicKey) (frontend.Variable, error) {
+	if api.Compiler().FieldBitLen() <= 160 {
+		return nil, fmt.Errorf("native field too small for an address")
+	}
+	baseApi, err := emulated.NewField[emulated.Secp256k1Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	h, err := sha3.NewLegacyKeccak256(api)
+	if err != nil {
+		return nil, fmt.Errorf("new hash: %w", err)
+	}
+	h.Write(toBytes(api, baseApi, &pk.X))
+	h.Write(toBytes(api, baseApi, &pk.Y))
+	var res frontend.Variable = 0
+	for _, b := range h.Sum()[12:] {
+		res = api.Add(api.Mul(res, 256), b.Val)
+	}
+	return res, nil
+}
+
+// VerifyPersonalSign asserts that sig is a valid signature of msg by pk as
+// done by the personal_sign method of the Ethereum wallets ([EIP-191]): the
+// signed hash is the Keccak-256 of "\x19Ethereum Signed Message:\n", the
+// length of the message in decimal, and the message. Combined with
+// [EthereumAddress], it proves the ownership of an account.
+//
+// [EIP-191]: https://eips.ethereum.org/EIPS/eip-191
+func VerifyPersonalSign(api frontend.API, pk *Secp256k1PublicKey, msg []uints.U8, sig *Secp256k1Signature) error {
+	scalarApi, err := emulated.NewField[emulated.Secp256k1Fr](api)
+	if err != nil {
+		return fmt.Errorf("new scalar field: %w", err)
+	}
+	h, err := sha3.NewLegacyKeccak256(api)
+	if err != nil {
+		return fmt.Errorf("new hash: %w", err)
+	}
+	h.Write(uints.NewU8Array([]byte("\x19Ethereum Signed Message:\n" + strconv.Itoa(len(msg)))))
+	h.Write(msg)
+	digest := h.Sum()
+	hashBits := make([]frontend.Variable, 0, 8*len(digest))
+	for i := len(digest) - 1; i >= 0; i-- {
+		hashBits = append(hashBits, bits.ToBinary(api, digest[i].Val, bits.WithNbDigits(8))...)
+	}
+	pk.Verify(api, sw_emulated.GetSecp256k1Params(), scalarApi.FromBits(hashBits...), sig)
+	return nil
+}
+
+// toBytes returns the 32 bytes of the canonical representation of e in
+// big-endian.
+func toBytes(api frontend.API, f *emulated.Field[emulated.Secp256k1Fp], e *emulated.Element[emulated.Secp256k1Fp]) []uints.U8 {
+	r := f.Reduce(e)
+	f.AssertIsInRange(r)
+	eBits := f.ToBits(r)
+	res := make([]uints.U8, 32)
+	for i := range res {
+		res[len(res)-1-i] = uints.U8{Val: bits.FromBinary(api, eBits[8*i:8*i+8])}
+	}
+	return res
+}
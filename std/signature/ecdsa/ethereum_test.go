@@ -0,0 +1,94 @@
+package ecdsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/ecdsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/sha3"
+)
+
+type ethereumAddressCircuit struct {
+	Pub     Secp256k1PublicKey
+	Address frontend.Variable
+}
+
+func (c *ethereumAddressCircuit) Define(api frontend.API) error {
+	addr, err := EthereumAddress(api, &c.Pub)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(addr, c.Address)
+	return nil
+}
+
+func TestEthereumAddress(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the account of the private key 1
+	_, g := secp256k1.Generators()
+	address, _ := new(big.Int).SetString("7E5F4552091A69125d5DfCb7b8C2659029395Bdf", 16)
+	witness := ethereumAddressCircuit{
+		Pub: Secp256k1PublicKey{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](g.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](g.Y),
+		},
+		Address: address,
+	}
+	err := test.IsSolved(&ethereumAddressCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type personalSignCircuit struct {
+	Pub Secp256k1PublicKey
+	Msg []uints.U8
+	Sig Secp256k1Signature
+}
+
+func (c *personalSignCircuit) Define(api frontend.API) error {
+	return VerifyPersonalSign(api, &c.Pub, c.Msg, &c.Sig)
+}
+
+func TestVerifyPersonalSign(t *testing.T) {
+	assert := test.NewAssert(t)
+	privKey, err := ecdsa.GenerateKey(rand.Reader)
+	assert.NoError(err)
+
+	msg := []byte("I own this account")
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte("\x19Ethereum Signed Message:\n" + strconv.Itoa(len(msg))))
+	h.Write(msg)
+	// the hash is signed as is
+	sigBin, err := privKey.Sign(h.Sum(nil), nil)
+	assert.NoError(err)
+	var sig ecdsa.Signature
+	_, err = sig.SetBytes(sigBin)
+	assert.NoError(err)
+
+	witness := personalSignCircuit{
+		Pub: Secp256k1PublicKey{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.X),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](privKey.PublicKey.A.Y),
+		},
+		Msg: uints.NewU8Array(msg),
+		Sig: Secp256k1Signature{
+			R: emulated.ValueOf[emulated.Secp256k1Fr](new(big.Int).SetBytes(sig.R[:32])),
+			S: emulated.ValueOf[emulated.Secp256k1Fr](new(big.Int).SetBytes(sig.S[:32])),
+		},
+	}
+	circuit := personalSignCircuit{Msg: make([]uints.U8, len(msg))}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// another message
+	witness.Msg = uints.NewU8Array([]byte("I own this accounT"))
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
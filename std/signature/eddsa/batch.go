@@ -0,0 +1,299 @@
+package eddsa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/multicommit"
+)
+
+// BatchVerify verifies the signatures sigs of the messages msgs by the public
+// keys pubKeys, hashed as in [Verify].
+//
+// Instead of checking [S]G = R + [H(R,A,M)]A for each signature, it checks a
+// random linear combination of the equations
+//
+//	[Σ zᵢSᵢ]G = Σ [zᵢ]Rᵢ + [zᵢH(Rᵢ,Aᵢ,Mᵢ)]Aᵢ
+//
+// with a single multi-scalar multiplication, so that the doublings are shared
+// between all the signatures. The coefficients zᵢ of about 128 bits are
+// derived from a commitment to the inputs, see [multicommit.WithCommitment],
+// so that they are unpredictable to the prover. As in [Verify], the equation
+// is multiplied by the cofactor, hence a batch is valid if and only if all its
+// signatures are valid, up to a negligible probability.
+//
+// The cost per signature is lower than with [Verify], at the price of a fixed
+// cost for the multiplication of the base point, so that batching pays off
+// from a few signatures on. The verification is done when the circuit is
+// finalized, and uses the hash function until then.
+func BatchVerify(curve twistededwards.Curve, sigs []Signature, msgs []frontend.Variable, pubKeys []PublicKey, hash hash.FieldHasher) error {
+	if len(sigs) != len(msgs) || len(sigs) != len(pubKeys) {
+		return fmt.Errorf("got %d signatures, %d messages and %d public keys", len(sigs), len(msgs), len(pubKeys))
+	}
+	if len(sigs) == 0 {
+		return nil
+	}
+	v, err := NewBatchVerifier(curve, hash)
+	if err != nil {
+		return err
+	}
+	for i := range sigs {
+		v.Add(sigs[i], msgs[i], pubKeys[i])
+	}
+	return nil
+}
+
+// BatchVerifier accumulates signatures to be verified in a single batch as
+// with [BatchVerify]. It is useful when the signatures are verified in
+// different places of the circuit, for example one per transaction of a
+// rollup block, as they are all verified together when the circuit is
+// finalized.
+type BatchVerifier struct {
+	v batchVerifier
+}
+
+// NewBatchVerifier returns a new batch verifier for the signatures on the
+// curve, with the messages hashed with hash. The hash function is used until
+// the circuit is finalized.
+func NewBatchVerifier(curve twistededwards.Curve, hash hash.FieldHasher) (*BatchVerifier, error) {
+	if !curve.Params().Cofactor.IsUint64() {
+		return nil, errors.New("invalid cofactor")
+	}
+	fieldBits := curve.API().Compiler().FieldBitLen()
+	challengeBits := 128
+	if challengeBits > (fieldBits-1)/2 {
+		challengeBits = (fieldBits - 1) / 2
+	}
+	if 3*((challengeBits+4)/2)+3 >= fieldBits {
+		return nil, errors.New("native field too small")
+	}
+	return &BatchVerifier{v: batchVerifier{curve: curve, hash: hash, challengeBits: challengeBits}}, nil
+}
+
+// Add adds the signature sig of the message msg by pubKey to the batch.
+func (b *BatchVerifier) Add(sig Signature, msg frontend.Variable, pubKey PublicKey) {
+	v := &b.v
+	// Hᵢ = H(Rᵢ,Aᵢ,Mᵢ) binds the points and the message, so that it is
+	// sufficient to commit to Hᵢ and Sᵢ
+	v.hash.Reset()
+	v.hash.Write(sig.R.X, sig.R.Y, pubKey.A.X, pubKey.A.Y, msg)
+	hRAM := v.hash.Sum()
+	api := v.curve.API()
+	if len(v.sigs) == 0 {
+		multicommit.WithCommitment(api, v.verify, hRAM, sig.S)
+	} else {
+		multicommit.AppendCommitted(api, hRAM, sig.S)
+	}
+	v.sigs = append(v.sigs, sig)
+	v.pubKeys = append(v.pubKeys, pubKey)
+	v.hRAM = append(v.hRAM, hRAM)
+}
+
+type batchVerifier struct {
+	curve         twistededwards.Curve
+	sigs          []Signature
+	pubKeys       []PublicKey
+	hRAM          []frontend.Variable
+	hash          hash.FieldHasher
+	challengeBits int
+}
+
+func (v *batchVerifier) verify(api frontend.API, commitment frontend.Variable) error {
+	curve := v.curve
+	sigs := v.sigs
+	order := curve.Params().Order
+	fieldBits := api.Compiler().FieldBitLen()
+	c := v.challengeBits
+
+	// the coefficients are taken by pairs in the bits of a chain of hashes. The
+	// decomposition may not be canonical, which only leaves the choice between
+	// two coefficients to the prover.
+	zBits := make([][]frontend.Variable, len(sigs))
+	seed := commitment
+	for i := 0; i < len(sigs); i += 2 {
+		v.hash.Reset()
+		v.hash.Write(seed)
+		seed = v.hash.Sum()
+		b := bits.ToBinary(api, seed, bits.OmitModulusCheck())
+		zBits[i] = b[:c]
+		if i+1 < len(sigs) {
+			zBits[i+1] = b[c : 2*c]
+		}
+	}
+
+	// [Σ zᵢSᵢ]G = Σₖ [Σᵢ zᵢSᵢₖ][2^(k·limbBits)]G where Sᵢₖ are the limbs of
+	// Sᵢ, such that the sums fit in the native field
+	limbBits := fieldBits - c - mbits.Len(uint(len(sigs))) - 1
+	nbLimbs := (fieldBits + limbBits - 1) / limbBits
+	limbBits = (fieldBits + nbLimbs - 1) / nbLimbs
+	sumBits := limbBits + c + mbits.Len(uint(len(sigs)))
+	sums := make([]frontend.Variable, nbLimbs)
+	for k := range sums {
+		sums[k] = 0
+	}
+	eBits := make([][]frontend.Variable, len(sigs))
+	negA := make([][4]twistededwards.Point, len(sigs))
+	negR := make([]twistededwards.Point, len(sigs))
+	negAR := make([]twistededwards.Point, len(sigs))
+	for i := range sigs {
+		z := bits.FromBinary(api, zBits[i])
+		sBits := api.ToBinary(sigs[i].S)
+		for k := range sums {
+			end := (k + 1) * limbBits
+			if end > len(sBits) {
+				end = len(sBits)
+			}
+			limb := bits.FromBinary(api, sBits[k*limbBits:end])
+			sums[k] = api.Add(sums[k], api.Mul(z, limb))
+		}
+		eBits[i] = mulModOrder(api, zBits[i], api.ToBinary(v.hRAM[i]), order)
+		// pad to an even number of bits above the coefficient
+		if (len(eBits[i])-c)%2 == 1 {
+			eBits[i] = append(eBits[i], 0)
+		}
+
+		// 0, -A, -2A, -3A for the windows of two bits of the scalar of A
+		// above the coefficient, and -R, -A-R for the bits below
+		negA[i][0] = twistededwards.Point{X: 0, Y: 1}
+		negA[i][1] = curve.Neg(v.pubKeys[i].A)
+		negA[i][2] = curve.Double(negA[i][1])
+		negA[i][3] = curve.Add(negA[i][2], negA[i][1])
+		negR[i] = curve.Neg(sigs[i].R)
+		negAR[i] = curve.Add(negA[i][1], negR[i])
+	}
+	sumsBits := make([][]frontend.Variable, nbLimbs, nbLimbs+1)
+	for k := range sums {
+		sumsBits[k] = bits.ToBinary(api, sums[k], bits.WithNbDigits(sumBits))
+	}
+
+	// the bases [2^(k·limbBits)]G, and the sums of consecutive pairs of them,
+	// are constants. We pad them to an even number with the neutral element.
+	bases := make([]twistededwards.Point, nbLimbs, nbLimbs+1)
+	bases[0] = twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+	for k := 1; k < nbLimbs; k++ {
+		bases[k] = bases[k-1]
+		for j := 0; j < limbBits; j++ {
+			bases[k] = curve.Double(bases[k])
+		}
+	}
+	if nbLimbs%2 == 1 {
+		bases = append(bases, twistededwards.Point{X: 0, Y: 1})
+		zeros := make([]frontend.Variable, sumBits)
+		for j := range zeros {
+			zeros[j] = 0
+		}
+		sumsBits = append(sumsBits, zeros)
+	}
+	baseSums := make([]twistededwards.Point, len(bases)/2)
+	for k := range baseSums {
+		baseSums[k] = curve.Add(bases[2*k], bases[2*k+1])
+	}
+
+	// Q = [Σ zᵢSᵢ]G - Σ [zᵢ]Rᵢ + [zᵢHᵢ]Aᵢ
+	n := sumBits
+	for i := range eBits {
+		if len(eBits[i]) > n {
+			n = len(eBits[i])
+		}
+	}
+	Q := twistededwards.Point{X: 0, Y: 1}
+	for j := n - 1; j >= 0; j-- {
+		Q = curve.Double(Q)
+		if j < sumBits {
+			for k := range baseSums {
+				b0, b1 := sumsBits[2*k][j], sumsBits[2*k+1][j]
+				Q = curve.Add(Q, twistededwards.Point{
+					X: api.Lookup2(b0, b1, 0, bases[2*k].X, bases[2*k+1].X, baseSums[k].X),
+					Y: api.Lookup2(b0, b1, 1, bases[2*k].Y, bases[2*k+1].Y, baseSums[k].Y),
+				})
+			}
+		}
+		for i := range sigs {
+			var tmp twistededwards.Point
+			switch {
+			case j < c:
+				tmp.X = api.Lookup2(eBits[i][j], zBits[i][j], 0, negA[i][1].X, negR[i].X, negAR[i].X)
+				tmp.Y = api.Lookup2(eBits[i][j], zBits[i][j], 1, negA[i][1].Y, negR[i].Y, negAR[i].Y)
+			case j < len(eBits[i]) && (j-c)%2 == 0:
+				// window of the bits j and j+1
+				tmp.X = api.Lookup2(eBits[i][j], eBits[i][j+1], negA[i][0].X, negA[i][1].X, negA[i][2].X, negA[i][3].X)
+				tmp.Y = api.Lookup2(eBits[i][j], eBits[i][j+1], negA[i][0].Y, negA[i][1].Y, negA[i][2].Y, negA[i][3].Y)
+			default:
+				continue
+			}
+			Q = curve.Add(Q, tmp)
+		}
+	}
+
+	// [cofactor]*(lhs-rhs)
+	Q, err := clearCofactor(curve, Q)
+	if err != nil {
+		return err
+	}
+
+	api.AssertIsEqual(Q.X, 0)
+	api.AssertIsEqual(Q.Y, 1)
+
+	return nil
+}
+
+// mulModOrder returns the order.BitLen() bits of a representative of x·y
+// modulo order, given the bits of x and the canonical bits of y. The
+// representative is not necessarily reduced, which is sufficient for the
+// multiplication of points of order order.
+//
+// The product x·y = q·order + e is checked as an equality of integers by
+// checking it modulo the native modulus r and modulo 2^(2w), for w such that
+// both sides are smaller than r·2^(2w). The latter is done on the limbs of w
+// bits of the operands, whose products fit in the native field.
+func mulModOrder(api frontend.API, xBits, yBits []frontend.Variable, order *big.Int) []frontend.Variable {
+	fieldBits := api.Compiler().FieldBitLen()
+	w := (len(xBits) + 4) / 2
+	qBits := len(xBits) + fieldBits - order.BitLen() + 1
+	if qBits > 2*w {
+		panic("order too small")
+	}
+
+	x := bits.FromBinary(api, xBits)
+	y := bits.FromBinary(api, yBits)
+	res, err := api.Compiler().NewHint(mulModOrderHint, 3, x, y, order, w)
+	if err != nil {
+		panic(err)
+	}
+	q, e, k := res[0], res[1], res[2]
+	api.AssertIsEqual(api.Mul(x, y), api.Add(api.Mul(q, order), e))
+
+	eBits := bits.ToBinary(api, e, bits.WithNbDigits(order.BitLen()))
+	qb := bits.ToBinary(api, q, bits.WithNbDigits(qBits))
+	bits.ToBinary(api, k, bits.WithNbDigits(w+3))
+
+	limbs := func(b []frontend.Variable) (frontend.Variable, frontend.Variable) {
+		if len(b) > 2*w {
+			b = b[:2*w]
+		}
+		return bits.FromBinary(api, b[:w]), bits.FromBinary(api, b[w:])
+	}
+	x0, x1 := limbs(xBits)
+	y0, y1 := limbs(yBits)
+	q0, q1 := limbs(qb)
+	e0, e1 := limbs(eBits)
+	var l0, l1 big.Int
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(w)), big.NewInt(1))
+	l0.And(order, mask)
+	l1.Rsh(order, uint(w)).And(&l1, mask)
+
+	// T = x·y - q·order - e mod 2^(2w) before the reduction, and |T| < 2^(3w+2)
+	// is a multiple of 2^(2w), which is checked with the shift of k by 2^(w+2)
+	lhs := api.Add(api.Mul(x0, y0), api.Mul(api.Add(api.Mul(x0, y1), api.Mul(x1, y0)), new(big.Int).Lsh(big.NewInt(1), uint(w))))
+	rhs := api.Add(api.Mul(q0, &l0), api.Mul(api.Add(api.Mul(q0, &l1), api.Mul(q1, &l0)), new(big.Int).Lsh(big.NewInt(1), uint(w))), e0, api.Mul(e1, new(big.Int).Lsh(big.NewInt(1), uint(w))))
+	shift := new(big.Int).Lsh(big.NewInt(1), uint(3*w+2))
+	api.AssertIsEqual(api.Add(lhs, shift), api.Add(rhs, api.Mul(k, new(big.Int).Lsh(big.NewInt(1), uint(2*w)))))
+
+	return eBits
+}
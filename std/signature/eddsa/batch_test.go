@@ -0,0 +1,111 @@
+package eddsa
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/test"
+)
+
+type batchCircuit struct {
+	curveID    tedwards.ID
+	accumulate bool
+	PublicKeys []PublicKey
+	Signatures []Signature
+	Messages   []frontend.Variable
+}
+
+func (circuit *batchCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	if !circuit.accumulate {
+		return BatchVerify(curve, circuit.Signatures, circuit.Messages, circuit.PublicKeys, &mimc)
+	}
+	// add the signatures one by one, interleaved with other users of the
+	// commitment
+	v, err := NewBatchVerifier(curve, &mimc)
+	if err != nil {
+		return err
+	}
+	for i := range circuit.Signatures {
+		v.Add(circuit.Signatures[i], circuit.Messages[i], circuit.PublicKeys[i])
+		multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+			api.AssertIsDifferent(commitment, 0)
+			return nil
+		}, circuit.Messages[i])
+	}
+	return nil
+}
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	const nbSignatures = 3
+	confs := []struct {
+		hash  hash.Hash
+		curve tedwards.ID
+	}{
+		{hash.MIMC_BN254, tedwards.BN254},
+		{hash.MIMC_BLS12_377, tedwards.BLS12_377},
+	}
+	randomness := rand.New(rand.NewSource(42)) //#nosec G404 -- test only
+
+	for _, conf := range confs {
+		snarkField, err := twistededwards.GetSnarkField(conf.curve)
+		assert.NoError(err)
+
+		circuit := batchCircuit{
+			curveID:    conf.curve,
+			PublicKeys: make([]PublicKey, nbSignatures),
+			Signatures: make([]Signature, nbSignatures),
+			Messages:   make([]frontend.Variable, nbSignatures),
+		}
+		validWitness := batchCircuit{
+			PublicKeys: make([]PublicKey, nbSignatures),
+			Signatures: make([]Signature, nbSignatures),
+			Messages:   make([]frontend.Variable, nbSignatures),
+		}
+		for i := 0; i < nbSignatures; i++ {
+			privKey, err := eddsa.New(conf.curve, randomness)
+			assert.NoError(err)
+			var msg big.Int
+			msg.Rand(randomness, snarkField)
+			msgData := make([]byte, len(snarkField.Bytes()))
+			msg.FillBytes(msgData)
+			signature, err := privKey.Sign(msgData, conf.hash.New())
+			assert.NoError(err)
+
+			validWitness.Messages[i] = msg
+			validWitness.PublicKeys[i].Assign(conf.curve, privKey.Public().Bytes())
+			validWitness.Signatures[i].Assign(conf.curve, signature)
+		}
+
+		// a single invalid signature invalidates the batch
+		invalidWitness := validWitness
+		invalidWitness.Messages = append([]frontend.Variable{}, validWitness.Messages...)
+		invalidWitness.Messages[1] = 1
+
+		for _, accumulate := range []bool{false, true} {
+			circuit.accumulate = accumulate
+			assert.CheckCircuit(&circuit,
+				test.WithValidAssignment(&validWitness),
+				test.WithInvalidAssignment(&invalidWitness),
+				test.WithCurves(utils.FieldToCurve(snarkField)))
+		}
+	}
+}
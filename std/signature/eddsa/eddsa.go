@@ -79,11 +79,24 @@ func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pu
 	Q = curve.Add(curve.Neg(Q), sig.R)
 
 	// [cofactor]*(lhs-rhs)
+	Q, err := clearCofactor(curve, Q)
+	if err != nil {
+		return err
+	}
+
+	curve.API().AssertIsEqual(Q.X, 0)
+	curve.API().AssertIsEqual(Q.Y, 1)
+
+	return nil
+}
+
+// clearCofactor returns [cofactor]Q.
+func clearCofactor(curve twistededwards.Curve, Q twistededwards.Point) (twistededwards.Point, error) {
 	log := logger.Logger()
 	if !curve.Params().Cofactor.IsUint64() {
 		err := errors.New("invalid cofactor")
 		log.Err(err).Str("cofactor", curve.Params().Cofactor.String()).Send()
-		return err
+		return Q, err
 	}
 	cofactor := curve.Params().Cofactor.Uint64()
 	switch cofactor {
@@ -94,11 +107,7 @@ func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pu
 	default:
 		log.Warn().Str("cofactor", curve.Params().Cofactor.String()).Msg("curve cofactor is not implemented")
 	}
-
-	curve.API().AssertIsEqual(Q.X, 0)
-	curve.API().AssertIsEqual(Q.Y, 1)
-
-	return nil
+	return Q, nil
 }
 
 // Assign is a helper to assigned a compressed binary public key representation into its uncompressed form
@@ -0,0 +1,63 @@
+package eddsa
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{
+		mulModOrderHint,
+	}
+}
+
+// mulModOrderHint returns the quotient q and the remainder e of the division
+// of x·y by order, and the quotient shifted by 2^(w+2) of the division by
+// 2^(2w) of the difference of x·y and q·order+e on their limbs of w bits, as
+// checked in mulModOrder.
+func mulModOrderHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 4 || len(outputs) != 3 {
+		return fmt.Errorf("expected 4 inputs and 3 outputs")
+	}
+	x, y, order := inputs[0], inputs[1], inputs[2]
+	w := uint(inputs[3].Uint64())
+
+	var q, e big.Int
+	q.DivMod(new(big.Int).Mul(x, y), order, &e)
+
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), w), big.NewInt(1))
+	limbs := func(v *big.Int) (*big.Int, *big.Int) {
+		v0 := new(big.Int).And(v, mask)
+		v1 := new(big.Int).Rsh(v, w)
+		v1.And(v1, mask)
+		return v0, v1
+	}
+	x0, x1 := limbs(x)
+	y0, y1 := limbs(y)
+	q0, q1 := limbs(&q)
+	e0, e1 := limbs(&e)
+	l0, l1 := limbs(order)
+	// t = x0y0 + (x0y1+x1y0)2^w - q0l0 - (q0l1+q1l0)2^w - e0 - e1·2^w
+	var t, tmp big.Int
+	t.Mul(x0, y0)
+	tmp.Mul(x0, y1).Add(&tmp, new(big.Int).Mul(x1, y0)).Lsh(&tmp, w)
+	t.Add(&t, &tmp)
+	t.Sub(&t, new(big.Int).Mul(q0, l0))
+	tmp.Mul(q0, l1).Add(&tmp, new(big.Int).Mul(q1, l0)).Lsh(&tmp, w)
+	t.Sub(&t, &tmp)
+	t.Sub(&t, e0)
+	t.Sub(&t, new(big.Int).Lsh(e1, w))
+	t.Add(&t, new(big.Int).Lsh(big.NewInt(1), 3*w+2))
+
+	outputs[0].Set(&q)
+	outputs[1].Set(&e)
+	outputs[2].Rsh(&t, 2*w)
+	return nil
+}
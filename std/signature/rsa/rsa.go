@@ -0,0 +1,242 @@
+// Package rsa implements the verification of RSA signatures in-circuit, with
+// the PKCS #1 v1.5 and PSS encodings of [RFC 8017].
+//
+// The modulus of the public key is a witness, so that a single circuit
+// verifies the signatures of any key of the given size, for example the
+// DKIM-Signature of an email or the signature of an X.509 certificate. The
+// public exponent is fixed when defining the circuit. The modular
+// exponentiation relies on [bigint.Arithmetic], and a verification costs
+// about 17 modular multiplications for the usual exponent 65537.
+//
+// The verification only proves that the signature is valid for the modulus of
+// the witness. If the modulus is a private input, the prover picks the key
+// and the statement is vacuous. Callers must make the modulus public, by
+// tagging the [PublicKey] field of their circuit with gnark:",public", or bind
+// it to a public input, for example a commitment to the key checked in the
+// circuit.
+//
+// [RFC 8017]: https://www.rfc-editor.org/rfc/rfc8017
+package rsa
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bigint"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// DefaultExponent is the public exponent of the keys returned by
+// [PlaceholderPublicKey].
+const DefaultExponent = 65537
+
+// PublicKey is an RSA public key. The modulus is part of the witness, the
+// public exponent and the size of the modulus are part of the circuit
+// definition. The modulus must be public or bound to a public input, see the
+// package documentation.
+type PublicKey struct {
+	N      bigint.Int
+	E      int `gnark:"-"`
+	NbBits int `gnark:"-"`
+}
+
+// PlaceholderPublicKey returns a public key of nbBits bits with the exponent
+// [DefaultExponent], to be used in the definition of a circuit.
+func PlaceholderPublicKey(nbBits int) PublicKey {
+	return PublicKey{N: bigint.Placeholder(nbBits), E: DefaultExponent, NbBits: nbBits}
+}
+
+// ValueOfPublicKey returns the witness assignment of the public key pk.
+func ValueOfPublicKey(pk *rsa.PublicKey) PublicKey {
+	nbBits := pk.N.BitLen()
+	return PublicKey{N: bigint.ValueOf(pk.N, nbBits), E: pk.E, NbBits: nbBits}
+}
+
+// Signature is an RSA signature, as an integer smaller than the modulus.
+type Signature struct {
+	S bigint.Int
+}
+
+// PlaceholderSignature returns a signature for a key of nbBits bits, to be
+// used in the definition of a circuit.
+func PlaceholderSignature(nbBits int) Signature {
+	return Signature{S: bigint.Placeholder(nbBits)}
+}
+
+// ValueOfSignature returns the witness assignment of the signature sig for a
+// key of nbBits bits.
+func ValueOfSignature(sig []byte, nbBits int) Signature {
+	return Signature{S: bigint.ValueOf(new(big.Int).SetBytes(sig), nbBits)}
+}
+
+// Verifier verifies RSA signatures in-circuit.
+type Verifier struct {
+	api   frontend.API
+	big   *bigint.Arithmetic
+	bytes *uints.BinaryField[uints.U32]
+}
+
+// NewVerifier returns a new RSA signature verifier.
+func NewVerifier(api frontend.API) (*Verifier, error) {
+	arith, err := bigint.New(api)
+	if err != nil {
+		return nil, fmt.Errorf("new bigint arithmetic: %w", err)
+	}
+	bytes, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, fmt.Errorf("new uints api: %w", err)
+	}
+	return &Verifier{api: api, big: arith, bytes: bytes}, nil
+}
+
+// hashPrefixes are the DER encodings of the DigestInfo of PKCS #1 v1.5, up to
+// the digest itself.
+var hashPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// VerifyPKCS1v15 asserts that sig is a valid PKCS #1 v1.5 signature by pk of
+// the digest hashed, computed with the hash function h. The digest is
+// usually computed in-circuit, and h is only used for the encoding. When the
+// digest is given as witness, its bytes must be range checked by the caller.
+func (v *Verifier) VerifyPKCS1v15(pk *PublicKey, h crypto.Hash, hashed []uints.U8, sig *Signature) error {
+	prefix, ok := hashPrefixes[h]
+	if !ok {
+		return fmt.Errorf("unsupported hash function %s", h)
+	}
+	if len(hashed) != h.Size() {
+		return fmt.Errorf("digest of %d bytes for %s", len(hashed), h)
+	}
+	k := (pk.NbBits + 7) / 8
+	psLen := k - len(prefix) - len(hashed) - 3
+	if psLen < 8 {
+		return fmt.Errorf("modulus of %d bits too small", pk.NbBits)
+	}
+	m := v.encrypt(pk, sig)
+
+	// EM = 0x00 ‖ 0x01 ‖ PS ‖ 0x00 ‖ prefix ‖ hashed, where PS is 0xff bytes
+	em := make([]byte, 2+psLen+1, k)
+	em[1] = 0x01
+	for i := 2; i < 2+psLen; i++ {
+		em[i] = 0xff
+	}
+	em = append(em, prefix...)
+	encoded := append(uints.NewU8Array(em), hashed...)
+	v.big.AssertIsEqual(m, v.big.FromBytes(encoded))
+	return nil
+}
+
+// VerifyPSS asserts that sig is a valid PSS signature by pk of the digest
+// hashed, with a salt of saltLen bytes. The hash function h is used for the
+// encoding and the mask generation function, it must be one of SHA-256,
+// SHA-384 and SHA-512.
+func (v *Verifier) VerifyPSS(pk *PublicKey, h crypto.Hash, hashed []uints.U8, sig *Signature, saltLen int) error {
+	if len(hashed) != h.Size() {
+		return fmt.Errorf("digest of %d bytes for %s", len(hashed), h)
+	}
+	if _, err := v.newHash(h); err != nil {
+		return err
+	}
+	emBits := pk.NbBits - 1
+	emLen := (emBits + 7) / 8
+	hLen := h.Size()
+	if emLen < hLen+saltLen+2 {
+		return fmt.Errorf("modulus of %d bits too small", pk.NbBits)
+	}
+	m := v.encrypt(pk, sig)
+
+	// EM = maskedDB ‖ H ‖ 0xbc, with the leftmost 8·emLen-emBits bits of
+	// maskedDB set to zero
+	em := v.big.ToBytes(m, emLen)
+	v.api.AssertIsEqual(em[emLen-1].Val, 0xbc)
+	dbLen := emLen - hLen - 1
+	maskedDB, digest := em[:dbLen], em[dbLen:emLen-1]
+	nbZeroBits := 8*emLen - emBits
+	lead := v.api.ToBinary(maskedDB[0].Val, 8)
+	for i := 8 - nbZeroBits; i < 8; i++ {
+		v.api.AssertIsEqual(lead[i], 0)
+	}
+
+	// DB = maskedDB ⊕ MGF1(H) = PS ‖ 0x01 ‖ salt, where PS is zero bytes. The
+	// leftmost bits of the mask are ignored.
+	db := v.xor(maskedDB, v.mgf1(h, digest, dbLen))
+	first := v.api.ToBinary(db[0].Val, 8)
+	db[0] = uints.U8{Val: v.api.FromBinary(first[:8-nbZeroBits]...)}
+	psLen := dbLen - saltLen - 1
+	for i := 0; i < psLen; i++ {
+		v.api.AssertIsEqual(db[i].Val, 0)
+	}
+	v.api.AssertIsEqual(db[psLen].Val, 1)
+	salt := db[psLen+1:]
+
+	// H = Hash(0x00 × 8 ‖ hashed ‖ salt)
+	hf, _ := v.newHash(h)
+	hf.Write(uints.NewU8Array(make([]byte, 8)))
+	hf.Write(hashed)
+	hf.Write(salt)
+	expected := hf.Sum()
+	for i := range digest {
+		v.bytes.ByteAssertEq(digest[i], expected[i])
+	}
+	return nil
+}
+
+// encrypt returns sig^e mod n, after asserting that sig is smaller than the
+// modulus.
+func (v *Verifier) encrypt(pk *PublicKey, sig *Signature) *bigint.Int {
+	v.big.AssertIsWellFormed(&pk.N)
+	v.big.AssertIsWellFormed(&sig.S)
+	v.big.AssertIsLess(&sig.S, &pk.N)
+	return v.big.ModExp(&sig.S, big.NewInt(int64(pk.E)), &pk.N)
+}
+
+// mgf1 returns the first length bytes of the mask generated from seed.
+func (v *Verifier) mgf1(h crypto.Hash, seed []uints.U8, length int) []uints.U8 {
+	res := make([]uints.U8, 0, length+h.Size())
+	for counter := uint32(0); len(res) < length; counter++ {
+		hf, _ := v.newHash(h)
+		hf.Write(seed)
+		hf.Write(uints.NewU8Array([]byte{byte(counter >> 24), byte(counter >> 16), byte(counter >> 8), byte(counter)}))
+		res = append(res, hf.Sum()...)
+	}
+	return res[:length]
+}
+
+// xor returns the bytewise exclusive or of a and b, of same length.
+func (v *Verifier) xor(a, b []uints.U8) []uints.U8 {
+	res := make([]uints.U8, len(a))
+	for i := 0; i < len(a); i += 4 {
+		var x, y uints.U32
+		for j := 0; j < 4; j++ {
+			if i+j < len(a) {
+				x[j], y[j] = a[i+j], b[i+j]
+			} else {
+				x[j], y[j] = uints.NewU8(0), uints.NewU8(0)
+			}
+		}
+		z := v.bytes.Xor(x, y)
+		copy(res[i:], z[:])
+	}
+	return res
+}
+
+func (v *Verifier) newHash(h crypto.Hash) (hash.BinaryHasher, error) {
+	switch h {
+	case crypto.SHA256:
+		return sha2.New(v.api)
+	case crypto.SHA384:
+		return sha2.New384(v.api)
+	case crypto.SHA512:
+		return sha2.New512(v.api)
+	default:
+		return nil, fmt.Errorf("unsupported hash function %s", h)
+	}
+}
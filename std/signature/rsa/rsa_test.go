@@ -0,0 +1,77 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type verifyCircuit struct {
+	PSS       bool      `gnark:"-"`
+	PublicKey PublicKey `gnark:",public"`
+	Hashed    [32]uints.U8
+	Signature Signature
+}
+
+func (c *verifyCircuit) Define(api frontend.API) error {
+	v, err := NewVerifier(api)
+	if err != nil {
+		return err
+	}
+	if c.PSS {
+		return v.VerifyPSS(&c.PublicKey, crypto.SHA256, c.Hashed[:], &c.Signature, sha256.Size)
+	}
+	return v.VerifyPKCS1v15(&c.PublicKey, crypto.SHA256, c.Hashed[:], &c.Signature)
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, nbBits := range []int{2048, 4096} {
+		if testing.Short() && nbBits > 2048 {
+			continue
+		}
+		sk, err := rsa.GenerateKey(rand.Reader, nbBits)
+		assert.NoError(err)
+		otherSk, err := rsa.GenerateKey(rand.Reader, nbBits)
+		assert.NoError(err)
+		hashed := sha256.Sum256([]byte("gnark"))
+		other := sha256.Sum256([]byte("other"))
+
+		for _, pss := range []bool{false, true} {
+			var sig []byte
+			if pss {
+				sig, err = rsa.SignPSS(rand.Reader, sk, crypto.SHA256, hashed[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+			} else {
+				sig, err = rsa.SignPKCS1v15(rand.Reader, sk, crypto.SHA256, hashed[:])
+			}
+			assert.NoError(err)
+
+			tampered := append([]byte(nil), sig...)
+			tampered[len(tampered)/2] ^= 1
+
+			assignment := func(pk *rsa.PublicKey, digest [32]byte, sig []byte) *verifyCircuit {
+				res := verifyCircuit{PublicKey: ValueOfPublicKey(pk), Signature: ValueOfSignature(sig, nbBits)}
+				copy(res.Hashed[:], uints.NewU8Array(digest[:]))
+				return &res
+			}
+			circuit := verifyCircuit{PSS: pss, PublicKey: PlaceholderPublicKey(nbBits), Signature: PlaceholderSignature(nbBits)}
+			assert.CheckCircuit(&circuit,
+				test.WithCurves(ecc.BN254),
+				test.WithValidAssignment(assignment(&sk.PublicKey, hashed, sig)),
+				// wrong digest
+				test.WithInvalidAssignment(assignment(&sk.PublicKey, other, sig)),
+				// tampered signature
+				test.WithInvalidAssignment(assignment(&sk.PublicKey, hashed, tampered)),
+				// signature of another key
+				test.WithInvalidAssignment(assignment(&otherSk.PublicKey, hashed, sig)),
+			)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// BIP340PublicKeySize is the size in bytes of a BIP-340 public key, the
+	// x-coordinate of the point.
+	BIP340PublicKeySize = 32
+	// BIP340SignatureSize is the size in bytes of a BIP-340 signature.
+	BIP340SignatureSize = 64
+)
+
+// VerifyBIP340 asserts that sig is a valid [BIP-340] signature of msg by the
+// public key pubKey, in their byte encodings. It follows the verification
+// algorithm of the specification:
+//   - the public key P is the point with x-coordinate pubKey and an even
+//     y-coordinate;
+//   - the signature is r ‖ s, with r < p and s < n;
+//   - e = tagged_hash("BIP0340/challenge", r ‖ pubKey ‖ msg) mod n;
+//   - R = [s]G - [e]P must have an even y-coordinate and r as x-coordinate.
+//
+// The points [s]G and [-e]P are added with an incomplete formula, which fails
+// with negligible probability for honest signatures.
+//
+// [BIP-340]: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+func VerifyBIP340(api frontend.API, pubKey *[BIP340PublicKeySize]uints.U8, msg []uints.U8, sig *[BIP340SignatureSize]uints.U8) error {
+	curve, err := sw_emulated.New[emulated.Secp256k1Fp, emulated.Secp256k1Fr](api, sw_emulated.GetSecp256k1Params())
+	if err != nil {
+		return fmt.Errorf("new curve: %w", err)
+	}
+	fp, err := emulated.NewField[emulated.Secp256k1Fp](api)
+	if err != nil {
+		return fmt.Errorf("new base field: %w", err)
+	}
+	fr, err := emulated.NewField[emulated.Secp256k1Fr](api)
+	if err != nil {
+		return fmt.Errorf("new scalar field: %w", err)
+	}
+
+	// P = lift_x(pubKey)
+	px := fromBytes(api, fp, pubKey[:])
+	fp.AssertIsInRange(px)
+	y2 := fp.Add(fp.Mul(fp.Mul(px, px), px), fp.NewElement(7))
+	py := fp.Sqrt(y2)
+	py = fp.Select(isOdd(api, fp, py), fp.Neg(py), py)
+	P := &sw_emulated.AffinePoint[emulated.Secp256k1Fp]{X: *px, Y: *py}
+
+	r := fromBytes(api, fp, sig[:32])
+	fp.AssertIsInRange(r)
+	s := fromBytes(api, fr, sig[32:])
+	fr.AssertIsInRange(s)
+
+	// e = tagged_hash("BIP0340/challenge", r ‖ P ‖ m) mod n
+	h, err := sha2.New(api)
+	if err != nil {
+		return fmt.Errorf("new hash: %w", err)
+	}
+	tag := sha256.Sum256([]byte("BIP0340/challenge"))
+	h.Write(uints.NewU8Array(append(tag[:], tag[:]...)))
+	h.Write(sig[:32])
+	h.Write(pubKey[:])
+	h.Write(msg)
+	e := fromBytes(api, fr, h.Sum())
+
+	// R = [s]G - [e]P
+	R := curve.JointScalarMulBase(P, fr.Neg(e), s)
+	api.AssertIsEqual(isOdd(api, fp, &R.Y), 0)
+	fp.AssertIsEqual(&R.X, r)
+	return nil
+}
+
+// fromBytes returns the element encoded in big-endian by b. The element may be
+// larger than the modulus.
+func fromBytes[T emulated.FieldParams](api frontend.API, f *emulated.Field[T], b []uints.U8) *emulated.Element[T] {
+	bits := make([]frontend.Variable, 0, 8*len(b))
+	for i := len(b) - 1; i >= 0; i-- {
+		bits = append(bits, api.ToBinary(b[i].Val, 8)...)
+	}
+	return f.FromBits(bits...)
+}
+
+// isOdd returns 1 if the canonical representation of a is odd and 0
+// otherwise.
+func isOdd(api frontend.API, f *emulated.Field[emulated.Secp256k1Fp], a *emulated.Element[emulated.Secp256k1Fp]) frontend.Variable {
+	r := f.Reduce(a)
+	f.AssertIsInRange(r)
+	return api.ToBinary(r.Limbs[0], int(emulated.Secp256k1Fp{}.BitsPerLimb()))[0]
+}
@@ -0,0 +1,111 @@
+package schnorr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+type bip340Circuit struct {
+	PublicKey [BIP340PublicKeySize]uints.U8
+	Message   []uints.U8
+	Signature [BIP340SignatureSize]uints.U8
+}
+
+func (c *bip340Circuit) Define(api frontend.API) error {
+	return VerifyBIP340(api, &c.PublicKey, c.Message, &c.Signature)
+}
+
+func taggedHash(tag string, data ...[]byte) []byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:])
+	h.Write(t[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// signBIP340 signs msg with the secret key sk as specified in BIP-340, with
+// auxiliary random data aux.
+func signBIP340(sk *big.Int, msg, aux []byte) (pubKey, sig []byte) {
+	n := fr.Modulus()
+	var P secp256k1.G1Affine
+	P.ScalarMultiplicationBase(sk)
+	d := new(big.Int).Set(sk)
+	if P.Y.BigInt(new(big.Int)).Bit(0) == 1 {
+		d.Sub(n, d)
+	}
+	px := P.X.Bytes()
+
+	var db [32]byte
+	d.FillBytes(db[:])
+	t := taggedHash("BIP0340/aux", aux)
+	for i := range t {
+		t[i] ^= db[i]
+	}
+	k := new(big.Int).SetBytes(taggedHash("BIP0340/nonce", t, px[:], msg))
+	k.Mod(k, n)
+	var R secp256k1.G1Affine
+	R.ScalarMultiplicationBase(k)
+	if R.Y.BigInt(new(big.Int)).Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+	rx := R.X.Bytes()
+	e := new(big.Int).SetBytes(taggedHash("BIP0340/challenge", rx[:], px[:], msg))
+	e.Mod(e, n)
+	s := new(big.Int).Mul(e, d)
+	s.Add(s, k).Mod(s, n)
+
+	sig = append(sig, rx[:]...)
+	var sb [32]byte
+	s.FillBytes(sb[:])
+	sig = append(sig, sb[:]...)
+	return px[:], sig
+}
+
+func bip340Assignment(pubKey, msg, sig []byte) *bip340Circuit {
+	var res bip340Circuit
+	copy(res.PublicKey[:], uints.NewU8Array(pubKey))
+	res.Message = uints.NewU8Array(msg)
+	copy(res.Signature[:], uints.NewU8Array(sig))
+	return &res
+}
+
+func TestBIP340(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// test vector 0 of BIP-340
+	pubKey, _ := hex.DecodeString("F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9")
+	msg := make([]byte, 32)
+	sig, _ := hex.DecodeString("E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0")
+	gotPubKey, gotSig := signBIP340(big.NewInt(3), msg, make([]byte, 32))
+	assert.Equal(pubKey, gotPubKey)
+	assert.Equal(sig, gotSig)
+
+	circuit := bip340Circuit{Message: make([]uints.U8, len(msg))}
+	err := test.IsSolved(&circuit, bip340Assignment(pubKey, msg, sig), ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// another message
+	msg[0] = 1
+	err = test.IsSolved(&circuit, bip340Assignment(pubKey, msg, sig), ecc.BN254.ScalarField())
+	assert.Error(err)
+
+	// a message of another length and another key
+	msg = []byte("hello nostr")
+	sk, _ := new(big.Int).SetString("b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef", 16)
+	pubKey, sig = signBIP340(sk, msg, []byte("aux"))
+	circuit = bip340Circuit{Message: make([]uints.U8, len(msg))}
+	err = test.IsSolved(&circuit, bip340Assignment(pubKey, msg, sig), ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
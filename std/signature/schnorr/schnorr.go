@@ -0,0 +1,58 @@
+// Package schnorr provides ZKP-circuit functions to verify Schnorr signatures.
+//
+// Two variants are implemented:
+//   - [Verify] verifies signatures over the twisted Edwards curve embedded in
+//     the scalar field of the SNARK, with a SNARK-friendly hash function. It
+//     is the cheapest signature to verify in-circuit, a few thousand
+//     constraints, as no arithmetic is emulated;
+//   - [VerifyBIP340] verifies the signatures of [BIP-340] over secp256k1, as
+//     used by Bitcoin Taproot and Nostr, with emulated arithmetic.
+//
+// [BIP-340]: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+package schnorr
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// PublicKey stores a Schnorr public key over the embedded twisted Edwards
+// curve (to be used in gnark circuit).
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Signature stores a Schnorr signature over the embedded twisted Edwards curve
+// (to be used in gnark circuit). The signature is the pair (E, S) of the
+// challenge and of the response, both integers modulo the order of the
+// subgroup of the curve.
+type Signature struct {
+	E, S frontend.Variable
+}
+
+// Verify verifies the Schnorr signature sig of the message msg by the public
+// key pubKey. The signature (e, s) of a secret key x, with public key A = [x]G,
+// is computed with a random nonce k as
+//
+//	R = [k]G, e = H(R.X, R.Y, A.X, A.Y, msg), s = k - x·e mod ℓ
+//
+// where G is the base point of the curve and ℓ the order of its subgroup. The
+// verification recomputes R = [s]G + [e]A and checks the challenge.
+//
+// The public key must be a point of the subgroup, which is not checked.
+func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.FieldHasher) error {
+	base := twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+
+	// R = [s]G + [e]A
+	R := curve.DoubleBaseScalarMul(base, pubKey.A, sig.S, sig.E)
+
+	// e = H(R, A, M)
+	hash.Write(R.X, R.Y, pubKey.A.X, pubKey.A.Y, msg)
+	curve.API().AssertIsEqual(hash.Sum(), sig.E)
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	edwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type schnorrCircuit struct {
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+}
+
+func (circuit *schnorrCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return Verify(curve, circuit.Signature, circuit.Message, circuit.PublicKey, &h)
+}
+
+// sign returns the public key of the secret key x and the signature of msg.
+func sign(t *testing.T, x *big.Int, msg fr.Element) (edwardsbn254.PointAffine, *big.Int, *big.Int) {
+	params := edwardsbn254.GetEdwardsCurve()
+	var A, R edwardsbn254.PointAffine
+	A.ScalarMultiplication(&params.Base, x)
+	k, err := rand.Int(rand.Reader, &params.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	R.ScalarMultiplication(&params.Base, k)
+
+	h := hash.MIMC_BN254.New()
+	for _, v := range []fr.Element{R.X, R.Y, A.X, A.Y, msg} {
+		b := v.Bytes()
+		h.Write(b[:])
+	}
+	e := new(big.Int).SetBytes(h.Sum(nil))
+
+	// s = k - x·e mod ℓ
+	s := new(big.Int).Mul(x, e)
+	s.Sub(k, s).Mod(s, &params.Order)
+	return A, e, s
+}
+
+func TestSchnorr(t *testing.T) {
+	assert := test.NewAssert(t)
+	var msg fr.Element
+	msg.SetUint64(42)
+	A, e, s := sign(t, big.NewInt(123456789), msg)
+
+	witness := schnorrCircuit{
+		PublicKey: PublicKey{A: twistededwards.Point{X: A.X, Y: A.Y}},
+		Signature: Signature{E: e, S: s},
+		Message:   msg,
+	}
+	assert.CheckCircuit(&schnorrCircuit{}, test.WithValidAssignment(&witness), test.WithCurves(ecc.BN254))
+
+	// another message
+	invalid := witness
+	invalid.Message = 43
+	assert.CheckCircuit(&schnorrCircuit{}, test.WithInvalidAssignment(&invalid), test.WithCurves(ecc.BN254))
+}
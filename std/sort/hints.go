@@ -0,0 +1,62 @@
+package sort
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{sortHint}
+}
+
+// hintInputs returns the inputs of [sortHint]: the number of columns, the key
+// column and the rows.
+func hintInputs(rows [][]frontend.Variable, key int) []frontend.Variable {
+	inputs := []frontend.Variable{len(rows[0]), key}
+	for i := range rows {
+		inputs = append(inputs, rows[i]...)
+	}
+	return inputs
+}
+
+// sortHint sorts the rows by their key column, stably. It returns the
+// permutation and then the sorted rows.
+func sortHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 2 || !inputs[0].IsUint64() || !inputs[1].IsUint64() {
+		return fmt.Errorf("missing number of columns or key")
+	}
+	nbCols, key := int(inputs[0].Uint64()), int(inputs[1].Uint64())
+	if nbCols == 0 || key >= nbCols || (len(inputs)-2)%nbCols != 0 {
+		return fmt.Errorf("invalid number of columns")
+	}
+	nbRows := (len(inputs) - 2) / nbCols
+	if len(outputs) != nbRows*(nbCols+1) {
+		return fmt.Errorf("expected %d outputs", nbRows*(nbCols+1))
+	}
+	rows := make([][]*big.Int, nbRows)
+	perm := make([]int, nbRows)
+	for i := range rows {
+		rows[i] = inputs[2+i*nbCols : 2+(i+1)*nbCols]
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool {
+		return rows[perm[i]][key].Cmp(rows[perm[j]][key]) < 0
+	})
+	for i := range perm {
+		outputs[i].SetInt64(int64(perm[i]))
+		for j := 0; j < nbCols; j++ {
+			outputs[nbRows+i*nbCols+j].Set(rows[perm[i]][j])
+		}
+	}
+	return nil
+}
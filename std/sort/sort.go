@@ -0,0 +1,112 @@
+// Package sort implements the verification of sorting in-circuit.
+//
+// The sorted values are given by a hint and the circuit only verifies the
+// result: it asserts that the output is a permutation of the input with
+// [permutation.AssertIsRowPermutation], and that the output is sorted by range
+// checking the differences of the consecutive values. This is used for example to
+// deduplicate values, to compute a median or to match the orders of an order
+// book.
+//
+// The builder must implement [frontend.Committer].
+package sort
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/permutation"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// AssertIsSorted asserts that in is sorted in non-decreasing order. The first
+// value and the differences of the consecutive values must be less than
+// 2^nbBits, and len(in)·2^nbBits must be less than the native modulus.
+func AssertIsSorted(api frontend.API, in []frontend.Variable, nbBits int) {
+	if len(in) == 0 {
+		return
+	}
+	rc := rangecheck.New(api)
+	rc.Check(in[0], nbBits)
+	for i := 1; i < len(in); i++ {
+		rc.Check(api.Sub(in[i], in[i-1]), nbBits)
+	}
+}
+
+// Sort returns the values of in sorted in non-decreasing order, with the same
+// bounds as in [AssertIsSorted].
+func Sort(api frontend.API, in []frontend.Variable, nbBits int) []frontend.Variable {
+	if len(in) == 0 {
+		return nil
+	}
+	rows := make([][]frontend.Variable, len(in))
+	for i := range in {
+		rows[i] = []frontend.Variable{in[i]}
+	}
+	res, err := api.Compiler().NewHint(sortHint, 2*len(in), hintInputs(rows, 0)...)
+	if err != nil {
+		panic(err)
+	}
+	sorted := res[len(in):]
+	sortedRows := make([][]frontend.Variable, len(in))
+	for i := range sorted {
+		sortedRows[i] = []frontend.Variable{sorted[i]}
+	}
+	// the range checks are deferred and may use the commitment too, so they
+	// must be registered first.
+	AssertIsSorted(api, sorted, nbBits)
+	permutation.AssertIsRowPermutation(api, rows, sortedRows)
+	return sorted
+}
+
+// SortBy returns the rows sorted by the values in their column key, and the
+// permutation applied: perm[i] is the index in rows of sorted[i]. The sort is
+// stable, so that the result is unique. The keys must be less than 2^nbBits,
+// and len(rows)²·2^nbBits must be less than the native modulus.
+func SortBy(api frontend.API, rows [][]frontend.Variable, key int, nbBits int) (sorted [][]frontend.Variable, perm []frontend.Variable) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	nbCols := len(rows[0])
+	for i := range rows {
+		if len(rows[i]) != nbCols {
+			panic("row length mismatch")
+		}
+	}
+	if key < 0 || key >= nbCols {
+		panic(fmt.Sprintf("invalid key column %d", key))
+	}
+	res, err := api.Compiler().NewHint(sortHint, len(rows)*(nbCols+1), hintInputs(rows, key)...)
+	if err != nil {
+		panic(err)
+	}
+	perm = res[:len(rows)]
+	sorted = make([][]frontend.Variable, len(rows))
+	for i := range sorted {
+		sorted[i] = res[len(rows)+i*nbCols : len(rows)+(i+1)*nbCols]
+	}
+	// the pairs (key, index) are strictly increasing, which makes the sort
+	// stable. Subtracting i turns the strict order into a non-strict one.
+	nbIndexBits := bitLen(len(rows) - 1)
+	combined := make([]frontend.Variable, len(rows))
+	for i := range sorted {
+		combined[i] = api.Add(api.Mul(sorted[i][key], 1<<nbIndexBits), perm[i], -i)
+	}
+	AssertIsSorted(api, combined, nbBits+nbIndexBits)
+	// the rows with their index are permuted, so the indices are a permutation
+	// of [0, len(rows)).
+	indexed, sortedIndexed := make([][]frontend.Variable, len(rows)), make([][]frontend.Variable, len(rows))
+	for i := range rows {
+		indexed[i] = append([]frontend.Variable{i}, rows[i]...)
+		sortedIndexed[i] = append([]frontend.Variable{perm[i]}, sorted[i]...)
+	}
+	permutation.AssertIsRowPermutation(api, indexed, sortedIndexed)
+	return sorted, perm
+}
+
+func bitLen(n int) int {
+	l := 0
+	for ; n > 0; n >>= 1 {
+		l++
+	}
+	return l
+}
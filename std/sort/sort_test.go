@@ -0,0 +1,94 @@
+package sort
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type sortCircuit struct {
+	In, Expected []frontend.Variable
+}
+
+func (c *sortCircuit) Define(api frontend.API) error {
+	sorted := Sort(api, c.In, 16)
+	for i := range sorted {
+		api.AssertIsEqual(sorted[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestSort(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := sortCircuit{In: make([]frontend.Variable, 6), Expected: make([]frontend.Variable, 6)}
+	assert.CheckCircuit(&circuit,
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&sortCircuit{
+			In:       []frontend.Variable{5, 3, 65535, 3, 0, 9},
+			Expected: []frontend.Variable{0, 3, 3, 5, 9, 65535},
+		}),
+		test.WithInvalidAssignment(&sortCircuit{
+			In:       []frontend.Variable{5, 3, 65535, 3, 0, 9},
+			Expected: []frontend.Variable{0, 3, 5, 5, 9, 65535},
+		}),
+	)
+}
+
+type sortByCircuit struct {
+	Keys, Values []frontend.Variable
+	Perm, Sorted []frontend.Variable
+}
+
+func (c *sortByCircuit) Define(api frontend.API) error {
+	rows := make([][]frontend.Variable, len(c.Keys))
+	for i := range rows {
+		rows[i] = []frontend.Variable{c.Values[i], c.Keys[i]}
+	}
+	sorted, perm := SortBy(api, rows, 1, 8)
+	for i := range sorted {
+		api.AssertIsEqual(perm[i], c.Perm[i])
+		api.AssertIsEqual(sorted[i][0], c.Sorted[i])
+	}
+	return nil
+}
+
+func TestSortBy(t *testing.T) {
+	assert := test.NewAssert(t)
+	keys := []frontend.Variable{7, 2, 7, 1, 2}
+	values := []frontend.Variable{100, 101, 102, 103, 104}
+	perm := []frontend.Variable{3, 1, 4, 0, 2}
+	sorted := []frontend.Variable{103, 101, 104, 100, 102}
+	// a sort which isn't stable
+	unstable := []frontend.Variable{3, 4, 1, 0, 2}
+	unstableSorted := []frontend.Variable{103, 104, 101, 100, 102}
+	circuit := sortByCircuit{
+		Keys: make([]frontend.Variable, 5), Values: make([]frontend.Variable, 5),
+		Perm: make([]frontend.Variable, 5), Sorted: make([]frontend.Variable, 5),
+	}
+	assert.CheckCircuit(&circuit,
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&sortByCircuit{Keys: keys, Values: values, Perm: perm, Sorted: sorted}),
+		test.WithInvalidAssignment(&sortByCircuit{Keys: keys, Values: values, Perm: unstable, Sorted: unstableSorted}),
+	)
+}
+
+type sortedCircuit struct {
+	In []frontend.Variable
+}
+
+func (c *sortedCircuit) Define(api frontend.API) error {
+	AssertIsSorted(api, c.In, 8)
+	return nil
+}
+
+func TestAssertIsSorted(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&sortedCircuit{In: make([]frontend.Variable, 4)},
+		test.WithCurves(ecc.BN254),
+		test.WithValidAssignment(&sortedCircuit{In: []frontend.Variable{0, 0, 200, 455}}),
+		test.WithInvalidAssignment(&sortedCircuit{In: []frontend.Variable{0, 2, 1, 3}}),
+		test.WithInvalidAssignment(&sortedCircuit{In: []frontend.Variable{0, 1, 300, 301}}),
+	)
+}
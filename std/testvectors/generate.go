@@ -0,0 +1,398 @@
+package testvectors
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/std/hash/anemoi"
+	"github.com/consensys/gnark/std/hash/blake3"
+	"github.com/consensys/gnark/std/hash/gmimc"
+	"github.com/consensys/gnark/std/hash/griffin"
+	"github.com/consensys/gnark/std/hash/pedersen"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/std/hash/rescue"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // reference implementation
+	"golang.org/x/crypto/sha3"
+)
+
+// PedersenDomain is the domain separation string of the Pedersen vectors.
+const PedersenDomain = "gnark/testvectors"
+
+// references of the native implementations which are checked against
+// published test vectors in the tests of this package.
+const (
+	referenceBlake3    = "official BLAKE3 test vectors (github.com/BLAKE3-team/BLAKE3/test_vectors)"
+	referenceCircomlib = "circomlib Poseidon (github.com/iden3/circomlib)"
+	referenceHadeshash = "test vectors of the Poseidon reference implementation (extgit.iaik.tugraz.at/krypto/hadeshash)"
+)
+
+// messageLengths are the lengths of the messages of the binary hashes, around
+// the block sizes of the hash functions.
+var messageLengths = []int{0, 3, 55, 56, 64, 65, 128, 136, 200}
+
+// fieldCurves are the curves over the scalar field of which the vectors of the
+// field gadgets are generated.
+var fieldCurves = []struct {
+	name  string
+	id    ecc.ID
+	ed    tedwards.ID
+	mimc  cryptohash.Hash
+	nbMsg []int // number of field elements of the messages
+}{
+	{"bn254", ecc.BN254, tedwards.BN254, cryptohash.MIMC_BN254, []int{1, 2, 5}},
+	{"bls12-381", ecc.BLS12_381, tedwards.BLS12_381, cryptohash.MIMC_BLS12_381, []int{1, 2, 5}},
+}
+
+// Generate returns the test vectors computed with the native implementations,
+// sorted by name. The inputs are derived from fixed seeds, so that the result
+// is deterministic.
+func Generate() ([]Suite, error) {
+	var suites []Suite
+	suites = append(suites, binaryHashSuites()...)
+	for _, c := range fieldCurves {
+		s, err := fieldHashSuites(c.name, c.id, c.ed, c.mimc, c.nbMsg)
+		if err != nil {
+			return nil, err
+		}
+		suites = append(suites, s...)
+	}
+	sort.Slice(suites, func(i, j int) bool { return suites[i].Name < suites[j].Name })
+	return suites, nil
+}
+
+const descriptionBinary = "input: message bytes; key: key bytes of the keyed mode, if any; output: digest bytes"
+
+func binaryHashSuites() []Suite {
+	defs := []struct {
+		name, gadget, reference string
+		keySize                 int
+		new                     func(key []byte) hash.Hash
+	}{
+		{"sha1", "sha1", "crypto/sha1", 0, func([]byte) hash.Hash { return sha1.New() }},
+		{"sha2-256", "sha2", "crypto/sha256", 0, func([]byte) hash.Hash { return sha256.New() }},
+		{"sha2-384", "sha2", "crypto/sha512", 0, func([]byte) hash.Hash { return sha512.New384() }},
+		{"sha2-512", "sha2", "crypto/sha512", 0, func([]byte) hash.Hash { return sha512.New() }},
+		{"sha3-256", "sha3", "golang.org/x/crypto/sha3", 0, func([]byte) hash.Hash { return sha3.New256() }},
+		{"sha3-512", "sha3", "golang.org/x/crypto/sha3", 0, func([]byte) hash.Hash { return sha3.New512() }},
+		{"keccak-256", "sha3", "golang.org/x/crypto/sha3", 0, func([]byte) hash.Hash { return sha3.NewLegacyKeccak256() }},
+		{"blake2b-512", "blake2", "golang.org/x/crypto/blake2b", 0, func(key []byte) hash.Hash { return mustHash(blake2b.New512(key)) }},
+		{"blake2b-256-keyed", "blake2", "golang.org/x/crypto/blake2b", 64, func(key []byte) hash.Hash { return mustHash(blake2b.New256(key)) }},
+		{"blake2s-256", "blake2", "golang.org/x/crypto/blake2s", 0, func(key []byte) hash.Hash { return mustHash(blake2s.New256(key)) }},
+		{"blake2s-256-keyed", "blake2", "golang.org/x/crypto/blake2s", 32, func(key []byte) hash.Hash { return mustHash(blake2s.New256(key)) }},
+		{"ripemd160", "ripemd160", "golang.org/x/crypto/ripemd160", 0, func([]byte) hash.Hash { return ripemd160.New() }},
+		{"blake3-256", "blake3", referenceBlake3, 0, nil},
+		{"blake3-256-keyed", "blake3", referenceBlake3, blake3.KeySize, nil},
+	}
+	suites := make([]Suite, len(defs))
+	for i, d := range defs {
+		r := newStream(d.name)
+		key := r.bytes(d.keySize)
+		lengths := messageLengths
+		if d.gadget == "blake3" {
+			// messages spanning several chunks
+			lengths = append(lengths[:len(lengths):len(lengths)], 1025)
+		}
+		suites[i] = Suite{
+			Name:        d.name,
+			Gadget:      "github.com/consensys/gnark/std/hash/" + d.gadget,
+			Description: descriptionBinary,
+			Reference:   d.reference,
+		}
+		for _, n := range lengths {
+			msg := r.bytes(n)
+			var digest []byte
+			if d.new == nil {
+				digest = blake3.Sum(msg, key, blake3.Size)
+			} else {
+				h := d.new(key)
+				h.Write(msg)
+				digest = h.Sum(nil)
+			}
+			suites[i].Vectors = append(suites[i].Vectors, Vector{
+				Input:  []string{hex.EncodeToString(msg)},
+				Key:    hex.EncodeToString(key),
+				Output: []string{hex.EncodeToString(digest)},
+			})
+		}
+	}
+	return suites
+}
+
+func mustHash(h hash.Hash, err error) hash.Hash {
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+func fieldHashSuites(curveName string, id ecc.ID, edID tedwards.ID, mimcHash cryptohash.Hash, nbMsg []int) ([]Suite, error) {
+	field := id.ScalarField()
+	elementSize := (field.BitLen() + 7) / 8
+	encode := func(e *big.Int) string {
+		b := make([]byte, elementSize)
+		return hex.EncodeToString(e.FillBytes(b))
+	}
+	encodeAll := func(es []*big.Int) []string {
+		res := make([]string, len(es))
+		for i := range es {
+			res[i] = encode(es[i])
+		}
+		return res
+	}
+	const descriptionField = "input: field elements; output: digest as a field element"
+
+	// MiMC
+	mimcSuite := Suite{
+		Name:        "mimc-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/hash/mimc",
+		Description: descriptionField,
+		Reference:   "github.com/consensys/gnark-crypto/ecc/" + curveName + "/fr/mimc",
+	}
+	r := newStream(mimcSuite.Name)
+	for _, n := range nbMsg {
+		msg := r.elements(n, field)
+		h := mimcHash.New()
+		for i := range msg {
+			h.Write(msg[i].FillBytes(make([]byte, elementSize)))
+		}
+		mimcSuite.Vectors = append(mimcSuite.Vectors, Vector{
+			Input:  encodeAll(msg),
+			Output: []string{hex.EncodeToString(h.Sum(nil))},
+		})
+	}
+
+	// Poseidon
+	poseidonSuite := Suite{
+		Name:        "poseidon-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/hash/poseidon",
+		Description: descriptionField + fmt.Sprintf(", with the default parameters of width %d", poseidon.DefaultWidth),
+	}
+	switch id {
+	case ecc.BN254:
+		poseidonSuite.Reference = referenceCircomlib
+	case ecc.BLS12_381:
+		poseidonSuite.Reference = referenceHadeshash
+	}
+	poseidonParams, err := poseidon.GetDefaultParameters(field, poseidon.DefaultWidth)
+	if err != nil {
+		return nil, err
+	}
+	r = newStream(poseidonSuite.Name)
+	for _, n := range nbMsg {
+		msg := r.elements(n, field)
+		poseidonSuite.Vectors = append(poseidonSuite.Vectors, Vector{
+			Input:  encodeAll(msg),
+			Output: []string{encode(poseidonParams.HashElements(msg...))},
+		})
+	}
+
+	// Anemoi
+	anemoiSuite := Suite{
+		Name:        "anemoi-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/hash/anemoi",
+		Description: descriptionField + ", the elements being chained with the Jive compression",
+	}
+	anemoiParams, err := anemoi.GetParameters(field)
+	if err != nil {
+		return nil, err
+	}
+	r = newStream(anemoiSuite.Name)
+	for _, n := range nbMsg {
+		msg := r.elements(n, field)
+		anemoiSuite.Vectors = append(anemoiSuite.Vectors, Vector{
+			Input:  encodeAll(msg),
+			Output: []string{encode(anemoiParams.HashElements(msg...))},
+		})
+	}
+
+	// Rescue-Prime
+	rescueSuite := Suite{
+		Name:        "rescue-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/hash/rescue",
+		Description: fmt.Sprintf("input: field elements; output: digest as %d field elements, with the default parameters of width %d", rescue.DefaultWidth-rescue.DefaultCapacity, rescue.DefaultWidth),
+	}
+	rescueParams, err := rescue.GetDefaultParameters(field)
+	if err != nil {
+		return nil, err
+	}
+	r = newStream(rescueSuite.Name)
+	for _, n := range nbMsg {
+		msg := r.elements(n, field)
+		rescueSuite.Vectors = append(rescueSuite.Vectors, Vector{
+			Input:  encodeAll(msg),
+			Output: encodeAll(rescueParams.HashElements(msg...)),
+		})
+	}
+
+	// Griffin
+	griffinSuite := Suite{
+		Name:        "griffin-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/hash/griffin",
+		Description: descriptionField + fmt.Sprintf(", with the default parameters of width %d", griffin.DefaultWidth),
+	}
+	griffinParams, err := griffin.GetDefaultParameters(field)
+	if err != nil {
+		return nil, err
+	}
+	r = newStream(griffinSuite.Name)
+	for _, n := range nbMsg {
+		msg := r.elements(n, field)
+		griffinSuite.Vectors = append(griffinSuite.Vectors, Vector{
+			Input:  encodeAll(msg),
+			Output: []string{encode(griffinParams.HashElements(msg...))},
+		})
+	}
+
+	// GMiMC
+	var gmimcSuites []Suite
+	for _, mode := range []gmimc.Mode{gmimc.ERF, gmimc.CRF} {
+		gmimcSuite := Suite{
+			Name:        fmt.Sprintf("gmimc-%s-%s", mode, curveName),
+			Gadget:      "github.com/consensys/gnark/std/hash/gmimc",
+			Description: descriptionField + fmt.Sprintf(", with the default parameters of width %d and the %s round function", gmimc.DefaultWidth, mode),
+		}
+		gmimcParams, err := gmimc.GetDefaultParameters(field, mode)
+		if err != nil {
+			return nil, err
+		}
+		r = newStream(gmimcSuite.Name)
+		for _, n := range nbMsg {
+			msg := r.elements(n, field)
+			gmimcSuite.Vectors = append(gmimcSuite.Vectors, Vector{
+				Input:  encodeAll(msg),
+				Output: []string{encode(gmimcParams.HashElements(msg...))},
+			})
+		}
+		gmimcSuites = append(gmimcSuites, gmimcSuite)
+	}
+
+	// Pedersen hash and commitment
+	pedersenParams, err := pedersen.NewParameters(edID, PedersenDomain)
+	if err != nil {
+		return nil, err
+	}
+	pedersenSuite := Suite{
+		Name:        "pedersen-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/hash/pedersen",
+		Description: descriptionField + fmt.Sprintf(" (x coordinate of the hash), with the domain %q", PedersenDomain),
+	}
+	commitSuite := Suite{
+		Name:        "pedersen-commitment-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/hash/pedersen",
+		Description: fmt.Sprintf("input: field elements; key: randomness; output: coordinates x, y of the commitment, with the domain %q", PedersenDomain),
+	}
+	r = newStream(pedersenSuite.Name)
+	for _, n := range nbMsg {
+		msg := r.elements(n, field)
+		randomness := r.elements(1, pedersenParams.Curve.Order)[0]
+		pedersenSuite.Vectors = append(pedersenSuite.Vectors, Vector{
+			Input:  encodeAll(msg),
+			Output: []string{encode(pedersenParams.HashElements(msg...))},
+		})
+		x, y := pedersenParams.Commit(elementsToBits(msg, field.BitLen()), randomness)
+		commitSuite.Vectors = append(commitSuite.Vectors, Vector{
+			Input:  encodeAll(msg),
+			Key:    encode(randomness),
+			Output: []string{encode(x), encode(y)},
+		})
+	}
+
+	// EdDSA with MiMC
+	eddsaSuite := Suite{
+		Name:        "eddsa-" + curveName,
+		Gadget:      "github.com/consensys/gnark/std/signature/eddsa",
+		Description: "input: message as a field element; key: compressed public key; output: signature (compressed R, S) of the message hashed with MiMC",
+		Reference:   "github.com/consensys/gnark-crypto/signature/eddsa",
+	}
+	r = newStream(eddsaSuite.Name)
+	privKey, err := eddsa.New(edID, r)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < 3; i++ {
+		msg := r.elements(1, field)[0]
+		msgBytes := msg.FillBytes(make([]byte, elementSize))
+		sig, err := privKey.Sign(msgBytes, mimcHash.New())
+		if err != nil {
+			return nil, err
+		}
+		eddsaSuite.Vectors = append(eddsaSuite.Vectors, Vector{
+			Input:  []string{hex.EncodeToString(msgBytes)},
+			Key:    hex.EncodeToString(privKey.Public().Bytes()),
+			Output: []string{hex.EncodeToString(sig)},
+		})
+	}
+
+	return []Suite{mimcSuite, poseidonSuite, anemoiSuite, rescueSuite, griffinSuite, gmimcSuites[0], gmimcSuites[1], pedersenSuite, commitSuite, eddsaSuite}, nil
+}
+
+// elementsToBits decomposes the elements in nbBits bits each, least
+// significant bit first, as the Write method of the Pedersen gadget.
+func elementsToBits(elements []*big.Int, nbBits int) []bool {
+	bits := make([]bool, 0, len(elements)*nbBits)
+	for _, e := range elements {
+		for i := 0; i < nbBits; i++ {
+			bits = append(bits, e.Bit(i) == 1)
+		}
+	}
+	return bits
+}
+
+// stream is a deterministic source of bytes: SHA-256(seed || counter) for
+// increasing counters.
+type stream struct {
+	seed    string
+	counter uint64
+	buf     []byte
+}
+
+func newStream(seed string) *stream {
+	return &stream{seed: "gnark/testvectors/" + seed}
+}
+
+// Read implements io.Reader. It never fails.
+func (s *stream) Read(p []byte) (int, error) {
+	for len(s.buf) < len(p) {
+		h := sha256.New()
+		h.Write([]byte(s.seed))
+		var c [8]byte
+		binary.BigEndian.PutUint64(c[:], s.counter)
+		h.Write(c[:])
+		s.buf = h.Sum(s.buf)
+		s.counter++
+	}
+	copy(p, s.buf)
+	s.buf = s.buf[len(p):]
+	return len(p), nil
+}
+
+func (s *stream) bytes(n int) []byte {
+	b := make([]byte, n)
+	_, _ = s.Read(b)
+	return b
+}
+
+// elements returns n elements reduced modulo the modulus, each derived from
+// twice as many bytes as the modulus to make the bias negligible.
+func (s *stream) elements(n int, modulus *big.Int) []*big.Int {
+	res := make([]*big.Int, n)
+	size := 2 * ((modulus.BitLen() + 7) / 8)
+	for i := range res {
+		res[i] = new(big.Int).SetBytes(s.bytes(size))
+		res[i].Mod(res[i], modulus)
+	}
+	return res
+}
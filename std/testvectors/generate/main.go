@@ -0,0 +1,18 @@
+// Command generate exports the test vectors of the std gadgets in JSON.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/consensys/gnark/std/testvectors"
+)
+
+var fOutput = flag.String("o", "vectors", "output directory")
+
+func main() {
+	flag.Parse()
+	if err := testvectors.Export(*fOutput); err != nil {
+		log.Fatal(err)
+	}
+}
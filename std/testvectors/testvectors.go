@@ -0,0 +1,115 @@
+// Package testvectors provides test vectors for the gadgets of gnark/std which
+// have a native counterpart (hashes, signatures and commitments).
+//
+// The vectors are generated with the native implementations from fixed seeds,
+// checked against the in-circuit gadgets by the tests of this package and
+// embedded as JSON files in the vectors directory. They allow other
+// implementations (in other languages) to check that they are compatible with
+// the gadgets at the byte level.
+//
+// The binary hashes are computed with the Go standard library and
+// golang.org/x/crypto, MiMC and EdDSA with gnark-crypto. The native BLAKE3 is
+// checked against the official BLAKE3 test vectors, and the native Poseidon
+// against circomlib over BN254 and the test vectors of the reference
+// implementation over BLS12-381. The other suites (Pedersen, Anemoi, Griffin,
+// GMiMC and Rescue-Prime) are self-referential:
+// they are computed with the native counterparts of the gadgets in gnark only,
+// see [Suite.Reference].
+//
+// The embedded vectors are returned by [Load]. They are regenerated with
+//
+//	go run ./std/testvectors/generate -o std/testvectors/vectors
+//
+// which writes one file per [Suite] (see [Export]).
+package testvectors
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Suite is a set of test vectors of a gadget.
+type Suite struct {
+	// Name identifies the suite, for example "sha2-256" or "mimc-bn254".
+	Name string `json:"name"`
+	// Gadget is the import path of the gadget.
+	Gadget string `json:"gadget"`
+	// Description describes the encoding of the inputs, the key and the
+	// outputs of the vectors.
+	Description string `json:"description"`
+	// Reference is the implementation, independent of gnark, the outputs are
+	// computed with or checked against. It is empty for the self-referential
+	// suites, computed with the native counterpart of the gadget in gnark only:
+	// they check that the gadget and its native counterpart agree, not that
+	// they follow the specification of the primitive.
+	Reference string `json:"reference,omitempty"`
+	// Vectors are the test vectors.
+	Vectors []Vector `json:"vectors"`
+}
+
+// Vector is a test vector. All the values are hex encoded. Field elements are
+// encoded in big-endian on the byte size of the field.
+type Vector struct {
+	Input  []string `json:"input"`
+	Key    string   `json:"key,omitempty"`
+	Output []string `json:"output"`
+}
+
+//go:embed vectors/*.json
+var vectorsFS embed.FS
+
+// Load returns the embedded test vectors, sorted by name.
+func Load() ([]Suite, error) {
+	entries, err := vectorsFS.ReadDir("vectors")
+	if err != nil {
+		return nil, err
+	}
+	suites := make([]Suite, 0, len(entries))
+	for _, e := range entries {
+		b, err := vectorsFS.ReadFile("vectors/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		var s Suite
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", e.Name(), err)
+		}
+		suites = append(suites, s)
+	}
+	sort.Slice(suites, func(i, j int) bool { return suites[i].Name < suites[j].Name })
+	return suites, nil
+}
+
+// Export generates the test vectors and writes each suite to the file
+// <name>.json in dir.
+func Export(dir string) error {
+	suites, err := Generate()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i := range suites {
+		b, err := suites[i].marshal()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, suites[i].Name+".json"), b, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Suite) marshal() ([]byte, error) {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,328 @@
+package testvectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/anemoi"
+	"github.com/consensys/gnark/std/hash/blake2"
+	"github.com/consensys/gnark/std/hash/blake3"
+	"github.com/consensys/gnark/std/hash/gmimc"
+	"github.com/consensys/gnark/std/hash/griffin"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/pedersen"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/std/hash/rescue"
+	"github.com/consensys/gnark/std/hash/ripemd160"
+	"github.com/consensys/gnark/std/hash/sha1"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/signature/eddsa"
+	"github.com/consensys/gnark/test"
+)
+
+func TestEmbeddedVectors(t *testing.T) {
+	assert := test.NewAssert(t)
+	embedded, err := Load()
+	assert.NoError(err)
+	generated, err := Generate()
+	assert.NoError(err)
+	assert.Equal(len(generated), len(embedded), "run go run ./std/testvectors/generate -o std/testvectors/vectors")
+	for i := range generated {
+		b1, err := generated[i].marshal()
+		assert.NoError(err)
+		b2, err := embedded[i].marshal()
+		assert.NoError(err)
+		assert.True(bytes.Equal(b1, b2), "suite %s differs from the embedded vectors", generated[i].Name)
+	}
+}
+
+// TestKnownAnswers pins the native implementations which are not taken from
+// outside gnark to published test vectors, see [Suite.Reference].
+func TestKnownAnswers(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// BLAKE3, test_vectors.json of the reference implementation: the input is
+	// empty and the key of the keyed mode is "whats the Elvish word for friend".
+	assert.Equal("af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262", hex.EncodeToString(blake3.Sum(nil, nil, blake3.Size)))
+	assert.Equal("92b2b75604ed3c761f9d6f62392c8a9227ad0ea3f09573e783f1498a4ed60d26", hex.EncodeToString(blake3.Sum(nil, []byte("whats the Elvish word for friend"), blake3.Size)))
+
+	// Poseidon over BN254 with width 3, circomlib poseidon([1, 2]).
+	p, err := poseidon.GetDefaultParameters(ecc.BN254.ScalarField(), poseidon.DefaultWidth)
+	assert.NoError(err)
+	assert.Equal("115cc0f5e7d690413df64c6b9662e9cf2a3617f2743245519e19607a4417189a", p.HashElements(big.NewInt(1), big.NewInt(2)).Text(16))
+
+	// Poseidon over BLS12-381 with width 3, first element of the permutation of
+	// (0, 1, 2) of poseidonperm_x5_255_3 in the reference implementation. The
+	// sponge has a zero capacity element, so that it is the hash of (1, 2).
+	p, err = poseidon.GetDefaultParameters(ecc.BLS12_381.ScalarField(), poseidon.DefaultWidth)
+	assert.NoError(err)
+	assert.Equal("28ce19420fc246a05553ad1e8c98f5c9d67166be2c18e9e4cb4b4e317dd2a78a", p.HashElements(big.NewInt(1), big.NewInt(2)).Text(16))
+}
+
+type binaryHashCircuit struct {
+	In       []uints.U8
+	Key      []uints.U8
+	Expected []uints.U8
+
+	suite string
+}
+
+func (c *binaryHashCircuit) Define(api frontend.API) error {
+	h, err := binaryHashes[c.suite](api, c.Key)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != len(c.Expected) {
+		return fmt.Errorf("expected %d bytes, got %d", len(c.Expected), len(res))
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+var binaryHashes = map[string]func(api frontend.API, key []uints.U8) (hash.BinaryHasher, error){
+	"sha1":       func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return sha1.New(api) },
+	"sha2-256":   func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return sha2.New(api) },
+	"sha2-384":   func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return sha2.New384(api) },
+	"sha2-512":   func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return sha2.New512(api) },
+	"sha3-256":   func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return sha3.New256(api) },
+	"sha3-512":   func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return sha3.New512(api) },
+	"keccak-256": func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return sha3.NewLegacyKeccak256(api) },
+	"blake2b-512": func(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+		return blake2.NewBlake2b(api, 64, key)
+	},
+	"blake2b-256-keyed": func(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+		return blake2.NewBlake2b(api, 32, key)
+	},
+	"blake2s-256": func(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+		return blake2.NewBlake2s(api, 32, key)
+	},
+	"blake2s-256-keyed": func(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+		return blake2.NewBlake2s(api, 32, key)
+	},
+	"ripemd160": func(api frontend.API, _ []uints.U8) (hash.BinaryHasher, error) { return ripemd160.New(api) },
+	"blake3-256": func(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+		return blake3.New(api, blake3.Size, key)
+	},
+	"blake3-256-keyed": func(api frontend.API, key []uints.U8) (hash.BinaryHasher, error) {
+		return blake3.New(api, blake3.Size, key)
+	},
+}
+
+type fieldHashCircuit struct {
+	In       []frontend.Variable
+	Expected []frontend.Variable
+
+	gadget string
+	id     tedwards.ID
+}
+
+func (c *fieldHashCircuit) Define(api frontend.API) error {
+	var h hash.FieldHasher
+	switch c.gadget {
+	case "mimc":
+		m, err := mimc.NewMiMC(api)
+		if err != nil {
+			return err
+		}
+		h = &m
+	case "poseidon":
+		p, err := poseidon.NewPoseidon(api)
+		if err != nil {
+			return err
+		}
+		h = &p
+	case "pedersen":
+		p, err := pedersen.New(api, c.id, PedersenDomain)
+		if err != nil {
+			return err
+		}
+		h = p
+	case "anemoi":
+		a, err := anemoi.NewAnemoi(api)
+		if err != nil {
+			return err
+		}
+		h = &a
+	case "gmimc-erf", "gmimc-crf":
+		mode := gmimc.ERF
+		if c.gadget == "gmimc-crf" {
+			mode = gmimc.CRF
+		}
+		g, err := gmimc.NewGMiMC(api, mode)
+		if err != nil {
+			return err
+		}
+		h = &g
+	case "griffin":
+		g, err := griffin.NewGriffin(api)
+		if err != nil {
+			return err
+		}
+		h = &g
+	case "rescue":
+		r, err := rescue.NewRescue(api)
+		if err != nil {
+			return err
+		}
+		r.Write(c.In...)
+		res := r.SumAll()
+		if len(res) != len(c.Expected) {
+			return fmt.Errorf("expected %d elements, got %d", len(c.Expected), len(res))
+		}
+		for i := range res {
+			api.AssertIsEqual(res[i], c.Expected[i])
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown gadget %s", c.gadget)
+	}
+	h.Write(c.In...)
+	api.AssertIsEqual(h.Sum(), c.Expected[0])
+	return nil
+}
+
+type commitmentCircuit struct {
+	In         []frontend.Variable
+	Randomness frontend.Variable
+	Expected   twistededwards.Point
+
+	id tedwards.ID
+}
+
+func (c *commitmentCircuit) Define(api frontend.API) error {
+	h, err := pedersen.New(api, c.id, PedersenDomain)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In...)
+	cm := h.Commit(c.Randomness)
+	api.AssertIsEqual(cm.X, c.Expected.X)
+	api.AssertIsEqual(cm.Y, c.Expected.Y)
+	return nil
+}
+
+type eddsaCircuit struct {
+	PublicKey eddsa.PublicKey
+	Signature eddsa.Signature
+	Message   frontend.Variable
+
+	id tedwards.ID
+}
+
+func (c *eddsaCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, c.id)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return eddsa.Verify(curve, c.Signature, c.Message, c.PublicKey, &h)
+}
+
+func TestInCircuit(t *testing.T) {
+	assert := test.NewAssert(t)
+	suites, err := Load()
+	assert.NoError(err)
+	for _, s := range suites {
+		for i, v := range s.Vectors {
+			if testing.Short() && i != len(s.Vectors)-1 {
+				// the last vector has the longest input
+				continue
+			}
+			s, v := s, v
+			assert.Run(func(assert *test.Assert) {
+				circuit, witness, field, err := assignment(s.Name, v)
+				assert.NoError(err)
+				assert.NoError(test.IsSolved(circuit, witness, field))
+			}, s.Name, fmt.Sprintf("vector=%d", i))
+		}
+	}
+}
+
+// assignment returns the circuit checking the vector of the suite, its
+// witness and the field over which it must be solved.
+func assignment(suite string, v Vector) (circuit, witness frontend.Circuit, field *big.Int, err error) {
+	decode := func(s string) []byte {
+		b, errDecode := hex.DecodeString(s)
+		if errDecode != nil && err == nil {
+			err = errDecode
+		}
+		return b
+	}
+	elements := func(ss []string) []frontend.Variable {
+		res := make([]frontend.Variable, len(ss))
+		for i := range ss {
+			res[i] = new(big.Int).SetBytes(decode(ss[i]))
+		}
+		return res
+	}
+
+	if _, ok := binaryHashes[suite]; ok {
+		in, key, expected := decode(v.Input[0]), decode(v.Key), decode(v.Output[0])
+		circuit = &binaryHashCircuit{
+			In:       make([]uints.U8, len(in)),
+			Key:      make([]uints.U8, len(key)),
+			Expected: make([]uints.U8, len(expected)),
+			suite:    suite,
+		}
+		witness = &binaryHashCircuit{
+			In:       uints.NewU8Array(in),
+			Key:      uints.NewU8Array(key),
+			Expected: uints.NewU8Array(expected),
+		}
+		return circuit, witness, fieldCurves[0].id.ScalarField(), err
+	}
+
+	for _, c := range fieldCurves {
+		if !strings.HasSuffix(suite, "-"+c.name) {
+			continue
+		}
+		field = c.id.ScalarField()
+		gadget := strings.TrimSuffix(suite, "-"+c.name)
+		in := elements(v.Input)
+		switch gadget {
+		case "mimc", "poseidon", "anemoi", "rescue", "griffin", "gmimc-erf", "gmimc-crf", "pedersen":
+			out := elements(v.Output)
+			circuit = &fieldHashCircuit{In: make([]frontend.Variable, len(in)), Expected: make([]frontend.Variable, len(out)), gadget: gadget, id: c.ed}
+			witness = &fieldHashCircuit{In: in, Expected: out}
+		case "pedersen-commitment":
+			out := elements(v.Output)
+			circuit = &commitmentCircuit{In: make([]frontend.Variable, len(in)), id: c.ed}
+			witness = &commitmentCircuit{
+				In:         in,
+				Randomness: elements([]string{v.Key})[0],
+				Expected:   twistededwards.Point{X: out[0], Y: out[1]},
+			}
+		case "eddsa":
+			w := &eddsaCircuit{Message: in[0]}
+			w.PublicKey.Assign(c.ed, decode(v.Key))
+			w.Signature.Assign(c.ed, decode(v.Output[0]))
+			circuit, witness = &eddsaCircuit{id: c.ed}, w
+		default:
+			return nil, nil, nil, fmt.Errorf("unknown suite %s", suite)
+		}
+		return circuit, witness, field, err
+	}
+	return nil, nil, nil, fmt.Errorf("unknown suite %s", suite)
+}
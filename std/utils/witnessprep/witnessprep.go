@@ -0,0 +1,166 @@
+// Package witnessprep provides helpers to compute witness assignments in
+// parallel out of the circuit.
+//
+// Once the prover is optimized, computing the assignment often dominates the
+// end-to-end latency: hashing the leaves and building the Merkle paths of the
+// [merkle.MerkleProof] gadget or decomposing integers into the limbs of
+// emulated elements. The helpers of this package spread this work over all
+// CPUs. The output is deterministic: the i-th output always corresponds to the
+// i-th input, so that it can be assigned directly to the slices of the circuit
+// in the order of its schema, and in case of errors the error of the smallest
+// index is returned.
+package witnessprep
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/math/bigint"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Map returns the results of f applied to all the inputs, in parallel. The
+// result at index i is f(i, in[i]). If f fails for some inputs, Map returns
+// the error of the smallest index.
+func Map[T, U any](in []T, f func(i int, v T) (U, error)) ([]U, error) {
+	res := make([]U, len(in))
+	errs := make([]error, len(in))
+	utils.Parallelize(len(in), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i], errs[i] = f(i, in[i])
+		}
+	})
+	for i := range errs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("input %d: %w", i, errs[i])
+		}
+	}
+	return res, nil
+}
+
+// HashLeaves returns the hashes of the leaves with h, in parallel. Each worker
+// uses its own instance of the hash function.
+func HashLeaves(h hash.Hash, leaves [][]byte) [][]byte {
+	res := make([][]byte, len(leaves))
+	utils.Parallelize(len(leaves), func(start, end int) {
+		hh := h.New()
+		for i := start; i < end; i++ {
+			hh.Reset()
+			hh.Write(leaves[i])
+			res[i] = hh.Sum(nil)
+		}
+	})
+	return res
+}
+
+// MerkleProofs returns the Merkle proofs of the leaves at the indices, in the
+// format of the [merkle.MerkleProof] gadget: the first element of the path is
+// the leaf and the following ones are the siblings from the bottom to the
+// root. The number of leaves must be a power of two, and each leaf must be the
+// encoding of a single element for h, as the tree of
+// [github.com/consensys/gnark-crypto/accumulator/merkletree].
+//
+// The tree is built once, each level being hashed in parallel, and the paths
+// are then extracted in parallel. The i-th proof is the one of indices[i].
+func MerkleProofs(h hash.Hash, leaves [][]byte, indices []uint64) ([]merkle.MerkleProof, error) {
+	levels, err := merkleLevels(h, leaves, indices)
+	if err != nil {
+		return nil, err
+	}
+	root := levels[len(levels)-1][0]
+	return Map(indices, func(_ int, idx uint64) (merkle.MerkleProof, error) {
+		path := make([]frontend.Variable, len(levels))
+		path[0] = leaves[idx]
+		for j := 1; j < len(levels); j++ {
+			path[j] = levels[j-1][idx^1]
+			idx >>= 1
+		}
+		return merkle.MerkleProof{RootHash: root, Path: path}, nil
+	})
+}
+
+// MerkleMultiProof returns the multi-proof of the leaves at the indices, in the
+// format of [merkle.MultiProof], with the same requirements on the leaves as
+// [MerkleProofs]. The paths are in the order of the indices.
+func MerkleMultiProof(h hash.Hash, leaves [][]byte, indices []uint64) (merkle.MultiProof, error) {
+	if len(indices) == 0 {
+		return merkle.MultiProof{}, errors.New("no index to open")
+	}
+	levels, err := merkleLevels(h, leaves, indices)
+	if err != nil {
+		return merkle.MultiProof{}, err
+	}
+	depth := len(levels) - 1
+	res := merkle.PlaceholderMultiProof(depth, len(indices))
+	res.RootHash = levels[depth][0]
+	// the paths stop at the level of the top nodes
+	top := levels[len(res.Paths[0])]
+	for i := range res.Top {
+		res.Top[i] = top[i]
+	}
+	res.Paths, err = Map(indices, func(i int, idx uint64) ([]frontend.Variable, error) {
+		path := res.Paths[i]
+		for j := range path {
+			path[j] = levels[j][idx^1]
+			idx >>= 1
+		}
+		return path, nil
+	})
+	return res, err
+}
+
+// merkleLevels returns the levels of the Merkle tree of the leaves, from the
+// hashes of the leaves to the root, after checking the indices.
+func merkleLevels(h hash.Hash, leaves [][]byte, indices []uint64) ([][][]byte, error) {
+	if len(leaves) == 0 || len(leaves)&(len(leaves)-1) != 0 {
+		return nil, errors.New("number of leaves must be a power of two")
+	}
+	for i, idx := range indices {
+		if idx >= uint64(len(leaves)) {
+			return nil, fmt.Errorf("index %d out of range: %d", i, idx)
+		}
+	}
+
+	// levels[0] are the hashes of the leaves and levels[len-1] is the root
+	levels := [][][]byte{HashLeaves(h, leaves)}
+	for prev := levels[0]; len(prev) > 1; prev = levels[len(levels)-1] {
+		next := make([][]byte, len(prev)/2)
+		utils.Parallelize(len(next), func(start, end int) {
+			hh := h.New()
+			for i := start; i < end; i++ {
+				hh.Reset()
+				hh.Write(prev[2*i])
+				hh.Write(prev[2*i+1])
+				next[i] = hh.Sum(nil)
+			}
+		})
+		levels = append(levels, next)
+	}
+	return levels, nil
+}
+
+// EmulatedElements returns the assignments of the values as emulated elements
+// of the field T, decomposed into limbs in parallel.
+func EmulatedElements[T emulated.FieldParams](values []*big.Int) []emulated.Element[T] {
+	res, _ := Map(values, func(_ int, v *big.Int) (emulated.Element[T], error) {
+		return emulated.ValueOf[T](v), nil
+	})
+	return res
+}
+
+// BigInts returns the assignments of the values as integers of nbBits bits of
+// the [bigint] package, decomposed into limbs in parallel. It returns an error
+// if a value is negative or larger than nbBits bits.
+func BigInts(values []*big.Int, nbBits int) ([]bigint.Int, error) {
+	return Map(values, func(_ int, v *big.Int) (bigint.Int, error) {
+		if v.Sign() < 0 || v.BitLen() > nbBits {
+			return bigint.Int{}, fmt.Errorf("value does not fit in %d bits", nbBits)
+		}
+		return bigint.ValueOf(v, nbBits), nil
+	})
+}
@@ -0,0 +1,109 @@
+package witnessprep
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+func TestMap(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := make([]int, 1000)
+	for i := range in {
+		in[i] = i
+	}
+	res, err := Map(in, func(i, v int) (int, error) { return v * v, nil })
+	assert.NoError(err)
+	for i := range res {
+		assert.Equal(i*i, res[i])
+	}
+
+	errTest := errors.New("test")
+	_, err = Map(in, func(i, v int) (int, error) {
+		if v%100 == 42 {
+			return 0, errTest
+		}
+		return v, nil
+	})
+	assert.ErrorIs(err, errTest)
+	assert.Contains(err.Error(), "input 42")
+}
+
+type merkleCircuit struct {
+	M    merkle.MerkleProof
+	Leaf frontend.Variable
+}
+
+func (c *merkleCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	c.M.VerifyProof(api, &h, c.Leaf)
+	return nil
+}
+
+func TestMerkleProofs(t *testing.T) {
+	assert := test.NewAssert(t)
+	const depth = 5
+	mod := ecc.BN254.ScalarField()
+	leaves := make([][]byte, 1<<depth)
+	var buf bytes.Buffer
+	for i := range leaves {
+		v, err := rand.Int(rand.Reader, mod)
+		assert.NoError(err)
+		leaves[i] = make([]byte, 32)
+		v.FillBytes(leaves[i])
+		buf.Write(leaves[i])
+	}
+	indices := []uint64{0, 7, 31, 7}
+	proofs, err := MerkleProofs(hash.MIMC_BN254, leaves, indices)
+	assert.NoError(err)
+
+	for i, idx := range indices {
+		root, path, _, err := merkletree.BuildReaderProof(bytes.NewReader(buf.Bytes()), hash.MIMC_BN254.New(), 32, idx)
+		assert.NoError(err)
+		assert.Equal(root, proofs[i].RootHash)
+		for j := range path {
+			assert.Equal(path[j], proofs[i].Path[j])
+		}
+
+		circuit := merkleCircuit{M: merkle.MerkleProof{Path: make([]frontend.Variable, depth+1)}}
+		witness := merkleCircuit{M: proofs[i], Leaf: idx}
+		assert.NoError(test.IsSolved(&circuit, &witness, mod))
+	}
+
+	_, err = MerkleProofs(hash.MIMC_BN254, leaves[:3], nil)
+	assert.Error(err)
+	_, err = MerkleProofs(hash.MIMC_BN254, leaves, []uint64{32})
+	assert.Error(err)
+}
+
+func TestLimbs(t *testing.T) {
+	assert := test.NewAssert(t)
+	values := make([]*big.Int, 100)
+	for i := range values {
+		values[i] = new(big.Int).Lsh(big.NewInt(int64(i)), 200)
+	}
+	elements := EmulatedElements[emulated.Secp256k1Fp](values)
+	ints, err := BigInts(values, 256)
+	assert.NoError(err)
+	for i := range values {
+		expected := emulated.ValueOf[emulated.Secp256k1Fp](values[i])
+		assert.Equal(expected.Limbs, elements[i].Limbs)
+		assert.Equal(3, len(ints[i].Limbs))
+	}
+	_, err = BigInts(values, 200)
+	assert.Error(err)
+}
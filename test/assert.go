@@ -135,6 +135,8 @@ func (assert *Assert) compile(circuit frontend.Circuit, curveID ecc.ID, backendI
 		newBuilder = scs.NewBuilder
 	case backend.PLONKFRI:
 		newBuilder = scs.NewBuilder
+	case backend.MARLIN, backend.SPARTAN, backend.BULLETPROOFS:
+		newBuilder = r1cs.NewBuilder
 	default:
 		panic("not implemented")
 	}
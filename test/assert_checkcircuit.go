@@ -3,9 +3,12 @@ package test
 import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/bulletproofs"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/marlin"
 	"github.com/consensys/gnark/backend/plonk"
 	"github.com/consensys/gnark/backend/plonkfri"
+	"github.com/consensys/gnark/backend/spartan"
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
@@ -109,6 +112,12 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 						concreteBackend = _plonk
 					case backend.PLONKFRI:
 						concreteBackend = _plonkfri
+					case backend.MARLIN:
+						concreteBackend = _marlin
+					case backend.SPARTAN:
+						concreteBackend = _spartan
+					case backend.BULLETPROOFS:
+						concreteBackend = _bulletproofs
 					default:
 						panic("backend not implemented")
 					}
@@ -286,4 +295,56 @@ var (
 			return plonkfri.Verify(proof, vk.(plonkfri.VerifyingKey), publicWitness, opts...)
 		},
 	}
+
+	_marlin = tBackend{
+		setup: func(ccs constraint.ConstraintSystem, curve ecc.ID) (
+			pk, vk any,
+			pkBuilder, vkBuilder, proofBuilder func() any,
+			err error) {
+			srs, err := newKZGSRS(curve, uint64(marlin.SRSSize(ccs)))
+			if err != nil {
+				return nil, nil, nil, nil, nil, err
+			}
+			pk, vk, err = marlin.Setup(ccs, srs)
+			return pk, vk, func() any { return marlin.NewProvingKey(curve) }, func() any { return marlin.NewVerifyingKey(curve) }, func() any { return marlin.NewProof(curve) }, err
+		},
+		prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
+			return marlin.Prove(ccs, pk.(marlin.ProvingKey), fullWitness, opts...)
+		},
+		verify: func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+			return marlin.Verify(proof.(marlin.Proof), vk.(marlin.VerifyingKey), publicWitness, opts...)
+		},
+	}
+
+	_spartan = tBackend{
+		setup: func(ccs constraint.ConstraintSystem, curve ecc.ID) (
+			pk, vk any,
+			pkBuilder, vkBuilder, proofBuilder func() any,
+			err error) {
+			pk, vk, err = spartan.Setup(ccs)
+			return pk, vk, func() any { return spartan.NewProvingKey(curve) }, func() any { return spartan.NewVerifyingKey(curve) }, func() any { return spartan.NewProof(curve) }, err
+		},
+		prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
+			return spartan.Prove(ccs, pk.(spartan.ProvingKey), fullWitness, opts...)
+		},
+		verify: func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+			return spartan.Verify(proof.(spartan.Proof), vk.(spartan.VerifyingKey), publicWitness, opts...)
+		},
+	}
+
+	_bulletproofs = tBackend{
+		setup: func(ccs constraint.ConstraintSystem, curve ecc.ID) (
+			pk, vk any,
+			pkBuilder, vkBuilder, proofBuilder func() any,
+			err error) {
+			pk, vk, err = bulletproofs.Setup(ccs)
+			return pk, vk, func() any { return bulletproofs.NewProvingKey(curve) }, func() any { return bulletproofs.NewVerifyingKey(curve) }, func() any { return bulletproofs.NewProof(curve) }, err
+		},
+		prove: func(ccs constraint.ConstraintSystem, pk any, fullWitness witness.Witness, opts ...backend.ProverOption) (proof any, err error) {
+			return bulletproofs.Prove(ccs, pk.(bulletproofs.ProvingKey), fullWitness, opts...)
+		},
+		verify: func(proof, vk any, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
+			return bulletproofs.Verify(proof.(bulletproofs.Proof), vk.(bulletproofs.VerifyingKey), publicWitness, opts...)
+		},
+	}
 )
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+// ErrNotEquivalent is returned by [CheckEquivalence] when the constraint
+// systems disagree on the satisfiability of a sampled assignment.
+var ErrNotEquivalent = errors.New("constraint systems are not equivalent")
+
+// AssignmentGenerator returns a new assignment for the circuits compared in
+// [CheckEquivalence] and [Assert.CheckEquivalence]. The generator is called
+// once per sample and should return a fresh (random) assignment on every call.
+type AssignmentGenerator func() frontend.Circuit
+
+// CheckEquivalence checks that the constraint systems ccsA and ccsB agree on
+// nbSamples assignments returned by generator. For every sampled assignment
+// both constraint systems must either be satisfied or not satisfied.
+//
+// The check is probabilistic: if the constraint systems agree on all samples,
+// it gives confidence (but not a proof) that they define the same relation.
+// It is useful for ensuring that a refactored gadget behaves identically to
+// the previous implementation. The generator should return both valid and
+// invalid assignments to get meaningful results.
+//
+// The constraint systems must be defined over the same field and have the same
+// number of public and secret inputs.
+func CheckEquivalence(ccsA, ccsB constraint.ConstraintSystem, nbSamples int, generator AssignmentGenerator, opts ...solver.Option) error {
+	if ccsA.Field().Cmp(ccsB.Field()) != 0 {
+		return fmt.Errorf("%w: field mismatch", ErrNotEquivalent)
+	}
+	if ccsA.GetNbPublicVariables() != ccsB.GetNbPublicVariables() {
+		return fmt.Errorf("%w: number of public variables mismatch %d != %d", ErrNotEquivalent, ccsA.GetNbPublicVariables(), ccsB.GetNbPublicVariables())
+	}
+	if ccsA.GetNbSecretVariables() != ccsB.GetNbSecretVariables() {
+		return fmt.Errorf("%w: number of secret variables mismatch %d != %d", ErrNotEquivalent, ccsA.GetNbSecretVariables(), ccsB.GetNbSecretVariables())
+	}
+	for i := 0; i < nbSamples; i++ {
+		w, err := frontend.NewWitness(generator(), ccsA.Field())
+		if err != nil {
+			return fmt.Errorf("sample %d: new witness: %w", i, err)
+		}
+		_, errA := ccsA.Solve(w, opts...)
+		_, errB := ccsB.Solve(w, opts...)
+		if (errA == nil) != (errB == nil) {
+			return fmt.Errorf("%w: sample %d: first system: %v, second system: %v", ErrNotEquivalent, i, errA, errB)
+		}
+	}
+	return nil
+}
+
+// CheckEquivalence checks that the circuits circuitA and circuitB agree on
+// nbSamples assignments returned by generator. See [CheckEquivalence] for
+// details.
+//
+// The check is performed using the test engine and the constraint system
+// solver for every curve and backend given by the testing options.
+func (assert *Assert) CheckEquivalence(circuitA, circuitB frontend.Circuit, nbSamples int, generator AssignmentGenerator, opts ...TestingOption) {
+	opt := assert.options(opts...)
+
+	for _, curve := range opt.curves {
+		curve := curve
+		assert.Run(func(assert *Assert) {
+			if !opt.skipTestEngine {
+				err := checkEquivalenceEngine(circuitA, circuitB, curve, nbSamples, generator)
+				assert.NoError(err)
+			}
+			for _, b := range opt.backends {
+				b := b
+				assert.Run(func(assert *Assert) {
+					ccsA, err := assert.compile(circuitA, curve, b, opt.compileOpts)
+					assert.NoError(err)
+					ccsB, err := assert.compile(circuitB, curve, b, opt.compileOpts)
+					assert.NoError(err)
+					err = CheckEquivalence(ccsA, ccsB, nbSamples, generator, opt.solverOpts...)
+					assert.NoError(err)
+				}, b.String())
+			}
+		}, curve.String())
+	}
+}
+
+func checkEquivalenceEngine(circuitA, circuitB frontend.Circuit, curve ecc.ID, nbSamples int, generator AssignmentGenerator) error {
+	for i := 0; i < nbSamples; i++ {
+		assignment := generator()
+		errA := IsSolved(circuitA, assignment, curve.ScalarField())
+		errB := IsSolved(circuitB, assignment, curve.ScalarField())
+		if (errA == nil) != (errB == nil) {
+			return fmt.Errorf("%w: sample %d: first circuit: %v, second circuit: %v", ErrNotEquivalent, i, errA, errB)
+		}
+	}
+	return nil
+}
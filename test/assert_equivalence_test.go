@@ -0,0 +1,76 @@
+package test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+type cubeCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.X), c.Y)
+	return nil
+}
+
+type cubeAltCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubeAltCircuit) Define(api frontend.API) error {
+	x2 := api.Mul(c.X, c.X)
+	api.AssertIsEqual(api.Sub(api.Mul(x2, c.X), c.Y), 0)
+	return nil
+}
+
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func cubeGenerator(seed int64) AssignmentGenerator {
+	rng := rand.New(rand.NewSource(seed)) //#nosec G404 weak rng is fine here
+	return func() frontend.Circuit {
+		x := rng.Int63n(1 << 20)
+		y := x * x * x
+		if rng.Intn(2) == 0 {
+			// invalid assignment
+			y++
+		}
+		return &cubeCircuit{X: x, Y: y}
+	}
+}
+
+func TestCheckEquivalence(t *testing.T) {
+	assert := NewAssert(t)
+	field := ecc.BN254.ScalarField()
+
+	ccsA, err := frontend.Compile(field, r1cs.NewBuilder, &cubeCircuit{})
+	assert.NoError(err)
+	ccsB, err := frontend.Compile(field, r1cs.NewBuilder, &cubeAltCircuit{})
+	assert.NoError(err)
+	ccsC, err := frontend.Compile(field, r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+
+	assert.NoError(CheckEquivalence(ccsA, ccsB, 16, cubeGenerator(1)))
+	err = CheckEquivalence(ccsA, ccsC, 16, cubeGenerator(1))
+	assert.True(errors.Is(err, ErrNotEquivalent))
+}
+
+func TestAssertCheckEquivalence(t *testing.T) {
+	assert := NewAssert(t)
+	assert.CheckEquivalence(&cubeCircuit{}, &cubeAltCircuit{}, 8, cubeGenerator(2))
+}
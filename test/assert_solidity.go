@@ -1,7 +1,6 @@
 package test
 
 import (
-	"bytes"
 	"encoding/hex"
 	"io"
 	"os"
@@ -59,14 +58,8 @@ func (assert *Assert) solidityVerification(b backend.ID, vk verifyingKey,
 
 	if b == backend.GROTH16 {
 		optBackend = "--groth16"
-		var buf bytes.Buffer
 		_proof := proof.(*groth16_bn254.Proof)
-		_, err = _proof.WriteRawTo(&buf)
-		assert.NoError(err)
-		proofBytes := buf.Bytes()
-		// keep only fpSize * 8 bytes; for now solidity contract doesn't handle the commitment part.
-		proofBytes = proofBytes[:32*8]
-		proofStr = hex.EncodeToString(proofBytes)
+		proofStr = hex.EncodeToString(_proof.MarshalSolidity())
 	} else if b == backend.PLONK {
 		optBackend = "--plonk"
 		_proof := proof.(*plonk_bn254.Proof)
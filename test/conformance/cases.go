@@ -0,0 +1,425 @@
+package conformance
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []solver.Hint {
+	return []solver.Hint{cubeAndIncrementHint}
+}
+
+// Cases returns the conformance test cases over the given field.
+func Cases(field *big.Int) []Case {
+	one := big.NewInt(1)
+	pm1 := new(big.Int).Sub(field, one)
+	// an arbitrary element of the full size of the field
+	r := new(big.Int).Div(field, big.NewInt(3))
+	r.Add(r, big.NewInt(12345))
+	mod := func(x *big.Int) *big.Int { return x.Mod(x, field) }
+	add := func(a, b *big.Int) *big.Int { return mod(new(big.Int).Add(a, b)) }
+	sub := func(a, b *big.Int) *big.Int { return mod(new(big.Int).Sub(a, b)) }
+	mul := func(a, b *big.Int) *big.Int { return mod(new(big.Int).Mul(a, b)) }
+	inv := func(a *big.Int) *big.Int { return new(big.Int).ModInverse(a, field) }
+	zero := new(big.Int)
+	two := big.NewInt(2)
+
+	var cases []Case
+
+	// arithmetic
+	arith := func(x, y *big.Int) *arithCircuit {
+		return &arithCircuit{
+			X: x, Y: y,
+			Sum:  add(x, y),
+			Diff: sub(x, y),
+			Prod: mul(x, y),
+			Neg:  sub(zero, x),
+			Acc:  add(x, mul(x, y)),
+		}
+	}
+	wrongArith := arith(r, pm1)
+	wrongArith.Prod = add(mul(r, pm1), one)
+	cases = append(cases, Case{
+		Name:    "arithmetic",
+		Circuit: &arithCircuit{},
+		Valid:   []frontend.Circuit{arith(zero, zero), arith(one, zero), arith(pm1, one), arith(pm1, pm1), arith(r, pm1), arith(r, r)},
+		Invalid: []frontend.Circuit{wrongArith},
+	})
+
+	// division
+	div := func(x, y *big.Int) *divCircuit {
+		return &divCircuit{X: x, Y: y, Quo: mul(x, inv(y)), Inv: inv(y)}
+	}
+	wrongDiv := div(r, two)
+	wrongDiv.Quo = r
+	cases = append(cases, Case{
+		Name:    "division",
+		Circuit: &divCircuit{},
+		Valid:   []frontend.Circuit{div(zero, one), div(one, pm1), div(pm1, pm1), div(r, two), div(pm1, r)},
+		Invalid: []frontend.Circuit{
+			wrongDiv,
+			// division by zero
+			&divCircuit{X: one, Y: zero, Quo: zero, Inv: zero},
+			&divCircuit{X: zero, Y: zero, Quo: zero, Inv: zero},
+		},
+	})
+	cases = append(cases, Case{
+		Name:    "division_unchecked",
+		Circuit: &divUncheckedCircuit{},
+		Valid: []frontend.Circuit{
+			&divUncheckedCircuit{X: pm1, Y: two, Quo: mul(pm1, inv(two))},
+			// the honest solver returns 0 for 0/0
+			&divUncheckedCircuit{X: zero, Y: zero, Quo: zero},
+		},
+		Invalid: []frontend.Circuit{&divUncheckedCircuit{X: one, Y: zero, Quo: zero}},
+	})
+
+	// binary decomposition
+	binary := func(x *big.Int, small uint64) *binaryCircuit {
+		c := &binaryCircuit{X: x, Small: small}
+		for i := range c.SmallBits {
+			c.SmallBits[i] = (small >> i) & 1
+		}
+		return c
+	}
+	wrongBits := binary(r, 0xa5)
+	wrongBits.SmallBits[0] = 0
+	cases = append(cases, Case{
+		Name:    "binary",
+		Circuit: &binaryCircuit{},
+		Valid:   []frontend.Circuit{binary(zero, 0), binary(one, 1), binary(pm1, 0xff), binary(r, 0xa5)},
+		Invalid: []frontend.Circuit{
+			wrongBits,
+			// out of range
+			&binaryCircuit{X: zero, Small: 256, SmallBits: [8]frontend.Variable{0, 0, 0, 0, 0, 0, 0, 0}},
+			&binaryCircuit{X: zero, Small: pm1, SmallBits: [8]frontend.Variable{0, 1, 1, 1, 1, 1, 1, 1}},
+		},
+	})
+
+	// boolean operations and conditionals
+	var valid []frontend.Circuit
+	for a := 0; a < 2; a++ {
+		for b := 0; b < 2; b++ {
+			c := &booleanCircuit{A: a, B: b, Xor: a ^ b, Or: a | b, And: a & b, X: r, Y: pm1}
+			c.Select = pm1
+			if a == 1 {
+				c.Select = r
+			}
+			c.Lookup = []*big.Int{zero, one, r, pm1}[a+2*b]
+			valid = append(valid, c)
+		}
+	}
+	cases = append(cases, Case{
+		Name:    "boolean",
+		Circuit: &booleanCircuit{},
+		Valid:   valid,
+		Invalid: []frontend.Circuit{
+			&booleanCircuit{A: 1, B: 1, Xor: 1, Or: 1, And: 1, X: r, Y: pm1, Select: r, Lookup: pm1},
+			// non boolean inputs
+			&booleanCircuit{A: 2, B: 0, Xor: 0, Or: 0, And: 0, X: r, Y: pm1, Select: pm1, Lookup: zero},
+			&booleanCircuit{A: pm1, B: 0, Xor: 1, Or: 1, And: 0, X: r, Y: pm1, Select: pm1, Lookup: zero},
+		},
+	})
+
+	// zero test and comparisons
+	cmp := func(x, y *big.Int) *cmpCircuit {
+		c := &cmpCircuit{X: x, Y: y, IsZero: 0, Cmp: x.Cmp(y)}
+		if x.Sign() == 0 {
+			c.IsZero = 1
+		}
+		if c.Cmp == -1 {
+			c.Cmp = pm1
+		}
+		return c
+	}
+	wrongCmp := cmp(one, pm1)
+	wrongCmp.Cmp = 1
+	cases = append(cases, Case{
+		Name:    "comparison",
+		Circuit: &cmpCircuit{},
+		Valid:   []frontend.Circuit{cmp(zero, zero), cmp(zero, pm1), cmp(pm1, zero), cmp(one, pm1), cmp(pm1, pm1), cmp(r, one)},
+		Invalid: []frontend.Circuit{wrongCmp, &cmpCircuit{X: zero, Y: zero, IsZero: 0, Cmp: 0}},
+	})
+
+	// assertions
+	cases = append(cases, Case{
+		Name:    "assertions",
+		Circuit: &assertCircuit{},
+		Valid: []frontend.Circuit{
+			&assertCircuit{X: zero, Y: one, B: 0, Bound: pm1},
+			&assertCircuit{X: pm1, Y: zero, B: 1, Bound: pm1},
+			&assertCircuit{X: one, Y: pm1, B: 0, Bound: one},
+		},
+		Invalid: []frontend.Circuit{
+			&assertCircuit{X: r, Y: r, B: 0, Bound: pm1},
+			&assertCircuit{X: zero, Y: one, B: 2, Bound: pm1},
+			&assertCircuit{X: zero, Y: one, B: pm1, Bound: pm1},
+			&assertCircuit{X: two, Y: one, B: 0, Bound: one},
+			&assertCircuit{X: pm1, Y: one, B: 0, Bound: zero},
+		},
+	})
+
+	// hints
+	hint := func(x *big.Int) *hintCircuit {
+		return &hintCircuit{X: x, Cube: mul(x, mul(x, x)), Next: add(x, one)}
+	}
+	wrongHint := hint(r)
+	wrongHint.Next = r
+	cases = append(cases, Case{
+		Name:    "hint",
+		Circuit: &hintCircuit{},
+		Valid:   []frontend.Circuit{hint(zero), hint(pm1), hint(r)},
+		Invalid: []frontend.Circuit{wrongHint},
+	})
+
+	// commitment
+	commit := func(a, b [4]*big.Int) *commitCircuit {
+		c := &commitCircuit{}
+		for i := range a {
+			c.A[i], c.B[i] = a[i], b[i]
+		}
+		return c
+	}
+	cases = append(cases, Case{
+		Name:    "commitment",
+		Circuit: &commitCircuit{},
+		Valid: []frontend.Circuit{
+			commit([4]*big.Int{zero, one, pm1, r}, [4]*big.Int{r, pm1, zero, one}),
+			commit([4]*big.Int{r, r, one, one}, [4]*big.Int{one, r, one, r}),
+		},
+		Invalid: []frontend.Circuit{
+			commit([4]*big.Int{zero, one, pm1, r}, [4]*big.Int{r, pm1, one, one}),
+			commit([4]*big.Int{r, r, one, one}, [4]*big.Int{one, r, r, r}),
+		},
+	})
+
+	return cases
+}
+
+// arithCircuit checks the arithmetic operations, on variables and constants.
+type arithCircuit struct {
+	X, Y                      frontend.Variable
+	Sum, Diff, Prod, Neg, Acc frontend.Variable `gnark:",public"`
+}
+
+func (c *arithCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Add(c.X, c.Y), c.Sum)
+	api.AssertIsEqual(api.Sub(c.X, c.Y), c.Diff)
+	api.AssertIsEqual(api.Mul(c.X, c.Y), c.Prod)
+	api.AssertIsEqual(api.Neg(c.X), c.Neg)
+	api.AssertIsEqual(api.MulAcc(api.Mul(c.X, 1), c.X, c.Y), c.Acc)
+
+	// variadic forms
+	api.AssertIsEqual(api.Add(c.X, c.Y, c.X, 0), api.Add(api.Mul(c.X, 2), c.Y))
+	api.AssertIsEqual(api.Sub(c.X, c.Y, c.X), api.Neg(c.Y))
+	api.AssertIsEqual(api.Mul(c.X, c.Y, 1, -1), api.Neg(c.Prod))
+
+	// constants
+	field := api.Compiler().Field()
+	pm1 := new(big.Int).Sub(field, big.NewInt(1))
+	api.AssertIsEqual(api.Add(pm1, 1), 0)
+	api.AssertIsEqual(api.Sub(0, 1), pm1)
+	api.AssertIsEqual(api.Mul(pm1, pm1), 1)
+	api.AssertIsEqual(api.Neg(1), pm1)
+	api.AssertIsEqual(api.Add(c.X, pm1), api.Sub(c.X, 1))
+	api.AssertIsEqual(api.Mul(c.X, pm1), api.Neg(c.X))
+	api.AssertIsEqual(api.Mul(c.X, 0), 0)
+	return nil
+}
+
+// divCircuit checks the checked divisions.
+type divCircuit struct {
+	X, Y     frontend.Variable
+	Quo, Inv frontend.Variable `gnark:",public"`
+}
+
+func (c *divCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Div(c.X, c.Y), c.Quo)
+	api.AssertIsEqual(api.Inverse(c.Y), c.Inv)
+
+	// constants
+	field := api.Compiler().Field()
+	pm1 := new(big.Int).Sub(field, big.NewInt(1))
+	api.AssertIsEqual(api.Inverse(pm1), pm1)
+	api.AssertIsEqual(api.Div(1, pm1), pm1)
+	api.AssertIsEqual(api.Div(c.X, 1), c.X)
+	api.AssertIsEqual(api.Div(c.X, pm1), api.Neg(c.X))
+	return nil
+}
+
+// divUncheckedCircuit checks the unchecked division.
+type divUncheckedCircuit struct {
+	X, Y frontend.Variable
+	Quo  frontend.Variable `gnark:",public"`
+}
+
+func (c *divUncheckedCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.DivUnchecked(c.X, c.Y), c.Quo)
+	return nil
+}
+
+// binaryCircuit checks the binary decomposition.
+type binaryCircuit struct {
+	X         frontend.Variable
+	Small     frontend.Variable
+	SmallBits [8]frontend.Variable `gnark:",public"`
+}
+
+func (c *binaryCircuit) Define(api frontend.API) error {
+	bits := api.ToBinary(c.Small, len(c.SmallBits))
+	if len(bits) != len(c.SmallBits) {
+		return fmt.Errorf("expected %d bits, got %d", len(c.SmallBits), len(bits))
+	}
+	for i := range bits {
+		api.AssertIsEqual(bits[i], c.SmallBits[i])
+	}
+	api.AssertIsEqual(api.FromBinary(bits...), c.Small)
+
+	// full decomposition
+	bits = api.ToBinary(c.X)
+	if len(bits) != api.Compiler().FieldBitLen() {
+		return fmt.Errorf("expected %d bits, got %d", api.Compiler().FieldBitLen(), len(bits))
+	}
+	api.AssertIsEqual(api.FromBinary(bits...), c.X)
+
+	// constants
+	field := api.Compiler().Field()
+	pm1 := new(big.Int).Sub(field, big.NewInt(1))
+	api.AssertIsEqual(api.FromBinary(api.ToBinary(pm1)...), pm1)
+	api.AssertIsEqual(api.FromBinary(1, 0, 1), 5)
+	return nil
+}
+
+// booleanCircuit checks the boolean operations and the conditionals.
+type booleanCircuit struct {
+	A, B           frontend.Variable
+	X, Y           frontend.Variable
+	Xor, Or, And   frontend.Variable `gnark:",public"`
+	Select, Lookup frontend.Variable `gnark:",public"`
+}
+
+func (c *booleanCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Xor(c.A, c.B), c.Xor)
+	api.AssertIsEqual(api.Or(c.A, c.B), c.Or)
+	api.AssertIsEqual(api.And(c.A, c.B), c.And)
+	api.AssertIsEqual(api.Select(c.A, c.X, c.Y), c.Select)
+	field := api.Compiler().Field()
+	pm1 := new(big.Int).Sub(field, big.NewInt(1))
+	api.AssertIsEqual(api.Lookup2(c.A, c.B, 0, 1, c.X, pm1), c.Lookup)
+
+	// constant selectors
+	api.AssertIsEqual(api.Select(1, c.X, c.Y), c.X)
+	api.AssertIsEqual(api.Select(0, c.X, c.Y), c.Y)
+	api.AssertIsEqual(api.Xor(1, 1), 0)
+	api.AssertIsEqual(api.Or(0, 1), 1)
+	api.AssertIsEqual(api.And(1, 0), 0)
+	return nil
+}
+
+// cmpCircuit checks the zero test and the comparison.
+type cmpCircuit struct {
+	X, Y        frontend.Variable
+	IsZero, Cmp frontend.Variable `gnark:",public"`
+}
+
+func (c *cmpCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.IsZero(c.X), c.IsZero)
+	api.AssertIsEqual(api.Cmp(c.X, c.Y), c.Cmp)
+
+	// constants
+	field := api.Compiler().Field()
+	pm1 := new(big.Int).Sub(field, big.NewInt(1))
+	api.AssertIsEqual(api.IsZero(0), 1)
+	api.AssertIsEqual(api.IsZero(pm1), 0)
+	api.AssertIsEqual(api.Cmp(0, pm1), -1)
+	api.AssertIsEqual(api.Cmp(pm1, 1), 1)
+	return nil
+}
+
+// assertCircuit checks the assertions.
+type assertCircuit struct {
+	X, Y, B frontend.Variable
+	Bound   frontend.Variable `gnark:",public"`
+}
+
+func (c *assertCircuit) Define(api frontend.API) error {
+	api.AssertIsDifferent(c.X, c.Y)
+	api.AssertIsBoolean(c.B)
+	api.AssertIsLessOrEqual(c.X, c.Bound)
+
+	// constants
+	field := api.Compiler().Field()
+	pm1 := new(big.Int).Sub(field, big.NewInt(1))
+	api.AssertIsBoolean(0)
+	api.AssertIsBoolean(1)
+	api.AssertIsDifferent(0, pm1)
+	api.AssertIsLessOrEqual(c.X, pm1)
+	api.AssertIsLessOrEqual(0, c.Bound)
+	return nil
+}
+
+// hintCircuit checks that the hints are called with the values of their
+// inputs, including constants.
+type hintCircuit struct {
+	X          frontend.Variable
+	Cube, Next frontend.Variable `gnark:",public"`
+}
+
+func (c *hintCircuit) Define(api frontend.API) error {
+	res, err := api.Compiler().NewHint(cubeAndIncrementHint, 2, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], c.Cube)
+	api.AssertIsEqual(res[1], c.Next)
+
+	res, err = api.Compiler().NewHint(cubeAndIncrementHint, 2, 2)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res[0], 8)
+	api.AssertIsEqual(res[1], 3)
+	return nil
+}
+
+func cubeAndIncrementHint(field *big.Int, inputs, outputs []*big.Int) error {
+	if len(inputs) != 1 || len(outputs) != 2 {
+		return fmt.Errorf("expected 1 input and 2 outputs, got %d and %d", len(inputs), len(outputs))
+	}
+	outputs[0].Exp(inputs[0], big.NewInt(3), field)
+	outputs[1].Add(inputs[0], big.NewInt(1)).Mod(outputs[1], field)
+	return nil
+}
+
+// commitCircuit checks that a commitment can be used as a random challenge:
+// it checks that B is a permutation of A with the products of (r - A[i]) and
+// (r - B[i]) for the challenge r.
+type commitCircuit struct {
+	A, B [4]frontend.Variable
+}
+
+func (c *commitCircuit) Define(api frontend.API) error {
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		return fmt.Errorf("commitment: %w", ErrUnsupported)
+	}
+	r, err := committer.Commit(append(c.A[:], c.B[:]...)...)
+	if err != nil {
+		return err
+	}
+	pa, pb := frontend.Variable(1), frontend.Variable(1)
+	for i := range c.A {
+		pa = api.Mul(pa, api.Sub(r, c.A[i]))
+		pb = api.Mul(pb, api.Sub(r, c.B[i]))
+	}
+	api.AssertIsEqual(pa, pb)
+	return nil
+}
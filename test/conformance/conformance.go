@@ -0,0 +1,97 @@
+// Package conformance provides a conformance suite for the implementations of
+// [frontend.API]: the test engine, the R1CS and PLONK builders, and third-party
+// builders.
+//
+// The suite is a list of [Case]s, each one a circuit exercising a part of the
+// API with assignments which must be accepted and assignments which must be
+// rejected. The values include the edge cases 0, 1 and p-1 of the field. The
+// cases cover the arithmetic, the binary decomposition, the boolean and
+// conditional operations, the comparisons, the assertions, the hints and the
+// commitments. An implementation is checked with [Run], given a [Checker]
+// which decides if an assignment satisfies a circuit:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, ecc.BN254.ScalarField(), conformance.BuilderChecker(mybuilder.NewBuilder))
+//	}
+//
+// Optional features (for example [frontend.Committer]) are tested only when the
+// implementation provides them, otherwise the case is skipped.
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// ErrUnsupported is returned by the Define method of a case when the
+// implementation under test does not provide an optional feature.
+var ErrUnsupported = errors.New("unsupported by the implementation")
+
+// Case is a conformance test case.
+type Case struct {
+	// Name identifies the case.
+	Name string
+	// Circuit is the circuit definition.
+	Circuit frontend.Circuit
+	// Valid are assignments which must satisfy the circuit.
+	Valid []frontend.Circuit
+	// Invalid are assignments which must not satisfy the circuit.
+	Invalid []frontend.Circuit
+}
+
+// Checker returns nil if the assignment satisfies the circuit over the given
+// field and an error otherwise.
+type Checker func(circuit, assignment frontend.Circuit, field *big.Int) error
+
+// EngineChecker returns a [Checker] running the circuit in the test engine.
+func EngineChecker(opts ...test.TestEngineOption) Checker {
+	return func(circuit, assignment frontend.Circuit, field *big.Int) error {
+		return test.IsSolved(circuit, assignment, field, opts...)
+	}
+}
+
+// BuilderChecker returns a [Checker] compiling the circuit with newBuilder and
+// solving the constraint system.
+func BuilderChecker(newBuilder frontend.NewBuilder, opts ...frontend.CompileOption) Checker {
+	return func(circuit, assignment frontend.Circuit, field *big.Int) error {
+		ccs, err := frontend.Compile(field, newBuilder, circuit, opts...)
+		if err != nil {
+			return fmt.Errorf("compile: %w", err)
+		}
+		w, err := frontend.NewWitness(assignment, field)
+		if err != nil {
+			return fmt.Errorf("new witness: %w", err)
+		}
+		_, err = ccs.Solve(w)
+		return err
+	}
+}
+
+// Run runs the conformance suite over the given field, one subtest per case.
+func Run(t *testing.T, field *big.Int, check Checker) {
+	for _, c := range Cases(field) {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			for i, a := range c.Valid {
+				if err := check(c.Circuit, a, field); err != nil {
+					if errors.Is(err, ErrUnsupported) {
+						t.Skip(err)
+					}
+					t.Errorf("valid assignment %d rejected: %v", i, err)
+				}
+			}
+			for i, a := range c.Invalid {
+				if err := check(c.Circuit, a, field); err == nil {
+					t.Errorf("invalid assignment %d accepted", i)
+				} else if errors.Is(err, ErrUnsupported) {
+					t.Skip(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+func TestTestEngine(t *testing.T) {
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BW6_761} {
+		t.Run(curve.String(), func(t *testing.T) {
+			Run(t, curve.ScalarField(), EngineChecker())
+		})
+	}
+}
+
+func TestR1CSBuilder(t *testing.T) {
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BW6_761} {
+		t.Run(curve.String(), func(t *testing.T) {
+			Run(t, curve.ScalarField(), BuilderChecker(r1cs.NewBuilder))
+		})
+	}
+}
+
+func TestPLONKBuilder(t *testing.T) {
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BW6_761} {
+		t.Run(curve.String(), func(t *testing.T) {
+			Run(t, curve.ScalarField(), BuilderChecker(scs.NewBuilder))
+		})
+	}
+}
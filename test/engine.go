@@ -17,6 +17,7 @@ limitations under the License.
 package test
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"path/filepath"
@@ -59,6 +60,20 @@ type engine struct {
 	kvstore.Store
 	blueprints        []constraint.Blueprint
 	internalVariables []*big.Int
+	customGates       []customGate
+	lookupTables      []lookupTable
+}
+
+// customGate is a custom gate defined with DefineCustomGate.
+type customGate struct {
+	name      string
+	monomials []frontend.GateMonomial
+}
+
+// lookupTable is a lookup table defined with DefineLookupTable.
+type lookupTable struct {
+	name    string
+	columns [][]*big.Int
 }
 
 // TestEngineOption defines an option for the test engine.
@@ -456,6 +471,19 @@ func (e *engine) AssertIsBoolean(i1 frontend.Variable) {
 	e.mustBeBoolean(b1)
 }
 
+func (e *engine) AssertIsInSet(i1 frontend.Variable, set ...frontend.Variable) {
+	if len(set) == 0 {
+		panic("[assertIsInSet] empty set")
+	}
+	b1 := e.toBigInt(i1)
+	for _, s := range set {
+		if b1.Cmp(e.toBigInt(s)) == 0 {
+			return
+		}
+	}
+	panic(fmt.Sprintf("[assertIsInSet] %s ∉ set", b1.String()))
+}
+
 func (e *engine) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variable) {
 
 	bValue := e.toBigInt(bound)
@@ -483,27 +511,39 @@ func (e *engine) Println(a ...frontend.Variable) {
 	}
 
 	for i := 0; i < len(a); i++ {
-		e.print(&sbb, a[i])
+		e.print(&sbb, a[i], constraint.LogFormat{})
 		sbb.WriteByte(' ')
 	}
 	fmt.Println(sbb.String())
 }
 
-func (e *engine) print(sbb *strings.Builder, x interface{}) {
+func (e *engine) print(sbb *strings.Builder, x interface{}, f constraint.LogFormat) {
 	switch v := x.(type) {
+	case frontend.LogValue:
+		e.print(sbb, v.V, v.Format)
 	case string:
 		sbb.WriteString(v)
 	case []frontend.Variable:
 		sbb.WriteRune('[')
 		for i := range v {
-			e.print(sbb, v[i])
+			e.print(sbb, v[i], f)
 			if i+1 != len(v) {
 				sbb.WriteRune(',')
 			}
 		}
 		sbb.WriteRune(']')
 	default:
+		if rv := reflect.Indirect(reflect.ValueOf(v)); rv.Kind() == reflect.Struct && rv.Type() != reflect.TypeOf(big.Int{}) {
+			e.printStruct(sbb, v, f)
+			return
+		}
 		i := e.toBigInt(v)
+		if !f.IsDefault() {
+			var r big.Int
+			r.Mod(i, e.modulus())
+			sbb.WriteString(f.Format(&r))
+			return
+		}
 		var iAsNeg big.Int
 		iAsNeg.Sub(i, e.q)
 		if iAsNeg.IsInt64() {
@@ -514,6 +554,31 @@ func (e *engine) print(sbb *strings.Builder, x interface{}) {
 	}
 }
 
+// printStruct prints the variables of a struct in the same layout as the
+// constraint system builders.
+func (e *engine) printStruct(sbb *strings.Builder, s interface{}, f constraint.LogFormat) {
+	leafCount, err := schema.Walk(s, tVariable, nil)
+	count := leafCount.Public + leafCount.Secret
+	if count == 0 || err != nil {
+		sbb.WriteString(fmt.Sprint(s))
+		return
+	}
+	sbb.WriteByte('{')
+	printer := func(leaf schema.LeafInfo, tValue reflect.Value) error {
+		count--
+		sbb.WriteString(leaf.FullName())
+		sbb.WriteString(": ")
+		e.print(sbb, tValue.Interface(), f)
+		if count != 0 {
+			sbb.WriteString(", ")
+		}
+		return nil
+	}
+	// ignoring error, printer() doesn't return errors
+	_, _ = schema.Walk(s, tVariable, printer)
+	sbb.WriteByte('}')
+}
+
 func (e *engine) NewHint(f solver.Hint, nbOutputs int, inputs ...frontend.Variable) ([]frontend.Variable, error) {
 
 	if nbOutputs <= 0 {
@@ -673,6 +738,182 @@ func (e *engine) Commit(v ...frontend.Variable) (frontend.Variable, error) {
 	return res, nil
 }
 
+// DefineCustomGate implements [frontend.CustomGater].
+func (e *engine) DefineCustomGate(name string, monomials ...frontend.GateMonomial) (int, error) {
+	if len(monomials) == 0 {
+		return 0, errors.New("custom gate without terms")
+	}
+	for _, m := range monomials {
+		if m.Coeff == nil {
+			return 0, errors.New("custom gate monomial without coefficient")
+		}
+		if m.A < 0 || m.B < 0 || m.A+m.B > constraint.MaxCustomGateDegree {
+			return 0, fmt.Errorf("custom gate %q: invalid exponents (%d, %d)", name, m.A, m.B)
+		}
+	}
+	for i := range e.customGates {
+		if e.customGates[i].name != name {
+			continue
+		}
+		if !e.sameMonomials(e.customGates[i].monomials, monomials) {
+			return 0, fmt.Errorf("custom gate %q already defined with another polynomial", name)
+		}
+		return i, nil
+	}
+	e.customGates = append(e.customGates, customGate{name: name, monomials: monomials})
+	return len(e.customGates) - 1, nil
+}
+
+func (e *engine) sameMonomials(m1, m2 []frontend.GateMonomial) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+	for i := range m1 {
+		if m1[i].A != m2[i].A || m1[i].B != m2[i].B || e.toBigInt(m1[i].Coeff).Cmp(e.toBigInt(m2[i].Coeff)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CustomGate implements [frontend.CustomGater].
+func (e *engine) CustomGate(gate int, a, b frontend.Variable) frontend.Variable {
+	if gate < 0 || gate >= len(e.customGates) {
+		panic("undefined custom gate")
+	}
+	_a, _b := e.toBigInt(a), e.toBigInt(b)
+	res, t := new(big.Int), new(big.Int)
+	for _, m := range e.customGates[gate].monomials {
+		t.Mod(m.Coeff, e.modulus())
+		for i := 0; i < m.A; i++ {
+			t.Mul(t, _a)
+		}
+		for i := 0; i < m.B; i++ {
+			t.Mul(t, _b)
+		}
+		res.Add(res, t)
+	}
+	return res.Mod(res, e.modulus())
+}
+
+// DefineLookupTable implements [frontend.TableLookuper].
+func (e *engine) DefineLookupTable(name string, columns ...[]*big.Int) (int, error) {
+	if len(columns) == 0 || len(columns[0]) == 0 {
+		return 0, errors.New("empty lookup table")
+	}
+	if len(columns) > constraint.MaxLookupTableColumns {
+		return 0, fmt.Errorf("lookup table %q has %d columns, at most %d supported", name, len(columns), constraint.MaxLookupTableColumns)
+	}
+	t := lookupTable{name: name, columns: make([][]*big.Int, len(columns))}
+	for i := range columns {
+		if len(columns[i]) != len(columns[0]) {
+			return 0, fmt.Errorf("lookup table %q has columns of different lengths", name)
+		}
+		t.columns[i] = make([]*big.Int, len(columns[i]))
+		for j, v := range columns[i] {
+			if v == nil {
+				return 0, errors.New("lookup table with nil entry")
+			}
+			t.columns[i][j] = new(big.Int).Mod(v, e.modulus())
+		}
+	}
+	for i := range e.lookupTables {
+		if e.lookupTables[i].name != name {
+			continue
+		}
+		if !e.sameColumns(e.lookupTables[i].columns, t.columns) {
+			return 0, fmt.Errorf("lookup table %q already defined with other columns", name)
+		}
+		return i, nil
+	}
+	e.lookupTables = append(e.lookupTables, t)
+	return len(e.lookupTables) - 1, nil
+}
+
+func (e *engine) sameColumns(c1, c2 [][]*big.Int) bool {
+	if len(c1) != len(c2) {
+		return false
+	}
+	for i := range c1 {
+		if len(c1[i]) != len(c2[i]) {
+			return false
+		}
+		for j := range c1[i] {
+			if c1[i][j].Cmp(c2[i][j]) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// findRow returns the index of the first row of the table t matching the
+// values vs, ignoring the column skip, or -1 if there is none. Missing values
+// of vs are zero.
+func (e *engine) findRow(t *lookupTable, vs []*big.Int, skip int) int {
+	var zero big.Int
+	for i := range t.columns[0] {
+		match := true
+		for j := range t.columns {
+			if j == skip {
+				continue
+			}
+			v := &zero
+			if j < len(vs) {
+				v = vs[j]
+			}
+			if t.columns[j][i].Cmp(v) != 0 {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e *engine) lookupTable(table int) *lookupTable {
+	if table < 0 || table >= len(e.lookupTables) {
+		panic("undefined lookup table")
+	}
+	return &e.lookupTables[table]
+}
+
+// AssertIsInTable implements [frontend.TableLookuper].
+func (e *engine) AssertIsInTable(table int, vs ...frontend.Variable) {
+	t := e.lookupTable(table)
+	if len(vs) > len(t.columns) {
+		panic(fmt.Sprintf("assertIsInTable: %d values for a table of %d columns", len(vs), len(t.columns)))
+	}
+	row := make([]*big.Int, len(vs))
+	for i := range vs {
+		row[i] = e.toBigInt(vs[i])
+	}
+	if e.findRow(t, row, -1) == -1 {
+		panic(fmt.Sprintf("[assertIsInTable] %v not in table %q", row, t.name))
+	}
+}
+
+// Lookup implements [frontend.TableLookuper].
+func (e *engine) Lookup(table int, keys ...frontend.Variable) frontend.Variable {
+	t := e.lookupTable(table)
+	last := len(t.columns) - 1
+	if len(keys) != last {
+		panic(fmt.Sprintf("lookup: %d keys for a table of %d columns", len(keys), len(t.columns)))
+	}
+	row := make([]*big.Int, len(keys))
+	for i := range keys {
+		row[i] = e.toBigInt(keys[i])
+	}
+	i := e.findRow(t, row, last)
+	if i == -1 {
+		panic(fmt.Sprintf("[lookup] %v not in table %q", row, t.name))
+	}
+	return new(big.Int).Set(t.columns[last][i])
+}
+
 func (e *engine) Defer(cb func(frontend.API) error) {
 	circuitdefer.Put(e, cb)
 }